@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path/filepath"
 
 	"github.com/charmbracelet/soft-serve/pkg/backend"
 	"github.com/charmbracelet/soft-serve/pkg/config"
 	"github.com/charmbracelet/soft-serve/pkg/db"
 	"github.com/charmbracelet/soft-serve/pkg/hooks"
+	"github.com/charmbracelet/soft-serve/pkg/storage"
 	"github.com/charmbracelet/soft-serve/pkg/store"
 	"github.com/charmbracelet/soft-serve/pkg/store/database"
 	"github.com/spf13/cobra"
@@ -33,7 +35,11 @@ func InitBackendContext(cmd *cobra.Command, _ []string) error {
 	ctx = db.WithContext(ctx, dbx)
 	dbstore := database.New(ctx, dbx)
 	ctx = store.WithContext(ctx, dbstore)
-	be := backend.New(ctx, cfg, dbx, dbstore)
+	rs, err := storage.OpenRepoStorage(ctx, cfg.Repo.Storage, filepath.Join(cfg.DataPath, "repos"))
+	if err != nil {
+		return fmt.Errorf("open repository storage: %w", err)
+	}
+	be := backend.New(ctx, cfg, dbx, dbstore, backend.WithRepoStorage(rs))
 	ctx = backend.WithContext(ctx, be)
 
 	cmd.SetContext(ctx)
@@ -50,6 +50,23 @@ var (
 		},
 	}
 
+	configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Manage the server configuration",
+	}
+
+	configDumpCmd = &cobra.Command{
+		Use:   "dump",
+		Short: "Print the resolved server configuration",
+		Long:  "Print the server configuration after applying the config file, environment variables, and defaults.",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			cfg := config.FromContext(c.Context())
+			fmt.Fprint(c.OutOrStdout(), cfg.Dump()) //nolint:errcheck
+			return nil
+		},
+	}
+
 	syncHooksCmd = &cobra.Command{
 		Use:                "sync-hooks",
 		Short:              "Update repository hooks",
@@ -69,7 +86,10 @@ var (
 )
 
 func init() {
+	configCmd.AddCommand(configDumpCmd)
+
 	Command.AddCommand(
+		configCmd,
 		syncHooksCmd,
 		migrateCmd,
 		rollbackCmd,
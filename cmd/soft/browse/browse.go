@@ -47,11 +47,10 @@ var Command = &cobra.Command{
 			repo.NewReadme(c),
 			repo.NewFiles(c),
 			repo.NewLog(c),
+			repo.NewStash(c),
+			repo.NewRefs(c, git.RefsHeads),
+			repo.NewRefs(c, git.RefsTags),
 		}
-		if !r.IsBare {
-			comps = append(comps, repo.NewStash(c))
-		}
-		comps = append(comps, repo.NewRefs(c, git.RefsHeads), repo.NewRefs(c, git.RefsTags))
 		m := &model{
 			model:  repo.New(c, comps...),
 			repo:   repository{r},
@@ -84,6 +83,7 @@ type model struct {
 	state      state
 	showFooter bool
 	error      error
+	times      common.RelativeTimesTicker
 }
 
 var _ tea.Model = &model{}
@@ -200,6 +200,7 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// This fixes determining the height margin of the footer.
 	m.SetSize(m.common.Width, m.common.Height)
+	cmds = append(cmds, m.times.Update(msg, m.model))
 
 	return m, tea.Batch(cmds...)
 }
@@ -257,6 +258,11 @@ func (repository) IsHidden() bool {
 	return false
 }
 
+// IsArchived implements proto.Repository.
+func (repository) IsArchived() bool {
+	return false
+}
+
 // IsMirror implements proto.Repository.
 func (repository) IsMirror() bool {
 	return false
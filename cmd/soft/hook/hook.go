@@ -77,6 +77,8 @@ var (
 
 		switch cmdName {
 		case hooks.PreReceiveHook, hooks.PostReceiveHook:
+			// Git passes the push options, `git push -o`, to these hooks.
+			ctx = hooks.WithPushOptions(ctx, hooks.PushOptionsFromEnv(os.Getenv))
 			scanner := bufio.NewScanner(stdin)
 			for scanner.Scan() {
 				buf.Write(scanner.Bytes())
@@ -93,6 +95,22 @@ var (
 
 			switch cmdName {
 			case hooks.PreReceiveHook:
+				// Reject pushes that exceed the limits of the repository or
+				// of its references, break its branch protections, or add
+				// commits that don't follow the commit message policy before
+				// anything else sees them.
+				if err := hks.CheckPushLimits(ctx, repoName, opts); err != nil {
+					return err
+				}
+				if err := hks.CheckRefLimits(ctx, repoName, opts); err != nil {
+					return err
+				}
+				if err := hks.CheckBranchProtections(ctx, repoName, opts); err != nil {
+					return err
+				}
+				if err := hks.CheckCommitMessages(ctx, repoName, opts); err != nil {
+					return err
+				}
 				hks.PreReceive(ctx, stdout, stderr, repoName, opts)
 			case hooks.PostReceiveHook:
 				hks.PostReceive(ctx, stdout, stderr, repoName, opts)
@@ -8,6 +8,7 @@ import (
 
 	"github.com/charmbracelet/log"
 
+	"github.com/charmbracelet/soft-serve/pkg/audit"
 	"github.com/charmbracelet/soft-serve/pkg/backend"
 	"github.com/charmbracelet/soft-serve/pkg/config"
 	"github.com/charmbracelet/soft-serve/pkg/cron"
@@ -28,6 +29,7 @@ type Server struct {
 	HTTPServer  *web.HTTPServer
 	StatsServer *stats.StatsServer
 	Cron        *cron.Scheduler
+	Audit       *audit.Exporter
 	Config      *config.Config
 	Backend     *backend.Backend
 	DB          *db.DB
@@ -72,9 +74,11 @@ func NewServer(ctx context.Context) (*Server, error) {
 		return nil, fmt.Errorf("create ssh server: %w", err)
 	}
 
-	srv.GitDaemon, err = daemon.NewGitDaemon(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("create git daemon: %w", err)
+	if cfg.Git.Enabled {
+		srv.GitDaemon, err = daemon.NewGitDaemon(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("create git daemon: %w", err)
+		}
 	}
 
 	srv.HTTPServer, err = web.NewHTTPServer(ctx)
@@ -87,19 +91,28 @@ func NewServer(ctx context.Context) (*Server, error) {
 		return nil, fmt.Errorf("create stats server: %w", err)
 	}
 
+	if len(cfg.Audit.Sinks) > 0 {
+		srv.Audit, err = audit.NewExporter(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("create audit exporter: %w", err)
+		}
+	}
+
 	return srv, nil
 }
 
 // Start starts the SSH server.
 func (s *Server) Start() error {
 	errg, _ := errgroup.WithContext(s.ctx)
-	errg.Go(func() error {
-		s.logger.Print("Starting Git daemon", "addr", s.Config.Git.ListenAddr)
-		if err := s.GitDaemon.Start(); !errors.Is(err, daemon.ErrServerClosed) {
-			return err
-		}
-		return nil
-	})
+	if s.GitDaemon != nil {
+		errg.Go(func() error {
+			s.logger.Print("Starting Git daemon", "addr", s.Config.Git.ListenAddr)
+			if err := s.GitDaemon.Start(); !errors.Is(err, daemon.ErrServerClosed) {
+				return err
+			}
+			return nil
+		})
+	}
 	errg.Go(func() error {
 		s.logger.Print("Starting HTTP server", "addr", s.Config.HTTP.ListenAddr)
 		if err := s.HTTPServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
@@ -125,15 +138,36 @@ func (s *Server) Start() error {
 		s.Cron.Start()
 		return nil
 	})
+	if s.Audit != nil {
+		errg.Go(func() error {
+			s.logger.Print("Starting audit exporter", "sinks", len(s.Config.Audit.Sinks))
+			if err := s.Audit.Start(); !errors.Is(err, audit.ErrClosed) {
+				return err
+			}
+			return nil
+		})
+	}
+	errg.Go(func() error {
+		// Load the repositories before reporting the server as ready.
+		if _, err := s.Backend.Repositories(s.ctx); err != nil {
+			s.logger.Error("failed to load repositories", "err", err)
+			return nil
+		}
+		s.StatsServer.SetReady(true)
+		return nil
+	})
 	return errg.Wait()
 }
 
 // Shutdown lets the server gracefully shutdown.
 func (s *Server) Shutdown(ctx context.Context) error {
+	s.StatsServer.SetReady(false)
 	errg, ctx := errgroup.WithContext(ctx)
-	errg.Go(func() error {
-		return s.GitDaemon.Shutdown(ctx)
-	})
+	if s.GitDaemon != nil {
+		errg.Go(func() error {
+			return s.GitDaemon.Shutdown(ctx)
+		})
+	}
 	errg.Go(func() error {
 		return s.HTTPServer.Shutdown(ctx)
 	})
@@ -143,6 +177,11 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	errg.Go(func() error {
 		return s.StatsServer.Shutdown(ctx)
 	})
+	if s.Audit != nil {
+		errg.Go(func() error {
+			return s.Audit.Shutdown(ctx)
+		})
+	}
 	errg.Go(func() error {
 		for _, j := range jobs.List() {
 			s.Cron.Remove(j.ID)
@@ -157,10 +196,15 @@ func (s *Server) Shutdown(ctx context.Context) error {
 // Close closes the SSH server.
 func (s *Server) Close() error {
 	var errg errgroup.Group
-	errg.Go(s.GitDaemon.Close)
+	if s.GitDaemon != nil {
+		errg.Go(s.GitDaemon.Close)
+	}
 	errg.Go(s.HTTPServer.Close)
 	errg.Go(s.SSHServer.Close)
 	errg.Go(s.StatsServer.Close)
+	if s.Audit != nil {
+		errg.Go(s.Audit.Close)
+	}
 	errg.Go(func() error {
 		s.Cron.Stop()
 		return nil
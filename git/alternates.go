@@ -0,0 +1,127 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aymanbagabas/git-module"
+)
+
+// alternatesFile is the path of the alternates of a repository, relative to
+// it. See gitrepository-layout(5).
+const alternatesFile = "objects/info/alternates"
+
+// Alternates returns the absolute paths of the object directories the repo at
+// the given path borrows objects from, none if it has its own objects only.
+func Alternates(path string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(path, alternatesFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	objects := filepath.Join(path, "objects")
+	dirs := make([]string, 0)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !filepath.IsAbs(line) {
+			line = filepath.Join(objects, line)
+		}
+		dirs = append(dirs, filepath.Clean(line))
+	}
+	return dirs, nil
+}
+
+// SetAlternates sets the object directories the repo at the given path
+// borrows objects from. They're written relative to its object directory, so
+// that the repositories can move together. The alternates are removed when
+// there's none.
+func SetAlternates(path string, dirs ...string) error {
+	file := filepath.Join(path, alternatesFile)
+	if len(dirs) == 0 {
+		if err := os.Remove(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	objects, err := filepath.Abs(filepath.Join(path, "objects"))
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	for _, dir := range dirs {
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+		if rel, err := filepath.Rel(objects, dir); err == nil {
+			dir = rel
+		}
+		buf.WriteString(filepath.ToSlash(dir) + "\n")
+	}
+	if err := os.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(file, buf.Bytes(), 0o644) //nolint:gosec
+}
+
+// Fork makes the empty repo at the given path a fork of the repo at src: it
+// borrows the objects of src instead of copying them, and gets its branches,
+// tags, and default branch. The references of the fork are its own from then
+// on.
+func Fork(ctx context.Context, path, src string) error {
+	if !isGitDir(path) || !isGitDir(src) {
+		return ErrNotAGitRepository
+	}
+
+	if err := SetAlternates(path, filepath.Join(src, "objects")); err != nil {
+		return err
+	}
+
+	// All of the objects are there already, the fetch only copies the
+	// references.
+	if _, err := git.NewCommand("fetch", "--quiet", "--no-write-fetch-head", "--", src,
+		"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*").
+		WithContext(ctx).WithTimeout(-1).RunInDir(path); err != nil {
+		return err
+	}
+
+	head, err := git.NewCommand("symbolic-ref", "HEAD").RunInDir(src)
+	if err != nil {
+		// A detached HEAD is left to the default branch of the fork.
+		return nil //nolint:nilerr
+	}
+	_, err = git.NewCommand("symbolic-ref", "HEAD", strings.TrimSpace(string(head))).RunInDir(path)
+	return err
+}
+
+// Dissociate copies the objects the repo at the given path borrows from its
+// alternates into its own packs, and removes the alternates. The repo doesn't
+// depend on other repositories afterwards.
+func Dissociate(ctx context.Context, path string) error {
+	if !isGitDir(path) {
+		return ErrNotAGitRepository
+	}
+
+	dirs, err := Alternates(path)
+	if err != nil || len(dirs) == 0 {
+		return err
+	}
+
+	// Without -l, the borrowed objects are packed too.
+	if err := runMaintenance(ctx, path, "repack", "-a", "-d", "-q"); err != nil {
+		return err
+	}
+	return SetAlternates(path)
+}
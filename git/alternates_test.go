@@ -0,0 +1,39 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestAlternates(t *testing.T) {
+	is := is.New(t)
+	dir := t.TempDir()
+	fork := filepath.Join(dir, "group", "fork.git")
+	parent := filepath.Join(dir, "parent.git", "objects")
+
+	alts, err := Alternates(fork)
+	is.NoErr(err)
+	is.Equal(len(alts), 0)
+
+	is.NoErr(SetAlternates(fork, parent))
+	data, err := os.ReadFile(filepath.Join(fork, alternatesFile))
+	is.NoErr(err)
+	is.Equal(string(data), "../../../parent.git/objects\n")
+	alts, err = Alternates(fork)
+	is.NoErr(err)
+	is.Equal(alts, []string{parent})
+
+	// Absolute paths and comments written by git are understood too.
+	is.NoErr(os.WriteFile(filepath.Join(fork, alternatesFile), []byte("# shared\n"+parent+"\n\n"), 0o644))
+	alts, err = Alternates(fork)
+	is.NoErr(err)
+	is.Equal(alts, []string{parent})
+
+	is.NoErr(SetAlternates(fork))
+	_, err = os.Stat(filepath.Join(fork, alternatesFile))
+	is.True(os.IsNotExist(err))
+	is.NoErr(SetAlternates(fork))
+}
@@ -0,0 +1,198 @@
+package git
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ArchiveFormats are the formats of Archive.
+var ArchiveFormats = []string{"tar", "tar.gz", "zip"}
+
+// ArchiveOptions are the options of Archive.
+type ArchiveOptions struct {
+	// Format is the format of the archive, one of ArchiveFormats. It's tar
+	// when empty.
+	Format string
+
+	// Prefix is prepended to the paths in the archive, e.g. "project/".
+	Prefix string
+
+	// Replace is called with the path, size, and content of every regular
+	// file of the archive, and returns the content to archive instead and
+	// its size, e.g. the LFS object of a pointer. The content is closed
+	// once archived if it's an io.Closer. Files are archived as they are
+	// when it's nil.
+	Replace func(path string, size int64, r io.Reader) (io.Reader, int64, error)
+}
+
+// Archive writes an archive of the files of the given tree-ish to w, with the
+// attributes of the repository applied like with git archive, e.g.
+// export-ignore. The archive is streamed, files are never read in full.
+func (r *Repository) Archive(w io.Writer, treeish string, opts ArchiveOptions) error {
+	if opts.Format == "" {
+		opts.Format = "tar"
+	}
+	var aw archiveWriter
+	switch opts.Format {
+	case "tar":
+		aw = &tarArchive{tw: tar.NewWriter(w)}
+	case "tar.gz":
+		gw := gzip.NewWriter(w)
+		aw = &tarArchive{tw: tar.NewWriter(gw), gw: gw}
+	case "zip":
+		aw = &zipArchive{zw: zip.NewWriter(w)}
+	default:
+		return fmt.Errorf("unknown archive format %q: must be one of %s", opts.Format, strings.Join(ArchiveFormats, ", "))
+	}
+	if treeish == "" || strings.HasPrefix(treeish, "-") {
+		return ErrReferenceNotExist
+	}
+
+	// Let git apply the attributes and write a tar archive, then rewrite
+	// it in the requested format, replacing the files that need to be.
+	args := []string{"archive", "--format=tar"}
+	if opts.Prefix != "" {
+		args = append(args, "--prefix="+opts.Prefix)
+	}
+	pr, pw := io.Pipe()
+	var stderr bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		err := NewCommand(append(args, treeish)...).
+			WithTimeout(-1).
+			RunInDirWithOptions(r.Path, RunInDirOptions{
+				Stdout: pw,
+				Stderr: &stderr,
+			})
+		if msg := strings.TrimSpace(stderr.String()); err != nil && msg != "" {
+			err = errors.New(msg)
+		}
+		pw.CloseWithError(err) // nolint: errcheck
+		done <- err
+	}()
+
+	err := copyArchive(aw, tar.NewReader(pr), opts.Replace)
+	if err == nil {
+		// Read the padding at the end of the archive.
+		_, err = io.Copy(io.Discard, pr)
+	}
+	// Unblock git if the copy stopped early.
+	pr.CloseWithError(io.ErrClosedPipe) // nolint: errcheck
+	if gerr := <-done; err == nil {
+		err = gerr
+	}
+	return err
+}
+
+// copyArchive writes the entries of the tar archive from git archive to aw.
+func copyArchive(aw archiveWriter, tr *tar.Reader, replace func(string, int64, io.Reader) (io.Reader, int64, error)) error {
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		var content io.Reader = tr
+		if hdr.Typeflag == tar.TypeReg && replace != nil {
+			if content, hdr.Size, err = replace(hdr.Name, hdr.Size, tr); err != nil {
+				return err
+			}
+		}
+		err = aw.WriteEntry(hdr, content)
+		if c, ok := content.(io.Closer); ok {
+			c.Close() // nolint: errcheck
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return aw.Close()
+}
+
+// archiveWriter writes the entries of an archive in some format.
+type archiveWriter interface {
+	WriteEntry(hdr *tar.Header, r io.Reader) error
+	Close() error
+}
+
+// tarArchive writes a tar archive, compressed with gzip when gw is set.
+type tarArchive struct {
+	tw *tar.Writer
+	gw *gzip.Writer
+}
+
+func (a *tarArchive) WriteEntry(hdr *tar.Header, r io.Reader) error {
+	if err := a.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if hdr.Typeflag == tar.TypeReg {
+		if _, err := io.CopyN(a.tw, r, hdr.Size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *tarArchive) Close() error {
+	if err := a.tw.Close(); err != nil {
+		return err
+	}
+	if a.gw != nil {
+		return a.gw.Close()
+	}
+	return nil
+}
+
+// zipArchive writes a zip archive. The commit of the archive is its comment,
+// like with git archive.
+type zipArchive struct {
+	zw *zip.Writer
+}
+
+func (a *zipArchive) WriteEntry(hdr *tar.Header, r io.Reader) error {
+	switch hdr.Typeflag {
+	case tar.TypeXGlobalHeader:
+		if c, ok := hdr.PAXRecords["comment"]; ok {
+			return a.zw.SetComment(c)
+		}
+		return nil
+	case tar.TypeReg, tar.TypeDir, tar.TypeSymlink:
+	default:
+		// Git doesn't write other entries.
+		return nil
+	}
+
+	zh, err := zip.FileInfoHeader(hdr.FileInfo())
+	if err != nil {
+		return err
+	}
+	zh.Name = hdr.Name
+	zh.Modified = hdr.ModTime
+	if hdr.Typeflag == tar.TypeReg {
+		zh.Method = zip.Deflate
+	}
+	w, err := a.zw.CreateHeader(zh)
+	if err != nil {
+		return err
+	}
+	switch hdr.Typeflag {
+	case tar.TypeReg:
+		_, err = io.CopyN(w, r, hdr.Size)
+	case tar.TypeSymlink:
+		_, err = io.WriteString(w, hdr.Linkname)
+	}
+	return err
+}
+
+func (a *zipArchive) Close() error {
+	return a.zw.Close()
+}
@@ -0,0 +1,72 @@
+package git
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestCopyArchive(t *testing.T) {
+	is := is.New(t)
+	var src bytes.Buffer
+	tw := tar.NewWriter(&src)
+	is.NoErr(tw.WriteHeader(&tar.Header{Typeflag: tar.TypeXGlobalHeader, Name: "pax_global_header",
+		PAXRecords: map[string]string{"comment": "8073f2026d6082bf8073f2026d6082bf8073f202"}}))
+	is.NoErr(tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: "p/dir/", Mode: 0o775}))
+	for name, content := range map[string]string{"p/dir/big.bin": "pointer", "p/README.md": "readme"} {
+		is.NoErr(tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0o664, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		is.NoErr(err)
+	}
+	is.NoErr(tw.WriteHeader(&tar.Header{Typeflag: tar.TypeSymlink, Name: "p/link", Linkname: "README.md", Mode: 0o777}))
+	is.NoErr(tw.Close())
+
+	replace := func(path string, size int64, r io.Reader) (io.Reader, int64, error) {
+		if path == "p/dir/big.bin" {
+			return io.NopCloser(strings.NewReader("large object")), 12, nil
+		}
+		return r, size, nil
+	}
+
+	var out bytes.Buffer
+	is.NoErr(copyArchive(&tarArchive{tw: tar.NewWriter(&out)}, tar.NewReader(bytes.NewReader(src.Bytes())), replace))
+	tr := tar.NewReader(&out)
+	files := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		is.NoErr(err)
+		data, err := io.ReadAll(tr)
+		is.NoErr(err)
+		files[hdr.Name] = string(data)
+	}
+	is.Equal(files["p/dir/big.bin"], "large object")
+	is.Equal(files["p/README.md"], "readme")
+
+	out.Reset()
+	is.NoErr(copyArchive(&zipArchive{zw: zip.NewWriter(&out)}, tar.NewReader(bytes.NewReader(src.Bytes())), replace))
+	zr, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	is.NoErr(err)
+	is.Equal(zr.Comment, "8073f2026d6082bf8073f2026d6082bf8073f202")
+	files = map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		is.NoErr(err)
+		data, err := io.ReadAll(rc)
+		is.NoErr(err)
+		rc.Close() // nolint: errcheck
+		files[f.Name] = string(data)
+	}
+	is.Equal(len(files), 4)
+	is.Equal(files["p/dir/big.bin"], "large object")
+	is.Equal(files["p/link"], "README.md")
+	_, ok := files["p/dir/"]
+	is.True(ok)
+}
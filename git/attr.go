@@ -1,12 +1,11 @@
 package git
 
 import (
-	"math/rand"
-	"os"
-	"path/filepath"
-	"strconv"
+	"bytes"
+	"errors"
 	"strings"
-	"time"
+
+	"github.com/aymanbagabas/git-module"
 )
 
 // Attribute represents a Git attribute.
@@ -17,26 +16,60 @@ type Attribute struct {
 
 // CheckAttributes checks the attributes of the given ref and path.
 func (r *Repository) CheckAttributes(ref *Reference, path string) ([]Attribute, error) {
-	rnd := rand.NewSource(time.Now().UnixNano())
-	fn := "soft-serve-index-" + strconv.Itoa(rand.New(rnd).Int()) // nolint: gosec
-	tmpindex := filepath.Join(os.TempDir(), fn)
+	var out []byte
+	if err := r.withRefIndex(ref, func(env string) error {
+		var err error
+		out, err = NewCommand("check-attr", "--cached", "-a", "--", path).
+			AddEnvs(env).
+			RunInDir(r.Path)
+		return err
+	}); err != nil {
+		return nil, err
+	}
 
-	defer os.Remove(tmpindex) // nolint: errcheck
+	return parseAttributes(path, out), nil
+}
 
-	readTree := NewCommand("read-tree", "--reset", "-i", ref.Name().String()).
-		AddEnvs("GIT_INDEX_FILE=" + tmpindex)
-	if _, err := readTree.RunInDir(r.Path); err != nil {
-		return nil, err
+// CheckPathsAttributes checks the attributes of the given paths at the given
+// ref. It returns the attributes keyed by path. Paths without attributes are
+// omitted.
+func (r *Repository) CheckPathsAttributes(ref *Reference, paths []string) (map[string][]Attribute, error) {
+	if len(paths) == 0 {
+		return map[string][]Attribute{}, nil
 	}
 
-	checkAttr := NewCommand("check-attr", "--cached", "-a", "--", path).
-		AddEnvs("GIT_INDEX_FILE=" + tmpindex)
-	out, err := checkAttr.RunInDir(r.Path)
-	if err != nil {
+	var stdout, stderr bytes.Buffer
+	if err := r.withRefIndex(ref, func(env string) error {
+		return NewCommand("check-attr", "--cached", "--stdin", "-z", "-a").
+			AddEnvs(env).
+			RunInDirWithOptions(r.Path, git.RunInDirOptions{
+				Stdin:  strings.NewReader(strings.Join(paths, "\x00") + "\x00"),
+				Stdout: &stdout,
+				Stderr: &stderr,
+			})
+	}); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
 		return nil, err
 	}
 
-	return parseAttributes(path, out), nil
+	return parsePathsAttributes(stdout.Bytes()), nil
+}
+
+// withRefIndex reads the tree of the given ref into a temporary index and
+// calls fn with the environment variable that points Git to it.
+func (r *Repository) withRefIndex(ref *Reference, fn func(env string) error) error {
+	env, done := tempIndex()
+	defer done()
+
+	readTree := NewCommand("read-tree", "--reset", "-i", ref.Name().String()).
+		AddEnvs(env)
+	if _, err := readTree.RunInDir(r.Path); err != nil {
+		return err
+	}
+
+	return fn(env)
 }
 
 func parseAttributes(path string, buf []byte) []Attribute {
@@ -60,3 +93,26 @@ func parseAttributes(path string, buf []byte) []Attribute {
 
 	return attrs
 }
+
+// parsePathsAttributes parses the NUL separated output of git check-attr -z.
+// Every attribute is a triplet of path, attribute name and value.
+func parsePathsAttributes(buf []byte) map[string][]Attribute {
+	attrs := make(map[string][]Attribute)
+	fields := strings.Split(string(buf), "\x00")
+	for len(fields) >= 3 {
+		path, name, value := fields[0], fields[1], fields[2]
+		fields = fields[3:]
+		attrs[path] = append(attrs[path], Attribute{
+			Name:  name,
+			Value: value,
+		})
+	}
+
+	return attrs
+}
+
+// IsSet returns true if the attribute is set, either with no value or with
+// "true".
+func (a Attribute) IsSet() bool {
+	return a.Value == "set" || a.Value == "true"
+}
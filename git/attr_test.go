@@ -89,3 +89,22 @@ org/example/MyClass.java: myAttr: set`,
 		is.Equal(attrs, c.want)
 	}
 }
+
+func TestParsePathsAttr(t *testing.T) {
+	is := is.New(t)
+	in := "index.tpl\x00linguist-language\x00HTML\x00" +
+		"vendor/lib.go\x00linguist-vendored\x00set\x00" +
+		"vendor/lib.go\x00diff\x00go\x00" +
+		"a: b.txt\x00linguist-generated\x00true\x00"
+	attrs := parsePathsAttributes([]byte(in))
+	is.Equal(len(attrs), 3)
+	is.Equal(attrs["index.tpl"], []Attribute{{Name: "linguist-language", Value: "HTML"}})
+	is.Equal(attrs["vendor/lib.go"], []Attribute{
+		{Name: "linguist-vendored", Value: "set"},
+		{Name: "diff", Value: "go"},
+	})
+	is.True(attrs["vendor/lib.go"][0].IsSet())
+	is.True(attrs["a: b.txt"][0].IsSet())
+	is.True(!attrs["index.tpl"][0].IsSet())
+	is.Equal(len(parsePathsAttributes(nil)), 0)
+}
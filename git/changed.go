@@ -0,0 +1,66 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+)
+
+// ChangedFile is a file that differs between two revisions.
+type ChangedFile struct {
+	// Status is the kind of change: "A" for added, "M" for modified, "D"
+	// for deleted, "R" for renamed, "C" for copied, and "T" for a change of
+	// type, e.g. a file that became a symlink.
+	Status string
+	// Path is the path of the file in the head revision, or in the base
+	// revision for deleted files.
+	Path string
+	// OldPath is the path of renamed and copied files in the base revision.
+	OldPath string
+}
+
+// ChangedFiles returns the files that changed in head since it forked from
+// base, i.e. since their merge base, along with the revision the changes are
+// relative to. Head is compared with base itself when they share no history.
+func (r *Repository) ChangedFiles(base, head string) ([]ChangedFile, string, error) {
+	since := base
+	switch mb, err := r.MergeBase(base, head, false); {
+	case err == nil:
+		since = mb[0]
+	case errors.Is(err, ErrNoMergeBase):
+	default:
+		return nil, "", err
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := NewCommand("diff", "--name-status", "-z", "-M", since, head, "--").
+		AddEnvs("GIT_CONFIG_GLOBAL=/dev/null").
+		RunInDirPipeline(&stdout, &stderr, r.Path); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, "", errors.New(msg)
+		}
+		return nil, "", err
+	}
+
+	return parseNameStatus(stdout.Bytes()), since, nil
+}
+
+// parseNameStatus parses the NUL separated output of git diff --name-status
+// -z. Renames and copies have a score after their status and both paths.
+func parseNameStatus(out []byte) []ChangedFile {
+	fields := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	files := make([]ChangedFile, 0)
+	for i := 0; i+1 < len(fields); i += 2 {
+		if fields[i] == "" {
+			break
+		}
+		f := ChangedFile{Status: fields[i][:1], Path: fields[i+1]}
+		if (f.Status == "R" || f.Status == "C") && i+2 < len(fields) {
+			f.OldPath = f.Path
+			f.Path = fields[i+2]
+			i++
+		}
+		files = append(files, f)
+	}
+	return files
+}
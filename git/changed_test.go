@@ -0,0 +1,20 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestParseNameStatus(t *testing.T) {
+	is := is.New(t)
+	out := "M\x00a.txt\x00A\x00dir/b.txt\x00R087\x00old.txt\x00new.txt\x00D\x00gone.txt\x00T\x00link\x00"
+	is.Equal(parseNameStatus([]byte(out)), []ChangedFile{
+		{Status: "M", Path: "a.txt"},
+		{Status: "A", Path: "dir/b.txt"},
+		{Status: "R", Path: "new.txt", OldPath: "old.txt"},
+		{Status: "D", Path: "gone.txt"},
+		{Status: "T", Path: "link"},
+	})
+	is.Equal(len(parseNameStatus(nil)), 0)
+}
@@ -3,6 +3,7 @@ package git
 import (
 	"os"
 	"path/filepath"
+	"strings"
 
 	gcfg "github.com/go-git/go-git/v5/plumbing/format/config"
 )
@@ -37,3 +38,43 @@ func (r *Repository) SetConfig(cfg *gcfg.Config) error {
 	e := gcfg.NewEncoder(f)
 	return e.Encode(cfg)
 }
+
+// ConfigEntry is a key and its value in a Git configuration.
+type ConfigEntry struct {
+	// Key is the key with a lower case section and name, e.g.
+	// "core.logallrefupdates".
+	Key string
+	// Value is the value of the key. Keys without a value are empty.
+	Value string
+}
+
+// ConfigEntries returns the entries of the repository Git configuration in
+// the order they appear in the file. Keys with many values have an entry per
+// value.
+func (r *Repository) ConfigEntries() ([]ConfigEntry, error) {
+	out, err := NewCommand("config", "--local", "--null", "--list").RunInDir(r.Path)
+	if err != nil {
+		return nil, err
+	}
+	return parseConfigEntries(string(out)), nil
+}
+
+// SetConfigValue sets the value of a key in the repository Git configuration.
+func (r *Repository) SetConfigValue(key, value string) error {
+	_, err := NewCommand("config", "--local", "--", key, value).RunInDir(r.Path)
+	return err
+}
+
+// parseConfigEntries parses the output of git config --null --list. Every
+// entry is a key and a value separated by a newline, and ends with a NUL.
+func parseConfigEntries(out string) []ConfigEntry {
+	entries := make([]ConfigEntry, 0)
+	for _, e := range strings.Split(out, "\x00") {
+		if e == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(e, "\n")
+		entries = append(entries, ConfigEntry{Key: key, Value: value})
+	}
+	return entries
+}
@@ -0,0 +1,35 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestParseConfigEntries(t *testing.T) {
+	is := is.New(t)
+	entries := parseConfigEntries("core.bare\ntrue\x00core.flag\x00gc.auto\n0\x00remote.origin.fetch\na\nb\x00")
+	is.Equal(entries, []ConfigEntry{
+		{Key: "core.bare", Value: "true"},
+		{Key: "core.flag", Value: ""},
+		{Key: "gc.auto", Value: "0"},
+		{Key: "remote.origin.fetch", Value: "a\nb"},
+	})
+	is.Equal(len(parseConfigEntries("")), 0)
+}
+
+func TestRemotesFromConfig(t *testing.T) {
+	is := is.New(t)
+	remotes := remotesFromConfig([]ConfigEntry{
+		{Key: "core.bare", Value: "true"},
+		{Key: "remote.origin.url", Value: "https://example.com/a.git"},
+		{Key: "remote.origin.fetch", Value: "+refs/*:refs/*"},
+		{Key: "remote.up.stream.url", Value: "git@example.com:b.git"},
+		{Key: "remote.origin.url", Value: "https://example.com/c.git"},
+		{Key: "remote..url", Value: "x"},
+	})
+	is.Equal(remotes, []Remote{
+		{Name: "origin", URL: "https://example.com/a.git"},
+		{Name: "up.stream", URL: "git@example.com:b.git"},
+	})
+}
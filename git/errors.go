@@ -17,4 +17,14 @@ var (
 	ErrRevisionNotExist = git.ErrRevisionNotExist
 	// ErrNotAGitRepository is returned when the given path is not a Git repository.
 	ErrNotAGitRepository = errors.New("not a git repository")
+	// ErrObjectNotFound is returned when an object is not found.
+	ErrObjectNotFound = errors.New("object not found")
+	// ErrAmbiguousObject is returned when an abbreviated object name matches
+	// more than one object.
+	ErrAmbiguousObject = errors.New("ambiguous object name")
+	// ErrNoMergeBase is returned when two revisions have no common ancestor.
+	ErrNoMergeBase = errors.New("no common ancestor")
+	// ErrNotMergeCommit is returned when a commit isn't the merge of two
+	// parents.
+	ErrNotMergeCommit = errors.New("not a merge of two commits")
 )
@@ -0,0 +1,140 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/aymanbagabas/git-module"
+)
+
+// FsckResult is the summary of a git fsck run.
+type FsckResult struct {
+	// Dangling is the number of unreachable objects nothing points to. They
+	// are harmless, housekeeping prunes them eventually.
+	Dangling int
+	// Missing is the number of objects that are pointed to but don't exist.
+	Missing int
+	// Corrupt is the number of objects that exist but can't be read or are
+	// invalid.
+	Corrupt int
+	// Failed is true if git fsck reported errors, even ones it doesn't tie to
+	// an object.
+	Failed bool
+}
+
+// OK returns true if the repository has no missing or corrupt objects.
+func (r FsckResult) OK() bool {
+	return !r.Failed && r.Missing == 0 && r.Corrupt == 0
+}
+
+// String returns the summary, e.g. "2 missing, 1 corrupt, 3 dangling objects".
+func (r FsckResult) String() string {
+	return fmt.Sprintf("%d missing, %d corrupt, %d dangling objects", r.Missing, r.Corrupt, r.Dangling)
+}
+
+// Fsck verifies the connectivity and validity of the objects of the repo at
+// the given path with git fsck. Its output is written as it goes to out, and
+// summarized in the result. The error is only set if git fsck couldn't run or
+// check the repository, a repository with broken objects isn't an error, see
+// FsckResult.OK.
+func Fsck(ctx context.Context, path string, out io.Writer) (FsckResult, error) {
+	if !isGitDir(path) {
+		return FsckResult{}, ErrNotAGitRepository
+	}
+
+	w := &fsckWriter{
+		out:     out,
+		missing: map[string]struct{}{},
+		corrupt: map[string]struct{}{},
+	}
+	// Stdout and stderr are the same writer, so it's never written to
+	// concurrently.
+	err := git.NewCommand("fsck", "--no-progress").WithContext(ctx).WithTimeout(-1).
+		RunInDirWithOptions(path, git.RunInDirOptions{Stdout: w, Stderr: w})
+	w.flush()
+
+	res := w.result()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && ctx.Err() == nil {
+		res.Failed = true
+		err = nil
+	}
+	return res, err
+}
+
+// fsckWriter writes the output of git fsck through and classifies its lines.
+type fsckWriter struct {
+	out      io.Writer
+	buf      []byte
+	dangling int
+	// An object that can't be read is reported as both corrupt and missing,
+	// it's only counted as corrupt.
+	missing map[string]struct{}
+	corrupt map[string]struct{}
+}
+
+// Write implements io.Writer.
+func (w *fsckWriter) Write(p []byte) (int, error) {
+	if w.out != nil {
+		if _, err := w.out.Write(p); err != nil {
+			return 0, err
+		}
+	}
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.parse(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush parses the last line if it isn't terminated.
+func (w *fsckWriter) flush() {
+	if len(w.buf) > 0 {
+		w.parse(string(w.buf))
+		w.buf = nil
+	}
+}
+
+// parse classifies a line of output:
+//
+//	dangling blob <id>
+//	missing tree <id>
+//	error: <id>: object corrupt or missing: <path>
+//	error: <id>: hash-path mismatch, found at: <path>
+//	error in commit <id>: <problem>
+func (w *fsckWriter) parse(line string) {
+	fields := strings.Fields(line)
+	switch {
+	case len(fields) == 3 && fields[0] == "dangling":
+		w.dangling++
+	case len(fields) == 3 && fields[0] == "missing":
+		w.missing[fields[2]] = struct{}{}
+	case len(fields) >= 4 && fields[0] == "error" && fields[1] == "in":
+		w.corrupt[strings.TrimSuffix(fields[3], ":")] = struct{}{}
+	case len(fields) >= 3 && fields[0] == "error:" && strings.HasSuffix(fields[1], ":"):
+		if strings.HasPrefix(fields[2], "object") || strings.HasPrefix(fields[2], "hash") {
+			w.corrupt[strings.TrimSuffix(fields[1], ":")] = struct{}{}
+		}
+	}
+}
+
+// result returns the summary of the lines parsed so far.
+func (w *fsckWriter) result() FsckResult {
+	res := FsckResult{Dangling: w.dangling, Corrupt: len(w.corrupt)}
+	for id := range w.missing {
+		if _, ok := w.corrupt[id]; !ok {
+			res.Missing++
+		}
+	}
+	return res
+}
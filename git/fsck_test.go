@@ -0,0 +1,41 @@
+package git
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestFsckWriter(t *testing.T) {
+	is := is.New(t)
+	var out bytes.Buffer
+	w := &fsckWriter{out: &out, missing: map[string]struct{}{}, corrupt: map[string]struct{}{}}
+	output := "error: inflate: data stream error (incorrect header check)\n" +
+		"error: unable to unpack header of objects/78/981922613b2afb6025042ff6bd878ac1994e85\n" +
+		"error: 78981922613b2afb6025042ff6bd878ac1994e85: object corrupt or missing: objects/78/981922613b2afb6025042ff6bd878ac1994e85\n" +
+		"error: f2ad6c76f0115a6ba5b00456a849810e7ec0af20: hash-path mismatch, found at: objects/61/780798228d17af2d34fce4cfbdf35556832472\n" +
+		"error in commit 0123456789abcdef0123456789abcdef01234567: badTimezone: invalid author/committer line - bad time zone\n" +
+		"broken link from    tree 89abcdef0123456789abcdef0123456789abcdef\n" +
+		"              to    blob 8073f2026d6082bf8073f2026d6082bf8073f202\n" +
+		"missing blob 8073f2026d6082bf8073f2026d6082bf8073f202\n" +
+		"missing blob 78981922613b2afb6025042ff6bd878ac1994e85\n" +
+		"dangling blob 61780798228d17af2d34fce4cfbdf35556832472\n" +
+		"dangling commit 89abcdef0123456789abcdef0123456789abcdef"
+
+	// Lines can be split across writes.
+	_, err := w.Write([]byte(output[:100]))
+	is.NoErr(err)
+	_, err = w.Write([]byte(output[100:]))
+	is.NoErr(err)
+	w.flush()
+
+	is.Equal(out.String(), output)
+	res := w.result()
+	is.Equal(res, FsckResult{Dangling: 2, Missing: 1, Corrupt: 3})
+	is.True(!res.OK())
+	is.Equal(res.String(), "1 missing, 3 corrupt, 2 dangling objects")
+
+	is.True(FsckResult{Dangling: 2}.OK())
+	is.True(!FsckResult{Failed: true}.OK())
+}
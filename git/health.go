@@ -0,0 +1,148 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultGCAuto is the default number of loose objects above which git
+	// gc --auto packs them, see gc.auto.
+	DefaultGCAuto = 6700
+	// DefaultGCAutoPackLimit is the default number of packs above which git
+	// gc --auto consolidates them, see gc.autoPackLimit.
+	DefaultGCAutoPackLimit = 50
+)
+
+// ObjectStats are the number and size of the objects of a repository.
+type ObjectStats struct {
+	// Loose is the number of loose objects.
+	Loose int64
+	// LooseSize is the disk size of the loose objects in bytes.
+	LooseSize int64
+	// Packed is the number of packed objects.
+	Packed int64
+	// Packs is the number of packs.
+	Packs int64
+	// PackSize is the disk size of the packs in bytes.
+	PackSize int64
+	// Prunable is the number of loose objects that are also packed.
+	Prunable int64
+	// Garbage is the number of files in the object database that aren't
+	// objects or packs.
+	Garbage int64
+	// GarbageSize is the disk size of the garbage in bytes.
+	GarbageSize int64
+}
+
+// Size returns the disk size of the objects and garbage in bytes.
+func (s ObjectStats) Size() int64 {
+	return s.LooseSize + s.PackSize + s.GarbageSize
+}
+
+// GCThresholds are the limits above which the objects of a repository should
+// be garbage collected. Zero disables a limit.
+type GCThresholds struct {
+	// Loose is the number of loose objects.
+	Loose int64
+	// Packs is the number of packs.
+	Packs int64
+}
+
+// GCReasons returns the reasons the objects should be garbage collected given
+// the thresholds, none if they don't need it.
+func (s ObjectStats) GCReasons(t GCThresholds) []string {
+	reasons := make([]string, 0)
+	if t.Loose > 0 && s.Loose > t.Loose {
+		reasons = append(reasons, fmt.Sprintf("%d loose objects, more than %d", s.Loose, t.Loose))
+	}
+	if t.Packs > 0 && s.Packs > t.Packs {
+		reasons = append(reasons, fmt.Sprintf("%d packs, more than %d", s.Packs, t.Packs))
+	}
+	if s.Garbage > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d garbage files", s.Garbage))
+	}
+	return reasons
+}
+
+// ObjectStats returns the number and size of the objects of the repository.
+// It only counts the files of the object database, it's cheap even for large
+// repositories.
+func (r *Repository) ObjectStats() (*ObjectStats, error) {
+	var stdout, stderr bytes.Buffer
+	if err := NewCommand("count-objects", "-v").
+		RunInDirWithOptions(r.Path, RunInDirOptions{
+			Stdout: &stdout,
+			Stderr: &stderr,
+		}); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+
+	return parseObjectStats(stdout.String())
+}
+
+// GCThresholds returns the thresholds git gc --auto uses for the repository,
+// from its configuration or the Git defaults.
+func (r *Repository) GCThresholds() GCThresholds {
+	return GCThresholds{
+		Loose: r.configInt("gc.auto", DefaultGCAuto),
+		Packs: r.configInt("gc.autoPackLimit", DefaultGCAutoPackLimit),
+	}
+}
+
+// configInt returns the integer value of the given configuration key, def if
+// it's unset or invalid.
+func (r *Repository) configInt(key string, def int64) int64 {
+	out, err := NewCommand("config", "--int", "--get", key).RunInDir(r.Path)
+	if err != nil {
+		return def
+	}
+	v, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// parseObjectStats parses the output of git count-objects -v. Sizes are
+// reported in KiB.
+func parseObjectStats(out string) (*ObjectStats, error) {
+	var s ObjectStats
+	fields := map[string]*int64{
+		"count":          &s.Loose,
+		"size":           &s.LooseSize,
+		"in-pack":        &s.Packed,
+		"packs":          &s.Packs,
+		"size-pack":      &s.PackSize,
+		"prune-packable": &s.Prunable,
+		"garbage":        &s.Garbage,
+		"size-garbage":   &s.GarbageSize,
+	}
+	for _, line := range strings.Split(out, "\n") {
+		k, v, ok := strings.Cut(line, ": ")
+		if !ok {
+			continue
+		}
+		f, ok := fields[k]
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		*f = n
+	}
+
+	s.LooseSize *= 1024
+	s.PackSize *= 1024
+	s.GarbageSize *= 1024
+
+	return &s, nil
+}
@@ -0,0 +1,39 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestParseObjectStats(t *testing.T) {
+	is := is.New(t)
+	s, err := parseObjectStats("count: 12\nsize: 48\nin-pack: 300\npacks: 2\nsize-pack: 120\nprune-packable: 1\ngarbage: 0\nsize-garbage: 0\n")
+	is.NoErr(err)
+	is.Equal(*s, ObjectStats{
+		Loose:     12,
+		LooseSize: 48 * 1024,
+		Packed:    300,
+		Packs:     2,
+		PackSize:  120 * 1024,
+		Prunable:  1,
+	})
+	is.Equal(s.Size(), int64(168*1024))
+
+	_, err = parseObjectStats("count: many\n")
+	is.True(err != nil)
+}
+
+func TestGCReasons(t *testing.T) {
+	is := is.New(t)
+	s := ObjectStats{Loose: 10, Packs: 3}
+	is.Equal(len(s.GCReasons(GCThresholds{Loose: 10, Packs: 3})), 0)
+	is.Equal(s.GCReasons(GCThresholds{Loose: 5, Packs: 2}), []string{
+		"10 loose objects, more than 5",
+		"3 packs, more than 2",
+	})
+	is.Equal(len(s.GCReasons(GCThresholds{})), 0)
+
+	s.Garbage = 2
+	is.Equal(s.GCReasons(GCThresholds{}), []string{"2 garbage files"})
+}
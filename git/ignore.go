@@ -0,0 +1,200 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ignorePattern is a pattern of a .gitignore file.
+type ignorePattern struct {
+	// dir is the directory of the .gitignore file the pattern is from,
+	// empty for the root of the repository.
+	dir string
+	// segments are the parts of the pattern between slashes.
+	segments []string
+	// anchored patterns match relative to dir, the others match the name
+	// of a file or directory at any depth.
+	anchored bool
+	dirOnly  bool
+	negate   bool
+}
+
+// parseIgnorePatterns parses the patterns of the .gitignore file of the
+// given directory.
+func parseIgnorePatterns(dir string, content []byte) []ignorePattern {
+	patterns := make([]ignorePattern, 0)
+	s := bufio.NewScanner(bytes.NewReader(content))
+	for s.Scan() {
+		line := strings.TrimSuffix(s.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !strings.HasSuffix(line, `\ `) {
+			line = strings.TrimRight(line, " ")
+		}
+		p := ignorePattern{dir: dir}
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		// A slash at the start or in the middle anchors the pattern.
+		p.anchored = strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		// fnmatch negates classes with "!", path.Match with "^".
+		line = strings.ReplaceAll(line, "[!", "[^")
+		p.segments = strings.Split(line, "/")
+		patterns = append(patterns, p)
+	}
+	return patterns
+}
+
+// match returns true if the pattern matches the given path, relative to the
+// root of the repository.
+func (p ignorePattern) match(name string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+	if p.dir != "" {
+		rel, ok := strings.CutPrefix(name, p.dir+"/")
+		if !ok {
+			return false
+		}
+		name = rel
+	}
+	if !p.anchored {
+		ok, _ := path.Match(p.segments[0], path.Base(name))
+		return ok
+	}
+	return matchSegments(p.segments, strings.Split(name, "/"))
+}
+
+// matchSegments matches the parts of a path with the parts of a pattern,
+// where "**" matches any number of directories.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			if len(pattern) == 1 {
+				// A trailing "/**" matches everything inside.
+				return len(name) > 0
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// ignoreMatcher matches paths with the patterns of .gitignore files, in
+// increasing order of priority.
+type ignoreMatcher []ignorePattern
+
+// ignored returns true if the file at the given path is ignored, because a
+// pattern matches it or one of its directories. Like with Git, files in an
+// ignored directory can't be included again.
+func (m ignoreMatcher) ignored(name string) bool {
+	parts := strings.Split(name, "/")
+	for i := 1; i <= len(parts); i++ {
+		if m.match(strings.Join(parts[:i], "/"), i < len(parts)) {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns true if the last pattern matching the path excludes it.
+func (m ignoreMatcher) match(name string, isDir bool) bool {
+	for i := len(m) - 1; i >= 0; i-- {
+		if m[i].match(name, isDir) {
+			return !m[i].negate
+		}
+	}
+	return false
+}
+
+// IgnoredFiles returns the given paths that match the .gitignore files of
+// the given revision, keyed by path. The .gitignore files of the directories
+// of a path apply to it, the deepest one taking precedence, like with Git.
+func (r *Repository) IgnoredFiles(rev string, paths ...string) (map[string]bool, error) {
+	ignored := map[string]bool{}
+	if len(paths) == 0 {
+		return ignored, nil
+	}
+
+	seen := map[string]bool{}
+	dirs := make([]string, 0)
+	for _, p := range paths {
+		if strings.Contains(p, "\n") {
+			continue
+		}
+		for dir := path.Dir(p); ; dir = path.Dir(dir) {
+			if dir == "." {
+				dir = ""
+			}
+			if !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+			if dir == "" {
+				break
+			}
+		}
+	}
+	// The patterns of the deeper directories come last, they take
+	// precedence.
+	depth := func(dir string) int {
+		if dir == "" {
+			return 0
+		}
+		return strings.Count(dir, "/") + 1
+	}
+	sort.SliceStable(dirs, func(i, j int) bool {
+		return depth(dirs[i]) < depth(dirs[j])
+	})
+
+	names := make([]string, len(dirs))
+	for i, dir := range dirs {
+		names[i] = rev + ":" + path.Join(dir, ".gitignore")
+	}
+	objs, err := r.catFileBatch(names)
+	if err != nil {
+		return nil, err
+	}
+	var m ignoreMatcher
+	for i, obj := range objs {
+		if obj.Type == "blob" {
+			m = append(m, parseIgnorePatterns(dirs[i], obj.Content)...)
+		}
+	}
+	if len(m) == 0 {
+		return ignored, nil
+	}
+	for _, p := range paths {
+		if m.ignored(p) {
+			ignored[p] = true
+		}
+	}
+	return ignored, nil
+}
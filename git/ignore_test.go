@@ -0,0 +1,43 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+	is := is.New(t)
+	var m ignoreMatcher
+	m = append(m, parseIgnorePatterns("", []byte("# build output\n*.o\n/dist\nbuild/\n!keep.o\nlogs/**/*.log\n\\#notes\n"))...)
+	m = append(m, parseIgnorePatterns("web", []byte("node_modules/\n*.min.js\n!vendor.min.js\n/local.json\n"))...)
+
+	cases := map[string]bool{
+		"main.o":                      true,
+		"src/util.o":                  true,
+		"keep.o":                      false,
+		"src/keep.o":                  false,
+		"dist":                        true,
+		"dist/app":                    true,
+		"src/dist/app":                false,
+		"build/out":                   true,
+		"src/build/out":               true,
+		"build":                       false,
+		"logs/a/b/debug.log":          true,
+		"logs/debug.log":              true,
+		"logs/debug.txt":              false,
+		"#notes":                      true,
+		"README.md":                   false,
+		"web/node_modules/x/index.js": true,
+		"web/app.min.js":              true,
+		"web/vendor.min.js":           false,
+		"app.min.js":                  false,
+		"web/local.json":              true,
+		"web/src/local.json":          false,
+		// Files in an ignored directory can't be included again.
+		"build/keep.o": true,
+	}
+	for name, want := range cases {
+		is.Equal(m.ignored(name), want) // name
+	}
+}
@@ -0,0 +1,145 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CommitSummary is the abbreviated hash and the subject of a commit.
+type CommitSummary struct {
+	// Hash is the abbreviated hash of the commit.
+	Hash string
+	// Subject is the first line of the commit message.
+	Subject string
+}
+
+// RecentCommits returns the abbreviated hashes and subjects of the latest
+// commits reachable from any reference, newest first. It returns at most
+// limit commits.
+func (r *Repository) RecentCommits(limit int) ([]CommitSummary, error) {
+	out, err := NewCommand("log", "--all", "--format=%h%x00%s",
+		"--max-count="+strconv.Itoa(limit)).RunInDir(r.Path)
+	if err != nil {
+		return nil, err
+	}
+	return parseCommitSummaries(string(out)), nil
+}
+
+// parseCommitSummaries parses the output of RecentCommits. Every line is an
+// abbreviated hash and a subject separated by a NUL.
+func parseCommitSummaries(out string) []CommitSummary {
+	commits := make([]CommitSummary, 0)
+	for _, line := range strings.Split(out, "\n") {
+		hash, subject, _ := strings.Cut(line, "\x00")
+		if hash == "" {
+			continue
+		}
+		commits = append(commits, CommitSummary{Hash: hash, Subject: subject})
+	}
+	return commits
+}
+
+// CommitMessage is the hash, the number of parents, and the message of a
+// commit.
+type CommitMessage struct {
+	Hash    string
+	Parents int
+	Message string
+}
+
+// NewCommitMessages returns the messages of the commits reachable from the
+// given commit hashes that aren't reachable from any reference of the
+// repository, e.g. the commits of a push that haven't been accepted yet. The
+// oldest commits come first.
+func (r *Repository) NewCommitMessages(revs ...string) ([]CommitMessage, error) {
+	if len(revs) == 0 {
+		return []CommitMessage{}, nil
+	}
+
+	for _, rev := range revs {
+		if !isHash(rev) {
+			return nil, ErrObjectNotFound
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	args := append([]string{"log", "-z", "--reverse", "--format=%H %P%n%B"}, revs...)
+	if err := NewCommand(append(args, "--not", "--all")...).
+		WithTimeout(-1).
+		RunInDirWithOptions(r.Path, RunInDirOptions{
+			Stdout: &stdout,
+			Stderr: &stderr,
+		}); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+
+	return parseCommitMessages(stdout.String()), nil
+}
+
+// parseCommitMessages parses the output of NewCommitMessages. Every commit is
+// a line with its hash and the hashes of its parents, then its message, and
+// is terminated by a NUL.
+func parseCommitMessages(out string) []CommitMessage {
+	commits := make([]CommitMessage, 0)
+	for _, rec := range strings.Split(out, "\x00") {
+		header, message, _ := strings.Cut(rec, "\n")
+		fields := strings.Fields(header)
+		if len(fields) == 0 {
+			continue
+		}
+		commits = append(commits, CommitMessage{
+			Hash:    fields[0],
+			Parents: len(fields) - 1,
+			Message: strings.TrimRight(message, "\n"),
+		})
+	}
+	return commits
+}
+
+// CommitAuthor is the author of a commit and when it was authored.
+type CommitAuthor struct {
+	Name  string
+	Email string
+	When  time.Time
+}
+
+// CommitAuthors returns the authors of the commits reachable from the given
+// ref, merges aside, newest first. The identities are the ones the commits
+// were made with, see Mailmap to map them to canonical ones.
+func (r *Repository) CommitAuthors(ref *Reference) ([]CommitAuthor, error) {
+	out, err := NewCommand("log", "--no-merges", "--format=%at%x00%an%x00%ae", ref.ID).
+		WithTimeout(-1).
+		RunInDir(r.Path)
+	if err != nil {
+		return nil, err
+	}
+	return parseCommitAuthors(string(out)), nil
+}
+
+// parseCommitAuthors parses the output of CommitAuthors. Every line is a
+// timestamp, a name, and an email separated by NULs.
+func parseCommitAuthors(out string) []CommitAuthor {
+	authors := make([]CommitAuthor, 0)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 3 {
+			continue
+		}
+		sec, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		authors = append(authors, CommitAuthor{
+			Name:  fields[1],
+			Email: fields[2],
+			When:  time.Unix(sec, 0),
+		})
+	}
+	return authors
+}
@@ -0,0 +1,41 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestParseCommitSummaries(t *testing.T) {
+	is := is.New(t)
+	commits := parseCommitSummaries("0123456\x00Second commit\n89abcde\x00\n\n")
+	is.Equal(commits, []CommitSummary{
+		{Hash: "0123456", Subject: "Second commit"},
+		{Hash: "89abcde", Subject: ""},
+	})
+	is.Equal(len(parseCommitSummaries("")), 0)
+}
+
+func TestParseCommitMessages(t *testing.T) {
+	is := is.New(t)
+	out := "89abcde \nfirst\n\nwith a body\n\n\x00" +
+		"0123456 89abcde fedcba9\nMerge branch 'feature'\n\x00"
+	is.Equal(parseCommitMessages(out), []CommitMessage{
+		{Hash: "89abcde", Parents: 0, Message: "first\n\nwith a body"},
+		{Hash: "0123456", Parents: 2, Message: "Merge branch 'feature'"},
+	})
+	is.Equal(len(parseCommitMessages("")), 0)
+}
+
+func TestParseCommitAuthors(t *testing.T) {
+	is := is.New(t)
+	out := "1700000000\x00Frankie\x00frankie@example.com\n" +
+		"1600000000\x00\x00nameless@example.com\n" +
+		"garbage\n\n"
+	is.Equal(parseCommitAuthors(out), []CommitAuthor{
+		{Name: "Frankie", Email: "frankie@example.com", When: time.Unix(1700000000, 0)},
+		{Name: "", Email: "nameless@example.com", When: time.Unix(1600000000, 0)},
+	})
+	is.Equal(len(parseCommitAuthors("")), 0)
+}
@@ -0,0 +1,180 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// MailmapFile is the path of the mailmap in the tree.
+const MailmapFile = ".mailmap"
+
+// mailmapCache caches the mailmaps keyed by repository path and commit.
+var mailmapCache, _ = lru.New[string, *Mailmap](100)
+
+// Mailmap maps the names and emails commits are made with to canonical ones,
+// like git does with a .mailmap file. See gitmailmap(5).
+type Mailmap struct {
+	// entries are keyed by the lower case commit email.
+	entries map[string]*mailmapEntry
+}
+
+// mailmapEntry is the mapping of a commit email, for any name and for
+// specific names, keyed by the lower case commit name.
+type mailmapEntry struct {
+	mailmapIdentity
+	names map[string]mailmapIdentity
+}
+
+// mailmapIdentity is a canonical identity, an empty name or email is kept as
+// is.
+type mailmapIdentity struct {
+	name  string
+	email string
+}
+
+// ParseMailmap parses the content of a .mailmap file. Lines it doesn't
+// understand are ignored, like git does.
+func ParseMailmap(data []byte) *Mailmap {
+	m := &Mailmap{entries: map[string]*mailmapEntry{}}
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		line := s.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		name1, email1, rest, ok := parseMailmapIdentity(line)
+		if !ok {
+			continue
+		}
+		name2, email2, _, ok := parseMailmapIdentity(rest)
+		if !ok {
+			// Proper Name <commit@email>
+			m.add(name1, "", "", email1)
+			continue
+		}
+		// <proper@email> <commit@email>
+		// Proper Name <proper@email> <commit@email>
+		// Proper Name <proper@email> Commit Name <commit@email>
+		m.add(name1, email1, name2, email2)
+	}
+	return m
+}
+
+// parseMailmapIdentity parses a "Name <email>" identity at the start of s,
+// the name being optional, and returns what follows it.
+func parseMailmapIdentity(s string) (name, email, rest string, ok bool) {
+	left := strings.IndexByte(s, '<')
+	if left < 0 {
+		return "", "", "", false
+	}
+	right := strings.IndexByte(s[left:], '>')
+	if right < 0 {
+		return "", "", "", false
+	}
+	right += left
+	return strings.TrimSpace(s[:left]), s[left+1 : right], s[right+1:], true
+}
+
+// add adds the mapping of an identity, the name of which is empty to match
+// any name, to a canonical one.
+func (m *Mailmap) add(name, email, oldName, oldEmail string) {
+	k := strings.ToLower(oldEmail)
+	e, ok := m.entries[k]
+	if !ok {
+		e = &mailmapEntry{names: map[string]mailmapIdentity{}}
+		m.entries[k] = e
+	}
+	if oldName == "" {
+		if name != "" {
+			e.name = name
+		}
+		if email != "" {
+			e.email = email
+		}
+		return
+	}
+	e.names[strings.ToLower(oldName)] = mailmapIdentity{name: name, email: email}
+}
+
+// Map returns the canonical name and email of the given identity. Emails and
+// names are matched regardless of case, identities the mailmap doesn't know
+// are returned as is.
+func (m *Mailmap) Map(name, email string) (string, string) {
+	if m == nil {
+		return name, email
+	}
+	e, ok := m.entries[strings.ToLower(email)]
+	if !ok {
+		return name, email
+	}
+	id, ok := e.names[strings.ToLower(name)]
+	if !ok {
+		id = e.mailmapIdentity
+	}
+	if id.name != "" {
+		name = id.name
+	}
+	if id.email != "" {
+		email = id.email
+	}
+	return name, email
+}
+
+// MapSignature returns the signature with the canonical name and email. The
+// signature is returned as is if the mailmap doesn't know it.
+func (m *Mailmap) MapSignature(sig *Signature) *Signature {
+	if sig == nil {
+		return nil
+	}
+	name, email := m.Map(sig.Name, sig.Email)
+	if name == sig.Name && email == sig.Email {
+		return sig
+	}
+	return &Signature{Name: name, Email: email, When: sig.When}
+}
+
+// MapCommits replaces the authors and committers of the given commits with
+// their canonical identities.
+func (m *Mailmap) MapCommits(commits ...*Commit) {
+	if m == nil || len(m.entries) == 0 {
+		return
+	}
+	for _, c := range commits {
+		if c == nil {
+			continue
+		}
+		c.Author = m.MapSignature(c.Author)
+		c.Committer = m.MapSignature(c.Committer)
+	}
+}
+
+// Mailmap returns the mailmap of the given ref, read from the .mailmap file
+// at its root. The mailmap is empty, and maps nothing, when there's no such
+// file. The result is cached per commit.
+func (r *Repository) Mailmap(ref *Reference) (*Mailmap, error) {
+	key := r.Path + "@" + ref.ID
+	if m, ok := mailmapCache.Get(key); ok {
+		return m, nil
+	}
+
+	m := &Mailmap{entries: map[string]*mailmapEntry{}}
+	info, err := r.ObjectInfo(ref.ID + ":" + MailmapFile)
+	switch {
+	case errors.Is(err, ErrObjectNotFound):
+	case err != nil:
+		return nil, err
+	case info.Type == "blob":
+		var buf bytes.Buffer
+		if err := r.CatObject(info.ID, &buf); err != nil {
+			return nil, err
+		}
+		m = ParseMailmap(buf.Bytes())
+	}
+
+	mailmapCache.Add(key, m)
+	return m, nil
+}
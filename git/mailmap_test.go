@@ -0,0 +1,53 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestParseMailmap(t *testing.T) {
+	is := is.New(t)
+
+	m := ParseMailmap([]byte(`# The team
+Jane Doe <jane@example.com>
+<john@example.com> <john@old.example.com>
+Bob Smith <bob@example.com> <bob@laptop>
+Alice <alice@example.com> alice <root@localhost>
+Eve Example <eve@example.com> Eve <EVE@other.example.com> # trailing comment
+not an identity
+`))
+
+	cases := []struct {
+		name, email   string
+		wname, wemail string
+	}{
+		// Proper Name <commit@email>
+		{"jane", "JANE@example.com", "Jane Doe", "JANE@example.com"},
+		// <proper@email> <commit@email>
+		{"John", "john@old.example.com", "John", "john@example.com"},
+		// Proper Name <proper@email> <commit@email>
+		{"bob", "bob@laptop", "Bob Smith", "bob@example.com"},
+		// Proper Name <proper@email> Commit Name <commit@email>
+		{"Alice", "root@localhost", "Alice", "alice@example.com"},
+		{"root", "root@localhost", "root", "root@localhost"},
+		{"eve", "eve@other.example.com", "Eve Example", "eve@example.com"},
+		// Unknown identities are kept.
+		{"Mallory", "mallory@example.com", "Mallory", "mallory@example.com"},
+	}
+	for _, c := range cases {
+		name, email := m.Map(c.name, c.email)
+		is.Equal(name, c.wname)
+		is.Equal(email, c.wemail)
+	}
+
+	// An empty or missing mailmap maps nothing.
+	var none *Mailmap
+	name, email := none.Map("Jane", "jane@example.com")
+	is.Equal(name, "Jane")
+	is.Equal(email, "jane@example.com")
+
+	sig := &Signature{Name: "Mallory", Email: "mallory@example.com"}
+	is.True(m.MapSignature(sig) == sig)
+	is.Equal(m.MapSignature(&Signature{Name: "bob", Email: "bob@laptop"}).Name, "Bob Smith")
+}
@@ -0,0 +1,271 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// MergeOptions are options for TrialMerge.
+type MergeOptions struct {
+	// Quick stops the merge as soon as the first conflict is found. The result
+	// only tells whether the merge is clean and has no tree.
+	Quick bool
+}
+
+// MergeResult is the result of a trial merge.
+type MergeResult struct {
+	// Tree is the ID of the merged tree. Conflicting files contain diff3 style
+	// conflict markers.
+	Tree string
+
+	// Conflicts are the paths that conflict.
+	Conflicts []string
+}
+
+// Clean returns true if the merge has no conflicts.
+func (m *MergeResult) Clean() bool {
+	return len(m.Conflicts) == 0
+}
+
+// TrialMerge merges head into base in memory and reports the conflicting
+// paths. It writes the merged objects to the object database but never
+// updates any refs, the index, or the working tree. ErrNoMergeBase is
+// returned when base and head have unrelated histories.
+//
+// This requires Git 2.38 or later.
+func (r *Repository) TrialMerge(base, head string, opts ...MergeOptions) (*MergeResult, error) {
+	var opt MergeOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	res := &MergeResult{}
+	w := &mergeTreeWriter{
+		result: res,
+		quick:  opt.Quick,
+		cancel: cancel,
+	}
+
+	var stderr bytes.Buffer
+	cmd := NewCommand("-c", "merge.conflictStyle=diff3",
+		"merge-tree", "--write-tree", "--no-messages", "--name-only", "-z",
+		"--", base, head).WithContext(ctx)
+	err := cmd.RunInDirPipeline(w, &stderr, r.Path)
+	w.flush()
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case opt.Quick && !res.Clean():
+		// We stopped the merge early.
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+		// Exit code 1 means the merge has conflicts.
+	case strings.Contains(stderr.String(), "unrelated histories"):
+		return nil, ErrNoMergeBase
+	default:
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+
+	if opt.Quick {
+		res.Tree = ""
+	}
+
+	return res, nil
+}
+
+// MergeBase returns the best common ancestor of two revisions. When all is
+// true, it returns all the best common ancestors instead of just one.
+// ErrNoMergeBase is returned when the revisions have no common ancestor.
+func (r *Repository) MergeBase(a, b string, all bool) ([]string, error) {
+	args := []string{"merge-base"}
+	if all {
+		args = append(args, "--all")
+	}
+	for _, rev := range []string{a, b} {
+		if strings.HasPrefix(rev, "-") {
+			return nil, ErrRevisionNotExist
+		}
+		id, err := r.RevParse(rev + "^{commit}")
+		if err != nil {
+			return nil, ErrRevisionNotExist
+		}
+		args = append(args, id)
+	}
+
+	var stdout, stderr bytes.Buffer
+	err := NewCommand(args...).RunInDirPipeline(&stdout, &stderr, r.Path)
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+		// Exit code 1 means there is no common ancestor.
+		return nil, ErrNoMergeBase
+	default:
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+
+	return strings.Fields(stdout.String()), nil
+}
+
+// Unrelated returns true if the two revisions have no common ancestor, e.g.
+// when one of them is on an orphan branch.
+func (r *Repository) Unrelated(a, b string) (bool, error) {
+	_, err := r.MergeBase(a, b, false)
+	switch {
+	case err == nil:
+		return false, nil
+	case errors.Is(err, ErrNoMergeBase):
+		return true, nil
+	default:
+		return false, err
+	}
+}
+
+// ConflictContent returns the content of a conflicting path of a trial merge
+// including the conflict markers.
+func (r *Repository) ConflictContent(res *MergeResult, path string) ([]byte, error) {
+	if res.Tree == "" {
+		return nil, ErrFileNotFound
+	}
+	out, err := NewCommand("cat-file", "blob", res.Tree+":"+path).RunInDir(r.Path)
+	if err != nil {
+		return nil, ErrFileNotFound
+	}
+	return out, nil
+}
+
+// MergeResolution is what a merge commit changes on top of the automatic merge
+// of its parents.
+type MergeResolution struct {
+	// Diff is the diff from the automatic merge to the merge commit. The
+	// conflicting files of the automatic merge have diff3 style conflict
+	// markers, the diff shows how they were resolved.
+	*Diff
+
+	// Conflicts are the paths the automatic merge conflicts on.
+	Conflicts []string
+}
+
+// Edits returns the paths the merge commit changes that don't conflict in the
+// automatic merge. These changes come from neither parent, they were made
+// while merging, also known as an evil merge.
+func (m *MergeResolution) Edits() []string {
+	conflicts := make(map[string]bool, len(m.Conflicts))
+	for _, c := range m.Conflicts {
+		conflicts[c] = true
+	}
+	edits := make([]string, 0)
+	for _, f := range m.Files {
+		if !conflicts[f.Name] {
+			edits = append(edits, f.Name)
+		}
+	}
+	return edits
+}
+
+// MergeResolution returns what a merge commit changes on top of the automatic
+// merge of its two parents, how its conflicts were resolved and the edits made
+// while merging. Clean merges have an empty diff. ErrNotMergeCommit is
+// returned for commits that don't have two parents.
+//
+// This requires Git 2.38 or later.
+func (r *Repository) MergeResolution(commit *Commit, opts DiffOptions) (*MergeResolution, error) {
+	if commit.ParentsCount() != 2 {
+		return nil, ErrNotMergeCommit
+	}
+	parents := make([]string, 2)
+	for i := range parents {
+		id, err := commit.ParentID(i)
+		if err != nil {
+			return nil, err
+		}
+		parents[i] = id.String()
+	}
+
+	res, err := r.TrialMerge(parents[0], parents[1])
+	if err != nil {
+		return nil, err
+	}
+	diff, err := r.diff(opts.Args(), res.Tree, commit.ID.String()+"^{tree}")
+	if err != nil {
+		return nil, err
+	}
+	return &MergeResolution{
+		Diff:      diff,
+		Conflicts: res.Conflicts,
+	}, nil
+}
+
+// mergeTreeWriter parses the NUL separated output of git merge-tree
+// --name-only -z as it's written. The first field is the tree ID, followed by
+// the conflicting paths and an empty field.
+type mergeTreeWriter struct {
+	result *MergeResult
+	quick  bool
+	cancel context.CancelFunc
+	buf    bytes.Buffer
+	done   bool
+}
+
+func (w *mergeTreeWriter) Write(p []byte) (int, error) {
+	if w.done {
+		return len(p), nil
+	}
+	w.buf.Write(p)
+	s := bufio.NewScanner(bytes.NewReader(w.buf.Bytes()))
+	s.Split(scanNUL)
+	consumed := 0
+	for s.Scan() {
+		field := s.Text()
+		consumed += len(field) + 1
+		w.field(field)
+		if w.done {
+			break
+		}
+	}
+	w.buf.Next(consumed)
+	return len(p), nil
+}
+
+func (w *mergeTreeWriter) flush() {
+	if !w.done && w.buf.Len() > 0 {
+		w.field(w.buf.String())
+	}
+}
+
+func (w *mergeTreeWriter) field(f string) {
+	switch {
+	case w.result.Tree == "":
+		w.result.Tree = f
+	case f == "":
+		w.done = true
+	default:
+		w.result.Conflicts = append(w.result.Conflicts, f)
+		if w.quick {
+			w.done = true
+			w.cancel()
+		}
+	}
+}
+
+// scanNUL is a bufio.SplitFunc that splits on NUL bytes. It never returns a
+// partial field.
+func scanNUL(data []byte, _ bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,82 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestMergeTreeWriter(t *testing.T) {
+	cases := []struct {
+		name   string
+		chunks []string
+		quick  bool
+		want   MergeResult
+	}{
+		{
+			name:   "clean",
+			chunks: []string{"8073f2026d6082bf\x00"},
+			want:   MergeResult{Tree: "8073f2026d6082bf"},
+		},
+		{
+			name:   "conflicts",
+			chunks: []string{"d42f6e61\x00a.txt\x00dir/b.txt\x00"},
+			want: MergeResult{
+				Tree:      "d42f6e61",
+				Conflicts: []string{"a.txt", "dir/b.txt"},
+			},
+		},
+		{
+			name:   "split writes",
+			chunks: []string{"d42f", "6e61\x00a.t", "xt\x00dir/", "b.txt\x00"},
+			want: MergeResult{
+				Tree:      "d42f6e61",
+				Conflicts: []string{"a.txt", "dir/b.txt"},
+			},
+		},
+		{
+			name:   "quick",
+			chunks: []string{"d42f6e61\x00a.txt\x00dir/b.txt\x00"},
+			quick:  true,
+			want: MergeResult{
+				Tree:      "d42f6e61",
+				Conflicts: []string{"a.txt"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			is := is.New(t)
+			cancelled := false
+			res := &MergeResult{}
+			w := &mergeTreeWriter{
+				result: res,
+				quick:  c.quick,
+				cancel: func() { cancelled = true },
+			}
+			for _, chunk := range c.chunks {
+				n, err := w.Write([]byte(chunk))
+				is.NoErr(err)
+				is.Equal(n, len(chunk))
+			}
+			w.flush()
+			is.Equal(res.Tree, c.want.Tree)
+			is.Equal(res.Conflicts, c.want.Conflicts)
+			is.Equal(res.Clean(), len(c.want.Conflicts) == 0)
+			is.Equal(cancelled, c.quick)
+		})
+	}
+}
+
+func TestMergeResolutionEdits(t *testing.T) {
+	is := is.New(t)
+	m := &MergeResolution{
+		Diff:      parseTestDiff(t, testPatch),
+		Conflicts: []string{"a.txt", "c.txt"},
+	}
+	is.Equal(m.Edits(), []string{"b.txt"})
+
+	m.Conflicts = nil
+	is.Equal(m.Edits(), []string{"a.txt", "b.txt"})
+}
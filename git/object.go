@@ -0,0 +1,309 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ObjectInfo is the type and size of an object.
+type ObjectInfo struct {
+	// ID is the full hash of the object.
+	ID string
+	// Type is the type of the object, i.e. blob, tree, commit, or tag.
+	Type string
+	// Size is the size of the object in bytes.
+	Size int64
+}
+
+// ObjectInfo returns the type and size of the object with the given name. The
+// name can be an abbreviated hash or any revision Git understands.
+func (r *Repository) ObjectInfo(name string) (*ObjectInfo, error) {
+	var stdout, stderr bytes.Buffer
+	// Pass the name on stdin so that it's never parsed as an option.
+	if err := NewCommand("cat-file", "--batch-check").
+		RunInDirWithOptions(r.Path, RunInDirOptions{
+			Stdin:  strings.NewReader(name + "\n"),
+			Stdout: &stdout,
+			Stderr: &stderr,
+		}); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+
+	return parseObjectInfo(stdout.String())
+}
+
+// ObjectInfos returns the type and size of the objects with the given hashes,
+// in order, read in one pass. Only the headers of the objects are read, not
+// their content. Missing objects are nil.
+func (r *Repository) ObjectInfos(ids []string) ([]*ObjectInfo, error) {
+	if len(ids) == 0 {
+		return []*ObjectInfo{}, nil
+	}
+	for _, id := range ids {
+		if !isHash(id) {
+			return nil, ErrObjectNotFound
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := NewCommand("cat-file", "--batch-check").
+		WithTimeout(-1).
+		RunInDirWithOptions(r.Path, RunInDirOptions{
+			Stdin:  strings.NewReader(strings.Join(ids, "\n") + "\n"),
+			Stdout: &stdout,
+			Stderr: &stderr,
+		}); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+
+	return parseObjectInfos(stdout.String(), len(ids)), nil
+}
+
+// CatObject writes the pretty-printed content of the object with the given
+// hash to w. Blobs are written as is.
+func (r *Repository) CatObject(id string, w io.Writer) error {
+	if !isHash(id) {
+		return ErrObjectNotFound
+	}
+
+	var stderr bytes.Buffer
+	if err := NewCommand("cat-file", "-p", id).
+		WithTimeout(-1).
+		RunInDirWithOptions(r.Path, RunInDirOptions{
+			Stdout: w,
+			Stderr: &stderr,
+		}); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return errors.New(msg)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// batchObject is an object read with git cat-file --batch.
+type batchObject struct {
+	// Name is the name the object was asked for, e.g. a hash or
+	// "HEAD:README.md".
+	Name string
+	// Type is the type of the object, or empty if it doesn't exist.
+	Type string
+	// Content is the raw content of the object.
+	Content []byte
+}
+
+// catFileBatch returns the objects with the given names, in order. The names
+// can be hashes or any revision Git understands.
+func (r *Repository) catFileBatch(names []string) ([]batchObject, error) {
+	if len(names) == 0 {
+		return []batchObject{}, nil
+	}
+	for _, name := range names {
+		if strings.ContainsAny(name, "\n") {
+			return nil, ErrObjectNotFound
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := NewCommand("cat-file", "--batch").
+		WithTimeout(-1).
+		RunInDirWithOptions(r.Path, RunInDirOptions{
+			Stdin:  strings.NewReader(strings.Join(names, "\n") + "\n"),
+			Stdout: &stdout,
+			Stderr: &stderr,
+		}); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+
+	br := bufio.NewReader(&stdout)
+	objs := make([]batchObject, 0, len(names))
+	for _, name := range names {
+		obj, err := readBatchObject(br)
+		if err != nil {
+			return nil, err
+		}
+		obj.Name = name
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// readBatchObject reads the next object of the output of git cat-file
+// --batch. Missing objects have an empty type.
+func readBatchObject(br *bufio.Reader) (batchObject, error) {
+	var obj batchObject
+	header, err := br.ReadString('\n')
+	if err != nil {
+		return obj, err
+	}
+	fields := strings.Fields(header)
+	if len(fields) != 3 {
+		// "<name> missing" or "<name> ambiguous"
+		return obj, nil
+	}
+	size, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return obj, err
+	}
+	content := make([]byte, size+1)
+	if _, err := io.ReadFull(br, content); err != nil {
+		return obj, err
+	}
+	obj.Type, obj.Content = fields[1], content[:size]
+	return obj, nil
+}
+
+// PathObject is an object with the path it's found at in a tree. Commits and
+// root trees have an empty path.
+type PathObject struct {
+	ObjectInfo
+	// Path is the path of the object relative to the root tree.
+	Path string
+}
+
+// NewObjects returns the objects reachable from the given commit hashes that
+// aren't reachable from any reference of the repository, e.g. the objects of
+// a push that haven't been accepted yet.
+func (r *Repository) NewObjects(revs ...string) ([]PathObject, error) {
+	if len(revs) == 0 {
+		return []PathObject{}, nil
+	}
+
+	for _, rev := range revs {
+		if !isHash(rev) {
+			return nil, ErrObjectNotFound
+		}
+	}
+
+	var objects, stderr bytes.Buffer
+	args := append([]string{"rev-list", "--objects"}, revs...)
+	if err := NewCommand(append(args, "--not", "--all")...).
+		WithTimeout(-1).
+		RunInDirWithOptions(r.Path, RunInDirOptions{
+			Stdout: &objects,
+			Stderr: &stderr,
+		}); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+
+	var stdout bytes.Buffer
+	stderr.Reset()
+	if err := NewCommand("cat-file", "--batch-check=%(objectname) %(objecttype) %(objectsize) %(rest)").
+		WithTimeout(-1).
+		RunInDirWithOptions(r.Path, RunInDirOptions{
+			Stdin:  &objects,
+			Stdout: &stdout,
+			Stderr: &stderr,
+		}); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+
+	return parsePathObjects(stdout.String())
+}
+
+// parsePathObjects parses the output of git cat-file --batch-check with the
+// object name, type, size, and path of every object.
+func parsePathObjects(out string) ([]PathObject, error) {
+	objects := make([]PathObject, 0)
+	for _, line := range strings.Split(out, "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, " ", 4)
+		if len(fields) < 3 {
+			return nil, ErrObjectNotFound
+		}
+		size, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		obj := PathObject{
+			ObjectInfo: ObjectInfo{
+				ID:   fields[0],
+				Type: fields[1],
+				Size: size,
+			},
+		}
+		if len(fields) == 4 {
+			obj.Path = fields[3]
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// parseObjectInfo parses a line of git cat-file --batch-check output.
+func parseObjectInfo(out string) (*ObjectInfo, error) {
+	fields := strings.Fields(out)
+	switch {
+	case len(fields) == 2 && fields[1] == "missing":
+		return nil, ErrObjectNotFound
+	case len(fields) == 2 && fields[1] == "ambiguous":
+		return nil, ErrAmbiguousObject
+	case len(fields) != 3:
+		return nil, ErrObjectNotFound
+	}
+
+	size, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ObjectInfo{
+		ID:   fields[0],
+		Type: fields[1],
+		Size: size,
+	}, nil
+}
+
+// parseObjectInfos parses the git cat-file --batch-check output of n objects.
+// Missing objects are nil.
+func parseObjectInfos(out string, n int) []*ObjectInfo {
+	infos := make([]*ObjectInfo, n)
+	for i, l := range strings.SplitN(out, "\n", n+1) {
+		if i == n {
+			break
+		}
+		infos[i], _ = parseObjectInfo(l)
+	}
+	return infos
+}
+
+// isAbbrevHash returns true if s is a hash, or a hash abbreviated to at
+// least 4 characters like Git does.
+func isAbbrevHash(s string) bool {
+	if len(s) < 4 || len(s) > 64 {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// isHash returns true if s is a full hexadecimal object hash.
+func isHash(s string) bool {
+	return (len(s) == 40 || len(s) == 64) && isAbbrevHash(s)
+}
@@ -0,0 +1,93 @@
+package git
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestParseObjectInfo(t *testing.T) {
+	is := is.New(t)
+	info, err := parseObjectInfo("8073f2026d6082bf8073f2026d6082bf8073f202 blob 12\n")
+	is.NoErr(err)
+	is.Equal(*info, ObjectInfo{
+		ID:   "8073f2026d6082bf8073f2026d6082bf8073f202",
+		Type: "blob",
+		Size: 12,
+	})
+
+	_, err = parseObjectInfo("abc missing\n")
+	is.Equal(err, ErrObjectNotFound)
+	_, err = parseObjectInfo("abc ambiguous\n")
+	is.Equal(err, ErrAmbiguousObject)
+	_, err = parseObjectInfo("")
+	is.Equal(err, ErrObjectNotFound)
+}
+
+func TestParseObjectInfos(t *testing.T) {
+	is := is.New(t)
+	infos := parseObjectInfos("8073f2026d6082bf8073f2026d6082bf8073f202 blob 12\n"+
+		"0123456789abcdef0123456789abcdef01234567 missing\n"+
+		"89abcdef0123456789abcdef0123456789abcdef blob 0\n", 3)
+	is.Equal(len(infos), 3)
+	is.Equal(infos[0].Size, int64(12))
+	is.True(infos[1] == nil)
+	is.Equal(*infos[2], ObjectInfo{
+		ID:   "89abcdef0123456789abcdef0123456789abcdef",
+		Type: "blob",
+	})
+
+	// Missing output leaves the objects nil.
+	infos = parseObjectInfos("", 2)
+	is.True(infos[0] == nil && infos[1] == nil)
+}
+
+func TestParsePathObjects(t *testing.T) {
+	is := is.New(t)
+	objects, err := parsePathObjects("0123456789abcdef0123456789abcdef01234567 commit 180 \n" +
+		"89abcdef0123456789abcdef0123456789abcdef tree 70 \n" +
+		"8073f2026d6082bf8073f2026d6082bf8073f202 blob 12 docs/read me.md\n")
+	is.NoErr(err)
+	is.Equal(len(objects), 3)
+	is.Equal(objects[0].Type, "commit")
+	is.Equal(objects[0].Path, "")
+	is.Equal(objects[2], PathObject{
+		ObjectInfo: ObjectInfo{
+			ID:   "8073f2026d6082bf8073f2026d6082bf8073f202",
+			Type: "blob",
+			Size: 12,
+		},
+		Path: "docs/read me.md",
+	})
+
+	_, err = parsePathObjects("abc missing\n")
+	is.Equal(err, ErrObjectNotFound)
+}
+
+func TestReadBatchObject(t *testing.T) {
+	is := is.New(t)
+	out := "1111111111111111111111111111111111111111 commit 3\nabc\n" +
+		"HEAD:missing missing\n" +
+		"2222222222222222222222222222222222222222 blob 2\nxy\n"
+	br := bufio.NewReader(strings.NewReader(out))
+
+	obj, err := readBatchObject(br)
+	is.NoErr(err)
+	is.Equal(obj.Type, "commit")
+	is.Equal(string(obj.Content), "abc")
+
+	obj, err = readBatchObject(br)
+	is.NoErr(err)
+	is.Equal(obj.Type, "")
+
+	obj, err = readBatchObject(br)
+	is.NoErr(err)
+	is.Equal(obj.Type, "blob")
+	is.Equal(string(obj.Content), "xy")
+
+	_, err = readBatchObject(br)
+	is.Equal(err, io.EOF)
+}
@@ -333,6 +333,122 @@ func (d *Diff) Patch() string {
 	return p.String()
 }
 
+// LineAt returns the file at the given zero based line of the patch and the
+// line number, one based, of that line in the old version of the file. Added
+// lines and headers map to the closest line of the old file. The file is nil
+// if the line is past the end of the patch.
+func (d *Diff) LineAt(n int) (*DiffFile, int) {
+	for _, f := range d.Files {
+		header := patchHeaderLines(f)
+		if n < header {
+			return f, oldLineAt(f, 0, 0)
+		}
+		n -= header
+		for i, s := range f.Sections {
+			if n < len(s.Lines) {
+				return f, oldLineAt(f, i, n)
+			}
+			n -= len(s.Lines)
+		}
+	}
+	return nil, 0
+}
+
+// FileStart returns the zero based line of the patch where the file with the
+// given name starts. It returns -1 if the file isn't part of the diff.
+func (d *Diff) FileStart(name string) int {
+	var n int
+	for _, f := range d.Files {
+		from, to := f.Files()
+		if (to != nil && to.Name() == name) || (to == nil && from != nil && from.Name() == name) {
+			return n
+		}
+		n += patchHeaderLines(f)
+		for _, s := range f.Sections {
+			n += len(s.Lines)
+		}
+	}
+	return -1
+}
+
+// DiffHunk is the position of a hunk, a section of a file, in the patch of a
+// diff.
+type DiffHunk struct {
+	// File is the index of the file of the hunk in Files.
+	File int
+	// Line is the zero based line of the patch of the "@@" header of the hunk.
+	Line int
+	// Change is the zero based line of the patch of the first added or
+	// deleted line of the hunk, the header if it has none.
+	Change int
+}
+
+// Hunks returns the positions of the hunks of all files in the patch, in
+// order.
+func (d *Diff) Hunks() []DiffHunk {
+	hunks := make([]DiffHunk, 0)
+	var n int
+	for i, f := range d.Files {
+		n += patchHeaderLines(f)
+		for _, s := range f.Sections {
+			h := DiffHunk{File: i, Line: n, Change: n}
+			for j, l := range s.Lines {
+				if l.Type == git.DiffLineAdd || l.Type == git.DiffLineDelete {
+					h.Change = n + j
+					break
+				}
+			}
+			hunks = append(hunks, h)
+			n += len(s.Lines)
+		}
+	}
+	return hunks
+}
+
+// FileStarts returns the zero based lines of the patch where the files start,
+// in order.
+func (d *Diff) FileStarts() []int {
+	starts := make([]int, 0, len(d.Files))
+	var n int
+	for _, f := range d.Files {
+		starts = append(starts, n)
+		n += patchHeaderLines(f)
+		for _, s := range f.Sections {
+			n += len(s.Lines)
+		}
+	}
+	return starts
+}
+
+// patchHeaderLines returns the number of header lines of the file in the
+// patch.
+func patchHeaderLines(f *DiffFile) int {
+	var sb strings.Builder
+	writeFilePatchHeader(&sb, f)
+	return strings.Count(sb.String(), "\n")
+}
+
+// oldLineAt returns the line number in the old file of the given line of a
+// section. Lines that don't exist in the old file use the closest preceding
+// line, or the following one at the start of a section.
+func oldLineAt(f *DiffFile, section, line int) int {
+	if section >= len(f.Sections) {
+		return 1
+	}
+	lines := f.Sections[section].Lines
+	for i := min(line, len(lines)-1); i >= 0; i-- {
+		if l := lines[i]; l.Type != git.DiffLineAdd && l.LeftLine > 0 {
+			return l.LeftLine
+		}
+	}
+	for _, l := range lines[line:] {
+		if l.Type != git.DiffLineAdd && l.LeftLine > 0 {
+			return l.LeftLine
+		}
+	}
+	return 1
+}
+
 func toDiff(ddiff *git.Diff) *Diff {
 	files := make([]*DiffFile, 0, len(ddiff.Files))
 	for _, df := range ddiff.Files {
@@ -0,0 +1,85 @@
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aymanbagabas/git-module"
+	"github.com/matryer/is"
+)
+
+const testPatch = `diff --git a/a.txt b/a.txt
+index 78981922613b2afb6025042ff6bd878ac1994e85..f4a1b43ed2e1a2c1d5e9fd402fc8e6f4961d3d70 100644
+--- a/a.txt
++++ b/a.txt
+@@ -2,3 +2,3 @@
+ b
+-c
++C
+ d
+diff --git a/b.txt b/b.txt
+new file mode 100644
+index 0000000000000000000000000000000000000000..617807982c41bb5da4ae2d2ef3c943a0b5e5df3a
+--- /dev/null
++++ b/b.txt
+@@ -0,0 +1 @@
++b
+`
+
+func parseTestDiff(t *testing.T, patch string) *Diff {
+	t.Helper()
+	done := make(chan git.SteamParseDiffResult)
+	go git.StreamParseDiff(strings.NewReader(patch), done, 0, 0, 0)
+	res := <-done
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+	return toDiff(res.Diff)
+}
+
+func TestDiffLineAt(t *testing.T) {
+	is := is.New(t)
+	d := parseTestDiff(t, testPatch)
+	is.Equal(d.Patch(), testPatch)
+
+	for _, c := range []struct {
+		line int
+		file string
+		old  int
+	}{
+		{0, "a.txt", 2}, // header
+		{4, "a.txt", 2}, // hunk header
+		{5, "a.txt", 2},
+		{6, "a.txt", 3},
+		{7, "a.txt", 3}, // added line
+		{8, "a.txt", 4},
+		{9, "b.txt", 1},
+		{15, "b.txt", 1},
+	} {
+		f, old := d.LineAt(c.line)
+		is.True(f != nil)
+		is.Equal(f.Name, c.file)
+		is.Equal(old, c.old)
+	}
+
+	f, _ := d.LineAt(16)
+	is.True(f == nil)
+}
+
+func TestDiffFileStart(t *testing.T) {
+	is := is.New(t)
+	d := parseTestDiff(t, testPatch)
+	is.Equal(d.FileStart("a.txt"), 0)
+	is.Equal(d.FileStart("b.txt"), 9)
+	is.Equal(d.FileStart("c.txt"), -1)
+}
+
+func TestDiffHunks(t *testing.T) {
+	is := is.New(t)
+	d := parseTestDiff(t, testPatch)
+	is.Equal(d.Hunks(), []DiffHunk{
+		{File: 0, Line: 4, Change: 6},
+		{File: 1, Line: 14, Change: 15},
+	})
+	is.Equal(d.FileStarts(), []int{0, 9})
+}
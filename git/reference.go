@@ -1,7 +1,10 @@
 package git
 
 import (
+	"bytes"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aymanbagabas/git-module"
 )
@@ -13,6 +16,9 @@ const (
 	RefsHeads = git.RefsHeads
 	// RefsTags represents the prefix for tag references.
 	RefsTags = git.RefsTags
+	// RefsRemotes represents the prefix for remote-tracking branch
+	// references.
+	RefsRemotes = "refs/remotes/"
 )
 
 // Reference is a wrapper around git.Reference with helper methods.
@@ -29,11 +35,30 @@ func (r ReferenceName) String() string {
 	return string(r)
 }
 
-// Short returns the short name of the reference i.e. master.
+// Short returns the short name of the reference i.e. master. Remote-tracking
+// branches are named after their branch too, without the remote.
 func (r ReferenceName) Short() string {
+	if name, ok := remoteBranchName(string(r)); ok {
+		return name
+	}
 	return git.RefShortName(string(r))
 }
 
+// remoteBranchName returns the name of the branch of a remote-tracking
+// branch, i.e. master for refs/remotes/origin/master. The HEAD of remotes
+// isn't a branch.
+func remoteBranchName(ref string) (string, bool) {
+	rest, ok := strings.CutPrefix(ref, RefsRemotes)
+	if !ok {
+		return "", false
+	}
+	_, name, ok := strings.Cut(rest, "/")
+	if !ok || name == "" || name == HEAD {
+		return "", false
+	}
+	return name, true
+}
+
 // Name returns the reference name i.e. refs/heads/master.
 func (r *Reference) Name() ReferenceName {
 	return ReferenceName(r.Refspec)
@@ -48,3 +73,295 @@ func (r *Reference) IsBranch() bool {
 func (r *Reference) IsTag() bool {
 	return strings.HasPrefix(r.Refspec, git.RefsTags)
 }
+
+// IsRemoteBranch returns true if the reference is a remote-tracking branch,
+// i.e. refs/remotes/origin/master.
+func (r *Reference) IsRemoteBranch() bool {
+	_, ok := remoteBranchName(r.Refspec)
+	return ok
+}
+
+// MirrorReferences returns the references of a mirror out of the given
+// ones. Mirrors copy all the references of a repository, and the
+// remote-tracking branches of a non-bare one are branches of the mirror too
+// unless it has a branch of the same name. The HEAD of remotes is left out.
+func MirrorReferences(refs []*ReferenceInfo) []*ReferenceInfo {
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		if ref.IsBranch() {
+			seen[ref.Name().Short()] = true
+		}
+	}
+	mirrored := make([]*ReferenceInfo, 0, len(refs))
+	for _, ref := range refs {
+		if strings.HasPrefix(ref.Refspec, RefsRemotes) {
+			if !ref.IsRemoteBranch() || seen[ref.Name().Short()] {
+				continue
+			}
+			seen[ref.Name().Short()] = true
+		}
+		mirrored = append(mirrored, ref)
+	}
+	return mirrored
+}
+
+// remoteBranch returns the first remote-tracking branch of the branch with
+// the given name, in the order of the remotes' names.
+func (r *Repository) remoteBranch(name string) (*Reference, error) {
+	out, err := NewCommand("for-each-ref", "--format=%(objectname) %(refname)").
+		AddArgs("--", RefsRemotes).
+		RunInDir(r.Path)
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range parseReferences(out, r.Path) {
+		if ref.IsRemoteBranch() && ref.Name().Short() == name {
+			return ref, nil
+		}
+	}
+	return nil, ErrReferenceNotExist
+}
+
+// ReferenceInfo is a reference with the commit it points to. It's used to
+// list many references without reading each one of them separately.
+type ReferenceInfo struct {
+	*Reference
+
+	// Commit is the commit the reference points to, peeling annotated tags.
+	// It's nil if the reference doesn't point to a commit. Only the hash,
+	// signatures and message of the commit are set.
+	Commit *Commit
+
+	// TagMessage is the message of annotated tags.
+	TagMessage string
+
+	// Tagger is the tagger of annotated tags. It's nil for other references
+	// and tags without a tagger.
+	Tagger *git.Signature
+
+	// TagChain is the chain of annotated tags of a tag that points to another
+	// tag. It's empty for other references.
+	TagChain []AnnotatedTag
+
+	// nested is true if the annotated tag points to another tag.
+	nested bool
+}
+
+// refInfoFields are the for-each-ref fields read by ReferencesInfo. Fields
+// prefixed with an asterisk belong to the object an annotated tag points to.
+var refInfoFields = []string{
+	"refname", "objectname", "objecttype", "contents",
+	"authorname", "authoremail", "authordate:raw",
+	"committername", "committeremail", "committerdate:raw",
+	"*objectname", "*objecttype", "*contents",
+	"*authorname", "*authoremail", "*authordate:raw",
+	"*committername", "*committeremail", "*committerdate:raw",
+	"taggername", "taggeremail", "taggerdate:raw",
+}
+
+// ReferencesInfo returns the references that match the given patterns, or all
+// the references when there are none, along with the commits they point to.
+// It reads all the references and their commits in one pass, including
+// packed references.
+func (r *Repository) ReferencesInfo(patterns ...string) ([]*ReferenceInfo, error) {
+	format := make([]string, len(refInfoFields))
+	for i, f := range refInfoFields {
+		format[i] = "%(" + f + ")%00"
+	}
+
+	out, err := NewCommand("for-each-ref", "--format="+strings.Join(format, "")).
+		AddArgs(append([]string{"--"}, patterns...)...).
+		RunInDir(r.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := parseReferencesInfo(out, r.Path)
+	for _, ref := range refs {
+		if !ref.nested {
+			continue
+		}
+
+		// for-each-ref only peels one tag, follow nested tags to the
+		// commit.
+		chain, err := r.TagChain(ref.ID)
+		if err != nil || len(chain) == 0 {
+			continue
+		}
+		ref.TagChain = chain
+		if last := chain[len(chain)-1]; last.ObjectType == "commit" {
+			if c, err := r.CatFileCommit(last.Object); err == nil {
+				ref.Commit = c
+			}
+		}
+	}
+
+	return refs, nil
+}
+
+// ReferenceFullName returns the full name of the reference with the given
+// name, a full reference name or the name of a branch or tag, in that order,
+// and last the name of a remote-tracking branch, see MirrorReferences. It
+// returns ErrReferenceNotExist if there's no such reference.
+func (r *Repository) ReferenceFullName(name string) (string, error) {
+	refs, err := r.References()
+	if err != nil {
+		return "", err
+	}
+	for _, full := range []string{name, RefsHeads + name, RefsTags + name} {
+		for _, ref := range refs {
+			if ref.Name().String() == full {
+				return full, nil
+			}
+		}
+	}
+	for _, ref := range refs {
+		if ref.IsRemoteBranch() && ref.Name().Short() == name {
+			return ref.Name().String(), nil
+		}
+	}
+	return "", ErrReferenceNotExist
+}
+
+// ReferencesContaining returns the branches and tags the commit with the given
+// hash is reachable from, sorted by name. Git uses the generation numbers of
+// the commit-graph to walk less history when the repository has one.
+func (r *Repository) ReferencesContaining(id string) ([]*Reference, error) {
+	if !isHash(id) {
+		return nil, ErrObjectNotFound
+	}
+	out, err := NewCommand("for-each-ref", "--format=%(objectname) %(refname)", "--contains", id).
+		AddArgs("--", RefsHeads, RefsTags).
+		RunInDir(r.Path)
+	if err != nil {
+		return nil, err
+	}
+	return parseReferences(out, r.Path), nil
+}
+
+// BranchesNotMerged returns the branches that have commits the commit with
+// the given hash doesn't, sorted by name.
+func (r *Repository) BranchesNotMerged(id string) ([]*Reference, error) {
+	if !isHash(id) {
+		return nil, ErrObjectNotFound
+	}
+	out, err := NewCommand("for-each-ref", "--format=%(objectname) %(refname)", "--no-merged", id).
+		AddArgs("--", RefsHeads).
+		RunInDir(r.Path)
+	if err != nil {
+		return nil, err
+	}
+	return parseReferences(out, r.Path), nil
+}
+
+// CountReferences returns the number of references of the repository, of any
+// kind. The references aren't read, so it's cheap even with many of them.
+func (r *Repository) CountReferences() (int, error) {
+	out, err := NewCommand("for-each-ref", "--format=").RunInDir(r.Path)
+	if err != nil {
+		return 0, err
+	}
+	return bytes.Count(out, []byte("\n")), nil
+}
+
+// parseReferences parses lines of object hashes followed by a space and the
+// name of the reference pointing to them.
+func parseReferences(out []byte, path string) []*Reference {
+	refs := make([]*Reference, 0)
+	for _, line := range strings.Split(string(out), "\n") {
+		id, name, ok := strings.Cut(line, " ")
+		if !ok || name == "" {
+			continue
+		}
+		refs = append(refs, &Reference{
+			Reference: &git.Reference{
+				ID:      id,
+				Refspec: name,
+			},
+			path: path,
+		})
+	}
+	return refs
+}
+
+// parseReferencesInfo parses the output of ReferencesInfo. Every record is a
+// NUL terminated list of fields followed by a newline.
+func parseReferencesInfo(out []byte, path string) []*ReferenceInfo {
+	fields := strings.Split(string(out), "\x00")
+	n := len(refInfoFields)
+	refs := make([]*ReferenceInfo, 0, len(fields)/n)
+	for len(fields) >= n {
+		rec := fields[:n]
+		fields = fields[n:]
+		name := strings.TrimPrefix(rec[0], "\n")
+		if name == "" {
+			continue
+		}
+
+		info := &ReferenceInfo{
+			Reference: &Reference{
+				Reference: &git.Reference{
+					ID:      rec[1],
+					Refspec: name,
+				},
+				path: path,
+			},
+		}
+		switch rec[2] {
+		case "commit":
+			info.Commit = parseRefCommit(rec[1], rec[3], rec[4:10])
+		case "tag":
+			info.TagMessage = rec[3]
+			if rec[21] != "" {
+				info.Tagger = parseRefSignature(rec[19], rec[20], rec[21])
+			}
+			switch rec[11] {
+			case "commit":
+				info.Commit = parseRefCommit(rec[10], rec[12], rec[13:19])
+			case "tag":
+				info.nested = true
+			}
+		}
+		refs = append(refs, info)
+	}
+	return refs
+}
+
+// parseRefCommit returns a commit from its hash, message, and the author and
+// committer name, email and raw date fields.
+func parseRefCommit(id, message string, sigs []string) *Commit {
+	sha, err := git.NewIDFromString(id)
+	if err != nil {
+		return nil
+	}
+	return &Commit{
+		ID:        sha,
+		Message:   message,
+		Author:    parseRefSignature(sigs[0], sigs[1], sigs[2]),
+		Committer: parseRefSignature(sigs[3], sigs[4], sigs[5]),
+	}
+}
+
+// parseRefSignature returns a signature from a name, an email wrapped in angle
+// brackets, and a raw date i.e. "1700000000 +0100".
+func parseRefSignature(name, email, date string) *git.Signature {
+	sig := &git.Signature{
+		Name:  name,
+		Email: strings.TrimSuffix(strings.TrimPrefix(email, "<"), ">"),
+	}
+	fs := strings.Fields(date)
+	if len(fs) == 0 {
+		return sig
+	}
+	sec, err := strconv.ParseInt(fs[0], 10, 64)
+	if err != nil {
+		return sig
+	}
+	sig.When = time.Unix(sec, 0)
+	if len(fs) > 1 {
+		if tz, err := time.Parse("-0700", fs[1]); err == nil {
+			sig.When = sig.When.In(tz.Location())
+		}
+	}
+	return sig
+}
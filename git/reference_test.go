@@ -0,0 +1,147 @@
+package git
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aymanbagabas/git-module"
+	"github.com/matryer/is"
+)
+
+func TestParseReferencesInfo(t *testing.T) {
+	is := is.New(t)
+	const (
+		commit = "0123456789abcdef0123456789abcdef01234567"
+		tag    = "89abcdef0123456789abcdef0123456789abcdef"
+	)
+	record := func(fields ...string) string {
+		fs := make([]string, len(refInfoFields))
+		copy(fs, fields)
+		return strings.Join(fs, "\x00") + "\x00\n"
+	}
+	out := record("refs/heads/main", commit, "commit", "Initial commit\n",
+		"Foo", "<foo@bar.baz>", "1700000000 +0100",
+		"Bar", "<bar@bar.baz>", "1700000100 -0230") +
+		record("refs/tags/v1.0.0", tag, "tag", "Release v1.0.0\n",
+			"", "", "", "", "", "",
+			commit, "commit", "Initial commit\n",
+			"Foo", "<foo@bar.baz>", "1700000000 +0100",
+			"Bar", "<bar@bar.baz>", "1700000100 -0230",
+			"Baz", "<baz@bar.baz>", "1700000200 +0000") +
+		record("refs/tags/blob", tag, "blob") +
+		record("refs/tags/nested", tag, "tag", "Nested\n",
+			"", "", "", "", "", "",
+			commit, "tag", "Release v1.0.0\n")
+
+	refs := parseReferencesInfo([]byte(out), "/tmp/repo")
+	is.Equal(len(refs), 4)
+
+	main := refs[0]
+	is.Equal(main.Name().String(), "refs/heads/main")
+	is.Equal(main.ID, commit)
+	is.True(main.IsBranch())
+	is.Equal(main.TagMessage, "")
+	is.True(main.Commit != nil)
+	is.Equal(main.Commit.ID.String(), commit)
+	is.Equal(main.Commit.Message, "Initial commit\n")
+	is.Equal(main.Commit.Author.Name, "Foo")
+	is.Equal(main.Commit.Author.Email, "foo@bar.baz")
+	is.Equal(main.Commit.Author.When.Unix(), int64(1700000000))
+	_, offset := main.Commit.Author.When.Zone()
+	is.Equal(offset, 3600)
+	is.Equal(main.Commit.Committer.Name, "Bar")
+	_, offset = main.Commit.Committer.When.Zone()
+	is.Equal(offset, -9000)
+
+	v1 := refs[1]
+	is.True(v1.IsTag())
+	is.Equal(v1.ID, tag)
+	is.Equal(v1.TagMessage, "Release v1.0.0\n")
+	is.True(v1.Commit != nil)
+	is.Equal(v1.Commit.ID.String(), commit)
+	is.Equal(v1.Commit.Author.When.Unix(), int64(1700000000))
+	is.True(v1.Tagger != nil)
+	is.Equal(v1.Tagger.Name, "Baz")
+	is.Equal(v1.Tagger.When.Unix(), int64(1700000200))
+	is.True(main.Tagger == nil)
+
+	blob := refs[2]
+	is.True(blob.IsTag())
+	is.True(blob.Commit == nil)
+	is.True(!blob.nested)
+
+	nested := refs[3]
+	is.Equal(nested.TagMessage, "Nested\n")
+	is.True(nested.Commit == nil)
+	is.True(nested.nested)
+}
+
+func TestParseReferences(t *testing.T) {
+	is := is.New(t)
+	const commit = "0123456789abcdef0123456789abcdef01234567"
+	out := commit + " refs/heads/main\n" +
+		commit + " refs/tags/v1.0.0\n" +
+		"\n"
+	refs := parseReferences([]byte(out), "/tmp/repo")
+	is.Equal(len(refs), 2)
+	is.True(refs[0].IsBranch())
+	is.Equal(refs[0].Name().Short(), "main")
+	is.Equal(refs[0].ID, commit)
+	is.True(refs[1].IsTag())
+	is.Equal(refs[1].Name().Short(), "v1.0.0")
+	is.Equal(len(parseReferences(nil, "/tmp/repo")), 0)
+}
+
+func TestMirrorReferences(t *testing.T) {
+	is := is.New(t)
+	ref := func(name string) *ReferenceInfo {
+		return &ReferenceInfo{Reference: &Reference{Reference: &git.Reference{Refspec: name}}}
+	}
+	refs := MirrorReferences([]*ReferenceInfo{
+		ref("refs/heads/main"),
+		ref("refs/remotes/origin/HEAD"),
+		ref("refs/remotes/origin/feature/x"),
+		ref("refs/remotes/origin/main"),
+		ref("refs/remotes/upstream/feature/x"),
+		ref("refs/tags/v1.0.0"),
+	})
+	names := make([]string, len(refs))
+	for i, r := range refs {
+		names[i] = r.Name().String()
+	}
+	is.Equal(names, []string{"refs/heads/main", "refs/remotes/origin/feature/x", "refs/tags/v1.0.0"})
+
+	is.True(refs[1].IsRemoteBranch())
+	is.True(!refs[1].IsBranch())
+	is.Equal(refs[1].Name().Short(), "feature/x")
+	is.True(!ref("refs/remotes/origin/HEAD").IsRemoteBranch())
+	is.Equal(ReferenceName("refs/remotes/origin/HEAD").Short(), "refs/remotes/origin/HEAD")
+}
+
+func TestSortReleases(t *testing.T) {
+	is := is.New(t)
+	release := func(name string, sec int64) *Release {
+		return &Release{
+			ReferenceInfo: &ReferenceInfo{
+				Reference: &Reference{Reference: &git.Reference{Refspec: RefsTags + name}},
+			},
+			Date: time.Unix(sec, 0),
+		}
+	}
+	rels := []*Release{
+		release("v1.0.0", 100),
+		release("v1.2.0", 300),
+		release("v1.1.0", 200),
+		release("v1.1.1", 200),
+	}
+	sortReleases(rels)
+	names := make([]string, len(rels))
+	for i, rel := range rels {
+		names[i] = rel.Name().Short()
+	}
+	is.Equal(names, []string{"v1.2.0", "v1.1.1", "v1.1.0", "v1.0.0"})
+	is.Equal(rels[0].Previous, rels[1])
+	is.Equal(rels[2].Previous, rels[3])
+	is.True(rels[3].Previous == nil)
+}
@@ -0,0 +1,147 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReflogEntry is an update of a reference recorded in its reflog.
+type ReflogEntry struct {
+	// Index is the index of the entry, 0 being the latest, as in REF@{0}.
+	Index int
+	// Old is the hash the reference pointed to before the update, ZeroID
+	// when it was created.
+	Old string
+	// New is the hash the reference pointed to after the update, ZeroID
+	// when it was deleted.
+	New string
+	// Name and Email are the identity that updated the reference.
+	Name  string
+	Email string
+	// When is the time of the update.
+	When time.Time
+	// Message is the message of the update, e.g. "push".
+	Message string
+}
+
+// ReflogRefs returns the names of the references that have a reflog, sorted,
+// HEAD first. References only have a reflog when core.logAllRefUpdates is
+// set, which bare repositories don't do by default.
+func (r *Repository) ReflogRefs() ([]string, error) {
+	dir := filepath.Join(r.Path, "logs")
+	refs := make([]string, 0)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		refs = append(refs, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if (refs[i] == HEAD) != (refs[j] == HEAD) {
+			return refs[i] == HEAD
+		}
+		return refs[i] < refs[j]
+	})
+	return refs, nil
+}
+
+// ReflogRef returns the full name of the reference with a reflog that ref
+// names, e.g. "refs/heads/main" for "main". It returns ErrReferenceNotExist
+// if there's no such reflog.
+func (r *Repository) ReflogRef(ref string) (string, error) {
+	refs, err := r.ReflogRefs()
+	if err != nil {
+		return "", err
+	}
+	for _, name := range []string{ref, RefsHeads + ref, RefsTags + ref, "refs/" + ref} {
+		for _, rr := range refs {
+			if rr == name {
+				return rr, nil
+			}
+		}
+	}
+	return "", ErrReferenceNotExist
+}
+
+// Reflog returns the entries of the reflog of the given full reference name,
+// the latest first. Use ReflogRef to get the name of a reference with a
+// reflog.
+func (r *Repository) Reflog(ref string) ([]ReflogEntry, error) {
+	data, err := os.ReadFile(filepath.Join(r.Path, "logs", filepath.FromSlash(ref)))
+	if os.IsNotExist(err) {
+		return nil, ErrReferenceNotExist
+	} else if err != nil {
+		return nil, err
+	}
+	return parseReflog(data), nil
+}
+
+// parseReflog parses a reflog file. Every line is an update, the oldest first:
+//
+//	OLD NEW NAME <EMAIL> TIMESTAMP TZ\tMESSAGE
+//
+// Malformed lines are skipped. The entries are returned the latest first.
+func parseReflog(data []byte) []ReflogEntry {
+	entries := make([]ReflogEntry, 0)
+	s := bufio.NewScanner(bytes.NewReader(data))
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for s.Scan() {
+		line, msg, _ := strings.Cut(s.Text(), "\t")
+		old, rest, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		hash, rest, ok := strings.Cut(rest, " ")
+		if !ok {
+			continue
+		}
+		lt, gt := strings.Index(rest, "<"), strings.LastIndex(rest, ">")
+		if lt < 0 || gt < lt {
+			continue
+		}
+		e := ReflogEntry{
+			Old:     old,
+			New:     hash,
+			Name:    strings.TrimSpace(rest[:lt]),
+			Email:   rest[lt+1 : gt],
+			Message: msg,
+		}
+		if fields := strings.Fields(rest[gt+1:]); len(fields) == 2 {
+			if sec, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+				e.When = time.Unix(sec, 0)
+				if tz, err := time.Parse("-0700", fields[1]); err == nil {
+					e.When = e.When.In(tz.Location())
+				}
+			}
+		}
+		entries = append(entries, e)
+	}
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	for i := range entries {
+		entries[i].Index = i
+	}
+	return entries
+}
@@ -0,0 +1,34 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestParseReflog(t *testing.T) {
+	is := is.New(t)
+	a := "1111111111111111111111111111111111111111"
+	b := "2222222222222222222222222222222222222222"
+	data := ZeroID + " " + a + " Soft Serve <soft@serve> 1700000000 +0200\tpush\n" +
+		"garbage\n" +
+		a + " " + b + " Jane Doe <jane@example.com> 1700000100 -0500\tupdate by push\n"
+
+	entries := parseReflog([]byte(data))
+	is.Equal(len(entries), 2)
+
+	is.Equal(entries[0].Index, 0)
+	is.Equal(entries[0].Old, a)
+	is.Equal(entries[0].New, b)
+	is.Equal(entries[0].Name, "Jane Doe")
+	is.Equal(entries[0].Email, "jane@example.com")
+	is.Equal(entries[0].Message, "update by push")
+	is.True(entries[0].When.Equal(time.Unix(1700000100, 0)))
+	_, offset := entries[0].When.Zone()
+	is.Equal(offset, -5*60*60)
+
+	is.Equal(entries[1].Index, 1)
+	is.Equal(entries[1].Old, ZeroID)
+	is.Equal(entries[1].Message, "push")
+}
@@ -0,0 +1,74 @@
+package git
+
+import (
+	"sort"
+	"time"
+)
+
+// Release is a tag of a repository that points to a commit, and the release
+// before it.
+type Release struct {
+	*ReferenceInfo
+
+	// Date is when annotated tags were tagged, and when the commit was made
+	// for lightweight tags.
+	Date time.Time
+
+	// Previous is the release before this one, nil for the first release.
+	Previous *Release
+}
+
+// Releases returns the tags of the repository that point to commits, the
+// latest first. Tags that point to trees or blobs aren't releases.
+func (r *Repository) Releases() ([]*Release, error) {
+	refs, err := r.ReferencesInfo(RefsTags)
+	if err != nil {
+		return nil, err
+	}
+
+	rels := make([]*Release, 0, len(refs))
+	for _, ref := range refs {
+		if ref.Commit == nil {
+			continue
+		}
+		rel := &Release{ReferenceInfo: ref}
+		if ref.Commit.Committer != nil {
+			rel.Date = ref.Commit.Committer.When
+		}
+		if ref.Tagger != nil && !ref.Tagger.When.IsZero() {
+			rel.Date = ref.Tagger.When
+		}
+		rels = append(rels, rel)
+	}
+	sortReleases(rels)
+	return rels, nil
+}
+
+// sortReleases sorts releases by date, the latest first, and links each one
+// to the release before it. Releases made at the same time are sorted by
+// name.
+func sortReleases(rels []*Release) {
+	sort.SliceStable(rels, func(i, j int) bool {
+		if !rels[i].Date.Equal(rels[j].Date) {
+			return rels[i].Date.After(rels[j].Date)
+		}
+		return rels[i].Name().String() > rels[j].Name().String()
+	})
+	for i, rel := range rels {
+		rel.Previous = nil
+		if i+1 < len(rels) {
+			rel.Previous = rels[i+1]
+		}
+	}
+}
+
+// ReleaseCommits returns the hashes of the commits of a release, the ones
+// reachable from its tag but not from the previous release, the latest
+// first.
+func (r *Repository) ReleaseCommits(rel *Release) ([]string, error) {
+	var f LogFilter
+	if rel.Previous != nil {
+		f.Since = rel.Previous.Commit.ID.String()
+	}
+	return r.FilterCommits(rel.Reference, f)
+}
@@ -0,0 +1,42 @@
+package git
+
+import (
+	"strings"
+)
+
+// Remote is a remote of a repository.
+type Remote struct {
+	// Name is the name of the remote, e.g. "origin".
+	Name string
+	// URL is the URL the remote is fetched from.
+	URL string
+}
+
+// Remotes returns the remotes of the repository in the order they appear in
+// its configuration.
+func (r *Repository) Remotes() ([]Remote, error) {
+	entries, err := r.ConfigEntries()
+	if err != nil {
+		return nil, err
+	}
+	return remotesFromConfig(entries), nil
+}
+
+// remotesFromConfig returns the remotes that have a URL in the configuration
+// entries. Only the first URL of a remote is kept.
+func remotesFromConfig(entries []ConfigEntry) []Remote {
+	remotes := make([]Remote, 0)
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Key, "remote.") || !strings.HasSuffix(e.Key, ".url") {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(e.Key, "remote."), ".url")
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		remotes = append(remotes, Remote{Name: name, URL: e.Value})
+	}
+	return remotes
+}
@@ -1,7 +1,11 @@
 package git
 
 import (
+	"bytes"
+	"errors"
+	"io"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/aymanbagabas/git-module"
@@ -66,11 +70,31 @@ func Open(path string) (*Repository, error) {
 func (r *Repository) HEAD() (*Reference, error) {
 	rn, err := r.Repository.SymbolicRef(git.SymbolicRefOptions{Name: "HEAD"})
 	if err != nil {
-		return nil, err
+		// HEAD might be detached and point to a commit directly.
+		hash, rerr := r.RevParse("HEAD^{commit}")
+		if rerr != nil {
+			return nil, err
+		}
+		return &Reference{
+			Reference: &git.Reference{
+				ID:      hash,
+				Refspec: HEAD,
+			},
+			path: r.Path,
+		}, nil
 	}
 	hash, err := r.ShowRefVerify(rn)
 	if err != nil {
-		return nil, err
+		// A mirror of a non-bare repository may only have the branch of
+		// HEAD as a remote-tracking branch.
+		if !strings.HasPrefix(rn, RefsHeads) {
+			return nil, err
+		}
+		ref, rerr := r.remoteBranch(strings.TrimPrefix(rn, RefsHeads))
+		if rerr != nil {
+			return nil, err
+		}
+		return ref, nil
 	}
 	return &Reference{
 		Reference: &git.Reference{
@@ -97,6 +121,18 @@ func (r *Repository) References() ([]*Reference, error) {
 	return rrefs, nil
 }
 
+// CommitReference returns a detached reference that points to the given
+// commit.
+func (r *Repository) CommitReference(id string) *Reference {
+	return &Reference{
+		Reference: &git.Reference{
+			ID:      id,
+			Refspec: id,
+		},
+		path: r.Path,
+	}
+}
+
 // LsTree returns the tree for the given reference.
 func (r *Repository) LsTree(ref string) (*Tree, error) {
 	tree, err := r.Repository.LsTree(ref)
@@ -122,26 +158,114 @@ func (r *Repository) Tree(ref *Reference) (*Tree, error) {
 	return r.LsTree(ref.ID)
 }
 
-// TreePath returns the tree for the given path.
+// TreePath returns the tree for the given path. The tree is resolved in one
+// pass, only the trees on its path are read.
 func (r *Repository) TreePath(ref *Reference, path string) (*Tree, error) {
-	path = filepath.Clean(path)
+	path = filepath.ToSlash(filepath.Clean(path))
 	if path == "." {
 		path = ""
 	}
 	if path == "" {
 		return r.Tree(ref)
 	}
-	t, err := r.Tree(ref)
+	if ref == nil {
+		rref, err := r.HEAD()
+		if err != nil {
+			return nil, err
+		}
+		ref = rref
+	}
+	t, err := r.LsTree(ref.ID + ":" + path)
 	if err != nil {
 		return nil, err
 	}
-	return t.SubTree(path)
+	t.Path = path
+	return t, nil
+}
+
+const (
+	// DefaultDiffContext is the default number of context lines of a diff.
+	DefaultDiffContext = 3
+	// MaxDiffContext is the maximum number of context lines of a diff.
+	MaxDiffContext = 50
+)
+
+// DiffWhitespace is how a diff handles whitespace changes.
+type DiffWhitespace int
+
+const (
+	// DiffWhitespaceShow shows all whitespace changes.
+	DiffWhitespaceShow DiffWhitespace = iota
+	// DiffWhitespaceIgnoreAll ignores whitespace when comparing lines, like
+	// -w.
+	DiffWhitespaceIgnoreAll
+	// DiffWhitespaceIgnoreBlankLines ignores changes whose lines are all
+	// blank, like --ignore-blank-lines.
+	DiffWhitespaceIgnoreBlankLines
+	// DiffWhitespaceIgnoreAllAndBlankLines combines both.
+	DiffWhitespaceIgnoreAllAndBlankLines
+)
+
+// Next returns the next whitespace mode, wrapping around.
+func (w DiffWhitespace) Next() DiffWhitespace {
+	return (w + 1) % (DiffWhitespaceIgnoreAllAndBlankLines + 1)
+}
+
+// Args returns the git diff arguments of the whitespace mode.
+func (w DiffWhitespace) Args() []string {
+	switch w {
+	case DiffWhitespaceIgnoreAll:
+		return []string{"-w"}
+	case DiffWhitespaceIgnoreBlankLines:
+		return []string{"--ignore-blank-lines"}
+	case DiffWhitespaceIgnoreAllAndBlankLines:
+		return []string{"-w", "--ignore-blank-lines"}
+	default:
+		return []string{}
+	}
+}
+
+// String returns the git diff arguments of the whitespace mode.
+func (w DiffWhitespace) String() string {
+	return strings.Join(w.Args(), " ")
+}
+
+// DiffOptions are the options of a commit diff.
+type DiffOptions struct {
+	// Context is the number of context lines around changes.
+	Context int
+	// Whitespace is how whitespace changes are handled.
+	Whitespace DiffWhitespace
+	// Parent is the index of the parent of a merge commit to diff against,
+	// the first one by default.
+	Parent int
+}
+
+// Args returns the git diff arguments of the options.
+func (o DiffOptions) Args() []string {
+	return append([]string{"-U" + strconv.Itoa(o.Context)}, o.Whitespace.Args()...)
 }
 
 // Diff returns the diff for the given commit.
 func (r *Repository) Diff(commit *Commit) (*Diff, error) {
+	return r.DiffWithOptions(commit, DiffOptions{Context: DefaultDiffContext})
+}
+
+// DiffWithOptions returns the diff for the given commit with the given
+// context lines and whitespace handling.
+func (r *Repository) DiffWithOptions(commit *Commit, opts DiffOptions) (*Diff, error) {
+	var base string
+	if opts.Parent > 0 {
+		id, err := commit.ParentID(opts.Parent)
+		if err != nil {
+			return nil, err
+		}
+		base = id.String()
+	}
 	diff, err := r.Repository.Diff(commit.ID.String(), DiffMaxFiles, DiffMaxFileLines, DiffMaxLineChars, git.DiffOptions{
+		Base: base,
 		CommandOptions: git.CommandOptions{
+			Args: opts.Args(),
 			Envs: []string{"GIT_CONFIG_GLOBAL=/dev/null"},
 		},
 	})
@@ -151,6 +275,44 @@ func (r *Repository) Diff(commit *Commit) (*Diff, error) {
 	return toDiff(diff), nil
 }
 
+// DiffPaths returns the diff of the given paths between two revisions. Paths
+// that don't exist in one of the revisions show up as added or deleted.
+func (r *Repository) DiffPaths(base, head string, paths ...string) (*Diff, error) {
+	for _, rev := range []string{base, head} {
+		if strings.HasPrefix(rev, "-") {
+			return nil, ErrRevisionNotExist
+		}
+	}
+	return r.diff(nil, base, head, paths...)
+}
+
+// diff returns the diff of the given paths between two revisions with the
+// given extra git diff arguments.
+func (r *Repository) diff(extra []string, base, head string, paths ...string) (*Diff, error) {
+	args := append([]string{"diff", "--full-index", "-M"}, extra...)
+	args = append(append(args, base, head, "--"), paths...)
+	stdout, w := io.Pipe()
+	done := make(chan git.SteamParseDiffResult)
+	go git.StreamParseDiff(stdout, done, DiffMaxFiles, DiffMaxFileLines, DiffMaxLineChars)
+
+	var stderr bytes.Buffer
+	err := NewCommand(args...).
+		AddEnvs("GIT_CONFIG_GLOBAL=/dev/null").
+		RunInDirPipeline(w, &stderr, r.Path)
+	_ = w.Close() // Close the writer to stop parsing
+	res := <-done
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+	if res.Err != nil {
+		return nil, res.Err
+	}
+	return toDiff(res.Diff), nil
+}
+
 // Patch returns the patch for the given reference.
 func (r *Repository) Patch(commit *Commit) (string, error) {
 	diff, err := r.Diff(commit)
@@ -0,0 +1,21 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestDiffOptionsArgs(t *testing.T) {
+	is := is.New(t)
+	is.Equal(DiffOptions{Context: DefaultDiffContext}.Args(), []string{"-U3"})
+	is.Equal(DiffOptions{Context: 0, Whitespace: DiffWhitespaceIgnoreAll}.Args(), []string{"-U0", "-w"})
+	is.Equal(DiffOptions{Context: 10, Whitespace: DiffWhitespaceIgnoreAllAndBlankLines}.Args(),
+		[]string{"-U10", "-w", "--ignore-blank-lines"})
+
+	w := DiffWhitespaceShow
+	for _, want := range []string{"-w", "--ignore-blank-lines", "-w --ignore-blank-lines", ""} {
+		w = w.Next()
+		is.Equal(w.String(), want)
+	}
+}
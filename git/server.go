@@ -2,6 +2,9 @@ package git
 
 import (
 	"context"
+	"io/fs"
+	"path/filepath"
+	"strings"
 
 	"github.com/aymanbagabas/git-module"
 )
@@ -16,3 +19,145 @@ func UpdateServerInfo(ctx context.Context, path string) error {
 	_, err := cmd.RunInDir(path)
 	return err
 }
+
+// GCOptions are the options of GC.
+type GCOptions struct {
+	// Indexes writes a commit-graph file and a pack bitmap index while
+	// repacking, see WriteCommitGraph.
+	Indexes bool
+
+	// NoPrune keeps the unreachable objects, e.g. that forks borrowing the
+	// objects of the repo may still need.
+	NoPrune bool
+}
+
+// GC cleans up unnecessary files and optimizes the repo at the given path.
+func GC(ctx context.Context, path string, opts ...GCOptions) error {
+	if !isGitDir(path) {
+		return ErrNotAGitRepository
+	}
+
+	var opt GCOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	var args []string
+	if opt.Indexes {
+		if err := enableCommitGraph(path); err != nil {
+			return err
+		}
+		args = append(args,
+			"-c", "gc.writeCommitGraph=true",
+			"-c", "repack.writeBitmaps=true",
+		)
+	}
+
+	args = append(args, "gc", "--quiet")
+	if opt.NoPrune {
+		args = append(args, "--no-prune")
+	}
+	cmd := git.NewCommand(args...).WithContext(ctx).WithTimeout(-1)
+	_, err := cmd.RunInDir(path)
+	return err
+}
+
+// WriteCommitGraph updates the commit-graph file of the repo at the given
+// path with the commits reachable from its references. The commit-graph
+// speeds up walking the history, like logs, commit counts, and merge bases.
+// Git reads it whenever it's present, it's also enabled in the configuration
+// of the repo in case it's disabled globally.
+func WriteCommitGraph(ctx context.Context, path string) error {
+	if !isGitDir(path) {
+		return ErrNotAGitRepository
+	}
+
+	if err := enableCommitGraph(path); err != nil {
+		return err
+	}
+
+	// Split commit-graphs are merged incrementally, only the new commits are
+	// written most of the time.
+	cmd := git.NewCommand("commit-graph", "write", "--reachable", "--split", "--changed-paths").
+		WithContext(ctx).WithTimeout(-1)
+	_, err := cmd.RunInDir(path)
+	return err
+}
+
+// Prune removes the unreachable loose objects older than two weeks from the
+// repo at the given path, the objects younger than that may still be needed by
+// a push in progress.
+func Prune(ctx context.Context, path string) error {
+	return runMaintenance(ctx, path, "prune", "--expire=2.weeks.ago")
+}
+
+// PrunableObjects returns the IDs of the unreachable loose objects Prune would
+// remove from the repo at the given path, without removing them.
+func PrunableObjects(ctx context.Context, path string) ([]string, error) {
+	if !isGitDir(path) {
+		return nil, ErrNotAGitRepository
+	}
+
+	out, err := git.NewCommand("prune", "--dry-run", "--expire=2.weeks.ago").
+		WithContext(ctx).WithTimeout(-1).RunInDir(path)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0)
+	for _, line := range strings.Split(string(out), "\n") {
+		// Lines are "<id> <type>".
+		if id, _, ok := strings.Cut(line, " "); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// LooseRefs returns the number of loose references of the repo at the given
+// path, the references PackRefs would pack.
+func LooseRefs(path string) (int, error) {
+	var n int
+	if err := filepath.WalkDir(filepath.Join(path, "refs"), func(_ string, de fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if de.Type().IsRegular() {
+			n++
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Repack packs the loose objects of the repo at the given path into a new pack
+// and removes the packs and loose objects it makes redundant. The existing
+// packs aren't rewritten, which gc does.
+func Repack(ctx context.Context, path string) error {
+	return runMaintenance(ctx, path, "repack", "-d", "-l", "-q")
+}
+
+// PackRefs packs the references of the repo at the given path into its
+// packed-refs file.
+func PackRefs(ctx context.Context, path string) error {
+	return runMaintenance(ctx, path, "pack-refs", "--all", "--prune")
+}
+
+// runMaintenance runs a git maintenance command in the repo at the given path
+// without a timeout.
+func runMaintenance(ctx context.Context, path string, args ...string) error {
+	if !isGitDir(path) {
+		return ErrNotAGitRepository
+	}
+
+	_, err := git.NewCommand(args...).WithContext(ctx).WithTimeout(-1).RunInDir(path)
+	return err
+}
+
+// enableCommitGraph enables reading the commit-graph in the configuration of
+// the repo at the given path.
+func enableCommitGraph(path string) error {
+	_, err := git.NewCommand("config", "--local", "core.commitGraph", "true").RunInDir(path)
+	return err
+}
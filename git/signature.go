@@ -0,0 +1,141 @@
+package git
+
+import (
+	"bytes"
+	"strings"
+)
+
+// LogFilter selects the commits of a log. Empty fields match every commit,
+// the values are matched as fixed strings, ignoring case.
+type LogFilter struct {
+	// Author matches the name or email of the author.
+	Author string
+	// Message matches the commit message.
+	Message string
+	// Path matches the commits that change the path.
+	Path string
+	// Since is the hash, or abbreviated hash, of a commit. It leaves out the
+	// commits reachable from it.
+	Since string
+}
+
+// IsZero returns true if the filter matches every commit.
+func (f LogFilter) IsZero() bool {
+	return f == LogFilter{}
+}
+
+// Args returns the git rev-list arguments of the filter, without the path.
+func (f LogFilter) Args() []string {
+	args := []string{"--regexp-ignore-case", "--fixed-strings"}
+	if f.Author != "" {
+		args = append(args, "--author="+f.Author)
+	}
+	if f.Message != "" {
+		args = append(args, "--grep="+f.Message)
+	}
+	return args
+}
+
+// FilterCommits returns the hashes of the commits reachable from ref that
+// match the filter, newest first.
+func (r *Repository) FilterCommits(ref *Reference, f LogFilter) ([]string, error) {
+	rev := ref.Name().String()
+	if f.Since != "" {
+		if !isAbbrevHash(f.Since) {
+			return nil, ErrRevisionNotExist
+		}
+		rev = f.Since + ".." + rev
+	}
+	args := append([]string{"rev-list"}, f.Args()...)
+	args = append(args, rev, "--")
+	if f.Path != "" {
+		args = append(args, f.Path)
+	}
+	out, err := NewCommand(args...).RunInDir(r.Path)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// CommitSignature is the signature of a commit and the data it signs.
+type CommitSignature struct {
+	// Signature is the armored signature, e.g. an SSH or an OpenPGP
+	// signature.
+	Signature []byte
+	// Payload is the commit object without its signature.
+	Payload []byte
+}
+
+// IsSSH returns true if the commit is signed with an SSH key.
+func (s CommitSignature) IsSSH() bool {
+	return bytes.HasPrefix(s.Signature, []byte("-----BEGIN SSH SIGNATURE-----"))
+}
+
+// CommitSignatures returns the signatures of the commits with the given
+// hashes keyed by hash. Unsigned commits are left out.
+func (r *Repository) CommitSignatures(ids ...string) (map[string]CommitSignature, error) {
+	sigs := map[string]CommitSignature{}
+	if len(ids) == 0 {
+		return sigs, nil
+	}
+	for _, id := range ids {
+		if !isHash(id) {
+			return nil, ErrObjectNotFound
+		}
+	}
+
+	objs, err := r.catFileBatch(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objs {
+		if obj.Type != "commit" {
+			continue
+		}
+		if sig, ok := splitCommitSignature(obj.Content); ok {
+			sigs[obj.Name] = sig
+		}
+	}
+	return sigs, nil
+}
+
+// splitCommitSignature splits a raw commit object in its signature, the
+// gpgsig header, and the data the signature signs, the object without the
+// header.
+func splitCommitSignature(raw []byte) (CommitSignature, bool) {
+	var sig CommitSignature
+	var payload, signature bytes.Buffer
+	inSig, found := false, false
+	rest := raw
+	for len(rest) > 0 {
+		line := rest
+		if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+			line = rest[:i+1]
+		}
+		rest = rest[len(line):]
+		switch {
+		case inSig && bytes.HasPrefix(line, []byte(" ")):
+			signature.Write(line[1:])
+			continue
+		case !found && (bytes.HasPrefix(line, []byte("gpgsig ")) || bytes.HasPrefix(line, []byte("gpgsig-sha256 "))):
+			_, value, _ := bytes.Cut(line, []byte(" "))
+			signature.Write(value)
+			inSig, found = true, true
+			continue
+		case bytes.Equal(line, []byte("\n")):
+			// The end of the headers, the message follows.
+			payload.Write(line)
+			payload.Write(rest)
+			rest = nil
+			continue
+		}
+		inSig = false
+		payload.Write(line)
+	}
+	if !found {
+		return sig, false
+	}
+	sig.Signature, sig.Payload = signature.Bytes(), payload.Bytes()
+	return sig, true
+}
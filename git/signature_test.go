@@ -0,0 +1,50 @@
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestSplitCommitSignature(t *testing.T) {
+	is := is.New(t)
+	payload := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n" +
+		"author A U Thor <author@example.com> 1700000000 +0000\n" +
+		"committer A U Thor <author@example.com> 1700000000 +0000\n" +
+		"\n" +
+		"Signed commit\n" +
+		"\n" +
+		"gpgsig isn't a header in the message\n"
+	sig := "-----BEGIN SSH SIGNATURE-----\nU1NIU0lH\n-----END SSH SIGNATURE-----\n"
+	lines := strings.Split(sig, "\n")
+	raw := strings.Replace(payload, "\n\n", "\ngpgsig "+strings.Join(lines[:len(lines)-1], "\n ")+"\n\n", 1)
+
+	s, ok := splitCommitSignature([]byte(raw))
+	is.True(ok)
+	is.Equal(string(s.Payload), payload)
+	is.Equal(string(s.Signature), sig)
+	is.True(s.IsSSH())
+
+	_, ok = splitCommitSignature([]byte(payload))
+	is.True(!ok)
+}
+
+func TestLogFilterArgs(t *testing.T) {
+	is := is.New(t)
+	is.True(LogFilter{}.IsZero())
+	is.True(!LogFilter{Since: "abc1234"}.IsZero())
+	is.Equal(LogFilter{Author: "alice", Message: "fix", Path: "a.go"}.Args(),
+		[]string{"--regexp-ignore-case", "--fixed-strings", "--author=alice", "--grep=fix"})
+}
+
+func TestIsAbbrevHash(t *testing.T) {
+	is := is.New(t)
+	is.True(isAbbrevHash("abc1234"))
+	is.True(isAbbrevHash(strings.Repeat("a", 40)))
+	is.True(!isAbbrevHash("abc"))
+	is.True(!isAbbrevHash("-abc1234"))
+	is.True(!isAbbrevHash("ABC1234"))
+	is.True(isHash(strings.Repeat("a", 64)))
+	is.True(!isHash("abc1234"))
+}
@@ -0,0 +1,72 @@
+package git
+
+import (
+	"strings"
+
+	"github.com/aymanbagabas/git-module"
+)
+
+// SplitRow is a row of the side-by-side view of a diff. Header rows, the
+// file headers and the hunk headers, span both sides. Other rows have the
+// line of the old file on the left and the line of the new file on the right.
+// Either side is nil where a line was only added or deleted. Line is the zero
+// based line of the patch the row starts at.
+type SplitRow struct {
+	Header string
+	Left   *git.DiffLine
+	Right  *git.DiffLine
+	Line   int
+}
+
+// Split returns the rows of the side-by-side view of the diff. Runs of
+// deleted lines and the added lines that follow them are paired in order.
+func (d *Diff) Split() []SplitRow {
+	rows := make([]SplitRow, 0)
+	n := 0
+	for _, f := range d.Files {
+		var sb strings.Builder
+		writeFilePatchHeader(&sb, f)
+		for _, h := range strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n") {
+			if h == "" {
+				continue
+			}
+			rows = append(rows, SplitRow{Header: h, Line: n})
+			n++
+		}
+		for _, s := range f.Sections {
+			rows = append(rows, splitSection(s, n)...)
+			n += len(s.Lines)
+		}
+	}
+	return rows
+}
+
+// splitSection returns the side-by-side rows of a section that starts at the
+// given line of the patch.
+func splitSection(s *DiffSection, start int) []SplitRow {
+	rows := make([]SplitRow, 0, len(s.Lines))
+	// dels are the rows of the deleted lines that added lines can still be
+	// paired with.
+	dels := make([]int, 0)
+	for i, l := range s.Lines {
+		switch l.Type {
+		case git.DiffLineSection:
+			dels = dels[:0]
+			rows = append(rows, SplitRow{Header: l.Content, Line: start + i})
+		case git.DiffLineDelete:
+			dels = append(dels, len(rows))
+			rows = append(rows, SplitRow{Left: l, Line: start + i})
+		case git.DiffLineAdd:
+			if len(dels) > 0 {
+				rows[dels[0]].Right = l
+				dels = dels[1:]
+				continue
+			}
+			rows = append(rows, SplitRow{Right: l, Line: start + i})
+		default:
+			dels = dels[:0]
+			rows = append(rows, SplitRow{Left: l, Right: l, Line: start + i})
+		}
+	}
+	return rows
+}
@@ -0,0 +1,46 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestDiffSplit(t *testing.T) {
+	is := is.New(t)
+	d := parseTestDiff(t, testPatch)
+	rows := d.Split()
+
+	type row struct {
+		header      string
+		left, right string
+		line        int
+	}
+	got := make([]row, len(rows))
+	for i, r := range rows {
+		got[i] = row{header: r.Header, line: r.Line}
+		if r.Left != nil {
+			got[i].left = r.Left.Content
+		}
+		if r.Right != nil {
+			got[i].right = r.Right.Content
+		}
+	}
+	is.Equal(got, []row{
+		{header: "diff --git a/a.txt b/a.txt", line: 0},
+		{header: "index 78981922613b2afb6025042ff6bd878ac1994e85..f4a1b43ed2e1a2c1d5e9fd402fc8e6f4961d3d70 100644", line: 1},
+		{header: "--- a/a.txt", line: 2},
+		{header: "+++ b/a.txt", line: 3},
+		{header: "@@ -2,3 +2,3 @@", line: 4},
+		{left: " b", right: " b", line: 5},
+		{left: "-c", right: "+C", line: 6},
+		{left: " d", right: " d", line: 8},
+		{header: "diff --git a/b.txt b/b.txt", line: 9},
+		{header: "new file mode 100644", line: 10},
+		{header: "index 0000000000000000000000000000000000000000..617807982c41bb5da4ae2d2ef3c943a0b5e5df3a", line: 11},
+		{header: "--- /dev/null", line: 12},
+		{header: "+++ b/b.txt", line: 13},
+		{header: "@@ -0,0 +1 @@", line: 14},
+		{right: "+b", line: 15},
+	})
+}
@@ -1,16 +1,73 @@
 package git
 
-import "github.com/aymanbagabas/git-module"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
 
-// StashDiff returns the diff of the given stash index.
+// RefsStash is the reference of the stash.
+const RefsStash = "refs/stash"
+
+// Stash is an entry of the stash.
+type Stash struct {
+	// Index is the index of the entry, 0 being the latest.
+	Index int
+	// Message is the message of the entry.
+	Message string
+	// Hash is the hash of the commit of the entry.
+	Hash string
+}
+
+// HasStash returns whether the repository has a stash.
+func (r *Repository) HasStash() bool {
+	_, err := r.RevParse(RefsStash)
+	return err == nil
+}
+
+// Stashes returns the entries of the stash, the latest first. Unlike "git
+// stash list", it works in bare repositories too. Pushes and mirrors carry
+// the stash reference but not its reflog, which holds the older entries, so
+// they only have the latest entry.
+func (r *Repository) Stashes() ([]*Stash, error) {
+	if !r.HasStash() {
+		return []*Stash{}, nil
+	}
+
+	args := []string{"log", "--format=%H%x00%s", "--no-walk", RefsStash}
+	if _, err := os.Stat(filepath.Join(r.Path, "logs", RefsStash)); err == nil {
+		args = []string{"log", "--format=%H%x00%gs", "--walk-reflogs", RefsStash}
+	}
+	out, err := NewCommand(args...).RunInDir(r.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	stashes := make([]*Stash, 0)
+	for _, line := range strings.Split(string(out), "\n") {
+		id, msg, ok := strings.Cut(line, "\x00")
+		if !ok {
+			continue
+		}
+		stashes = append(stashes, &Stash{Index: len(stashes), Message: msg, Hash: id})
+	}
+	return stashes, nil
+}
+
+// StashDiff returns the diff of the given stash index, the changes of the
+// entry to the commit it was made on.
 func (r *Repository) StashDiff(index int) (*Diff, error) {
-	diff, err := r.Repository.StashDiff(index, DiffMaxFiles, DiffMaxFileLines, DiffMaxLineChars, git.DiffOptions{
-		CommandOptions: git.CommandOptions{
-			Envs: []string{"GIT_CONFIG_GLOBAL=/dev/null"},
-		},
-	})
+	stashes, err := r.Stashes()
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(stashes) {
+		return nil, ErrRevisionNotExist
+	}
+
+	c, err := r.CatFileCommit(stashes[index].Hash)
 	if err != nil {
 		return nil, err
 	}
-	return toDiff(diff), nil
+	return r.Diff(c)
 }
@@ -0,0 +1,123 @@
+package git
+
+import (
+	"strings"
+)
+
+// Submodule is a submodule of a repository.
+type Submodule struct {
+	// Name is the name of the submodule in .gitmodules.
+	Name string
+	// Path is the path of the submodule in the tree.
+	Path string
+	// URL is the configured URL of the submodule.
+	URL string
+	// Branch is the configured branch of the submodule, if any.
+	Branch string
+	// Commit is the commit the submodule is pinned to. It's empty if the
+	// submodule isn't in the tree.
+	Commit string
+}
+
+// Submodules returns the submodules configured in the .gitmodules file at the
+// given revision along with their pinned commits.
+func (r *Repository) Submodules(rev string) ([]Submodule, error) {
+	if _, err := NewCommand("cat-file", "-e", rev+":.gitmodules").RunInDir(r.Path); err != nil {
+		// No submodules.
+		return []Submodule{}, nil
+	}
+
+	out, err := NewCommand("config", "-z", "--blob", rev+":.gitmodules", "--list").RunInDir(r.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	subs := parseGitmodules(out)
+	if len(subs) == 0 {
+		return subs, nil
+	}
+
+	paths := make([]string, len(subs))
+	for i, s := range subs {
+		paths[i] = s.Path
+	}
+	out, err = NewCommand("ls-tree", "-z", rev, "--").AddArgs(paths...).RunInDir(r.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := parseGitlinks(out)
+	for i, s := range subs {
+		subs[i].Commit = commits[s.Path]
+	}
+
+	return subs, nil
+}
+
+// parseGitmodules parses the output of git config -z --list of a .gitmodules
+// file. Every entry is in the form "key\nvalue" and terminated by a NUL.
+// Submodules are returned in the order they're configured.
+func parseGitmodules(out []byte) []Submodule {
+	subs := make([]Submodule, 0)
+	index := map[string]int{}
+	for _, entry := range strings.Split(string(out), "\x00") {
+		key, value, _ := strings.Cut(entry, "\n")
+		if !strings.HasPrefix(key, "submodule.") {
+			continue
+		}
+
+		// The name of the submodule can contain dots.
+		i := strings.LastIndex(key, ".")
+		name, field := key[len("submodule."):i], key[i+1:]
+		if name == "" {
+			continue
+		}
+
+		n, ok := index[name]
+		if !ok {
+			n = len(subs)
+			index[name] = n
+			subs = append(subs, Submodule{Name: name})
+		}
+
+		switch strings.ToLower(field) {
+		case "path":
+			subs[n].Path = value
+		case "url":
+			subs[n].URL = value
+		case "branch":
+			subs[n].Branch = value
+		}
+	}
+
+	// Skip submodules without a path.
+	valid := subs[:0]
+	for _, s := range subs {
+		if s.Path != "" {
+			valid = append(valid, s)
+		}
+	}
+
+	return valid
+}
+
+// parseGitlinks parses the output of git ls-tree -z and returns the commits of
+// the gitlink entries keyed by path.
+func parseGitlinks(out []byte) map[string]string {
+	commits := map[string]string{}
+	for _, line := range strings.Split(string(out), "\x00") {
+		meta, path, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(meta)
+		if len(fields) != 3 || fields[1] != "commit" {
+			continue
+		}
+
+		commits[path] = fields[2]
+	}
+
+	return commits
+}
@@ -0,0 +1,32 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestParseGitmodules(t *testing.T) {
+	is := is.New(t)
+	in := "submodule.lib.path\nvendor/lib\x00" +
+		"submodule.lib.url\n../lib.git\x00" +
+		"submodule.v1.0.docs.path\ndocs\x00" +
+		"submodule.v1.0.docs.url\nhttps://example.com/docs.git\x00" +
+		"submodule.v1.0.docs.branch\nstable\x00" +
+		"submodule.nopath.url\nhttps://example.com/nopath.git\x00" +
+		"core.bare\nfalse\x00"
+	is.Equal(parseGitmodules([]byte(in)), []Submodule{
+		{Name: "lib", Path: "vendor/lib", URL: "../lib.git"},
+		{Name: "v1.0.docs", Path: "docs", URL: "https://example.com/docs.git", Branch: "stable"},
+	})
+	is.Equal(len(parseGitmodules(nil)), 0)
+}
+
+func TestParseGitlinks(t *testing.T) {
+	is := is.New(t)
+	in := "160000 commit 8073f2026d6082bf8073f2026d6082bf8073f202\tvendor/lib\x00" +
+		"100644 blob d42f6e6182bf8073f2d42f6e6182bf8073f2d42f\tdocs\x00"
+	is.Equal(parseGitlinks([]byte(in)), map[string]string{
+		"vendor/lib": "8073f2026d6082bf8073f2026d6082bf8073f202",
+	})
+}
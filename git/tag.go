@@ -1,6 +1,100 @@
 package git
 
-import "github.com/aymanbagabas/git-module"
+import (
+	"errors"
+	"strings"
+
+	"github.com/aymanbagabas/git-module"
+)
 
 // Tag is a git tag.
 type Tag = git.Tag
+
+// Signature is a git signature.
+type Signature = git.Signature
+
+// maxTagDepth is the maximum number of nested annotated tags TagChain
+// follows.
+const maxTagDepth = 10
+
+// ErrTagDepth is returned when a chain of annotated tags is deeper than
+// maxTagDepth or loops.
+var ErrTagDepth = errors.New("too many nested tags")
+
+// AnnotatedTag is an annotated tag object.
+type AnnotatedTag struct {
+	// ID is the hash of the tag object.
+	ID string
+	// Name is the tag name recorded in the tag object.
+	Name string
+	// Tagger is the tagger of the tag, if any.
+	Tagger *Signature
+	// Message is the tag message.
+	Message string
+	// Object is the hash of the object the tag points to.
+	Object string
+	// ObjectType is the type of the object the tag points to.
+	ObjectType string
+}
+
+// TagChain follows the annotated tags starting at the given object, usually a
+// tag reference hash, and returns them in order. The object of the last tag
+// is the final non-tag object. It returns an empty chain for lightweight tags.
+func (r *Repository) TagChain(id string) ([]AnnotatedTag, error) {
+	chain := make([]AnnotatedTag, 0)
+	seen := map[string]bool{}
+	for {
+		typ, err := NewCommand("cat-file", "-t", id).RunInDir(r.Path)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(string(typ)) != "tag" {
+			return chain, nil
+		}
+		if seen[id] || len(chain) >= maxTagDepth {
+			return nil, ErrTagDepth
+		}
+		seen[id] = true
+
+		out, err := NewCommand("cat-file", "tag", id).RunInDir(r.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		tag := parseTagObject(id, out)
+		chain = append(chain, tag)
+		id = tag.Object
+	}
+}
+
+// parseTagObject parses a raw annotated tag object. The headers are separated
+// from the message by an empty line.
+func parseTagObject(id string, data []byte) AnnotatedTag {
+	tag := AnnotatedTag{ID: id}
+	headers, message, _ := strings.Cut(string(data), "\n\n")
+	tag.Message = message
+	for _, line := range strings.Split(headers, "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "object":
+			tag.Object = value
+		case "type":
+			tag.ObjectType = value
+		case "tag":
+			tag.Name = value
+		case "tagger":
+			// Name <email> 1700000000 +0100
+			i := strings.LastIndex(value, ">")
+			j := strings.Index(value, "<")
+			if i < 0 || j < 0 || j > i {
+				continue
+			}
+			tag.Tagger = parseRefSignature(
+				strings.TrimSpace(value[:j]),
+				value[j:i+1],
+				strings.TrimSpace(value[i+1:]),
+			)
+		}
+	}
+	return tag
+}
@@ -0,0 +1,38 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestParseTagObject(t *testing.T) {
+	is := is.New(t)
+	const (
+		id     = "89abcdef0123456789abcdef0123456789abcdef"
+		object = "0123456789abcdef0123456789abcdef01234567"
+	)
+	data := "object " + object + "\n" +
+		"type tag\n" +
+		"tag v1.0.0-final\n" +
+		"tagger Foo Bar <foo@bar.baz> 1700000000 +0100\n" +
+		"\n" +
+		"Final release\n\nSee v1.0.0.\n"
+
+	tag := parseTagObject(id, []byte(data))
+	is.Equal(tag.ID, id)
+	is.Equal(tag.Object, object)
+	is.Equal(tag.ObjectType, "tag")
+	is.Equal(tag.Name, "v1.0.0-final")
+	is.Equal(tag.Message, "Final release\n\nSee v1.0.0.\n")
+	is.True(tag.Tagger != nil)
+	is.Equal(tag.Tagger.Name, "Foo Bar")
+	is.Equal(tag.Tagger.Email, "foo@bar.baz")
+	is.Equal(tag.Tagger.When.Unix(), int64(1700000000))
+
+	// Tags without a tagger.
+	tag = parseTagObject(id, []byte("object "+object+"\ntype commit\ntag old\n\nOld tag\n"))
+	is.Equal(tag.ObjectType, "commit")
+	is.True(tag.Tagger == nil)
+	is.Equal(tag.Message, "Old tag\n")
+}
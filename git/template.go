@@ -0,0 +1,103 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/git-module"
+)
+
+// tempIndex returns the environment variable that points Git to a new
+// temporary index, and a function that removes it.
+func tempIndex() (string, func()) {
+	rnd := rand.NewSource(time.Now().UnixNano())
+	name := "soft-serve-index-" + strconv.Itoa(rand.New(rnd).Int()) // nolint: gosec
+	tmpindex := filepath.Join(os.TempDir(), name)
+	return "GIT_INDEX_FILE=" + tmpindex, func() {
+		os.Remove(tmpindex) // nolint: errcheck
+	}
+}
+
+// ExportTree writes the files of the given revision of the repo at path to
+// dir, which is created if it doesn't exist.
+func ExportTree(ctx context.Context, path, rev, dir string) error {
+	if !isGitDir(path) {
+		return ErrNotAGitRepository
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	env, done := tempIndex()
+	defer done()
+
+	if _, err := git.NewCommand("read-tree", rev+"^{tree}").
+		AddEnvs(env).WithContext(ctx).WithTimeout(-1).RunInDir(path); err != nil {
+		return err
+	}
+	_, err := git.NewCommand("--work-tree="+dir, "checkout-index", "--all", "--force").
+		AddEnvs(env).WithContext(ctx).WithTimeout(-1).RunInDir(path)
+	return err
+}
+
+// CommitDir makes the first commit of the empty repo at the given path from
+// the files of dir, on the given branch, and points HEAD to the branch. The
+// branch HEAD points to is used when it's empty. The commit has no parent and
+// is authored and committed by sig. It returns the hash of the commit.
+func CommitDir(ctx context.Context, path, dir, branch, message string, sig *Signature) (string, error) {
+	if !isGitDir(path) {
+		return "", ErrNotAGitRepository
+	}
+	ref := "refs/heads/" + branch
+	if branch == "" {
+		head, err := git.NewCommand("symbolic-ref", "HEAD").RunInDir(path)
+		if err != nil {
+			return "", err
+		}
+		ref = strings.TrimSpace(string(head))
+	} else if _, err := git.NewCommand("check-ref-format", ref).RunInDir(path); err != nil {
+		return "", fmt.Errorf("invalid branch name %q", branch)
+	}
+
+	env, done := tempIndex()
+	defer done()
+
+	if _, err := git.NewCommand("--work-tree="+dir, "add", "--all", ".").
+		AddEnvs(env).WithContext(ctx).WithTimeout(-1).RunInDir(path); err != nil {
+		return "", err
+	}
+	tree, err := git.NewCommand("write-tree").AddEnvs(env).RunInDir(path)
+	if err != nil {
+		return "", err
+	}
+
+	date := sig.When.Format(time.RFC3339)
+	commit, err := git.NewCommand("commit-tree", "-m", message, strings.TrimSpace(string(tree))).
+		AddEnvs(
+			"GIT_AUTHOR_NAME="+sig.Name,
+			"GIT_AUTHOR_EMAIL="+sig.Email,
+			"GIT_AUTHOR_DATE="+date,
+			"GIT_COMMITTER_NAME="+sig.Name,
+			"GIT_COMMITTER_EMAIL="+sig.Email,
+			"GIT_COMMITTER_DATE="+date,
+		).RunInDir(path)
+	if err != nil {
+		return "", err
+	}
+	hash := strings.TrimSpace(string(commit))
+
+	// The empty old value makes sure the branch didn't exist.
+	if _, err := git.NewCommand("update-ref", "-m", "template", ref, hash, "").RunInDir(path); err != nil {
+		return "", err
+	}
+	if _, err := git.NewCommand("symbolic-ref", "HEAD", ref).RunInDir(path); err != nil {
+		return "", err
+	}
+	return hash, nil
+}
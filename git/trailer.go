@@ -0,0 +1,93 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// Trailer is a "Key: value" line at the end of a commit message, e.g.
+// "Signed-off-by: Jane Doe <jane@example.com>".
+type Trailer struct {
+	Key   string
+	Value string
+}
+
+// trailerRe matches a trailer line. Keys are made of letters, digits, and
+// dashes.
+var trailerRe = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*):[ \t]*(.*?)[ \t]*$`)
+
+// identityRe matches a "Name <email>" identity.
+var identityRe = regexp.MustCompile(`^[^<>]*<[^<>]+>$`)
+
+// SplitTrailers splits the message into its body and its trailers. The
+// trailers are the last paragraph of the message when all of its lines are
+// trailers, or continuation lines indented under them. The subject is never
+// a trailer.
+func SplitTrailers(msg string) (string, []Trailer) {
+	msg = strings.TrimRight(msg, "\n")
+	i := strings.LastIndex(msg, "\n\n")
+	if i < 0 {
+		return msg, nil
+	}
+
+	trailers := make([]Trailer, 0)
+	for _, line := range strings.Split(msg[i+2:], "\n") {
+		if len(trailers) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			t := &trailers[len(trailers)-1]
+			t.Value = strings.TrimSpace(t.Value + " " + strings.TrimSpace(line))
+			continue
+		}
+		m := trailerRe.FindStringSubmatch(line)
+		if m == nil {
+			return msg, nil
+		}
+		trailers = append(trailers, Trailer{Key: m[1], Value: m[2]})
+	}
+
+	return strings.TrimRight(msg[:i], "\n"), trailers
+}
+
+// IsIdentity returns true if the value is a "Name <email>" identity, like the
+// values of Co-authored-by and Signed-off-by trailers.
+func IsIdentity(v string) bool {
+	return identityRe.MatchString(v)
+}
+
+// MapIdentities returns the canonical identities of the given "Name <email>"
+// identities according to the mailmap of the repository. Identities the
+// mailmap doesn't know are returned as is.
+func (r *Repository) MapIdentities(ids ...string) (map[string]string, error) {
+	mapped := map[string]string{}
+	contacts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if IsIdentity(id) {
+			contacts = append(contacts, id)
+		}
+	}
+	if len(contacts) == 0 {
+		return mapped, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	// Bare repositories read the mailmap from HEAD:.mailmap.
+	if err := NewCommand("check-mailmap").
+		AddArgs(contacts...).
+		RunInDirWithOptions(r.Path, RunInDirOptions{
+			Stdout: &stdout,
+			Stderr: &stderr,
+		}); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+
+	s := bufio.NewScanner(&stdout)
+	for i := 0; s.Scan() && i < len(contacts); i++ {
+		mapped[contacts[i]] = s.Text()
+	}
+	return mapped, s.Err()
+}
@@ -0,0 +1,36 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestSplitTrailers(t *testing.T) {
+	is := is.New(t)
+
+	body, trailers := SplitTrailers("Fix the thing\n\nIt was broken.\n\nCo-authored-by: Jane Doe <jane@example.com>\nSigned-off-by: John Doe\n  <john@example.com>\n")
+	is.Equal(body, "Fix the thing\n\nIt was broken.")
+	is.Equal(trailers, []Trailer{
+		{Key: "Co-authored-by", Value: "Jane Doe <jane@example.com>"},
+		{Key: "Signed-off-by", Value: "John Doe <john@example.com>"},
+	})
+
+	// The subject isn't a trailer.
+	body, trailers = SplitTrailers("Fixes: the thing")
+	is.Equal(body, "Fixes: the thing")
+	is.Equal(len(trailers), 0)
+
+	// The last paragraph must only have trailers.
+	msg := "Fix the thing\n\nSigned-off-by: Jane Doe <jane@example.com>\nand more"
+	body, trailers = SplitTrailers(msg)
+	is.Equal(body, msg)
+	is.Equal(len(trailers), 0)
+}
+
+func TestIsIdentity(t *testing.T) {
+	is := is.New(t)
+	is.True(IsIdentity("Jane Doe <jane@example.com>"))
+	is.True(!IsIdentity("Jane Doe"))
+	is.True(!IsIdentity("<a> <b>"))
+}
@@ -3,10 +3,13 @@ package git
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"io"
 	"io/fs"
 	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/aymanbagabas/git-module"
 )
@@ -23,6 +26,10 @@ type TreeEntry struct {
 	*git.TreeEntry
 	// path is the full path of the file
 	path string
+	// size is the size of the file once it's known, see
+	// Repository.LoadSizes.
+	size  int64
+	sized bool
 }
 
 // Entries is a wrapper around git.Entries.
@@ -80,6 +87,12 @@ func (f *File) Path() string {
 	return f.Entry.path
 }
 
+// Size returns the size of the file. It's read from the repository unless it's
+// known already.
+func (f *File) Size() int64 {
+	return f.Entry.Size()
+}
+
 // SubTree returns the sub-tree at the given path.
 func (t *Tree) SubTree(path string) (*Tree, error) {
 	tree, err := t.Subtree(path)
@@ -151,16 +164,15 @@ func IsBinary(r io.Reader) (bool, error) {
 	return false, nil
 }
 
-// IsBinary returns true if the file is binary.
+// IsBinary returns true if the file is binary. Only the beginning of the file
+// is read.
 func (f *File) IsBinary() (bool, error) {
-	stdout := new(bytes.Buffer)
-	stderr := new(bytes.Buffer)
-	err := f.Pipeline(stdout, stderr)
-	if err != nil {
+	buf := make([]byte, sniffLen)
+	n, err := f.ReadAt(buf, 0)
+	if err != nil && err != io.EOF {
 		return false, err
 	}
-	r := bufio.NewReader(stdout)
-	return IsBinary(r)
+	return IsBinary(bytes.NewReader(buf[:n]))
 }
 
 // Mode returns the mode of the file in fs.FileMode format.
@@ -174,6 +186,40 @@ func (e *TreeEntry) Mode() fs.FileMode {
 	}
 }
 
+// Size returns the size of the entry, 0 for trees. It's read from the
+// repository unless it's known already.
+func (e *TreeEntry) Size() int64 {
+	if e.sized {
+		return e.size
+	}
+	return e.TreeEntry.Size()
+}
+
+// LoadSizes reads the sizes of the blobs of the given entries whose size isn't
+// known yet, all of them in one pass. Listing a tree only reads the tree, the
+// size of a blob is otherwise read on its own when it's asked for.
+func (r *Repository) LoadSizes(ents Entries) error {
+	blobs := make(Entries, 0, len(ents))
+	ids := make([]string, 0, len(ents))
+	for _, e := range ents {
+		if e.sized || !e.IsBlob() {
+			continue
+		}
+		blobs = append(blobs, e)
+		ids = append(ids, e.ID().String())
+	}
+	infos, err := r.ObjectInfos(ids)
+	if err != nil {
+		return err
+	}
+	for i, info := range infos {
+		if info != nil {
+			blobs[i].size, blobs[i].sized = info.Size, true
+		}
+	}
+	return nil
+}
+
 // File returns the file for the TreeEntry.
 func (e *TreeEntry) File() *File {
 	b := e.Blob()
@@ -190,5 +236,143 @@ func (e *TreeEntry) Contents() ([]byte, error) {
 
 // Contents returns the contents of the file.
 func (f *File) Contents() ([]byte, error) {
-	return f.Blob.Bytes()
+	return f.Bytes()
+}
+
+// Bytes returns the contents of the file. It's read in one pass, in a buffer
+// of the size of the file.
+func (f *File) Bytes() ([]byte, error) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	stdout.Grow(int(f.Size()))
+	if err := f.Pipeline(stdout, stderr); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// errSectionRead stops streaming a file once the section is read.
+var errSectionRead = errors.New("section read")
+
+// ReadAt reads len(p) bytes of the file from offset off and implements
+// io.ReaderAt. The file is streamed up to the end of the section, it isn't
+// read in memory all at once.
+func (f *File) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	w := &sectionWriter{skip: off, buf: p}
+	stderr := new(bytes.Buffer)
+	// git may fail to write the rest of the file once the section is read.
+	if err := f.Pipeline(w, stderr); err != nil && !w.full() {
+		return w.n, err
+	}
+	if !w.full() {
+		return w.n, io.EOF
+	}
+	return w.n, nil
+}
+
+// sectionWriter keeps the bytes written to it after the first skip ones, up
+// to the size of buf.
+type sectionWriter struct {
+	skip int64
+	buf  []byte
+	n    int
+}
+
+func (w *sectionWriter) full() bool {
+	return w.n == len(w.buf)
+}
+
+// Write implements io.Writer. It returns errSectionRead once buf is full.
+func (w *sectionWriter) Write(p []byte) (int, error) {
+	l := len(p)
+	if w.skip > 0 {
+		s := min(w.skip, int64(len(p)))
+		w.skip -= s
+		p = p[s:]
+	}
+	w.n += copy(w.buf[w.n:], p)
+	if w.full() {
+		return l, errSectionRead
+	}
+	return l, nil
+}
+
+// TreeBlob is a file in a tree along with its size.
+type TreeBlob struct {
+	Path string
+	ID   string
+	Size int64
+}
+
+// TreeBlobs returns all the files of the given ref recursively. It lists the
+// whole tree in one pass. Submodules and symlinks are skipped.
+func (r *Repository) TreeBlobs(ref *Reference) ([]TreeBlob, error) {
+	out, err := NewCommand("ls-tree", "-r", "-l", "-z", ref.ID).RunInDir(r.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTreeBlobs(out), nil
+}
+
+// SmallBlobs returns the content of the given blobs that are at most maxSize
+// bytes keyed by path, e.g. to find the LFS pointers of a tree. The blobs are
+// read in one pass.
+func (r *Repository) SmallBlobs(blobs []TreeBlob, maxSize int64) (map[string][]byte, error) {
+	contents := map[string][]byte{}
+	ids := make([]string, 0)
+	paths := make([]string, 0)
+	for _, b := range blobs {
+		if b.ID == "" || b.Size == 0 || b.Size > maxSize {
+			continue
+		}
+		ids = append(ids, b.ID)
+		paths = append(paths, b.Path)
+	}
+	objs, err := r.catFileBatch(ids)
+	if err != nil {
+		return nil, err
+	}
+	for i, obj := range objs {
+		if obj.Type == "blob" {
+			contents[paths[i]] = obj.Content
+		}
+	}
+	return contents, nil
+}
+
+// parseTreeBlobs parses the output of git ls-tree -r -l -z. Every entry is in
+// the form "<mode> <type> <object> <size>\t<path>" and terminated by a NUL.
+func parseTreeBlobs(out []byte) []TreeBlob {
+	blobs := make([]TreeBlob, 0)
+	for _, line := range strings.Split(string(out), "\x00") {
+		meta, path, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(meta)
+		if len(fields) != 4 || fields[1] != "blob" || fields[0] == "120000" {
+			continue
+		}
+
+		size, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		blobs = append(blobs, TreeBlob{
+			Path: path,
+			ID:   fields[2],
+			Size: size,
+		})
+	}
+
+	return blobs
 }
@@ -0,0 +1,49 @@
+package git
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestParseTreeBlobs(t *testing.T) {
+	is := is.New(t)
+	in := "100644 blob 8073f2026d6082bf     120\tREADME.md\x00" +
+		"100755 blob d42f6e6182bf8073f2      7\tdir/run me.sh\x00" +
+		"120000 blob 82bf8073f2d42f6e61     12\tlink\x00" +
+		"160000 commit 6e6182bf8073f2d42f       -\tsubmodule\x00"
+	is.Equal(parseTreeBlobs([]byte(in)), []TreeBlob{
+		{Path: "README.md", ID: "8073f2026d6082bf", Size: 120},
+		{Path: "dir/run me.sh", ID: "d42f6e6182bf8073f2", Size: 7},
+	})
+	is.Equal(len(parseTreeBlobs(nil)), 0)
+}
+
+func TestSectionWriter(t *testing.T) {
+	is := is.New(t)
+	section := func(s string, off int64, size int) (string, error) {
+		w := &sectionWriter{skip: off, buf: make([]byte, size)}
+		// Write in small chunks like a pipe would.
+		for len(s) > 0 {
+			n := min(3, len(s))
+			if _, err := w.Write([]byte(s[:n])); err != nil {
+				return string(w.buf[:w.n]), err
+			}
+			s = s[n:]
+		}
+		return string(w.buf[:w.n]), nil
+	}
+
+	got, err := section("0123456789", 2, 4)
+	is.True(errors.Is(err, errSectionRead))
+	is.Equal(got, "2345")
+
+	got, err = section("0123456789", 8, 4)
+	is.NoErr(err)
+	is.Equal(got, "89")
+
+	got, err = section("0123456789", 12, 4)
+	is.NoErr(err)
+	is.Equal(got, "")
+}
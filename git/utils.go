@@ -9,22 +9,37 @@ import (
 
 // LatestFile returns the contents of the first file at the specified path pattern in the repository and its file path.
 func LatestFile(repo *Repository, ref *Reference, pattern string) (string, string, error) {
+	te, fp, err := LatestFileEntry(repo, ref, pattern)
+	if err != nil {
+		return "", "", err
+	}
+	bts, err := te.Contents()
+	if err != nil {
+		return "", "", err
+	}
+	return string(bts), fp, nil
+}
+
+// LatestFileEntry returns the tree entry of the first file at the specified
+// path pattern in the repository and its file path, without reading the file.
+// Symlinks are followed.
+func LatestFileEntry(repo *Repository, ref *Reference, pattern string) (*TreeEntry, string, error) {
 	g := glob.MustCompile(pattern)
 	dir := filepath.Dir(pattern)
 	if ref == nil {
 		head, err := repo.HEAD()
 		if err != nil {
-			return "", "", err
+			return nil, "", err
 		}
 		ref = head
 	}
 	t, err := repo.TreePath(ref, dir)
 	if err != nil {
-		return "", "", err
+		return nil, "", err
 	}
 	ents, err := t.Entries()
 	if err != nil {
-		return "", "", err
+		return nil, "", err
 	}
 	for _, e := range ents {
 		te := e
@@ -36,22 +51,18 @@ func LatestFile(repo *Repository, ref *Reference, pattern string) (string, strin
 			if te.IsSymlink() {
 				bts, err := te.Contents()
 				if err != nil {
-					return "", "", err
+					return nil, "", err
 				}
 				fp = string(bts)
 				te, err = t.TreeEntry(fp)
 				if err != nil {
-					return "", "", err
+					return nil, "", err
 				}
 			}
-			bts, err := te.Contents()
-			if err != nil {
-				return "", "", err
-			}
-			return string(bts), fp, nil
+			return te, fp, nil
 		}
 	}
-	return "", "", ErrFileNotFound
+	return nil, "", ErrFileNotFound
 }
 
 // Returns true if path is a directory containing an `objects` directory and a
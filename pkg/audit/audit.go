@@ -0,0 +1,237 @@
+// Package audit exports the events of the server to external sinks, e.g. a
+// SIEM.
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+)
+
+var (
+	// retryMinInterval is how long the delivery of an event waits before
+	// being retried the first time. It's doubled at every retry.
+	retryMinInterval = time.Second
+
+	// retryMaxInterval is the longest the delivery of an event waits before
+	// being retried.
+	retryMaxInterval = time.Minute
+)
+
+// ErrClosed is returned by Start once the exporter is shut down.
+var ErrClosed = errors.New("audit: exporter closed")
+
+// writer writes formatted events to a sink.
+type writer interface {
+	// Write writes an event, without a trailing newline.
+	Write(ctx context.Context, msg []byte) error
+	Close() error
+}
+
+// sink is a sink with the events waiting to be written to it.
+type sink struct {
+	cfg     config.AuditSink
+	w       writer
+	queue   chan []byte
+	dropped atomic.Int64
+}
+
+// enqueue queues an event without blocking, dropping the oldest one if the
+// queue is full. It must only be called by one goroutine.
+func (s *sink) enqueue(msg []byte) {
+	for {
+		select {
+		case s.queue <- msg:
+			return
+		default:
+		}
+		select {
+		case <-s.queue:
+			s.dropped.Add(1)
+		default:
+		}
+	}
+}
+
+// Exporter exports the events of the server to the sinks of the config.
+// Delivery is best-effort: every sink has its own buffer, so a sink that
+// can't be reached doesn't hold back the others or the server, and the oldest
+// events are dropped once the buffer is full.
+type Exporter struct {
+	be     *backend.Backend
+	sinks  []*sink
+	logger *log.Logger
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	started atomic.Bool
+	// done is closed once the events are exported and the sinks closed.
+	done chan struct{}
+	// stop is closed to stop retrying the delivery of the buffered events.
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewExporter returns a new exporter of the events to the sinks of the
+// config. It expects a context with *backend.Backend, *log.Logger, and
+// *config.Config attached.
+func NewExporter(ctx context.Context) (*Exporter, error) {
+	cfg := config.FromContext(ctx)
+	e := &Exporter{
+		be:     backend.FromContext(ctx),
+		logger: log.FromContext(ctx).WithPrefix("audit"),
+		done:   make(chan struct{}),
+		stop:   make(chan struct{}),
+	}
+	for _, sc := range cfg.Audit.Sinks {
+		w, err := newWriter(sc)
+		if err != nil {
+			return nil, fmt.Errorf("create %s audit sink: %w", sc.Type, err)
+		}
+		e.sinks = append(e.sinks, &sink{
+			cfg:   sc,
+			w:     w,
+			queue: make(chan []byte, cfg.Audit.BufferSize),
+		})
+	}
+	e.ctx, e.cancel = context.WithCancel(ctx)
+	return e, nil
+}
+
+// newWriter returns the writer of a sink.
+func newWriter(s config.AuditSink) (writer, error) {
+	switch s.Type {
+	case config.AuditSinkFile:
+		return &fileWriter{path: s.Address}, nil
+	case config.AuditSinkSyslog:
+		return newSyslogWriter(s.Address)
+	case config.AuditSinkWebhook:
+		return newWebhookWriter(s.Address, s.Format), nil
+	default:
+		return nil, fmt.Errorf("unknown sink type %q", s.Type)
+	}
+}
+
+// Start exports the events until the exporter is shut down, then returns
+// ErrClosed.
+func (e *Exporter) Start() error {
+	e.started.Store(true)
+	defer close(e.done)
+
+	events, err := e.be.SubscribeEvents(e.ctx)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, s := range e.sinks {
+		wg.Add(1)
+		go func(s *sink) {
+			defer wg.Done()
+			e.deliver(s)
+		}(s)
+	}
+
+	for ev := range events {
+		msgs := make(map[string][]byte)
+		for _, s := range e.sinks {
+			if !s.cfg.Exports(ev.Type) {
+				continue
+			}
+			msg, ok := msgs[s.cfg.Format]
+			if !ok {
+				msg, err = Format(ev, s.cfg.Format)
+				if err != nil {
+					e.logger.Error("error formatting event", "type", ev.Type, "err", err)
+					continue
+				}
+				msgs[s.cfg.Format] = msg
+			}
+			s.enqueue(msg)
+		}
+	}
+
+	// The subscription is over, deliver the buffered events.
+	for _, s := range e.sinks {
+		close(s.queue)
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, s := range e.sinks {
+		errs = append(errs, s.w.Close())
+	}
+	if err := errors.Join(errs...); err != nil {
+		e.logger.Error("error closing audit sinks", "err", err)
+	}
+	return ErrClosed
+}
+
+// deliver writes the queued events to the sink until the queue is closed and
+// empty, or the exporter stops retrying. Failed writes are retried with an
+// exponential backoff.
+func (e *Exporter) deliver(s *sink) {
+	failing := false
+	for msg := range s.queue {
+		backoff := retryMinInterval
+		for {
+			err := s.w.Write(context.Background(), msg)
+			if err == nil {
+				break
+			}
+			if !failing {
+				e.logger.Warn("error exporting event, retrying", "sink", s.cfg.Type, "err", err)
+				failing = true
+			}
+			select {
+			case <-e.stop:
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(2*backoff, retryMaxInterval)
+		}
+		if failing {
+			e.logger.Info("exporting events again", "sink", s.cfg.Type)
+			failing = false
+		}
+		if n := s.dropped.Swap(0); n > 0 {
+			e.logger.Warn("dropped events, the buffer was full", "sink", s.cfg.Type, "count", n)
+		}
+	}
+}
+
+// Shutdown stops exporting events. The buffered events are delivered until
+// ctx is done.
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	e.cancel()
+	if !e.started.Load() {
+		return nil
+	}
+	select {
+	case <-e.done:
+		return nil
+	case <-ctx.Done():
+		e.stopOnce.Do(func() { close(e.stop) })
+		<-e.done
+		return ctx.Err()
+	}
+}
+
+// Close stops exporting events and drops the buffered ones that can't be
+// delivered right away.
+func (e *Exporter) Close() error {
+	e.cancel()
+	if !e.started.Load() {
+		return nil
+	}
+	e.stopOnce.Do(func() { close(e.stop) })
+	<-e.done
+	return nil
+}
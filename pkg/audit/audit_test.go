@@ -0,0 +1,18 @@
+package audit
+
+import "testing"
+
+func TestSinkEnqueue(t *testing.T) {
+	s := &sink{queue: make(chan []byte, 2)}
+	for _, msg := range []string{"1", "2", "3", "4"} {
+		s.enqueue([]byte(msg))
+	}
+	if n := s.dropped.Load(); n != 2 {
+		t.Errorf("dropped = %d, want 2", n)
+	}
+	for _, want := range []string{"3", "4"} {
+		if got := string(<-s.queue); got != want {
+			t.Errorf("queued event = %s, want %s", got, want)
+		}
+	}
+}
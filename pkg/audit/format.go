@@ -0,0 +1,97 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/version"
+)
+
+// cefEventNames are the human-readable names of the event types in CEF.
+var cefEventNames = map[string]string{
+	proto.EventPush:       "Push",
+	proto.EventRefUpdate:  "Reference updated",
+	proto.EventRepoCreate: "Repository created",
+	proto.EventRepoDelete: "Repository deleted",
+	proto.EventRepoRename: "Repository renamed",
+}
+
+// Format formats an event, without a trailing newline. An empty format is
+// JSON.
+func Format(ev proto.Event, format string) ([]byte, error) {
+	switch format {
+	case "", config.AuditFormatJSON:
+		return json.Marshal(ev)
+	case config.AuditFormatCEF:
+		return []byte(formatCEF(ev)), nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// formatCEF formats an event in the ArcSight Common Event Format, e.g.
+//
+//	CEF:0|Charmbracelet|Soft Serve|0.8.0|push|Push|3|rt=1700000000000 suser=alice cs1Label=repo cs1=soft-serve ...
+func formatCEF(ev proto.Event) string {
+	name, ok := cefEventNames[ev.Type]
+	if !ok {
+		name = ev.Type
+	}
+	// Deleted repositories and rewritten histories stand out.
+	severity := 3
+	if ev.Type == proto.EventRepoDelete || ev.Forced {
+		severity = 6
+	}
+	ver := version.Version
+	if ver == "" {
+		ver = "unknown"
+	}
+
+	ext := []string{"rt=" + strconv.FormatInt(ev.Time.UnixMilli(), 10)}
+	add := func(key, value string) {
+		if value != "" {
+			ext = append(ext, key+"="+cefExtensionEscaper.Replace(value))
+		}
+	}
+	addLabeled := func(key, label, value string) {
+		if value != "" {
+			add(key+"Label", label)
+			add(key, value)
+		}
+	}
+	add("suser", ev.User)
+	addLabeled("cs1", "repo", ev.Repo)
+	addLabeled("cs2", "oldRepo", ev.OldRepo)
+	addLabeled("cs3", "ref", ev.Ref)
+	addLabeled("cs4", "before", ev.Before)
+	addLabeled("cs5", "after", ev.After)
+	if ev.Commits > 0 {
+		addLabeled("cn1", "commits", strconv.FormatInt(ev.Commits, 10))
+	}
+	if ev.Forced {
+		add("act", "forced")
+	}
+
+	return strings.Join([]string{
+		"CEF:0",
+		cefHeaderEscaper.Replace("Charmbracelet"),
+		cefHeaderEscaper.Replace("Soft Serve"),
+		cefHeaderEscaper.Replace(ver),
+		cefHeaderEscaper.Replace(ev.Type),
+		cefHeaderEscaper.Replace(name),
+		strconv.Itoa(severity),
+		strings.Join(ext, " "),
+	}, "|")
+}
+
+var (
+	// cefHeaderEscaper escapes the header fields of CEF events.
+	cefHeaderEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ")
+
+	// cefExtensionEscaper escapes the extension values of CEF events.
+	cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`)
+)
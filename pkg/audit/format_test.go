@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+)
+
+func TestFormat(t *testing.T) {
+	ev := proto.Event{
+		Type:    proto.EventPush,
+		Repo:    "repo1",
+		Ref:     "refs/heads/main",
+		Before:  "0000000",
+		After:   "1a2b3c4",
+		Commits: 2,
+		Forced:  true,
+		User:    "alice",
+		Time:    time.UnixMilli(1700000000000).UTC(),
+	}
+
+	tests := []struct {
+		name   string
+		ev     proto.Event
+		format string
+		want   string
+	}{
+		{
+			name: "JSON",
+			ev:   ev,
+			want: `{"type":"push","repo":"repo1","ref":"refs/heads/main","before":"0000000","after":"1a2b3c4","commits":2,"forced":true,"user":"alice","time":"2023-11-14T22:13:20Z"}`,
+		},
+		{
+			name:   "CEF",
+			ev:     ev,
+			format: "cef",
+			want:   `CEF:0|Charmbracelet|Soft Serve|unknown|push|Push|6|rt=1700000000000 suser=alice cs1Label=repo cs1=repo1 cs3Label=ref cs3=refs/heads/main cs4Label=before cs4=0000000 cs5Label=after cs5=1a2b3c4 cn1Label=commits cn1=2 act=forced`,
+		},
+		{
+			name: "CEF escaping",
+			ev: proto.Event{
+				Type:    proto.EventRepoRename,
+				Repo:    `a=b\c`,
+				OldRepo: "old\nrepo",
+				Time:    time.UnixMilli(0),
+			},
+			format: "cef",
+			want:   `CEF:0|Charmbracelet|Soft Serve|unknown|repo_rename|Repository renamed|3|rt=0 cs1Label=repo cs1=a\=b\\c cs2Label=oldRepo cs2=old\nrepo`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Format(tt.ev, tt.format)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Format() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Format(ev, "xml"); err == nil {
+		t.Error("Format() error = nil, want an error")
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+
+package audit
+
+import "errors"
+
+// newSyslogWriter returns an error, syslog isn't supported on this platform.
+func newSyslogWriter(string) (writer, error) {
+	return nil, errors.New("syslog isn't supported on this platform")
+}
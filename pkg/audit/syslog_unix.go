@@ -0,0 +1,62 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package audit
+
+import (
+	"context"
+	"log/syslog"
+	"net/url"
+)
+
+// syslogTag is the tag of the events sent to syslog.
+const syslogTag = "soft-serve"
+
+// syslogWriter sends the events to a syslog server. It connects on the first
+// write, and reconnects after a failed one.
+type syslogWriter struct {
+	network string
+	addr    string
+	w       *syslog.Writer
+}
+
+// newSyslogWriter returns a writer to the syslog server at the given address,
+// e.g. "udp://host:514". An empty address is the local syslog server.
+func newSyslogWriter(address string) (writer, error) {
+	if address == "" {
+		return &syslogWriter{}, nil
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, err
+	}
+	addr := u.Host
+	if u.Scheme == "unix" || u.Scheme == "unixgram" {
+		addr = u.Path
+	}
+	return &syslogWriter{network: u.Scheme, addr: addr}, nil
+}
+
+// Write implements writer.
+func (w *syslogWriter) Write(_ context.Context, msg []byte) error {
+	if w.w == nil {
+		sw, err := syslog.Dial(w.network, w.addr, syslog.LOG_INFO|syslog.LOG_AUTH, syslogTag)
+		if err != nil {
+			return err
+		}
+		w.w = sw
+	}
+	if err := w.w.Info(string(msg)); err != nil {
+		w.w.Close() // nolint: errcheck
+		w.w = nil
+		return err
+	}
+	return nil
+}
+
+// Close implements writer.
+func (w *syslogWriter) Close() error {
+	if w.w == nil {
+		return nil
+	}
+	return w.w.Close()
+}
@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/version"
+)
+
+// fileWriter appends the events to a file, one per line. The file is opened
+// on the first write, and reopened after a failed one, e.g. if it was removed
+// by a log rotation.
+type fileWriter struct {
+	path string
+	f    *os.File
+}
+
+// Write implements writer.
+func (w *fileWriter) Write(_ context.Context, msg []byte) error {
+	if w.f == nil {
+		if err := os.MkdirAll(filepath.Dir(w.path), os.ModePerm); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(w.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return err
+		}
+		w.f = f
+	}
+	if _, err := w.f.Write(append(msg, '\n')); err != nil {
+		w.f.Close() // nolint: errcheck
+		w.f = nil
+		return err
+	}
+	return nil
+}
+
+// Close implements writer.
+func (w *fileWriter) Close() error {
+	if w.f == nil {
+		return nil
+	}
+	return w.f.Close()
+}
+
+// webhookTimeout is the maximum time the delivery of an event to a webhook
+// can take.
+const webhookTimeout = 10 * time.Second
+
+// webhookWriter posts every event to a URL.
+type webhookWriter struct {
+	url         string
+	contentType string
+	client      *http.Client
+}
+
+func newWebhookWriter(url, format string) *webhookWriter {
+	contentType := "application/json"
+	if format == config.AuditFormatCEF {
+		contentType = "text/plain; charset=utf-8"
+	}
+	return &webhookWriter{
+		url:         url,
+		contentType: contentType,
+		client:      &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// Write implements writer.
+func (w *webhookWriter) Write(ctx context.Context, msg []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(msg))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", w.contentType)
+	req.Header.Set("User-Agent", "SoftServe/"+version.Version)
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()        // nolint: errcheck
+	io.Copy(io.Discard, res.Body) // nolint: errcheck
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", res.Status)
+	}
+	return nil
+}
+
+// Close implements writer.
+func (w *webhookWriter) Close() error {
+	w.client.CloseIdleConnections()
+	return nil
+}
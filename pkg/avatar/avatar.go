@@ -0,0 +1,167 @@
+package avatar
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	// Register the supported image formats.
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/dustin/go-humanize"
+)
+
+const (
+	// MaxSize is the maximum size of an avatar image in bytes.
+	MaxSize = 1 << 20
+	// MaxDimension is the maximum width and height of an avatar image in
+	// pixels.
+	MaxDimension = 4096
+	// Size is the width and height of the thumbnails Decode returns in
+	// pixels. They're scaled down further to be shown.
+	Size = 12
+)
+
+var (
+	// ErrTooLarge is returned when an avatar image is bigger than MaxSize or
+	// MaxDimension.
+	ErrTooLarge = fmt.Errorf("avatar must be at most %s and %dx%d pixels",
+		humanize.IBytes(MaxSize), MaxDimension, MaxDimension)
+
+	// ErrInvalidImage is returned when an avatar isn't a PNG, JPEG, or GIF
+	// image.
+	ErrInvalidImage = errors.New("avatar must be a PNG, JPEG, or GIF image")
+)
+
+// Decode decodes an avatar image and returns its thumbnail. The size of the
+// image is checked before it's decoded.
+func Decode(data []byte) (image.Image, error) {
+	if len(data) > MaxSize {
+		return nil, ErrTooLarge
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if cfg.Width > MaxDimension || cfg.Height > MaxDimension {
+		return nil, ErrTooLarge
+	}
+	if cfg.Width == 0 || cfg.Height == 0 {
+		return nil, ErrInvalidImage
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	return Thumbnail(img, Size), nil
+}
+
+// Thumbnail crops the image to a centered square and scales it down to size
+// by size pixels. Every pixel is the average of the pixels it covers.
+func Thumbnail(img image.Image, size int) *image.NRGBA {
+	b := img.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+
+	src := image.NewNRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(src, src.Bounds(), img, image.Pt(x0, y0), draw.Src)
+
+	thumb := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for ty := 0; ty < size; ty++ {
+		for tx := 0; tx < size; tx++ {
+			sx0, sx1 := tx*side/size, max((tx+1)*side/size, tx*side/size+1)
+			sy0, sy1 := ty*side/size, max((ty+1)*side/size, ty*side/size+1)
+			var r, g, b, a, n uint64
+			for y := sy0; y < sy1 && y < side; y++ {
+				for x := sx0; x < sx1 && x < side; x++ {
+					c := src.NRGBAAt(x, y)
+					r += uint64(c.R)
+					g += uint64(c.G)
+					b += uint64(c.B)
+					a += uint64(c.A)
+					n++
+				}
+			}
+			if n == 0 {
+				continue
+			}
+			thumb.SetNRGBA(tx, ty, color.NRGBA{
+				R: uint8(r / n),
+				G: uint8(g / n),
+				B: uint8(b / n),
+				A: uint8(a / n),
+			})
+		}
+	}
+	return thumb
+}
+
+// Identicon returns a size by size pixels image generated from the name. It
+// is symmetric, has a single color on a transparent background, and the same
+// name always gives the same image.
+func Identicon(name string, size int) *image.NRGBA {
+	sum := sha256.Sum256([]byte(name))
+	fg := hslColor(float64(sum[0])/255*360, 0.5+float64(sum[1])/255*0.2, 0.5)
+
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	half := (size + 1) / 2
+	bits := sum[2:]
+	filled := false
+	for y := 0; y < size; y++ {
+		for x := 0; x < half; x++ {
+			i := y*half + x
+			if bits[i/8%len(bits)]&(1<<(i%8)) == 0 {
+				continue
+			}
+			filled = true
+			img.SetNRGBA(x, y, fg)
+			img.SetNRGBA(size-1-x, y, fg)
+		}
+	}
+	if !filled {
+		// Don't leave the avatar blank.
+		for y := 0; y < size; y++ {
+			img.SetNRGBA(y, y, fg)
+			img.SetNRGBA(size-1-y, y, fg)
+		}
+	}
+	return img
+}
+
+// hslColor converts a hue in degrees, a saturation, and a lightness to an
+// opaque color.
+func hslColor(h, s, l float64) color.NRGBA {
+	c := (1 - math.Abs(2*l-1)) * s
+	hp := h / 60
+	x := c * (1 - math.Abs(math.Mod(hp, 2)-1))
+	var r, g, b float64
+	switch {
+	case hp < 1:
+		r, g = c, x
+	case hp < 2:
+		r, g = x, c
+	case hp < 3:
+		g, b = c, x
+	case hp < 4:
+		g, b = x, c
+	case hp < 5:
+		r, b = x, c
+	default:
+		r, b = c, x
+	}
+	m := l - c/2
+	return color.NRGBA{
+		R: uint8((r + m) * 255),
+		G: uint8((g + m) * 255),
+		B: uint8((b + m) * 255),
+		A: 0xff,
+	}
+}
@@ -0,0 +1,69 @@
+package avatar
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecode(t *testing.T) {
+	is := is.New(t)
+
+	// Left half red, right half blue, with a taller than wide image that's
+	// cropped to its center.
+	img := image.NewNRGBA(image.Rect(0, 0, 24, 48))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 24; x++ {
+			c := color.NRGBA{R: 0xff, A: 0xff}
+			if x >= 12 {
+				c = color.NRGBA{B: 0xff, A: 0xff}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	thumb, err := Decode(encodePNG(t, img))
+	is.NoErr(err)
+	is.Equal(thumb.Bounds(), image.Rect(0, 0, Size, Size))
+	is.Equal(color.NRGBAModel.Convert(thumb.At(0, 0)), color.NRGBA{R: 0xff, A: 0xff})
+	is.Equal(color.NRGBAModel.Convert(thumb.At(Size-1, Size-1)), color.NRGBA{B: 0xff, A: 0xff})
+
+	_, err = Decode([]byte("hello"))
+	is.Equal(err, ErrInvalidImage)
+
+	_, err = Decode(encodePNG(t, image.NewGray(image.Rect(0, 0, MaxDimension+1, 1))))
+	is.Equal(err, ErrTooLarge)
+
+	_, err = Decode(make([]byte, MaxSize+1))
+	is.Equal(err, ErrTooLarge)
+}
+
+func TestIdenticon(t *testing.T) {
+	is := is.New(t)
+	a := Identicon("repo1", 6)
+	is.Equal(a.Pix, Identicon("repo1", 6).Pix)
+	is.True(!bytes.Equal(a.Pix, Identicon("repo2", 6).Pix))
+
+	filled := false
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 6; x++ {
+			is.Equal(a.NRGBAAt(x, y), a.NRGBAAt(5-x, y))
+			if a.NRGBAAt(x, y).A != 0 {
+				filled = true
+			}
+		}
+	}
+	is.True(filled)
+}
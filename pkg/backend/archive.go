@@ -0,0 +1,87 @@
+package backend
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// IsArchived returns true if the repository is archived.
+func (d *Backend) IsArchived(ctx context.Context, name string) (bool, error) {
+	name = utils.SanitizeRepo(name)
+	var archived bool
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		archived, err = d.store.GetRepoIsArchivedByName(ctx, tx, name)
+		return err
+	}); err != nil {
+		return false, db.WrapError(err)
+	}
+
+	return archived, nil
+}
+
+// SetArchived archives or unarchives a repository. Archived repositories can
+// be cloned but reject pushes. A repository that's unarchived gets a whole
+// new period of inactivity before it's archived again.
+func (d *Backend) SetArchived(ctx context.Context, name string, archived bool) error {
+	name = utils.SanitizeRepo(name)
+
+	// Delete cache
+	d.cache.Delete(name)
+
+	return db.WrapError(d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return d.store.SetRepoIsArchivedByName(ctx, tx, name, archived)
+	}))
+}
+
+// ArchiveIdleRepositories archives the repositories that weren't pushed to,
+// created, or unarchived in the last ArchiveAfter days of the config, and
+// returns their names. Mirrors are left alone, they're updated by syncs. It
+// does nothing when ArchiveAfter is zero.
+func (d *Backend) ArchiveIdleRepositories(ctx context.Context) ([]string, error) {
+	days := d.cfg.Repo.ArchiveAfter
+	if days <= 0 {
+		return nil, nil
+	}
+
+	repos, err := d.Repositories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -days)
+	archived := make([]string, 0)
+	for _, r := range repos {
+		rr, ok := r.(*repo)
+		if !ok || rr.IsArchived() || rr.IsMirror() {
+			continue
+		}
+
+		if !rr.lastActivity().Before(cutoff) {
+			continue
+		}
+
+		if err := d.SetArchived(ctx, rr.Name(), true); err != nil {
+			return archived, err
+		}
+		archived = append(archived, rr.Name())
+	}
+
+	return archived, nil
+}
+
+// lastActivity returns the last time the repository was pushed to, created,
+// or archived or unarchived.
+func (r *repo) lastActivity() time.Time {
+	last := r.UpdatedAt()
+	if t := r.CreatedAt(); t.After(last) {
+		last = t
+	}
+	if t := r.repo.ArchiveChangedAt; t.Valid && t.Time.After(last) {
+		last = t.Time
+	}
+	return last
+}
@@ -0,0 +1,38 @@
+package backend
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+func TestRepoLastActivity(t *testing.T) {
+	now := time.Now()
+	old := now.AddDate(0, 0, -100)
+	r := &repo{
+		name: "repo1",
+		path: filepath.Join(t.TempDir(), "repo1.git"),
+		repo: models.Repo{CreatedAt: old, UpdatedAt: old},
+	}
+	if got := r.lastActivity(); !got.Equal(old) {
+		t.Errorf("last activity %v, want %v", got, old)
+	}
+
+	// Unarchiving a repository counts as activity.
+	r.repo.ArchiveChangedAt = sql.NullTime{Time: now, Valid: true}
+	if got := r.lastActivity(); !got.Equal(now) {
+		t.Errorf("last activity %v, want %v", got, now)
+	}
+
+	// So does a push.
+	later := now.Add(time.Hour)
+	if err := r.writeLastModified(later); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.lastActivity(); !got.Equal(later.Truncate(time.Second)) {
+		t.Errorf("last activity %v, want %v", got, later)
+	}
+}
@@ -0,0 +1,223 @@
+package backend
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/keygen"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// AttestationNamespace is the SSH signature namespace of attestations. It
+// keeps them apart from the signatures of commits and tags by their authors.
+const AttestationNamespace = "soft-serve-attestation"
+
+var (
+	// ErrAttestationsDisabled is returned when no attestation key is set.
+	ErrAttestationsDisabled = errors.New("attestations are disabled")
+
+	// ErrNoAttestation is returned when a repository has no attestation.
+	ErrNoAttestation = errors.New("repository has no attestation")
+)
+
+// AttestedRef is a reference whose target differs from its attestation.
+// Attested is empty when the reference was created after the attestation,
+// and Current is empty when it was deleted.
+type AttestedRef struct {
+	Name     string
+	Attested string
+	Current  string
+}
+
+// AttestationKey returns the public key attestations are signed with.
+func (d *Backend) AttestationKey() (gossh.PublicKey, error) {
+	kp, err := d.attestationKey()
+	if err != nil {
+		return nil, err
+	}
+	return kp.PublicKey(), nil
+}
+
+// Attestation returns the last attestation of a repository.
+func (d *Backend) Attestation(ctx context.Context, name string) (models.Attestation, error) {
+	r, err := d.repoModel(ctx, name)
+	if err != nil {
+		return models.Attestation{}, err
+	}
+	if r.repo.Attestation.Statement == "" {
+		return models.Attestation{}, ErrNoAttestation
+	}
+	return r.repo.Attestation, nil
+}
+
+// Attest signs the current references of a repository with the attestation
+// key and keeps the attestation, replacing the last one. The attestation
+// states which commit every reference pointed to when it was signed, and
+// which remotes a mirror is synced from. It doesn't vouch for the authors of
+// the commits.
+func (d *Backend) Attest(ctx context.Context, name string) (models.Attestation, error) {
+	kp, err := d.attestationKey()
+	if err != nil {
+		return models.Attestation{}, err
+	}
+	r, err := d.repoModel(ctx, name)
+	if err != nil {
+		return models.Attestation{}, err
+	}
+
+	refs, err := d.refTargets(r)
+	if err != nil {
+		return models.Attestation{}, err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "soft-serve attestation v1\n")
+	fmt.Fprintf(&sb, "server: %s\n", d.cfg.Name)
+	if d.cfg.SSH.PublicURL != "" {
+		fmt.Fprintf(&sb, "url: %s\n", d.cfg.SSH.PublicURL)
+	}
+	fmt.Fprintf(&sb, "repository: %s\n", r.name)
+	if r.IsMirror() {
+		remotes, err := d.MirrorRemotes(ctx, r.name)
+		if err != nil {
+			return models.Attestation{}, err
+		}
+		for _, rm := range remotes {
+			fmt.Fprintf(&sb, "mirror: %s %s\n", rm.Name, rm.URL)
+		}
+	}
+	fmt.Fprintf(&sb, "time: %s\n\n", time.Now().UTC().Format(time.RFC3339))
+	for _, ref := range refs {
+		fmt.Fprintf(&sb, "%s %s\n", ref[1], ref[0])
+	}
+
+	statement := sb.String()
+	sig, err := sshutils.Sign(kp.Signer(), AttestationNamespace, []byte(statement))
+	if err != nil {
+		return models.Attestation{}, err
+	}
+	att := models.Attestation{Statement: statement, Signature: string(sig)}
+
+	d.cache.Delete(r.name)
+	if err := db.WrapError(d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return d.store.SetRepoAttestationByName(ctx, tx, r.name, att)
+	})); err != nil {
+		return models.Attestation{}, err
+	}
+
+	return att, nil
+}
+
+// VerifyAttestation verifies the signature of the last attestation of a
+// repository with the attestation key, and returns the references that
+// changed since. The error wraps sshutils.ErrInvalidSignature when the
+// signature doesn't match.
+func (d *Backend) VerifyAttestation(ctx context.Context, name string) ([]AttestedRef, error) {
+	pk, err := d.AttestationKey()
+	if err != nil {
+		return nil, err
+	}
+	att, err := d.Attestation(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := sshutils.Verify(pk, AttestationNamespace, []byte(att.Statement), []byte(att.Signature)); err != nil {
+		return nil, err
+	}
+
+	r, err := d.repoModel(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := d.refTargets(r)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]string, len(refs))
+	for _, ref := range refs {
+		current[ref[0]] = ref[1]
+	}
+
+	attested := parseAttestedRefs(att.Statement)
+	var changed []AttestedRef
+	for ref, id := range attested {
+		if current[ref] != id {
+			changed = append(changed, AttestedRef{Name: ref, Attested: id, Current: current[ref]})
+		}
+	}
+	for ref, id := range current {
+		if _, ok := attested[ref]; !ok {
+			changed = append(changed, AttestedRef{Name: ref, Current: id})
+		}
+	}
+	sort.Slice(changed, func(i, j int) bool {
+		return changed[i].Name < changed[j].Name
+	})
+
+	return changed, nil
+}
+
+// attestMirror attests a mirror after a sync, when attestations are enabled.
+// Failures are logged, they don't fail the sync.
+func (d *Backend) attestMirror(ctx context.Context, name string) {
+	if d.cfg.Attestation.KeyPath == "" {
+		return
+	}
+	if _, err := d.Attest(ctx, name); err != nil {
+		d.logger.Error("failed to attest mirror", "repo", name, "err", err)
+	}
+}
+
+// attestationKey returns the key pair attestations are signed with.
+func (d *Backend) attestationKey() (*keygen.SSHKeyPair, error) {
+	kp, err := config.AttestationKeyPair(d.cfg)
+	if errors.Is(err, config.ErrEmptySSHKeyPath) {
+		return nil, ErrAttestationsDisabled
+	}
+	return kp, err
+}
+
+// refTargets returns the name and the target of every reference of a
+// repository, sorted by name.
+func (d *Backend) refTargets(r *repo) ([][2]string, error) {
+	out, err := git.NewCommand("for-each-ref", "--format=%(refname) %(objectname)").RunInDir(r.path)
+	if err != nil {
+		return nil, err
+	}
+	var refs [][2]string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if name, id, ok := strings.Cut(line, " "); ok {
+			refs = append(refs, [2]string{name, id})
+		}
+	}
+	return refs, nil
+}
+
+// parseAttestedRefs returns the targets of the references of a statement, by
+// name. They follow the first empty line.
+func parseAttestedRefs(statement string) map[string]string {
+	refs := make(map[string]string)
+	s := bufio.NewScanner(strings.NewReader(statement))
+	header := true
+	for s.Scan() {
+		line := s.Text()
+		if header {
+			header = line != ""
+			continue
+		}
+		if id, name, ok := strings.Cut(line, " "); ok {
+			refs[name] = id
+		}
+	}
+	return refs
+}
@@ -0,0 +1,25 @@
+package backend
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAttestedRefs(t *testing.T) {
+	statement := `soft-serve attestation v1
+server: Soft Serve
+repository: repo1
+mirror: origin https://example.com/repo1.git
+time: 2024-01-02T03:04:05Z
+
+1111111111111111111111111111111111111111 refs/heads/main
+2222222222222222222222222222222222222222 refs/tags/v1.0.0
+`
+	want := map[string]string{
+		"refs/heads/main":  "1111111111111111111111111111111111111111",
+		"refs/tags/v1.0.0": "2222222222222222222222222222222222222222",
+	}
+	if got := parseAttestedRefs(statement); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseAttestedRefs() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,200 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/charmbracelet/soft-serve/pkg/avatar"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// avatarEntry is a cached avatar thumbnail. version changes when the image
+// of the avatar might have changed.
+type avatarEntry struct {
+	version string
+	img     image.Image
+}
+
+// AvatarPath returns the path of the avatar image of a repository in its
+// default branch, or whether the avatar was uploaded. Both are empty when the
+// repository has no avatar.
+func (d *Backend) AvatarPath(ctx context.Context, name string) (path string, uploaded bool, err error) {
+	r, err := d.repoModel(ctx, name)
+	if err != nil {
+		return "", false, err
+	}
+	if r.repo.Avatar != "" {
+		return r.repo.Avatar, false, nil
+	}
+	if _, err := os.Stat(d.avatarFile(r.ID())); err == nil {
+		return "", true, nil
+	}
+	return "", false, nil
+}
+
+// Avatar returns the thumbnail of the avatar of a repository. It's nil when
+// the repository has no avatar, or its image can't be read. Thumbnails are
+// cached until the avatar or the repository changes.
+func (d *Backend) Avatar(ctx context.Context, name string) (image.Image, error) {
+	r, err := d.repoModel(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var version string
+	if p := r.repo.Avatar; p != "" {
+		version = fmt.Sprintf("%s@%d", p, r.UpdatedAt().UnixNano())
+	} else {
+		info, err := os.Stat(d.avatarFile(r.ID()))
+		if err != nil {
+			return nil, nil
+		}
+		version = fmt.Sprintf("@%d-%d", info.ModTime().UnixNano(), info.Size())
+	}
+
+	if e, ok := d.cache.avatars.Get(r.name); ok && e.version == version {
+		return e.img, nil
+	}
+
+	img, err := d.loadAvatar(r)
+	if err != nil {
+		d.logger.Debug("failed to load avatar", "repo", r.name, "err", err)
+	}
+	d.cache.avatars.Add(r.name, avatarEntry{version: version, img: img})
+	return img, nil
+}
+
+// SetAvatar sets the avatar of a repository to an uploaded image.
+func (d *Backend) SetAvatar(ctx context.Context, name string, data []byte) error {
+	r, err := d.repoModel(ctx, name)
+	if err != nil {
+		return err
+	}
+	if _, err := avatar.Decode(data); err != nil {
+		return err
+	}
+
+	fp := d.avatarFile(r.ID())
+	if err := os.MkdirAll(filepath.Dir(fp), os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.WriteFile(fp, data, 0o600); err != nil {
+		return err
+	}
+
+	return d.setAvatarPath(ctx, r.name, "")
+}
+
+// SetAvatarPath sets the avatar of a repository to an image in its default
+// branch. The image is read again after pushes.
+func (d *Backend) SetAvatarPath(ctx context.Context, name string, path string) error {
+	r, err := d.repoModel(ctx, name)
+	if err != nil {
+		return err
+	}
+	data, err := d.readRepoAvatar(r, path)
+	if err != nil {
+		return err
+	}
+	if _, err := avatar.Decode(data); err != nil {
+		return err
+	}
+
+	if err := os.Remove(d.avatarFile(r.ID())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return d.setAvatarPath(ctx, r.name, path)
+}
+
+// DeleteAvatar removes the avatar of a repository.
+func (d *Backend) DeleteAvatar(ctx context.Context, name string) error {
+	r, err := d.repoModel(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(d.avatarFile(r.ID())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return d.setAvatarPath(ctx, r.name, "")
+}
+
+func (d *Backend) setAvatarPath(ctx context.Context, name string, path string) error {
+	// Delete cache
+	d.cache.Delete(name)
+
+	return db.WrapError(d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return d.store.SetRepoAvatarByName(ctx, tx, name, path)
+	}))
+}
+
+// repoModel returns the repository with its database model.
+func (d *Backend) repoModel(ctx context.Context, name string) (*repo, error) {
+	rr, err := d.Repository(ctx, utils.SanitizeRepo(name))
+	if err != nil {
+		return nil, err
+	}
+	r, ok := rr.(*repo)
+	if !ok {
+		return nil, proto.ErrRepoNotFound
+	}
+	return r, nil
+}
+
+// avatarFile returns the path of the uploaded avatar of the repository with
+// the given ID.
+func (d *Backend) avatarFile(id int64) string {
+	return filepath.Join(d.cfg.DataPath, "avatars", strconv.FormatInt(id, 10))
+}
+
+// loadAvatar reads and decodes the avatar of the repository.
+func (d *Backend) loadAvatar(r *repo) (image.Image, error) {
+	var data []byte
+	var err error
+	if p := r.repo.Avatar; p != "" {
+		data, err = d.readRepoAvatar(r, p)
+	} else {
+		data, err = os.ReadFile(d.avatarFile(r.ID()))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return avatar.Decode(data)
+}
+
+// readRepoAvatar reads the image at the given path in the default branch of
+// the repository. Files bigger than avatar.MaxSize aren't read.
+func (d *Backend) readRepoAvatar(r *repo, path string) ([]byte, error) {
+	gr, err := r.Open()
+	if err != nil {
+		return nil, err
+	}
+	ref, err := gr.HEAD()
+	if err != nil {
+		return nil, err
+	}
+	tree, err := gr.Tree(ref)
+	if err != nil {
+		return nil, err
+	}
+	te, err := tree.TreeEntry(path)
+	if err != nil {
+		return nil, proto.ErrFileNotFound
+	}
+	if te.IsTree() {
+		return nil, proto.ErrFileNotFound
+	}
+	if te.Size() > avatar.MaxSize {
+		return nil, avatar.ErrTooLarge
+	}
+	return te.Contents()
+}
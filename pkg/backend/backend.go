@@ -2,10 +2,14 @@ package backend
 
 import (
 	"context"
+	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/charmbracelet/soft-serve/pkg/config"
 	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/storage"
 	"github.com/charmbracelet/soft-serve/pkg/store"
 	"github.com/charmbracelet/soft-serve/pkg/task"
 )
@@ -20,10 +24,35 @@ type Backend struct {
 	logger  *log.Logger
 	cache   *cache
 	manager *task.Manager
+	events  *eventHub
+
+	// repos stores the repositories.
+	repos storage.RepoStorage
+
+	// identities resolves public keys to users.
+	identities *identityCache
+
+	housekeeping *housekeeping
+	operations   *operations
+	pushReports  *pushReports
+
+	// mirrorSyncs are the names of the mirrors being synced.
+	mirrorSyncs sync.Map
+}
+
+// Option is an option of the backend.
+type Option func(*Backend)
+
+// WithRepoStorage sets the storage of the repositories. They're stored on the
+// local filesystem by default.
+func WithRepoStorage(s storage.RepoStorage) Option {
+	return func(b *Backend) {
+		b.repos = s
+	}
 }
 
 // New returns a new Soft Serve backend.
-func New(ctx context.Context, cfg *config.Config, db *db.DB, st store.Store) *Backend {
+func New(ctx context.Context, cfg *config.Config, db *db.DB, st store.Store, opts ...Option) *Backend {
 	logger := log.FromContext(ctx).WithPrefix("backend")
 	b := &Backend{
 		ctx:     ctx,
@@ -32,6 +61,21 @@ func New(ctx context.Context, cfg *config.Config, db *db.DB, st store.Store) *Ba
 		store:   st,
 		logger:  logger,
 		manager: task.NewManager(ctx),
+		events:  newEventHub(),
+
+		housekeeping: newHousekeeping(),
+		operations:   newOperations(),
+		pushReports:  newPushReports(),
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.repos == nil {
+		b.repos = storage.NewLocalRepoStorage(filepath.Join(cfg.DataPath, "repos"))
+	}
+	if b.identities == nil {
+		b.identities = newIdentityCache(newIdentityResolver(b), time.Duration(cfg.Identity.TTL)*time.Second)
 	}
 
 	// TODO: implement a proper caching interface
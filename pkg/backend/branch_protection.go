@@ -0,0 +1,198 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/hooks"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+var (
+	// ErrInvalidBranchPattern is returned when a branch protection pattern
+	// isn't a valid glob.
+	ErrInvalidBranchPattern = errors.New("invalid branch pattern")
+
+	// ErrInvalidStatusContext is returned when a required status context of
+	// a branch protection rule is empty or has a comma.
+	ErrInvalidStatusContext = errors.New("invalid status context")
+)
+
+// BranchProtections returns the branch protection rules of a repository
+// ordered by pattern.
+func (d *Backend) BranchProtections(ctx context.Context, repo string) ([]proto.BranchProtection, error) {
+	r, err := d.Repository(ctx, utils.SanitizeRepo(repo))
+	if err != nil {
+		return nil, err
+	}
+
+	var ms []models.BranchProtection
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		ms, err = d.store.GetBranchProtectionsByRepoID(ctx, tx, r.ID())
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	rules := make([]proto.BranchProtection, len(ms))
+	for i, m := range ms {
+		rules[i] = proto.BranchProtection{
+			Pattern:     m.Pattern,
+			FastForward: m.FastForward,
+		}
+		if m.RequiredStatuses != "" {
+			rules[i].RequiredStatuses = strings.Split(m.RequiredStatuses, ",")
+		}
+	}
+
+	return rules, nil
+}
+
+// SetBranchProtection creates or replaces the branch protection rule of a
+// repository for the pattern of the rule.
+func (d *Backend) SetBranchProtection(ctx context.Context, repo string, rule proto.BranchProtection) error {
+	if _, err := path.Match(rule.Pattern, ""); err != nil || strings.TrimSpace(rule.Pattern) == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidBranchPattern, rule.Pattern)
+	}
+
+	contexts := make([]string, 0, len(rule.RequiredStatuses))
+	for _, c := range rule.RequiredStatuses {
+		c = strings.TrimSpace(c)
+		if c == "" || strings.Contains(c, ",") {
+			return fmt.Errorf("%w: %q", ErrInvalidStatusContext, c)
+		}
+		contexts = append(contexts, c)
+	}
+
+	r, err := d.Repository(ctx, utils.SanitizeRepo(repo))
+	if err != nil {
+		return err
+	}
+
+	return db.WrapError(d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return d.store.SetBranchProtection(ctx, tx, r.ID(), rule.Pattern, strings.Join(contexts, ","), rule.FastForward)
+	}))
+}
+
+// DeleteBranchProtection deletes the branch protection rule of a repository
+// for a pattern.
+func (d *Backend) DeleteBranchProtection(ctx context.Context, repo string, pattern string) error {
+	r, err := d.Repository(ctx, utils.SanitizeRepo(repo))
+	if err != nil {
+		return err
+	}
+
+	return db.WrapError(d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return d.store.DeleteBranchProtection(ctx, tx, r.ID(), pattern)
+	}))
+}
+
+// CheckBranchProtections checks the reference updates of a push against the
+// branch protection rules of the repository. It's called by the git
+// pre-receive hook before any reference is updated, and returns an error
+// that names the rejected branch and the rule it breaks.
+func (d *Backend) CheckBranchProtections(ctx context.Context, repo string, args []hooks.HookArg) error {
+	rules, err := d.BranchProtections(ctx, repo)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+
+	rr, err := d.Repository(ctx, utils.SanitizeRepo(repo))
+	if err != nil {
+		return err
+	}
+
+	r, err := rr.Open()
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range args {
+		for _, rule := range rules {
+			if !MatchBranchProtection(rule.Pattern, arg.RefName) {
+				continue
+			}
+			if err := d.checkBranchProtection(ctx, rr, r, rule, arg); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkBranchProtection returns an error if the reference update breaks the
+// rule.
+func (d *Backend) checkBranchProtection(ctx context.Context, rr proto.Repository, r *git.Repository, rule proto.BranchProtection, arg hooks.HookArg) error {
+	name := strings.TrimPrefix(arg.RefName, git.RefsHeads)
+	if git.IsZeroHash(arg.NewSha) {
+		return fmt.Errorf("%s is protected by %q and can't be deleted", name, rule.Pattern)
+	}
+
+	if rule.FastForward && !git.IsZeroHash(arg.OldSha) {
+		forced, err := isForcedUpdate(r, arg.OldSha, arg.NewSha)
+		if err != nil {
+			return err
+		}
+		if forced {
+			return fmt.Errorf("%s is protected by %q and only accepts fast-forward pushes, %s isn't a descendant of %s",
+				name, rule.Pattern, arg.NewSha[:7], arg.OldSha[:7])
+		}
+	}
+
+	if len(rule.RequiredStatuses) == 0 {
+		return nil
+	}
+
+	statuses, err := d.CommitStatuses(ctx, rr, arg.NewSha)
+	if err != nil {
+		return err
+	}
+
+	return checkRequiredStatuses(name, rule, arg.NewSha, statuses)
+}
+
+// checkRequiredStatuses returns an error for the first required status
+// context that didn't succeed on the commit.
+func checkRequiredStatuses(name string, rule proto.BranchProtection, sha string, statuses []proto.CommitStatus) error {
+	states := make(map[string]proto.CommitState, len(statuses))
+	for _, s := range statuses {
+		states[s.Context] = s.State
+	}
+
+	for _, c := range rule.RequiredStatuses {
+		switch state, ok := states[c]; {
+		case !ok:
+			return fmt.Errorf("%s is protected by %q and requires a successful %q status, %s has none",
+				name, rule.Pattern, c, sha[:7])
+		case state != proto.CommitStateSuccess:
+			return fmt.Errorf("%s is protected by %q and requires a successful %q status, it's %s on %s",
+				name, rule.Pattern, c, state, sha[:7])
+		}
+	}
+
+	return nil
+}
+
+// MatchBranchProtection returns true if the pattern of a branch protection
+// rule matches the full reference name. Patterns match branch names unless
+// they start with "refs/".
+func MatchBranchProtection(pattern, ref string) bool {
+	name := ref
+	if !strings.HasPrefix(pattern, "refs/") {
+		if !strings.HasPrefix(ref, git.RefsHeads) {
+			return false
+		}
+		name = strings.TrimPrefix(ref, git.RefsHeads)
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
@@ -0,0 +1,61 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+)
+
+func TestMatchBranchProtection(t *testing.T) {
+	cases := []struct {
+		pattern string
+		ref     string
+		want    bool
+	}{
+		{"main", "refs/heads/main", true},
+		{"main", "refs/heads/main2", false},
+		{"main", "refs/tags/main", false},
+		{"release/*", "refs/heads/release/1.0", true},
+		{"release/*", "refs/heads/release/1.0/fix", false},
+		{"*", "refs/heads/feature", true},
+		{"refs/tags/v*", "refs/tags/v1.0.0", true},
+		{"refs/tags/v*", "refs/heads/v1", false},
+	}
+
+	for _, c := range cases {
+		if got := MatchBranchProtection(c.pattern, c.ref); got != c.want {
+			t.Errorf("MatchBranchProtection(%q, %q) = %v, want %v", c.pattern, c.ref, got, c.want)
+		}
+	}
+}
+
+func TestCheckRequiredStatuses(t *testing.T) {
+	sha := strings.Repeat("a", 40)
+	rule := proto.BranchProtection{Pattern: "main", RequiredStatuses: []string{"ci", "lint"}}
+	cases := []struct {
+		statuses []proto.CommitStatus
+		err      string
+	}{
+		{nil, `requires a successful "ci" status, aaaaaaa has none`},
+		{[]proto.CommitStatus{
+			{Context: "ci", State: proto.CommitStateSuccess},
+			{Context: "lint", State: proto.CommitStatePending},
+		}, `requires a successful "lint" status, it's pending on aaaaaaa`},
+		{[]proto.CommitStatus{
+			{Context: "ci", State: proto.CommitStateSuccess},
+			{Context: "lint", State: proto.CommitStateSuccess},
+			{Context: "other", State: proto.CommitStateFailure},
+		}, ""},
+	}
+
+	for i, c := range cases {
+		err := checkRequiredStatuses("main", rule, sha, c.statuses)
+		switch {
+		case c.err == "" && err != nil:
+			t.Errorf("case %d: unexpected error %v", i, err)
+		case c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)):
+			t.Errorf("case %d: error %v, want %q", i, err, c.err)
+		}
+	}
+}
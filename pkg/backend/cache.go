@@ -6,6 +6,9 @@ import lru "github.com/hashicorp/golang-lru/v2"
 type cache struct {
 	b     *Backend
 	repos *lru.Cache[string, *repo]
+
+	// avatars holds the avatar thumbnails of the repositories.
+	avatars *lru.Cache[string, avatarEntry]
 }
 
 func newCache(b *Backend, size int) *cache {
@@ -15,6 +18,7 @@ func newCache(b *Backend, size int) *cache {
 	c := &cache{b: b}
 	cache, _ := lru.New[string, *repo](size)
 	c.repos = cache
+	c.avatars, _ = lru.New[string, avatarEntry](size)
 	return c
 }
 
@@ -28,6 +32,7 @@ func (c *cache) Set(repo string, r *repo) {
 
 func (c *cache) Delete(repo string) {
 	c.repos.Remove(repo)
+	c.avatars.Remove(repo)
 }
 
 func (c *cache) Len() int {
@@ -0,0 +1,77 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"golang.org/x/crypto/ssh"
+)
+
+// UserByCertificate validates a user certificate and returns the user of the
+// first certificate principal that matches a username. It returns
+// proto.ErrUserNotFound if the certificate is valid but none of its
+// principals match a user.
+func (d *Backend) UserByCertificate(ctx context.Context, cert *ssh.Certificate) (proto.User, error) {
+	if err := d.CheckCertificate(cert); err != nil {
+		return nil, err
+	}
+
+	for _, p := range cert.ValidPrincipals {
+		if user, err := d.User(ctx, p); err == nil {
+			return user, nil
+		}
+	}
+
+	return nil, proto.ErrUserNotFound
+}
+
+// CheckCertificate checks that the user certificate is signed by a trusted
+// certificate authority, is within its validity period, and isn't revoked.
+func (d *Backend) CheckCertificate(cert *ssh.Certificate) error {
+	if cert.CertType != ssh.UserCert {
+		return fmt.Errorf("%w: not a user certificate", proto.ErrInvalidCertificate)
+	}
+
+	trusted := false
+	for _, ca := range d.cfg.UserCAKeys() {
+		if sshutils.KeysEqual(cert.SignatureKey, ca) {
+			trusted = true
+			break
+		}
+	}
+	if !trusted {
+		return proto.ErrUntrustedCertificate
+	}
+
+	// Any principal of the certificate is valid, the SSH login user is
+	// ignored just like with public keys.
+	var principal string
+	if len(cert.ValidPrincipals) > 0 {
+		principal = cert.ValidPrincipals[0]
+	}
+
+	checker := ssh.CertChecker{
+		IsRevoked: d.isCertificateRevoked,
+	}
+	if err := checker.CheckCert(principal, cert); err != nil {
+		return fmt.Errorf("%w: %v", proto.ErrInvalidCertificate, err)
+	}
+
+	return nil
+}
+
+// isCertificateRevoked returns whether the certificate serial number, key ID,
+// or certified key fingerprint is in the revoked certificates list.
+func (d *Backend) isCertificateRevoked(cert *ssh.Certificate) bool {
+	serial := strconv.FormatUint(cert.Serial, 10)
+	fp := ssh.FingerprintSHA256(cert.Key)
+	for _, r := range d.cfg.SSH.RevokedCertificates {
+		if r == serial || r == fp || (cert.KeyId != "" && r == cert.KeyId) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+const (
+	// MaxCloneInstructions is the maximum size of the clone instructions of a
+	// repository in bytes.
+	MaxCloneInstructions = 2048
+
+	// CloneInstructionsFile is the file in the default branch of a repository
+	// its clone instructions are read from when they aren't set.
+	CloneInstructionsFile = ".soft-serve/clone.md"
+)
+
+// ErrCloneInstructionsTooLong is returned when the clone instructions of a
+// repository are longer than MaxCloneInstructions.
+var ErrCloneInstructionsTooLong = fmt.Errorf("clone instructions are longer than %d bytes", MaxCloneInstructions)
+
+// CloneInstructions returns the clone instructions of a repository, the steps
+// to clone it besides the clone command, in Markdown. The instructions set
+// with SetCloneInstructions win over the CloneInstructionsFile of the default
+// branch. fromFile is true when they're read from the file.
+func (d *Backend) CloneInstructions(ctx context.Context, name string) (instructions string, fromFile bool, err error) {
+	r, err := d.repoModel(ctx, name)
+	if err != nil {
+		return "", false, err
+	}
+	if r.repo.CloneInstructions != "" {
+		return r.repo.CloneInstructions, false, nil
+	}
+
+	gr, err := r.Open()
+	if err != nil {
+		return "", false, err
+	}
+	ref, err := gr.HEAD()
+	if err != nil {
+		// Empty repositories have no file.
+		return "", false, nil
+	}
+	tree, err := gr.Tree(ref)
+	if err != nil {
+		return "", false, err
+	}
+	te, err := tree.TreeEntry(CloneInstructionsFile)
+	if err != nil || te.IsTree() {
+		return "", false, nil
+	}
+	if te.Size() > MaxCloneInstructions {
+		d.logger.Warn("clone instructions file is too large", "repo", r.name, "size", te.Size())
+		return "", false, nil
+	}
+	data, err := te.Contents()
+	if err != nil {
+		return "", false, err
+	}
+
+	return normalizeCloneInstructions(string(data)), true, nil
+}
+
+// SetCloneInstructions sets the clone instructions of a repository. Empty
+// instructions remove them, the CloneInstructionsFile of the default branch
+// is used again.
+func (d *Backend) SetCloneInstructions(ctx context.Context, name string, instructions string) error {
+	name = utils.SanitizeRepo(name)
+	instructions = normalizeCloneInstructions(instructions)
+	if len(instructions) > MaxCloneInstructions {
+		return ErrCloneInstructionsTooLong
+	}
+
+	r, err := d.repoModel(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	// Delete cache
+	d.cache.Delete(r.name)
+
+	return db.WrapError(d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return d.store.SetRepoCloneInstructionsByName(ctx, tx, r.name, instructions)
+	}))
+}
+
+// normalizeCloneInstructions uses Unix line endings and trims the blank lines
+// around the instructions.
+func normalizeCloneInstructions(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.Trim(s, "\n\t ")
+}
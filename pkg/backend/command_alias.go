@@ -0,0 +1,63 @@
+package backend
+
+import (
+	"context"
+	"errors"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"golang.org/x/crypto/ssh"
+)
+
+// CommandAliases returns the command aliases of a public key.
+func (d *Backend) CommandAliases(ctx context.Context, pk ssh.PublicKey) ([]models.CommandAlias, error) {
+	if pk == nil {
+		return nil, nil
+	}
+
+	var aliases []models.CommandAlias
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		aliases, err = d.store.GetCommandAliases(ctx, tx, sshutils.MarshalAuthorizedKey(pk))
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	return aliases, nil
+}
+
+// SetCommandAlias creates or replaces a command alias of a public key.
+func (d *Backend) SetCommandAlias(ctx context.Context, pk ssh.PublicKey, name string, command string) error {
+	if pk == nil {
+		return proto.ErrUnauthorized
+	}
+
+	return db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.SetCommandAlias(ctx, tx, sshutils.MarshalAuthorizedKey(pk), name, command)
+		}),
+	)
+}
+
+// DeleteCommandAlias deletes a command alias of a public key.
+func (d *Backend) DeleteCommandAlias(ctx context.Context, pk ssh.PublicKey, name string) error {
+	if pk == nil {
+		return proto.ErrUnauthorized
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.DeleteCommandAlias(ctx, tx, sshutils.MarshalAuthorizedKey(pk), name)
+		}),
+	); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return proto.ErrAliasNotFound
+		}
+		return err
+	}
+
+	return nil
+}
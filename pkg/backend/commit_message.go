@@ -0,0 +1,65 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/hooks"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// CheckCommitMessages checks the messages of the commits a push adds to the
+// branches and tags of a repository against the commit message policy of the
+// server. It's called by the git pre-receive hook before any reference is
+// updated, and returns an error that names the first offending commit and
+// the rule it breaks.
+func (d *Backend) CheckCommitMessages(ctx context.Context, repo string, args []hooks.HookArg) error {
+	policy := d.cfg.Repo.CommitMessage
+	if policy.Pattern == "" {
+		return nil
+	}
+
+	revs := make([]string, 0, len(args))
+	for _, arg := range args {
+		if git.IsZeroHash(arg.NewSha) {
+			continue
+		}
+		if strings.HasPrefix(arg.RefName, git.RefsHeads) || strings.HasPrefix(arg.RefName, git.RefsTags) {
+			revs = append(revs, arg.NewSha)
+		}
+	}
+	if len(revs) == 0 {
+		return nil
+	}
+
+	rr, err := d.Repository(ctx, utils.SanitizeRepo(repo))
+	if err != nil {
+		return err
+	}
+
+	r, err := rr.Open()
+	if err != nil {
+		return err
+	}
+
+	commits, err := r.NewCommitMessages(revs...)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range commits {
+		if err := policy.Check(c.Message, c.Parents); err != nil {
+			subject, _, _ := strings.Cut(c.Message, "\n")
+			msg := fmt.Sprintf("commit %s %q breaks the commit message policy, its message %s", c.Hash[:7], subject, err)
+			if policy.Hint != "" {
+				msg += ": " + policy.Hint
+			}
+			return errors.New(msg)
+		}
+	}
+
+	return nil
+}
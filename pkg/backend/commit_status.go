@@ -0,0 +1,94 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+)
+
+// DefaultCommitStatusContext is the context used for commit statuses that
+// don't specify one.
+const DefaultCommitStatusContext = "default"
+
+var shaRe = regexp.MustCompile(`^[0-9a-f]{40}([0-9a-f]{24})?$`)
+
+// SetCommitStatus creates or updates the status of a commit. Statuses are
+// keyed by the commit hash and the status context.
+func (d *Backend) SetCommitStatus(ctx context.Context, repo proto.Repository, status proto.CommitStatus) error {
+	sha := strings.ToLower(status.SHA)
+	if !shaRe.MatchString(sha) {
+		return errors.New("invalid commit hash")
+	}
+
+	state, err := proto.ParseCommitState(status.State.String())
+	if err != nil {
+		return err
+	}
+
+	statusCtx := strings.TrimSpace(status.Context)
+	if statusCtx == "" {
+		statusCtx = DefaultCommitStatusContext
+	}
+
+	return db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.SetCommitStatus(ctx, tx, repo.ID(), sha, statusCtx,
+				state.String(), status.Description, status.TargetURL)
+		}),
+	)
+}
+
+// CommitStatuses returns the statuses of a commit.
+func (d *Backend) CommitStatuses(ctx context.Context, repo proto.Repository, sha string) ([]proto.CommitStatus, error) {
+	var ms []models.CommitStatus
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		ms, err = d.store.GetCommitStatusesBySHA(ctx, tx, repo.ID(), strings.ToLower(sha))
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	statuses := make([]proto.CommitStatus, len(ms))
+	for i, m := range ms {
+		statuses[i] = commitStatusFromModel(m)
+	}
+
+	return statuses, nil
+}
+
+// CommitStatusesForCommits returns the statuses of the given commits keyed by
+// commit hash.
+func (d *Backend) CommitStatusesForCommits(ctx context.Context, repo proto.Repository, shas []string) (map[string][]proto.CommitStatus, error) {
+	var ms []models.CommitStatus
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		ms, err = d.store.GetCommitStatusesBySHAs(ctx, tx, repo.ID(), shas)
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	statuses := make(map[string][]proto.CommitStatus)
+	for _, m := range ms {
+		statuses[m.SHA] = append(statuses[m.SHA], commitStatusFromModel(m))
+	}
+
+	return statuses, nil
+}
+
+func commitStatusFromModel(m models.CommitStatus) proto.CommitStatus {
+	return proto.CommitStatus{
+		SHA:         m.SHA,
+		Context:     m.Context,
+		State:       proto.CommitState(m.State),
+		Description: m.Description.String,
+		TargetURL:   m.TargetURL.String,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
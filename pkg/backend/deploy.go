@@ -0,0 +1,215 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/hooks"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// ErrDeployScriptNotFound is returned when a deploy script isn't registered
+// in the config.
+var ErrDeployScriptNotFound = errors.New("deploy script not found")
+
+// deployWaitDelay is how long a killed deploy script has to release its
+// output before the hook stops waiting for it.
+const deployWaitDelay = 5 * time.Second
+
+// Deploys returns the deploy scripts bound to the branches of a repository
+// ordered by branch.
+func (d *Backend) Deploys(ctx context.Context, repo string) ([]proto.Deploy, error) {
+	r, err := d.Repository(ctx, utils.SanitizeRepo(repo))
+	if err != nil {
+		return nil, err
+	}
+
+	var ms []models.Deploy
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		ms, err = d.store.GetDeploysByRepoID(ctx, tx, r.ID())
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	deploys := make([]proto.Deploy, len(ms))
+	for i, m := range ms {
+		deploys[i] = proto.Deploy{Branch: m.Branch, Script: m.Script}
+	}
+
+	return deploys, nil
+}
+
+// SetDeploy binds a deploy script registered in the config to a branch of a
+// repository. Pushes to the branch run the script.
+func (d *Backend) SetDeploy(ctx context.Context, repo string, branch string, script string) error {
+	if _, ok := d.cfg.Deploy.Script(script); !ok {
+		return fmt.Errorf("%w: %q", ErrDeployScriptNotFound, script)
+	}
+
+	branch = strings.TrimPrefix(branch, git.RefsHeads)
+	if strings.TrimSpace(branch) == "" {
+		return fmt.Errorf("invalid branch %q", branch)
+	}
+
+	r, err := d.Repository(ctx, utils.SanitizeRepo(repo))
+	if err != nil {
+		return err
+	}
+
+	return db.WrapError(d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return d.store.SetDeploy(ctx, tx, r.ID(), branch, script)
+	}))
+}
+
+// DeleteDeploy unbinds the deploy script of a branch of a repository.
+func (d *Backend) DeleteDeploy(ctx context.Context, repo string, branch string) error {
+	r, err := d.Repository(ctx, utils.SanitizeRepo(repo))
+	if err != nil {
+		return err
+	}
+
+	branch = strings.TrimPrefix(branch, git.RefsHeads)
+	return db.WrapError(d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return d.store.DeleteDeploy(ctx, tx, r.ID(), branch)
+	}))
+}
+
+// RunDeploys runs the deploy scripts bound to the branches a push updated.
+// It's called by the git post-receive hook, after the references are
+// updated. Scripts run one after the other, and their output is written to w
+// and kept, along with their status, in the push events of the updates.
+func (d *Backend) RunDeploys(ctx context.Context, w io.Writer, repo string, args []hooks.HookArg) {
+	deploys, err := d.Deploys(ctx, repo)
+	if err != nil || len(deploys) == 0 {
+		if err != nil {
+			d.logger.Error("error getting deploys", "repo", repo, "err", err)
+		}
+		return
+	}
+
+	r, err := d.Repository(ctx, utils.SanitizeRepo(repo))
+	if err != nil {
+		d.logger.Error("error finding repository", "repo", repo, "err", err)
+		return
+	}
+
+	scripts := make(map[string]string, len(deploys))
+	for _, dp := range deploys {
+		scripts[dp.Branch] = dp.Script
+	}
+
+	for _, arg := range args {
+		if git.IsZeroHash(arg.NewSha) || !strings.HasPrefix(arg.RefName, git.RefsHeads) {
+			continue
+		}
+		name, ok := scripts[strings.TrimPrefix(arg.RefName, git.RefsHeads)]
+		if !ok {
+			continue
+		}
+		d.runDeploy(ctx, w, r, name, arg)
+	}
+}
+
+// runDeploy runs a deploy script for a reference update and records its
+// status and output.
+func (d *Backend) runDeploy(ctx context.Context, w io.Writer, r proto.Repository, name string, arg hooks.HookArg) {
+	record := func(status, output string) {
+		if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.SetPushEventDeploy(ctx, tx, r.ID(), arg.RefName, arg.NewSha, name, status, output)
+		}); err != nil {
+			d.logger.Error("error recording deploy", "repo", r.Name(), "script", name, "err", err)
+		}
+	}
+
+	branch := strings.TrimPrefix(arg.RefName, git.RefsHeads)
+	fmt.Fprintf(w, "Deploying %s with %s...\n", branch, name) // nolint: errcheck
+
+	cfg := d.cfg.Deploy
+	script, ok := cfg.Script(name)
+	if !ok {
+		msg := fmt.Sprintf("%s: %q\n", ErrDeployScriptNotFound, name)
+		fmt.Fprint(w, msg) // nolint: errcheck
+		record(proto.DeployFailed, msg)
+		return
+	}
+
+	record(proto.DeployRunning, "")
+
+	// Record the result with ctx, the script runs with runCtx.
+	runCtx, cancel := context.WithTimeout(ctx, time.Duration(cfg.Timeout)*time.Second)
+	defer cancel()
+
+	rp := filepath.Join(d.reposPath(), r.Name()+".git")
+	out := &tailBuffer{max: cfg.MaxOutput}
+	// Use the same writer for stdout and stderr to keep their order.
+	ow := io.MultiWriter(w, out)
+	cmd := deployCommand(runCtx, script.Path, cfg)
+	cmd.Dir = rp
+	cmd.Env = []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + os.Getenv("HOME"),
+		"SOFT_SERVE_REPO_NAME=" + r.Name(),
+		"SOFT_SERVE_REPO_PATH=" + rp,
+		"SOFT_SERVE_DEPLOY_SCRIPT=" + name,
+		"SOFT_SERVE_DEPLOY_REF=" + arg.RefName,
+		"SOFT_SERVE_DEPLOY_BRANCH=" + branch,
+		"SOFT_SERVE_DEPLOY_OLD_SHA=" + arg.OldSha,
+		"SOFT_SERVE_DEPLOY_NEW_SHA=" + arg.NewSha,
+	}
+	// Pass the push options on, e.g. to pick the environment to deploy to.
+	cmd.Env = append(cmd.Env, hooks.PushOptionsFromContext(ctx).Environ()...)
+	cmd.Stdout = ow
+	cmd.Stderr = ow
+	cmd.WaitDelay = deployWaitDelay
+
+	status := proto.DeploySucceeded
+	if err := cmd.Run(); errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		status = proto.DeployTimedOut
+		fmt.Fprintf(ow, "deploy timed out after %ds\n", cfg.Timeout) // nolint: errcheck
+	} else if err != nil {
+		status = proto.DeployFailed
+		fmt.Fprintf(ow, "deploy failed: %v\n", err) // nolint: errcheck
+	}
+
+	d.logger.Info("deploy finished", "repo", r.Name(), "branch", branch, "script", name, "status", status)
+	fmt.Fprintf(w, "Deploy %s\n", status) // nolint: errcheck
+	record(status, out.String())
+}
+
+// tailBuffer is a writer that keeps the last max bytes written to it.
+type tailBuffer struct {
+	buf []byte
+	max int
+}
+
+// Write implements io.Writer.
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if n := len(b.buf) - b.max; n > 0 {
+		b.buf = b.buf[:copy(b.buf, b.buf[n:])]
+	}
+	return len(p), nil
+}
+
+// String returns the bytes kept, without the partial character the buffer
+// may start with.
+func (b *tailBuffer) String() string {
+	s := b.buf
+	for len(s) > 0 && !utf8.RuneStart(s[0]) {
+		s = s[1:]
+	}
+	return string(s)
+}
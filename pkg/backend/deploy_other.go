@@ -0,0 +1,16 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+
+package backend
+
+import (
+	"context"
+	"os/exec"
+
+	"github.com/charmbracelet/soft-serve/pkg/config"
+)
+
+// deployCommand returns the command that runs a deploy script. Resource
+// limits aren't supported on this platform.
+func deployCommand(ctx context.Context, path string, _ config.DeployConfig) *exec.Cmd {
+	return exec.CommandContext(ctx, path)
+}
@@ -0,0 +1,32 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package backend
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"syscall"
+
+	"github.com/charmbracelet/soft-serve/pkg/config"
+)
+
+// deployCommand returns the command that runs a deploy script with the
+// resource limits of the config. The script runs in its own process group so
+// it's killed along with its children when the context is done.
+func deployCommand(ctx context.Context, path string, cfg config.DeployConfig) *exec.Cmd {
+	var limits string
+	if cfg.MaxCPU > 0 {
+		limits += fmt.Sprintf("ulimit -t %d && ", cfg.MaxCPU)
+	}
+	if cfg.MaxMemory > 0 {
+		limits += fmt.Sprintf("ulimit -v %d && ", cfg.MaxMemory*1024)
+	}
+
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", limits+`exec "$0"`, path)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	return cmd
+}
@@ -0,0 +1,255 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/storage"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// Change is a change a destructive operation makes. Dry runs return the
+// changes an operation would make without making them.
+type Change struct {
+	// Action is what happens, e.g. "delete", "move", or "run".
+	Action string
+	// Target is what it happens to: a repository, a reference, or a path
+	// relative to the data directory.
+	Target string
+	// Size is the number of bytes the change frees, if any.
+	Size int64
+	// Detail describes the change, e.g. where a path moves to.
+	Detail string
+}
+
+// DeleteRepositoryDryRun returns the changes DeleteRepository would make.
+func (d *Backend) DeleteRepositoryDryRun(ctx context.Context, name string) ([]Change, error) {
+	r, err := d.repoModel(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	collabs, err := d.Collaborators(ctx, r.name)
+	if err != nil {
+		return nil, err
+	}
+	hooks, err := d.ListWebhooks(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	changes := []Change{{
+		Action: "delete",
+		Target: r.name,
+		Detail: fmt.Sprintf("repository with %d collaborators and %d webhooks", len(collabs), len(hooks)),
+	}}
+
+	refs, err := d.refTargets(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range refs {
+		changes = append(changes, Change{Action: "delete", Target: ref[0], Detail: ref[1]})
+	}
+
+	forks, err := d.Forks(ctx, r.name)
+	if err != nil {
+		return nil, err
+	}
+	for _, fork := range forks {
+		changes = append(changes, Change{Action: "dissociate", Target: fork, Detail: "copy the objects the fork shares"})
+	}
+
+	size, err := storage.DirSize(ctx, r.path)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, Change{Action: "delete", Target: d.dataRel(r.path), Size: size, Detail: "repository directory"})
+
+	var objs []int64
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		lfsObjs, err := d.store.GetLFSObjectsByName(ctx, tx, r.name)
+		for _, obj := range lfsObjs {
+			objs = append(objs, obj.Size)
+		}
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+	if len(objs) > 0 {
+		var lfsSize int64
+		for _, s := range objs {
+			lfsSize += s
+		}
+		lfsPath := d.lfsPath(r.ID())
+		changes = append(changes, Change{Action: "delete", Target: d.dataRel(lfsPath), Size: lfsSize, Detail: fmt.Sprintf("%d LFS objects", len(objs))})
+	}
+
+	if fi, err := os.Stat(d.avatarFile(r.ID())); err == nil {
+		changes = append(changes, Change{Action: "delete", Target: d.dataRel(d.avatarFile(r.ID())), Size: fi.Size(), Detail: "avatar"})
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	return append(changes, d.notifyChange(len(hooks), "delete")...), nil
+}
+
+// RenameRepositoryDryRun returns the changes RenameRepository would make. It
+// fails like RenameRepository when the repository can't be renamed.
+func (d *Backend) RenameRepositoryDryRun(ctx context.Context, oldName string, newName string) ([]Change, error) {
+	oldName, newName, err := d.checkRename(ctx, oldName, newName)
+	if err != nil || oldName == newName {
+		return nil, err
+	}
+	r, err := d.repoModel(ctx, oldName)
+	if err != nil {
+		return nil, err
+	}
+	hooks, err := d.ListWebhooks(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	np := filepath.Join(d.reposPath(), newName+".git")
+	changes := []Change{
+		{Action: "rename", Target: oldName, Detail: newName},
+		{Action: "move", Target: d.dataRel(r.path), Detail: d.dataRel(np)},
+	}
+	forks, err := d.Forks(ctx, oldName)
+	if err != nil {
+		return nil, err
+	}
+	for _, fork := range forks {
+		changes = append(changes, Change{Action: "relink", Target: fork, Detail: "point the alternates to " + d.dataRel(np)})
+	}
+
+	return append(changes, d.notifyChange(len(hooks), "rename")...), nil
+}
+
+// HousekeepDryRun returns the changes Housekeep would make running the given
+// tasks on a repository. Tasks that would be skipped are reported as such.
+func (d *Backend) HousekeepDryRun(ctx context.Context, name string, tasks []string) ([]Change, error) {
+	name = utils.SanitizeRepo(name)
+	rp := filepath.Join(d.reposPath(), name+".git")
+	changes := make([]Change, 0, len(tasks))
+	for _, task := range tasks {
+		if skip := d.housekeepingSkip(ctx, name, task); skip != "" {
+			changes = append(changes, Change{Action: "skip", Target: task, Detail: skip})
+			continue
+		}
+
+		c := Change{Action: "run", Target: task}
+		var err error
+		switch task {
+		case config.HousekeepingFsck:
+			c.Detail = "verify the objects with git fsck"
+			if d.cfg.Housekeeping.Quarantine {
+				c.Detail += ", archive the repository if any is missing or corrupt"
+			}
+		case config.HousekeepingGC:
+			var loose, refs int
+			var pruned []string
+			if loose, _, err = d.looseObjects(rp); err != nil {
+				break
+			}
+			if !d.hasForks(ctx, name) {
+				if pruned, c.Size, err = d.prunableObjects(ctx, rp); err != nil {
+					break
+				}
+			}
+			if refs, err = git.LooseRefs(rp); err != nil {
+				break
+			}
+			c.Detail = fmt.Sprintf("pack %d loose objects, prune %d unreachable objects, pack %d references, repack all packs",
+				loose, len(pruned), refs)
+		case config.HousekeepingRepack:
+			var loose int
+			if loose, c.Size, err = d.looseObjects(rp); err == nil {
+				c.Detail = fmt.Sprintf("pack %d loose objects", loose)
+			}
+		case config.HousekeepingPrune:
+			var pruned []string
+			if pruned, c.Size, err = d.prunableObjects(ctx, rp); err == nil {
+				c.Detail = fmt.Sprintf("prune %d unreachable objects", len(pruned))
+			}
+		case config.HousekeepingPackRefs:
+			var refs int
+			if refs, err = git.LooseRefs(rp); err == nil {
+				c.Detail = fmt.Sprintf("pack %d references", refs)
+			}
+		case config.HousekeepingCommitGraph:
+			c.Detail = "write the commit-graph of the reachable commits"
+		default:
+			err = fmt.Errorf("unknown housekeeping task %q", task)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", task, err)
+		}
+		changes = append(changes, c)
+	}
+
+	return changes, nil
+}
+
+// looseObjects returns the number of loose objects of the repository at the
+// given path, and their size. Packing them frees at most that size.
+func (d *Backend) looseObjects(rp string) (int, int64, error) {
+	rr, err := git.Open(rp)
+	if err != nil {
+		return 0, 0, err
+	}
+	stats, err := rr.ObjectStats()
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(stats.Loose), stats.LooseSize, nil
+}
+
+// prunableObjects returns the objects git.Prune would remove from the
+// repository at the given path, and their size.
+func (d *Backend) prunableObjects(ctx context.Context, rp string) ([]string, int64, error) {
+	ids, err := git.PrunableObjects(ctx, rp)
+	if err != nil {
+		return nil, 0, err
+	}
+	var size int64
+	for _, id := range ids {
+		if len(id) < 3 {
+			continue
+		}
+		if fi, err := os.Stat(filepath.Join(rp, "objects", id[:2], id[2:])); err == nil {
+			size += fi.Size()
+		}
+	}
+	return ids, size, nil
+}
+
+// notifyChange returns the notification of the webhooks of a repository
+// about an event, if it has any.
+func (d *Backend) notifyChange(hooks int, action string) []Change {
+	if hooks == 0 {
+		return nil
+	}
+	return []Change{{
+		Action: "notify",
+		Target: "webhooks",
+		Detail: fmt.Sprintf("repository %s event to %d webhooks", action, hooks),
+	}}
+}
+
+// dataRel returns a path relative to the data directory, with forward
+// slashes.
+func (d *Backend) dataRel(p string) string {
+	rel, err := filepath.Rel(d.cfg.DataPath, p)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(p)
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,201 @@
+package backend
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+)
+
+// eventsPollInterval is how often new pushes are looked up while the event
+// stream has subscribers. Pushes are recorded by the Git hooks, which run in
+// their own processes.
+var eventsPollInterval = time.Second
+
+const (
+	// eventsBufferSize is the number of events buffered per subscriber.
+	// Events are dropped for subscribers that fall behind.
+	eventsBufferSize = 64
+
+	// eventsPageSize is the number of pushes looked up at once.
+	eventsPageSize = 100
+)
+
+// eventHub sends the events of all repositories to the subscribers of the
+// event stream.
+type eventHub struct {
+	mu       sync.Mutex
+	subs     map[chan proto.Event]struct{}
+	lastPush int64
+	cancel   context.CancelFunc
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{
+		subs: make(map[chan proto.Event]struct{}),
+	}
+}
+
+// send sends the event to the subscribers without blocking. It must be called
+// with the lock held.
+func (h *eventHub) send(ev proto.Event) {
+	for ch := range h.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// SubscribeEvents returns a channel of the events of all repositories, from
+// now on, until ctx is done. Callers must leave out the events of the
+// repositories the user can't access.
+func (d *Backend) SubscribeEvents(ctx context.Context) (<-chan proto.Event, error) {
+	h := d.events
+	ch := make(chan proto.Event, eventsBufferSize)
+
+	h.mu.Lock()
+	if len(h.subs) == 0 {
+		last, err := d.lastPushEventID(ctx)
+		if err != nil {
+			h.mu.Unlock()
+			return nil, err
+		}
+		h.lastPush = last
+		pctx, cancel := context.WithCancel(d.ctx)
+		h.cancel = cancel
+		go d.pollPushEvents(pctx)
+	}
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subs, ch)
+		if len(h.subs) == 0 && h.cancel != nil {
+			h.cancel()
+			h.cancel = nil
+		}
+		close(ch)
+	}()
+
+	return ch, nil
+}
+
+// publishEvent sends the event to the subscribers of the event stream. The
+// pushes recorded so far are sent first to keep the events in order.
+func (d *Backend) publishEvent(ev proto.Event) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+
+	h := d.events
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.subs) == 0 {
+		return
+	}
+
+	d.sendPushEvents(d.ctx)
+	h.send(ev)
+}
+
+// PublishRefUpdate publishes the update of a reference of a repository made
+// outside of a push, e.g. by deleting a branch.
+func (d *Backend) PublishRefUpdate(ctx context.Context, repo, ref, before, after string) {
+	var username string
+	if user := proto.UserFromContext(ctx); user != nil {
+		username = user.Username()
+	}
+
+	d.publishEvent(proto.Event{
+		Type:   proto.EventRefUpdate,
+		Repo:   repo,
+		Ref:    ref,
+		Before: before,
+		After:  after,
+		User:   username,
+	})
+}
+
+// publishRepoEvent publishes an event of a repository.
+func (d *Backend) publishRepoEvent(ctx context.Context, typ, repo, oldRepo string) {
+	var username string
+	if user := proto.UserFromContext(ctx); user != nil {
+		username = user.Username()
+	}
+
+	d.publishEvent(proto.Event{
+		Type:    typ,
+		Repo:    repo,
+		OldRepo: oldRepo,
+		User:    username,
+	})
+}
+
+// pollPushEvents sends the pushes recorded by the Git hooks until ctx is
+// done.
+func (d *Backend) pollPushEvents(ctx context.Context) {
+	ticker := time.NewTicker(eventsPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.events.mu.Lock()
+			d.sendPushEvents(ctx)
+			d.events.mu.Unlock()
+		}
+	}
+}
+
+// sendPushEvents sends the pushes recorded since the last one sent. It must
+// be called with the lock of the event hub held.
+func (d *Backend) sendPushEvents(ctx context.Context) {
+	h := d.events
+	for {
+		ms, err := d.store.GetPushEventsAfterID(ctx, d.db, h.lastPush, eventsPageSize)
+		if err != nil {
+			d.logger.Error("error getting push events", "err", err)
+			return
+		}
+
+		for _, m := range ms {
+			h.lastPush = m.ID
+			h.send(proto.Event{
+				Type:    proto.EventPush,
+				Repo:    m.RepoName,
+				Ref:     m.RefName,
+				Before:  m.OldSHA,
+				After:   m.NewSHA,
+				Commits: m.Commits,
+				Forced:  m.Forced,
+				User:    m.Username.String,
+				Time:    m.CreatedAt,
+			})
+		}
+
+		if len(ms) < eventsPageSize {
+			return
+		}
+	}
+}
+
+// lastPushEventID returns the ID of the last recorded push, zero if there is
+// none.
+func (d *Backend) lastPushEventID(ctx context.Context) (int64, error) {
+	ms, err := d.store.GetPushEvents(ctx, d.db, 1, 0)
+	if err != nil {
+		return 0, db.WrapError(err)
+	}
+	if len(ms) == 0 {
+		return 0, nil
+	}
+	return ms[0].ID, nil
+}
@@ -0,0 +1,29 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+)
+
+func TestEventHubSend(t *testing.T) {
+	h := newEventHub()
+	fast := make(chan proto.Event, 2)
+	slow := make(chan proto.Event, 1)
+	h.subs[fast] = struct{}{}
+	h.subs[slow] = struct{}{}
+
+	// Subscribers that fall behind don't block the others.
+	h.send(proto.Event{Type: proto.EventRepoCreate, Repo: "repo1"})
+	h.send(proto.Event{Type: proto.EventRepoDelete, Repo: "repo1"})
+
+	if len(fast) != 2 {
+		t.Errorf("fast subscriber got %d events, want 2", len(fast))
+	}
+	if len(slow) != 1 {
+		t.Errorf("slow subscriber got %d events, want 1", len(slow))
+	}
+	if ev := <-slow; ev.Type != proto.EventRepoCreate {
+		t.Errorf("slow subscriber got %q, want %q", ev.Type, proto.EventRepoCreate)
+	}
+}
@@ -0,0 +1,69 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/lfs"
+	"github.com/charmbracelet/soft-serve/pkg/storage"
+)
+
+// ExportRepository writes an archive of a tree-ish of a repository to w. When
+// lfsObjects is true, the LFS pointers in the archive are replaced with the
+// objects they point to, streamed from the LFS store. Pointers to objects the
+// server doesn't have are archived as they are.
+func (d *Backend) ExportRepository(ctx context.Context, name, treeish string, w io.Writer, opts git.ArchiveOptions, lfsObjects bool) error {
+	repo, err := d.Repository(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	r, err := repo.Open()
+	if err != nil {
+		return err
+	}
+
+	if lfsObjects {
+		strg := storage.NewLocalStorage(d.lfsPath(repo.ID()))
+		opts.Replace = func(name string, size int64, r io.Reader) (io.Reader, int64, error) {
+			return d.smudge(strg, repo.Name(), name, size, r)
+		}
+	}
+	return r.Archive(w, treeish, opts)
+}
+
+// smudge returns the LFS object the given file points to and its size if the
+// file is an LFS pointer, the file otherwise.
+func (d *Backend) smudge(strg *storage.LocalStorage, repo, name string, size int64, r io.Reader) (io.Reader, int64, error) {
+	if size > lfs.MaxPointerSize {
+		return r, size, nil
+	}
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	p, ok := lfs.ParsePointer(buf)
+	if !ok {
+		return bytes.NewReader(buf), size, nil
+	}
+
+	obj, err := strg.Open(path.Join("objects", p.RelativePath()))
+	if errors.Is(err, fs.ErrNotExist) {
+		d.logger.Warn("lfs object not found, archiving the pointer", "repo", repo, "path", name, "oid", p.Oid)
+		return bytes.NewReader(buf), size, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	fi, err := obj.Stat()
+	if err != nil {
+		obj.Close() // nolint: errcheck
+		return nil, 0, err
+	}
+	return obj, fi.Size(), nil
+}
@@ -0,0 +1,239 @@
+package backend
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/task"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// IsFollower returns whether the server is a read-only follower of a primary.
+func (d *Backend) IsFollower() bool {
+	return d.cfg.Follower.Primary != ""
+}
+
+// Primary returns the SSH URL of the primary the server follows, empty if it
+// isn't a follower.
+func (d *Backend) Primary() string {
+	return d.cfg.Follower.Primary
+}
+
+// PrimaryURL returns the URL of a repository on the primary.
+func (d *Backend) PrimaryURL(repo string) string {
+	repo = utils.SanitizeRepo(repo)
+	if repo == "" {
+		return d.cfg.Follower.Primary
+	}
+	return d.cfg.Follower.Primary + "/" + repo
+}
+
+// CheckFollower returns an error wrapping proto.ErrFollower, pointing at the
+// repository on the primary, if the server is a follower.
+func (d *Backend) CheckFollower(repo string) error {
+	if !d.IsFollower() {
+		return nil
+	}
+	return fmt.Errorf("%w, write to %s instead", proto.ErrFollower, d.PrimaryURL(repo))
+}
+
+// SyncFollower syncs a follower with its primary. The repositories of the
+// primary are imported as mirrors, the settings and the references of the
+// ones already imported are updated, and the ones deleted from the primary
+// are deleted. Repositories that aren't mirrors of the primary are left
+// alone.
+func (d *Backend) SyncFollower(ctx context.Context) error {
+	if !d.IsFollower() {
+		return nil
+	}
+
+	listings, err := d.primaryRepositories(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the repositories of the primary: %w", err)
+	}
+
+	repos, err := d.Repositories(ctx)
+	if err != nil {
+		return err
+	}
+	// The mirrors are owned by the initial admin, the user created with the
+	// database, and the changes of the sync are made on its behalf.
+	owner, err := d.UserByID(ctx, 1)
+	if err != nil {
+		return err
+	}
+	ctx = proto.WithUserContext(ctx, owner)
+
+	local := make(map[string]proto.Repository, len(repos))
+	for _, r := range repos {
+		local[r.Name()] = r
+	}
+
+	var errs []error
+	primary := make(map[string]bool, len(listings))
+	for _, l := range listings {
+		name := utils.SanitizeRepo(l.Name)
+		primary[name] = true
+		r, ok := local[name]
+		if !ok {
+			d.logger.Info("importing repository of the primary", "repo", name)
+			if _, err := d.ImportRepository(ctx, name, owner, d.PrimaryURL(name), proto.RepositoryOptions{
+				Private:     l.Private,
+				Description: l.Description,
+				ProjectName: l.ProjectName,
+				Hidden:      l.Hidden,
+				Mirror:      true,
+			}); err != nil && !errors.Is(err, task.ErrAlreadyStarted) {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+			continue
+		}
+		if !d.followsPrimary(ctx, r) {
+			d.logger.Warn("repository of the primary exists and isn't a mirror of it, skipping", "repo", name)
+			continue
+		}
+		if err := d.syncFollowerSettings(ctx, r, l); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+		if err := d.SyncMirror(ctx, name, "", io.Discard); err != nil && !errors.Is(err, task.ErrAlreadyStarted) {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	for name, r := range local {
+		if primary[name] || !d.followsPrimary(ctx, r) {
+			continue
+		}
+		d.logger.Info("deleting repository deleted from the primary", "repo", name)
+		if err := d.DeleteRepository(ctx, name); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// followsPrimary returns whether a repository is a mirror of the same
+// repository on the primary.
+func (d *Backend) followsPrimary(ctx context.Context, r proto.Repository) bool {
+	if !r.IsMirror() {
+		return false
+	}
+	remotes, err := d.MirrorRemotes(ctx, r.Name())
+	if err != nil {
+		return false
+	}
+	for _, rm := range remotes {
+		if rm.URL == d.PrimaryURL(r.Name()) {
+			return true
+		}
+	}
+	return false
+}
+
+// syncFollowerSettings updates the settings of a repository that changed on
+// the primary.
+func (d *Backend) syncFollowerSettings(ctx context.Context, r proto.Repository, l proto.RepositoryListing) error {
+	name := r.Name()
+	if r.IsPrivate() != l.Private {
+		if err := d.SetPrivate(ctx, name, l.Private); err != nil {
+			return err
+		}
+	}
+	if r.IsHidden() != l.Hidden {
+		if err := d.SetHidden(ctx, name, l.Hidden); err != nil {
+			return err
+		}
+	}
+	if r.Description() != l.Description {
+		if err := d.SetDescription(ctx, name, l.Description); err != nil {
+			return err
+		}
+	}
+	if r.ProjectName() != l.ProjectName {
+		if err := d.SetProjectName(ctx, name, l.ProjectName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// primaryRepositories lists the repositories of the primary with "repo list
+// --all --json". It connects like the git commands of mirrors do, with the
+// client key of the server.
+func (d *Backend) primaryRepositories(ctx context.Context) ([]proto.RepositoryListing, error) {
+	args, err := d.primarySSHArgs("repo", "list", "--all", "--json")
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "ssh", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	return parseRepositoryListings(&stdout)
+}
+
+// primarySSHArgs returns the arguments of ssh to run a command on the
+// primary.
+func (d *Backend) primarySSHArgs(command ...string) ([]string, error) {
+	u, err := url.Parse(d.cfg.Follower.Primary)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{
+		"-o", "UserKnownHostsFile=" + filepath.Join(d.cfg.DataPath, "ssh", "known_hosts"),
+		"-o", "StrictHostKeyChecking=no",
+		"-o", "BatchMode=yes",
+		"-i", d.cfg.SSH.ClientKeyPath,
+	}
+	if port := u.Port(); port != "" {
+		args = append(args, "-p", port)
+	}
+	host := u.Hostname()
+	if u.User != nil && u.User.Username() != "" {
+		host = u.User.Username() + "@" + host
+	}
+	args = append(args, "--", host)
+	return append(args, command...), nil
+}
+
+// parseRepositoryListings parses the output of "repo list --json", one
+// repository per line.
+func parseRepositoryListings(r io.Reader) ([]proto.RepositoryListing, error) {
+	var listings []proto.RepositoryListing
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+		var l proto.RepositoryListing
+		if err := json.Unmarshal([]byte(line), &l); err != nil {
+			return nil, fmt.Errorf("invalid repository %q: %w", line, err)
+		}
+		if l.Name == "" {
+			return nil, fmt.Errorf("invalid repository %q: missing name", line)
+		}
+		listings = append(listings, l)
+	}
+	return listings, s.Err()
+}
@@ -0,0 +1,73 @@
+package backend
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+)
+
+func TestCheckFollower(t *testing.T) {
+	d := &Backend{cfg: &config.Config{}}
+	if err := d.CheckFollower("repo1"); err != nil {
+		t.Fatalf("CheckFollower() error = %v, want nil", err)
+	}
+
+	d.cfg.Follower.Primary = "ssh://git.example.com:23231"
+	err := d.CheckFollower("/repo1.git")
+	if !errors.Is(err, proto.ErrFollower) {
+		t.Fatalf("CheckFollower() error = %v, want %v", err, proto.ErrFollower)
+	}
+	if want := "write to ssh://git.example.com:23231/repo1 instead"; !strings.Contains(err.Error(), want) {
+		t.Errorf("CheckFollower() error = %q, want it to contain %q", err, want)
+	}
+}
+
+func TestPrimarySSHArgs(t *testing.T) {
+	d := &Backend{cfg: &config.Config{DataPath: "/data"}}
+	d.cfg.SSH.ClientKeyPath = "/data/ssh/key"
+	d.cfg.Follower.Primary = "ssh://git@git.example.com:23231"
+	args, err := d.primarySSHArgs("repo", "list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"-o", "UserKnownHostsFile=/data/ssh/known_hosts",
+		"-o", "StrictHostKeyChecking=no",
+		"-o", "BatchMode=yes",
+		"-i", "/data/ssh/key",
+		"-p", "23231",
+		"--", "git@git.example.com",
+		"repo", "list",
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("primarySSHArgs() = %q, want %q", args, want)
+	}
+}
+
+func TestParseRepositoryListings(t *testing.T) {
+	in := `{"name":"repo1","description":"A repo","private":false,"hidden":false}
+
+{"name":"team/repo2","project_name":"Repo 2","private":true,"hidden":true}
+`
+	got, err := parseRepositoryListings(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []proto.RepositoryListing{
+		{Name: "repo1", Description: "A repo"},
+		{Name: "team/repo2", ProjectName: "Repo 2", Private: true, Hidden: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseRepositoryListings() = %+v, want %+v", got, want)
+	}
+
+	for _, in := range []string{"repo1\n", `{"private":true}`} {
+		if _, err := parseRepositoryListings(strings.NewReader(in)); err == nil {
+			t.Errorf("parseRepositoryListings(%q) error = nil, want an error", in)
+		}
+	}
+}
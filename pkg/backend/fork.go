@@ -0,0 +1,205 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/lfs"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/storage"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// ForkRepository creates a fork of a repository. The fork borrows the objects
+// of its parent instead of copying them, see git.Fork, and has references of
+// its own. Forks of private repositories are private, and they have the
+// description of their parent unless another one is given.
+func (d *Backend) ForkRepository(ctx context.Context, src string, name string, user proto.User, opts proto.RepositoryOptions) (proto.Repository, error) {
+	parent, err := d.Repository(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.Mirror = false
+	opts.Private = opts.Private || parent.IsPrivate()
+	if opts.Description == "" {
+		opts.Description = parent.Description()
+	}
+	r, err := d.CreateRepository(ctx, name, user, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.fork(ctx, parent, r); err != nil {
+		d.logger.Error("failed to fork repository", "parent", parent.Name(), "name", r.Name(), "err", err)
+		// Cleanup the mess!
+		if derr := d.DeleteRepository(ctx, r.Name()); derr != nil {
+			err = errors.Join(err, derr)
+		}
+		return nil, err
+	}
+
+	d.cache.Delete(r.Name())
+	return d.Repository(ctx, r.Name())
+}
+
+// fork makes the new repository r share the objects and get the references
+// of its parent, and copies the LFS objects of the parent.
+func (d *Backend) fork(ctx context.Context, parent, r proto.Repository) error {
+	pp := filepath.Join(d.reposPath(), parent.Name()+".git")
+	rp := filepath.Join(d.reposPath(), r.Name()+".git")
+	if err := d.WithRepository(ctx, parent.Name(), false, func() error {
+		return d.WithRepository(ctx, r.Name(), true, func() error {
+			return git.Fork(ctx, rp, pp)
+		})
+	}); err != nil {
+		return err
+	}
+
+	return db.WrapError(d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		if err := d.store.SetRepoParentByName(ctx, tx, r.Name(), parent.Name()); err != nil {
+			return err
+		}
+
+		objs, err := d.store.GetLFSObjects(ctx, tx, parent.ID())
+		if err != nil {
+			return err
+		}
+		for _, obj := range objs {
+			p := filepath.Join("objects", lfs.Pointer{Oid: obj.Oid, Size: obj.Size}.RelativePath())
+			if err := linkObject(d.lfsPath(parent.ID()), d.lfsPath(r.ID()), p); err != nil {
+				return err
+			}
+			if err := d.store.CreateLFSObject(ctx, tx, r.ID(), obj.Oid, obj.Size); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+// linkObject hard links an object from one storage to another, or copies it
+// when they're on different file systems.
+func linkObject(from, to, name string) error {
+	dst := filepath.Join(to, name)
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.Link(filepath.Join(from, name), dst); err == nil || errors.Is(err, os.ErrExist) {
+		return nil
+	}
+
+	obj, err := storage.NewLocalStorage(from).Open(name)
+	if err != nil {
+		return err
+	}
+	defer obj.Close() // nolint: errcheck
+	_, err = storage.NewLocalStorage(to).Put(name, obj)
+	return err
+}
+
+// lfsPath returns the path of the LFS objects of the repository with the
+// given ID.
+func (d *Backend) lfsPath(id int64) string {
+	return filepath.Join(d.cfg.DataPath, "lfs", strconv.FormatInt(id, 10))
+}
+
+// ForkParent returns the name of the repository a fork was forked from, empty
+// if the repository isn't a fork.
+func (d *Backend) ForkParent(ctx context.Context, name string) (string, error) {
+	name = utils.SanitizeRepo(name)
+	var parent string
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		parent, err = d.store.GetRepoParentByName(ctx, tx, name)
+		return err
+	}); err != nil {
+		return "", db.WrapError(err)
+	}
+
+	return parent, nil
+}
+
+// Forks returns the names of the forks of a repository.
+func (d *Backend) Forks(ctx context.Context, name string) ([]string, error) {
+	name = utils.SanitizeRepo(name)
+	var forks []string
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		forks, err = d.store.GetRepoForksByName(ctx, tx, name)
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	return forks, nil
+}
+
+// hasForks returns true if other repositories borrow the objects of the
+// repository. It's true on errors, it's used to keep objects that could be
+// needed.
+func (d *Backend) hasForks(ctx context.Context, name string) bool {
+	forks, err := d.Forks(ctx, name)
+	if err != nil {
+		d.logger.Error("failed to get forks", "repo", name, "err", err)
+		return true
+	}
+	return len(forks) > 0
+}
+
+// dissociateForks copies the objects the forks of a repository borrow from it
+// into them, before it's deleted. They aren't forks anymore afterwards.
+func (d *Backend) dissociateForks(ctx context.Context, name string) error {
+	forks, err := d.Forks(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	for _, fork := range forks {
+		fp := filepath.Join(d.reposPath(), fork+".git")
+		if err := d.WithRepository(ctx, fork, true, func() error {
+			return git.Dissociate(ctx, fp)
+		}); err != nil {
+			return err
+		}
+		if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.SetRepoParentByName(ctx, tx, fork, "")
+		}); err != nil {
+			return db.WrapError(err)
+		}
+		d.cache.Delete(fork)
+		d.logger.Info("dissociated fork", "repo", fork, "parent", name)
+	}
+	return nil
+}
+
+// relinkForks points the alternates of a renamed repository, and of its
+// forks, to the new paths.
+func (d *Backend) relinkForks(ctx context.Context, name string) error {
+	rp := filepath.Join(d.reposPath(), name+".git")
+	parent, err := d.ForkParent(ctx, name)
+	if err != nil {
+		return err
+	}
+	if parent != "" {
+		if err := git.SetAlternates(rp, filepath.Join(d.reposPath(), parent+".git", "objects")); err != nil {
+			return err
+		}
+	}
+
+	forks, err := d.Forks(ctx, name)
+	if err != nil {
+		return err
+	}
+	for _, fork := range forks {
+		if err := git.SetAlternates(filepath.Join(d.reposPath(), fork+".git"), filepath.Join(rp, "objects")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// ErrRepoCorrupt is returned when a repository has missing or corrupt objects.
+var ErrRepoCorrupt = errors.New("repository has missing or corrupt objects")
+
+// Fsck verifies the objects of a repository with git fsck, writing its output
+// to out as it goes. The run is logged and kept with the housekeeping runs, as
+// an fsck task. When objects are missing or corrupt, it fails with an error
+// wrapping ErrRepoCorrupt, and if quarantine is true, it archives the
+// repository first so that it's read-only, see SetArchived.
+func (d *Backend) Fsck(ctx context.Context, name string, out io.Writer, quarantine bool) (git.FsckResult, error) {
+	name = utils.SanitizeRepo(name)
+	if _, err := d.Repository(ctx, name); err != nil {
+		return git.FsckResult{}, err
+	}
+
+	r := HousekeepingRun{Repo: name, Task: config.HousekeepingFsck, StartedAt: time.Now()}
+	res, err := d.fsck(ctx, name, out, quarantine)
+	r.Duration = time.Since(r.StartedAt)
+	r.Err = err
+	d.recordHousekeeping(r)
+	return res, err
+}
+
+// fsck verifies the objects of a repository, and quarantines it if asked to
+// when they're broken.
+func (d *Backend) fsck(ctx context.Context, name string, out io.Writer, quarantine bool) (git.FsckResult, error) {
+	rp := filepath.Join(d.reposPath(), name+".git")
+	var res git.FsckResult
+	if err := d.WithRepository(ctx, name, false, func() error {
+		var err error
+		res, err = git.Fsck(ctx, rp, out)
+		return err
+	}); err != nil {
+		return res, err
+	}
+	if res.OK() {
+		return res, nil
+	}
+
+	err := fmt.Errorf("%w: %s", ErrRepoCorrupt, res)
+	if res.Missing == 0 && res.Corrupt == 0 {
+		err = fmt.Errorf("%w: git fsck failed", ErrRepoCorrupt)
+	}
+	if !quarantine {
+		return res, err
+	}
+
+	archived, aerr := d.IsArchived(ctx, name)
+	if aerr == nil && !archived {
+		aerr = d.SetArchived(ctx, name, true)
+	}
+	if aerr != nil {
+		return res, errors.Join(err, fmt.Errorf("quarantine: %w", aerr))
+	}
+	d.logger.WithPrefix("backend.fsck").Warn("repository quarantined", "repo", name, "objects", res.String())
+	return res, fmt.Errorf("%w, archived", err)
+}
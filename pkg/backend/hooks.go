@@ -18,15 +18,17 @@ var _ hooks.Hooks = (*Backend)(nil)
 // PostReceive is called by the git post-receive hook.
 //
 // It implements Hooks.
-func (d *Backend) PostReceive(_ context.Context, _ io.Writer, _ io.Writer, repo string, args []hooks.HookArg) {
-	d.logger.Debug("post-receive hook called", "repo", repo, "args", args)
+func (d *Backend) PostReceive(ctx context.Context, _ io.Writer, stderr io.Writer, repo string, args []hooks.HookArg) {
+	d.logger.Debug("post-receive hook called", "repo", repo, "args", args, "push-options", hooks.PushOptionsFromContext(ctx))
+
+	d.RunDeploys(ctx, stderr, repo, args)
 }
 
 // PreReceive is called by the git pre-receive hook.
 //
 // It implements Hooks.
-func (d *Backend) PreReceive(_ context.Context, _ io.Writer, _ io.Writer, repo string, args []hooks.HookArg) {
-	d.logger.Debug("pre-receive hook called", "repo", repo, "args", args)
+func (d *Backend) PreReceive(ctx context.Context, _ io.Writer, _ io.Writer, repo string, args []hooks.HookArg) {
+	d.logger.Debug("pre-receive hook called", "repo", repo, "args", args, "push-options", hooks.PushOptionsFromContext(ctx))
 }
 
 // Update is called by the git update hook.
@@ -84,6 +86,10 @@ func (d *Backend) Update(ctx context.Context, _ io.Writer, _ io.Writer, repo str
 	} else if err := webhook.SendEvent(ctx, wh); err != nil {
 		d.logger.Error("error sending push webhook", "err", err)
 	}
+
+	if err := d.RecordPush(ctx, user, r, arg.RefName, arg.OldSha, arg.NewSha); err != nil {
+		d.logger.Error("error recording push", "repo", repo, "err", err)
+	}
 }
 
 // PostUpdate is called by the git post-update hook.
@@ -0,0 +1,203 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// maxHousekeepingRuns is the number of housekeeping runs kept in memory.
+const maxHousekeepingRuns = 200
+
+// skippedPush is the reason housekeeping tasks are skipped while a repository
+// is being pushed to.
+const skippedPush = "push in progress"
+
+// skippedForks is the reason objects aren't pruned in repositories that have
+// forks, which may still need the unreachable objects.
+const skippedForks = "repository has forks"
+
+// HousekeepingRun is a run of a housekeeping task on a repository.
+type HousekeepingRun struct {
+	Repo      string
+	Task      string
+	StartedAt time.Time
+	Duration  time.Duration
+
+	// Skipped is the reason the task didn't run, if it didn't.
+	Skipped string
+
+	// Err is the error the task failed with.
+	Err error
+}
+
+// Status returns the outcome of the run, "ok", "skipped", or "failed", with
+// the reason.
+func (r HousekeepingRun) Status() string {
+	switch {
+	case r.Skipped != "":
+		return "skipped: " + r.Skipped
+	case r.Err != nil:
+		return "failed: " + r.Err.Error()
+	default:
+		return "ok"
+	}
+}
+
+// housekeeping keeps track of the pushes in progress, which housekeeping
+// skips, and of the recent housekeeping runs. Pushes are handled by the
+// server process, so both only live in memory.
+type housekeeping struct {
+	mu     sync.Mutex
+	pushes map[string]int
+	runs   []HousekeepingRun
+}
+
+func newHousekeeping() *housekeeping {
+	return &housekeeping{
+		pushes: make(map[string]int),
+	}
+}
+
+// record adds a run, dropping the oldest ones past maxHousekeepingRuns.
+func (h *housekeeping) record(r HousekeepingRun) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.runs = append(h.runs, r)
+	if n := len(h.runs) - maxHousekeepingRuns; n > 0 {
+		h.runs = append(h.runs[:0], h.runs[n:]...)
+	}
+}
+
+// StartPush marks the repository as being pushed to until the returned
+// function is called. Housekeeping skips the repositories that are being
+// pushed to.
+func (d *Backend) StartPush(name string) func() {
+	name = utils.SanitizeRepo(name)
+	h := d.housekeeping
+	h.mu.Lock()
+	h.pushes[name]++
+	h.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			if h.pushes[name]--; h.pushes[name] <= 0 {
+				delete(h.pushes, name)
+			}
+		})
+	}
+}
+
+// PushInProgress returns true if the repository is being pushed to.
+func (d *Backend) PushInProgress(name string) bool {
+	name = utils.SanitizeRepo(name)
+	h := d.housekeeping
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.pushes[name] > 0
+}
+
+// Housekeep runs housekeeping tasks on a repository in order, and returns
+// their runs. The tasks that would start while the repository is being
+// pushed to are skipped. Runs are logged and kept, see HousekeepingRuns.
+func (d *Backend) Housekeep(ctx context.Context, name string, tasks []string) []HousekeepingRun {
+	name = utils.SanitizeRepo(name)
+	runs := make([]HousekeepingRun, 0, len(tasks))
+	for _, task := range tasks {
+		r := HousekeepingRun{Repo: name, Task: task, StartedAt: time.Now()}
+		if r.Skipped = d.housekeepingSkip(ctx, name, task); r.Skipped == "" {
+			r.Err = d.runHousekeepingTask(ctx, name, task)
+			r.Duration = time.Since(r.StartedAt)
+		}
+		d.recordHousekeeping(r)
+		runs = append(runs, r)
+	}
+	return runs
+}
+
+// housekeepingSkip returns why a housekeeping task would be skipped on a
+// repository, empty if it wouldn't.
+func (d *Backend) housekeepingSkip(ctx context.Context, name, task string) string {
+	switch {
+	case d.PushInProgress(name):
+		return skippedPush
+	case task == config.HousekeepingPrune && d.hasForks(ctx, name):
+		return skippedForks
+	}
+	return ""
+}
+
+// HousekeepingRuns returns the recent housekeeping runs, the newest first.
+// The runs of all repositories are returned when name is empty.
+func (d *Backend) HousekeepingRuns(name string) []HousekeepingRun {
+	if name != "" {
+		name = utils.SanitizeRepo(name)
+	}
+	h := d.housekeeping
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	runs := make([]HousekeepingRun, 0, len(h.runs))
+	for i := len(h.runs) - 1; i >= 0; i-- {
+		if name == "" || h.runs[i].Repo == name {
+			runs = append(runs, h.runs[i])
+		}
+	}
+	return runs
+}
+
+// recordHousekeeping logs and keeps a housekeeping run.
+func (d *Backend) recordHousekeeping(r HousekeepingRun) {
+	logger := d.logger.WithPrefix("backend.housekeeping")
+	switch {
+	case r.Skipped != "":
+		logger.Info("housekeeping task skipped", "repo", r.Repo, "task", r.Task, "reason", r.Skipped)
+	case r.Err != nil:
+		logger.Error("housekeeping task failed", "repo", r.Repo, "task", r.Task, "duration", r.Duration, "err", r.Err)
+	default:
+		logger.Info("housekeeping task finished", "repo", r.Repo, "task", r.Task, "duration", r.Duration)
+	}
+	d.housekeeping.record(r)
+}
+
+// runHousekeepingTask runs a housekeeping task on a repository.
+func (d *Backend) runHousekeepingTask(ctx context.Context, name, task string) error {
+	if exists, err := d.repos.Exists(ctx, name); err != nil {
+		return err
+	} else if !exists {
+		return proto.ErrRepoNotFound
+	}
+
+	rp := filepath.Join(d.reposPath(), name+".git")
+	var run func() error
+	switch task {
+	case config.HousekeepingFsck:
+		// It only reads the repository, it doesn't need the write lock.
+		_, err := d.fsck(ctx, name, io.Discard, d.cfg.Housekeeping.Quarantine)
+		return err
+	case config.HousekeepingGC:
+		opts := git.GCOptions{Indexes: d.cfg.Repo.CommitGraph, NoPrune: d.hasForks(ctx, name)}
+		run = func() error { return git.GC(ctx, rp, opts) }
+	case config.HousekeepingRepack:
+		run = func() error { return git.Repack(ctx, rp) }
+	case config.HousekeepingPrune:
+		run = func() error { return git.Prune(ctx, rp) }
+	case config.HousekeepingPackRefs:
+		run = func() error { return git.PackRefs(ctx, rp) }
+	case config.HousekeepingCommitGraph:
+		run = func() error { return git.WriteCommitGraph(ctx, rp) }
+	default:
+		return fmt.Errorf("unknown housekeeping task %q", task)
+	}
+	return d.WithRepository(ctx, name, true, run)
+}
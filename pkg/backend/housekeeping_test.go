@@ -0,0 +1,63 @@
+package backend
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/storage"
+)
+
+func TestHousekeepingSkipsPushes(t *testing.T) {
+	d := &Backend{
+		cfg:          config.DefaultConfig(),
+		logger:       log.New(io.Discard),
+		housekeeping: newHousekeeping(),
+	}
+	d.cfg.DataPath = t.TempDir()
+	d.repos = storage.NewLocalRepoStorage(filepath.Join(d.cfg.DataPath, "repos"))
+
+	done := d.StartPush("repo1.git")
+	if !d.PushInProgress("repo1") {
+		t.Fatal("push not in progress")
+	}
+	runs := d.Housekeep(context.Background(), "repo1", []string{config.HousekeepingGC, config.HousekeepingPrune})
+	for _, r := range runs {
+		if r.Skipped != skippedPush {
+			t.Errorf("task %q ran during a push: %s", r.Task, r.Status())
+		}
+	}
+
+	done()
+	done()
+	if d.PushInProgress("repo1") {
+		t.Fatal("push still in progress")
+	}
+
+	// The repository doesn't exist, the task runs and fails.
+	runs = d.Housekeep(context.Background(), "repo1", []string{config.HousekeepingGC})
+	if runs[0].Err == nil {
+		t.Errorf("task %q didn't fail", runs[0].Task)
+	}
+
+	got := d.HousekeepingRuns("repo1")
+	if len(got) != 3 || got[0].Err == nil || got[2].Task != config.HousekeepingGC {
+		t.Errorf("unexpected runs %v", got)
+	}
+	if len(d.HousekeepingRuns("repo2")) != 0 {
+		t.Error("runs of another repository returned")
+	}
+}
+
+func TestHousekeepingRecord(t *testing.T) {
+	h := newHousekeeping()
+	for i := 0; i < maxHousekeepingRuns+10; i++ {
+		h.record(HousekeepingRun{Repo: "repo1"})
+	}
+	if len(h.runs) != maxHousekeepingRuns {
+		t.Errorf("kept %d runs, want %d", len(h.runs), maxHousekeepingRuns)
+	}
+}
@@ -0,0 +1,403 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"golang.org/x/crypto/ssh"
+	"gopkg.in/yaml.v3"
+)
+
+// Identity is the user a public key belongs to in an identity source.
+type Identity struct {
+	// Username is the username of the user.
+	Username string `yaml:"username"`
+
+	// Name is the display name of the user, empty to leave it as it is.
+	Name string `yaml:"name"`
+
+	// Teams are the names of the teams the user is a member of.
+	Teams []string `yaml:"teams"`
+}
+
+// IdentityResolver resolves public keys to the users they belong to when they
+// authenticate. Resolve returns proto.ErrUserNotFound for unknown keys.
+//
+// Sources like LDAP or an identity provider implement it and are set with
+// WithIdentityResolver.
+type IdentityResolver interface {
+	Resolve(ctx context.Context, pk ssh.PublicKey) (Identity, error)
+}
+
+// WithIdentityResolver sets the source of the users of public keys. The source
+// of the config is used by default.
+func WithIdentityResolver(r IdentityResolver) Option {
+	return func(b *Backend) {
+		b.identities = newIdentityCache(r, time.Duration(b.cfg.Identity.TTL)*time.Second)
+	}
+}
+
+// newIdentityResolver returns the identity resolver of the config.
+func newIdentityResolver(b *Backend) IdentityResolver {
+	if b.cfg.Identity.Source == config.IdentitySourceFile {
+		return NewFileIdentityResolver(b.cfg.Identity.File)
+	}
+	return storeIdentityResolver{b}
+}
+
+// storeIdentityResolver resolves public keys from the users of the server.
+type storeIdentityResolver struct {
+	d *Backend
+}
+
+// Resolve implements IdentityResolver.
+func (r storeIdentityResolver) Resolve(ctx context.Context, pk ssh.PublicKey) (Identity, error) {
+	u, err := r.d.userByStoredKey(ctx, pk)
+	if err != nil {
+		return Identity{}, err
+	}
+	teams, err := r.d.UserTeams(ctx, u)
+	if err != nil {
+		return Identity{}, err
+	}
+	return Identity{Username: u.Username(), Name: u.Name(), Teams: teams}, nil
+}
+
+// FileIdentityResolver resolves public keys from a YAML file listing the
+// users with their keys:
+//
+//	users:
+//	  - username: alice
+//	    name: Alice
+//	    teams: [devs]
+//	    keys: ["ssh-ed25519 AAAA..."]
+//
+// The file is read again when it changes.
+type FileIdentityResolver struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	keys    map[string]Identity
+}
+
+// NewFileIdentityResolver returns an identity resolver reading the file at
+// path.
+func NewFileIdentityResolver(path string) *FileIdentityResolver {
+	return &FileIdentityResolver{path: path}
+}
+
+// identityFile is the format of the file of a FileIdentityResolver.
+type identityFile struct {
+	Users []struct {
+		Identity `yaml:",inline"`
+		Keys     []string `yaml:"keys"`
+	} `yaml:"users"`
+}
+
+// Resolve implements IdentityResolver.
+func (r *FileIdentityResolver) Resolve(_ context.Context, pk ssh.PublicKey) (Identity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.load(); err != nil {
+		return Identity{}, err
+	}
+	id, ok := r.keys[ssh.FingerprintSHA256(pk)]
+	if !ok {
+		return Identity{}, proto.ErrUserNotFound
+	}
+	return id, nil
+}
+
+// load reads the file if it changed since it was last read.
+func (r *FileIdentityResolver) load() error {
+	fi, err := os.Stat(r.path)
+	if err != nil {
+		return err
+	}
+	if r.keys != nil && fi.ModTime().Equal(r.modTime) {
+		return nil
+	}
+
+	bts, err := os.ReadFile(r.path)
+	if err != nil {
+		return err
+	}
+	var f identityFile
+	if err := yaml.Unmarshal(bts, &f); err != nil {
+		return fmt.Errorf("invalid identities file %s: %w", r.path, err)
+	}
+
+	keys := make(map[string]Identity)
+	for i, u := range f.Users {
+		if u.Username == "" {
+			return fmt.Errorf("invalid identities file %s: user %d has no username", r.path, i+1)
+		}
+		for _, k := range u.Keys {
+			pk, _, err := sshutils.ParseAuthorizedKey(strings.TrimSpace(k))
+			if err != nil {
+				return fmt.Errorf("invalid identities file %s: invalid key of %s: %w", r.path, u.Username, err)
+			}
+			keys[ssh.FingerprintSHA256(pk)] = u.Identity
+		}
+	}
+
+	r.keys = keys
+	r.modTime = fi.ModTime()
+	return nil
+}
+
+// identityCache caches the identities of public keys for a time.
+type identityCache struct {
+	resolver IdentityResolver
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[string]identityEntry
+}
+
+// identityEntry is a cached identity, with proto.ErrUserNotFound for unknown
+// keys.
+type identityEntry struct {
+	id      Identity
+	err     error
+	expires time.Time
+}
+
+func newIdentityCache(r IdentityResolver, ttl time.Duration) *identityCache {
+	return &identityCache{
+		resolver: r,
+		ttl:      ttl,
+		entries:  make(map[string]identityEntry),
+	}
+}
+
+// resolve returns the identity of a public key, and whether it was resolved
+// from the source rather than the cache. Failures other than unknown keys
+// aren't cached.
+func (c *identityCache) resolve(ctx context.Context, pk ssh.PublicKey) (Identity, bool, error) {
+	fp := ssh.FingerprintSHA256(pk)
+	now := time.Now()
+
+	c.mu.Lock()
+	e, ok := c.entries[fp]
+	c.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		return e.id, false, e.err
+	}
+
+	id, err := c.resolver.Resolve(ctx, pk)
+	if err != nil && !errors.Is(err, proto.ErrUserNotFound) {
+		return Identity{}, true, err
+	}
+	if c.ttl > 0 {
+		c.mu.Lock()
+		c.entries[fp] = identityEntry{id: id, err: err, expires: now.Add(c.ttl)}
+		c.mu.Unlock()
+	}
+	return id, true, err
+}
+
+// syncIdentity creates or updates the user of a public key from the identity
+// source: the key is added to the user, created if needed, along with its
+// display name, and the team memberships from the source are made to match
+// it. Teams must exist already, unknown ones are skipped. The keys and
+// memberships added from the source are marked as synced, and are removed
+// once the source doesn't list them anymore. The ones added on the server
+// are left as they are.
+//
+// Nothing is synced from the store, which is the source of its own users, nor
+// for identities still cached. A synced key is rejected while the source
+// fails, since it may have been revoked.
+func (d *Backend) syncIdentity(ctx context.Context, pk ssh.PublicKey) error {
+	if _, ok := d.identities.resolver.(storeIdentityResolver); ok {
+		return nil
+	}
+
+	id, fresh, err := d.identities.resolve(ctx, pk)
+	switch {
+	case errors.Is(err, proto.ErrUserNotFound):
+		if !fresh {
+			return nil
+		}
+		return d.revokeIdentityKey(ctx, pk)
+	case err != nil:
+		synced, serr := d.isSyncedKey(ctx, pk)
+		if serr != nil {
+			return serr
+		}
+		if synced {
+			return err
+		}
+		d.logger.Error("error resolving identity", "pk", sshutils.MarshalAuthorizedKey(pk), "err", err)
+		return nil
+	case !fresh:
+		return nil
+	}
+
+	username := strings.ToLower(id.Username)
+	owner, err := d.userByStoredKey(ctx, pk)
+	switch {
+	case errors.Is(err, proto.ErrUserNotFound):
+		owner = nil
+	case err != nil:
+		return err
+	case owner.Username() != username:
+		synced, err := d.isSyncedKey(ctx, pk)
+		if err != nil {
+			return err
+		}
+		if !synced {
+			d.logger.Warn("public key of identity belongs to another user", "username", username, "user", owner.Username())
+			return nil
+		}
+		// The source moved the key to another user.
+		if err := d.revokeIdentityKey(ctx, pk); err != nil {
+			return err
+		}
+		owner = nil
+	}
+
+	u, err := d.User(ctx, username)
+	switch {
+	case errors.Is(err, proto.ErrUserNotFound):
+		u, err = d.CreateUser(ctx, username, proto.UserOptions{PublicKeys: []ssh.PublicKey{pk}})
+		if err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	case owner == nil:
+		if err := d.AddPublicKey(ctx, username, pk); err != nil {
+			return err
+		}
+	}
+	if owner == nil {
+		if err := db.WrapError(d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.SetPublicKeySyncedByUsername(ctx, tx, username, pk, true)
+		})); err != nil {
+			return err
+		}
+	}
+
+	if id.Name != "" && id.Name != u.Name() {
+		if err := d.SetName(ctx, username, id.Name); err != nil {
+			d.logger.Warn("error setting the name of identity", "username", username, "err", err)
+		}
+	}
+
+	want := make([]string, 0, len(id.Teams))
+	for _, t := range id.Teams {
+		want = append(want, strings.ToLower(t))
+	}
+	teams, err := d.UserTeams(ctx, u)
+	if err != nil {
+		return err
+	}
+	for _, t := range want {
+		if slices.Contains(teams, t) {
+			continue
+		}
+		if err := d.AddTeamMember(ctx, t, username); err != nil {
+			if errors.Is(err, proto.ErrTeamNotFound) {
+				d.logger.Debug("team of identity not found", "username", username, "team", t)
+				continue
+			}
+			return err
+		}
+		if err := db.WrapError(d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.SetTeamMemberSyncedByUsername(ctx, tx, t, username, true)
+		})); err != nil {
+			return err
+		}
+	}
+
+	synced, err := d.syncedTeams(ctx, u)
+	if err != nil {
+		return err
+	}
+	for _, t := range synced {
+		if slices.Contains(want, t) {
+			continue
+		}
+		if err := d.RemoveTeamMember(ctx, t, username); err != nil && !errors.Is(err, proto.ErrTeamMemberNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// revokeIdentityKey removes a public key the identity source doesn't list
+// anymore from its user, along with the team memberships from the source.
+// Keys added on the server are left as they are.
+func (d *Backend) revokeIdentityKey(ctx context.Context, pk ssh.PublicKey) error {
+	synced, err := d.isSyncedKey(ctx, pk)
+	if err != nil || !synced {
+		return err
+	}
+	u, err := d.userByStoredKey(ctx, pk)
+	if err != nil {
+		return err
+	}
+
+	teams, err := d.syncedTeams(ctx, u)
+	if err != nil {
+		return err
+	}
+	for _, t := range teams {
+		if err := d.RemoveTeamMember(ctx, t, u.Username()); err != nil && !errors.Is(err, proto.ErrTeamMemberNotFound) {
+			return err
+		}
+	}
+	if err := d.RemovePublicKey(ctx, u.Username(), pk); err != nil {
+		return err
+	}
+
+	d.logger.Info("revoked public key of identity", "username", u.Username(), "pk", ssh.FingerprintSHA256(pk))
+	return nil
+}
+
+// isSyncedKey returns whether a public key was added from the identity
+// source. Unknown keys aren't.
+func (d *Backend) isSyncedKey(ctx context.Context, pk ssh.PublicKey) (bool, error) {
+	var synced bool
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		synced, err = d.store.IsPublicKeySynced(ctx, tx, pk)
+		return err
+	}); err != nil {
+		err = db.WrapError(err)
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return synced, nil
+}
+
+// syncedTeams returns the names of the teams a user is a member of from the
+// identity source.
+func (d *Backend) syncedTeams(ctx context.Context, user proto.User) ([]string, error) {
+	var ms []models.Team
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		ms, err = d.store.ListSyncedTeamsByUserID(ctx, tx, user.ID())
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+	return teamNames(ms), nil
+}
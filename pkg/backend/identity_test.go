@@ -0,0 +1,221 @@
+package backend
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/migrate"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/store/database"
+	"golang.org/x/crypto/ssh"
+)
+
+func newTestKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pk
+}
+
+func TestFileIdentityResolver(t *testing.T) {
+	ctx := context.Background()
+	alice, bob := newTestKey(t), newTestKey(t)
+	path := filepath.Join(t.TempDir(), "identities.yaml")
+	write := func(content string, mtime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`users:
+  - username: alice
+    name: Alice
+    teams: [devs, ops]
+    keys: ["`+strings.TrimSpace(string(ssh.MarshalAuthorizedKey(alice)))+`"]
+`, time.Unix(1, 0))
+
+	r := NewFileIdentityResolver(path)
+	id, err := r.Resolve(ctx, alice)
+	if err != nil {
+		t.Fatalf("Resolve(alice) error = %v", err)
+	}
+	want := Identity{Username: "alice", Name: "Alice", Teams: []string{"devs", "ops"}}
+	if !reflect.DeepEqual(id, want) {
+		t.Errorf("Resolve(alice) = %+v, want %+v", id, want)
+	}
+	if _, err := r.Resolve(ctx, bob); !errors.Is(err, proto.ErrUserNotFound) {
+		t.Errorf("Resolve(bob) error = %v, want %v", err, proto.ErrUserNotFound)
+	}
+
+	// The file is read again when it changes.
+	write(`users:
+  - username: bob
+    keys: ["`+strings.TrimSpace(string(ssh.MarshalAuthorizedKey(bob)))+`"]
+`, time.Unix(2, 0))
+	if id, err := r.Resolve(ctx, bob); err != nil || id.Username != "bob" {
+		t.Errorf("Resolve(bob) = %+v, %v, want bob", id, err)
+	}
+	if _, err := r.Resolve(ctx, alice); !errors.Is(err, proto.ErrUserNotFound) {
+		t.Errorf("Resolve(alice) error = %v, want %v", err, proto.ErrUserNotFound)
+	}
+
+	write("users:\n  - keys: [\"ssh-ed25519 AAAA\"]\n", time.Unix(3, 0))
+	if _, err := r.Resolve(ctx, bob); err == nil {
+		t.Errorf("Resolve() error = nil for an invalid file")
+	}
+}
+
+type countingResolver struct {
+	calls int
+	err   error
+}
+
+func (r *countingResolver) Resolve(context.Context, ssh.PublicKey) (Identity, error) {
+	r.calls++
+	return Identity{Username: "alice"}, r.err
+}
+
+func TestIdentityCache(t *testing.T) {
+	ctx := context.Background()
+	pk := newTestKey(t)
+
+	r := &countingResolver{}
+	c := newIdentityCache(r, time.Minute)
+	if _, fresh, err := c.resolve(ctx, pk); err != nil || !fresh {
+		t.Errorf("resolve() = %v, %v, want fresh", fresh, err)
+	}
+	if id, fresh, err := c.resolve(ctx, pk); err != nil || fresh || id.Username != "alice" {
+		t.Errorf("resolve() = %+v, %v, %v, want cached alice", id, fresh, err)
+	}
+	if r.calls != 1 {
+		t.Errorf("resolver called %d times, want 1", r.calls)
+	}
+
+	// Failures aren't cached.
+	r = &countingResolver{err: errors.New("unreachable")}
+	c = newIdentityCache(r, time.Minute)
+	_, _, _ = c.resolve(ctx, pk)
+	_, _, _ = c.resolve(ctx, pk)
+	if r.calls != 2 {
+		t.Errorf("resolver called %d times, want 2", r.calls)
+	}
+
+	// Nothing is cached without a TTL.
+	r = &countingResolver{}
+	c = newIdentityCache(r, 0)
+	_, _, _ = c.resolve(ctx, pk)
+	if _, fresh, _ := c.resolve(ctx, pk); !fresh || r.calls != 2 {
+		t.Errorf("resolve() fresh = %v after %d calls, want fresh", fresh, r.calls)
+	}
+}
+
+// newIdentityBackend returns a backend resolving public keys from the
+// identities file at path.
+func newIdentityBackend(t *testing.T, path string, ttl time.Duration) *Backend {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.DataPath = t.TempDir()
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	ctx := config.WithContext(context.Background(), cfg)
+	dbx, err := db.Open(ctx, cfg.DB.Driver, cfg.DB.DataSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = dbx.Close() })
+	if err := migrate.Migrate(ctx, dbx); err != nil {
+		t.Fatal(err)
+	}
+	return New(ctx, cfg, dbx, database.New(ctx, dbx), func(b *Backend) {
+		b.identities = newIdentityCache(NewFileIdentityResolver(path), ttl)
+	})
+}
+
+func TestSyncIdentityRevoked(t *testing.T) {
+	ctx := context.Background()
+	alice, bob := newTestKey(t), newTestKey(t)
+	path := filepath.Join(t.TempDir(), "identities.yaml")
+	identities := `users:
+  - username: alice
+    teams: [devs]
+    keys: ["` + strings.TrimSpace(string(ssh.MarshalAuthorizedKey(alice))) + `"]
+`
+	if err := os.WriteFile(path, []byte(identities), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ttl := 100 * time.Millisecond
+	d := newIdentityBackend(t, path, ttl)
+	if err := d.CreateTeam(ctx, "devs"); err != nil {
+		t.Fatal(err)
+	}
+	// A key added on the server isn't revoked.
+	owner, err := d.CreateUser(ctx, "bob", proto.UserOptions{PublicKeys: []ssh.PublicKey{bob}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.CreateRepository(ctx, "repo1", owner, proto.RepositoryOptions{Private: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.AddTeamRepo(ctx, "devs", "repo1", access.ReadOnlyAccess); err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := d.UserByPublicKey(ctx, alice)
+	if err != nil {
+		t.Fatalf("UserByPublicKey(alice) error = %v", err)
+	}
+	if u.Username() != "alice" {
+		t.Errorf("UserByPublicKey(alice) = %s, want alice", u.Username())
+	}
+	if level := d.AccessLevelByPublicKey(ctx, "repo1", alice); level != access.ReadOnlyAccess {
+		t.Errorf("AccessLevelByPublicKey(alice) = %s, want %s", level, access.ReadOnlyAccess)
+	}
+
+	// Remove alice from the file, her access lasts until the TTL expires.
+	if err := os.WriteFile(path, []byte("users: []\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, time.Now().Add(time.Second), time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * ttl)
+
+	if _, err := d.UserByPublicKey(ctx, alice); !errors.Is(err, proto.ErrUserNotFound) {
+		t.Errorf("UserByPublicKey(alice) error = %v, want %v", err, proto.ErrUserNotFound)
+	}
+	if level := d.AccessLevelByPublicKey(ctx, "repo1", alice); level != access.NoAccess {
+		t.Errorf("AccessLevelByPublicKey(alice) = %s, want %s", level, access.NoAccess)
+	}
+	u, err = d.User(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if teams, err := d.UserTeams(ctx, u); err != nil || len(teams) != 0 {
+		t.Errorf("UserTeams(alice) = %v, %v, want none", teams, err)
+	}
+	if u, err := d.UserByPublicKey(ctx, bob); err != nil || u.Username() != "bob" {
+		t.Errorf("UserByPublicKey(bob) = %v, %v, want bob", u, err)
+	}
+}
@@ -0,0 +1,348 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/webhook"
+	"golang.org/x/crypto/ssh"
+)
+
+// MetaVersion is the version of the format of exported repository metadata.
+const MetaVersion = 1
+
+// Meta is the metadata of repositories, the settings Soft Serve keeps outside
+// of the Git repositories, as exported for backups and migrations between
+// servers.
+type Meta struct {
+	Version int        `json:"version"`
+	Repos   []RepoMeta `json:"repos"`
+}
+
+// RepoMeta is the metadata of a repository.
+type RepoMeta struct {
+	Name          string        `json:"name"`
+	ProjectName   string        `json:"project_name,omitempty"`
+	Description   string        `json:"description,omitempty"`
+	Private       bool          `json:"private"`
+	Hidden        bool          `json:"hidden"`
+	Owner         *UserMeta     `json:"owner,omitempty"`
+	Collaborators []CollabMeta  `json:"collaborators,omitempty"`
+	Webhooks      []WebhookMeta `json:"webhooks,omitempty"`
+}
+
+// UserMeta is a user in repository metadata. Users are matched by the
+// fingerprints of their public keys on import since usernames may differ
+// between servers, and by username only when they have no keys.
+type UserMeta struct {
+	Username string   `json:"username"`
+	Keys     []string `json:"keys,omitempty"`
+}
+
+// CollabMeta is a collaborator of a repository and its access level.
+type CollabMeta struct {
+	User   UserMeta           `json:"user"`
+	Access access.AccessLevel `json:"access"`
+}
+
+// WebhookMeta is a webhook of a repository, secret included.
+type WebhookMeta struct {
+	URL         string              `json:"url"`
+	ContentType webhook.ContentType `json:"content_type"`
+	Secret      string              `json:"secret,omitempty"`
+	Events      []webhook.Event     `json:"events"`
+	Active      bool                `json:"active"`
+}
+
+// MetaConflict is a setting of a repository that isn't imported because the
+// repository already has a different value.
+type MetaConflict struct {
+	Repo   string
+	Reason string
+}
+
+// String returns the repository and the reason of the conflict.
+func (c MetaConflict) String() string {
+	return c.Repo + ": " + c.Reason
+}
+
+// ExportRepoMeta returns the metadata of a repository.
+func (d *Backend) ExportRepoMeta(ctx context.Context, name string) (RepoMeta, error) {
+	r, err := d.Repository(ctx, name)
+	if err != nil {
+		return RepoMeta{}, err
+	}
+
+	m := RepoMeta{
+		Name:        r.Name(),
+		ProjectName: r.ProjectName(),
+		Description: r.Description(),
+		Private:     r.IsPrivate(),
+		Hidden:      r.IsHidden(),
+	}
+
+	if id := r.UserID(); id > 0 {
+		owner, err := d.UserByID(ctx, id)
+		switch {
+		case err == nil:
+			um, err := d.userMeta(ctx, owner.Username())
+			if err != nil {
+				return RepoMeta{}, err
+			}
+			m.Owner = &um
+		case !errors.Is(err, proto.ErrUserNotFound):
+			return RepoMeta{}, err
+		}
+	}
+
+	collabs, err := d.Collaborators(ctx, r.Name())
+	if err != nil {
+		return RepoMeta{}, err
+	}
+	for _, username := range collabs {
+		level, _, err := d.IsCollaborator(ctx, r.Name(), username)
+		if err != nil {
+			return RepoMeta{}, err
+		}
+		um, err := d.userMeta(ctx, username)
+		if err != nil {
+			return RepoMeta{}, err
+		}
+		m.Collaborators = append(m.Collaborators, CollabMeta{User: um, Access: level})
+	}
+
+	hooks, err := d.ListWebhooks(ctx, r)
+	if err != nil {
+		return RepoMeta{}, err
+	}
+	for _, h := range hooks {
+		m.Webhooks = append(m.Webhooks, WebhookMeta{
+			URL:         h.URL,
+			ContentType: h.ContentType,
+			Secret:      h.Secret,
+			Events:      h.Events,
+			Active:      h.Active,
+		})
+	}
+
+	return m, nil
+}
+
+// userMeta returns the user with the fingerprints of their public keys.
+func (d *Backend) userMeta(ctx context.Context, username string) (UserMeta, error) {
+	pks, err := d.ListPublicKeys(ctx, username)
+	if err != nil {
+		return UserMeta{}, err
+	}
+	um := UserMeta{Username: username}
+	for _, pk := range pks {
+		um.Keys = append(um.Keys, ssh.FingerprintSHA256(pk))
+	}
+	slices.Sort(um.Keys)
+	return um, nil
+}
+
+// ImportMeta restores the metadata of repositories. The repositories must
+// exist already. Settings the repositories don't have yet are set, and the
+// ones they have with a different value are returned as conflicts and left
+// as they are.
+func (d *Backend) ImportMeta(ctx context.Context, meta Meta) ([]MetaConflict, error) {
+	if meta.Version != MetaVersion {
+		return nil, fmt.Errorf("unsupported metadata version %d", meta.Version)
+	}
+
+	users, err := d.usersByFingerprint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := make([]MetaConflict, 0)
+	for _, m := range meta.Repos {
+		reasons, err := d.importRepoMeta(ctx, m, users)
+		if err != nil {
+			return conflicts, fmt.Errorf("%s: %w", m.Name, err)
+		}
+		for _, reason := range reasons {
+			conflicts = append(conflicts, MetaConflict{Repo: m.Name, Reason: reason})
+		}
+	}
+	return conflicts, nil
+}
+
+// importRepoMeta restores the metadata of a repository, and returns the
+// reasons of its conflicts.
+func (d *Backend) importRepoMeta(ctx context.Context, m RepoMeta, users map[string]string) ([]string, error) {
+	r, err := d.Repository(ctx, m.Name)
+	if errors.Is(err, proto.ErrRepoNotFound) {
+		return []string{"repository not found, create or import it first"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := make([]string, 0)
+	importField := func(set bool, conflict string, apply func() error) error {
+		if conflict != "" {
+			conflicts = append(conflicts, conflict)
+		}
+		if !set {
+			return nil
+		}
+		return apply()
+	}
+
+	name := r.Name()
+	set, conflict := importValue("project name", r.ProjectName(), m.ProjectName, "")
+	if err := importField(set, conflict, func() error {
+		return d.SetProjectName(ctx, name, m.ProjectName)
+	}); err != nil {
+		return nil, err
+	}
+	set, conflict = importValue("description", r.Description(), m.Description, "")
+	if err := importField(set, conflict, func() error {
+		return d.SetDescription(ctx, name, m.Description)
+	}); err != nil {
+		return nil, err
+	}
+	set, conflict = importValue("private", r.IsPrivate(), m.Private, false)
+	if err := importField(set, conflict, func() error {
+		return d.SetPrivate(ctx, name, m.Private)
+	}); err != nil {
+		return nil, err
+	}
+	set, conflict = importValue("hidden", r.IsHidden(), m.Hidden, false)
+	if err := importField(set, conflict, func() error {
+		return d.SetHidden(ctx, name, m.Hidden)
+	}); err != nil {
+		return nil, err
+	}
+
+	var owner proto.User
+	if m.Owner != nil {
+		owner = d.resolveUserMeta(ctx, *m.Owner, users)
+		switch {
+		case owner == nil:
+			conflicts = append(conflicts, fmt.Sprintf("owner %s not found", m.Owner.Username))
+		case r.UserID() == owner.ID():
+		case r.UserID() > 0:
+			conflicts = append(conflicts, fmt.Sprintf("owned by another user, not %s", owner.Username()))
+		default:
+			if _, err := d.TransferRepository(ctx, name, owner, access.NoAccess); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, c := range m.Collaborators {
+		u := d.resolveUserMeta(ctx, c.User, users)
+		if u == nil {
+			conflicts = append(conflicts, fmt.Sprintf("collaborator %s not found", c.User.Username))
+			continue
+		}
+		if owner != nil && u.ID() == owner.ID() {
+			continue
+		}
+		level, ok, _ := d.IsCollaborator(ctx, name, u.Username())
+		switch {
+		case !ok:
+			if err := d.AddCollaborator(ctx, name, u.Username(), c.Access); err != nil {
+				return nil, err
+			}
+		case level != c.Access:
+			conflicts = append(conflicts, fmt.Sprintf("collaborator %s has %s access, not %s", u.Username(), level, c.Access))
+		}
+	}
+
+	hooks, err := d.ListWebhooks(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	for _, wm := range m.Webhooks {
+		i := slices.IndexFunc(hooks, func(h webhook.Hook) bool {
+			return h.URL == wm.URL
+		})
+		switch {
+		case i < 0:
+			if err := d.CreateWebhook(ctx, r, wm.URL, wm.ContentType, wm.Secret, wm.Events, wm.Active); err != nil {
+				return nil, err
+			}
+		case !sameWebhook(hooks[i], wm):
+			conflicts = append(conflicts, fmt.Sprintf("webhook %s has different settings", wm.URL))
+		}
+	}
+
+	return conflicts, nil
+}
+
+// importValue returns whether an imported setting should be set, when the
+// repository still has the zero value, or the conflict when it has another
+// value.
+func importValue[T comparable](field string, cur, want, zero T) (bool, string) {
+	switch cur {
+	case want:
+		return false, ""
+	case zero:
+		return true, ""
+	}
+	return false, fmt.Sprintf("%s is %#v, not %#v", field, cur, want)
+}
+
+// sameWebhook returns whether the webhook has the settings of the imported
+// one.
+func sameWebhook(h webhook.Hook, wm WebhookMeta) bool {
+	if h.ContentType != wm.ContentType || h.Secret != wm.Secret || h.Active != wm.Active {
+		return false
+	}
+	events := slices.Clone(h.Events)
+	want := slices.Clone(wm.Events)
+	slices.Sort(events)
+	slices.Sort(want)
+	return slices.Equal(slices.Compact(events), slices.Compact(want))
+}
+
+// usersByFingerprint returns the usernames of the users keyed by the
+// fingerprints of their public keys.
+func (d *Backend) usersByFingerprint(ctx context.Context) (map[string]string, error) {
+	names, err := d.Users(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users := make(map[string]string)
+	for _, name := range names {
+		pks, err := d.ListPublicKeys(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, pk := range pks {
+			users[ssh.FingerprintSHA256(pk)] = name
+		}
+	}
+	return users, nil
+}
+
+// resolveUserMeta returns the user of the server an imported user is, nil if
+// there's none.
+func (d *Backend) resolveUserMeta(ctx context.Context, um UserMeta, users map[string]string) proto.User {
+	username := ""
+	if len(um.Keys) == 0 {
+		username = um.Username
+	}
+	for _, fp := range um.Keys {
+		if name, ok := users[fp]; ok {
+			username = name
+			break
+		}
+	}
+	if username == "" {
+		return nil
+	}
+	u, err := d.User(ctx, username)
+	if err != nil {
+		return nil
+	}
+	return u
+}
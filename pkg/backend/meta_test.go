@@ -0,0 +1,60 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/webhook"
+)
+
+func TestImportValue(t *testing.T) {
+	cases := []struct {
+		name     string
+		cur      string
+		want     string
+		set      bool
+		conflict string
+	}{
+		{"same", "docs", "docs", false, ""},
+		{"unset", "", "docs", true, ""},
+		{"different", "code", "docs", false, `description is "code", not "docs"`},
+		{"removed", "code", "", false, `description is "code", not ""`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			set, conflict := importValue("description", c.cur, c.want, "")
+			if set != c.set || conflict != c.conflict {
+				t.Errorf("importValue() = %v, %q, want %v, %q", set, conflict, c.set, c.conflict)
+			}
+		})
+	}
+
+	if set, conflict := importValue("private", false, true, false); !set || conflict != "" {
+		t.Errorf("importValue(false, true) = %v, %q, want true", set, conflict)
+	}
+	if _, conflict := importValue("private", true, false, false); conflict != "private is true, not false" {
+		t.Errorf("importValue(true, false) conflict = %q", conflict)
+	}
+}
+
+func TestSameWebhook(t *testing.T) {
+	h := webhook.Hook{
+		Webhook:     models.Webhook{URL: "https://example.com", Secret: "s", Active: true},
+		ContentType: webhook.ContentTypeJSON,
+		Events:      []webhook.Event{webhook.EventPush, webhook.EventBranchTagCreate},
+	}
+	wm := WebhookMeta{
+		URL:         "https://example.com",
+		ContentType: webhook.ContentTypeJSON,
+		Secret:      "s",
+		Events:      []webhook.Event{webhook.EventBranchTagCreate, webhook.EventPush},
+		Active:      true,
+	}
+	if !sameWebhook(h, wm) {
+		t.Errorf("sameWebhook() = false for events in another order")
+	}
+	wm.Secret = "t"
+	if sameWebhook(h, wm) {
+		t.Errorf("sameWebhook() = true for another secret")
+	}
+}
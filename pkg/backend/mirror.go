@@ -0,0 +1,249 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/lfs"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/task"
+)
+
+// remoteEnvs returns the environment of the git commands that talk to remotes.
+// SSH remotes are reached with the client key of the server, and git never
+// prompts for credentials.
+func (d *Backend) remoteEnvs() []string {
+	return []string{
+		fmt.Sprintf(`GIT_SSH_COMMAND=ssh -o UserKnownHostsFile="%s" -o StrictHostKeyChecking=no -i "%s"`,
+			filepath.Join(d.cfg.DataPath, "ssh", "known_hosts"),
+			d.cfg.SSH.ClientKeyPath,
+		),
+		"GIT_TERMINAL_PROMPT=0",
+	}
+}
+
+// MirrorRemotes returns the remotes a mirror is synced with. The passwords in
+// their URLs are redacted.
+func (d *Backend) MirrorRemotes(ctx context.Context, name string) ([]git.Remote, error) {
+	r, err := d.mirror(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	remotes, err := d.remotes(r)
+	if err != nil {
+		return nil, err
+	}
+	for i := range remotes {
+		remotes[i].URL = redactURL(remotes[i].URL)
+	}
+	return remotes, nil
+}
+
+// MirrorSync returns the state of the syncs of a mirror.
+func (d *Backend) MirrorSync(ctx context.Context, name string) (models.MirrorSync, error) {
+	r, err := d.mirror(ctx, name)
+	if err != nil {
+		return models.MirrorSync{}, err
+	}
+	return r.repo.MirrorSync, nil
+}
+
+// SyncMirror fetches a mirror from its remotes, or only from remote when it's
+// set, and writes the progress of git to progress. The references of a remote
+// are updated all at once, a failed sync keeps the last good state. The time
+// of the last successful sync and the error of the last sync are kept, see
+// MirrorSync. A successful sync is attested when attestations are enabled.
+// It returns task.ErrAlreadyStarted if the mirror is already being synced.
+func (d *Backend) SyncMirror(ctx context.Context, name, remote string, progress io.Writer) error {
+	r, err := d.mirror(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if _, loaded := d.mirrorSyncs.LoadOrStore(r.name, struct{}{}); loaded {
+		return task.ErrAlreadyStarted
+	}
+	defer d.mirrorSyncs.Delete(r.name)
+
+	remotes, err := d.remotes(r)
+	if err != nil {
+		return err
+	}
+	if remote != "" {
+		var found []git.Remote
+		for _, rm := range remotes {
+			if rm.Name == remote {
+				found = append(found, rm)
+			}
+		}
+		if len(found) == 0 {
+			return proto.ErrRemoteNotFound
+		}
+		remotes = found
+	}
+
+	var errs []error
+	if err := d.WithRepository(ctx, r.name, true, func() error {
+		for _, rm := range remotes {
+			fmt.Fprintf(progress, "Fetching %s\n", rm.Name) // nolint: errcheck
+			if err := d.fetchRemote(ctx, r.path, rm.Name, progress); err != nil {
+				d.logger.Error("failed to sync mirror", "repo", r.name, "remote", rm.Name, "err", err)
+				errs = append(errs, fmt.Errorf("%s: %w", rm.Name, err))
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	sync := r.repo.MirrorSync
+	if err := errors.Join(errs...); err != nil {
+		sync.Error = err.Error()
+	} else {
+		sync.SyncedAt = sql.NullTime{Time: time.Now(), Valid: true}
+		sync.Error = ""
+		if err := d.syncMirrorLFS(ctx, r); err != nil {
+			sync.Error = err.Error()
+		}
+	}
+
+	d.cache.Delete(r.name)
+	if err := db.WrapError(d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return d.store.SetRepoMirrorSyncByName(ctx, tx, r.name, sync)
+	})); err != nil {
+		return err
+	}
+
+	if sync.Error != "" {
+		return errors.New(sync.Error)
+	}
+
+	d.attestMirror(ctx, r.name)
+
+	return nil
+}
+
+// mirror returns a mirror with its database model.
+func (d *Backend) mirror(ctx context.Context, name string) (*repo, error) {
+	r, err := d.repoModel(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if !r.IsMirror() {
+		return nil, proto.ErrNotMirror
+	}
+	return r, nil
+}
+
+// remotes returns the remotes of a repository.
+func (d *Backend) remotes(r *repo) ([]git.Remote, error) {
+	rr, err := r.Open()
+	if err != nil {
+		return nil, err
+	}
+	return rr.Remotes()
+}
+
+// fetchRemote fetches a remote of the repository at the given path. The
+// error is the message of git, the reason the fetch failed.
+func (d *Backend) fetchRemote(ctx context.Context, path, remote string, progress io.Writer) error {
+	var stderr bytes.Buffer
+	cmd := git.NewCommand("fetch", "--prune", "--atomic", "--progress", "--", remote).
+		WithContext(ctx).WithTimeout(-1)
+	cmd.AddEnvs(d.remoteEnvs()...)
+	if err := cmd.RunInDirWithOptions(path, git.RunInDirOptions{
+		Stdout: progress,
+		Stderr: io.MultiWriter(progress, &stderr),
+	}); err != nil {
+		if msg := gitError(stderr.String()); msg != "" {
+			return errors.New(msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// syncMirrorLFS downloads the missing Git LFS objects of a mirror, when LFS
+// is enabled and the mirror has an LFS endpoint.
+func (d *Backend) syncMirrorLFS(ctx context.Context, r *repo) error {
+	if !d.cfg.LFS.Enabled {
+		return nil
+	}
+
+	rr, err := r.Open()
+	if err != nil {
+		return err
+	}
+	rcfg, err := rr.Config()
+	if err != nil {
+		return err
+	}
+
+	lfsEndpoint := rcfg.Section("lfs").Option("url")
+	if lfsEndpoint == "" {
+		// If there is no LFS url defined, means the repo doesn't use LFS and
+		// we can skip it.
+		return nil
+	}
+
+	ep, err := lfs.NewEndpoint(lfsEndpoint)
+	if err != nil {
+		return fmt.Errorf("invalid LFS endpoint: %w", err)
+	}
+
+	client := lfs.NewClient(ep)
+	if client == nil {
+		// Like on import, the objects of mirrors of SSH remotes aren't
+		// fetched, the LFS client only speaks HTTP.
+		d.logger.Debug("skipping lfs objects: unsupported endpoint", "repo", r.name, "endpoint", redactURL(lfsEndpoint))
+		return nil
+	}
+
+	if err := StoreRepoMissingLFSObjects(ctx, r, d.db, d.store, client); err != nil {
+		d.logger.Error("failed to store missing lfs objects", "repo", r.name, "err", err)
+		return fmt.Errorf("failed to store missing LFS objects: %w", err)
+	}
+
+	return nil
+}
+
+// redactURL returns the URL with its password redacted. URLs that can't be
+// parsed, like scp-like SSH addresses, are returned as they are.
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	return u.Redacted()
+}
+
+// gitError returns the reason of a failed git command from its output, the
+// first fatal or error message, or else the last line that isn't empty.
+// Progress lines end with a carriage return, they are lines too.
+func gitError(s string) string {
+	lines := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '\n' || r == '\r'
+	})
+	for _, l := range lines {
+		if strings.HasPrefix(l, "fatal:") || strings.HasPrefix(l, "error:") {
+			return strings.TrimSpace(l)
+		}
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		if l := strings.TrimSpace(lines[i]); l != "" {
+			return l
+		}
+	}
+	return ""
+}
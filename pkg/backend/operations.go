@@ -0,0 +1,252 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	operationsRunning = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "soft_serve",
+		Subsystem: "git",
+		Name:      "operations_running",
+		Help:      "The number of git operations running",
+	})
+
+	operationsQueued = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "soft_serve",
+		Subsystem: "git",
+		Name:      "operations_queued",
+		Help:      "The number of git operations waiting for others to finish",
+	})
+
+	operationsRejected = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "soft_serve",
+		Subsystem: "git",
+		Name:      "operations_rejected_total",
+		Help:      "The total number of git operations rejected because too many were running",
+	})
+)
+
+// ErrTooManyOperations is returned when a git operation can't start because
+// too many are running. The errors returned by StartOperation wrap it and
+// tell when to try again.
+var ErrTooManyOperations = errors.New("too many git operations")
+
+// OperationLimitError is returned when a git operation is rejected by the
+// concurrency limits.
+type OperationLimitError struct {
+	// Wait is the estimated time before the operation could start, zero if
+	// unknown.
+	Wait time.Duration
+}
+
+// Error implements error.
+func (e *OperationLimitError) Error() string {
+	if e.Wait <= 0 {
+		return "server busy: too many git operations, try again later"
+	}
+	return fmt.Sprintf("server busy: too many git operations, try again in about %s", roundWait(e.Wait))
+}
+
+// Unwrap returns ErrTooManyOperations.
+func (e *OperationLimitError) Unwrap() error {
+	return ErrTooManyOperations
+}
+
+// OperationStats is the utilization of the limits of git operations.
+type OperationStats struct {
+	// Running is the number of operations running, Queued the number waiting
+	// for others to finish.
+	Running int
+	Queued  int
+	// Max and PerRepo are the configured limits, zero means no limit.
+	Max     int
+	PerRepo int
+}
+
+// operationWaiter is a git operation waiting for others to finish.
+type operationWaiter struct {
+	repo  string
+	ready chan struct{}
+}
+
+// operations keeps track of the git operations running, by repository, and
+// of the ones waiting for them to finish, in order.
+type operations struct {
+	mu      sync.Mutex
+	running int
+	repos   map[string]int
+	queue   []*operationWaiter
+
+	// avg is the moving average of the duration of the operations, used to
+	// estimate the wait of the queued ones.
+	avg time.Duration
+}
+
+func newOperations() *operations {
+	return &operations{repos: make(map[string]int)}
+}
+
+// StartOperation starts a git operation, a fetch, clone, archive, or push, on
+// a repository under the concurrency limits of the config, and returns the
+// function to call when the operation is done. When too many operations are
+// running, it waits for up to the queue timeout for them to finish, calling
+// queued first with the number of operations queued ahead and the estimated
+// wait, then fails with an *OperationLimitError. Admins are never queued.
+func (d *Backend) StartOperation(ctx context.Context, repo string, user proto.User, queued func(ahead int, wait time.Duration)) (func(), error) {
+	repo = utils.SanitizeRepo(repo)
+	cfg := d.cfg.Repo.Concurrency
+	o := d.operations
+	start := time.Now()
+	done := func() {
+		o.finish(repo, time.Since(start), cfg.Max, cfg.PerRepo)
+	}
+
+	o.mu.Lock()
+	// The queued operations are held back by the limit of their repository,
+	// an operation that is allowed doesn't overtake them.
+	if (user != nil && user.IsAdmin()) || o.allowed(repo, cfg.Max, cfg.PerRepo) {
+		o.start(repo)
+		o.mu.Unlock()
+		return sync.OnceFunc(done), nil
+	}
+
+	ahead := len(o.queue)
+	wait := o.estimate(ahead, cfg.Max, cfg.PerRepo)
+	if cfg.QueueTimeout <= 0 {
+		o.mu.Unlock()
+		operationsRejected.Inc()
+		return nil, &OperationLimitError{Wait: wait}
+	}
+	w := &operationWaiter{repo: repo, ready: make(chan struct{})}
+	o.queue = append(o.queue, w)
+	operationsQueued.Inc()
+	o.mu.Unlock()
+
+	if queued != nil {
+		queued(ahead, wait)
+	}
+	timer := time.NewTimer(time.Duration(cfg.QueueTimeout) * time.Second)
+	defer timer.Stop()
+	select {
+	case <-w.ready:
+		start = time.Now()
+		return sync.OnceFunc(done), nil
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	o.mu.Lock()
+	select {
+	case <-w.ready:
+		// It was let in as it gave up, let the next one in instead.
+		o.mu.Unlock()
+		done()
+	default:
+		o.dequeue(w)
+		wait = o.estimate(len(o.queue), cfg.Max, cfg.PerRepo)
+		o.mu.Unlock()
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	operationsRejected.Inc()
+	return nil, &OperationLimitError{Wait: wait}
+}
+
+// OperationStats returns the utilization of the limits of git operations.
+func (d *Backend) OperationStats() OperationStats {
+	o := d.operations
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return OperationStats{
+		Running: o.running,
+		Queued:  len(o.queue),
+		Max:     d.cfg.Repo.Concurrency.Max,
+		PerRepo: d.cfg.Repo.Concurrency.PerRepo,
+	}
+}
+
+// allowed returns whether an operation on repo can start. The lock must be
+// held.
+func (o *operations) allowed(repo string, max, perRepo int) bool {
+	return (max <= 0 || o.running < max) && (perRepo <= 0 || o.repos[repo] < perRepo)
+}
+
+// start counts an operation on repo as running. The lock must be held.
+func (o *operations) start(repo string) {
+	o.running++
+	o.repos[repo]++
+	operationsRunning.Inc()
+}
+
+// dequeue removes a waiter from the queue. The lock must be held.
+func (o *operations) dequeue(w *operationWaiter) {
+	for i, q := range o.queue {
+		if q == w {
+			o.queue = append(o.queue[:i], o.queue[i+1:]...)
+			operationsQueued.Dec()
+			return
+		}
+	}
+}
+
+// finish counts an operation as done, and lets in the queued operations that
+// can start now, in order. The operations on a repository at its limit don't
+// hold back the others.
+func (o *operations) finish(repo string, took time.Duration, max, perRepo int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.running--
+	if o.repos[repo]--; o.repos[repo] <= 0 {
+		delete(o.repos, repo)
+	}
+	operationsRunning.Dec()
+	if o.avg == 0 {
+		o.avg = took
+	} else {
+		o.avg = (o.avg*4 + took) / 5
+	}
+
+	for i := 0; i < len(o.queue); {
+		w := o.queue[i]
+		if !o.allowed(w.repo, max, perRepo) {
+			i++
+			continue
+		}
+		o.queue = append(o.queue[:i], o.queue[i+1:]...)
+		operationsQueued.Dec()
+		o.start(w.repo)
+		close(w.ready)
+	}
+}
+
+// estimate returns the estimated wait of an operation queued behind ahead
+// others, zero until an operation finished. The lock must be held.
+func (o *operations) estimate(ahead, max, perRepo int) time.Duration {
+	if o.avg == 0 {
+		return 0
+	}
+	slots := max
+	if slots <= 0 {
+		slots = perRepo
+	}
+	return o.avg * time.Duration(ahead/slots+1)
+}
+
+// roundWait rounds an estimated wait for display.
+func roundWait(d time.Duration) time.Duration {
+	if d < time.Second {
+		return time.Second
+	}
+	return d.Round(time.Second)
+}
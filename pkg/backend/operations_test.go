@@ -0,0 +1,92 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+func TestOperationLimits(t *testing.T) {
+	d := &Backend{
+		cfg:        config.DefaultConfig(),
+		operations: newOperations(),
+	}
+	d.cfg.Repo.Concurrency = config.ConcurrencyConfig{Max: 2, PerRepo: 1}
+	ctx := context.Background()
+
+	done1, err := d.StartOperation(ctx, "repo1", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done2, err := d.StartOperation(ctx, "repo2.git", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Over the limits, operations are rejected right away without a queue.
+	if _, err := d.StartOperation(ctx, "repo3", nil, nil); !errors.Is(err, ErrTooManyOperations) {
+		t.Fatalf("expected too many operations, got %v", err)
+	}
+	done2()
+	done2()
+	if _, err := d.StartOperation(ctx, "repo1", nil, nil); !errors.Is(err, ErrTooManyOperations) {
+		t.Fatalf("expected too many operations on repo1, got %v", err)
+	}
+
+	// Admins are never held back.
+	admin := &user{user: models.User{Admin: true}}
+	done3, err := d.StartOperation(ctx, "repo1", admin, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := d.OperationStats(); st.Running != 2 || st.Queued != 0 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+	done3()
+
+	// Queued operations start in order once the others are done, and the
+	// estimated wait is the duration of the operations.
+	d.cfg.Repo.Concurrency.QueueTimeout = 10
+	started := make(chan error)
+	go func() {
+		done, err := d.StartOperation(ctx, "repo1", nil, func(ahead int, wait time.Duration) {
+			if ahead != 0 || wait <= 0 {
+				t.Errorf("unexpected queue position %d and wait %s", ahead, wait)
+			}
+			done1()
+		})
+		if done != nil {
+			done()
+		}
+		started <- err
+	}()
+	if err := <-started; err != nil {
+		t.Fatal(err)
+	}
+
+	// Queued operations give up after the timeout.
+	d.cfg.Repo.Concurrency.QueueTimeout = 1
+	done4, err := d.StartOperation(ctx, "repo1", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer done4()
+	start := time.Now()
+	var limit *OperationLimitError
+	if _, err := d.StartOperation(ctx, "repo1", nil, nil); !errors.As(err, &limit) {
+		t.Fatalf("expected an operation limit error, got %v", err)
+	}
+	if time.Since(start) < time.Second {
+		t.Error("gave up before the timeout")
+	}
+	if limit.Wait <= 0 {
+		t.Error("no estimated wait")
+	}
+	if st := d.OperationStats(); st.Running != 1 || st.Queued != 0 {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+}
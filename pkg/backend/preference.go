@@ -0,0 +1,63 @@
+package backend
+
+import (
+	"context"
+	"errors"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"golang.org/x/crypto/ssh"
+)
+
+// Preference returns a preference of a public key. It returns an empty string
+// if the preference isn't set.
+func (d *Backend) Preference(ctx context.Context, pk ssh.PublicKey, name string) (string, error) {
+	if pk == nil {
+		return "", nil
+	}
+
+	var value string
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		p, err := d.store.GetPreference(ctx, tx, sshutils.MarshalAuthorizedKey(pk), name)
+		if err != nil {
+			return err
+		}
+		value = p.Value
+		return nil
+	}); err != nil {
+		err = db.WrapError(err)
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return value, nil
+}
+
+// SetPreference creates or replaces a preference of a public key.
+func (d *Backend) SetPreference(ctx context.Context, pk ssh.PublicKey, name string, value string) error {
+	if pk == nil {
+		return proto.ErrUnauthorized
+	}
+
+	return db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.SetPreference(ctx, tx, sshutils.MarshalAuthorizedKey(pk), name, value)
+		}),
+	)
+}
+
+// DeletePreference deletes a preference of a public key.
+func (d *Backend) DeletePreference(ctx context.Context, pk ssh.PublicKey, name string) error {
+	if pk == nil {
+		return proto.ErrUnauthorized
+	}
+
+	return db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.DeletePreference(ctx, tx, sshutils.MarshalAuthorizedKey(pk), name)
+		}),
+	)
+}
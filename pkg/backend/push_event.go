@@ -0,0 +1,142 @@
+package backend
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+)
+
+// pushEventsPageSize is the number of push events fetched at once when
+// filtering the events a user can access.
+const pushEventsPageSize = 50
+
+// RecordPush records a reference update pushed to a repository. It must be
+// called before the reference is updated to count the new commits. Forced
+// updates, i.e. ones that aren't fast-forwards, are flagged and logged as a
+// warning.
+func (d *Backend) RecordPush(ctx context.Context, user proto.User, repo proto.Repository, ref, before, after string) error {
+	var commits int64
+	var forced bool
+	if !git.IsZeroHash(after) {
+		r, err := repo.Open()
+		if err != nil {
+			return err
+		}
+
+		// Count the commits that aren't reachable from any reference yet.
+		out, err := git.NewCommand("rev-list", "--count", after, "--not", "--all").RunInDir(r.Path)
+		if err != nil {
+			return err
+		}
+		commits, _ = strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+
+		if !git.IsZeroHash(before) {
+			forced, err = isForcedUpdate(r, before, after)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	var userID int64
+	var username string
+	if user != nil {
+		userID = user.ID()
+		username = user.Username()
+	}
+
+	if forced {
+		d.logger.Warn("forced update", "repo", repo.Name(), "ref", ref, "before", before, "after", after, "user", username)
+	}
+
+	return db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.CreatePushEvent(ctx, tx, repo.ID(), userID, ref, before, after, commits, forced)
+		}),
+	)
+}
+
+// isForcedUpdate returns true if updating a reference from before to after
+// drops commits from its history, i.e. before isn't an ancestor of after.
+func isForcedUpdate(r *git.Repository, before, after string) (bool, error) {
+	out, err := git.NewCommand("rev-list", "--count", "--max-count=1", before, "--not", after).RunInDir(r.Path)
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(string(out)) != "0", nil
+}
+
+// PushEvents returns up to limit push events, newest first, of the given
+// repository, or of all the repositories the user can read when repo is nil.
+// Push events of hidden repositories are only returned for the repository
+// itself. A non-positive limit returns all the events.
+func (d *Backend) PushEvents(ctx context.Context, user proto.User, repo proto.Repository, limit int) ([]proto.PushEvent, error) {
+	events := make([]proto.PushEvent, 0)
+	visible := make(map[string]bool)
+	for offset := 0; limit <= 0 || len(events) < limit; offset += pushEventsPageSize {
+		var ms []models.PushEvent
+		if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			var err error
+			if repo != nil {
+				ms, err = d.store.GetPushEventsByRepoID(ctx, tx, repo.ID(), pushEventsPageSize, offset)
+			} else {
+				ms, err = d.store.GetPushEvents(ctx, tx, pushEventsPageSize, offset)
+			}
+			return err
+		}); err != nil {
+			return nil, db.WrapError(err)
+		}
+
+		for _, m := range ms {
+			ok, seen := visible[m.RepoName]
+			if !seen {
+				ok = repo != nil || d.isPushEventVisible(ctx, user, m.RepoName)
+				visible[m.RepoName] = ok
+			}
+			if !ok {
+				continue
+			}
+			events = append(events, pushEventFromModel(m))
+			if limit > 0 && len(events) >= limit {
+				break
+			}
+		}
+
+		if len(ms) < pushEventsPageSize {
+			break
+		}
+	}
+
+	return events, nil
+}
+
+func (d *Backend) isPushEventVisible(ctx context.Context, user proto.User, name string) bool {
+	r, err := d.Repository(ctx, name)
+	if err != nil || r.IsHidden() {
+		return false
+	}
+	return d.AccessLevelForUser(ctx, name, user) >= access.ReadOnlyAccess
+}
+
+func pushEventFromModel(m models.PushEvent) proto.PushEvent {
+	return proto.PushEvent{
+		Repo:      m.RepoName,
+		Ref:       m.RefName,
+		Before:    m.OldSHA,
+		After:     m.NewSHA,
+		Commits:   m.Commits,
+		Forced:    m.Forced,
+		Pusher:    m.Username.String,
+		CreatedAt: m.CreatedAt,
+
+		DeployScript: m.DeployScript,
+		DeployStatus: m.DeployStatus,
+		DeployOutput: m.DeployOutput,
+	}
+}
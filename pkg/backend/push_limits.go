@@ -0,0 +1,105 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/hooks"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/dustin/go-humanize"
+)
+
+// PushLimits returns the limits of the objects pushed to a repository.
+func (d *Backend) PushLimits(ctx context.Context, name string) (models.PushLimits, error) {
+	name = utils.SanitizeRepo(name)
+	var limits models.PushLimits
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		limits, err = d.store.GetRepoPushLimitsByName(ctx, tx, name)
+		return err
+	}); err != nil {
+		return models.PushLimits{}, db.WrapError(err)
+	}
+
+	return limits, nil
+}
+
+// SetPushLimits sets the limits of the objects pushed to a repository.
+func (d *Backend) SetPushLimits(ctx context.Context, name string, limits models.PushLimits) error {
+	name = utils.SanitizeRepo(name)
+
+	// Delete cache
+	d.cache.Delete(name)
+
+	return db.WrapError(d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return d.store.SetRepoPushLimitsByName(ctx, tx, name, limits)
+	}))
+}
+
+// CheckPushLimits checks the objects of a push against the limits of the
+// repository. It's called by the git pre-receive hook, once the pushed pack
+// is indexed and before any reference is updated, and returns an error that
+// names the offending path.
+func (d *Backend) CheckPushLimits(ctx context.Context, repo string, args []hooks.HookArg) error {
+	limits, err := d.PushLimits(ctx, repo)
+	if err != nil {
+		return err
+	}
+	if limits.MaxBlobSize <= 0 && limits.MaxTreeDepth <= 0 {
+		return nil
+	}
+
+	revs := make([]string, 0, len(args))
+	for _, arg := range args {
+		if !git.IsZeroHash(arg.NewSha) {
+			revs = append(revs, arg.NewSha)
+		}
+	}
+	if len(revs) == 0 {
+		return nil
+	}
+
+	rr, err := d.Repository(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	r, err := rr.Open()
+	if err != nil {
+		return err
+	}
+
+	objects, err := r.NewObjects(revs...)
+	if err != nil {
+		return err
+	}
+
+	return checkPushLimits(limits, objects)
+}
+
+// checkPushLimits returns an error for the first object that exceeds the
+// limits.
+func checkPushLimits(limits models.PushLimits, objects []git.PathObject) error {
+	for _, obj := range objects {
+		switch obj.Type {
+		case "blob":
+			if limits.MaxBlobSize > 0 && obj.Size > limits.MaxBlobSize {
+				return fmt.Errorf("%s is %s, larger than the maximum file size of %s",
+					obj.Path, humanize.Bytes(uint64(obj.Size)), humanize.Bytes(uint64(limits.MaxBlobSize)))
+			}
+		case "tree":
+			if obj.Path == "" || limits.MaxTreeDepth <= 0 {
+				continue
+			}
+			if depth := strings.Count(obj.Path, "/") + 1; depth > limits.MaxTreeDepth {
+				return fmt.Errorf("%s is nested %d directories deep, more than the maximum of %d",
+					obj.Path, depth, limits.MaxTreeDepth)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package backend
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"github.com/charmbracelet/ssh"
+)
+
+// pushReportsBufferSize is the number of push reports buffered per
+// subscriber. Reports are dropped for subscribers that fall behind.
+const pushReportsBufferSize = 8
+
+// pushReports sends the reports of the pushes to the subscribers with the
+// same identity as the pusher, keyed by identity.
+type pushReports struct {
+	mu   sync.Mutex
+	subs map[string]map[chan proto.PushReport]struct{}
+}
+
+func newPushReports() *pushReports {
+	return &pushReports{
+		subs: make(map[string]map[chan proto.PushReport]struct{}),
+	}
+}
+
+// pushIdentity returns the identity pushes are matched to sessions by: the
+// user, or the public key of users without an account. It's empty for
+// anonymous users without a key.
+func pushIdentity(user proto.User, pk ssh.PublicKey) string {
+	switch {
+	case user != nil:
+		return "user:" + strconv.FormatInt(user.ID(), 10)
+	case pk != nil:
+		return "key:" + sshutils.MarshalAuthorizedKey(pk)
+	}
+	return ""
+}
+
+// SubscribePushReports returns a channel of the reports of the pushes made by
+// the given user, or public key for users without an account, from now on,
+// until ctx is done. The channel is nil when there's no identity to match
+// pushes to.
+func (d *Backend) SubscribePushReports(ctx context.Context, user proto.User, pk ssh.PublicKey) <-chan proto.PushReport {
+	id := pushIdentity(user, pk)
+	if id == "" {
+		return nil
+	}
+
+	p := d.pushReports
+	ch := make(chan proto.PushReport, pushReportsBufferSize)
+	p.mu.Lock()
+	if p.subs[id] == nil {
+		p.subs[id] = make(map[chan proto.PushReport]struct{})
+	}
+	p.subs[id][ch] = struct{}{}
+	p.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		delete(p.subs[id], ch)
+		if len(p.subs[id]) == 0 {
+			delete(p.subs, id)
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+// PublishPushReport sends the report of a push to the subscribers with the
+// identity of the pusher, without blocking.
+func (d *Backend) PublishPushReport(user proto.User, pk ssh.PublicKey, report proto.PushReport) {
+	id := pushIdentity(user, pk)
+	if id == "" {
+		return
+	}
+
+	p := d.pushReports
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for ch := range p.subs[id] {
+		select {
+		case ch <- report:
+		default:
+		}
+	}
+}
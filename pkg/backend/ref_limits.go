@@ -0,0 +1,69 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/hooks"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// CheckRefLimits checks the references a push creates against the reference
+// limits of the server. It's called by the git pre-receive hook before any
+// reference is updated. Updates and deletions are always allowed, so
+// repositories already over the limits keep working.
+func (d *Backend) CheckRefLimits(ctx context.Context, repo string, args []hooks.HookArg) error {
+	limits := d.cfg.Repo.Refs
+	if limits.MaxCount <= 0 && limits.MaxNameLength <= 0 {
+		return nil
+	}
+
+	count := 0
+	if limits.MaxCount > 0 {
+		rr, err := d.Repository(ctx, utils.SanitizeRepo(repo))
+		if err != nil {
+			return err
+		}
+
+		r, err := rr.Open()
+		if err != nil {
+			return err
+		}
+
+		count, err = r.CountReferences()
+		if err != nil {
+			return err
+		}
+	}
+
+	return checkRefLimits(limits, count, args)
+}
+
+// checkRefLimits returns an error for the first reference the push creates
+// with a name that's too long, or when the push leaves the repository with
+// more references than the limit and more than it had before.
+func checkRefLimits(limits config.RefsConfig, count int, args []hooks.HookArg) error {
+	created, deleted := 0, 0
+	for _, arg := range args {
+		switch {
+		case git.IsZeroHash(arg.OldSha) && !git.IsZeroHash(arg.NewSha):
+			if limits.MaxNameLength > 0 && len(arg.RefName) > limits.MaxNameLength {
+				return fmt.Errorf("reference name %s is %d bytes long, longer than the maximum of %d",
+					arg.RefName, len(arg.RefName), limits.MaxNameLength)
+			}
+			created++
+		case git.IsZeroHash(arg.NewSha):
+			deleted++
+		}
+	}
+
+	if limits.MaxCount > 0 && created > deleted {
+		if after := count + created - deleted; after > limits.MaxCount {
+			return fmt.Errorf("the push would leave the repository with %d references, more than the maximum of %d",
+				after, limits.MaxCount)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/hooks"
+)
+
+func TestCheckRefLimits(t *testing.T) {
+	const (
+		zero = "0000000000000000000000000000000000000000"
+		a    = "0123456789abcdef0123456789abcdef01234567"
+		b    = "89abcdef0123456789abcdef0123456789abcdef"
+	)
+	create := func(name string) hooks.HookArg { return hooks.HookArg{OldSha: zero, NewSha: a, RefName: name} }
+	update := func(name string) hooks.HookArg { return hooks.HookArg{OldSha: a, NewSha: b, RefName: name} }
+	remove := func(name string) hooks.HookArg { return hooks.HookArg{OldSha: a, NewSha: zero, RefName: name} }
+	limits := config.RefsConfig{MaxCount: 3, MaxNameLength: 20}
+
+	cases := []struct {
+		name  string
+		count int
+		args  []hooks.HookArg
+		err   string
+	}{
+		{"under the limit", 1, []hooks.HookArg{create("refs/heads/a"), create("refs/heads/b")}, ""},
+		{"over the limit", 2, []hooks.HookArg{create("refs/heads/a"), create("refs/heads/b")}, "the repository with 4 references, more than the maximum of 3"},
+		{"updates over the limit", 5, []hooks.HookArg{update("refs/heads/main")}, ""},
+		{"replacing over the limit", 5, []hooks.HookArg{remove("refs/heads/a"), create("refs/heads/b")}, ""},
+		{"adding over the limit", 5, []hooks.HookArg{create("refs/heads/b")}, "the repository with 6 references"},
+		{"long name", 0, []hooks.HookArg{create("refs/heads/a-very-long-name")}, "reference name refs/heads/a-very-long-name is 27 bytes long"},
+		{"long existing name", 0, []hooks.HookArg{update("refs/heads/a-very-long-name")}, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := checkRefLimits(limits, c.count, c.args)
+			switch {
+			case c.err == "" && err != nil:
+				t.Errorf("checkRefLimits() error = %v, want nil", err)
+			case c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)):
+				t.Errorf("checkRefLimits() error = %v, want %q", err, c.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package backend
+
+import (
+	"context"
+	"errors"
+
+	"github.com/charmbracelet/soft-serve/git"
+)
+
+// ErrReflogNoDiff is returned when a reflog entry has no diff because the
+// reference was created or deleted.
+var ErrReflogNoDiff = errors.New("the reference was created or deleted, there's nothing to compare")
+
+// ReflogEntry is an update of a reference of a repository recorded in its
+// reflog.
+type ReflogEntry struct {
+	git.ReflogEntry
+
+	// Forced is true if the update dropped commits from the history of the
+	// reference, like a force push.
+	Forced bool
+}
+
+// ReflogRefs returns the references of a repository that have a reflog.
+// References have a reflog once core.logAllRefUpdates is set in the Git
+// configuration of the repository.
+func (d *Backend) ReflogRefs(ctx context.Context, name string) ([]string, error) {
+	r, err := d.openRepo(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return r.ReflogRefs()
+}
+
+// Reflog returns the full name of the reference of a repository that ref
+// names, and the entries of its reflog, the latest first.
+func (d *Backend) Reflog(ctx context.Context, name, ref string) (string, []ReflogEntry, error) {
+	r, err := d.openRepo(ctx, name)
+	if err != nil {
+		return "", nil, err
+	}
+	full, err := r.ReflogRef(ref)
+	if err != nil {
+		return "", nil, err
+	}
+	ents, err := r.Reflog(full)
+	if err != nil {
+		return "", nil, err
+	}
+
+	entries := make([]ReflogEntry, len(ents))
+	for i, e := range ents {
+		entries[i] = ReflogEntry{ReflogEntry: e}
+		if git.IsZeroHash(e.Old) || git.IsZeroHash(e.New) {
+			continue
+		}
+		// The old commits may have been pruned already.
+		if forced, err := isForcedUpdate(r, e.Old, e.New); err == nil {
+			entries[i].Forced = forced
+		}
+	}
+	return full, entries, nil
+}
+
+// ReflogDiff returns the changes of a reflog entry of a repository, from the
+// old to the new commit of the reference.
+func (d *Backend) ReflogDiff(ctx context.Context, name string, e ReflogEntry) (*git.Diff, error) {
+	if git.IsZeroHash(e.Old) || git.IsZeroHash(e.New) {
+		return nil, ErrReflogNoDiff
+	}
+	r, err := d.openRepo(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return r.DiffPaths(e.Old, e.New)
+}
+
+// openRepo opens the Git repository of the repository with the given name.
+func (d *Backend) openRepo(ctx context.Context, name string) (*git.Repository, error) {
+	rr, err := d.Repository(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return rr.Open()
+}
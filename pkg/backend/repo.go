@@ -3,6 +3,7 @@ package backend
 import (
 	"bufio"
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -13,6 +14,7 @@ import (
 	"time"
 
 	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/config"
 	"github.com/charmbracelet/soft-serve/pkg/db"
 	"github.com/charmbracelet/soft-serve/pkg/db/models"
 	"github.com/charmbracelet/soft-serve/pkg/hooks"
@@ -28,12 +30,26 @@ func (d *Backend) reposPath() string {
 	return filepath.Join(d.cfg.DataPath, "repos")
 }
 
+// validateRepoName returns an error if the name of a new repository is
+// invalid or breaks one of the configured naming rules.
+func (d *Backend) validateRepoName(name string) error {
+	if err := utils.ValidateRepo(name); err != nil {
+		return err
+	}
+
+	if err := d.cfg.Repo.Name.Check(name); err != nil {
+		return fmt.Errorf("%w %q: %w", proto.ErrInvalidRepoName, name, err)
+	}
+
+	return nil
+}
+
 // CreateRepository creates a new repository.
 //
 // It implements backend.Backend.
 func (d *Backend) CreateRepository(ctx context.Context, name string, user proto.User, opts proto.RepositoryOptions) (proto.Repository, error) {
 	name = utils.SanitizeRepo(name)
-	if err := utils.ValidateRepo(name); err != nil {
+	if err := d.validateRepoName(name); err != nil {
 		return nil, err
 	}
 
@@ -60,25 +76,27 @@ func (d *Backend) CreateRepository(ctx context.Context, name string, user proto.
 			return err
 		}
 
-		_, err := git.Init(rp, true)
-		if err != nil {
-			d.logger.Debug("failed to create repository", "err", err)
-			return err
-		}
-
-		if err := os.WriteFile(filepath.Join(rp, "description"), []byte(opts.Description), fs.ModePerm); err != nil {
-			d.logger.Error("failed to write description", "repo", name, "err", err)
-			return err
-		}
+		return d.WithRepository(ctx, name, true, func() error {
+			_, err := git.Init(rp, true)
+			if err != nil {
+				d.logger.Debug("failed to create repository", "err", err)
+				return err
+			}
 
-		if !opts.Private {
-			if err := os.WriteFile(filepath.Join(rp, "git-daemon-export-ok"), []byte{}, fs.ModePerm); err != nil {
-				d.logger.Error("failed to write git-daemon-export-ok", "repo", name, "err", err)
+			if err := os.WriteFile(filepath.Join(rp, "description"), []byte(opts.Description), fs.ModePerm); err != nil {
+				d.logger.Error("failed to write description", "repo", name, "err", err)
 				return err
 			}
-		}
 
-		return hooks.GenerateHooks(ctx, d.cfg, repo)
+			if !opts.Private {
+				if err := os.WriteFile(filepath.Join(rp, "git-daemon-export-ok"), []byte{}, fs.ModePerm); err != nil {
+					d.logger.Error("failed to write git-daemon-export-ok", "repo", name, "err", err)
+					return err
+				}
+			}
+
+			return hooks.GenerateHooks(ctx, d.cfg, repo)
+		})
 	}); err != nil {
 		d.logger.Debug("failed to create repository in database", "err", err)
 		err = db.WrapError(err)
@@ -89,14 +107,16 @@ func (d *Backend) CreateRepository(ctx context.Context, name string, user proto.
 		return nil, err
 	}
 
+	d.publishRepoEvent(ctx, proto.EventRepoCreate, name, "")
+
 	return d.Repository(ctx, name)
 }
 
 // ImportRepository imports a repository from remote.
 // XXX: This a expensive operation and should be run in a goroutine.
-func (d *Backend) ImportRepository(_ context.Context, name string, user proto.User, remote string, opts proto.RepositoryOptions) (proto.Repository, error) {
+func (d *Backend) ImportRepository(ctx context.Context, name string, user proto.User, remote string, opts proto.RepositoryOptions) (proto.Repository, error) {
 	name = utils.SanitizeRepo(name)
-	if err := utils.ValidateRepo(name); err != nil {
+	if err := d.validateRepoName(name); err != nil {
 		return nil, err
 	}
 
@@ -108,7 +128,9 @@ func (d *Backend) ImportRepository(_ context.Context, name string, user proto.Us
 		return nil, task.ErrAlreadyStarted
 	}
 
-	if _, err := os.Stat(rp); err == nil || os.IsExist(err) {
+	if exists, err := d.repos.Exists(ctx, name); err != nil {
+		return nil, err
+	} else if exists {
 		return nil, proto.ErrRepoExist
 	}
 
@@ -125,19 +147,16 @@ func (d *Backend) ImportRepository(_ context.Context, name string, user proto.Us
 			CommandOptions: git.CommandOptions{
 				Timeout: -1,
 				Context: ctx,
-				Envs: []string{
-					fmt.Sprintf(`GIT_SSH_COMMAND=ssh -o UserKnownHostsFile="%s" -o StrictHostKeyChecking=no -i "%s"`,
-						filepath.Join(d.cfg.DataPath, "ssh", "known_hosts"),
-						d.cfg.SSH.ClientKeyPath,
-					),
-				},
+				Envs:    d.remoteEnvs(),
 			},
 		}
 
-		if err := git.Clone(remote, rp, copts); err != nil {
+		if err := d.WithRepository(ctx, name, true, func() error {
+			return git.Clone(remote, rp, copts)
+		}); err != nil {
 			d.logger.Error("failed to clone repository", "err", err, "mirror", opts.Mirror, "remote", remote, "path", rp)
 			// Cleanup the mess!
-			if rerr := os.RemoveAll(rp); rerr != nil {
+			if rerr := d.repos.Delete(ctx, name); rerr != nil {
 				err = errors.Join(err, rerr)
 			}
 
@@ -150,6 +169,18 @@ func (d *Backend) ImportRepository(_ context.Context, name string, user proto.Us
 			return err
 		}
 
+		if opts.Mirror {
+			// The clone is the first sync of the mirror.
+			sync := models.MirrorSync{SyncedAt: sql.NullTime{Time: time.Now(), Valid: true}}
+			if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+				return d.store.SetRepoMirrorSyncByName(ctx, tx, name, sync)
+			}); err != nil {
+				d.logger.Error("failed to set mirror sync", "err", err, "name", name)
+			}
+			d.cache.Delete(name)
+			d.attestMirror(ctx, name)
+		}
+
 		defer func() {
 			if err != nil {
 				if rerr := d.DeleteRepository(ctx, name); rerr != nil {
@@ -179,7 +210,9 @@ func (d *Backend) ImportRepository(_ context.Context, name string, user proto.Us
 
 		rcfg.Section("lfs").SetOption("url", endpoint)
 
-		if err := rr.SetConfig(rcfg); err != nil {
+		if err := d.WithRepository(ctx, name, true, func() error {
+			return rr.SetConfig(rcfg)
+		}); err != nil {
 			d.logger.Error("failed to set repository config", "err", err, "path", rp)
 			return err
 		}
@@ -217,8 +250,6 @@ func (d *Backend) ImportRepository(_ context.Context, name string, user proto.Us
 // It implements backend.Backend.
 func (d *Backend) DeleteRepository(ctx context.Context, name string) error {
 	name = utils.SanitizeRepo(name)
-	repo := name + ".git"
-	rp := filepath.Join(d.reposPath(), repo)
 
 	user := proto.UserFromContext(ctx)
 	r, err := d.Repository(ctx, name)
@@ -226,6 +257,11 @@ func (d *Backend) DeleteRepository(ctx context.Context, name string) error {
 		return err
 	}
 
+	// The forks of the repository need its objects.
+	if err := d.dissociateForks(ctx, name); err != nil {
+		return err
+	}
+
 	// We create the webhook event before deleting the repository so we can
 	// send the event after deleting the repository.
 	wh, err := webhook.NewRepositoryEvent(ctx, user, r, webhook.RepositoryEventActionDelete)
@@ -238,14 +274,17 @@ func (d *Backend) DeleteRepository(ctx context.Context, name string) error {
 		defer d.cache.Delete(name)
 
 		repom, dberr := d.store.GetRepoByName(ctx, tx, name)
-		_, ferr := os.Stat(rp)
-		if dberr != nil && ferr != nil {
+		exists, ferr := d.repos.Exists(ctx, name)
+		if ferr != nil {
+			return ferr
+		}
+		if dberr != nil && !exists {
 			return proto.ErrRepoNotFound
 		}
 
-		// If the repo is not in the database but the directory exists, remove it
-		if dberr != nil && ferr == nil {
-			return os.RemoveAll(rp)
+		// If the repo is not in the database but the storage has it, remove it
+		if dberr != nil && exists {
+			return d.repos.Delete(ctx, name)
 		} else if dberr != nil {
 			return db.WrapError(dberr)
 		}
@@ -273,7 +312,11 @@ func (d *Backend) DeleteRepository(ctx context.Context, name string) error {
 			return db.WrapError(err)
 		}
 
-		return os.RemoveAll(rp)
+		if err := os.Remove(d.avatarFile(repom.ID)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			d.logger.Error("failed to delete avatar", "repo", name, "err", err)
+		}
+
+		return d.repos.Delete(ctx, name)
 	}); err != nil {
 		if errors.Is(err, db.ErrRecordNotFound) {
 			return proto.ErrRepoNotFound
@@ -282,6 +325,8 @@ func (d *Backend) DeleteRepository(ctx context.Context, name string) error {
 		return db.WrapError(err)
 	}
 
+	d.publishRepoEvent(ctx, proto.EventRepoDelete, name, "")
+
 	return webhook.SendEvent(ctx, wh)
 }
 
@@ -316,32 +361,11 @@ func (d *Backend) DeleteUserRepositories(ctx context.Context, username string) e
 //
 // It implements backend.Backend.
 func (d *Backend) RenameRepository(ctx context.Context, oldName string, newName string) error {
-	oldName = utils.SanitizeRepo(oldName)
-	if err := utils.ValidateRepo(oldName); err != nil {
-		return err
-	}
-
-	newName = utils.SanitizeRepo(newName)
-	if err := utils.ValidateRepo(newName); err != nil {
+	oldName, newName, err := d.checkRename(ctx, oldName, newName)
+	if err != nil || oldName == newName {
 		return err
 	}
 
-	if oldName == newName {
-		return nil
-	}
-
-	oldRepo := oldName + ".git"
-	newRepo := newName + ".git"
-	op := filepath.Join(d.reposPath(), oldRepo)
-	np := filepath.Join(d.reposPath(), newRepo)
-	if _, err := os.Stat(op); err != nil {
-		return proto.ErrRepoNotFound
-	}
-
-	if _, err := os.Stat(np); err == nil {
-		return proto.ErrRepoExist
-	}
-
 	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
 		// Delete cache
 		defer d.cache.Delete(oldName)
@@ -350,16 +374,19 @@ func (d *Backend) RenameRepository(ctx context.Context, oldName string, newName
 			return err
 		}
 
-		// Make sure the new repository parent directory exists.
-		if err := os.MkdirAll(filepath.Dir(np), os.ModePerm); err != nil {
-			return err
-		}
-
-		return os.Rename(op, np)
+		return d.repos.Rename(ctx, oldName, newName)
 	}); err != nil {
 		return db.WrapError(err)
 	}
 
+	// Alternates point to the path of repositories, fix the links between
+	// forks.
+	if err := d.relinkForks(ctx, newName); err != nil {
+		d.logger.Error("failed to relink forks", "repo", newName, "err", err)
+	}
+
+	d.publishRepoEvent(ctx, proto.EventRepoRename, newName, oldName)
+
 	user := proto.UserFromContext(ctx)
 	repo, err := d.Repository(ctx, newName)
 	if err != nil {
@@ -374,6 +401,37 @@ func (d *Backend) RenameRepository(ctx context.Context, oldName string, newName
 	return webhook.SendEvent(ctx, wh)
 }
 
+// checkRename returns the sanitized names of a rename, and an error if the
+// repository can't be renamed. The names are equal when there is nothing to
+// rename.
+func (d *Backend) checkRename(ctx context.Context, oldName, newName string) (string, string, error) {
+	oldName = utils.SanitizeRepo(oldName)
+	if err := utils.ValidateRepo(oldName); err != nil {
+		return "", "", err
+	}
+
+	newName = utils.SanitizeRepo(newName)
+	if err := d.validateRepoName(newName); err != nil {
+		return "", "", err
+	}
+
+	if oldName == newName {
+		return oldName, newName, nil
+	}
+
+	if exists, err := d.repos.Exists(ctx, oldName); err != nil || !exists {
+		return "", "", proto.ErrRepoNotFound
+	}
+
+	if exists, err := d.repos.Exists(ctx, newName); err != nil {
+		return "", "", err
+	} else if exists {
+		return "", "", proto.ErrRepoExist
+	}
+
+	return oldName, newName, nil
+}
+
 // Repositories returns a list of repositories per page.
 //
 // It implements backend.Backend.
@@ -407,6 +465,34 @@ func (d *Backend) Repositories(ctx context.Context) ([]proto.Repository, error)
 	return repos, nil
 }
 
+// DiskUsage returns the number of bytes used by all the repositories and
+// their LFS objects.
+func (d *Backend) DiskUsage(ctx context.Context) (int64, error) {
+	size, err := d.repos.Size(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	lfsSize, err := storage.DirSize(ctx, filepath.Join(d.cfg.DataPath, "lfs"))
+	if err != nil {
+		return 0, err
+	}
+
+	return size + lfsSize, nil
+}
+
+// GCRepository runs git gc on a repository. The commit-graph and the pack
+// bitmap index are written too when commit-graphs are enabled. The run is kept
+// with the housekeeping runs.
+func (d *Backend) GCRepository(ctx context.Context, name string) error {
+	name = utils.SanitizeRepo(name)
+	r := HousekeepingRun{Repo: name, Task: config.HousekeepingGC, StartedAt: time.Now()}
+	r.Err = d.runHousekeepingTask(ctx, name, config.HousekeepingGC)
+	r.Duration = time.Since(r.StartedAt)
+	d.recordHousekeeping(r)
+	return r.Err
+}
+
 // Repository returns a repository by name.
 //
 // It implements backend.Backend.
@@ -419,9 +505,9 @@ func (d *Backend) Repository(ctx context.Context, name string) (proto.Repository
 	}
 
 	rp := filepath.Join(d.reposPath(), name+".git")
-	if _, err := os.Stat(rp); err != nil {
-		if !errors.Is(err, fs.ErrNotExist) {
-			d.logger.Errorf("failed to stat repository path: %v", err)
+	if exists, err := d.repos.Exists(ctx, name); err != nil || !exists {
+		if err != nil {
+			d.logger.Errorf("failed to check repository: %v", err)
 		}
 		return nil, proto.ErrRepoNotFound
 	}
@@ -516,6 +602,46 @@ func (d *Backend) IsHidden(ctx context.Context, name string) (bool, error) {
 	return hidden, nil
 }
 
+// LandingTab returns the tab the UI opens the repository on. It's empty for
+// the default tab.
+func (d *Backend) LandingTab(ctx context.Context, name string) (string, error) {
+	name = utils.SanitizeRepo(name)
+	var tab string
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		tab, err = d.store.GetRepoLandingTabByName(ctx, tx, name)
+		return err
+	}); err != nil {
+		return "", db.WrapError(err)
+	}
+
+	return tab, nil
+}
+
+// ReadmePath returns the path of the file the UI shows as the README of the
+// repository: the one set with SetReadmePath, or else the one of its view
+// settings. It's empty to show the README found at the root.
+func (d *Backend) ReadmePath(ctx context.Context, name string) (string, error) {
+	name = utils.SanitizeRepo(name)
+	var path string
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		path, err = d.store.GetRepoReadmePathByName(ctx, tx, name)
+		return err
+	}); err != nil {
+		return "", db.WrapError(err)
+	}
+	if path != "" {
+		return path, nil
+	}
+
+	vs, err := d.ViewSettings(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return vs.Readme, nil
+}
+
 // ProjectName returns the project name of a repository.
 //
 // It implements backend.Backend.
@@ -547,6 +673,32 @@ func (d *Backend) SetHidden(ctx context.Context, name string, hidden bool) error
 	}))
 }
 
+// SetLandingTab sets the tab the UI opens the repository on. An empty tab
+// resets it to the default.
+func (d *Backend) SetLandingTab(ctx context.Context, name string, tab string) error {
+	name = utils.SanitizeRepo(name)
+
+	// Delete cache
+	d.cache.Delete(name)
+
+	return db.WrapError(d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return d.store.SetRepoLandingTabByName(ctx, tx, name, tab)
+	}))
+}
+
+// SetReadmePath sets the path of the file the UI shows as the README of the
+// repository. An empty path resets it to the one of the view settings.
+func (d *Backend) SetReadmePath(ctx context.Context, name string, path string) error {
+	name = utils.SanitizeRepo(name)
+
+	// Delete cache
+	d.cache.Delete(name)
+
+	return db.WrapError(d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return d.store.SetRepoReadmePathByName(ctx, tx, name, path)
+	}))
+}
+
 // SetDescription sets the description of a repository.
 //
 // It implements backend.Backend.
@@ -558,7 +710,9 @@ func (d *Backend) SetDescription(ctx context.Context, name string, desc string)
 	d.cache.Delete(name)
 
 	return d.db.TransactionContext(ctx, func(tx *db.Tx) error {
-		if err := os.WriteFile(filepath.Join(rp, "description"), []byte(desc), fs.ModePerm); err != nil {
+		if err := d.WithRepository(ctx, name, true, func() error {
+			return os.WriteFile(filepath.Join(rp, "description"), []byte(desc), fs.ModePerm)
+		}); err != nil {
 			d.logger.Error("failed to write description", "repo", name, "err", err)
 			return err
 		}
@@ -580,18 +734,23 @@ func (d *Backend) SetPrivate(ctx context.Context, name string, private bool) err
 	if err := db.WrapError(
 		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
 			fp := filepath.Join(rp, "git-daemon-export-ok")
-			if !private {
-				if err := os.WriteFile(fp, []byte{}, fs.ModePerm); err != nil {
-					d.logger.Error("failed to write git-daemon-export-ok", "repo", name, "err", err)
-					return err
-				}
-			} else {
-				if _, err := os.Stat(fp); err == nil {
-					if err := os.Remove(fp); err != nil {
-						d.logger.Error("failed to remove git-daemon-export-ok", "repo", name, "err", err)
+			if err := d.WithRepository(ctx, name, true, func() error {
+				if !private {
+					if err := os.WriteFile(fp, []byte{}, fs.ModePerm); err != nil {
+						d.logger.Error("failed to write git-daemon-export-ok", "repo", name, "err", err)
 						return err
 					}
+				} else {
+					if _, err := os.Stat(fp); err == nil {
+						if err := os.Remove(fp); err != nil {
+							d.logger.Error("failed to remove git-daemon-export-ok", "repo", name, "err", err)
+							return err
+						}
+					}
 				}
+				return nil
+			}); err != nil {
+				return err
 			}
 
 			return d.store.SetRepoIsPrivateByName(ctx, tx, name, private)
@@ -712,6 +871,13 @@ func (r *repo) IsHidden() bool {
 	return r.repo.Hidden
 }
 
+// IsArchived returns whether the repository is archived.
+//
+// It implements backend.Repository.
+func (r *repo) IsArchived() bool {
+	return r.repo.Archived
+}
+
 // CreatedAt returns the repository's creation time.
 func (r *repo) CreatedAt() time.Time {
 	return r.repo.CreatedAt
@@ -0,0 +1,126 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/git"
+)
+
+// ErrRepoConfigKeyNotAllowed is returned when a key of the Git configuration
+// of a repository can't be read or set.
+var ErrRepoConfigKeyNotAllowed = errors.New("config key is not allowed")
+
+// repoConfigSections are the sections of the Git configuration of
+// repositories that can be read and set.
+var repoConfigSections = map[string]bool{
+	"core":    true,
+	"gc":      true,
+	"pack":    true,
+	"receive": true,
+}
+
+// repoConfigDenied are the keys of the allowed sections that run commands,
+// point outside of the repository, or would break the repository.
+var repoConfigDenied = map[string]bool{
+	"core.alternaterefscommand":    true,
+	"core.askpass":                 true,
+	"core.bare":                    true,
+	"core.editor":                  true,
+	"core.excludesfile":            true,
+	"core.fsmonitor":               true,
+	"core.gitproxy":                true,
+	"core.hookspath":               true,
+	"core.pager":                   true,
+	"core.repositoryformatversion": true,
+	"core.sharedrepository":        true,
+	"core.sshcommand":              true,
+	"core.worktree":                true,
+	"receive.procreceiverefs":      true,
+}
+
+// normalizeRepoConfigKey returns the key with a lower case section and name,
+// the way Git lists them. Subsections are case sensitive and kept as is.
+func normalizeRepoConfigKey(key string) string {
+	first := strings.Index(key, ".")
+	last := strings.LastIndex(key, ".")
+	if first < 0 {
+		return strings.ToLower(key)
+	}
+	return strings.ToLower(key[:first]) + key[first:last] + strings.ToLower(key[last:])
+}
+
+// IsRepoConfigKeyAllowed returns true if the key of the Git configuration of
+// a repository can be read and set.
+func IsRepoConfigKeyAllowed(key string) bool {
+	key = normalizeRepoConfigKey(key)
+	section, rest, ok := strings.Cut(key, ".")
+	if !ok || rest == "" || strings.HasSuffix(rest, ".") || strings.ContainsAny(key, "\n\x00") {
+		return false
+	}
+	return repoConfigSections[section] && !repoConfigDenied[key]
+}
+
+// RepoConfig returns the allowed entries of the Git configuration of a
+// repository. If key is not empty, only the values of that key are
+// returned.
+func (d *Backend) RepoConfig(ctx context.Context, name, key string) ([]git.ConfigEntry, error) {
+	if key != "" && !IsRepoConfigKeyAllowed(key) {
+		return nil, fmt.Errorf("%w: %s", ErrRepoConfigKeyNotAllowed, key)
+	}
+
+	rr, err := d.Repository(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := rr.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := r.ConfigEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	key = normalizeRepoConfigKey(key)
+	entries := make([]git.ConfigEntry, 0)
+	for _, e := range all {
+		if !IsRepoConfigKeyAllowed(e.Key) || (key != "" && e.Key != key) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// SetRepoConfig sets the value of an allowed key of the Git configuration of
+// a repository.
+func (d *Backend) SetRepoConfig(ctx context.Context, name, key, value string) error {
+	if !IsRepoConfigKeyAllowed(key) {
+		return fmt.Errorf("%w: %s", ErrRepoConfigKeyNotAllowed, key)
+	}
+
+	rr, err := d.Repository(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	return d.WithRepository(ctx, rr.Name(), true, func() error {
+		r, err := rr.Open()
+		if err != nil {
+			return err
+		}
+
+		if err := r.SetConfigValue(key, value); err != nil {
+			d.logger.Error("error setting repository config", "repo", name, "key", key, "err", err)
+			return fmt.Errorf("failed to set %s", key)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,29 @@
+package backend
+
+import "testing"
+
+func TestIsRepoConfigKeyAllowed(t *testing.T) {
+	cases := map[string]bool{
+		"core.compression":             true,
+		"Core.LogAllRefUpdates":        true,
+		"gc.auto":                      true,
+		"gc.refs/heads/*.reflogExpire": true,
+		"pack.windowMemory":            true,
+		"receive.denyDeletes":          true,
+		"core.hooksPath":               false,
+		"CORE.SSHCOMMAND":              false,
+		"core.fsmonitor":               false,
+		"remote.origin.url":            false,
+		"uploadpack.packObjectsHook":   false,
+		"core":                         false,
+		"core.":                        false,
+		"-core.foo":                    false,
+		"core.foo\nbar":                false,
+	}
+
+	for key, want := range cases {
+		if got := IsRepoConfigKeyAllowed(key); got != want {
+			t.Errorf("IsRepoConfigKeyAllowed(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
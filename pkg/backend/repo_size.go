@@ -0,0 +1,75 @@
+package backend
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+// repoSizeSnapshots is the number of size snapshots kept per repository.
+const repoSizeSnapshots = 30
+
+// RepoSize is a snapshot of the size and the number of objects of a
+// repository.
+type RepoSize struct {
+	Size    int64
+	Objects int64
+	At      time.Time
+}
+
+// RecordRepoSize records a snapshot of the size and the number of objects of
+// a repository. Only the latest snapshots are kept.
+func (d *Backend) RecordRepoSize(ctx context.Context, name string) error {
+	repo, err := d.Repository(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	r, err := repo.Open()
+	if err != nil {
+		return err
+	}
+
+	stats, err := r.ObjectStats()
+	if err != nil {
+		return err
+	}
+
+	return db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			if err := d.store.CreateRepoSize(ctx, tx, repo.ID(), stats.Size(), stats.Loose+stats.Packed); err != nil {
+				return err
+			}
+			return d.store.DeleteOldRepoSizes(ctx, tx, repo.ID(), repoSizeSnapshots)
+		}),
+	)
+}
+
+// RepoSizes returns the size snapshots of a repository, oldest first.
+func (d *Backend) RepoSizes(ctx context.Context, name string) ([]RepoSize, error) {
+	repo, err := d.Repository(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var ms []models.RepoSize
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		ms, err = d.store.GetRepoSizesByRepoID(ctx, tx, repo.ID(), repoSizeSnapshots)
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	sizes := make([]RepoSize, len(ms))
+	for i, m := range ms {
+		sizes[len(ms)-1-i] = RepoSize{
+			Size:    m.Size,
+			Objects: m.Objects,
+			At:      m.CreatedAt,
+		}
+	}
+	return sizes, nil
+}
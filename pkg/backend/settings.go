@@ -2,9 +2,11 @@ package backend
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/charmbracelet/soft-serve/pkg/access"
 	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
 )
 
 // AllowKeyless returns whether or not keyless access is allowed.
@@ -56,3 +58,90 @@ func (b *Backend) SetAnonAccess(ctx context.Context, level access.AccessLevel) e
 		return b.store.SetAnonAccess(ctx, tx, level)
 	})
 }
+
+// MaintenanceMode returns whether or not the server is in read-only
+// maintenance mode.
+func (b *Backend) MaintenanceMode(ctx context.Context) bool {
+	var enabled bool
+	if err := b.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		enabled, err = b.store.GetMaintenanceMode(ctx, tx)
+		return err
+	}); err != nil {
+		return false
+	}
+
+	return enabled
+}
+
+// SetMaintenanceMode sets whether or not the server is in read-only
+// maintenance mode.
+func (b *Backend) SetMaintenanceMode(ctx context.Context, enabled bool) error {
+	return b.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return b.store.SetMaintenanceMode(ctx, tx, enabled)
+	})
+}
+
+// MaintenanceMessage returns the message shown to users while the server is in
+// maintenance mode.
+func (b *Backend) MaintenanceMessage(ctx context.Context) string {
+	var message string
+	if err := b.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		message, err = b.store.GetMaintenanceMessage(ctx, tx)
+		return err
+	}); err != nil {
+		return ""
+	}
+
+	return message
+}
+
+// SetMaintenanceMessage sets the message shown to users while the server is in
+// maintenance mode.
+func (b *Backend) SetMaintenanceMessage(ctx context.Context, message string) error {
+	return b.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return b.store.SetMaintenanceMessage(ctx, tx, message)
+	})
+}
+
+// MaintenanceAdminsExempt returns whether or not admins can still push while
+// the server is in maintenance mode.
+func (b *Backend) MaintenanceAdminsExempt(ctx context.Context) bool {
+	var exempt bool
+	if err := b.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		exempt, err = b.store.GetMaintenanceAdminsExempt(ctx, tx)
+		return err
+	}); err != nil {
+		return false
+	}
+
+	return exempt
+}
+
+// SetMaintenanceAdminsExempt sets whether or not admins can still push while
+// the server is in maintenance mode.
+func (b *Backend) SetMaintenanceAdminsExempt(ctx context.Context, exempt bool) error {
+	return b.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		return b.store.SetMaintenanceAdminsExempt(ctx, tx, exempt)
+	})
+}
+
+// CheckMaintenance returns an error wrapping proto.ErrMaintenance if the
+// server is in maintenance mode and the user isn't allowed to write.
+func (b *Backend) CheckMaintenance(ctx context.Context, user proto.User) error {
+	if !b.MaintenanceMode(ctx) {
+		return nil
+	}
+
+	if user != nil && user.IsAdmin() && b.MaintenanceAdminsExempt(ctx) {
+		return nil
+	}
+
+	if msg := b.MaintenanceMessage(ctx); msg != "" {
+		return fmt.Errorf("%w: %s", proto.ErrMaintenance, msg)
+	}
+
+	return proto.ErrMaintenance
+}
@@ -0,0 +1,40 @@
+package backend
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// AcquireRepository makes a repository available in its local directory for
+// an operation, like serving a fetch or a push. write is true when the
+// operation changes the repository. release must be called once the
+// operation is done, it stores the changes.
+func (d *Backend) AcquireRepository(ctx context.Context, name string, write bool) (release func() error, err error) {
+	name = utils.SanitizeRepo(name)
+	release, err = d.repos.Acquire(ctx, name, write)
+	if err != nil {
+		d.logger.Error("failed to acquire repository", "repo", name, "write", write, "err", err)
+		return nil, err
+	}
+	return release, nil
+}
+
+// WithRepository runs fn while the repository is acquired, see
+// AcquireRepository. Changes to repositories are made with it, or between
+// AcquireRepository and release. The error of fn wins over the error of
+// releasing the repository.
+func (d *Backend) WithRepository(ctx context.Context, name string, write bool, fn func() error) error {
+	release, err := d.AcquireRepository(ctx, name, write)
+	if err != nil {
+		return err
+	}
+	err = fn()
+	if rerr := release(); rerr != nil {
+		d.logger.Error("failed to release repository", "repo", name, "write", write, "err", rerr)
+		if err == nil {
+			err = rerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,229 @@
+package backend
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// Submodules returns the submodules of a repository at the given revision.
+// Submodules hosted on this server, that the user can read, are resolved to
+// their repository along with the state of their pinned commit.
+func (d *Backend) Submodules(ctx context.Context, user proto.User, repo proto.Repository, rev string) ([]proto.Submodule, error) {
+	r, err := repo.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	subs, err := r.Submodules(rev)
+	if err != nil {
+		return nil, err
+	}
+
+	submodules := make([]proto.Submodule, len(subs))
+	for i, s := range subs {
+		sub := proto.Submodule{
+			Name:   s.Name,
+			Path:   s.Path,
+			URL:    s.URL,
+			Branch: s.Branch,
+			Commit: s.Commit,
+		}
+
+		name := d.localRepoName(repo.Name(), s.URL)
+		if name != "" && name != repo.Name() &&
+			d.AccessLevelForUser(ctx, name, user) >= access.ReadOnlyAccess {
+			if sr, err := d.Repository(ctx, name); err == nil {
+				sub.Repo = sr.Name()
+				if s.Commit != "" {
+					sub.Missing, sub.Behind = d.submoduleDrift(sr, s.Commit, s.Branch)
+				}
+			}
+		}
+
+		submodules[i] = sub
+	}
+
+	return submodules, nil
+}
+
+// SubmoduleGraph returns the links the submodules of the default branch of
+// the repositories the user can read make between the repositories hosted on
+// this server. The keys are the repositories with submodules pointing at other
+// hosted repositories, the values the repositories they point to, sorted.
+// Repositories without such submodules are left out.
+func (d *Backend) SubmoduleGraph(ctx context.Context, user proto.User) (map[string][]string, error) {
+	repos, err := d.Repositories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := make(map[string][]string)
+	for _, repo := range repos {
+		if d.AccessLevelForUser(ctx, repo.Name(), user) < access.ReadOnlyAccess {
+			continue
+		}
+		r, err := repo.Open()
+		if err != nil {
+			continue
+		}
+		head, err := r.HEAD()
+		if err != nil {
+			// Empty repositories have no submodules.
+			continue
+		}
+		subs, err := r.Submodules(head.ID)
+		if err != nil {
+			d.logger.Debugf("failed to read submodules of %s: %v", repo.Name(), err)
+			continue
+		}
+
+		var deps []string
+		for _, s := range subs {
+			name := d.localRepoName(repo.Name(), s.URL)
+			if name == "" || name == repo.Name() || slices.Contains(deps, name) ||
+				d.AccessLevelForUser(ctx, name, user) < access.ReadOnlyAccess {
+				continue
+			}
+			if _, err := d.Repository(ctx, name); err != nil {
+				continue
+			}
+			deps = append(deps, name)
+		}
+		if len(deps) > 0 {
+			slices.Sort(deps)
+			graph[repo.Name()] = deps
+		}
+	}
+
+	return graph, nil
+}
+
+// SubmoduleNode is a repository of the tree of a submodule graph.
+type SubmoduleNode struct {
+	// Repo is the name of the repository.
+	Repo string
+	// Depth is the number of links from the root of the tree.
+	Depth int
+	// Cycle is true if the repository is one of its own submodules, through
+	// the ones above it. Its submodules aren't repeated.
+	Cycle bool
+}
+
+// SubmoduleTree lays out a submodule graph as a tree, depth first. The roots
+// are the repositories no other one points to, sorted, then the ones only
+// reachable through a cycle.
+func SubmoduleTree(graph map[string][]string) []SubmoduleNode {
+	used := make(map[string]bool)
+	for _, deps := range graph {
+		for _, dep := range deps {
+			used[dep] = true
+		}
+	}
+	names := make([]string, 0, len(graph))
+	for name := range graph {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var nodes []SubmoduleNode
+	seen := make(map[string]bool)
+	above := make(map[string]bool)
+	var walk func(name string, depth int)
+	walk = func(name string, depth int) {
+		if above[name] {
+			nodes = append(nodes, SubmoduleNode{Repo: name, Depth: depth, Cycle: true})
+			return
+		}
+		seen[name] = true
+		nodes = append(nodes, SubmoduleNode{Repo: name, Depth: depth})
+		above[name] = true
+		for _, dep := range graph[name] {
+			walk(dep, depth+1)
+		}
+		delete(above, name)
+	}
+	for _, name := range names {
+		if !used[name] {
+			walk(name, 0)
+		}
+	}
+	for _, name := range names {
+		if !seen[name] {
+			walk(name, 0)
+		}
+	}
+	return nodes
+}
+
+// submoduleDrift reports whether the commit is missing from the repository
+// and how many commits it's behind the given branch, or HEAD.
+func (d *Backend) submoduleDrift(repo proto.Repository, commit, branch string) (bool, int64) {
+	r, err := repo.Open()
+	if err != nil {
+		return false, 0
+	}
+
+	if _, err := r.CatFileCommit(commit); err != nil {
+		return true, 0
+	}
+
+	target := git.HEAD
+	if branch != "" && branch != "." {
+		target = git.RefsHeads + branch
+	}
+
+	behind, err := r.RevListCount([]string{commit + ".." + target})
+	if err != nil {
+		d.logger.Debugf("failed to count submodule commits: %v", err)
+		return false, 0
+	}
+
+	return false, behind
+}
+
+// localRepoName returns the name of the repository a submodule URL points to
+// if it's hosted on this server. Relative URLs are resolved against the
+// superproject. It returns an empty string for external URLs.
+func (d *Backend) localRepoName(superproject, rawURL string) string {
+	if strings.HasPrefix(rawURL, "./") || strings.HasPrefix(rawURL, "../") {
+		repo := path.Join(superproject, rawURL)
+		if strings.HasPrefix(repo, "../") {
+			return ""
+		}
+		return utils.SanitizeRepo(repo)
+	}
+
+	var host, repo string
+	if u, err := url.Parse(rawURL); err == nil && u.Scheme != "" && u.Host != "" {
+		host, repo = u.Hostname(), u.Path
+	} else if before, after, ok := strings.Cut(rawURL, ":"); ok && !strings.Contains(before, "/") {
+		// SCP-like syntax, user@host:repo
+		if i := strings.LastIndex(before, "@"); i >= 0 {
+			before = before[i+1:]
+		}
+		host, repo = before, after
+	} else {
+		return ""
+	}
+
+	for _, pu := range []string{d.cfg.SSH.PublicURL, d.cfg.HTTP.PublicURL, d.cfg.Git.PublicURL} {
+		u, err := url.Parse(pu)
+		if err != nil || u.Hostname() == "" || !strings.EqualFold(u.Hostname(), host) {
+			continue
+		}
+
+		// Strip the HTTP base path of the public URL, if any.
+		repo = strings.TrimPrefix(repo, strings.TrimSuffix(u.Path, "/"))
+		return utils.SanitizeRepo(repo)
+	}
+
+	return ""
+}
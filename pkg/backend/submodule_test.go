@@ -0,0 +1,31 @@
+package backend
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSubmoduleTree(t *testing.T) {
+	graph := map[string][]string{
+		"app":   {"lib", "util"},
+		"lib":   {"util"},
+		"ring1": {"ring2"},
+		"ring2": {"ring1"},
+	}
+	want := []SubmoduleNode{
+		{Repo: "app"},
+		{Repo: "lib", Depth: 1},
+		{Repo: "util", Depth: 2},
+		{Repo: "util", Depth: 1},
+		{Repo: "ring1"},
+		{Repo: "ring2", Depth: 1},
+		{Repo: "ring1", Depth: 2, Cycle: true},
+	}
+	if got := SubmoduleTree(graph); !slices.Equal(got, want) {
+		t.Errorf("SubmoduleTree() = %+v, want %+v", got, want)
+	}
+
+	if got := SubmoduleTree(nil); len(got) != 0 {
+		t.Errorf("SubmoduleTree(nil) = %+v, want no nodes", got)
+	}
+}
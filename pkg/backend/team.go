@@ -0,0 +1,297 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// TeamAccess is the access of a team to a repository.
+type TeamAccess struct {
+	Team        string
+	Repo        string
+	AccessLevel access.AccessLevel
+}
+
+// CreateTeam creates a team without members.
+func (d *Backend) CreateTeam(ctx context.Context, team string) error {
+	team = strings.ToLower(team)
+	if err := utils.ValidateTeam(team); err != nil {
+		return err
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.CreateTeam(ctx, tx, team)
+		}),
+	); err != nil {
+		if errors.Is(err, db.ErrDuplicateKey) {
+			return proto.ErrTeamExist
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteTeam deletes a team. Its members lose the access the team gave them.
+func (d *Backend) DeleteTeam(ctx context.Context, team string) error {
+	team = strings.ToLower(team)
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.DeleteTeamByName(ctx, tx, team)
+		}),
+	); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return proto.ErrTeamNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Teams returns the names of the teams ordered by name.
+func (d *Backend) Teams(ctx context.Context) ([]string, error) {
+	var ms []models.Team
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		ms, err = d.store.ListTeams(ctx, tx)
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+	return teamNames(ms), nil
+}
+
+// UserTeams returns the names of the teams a user is a member of ordered by
+// name.
+func (d *Backend) UserTeams(ctx context.Context, user proto.User) ([]string, error) {
+	if user == nil {
+		return nil, nil
+	}
+
+	var ms []models.Team
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		ms, err = d.store.ListTeamsByUserID(ctx, tx, user.ID())
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+	return teamNames(ms), nil
+}
+
+// AddTeamMember adds a user to a team.
+func (d *Backend) AddTeamMember(ctx context.Context, team string, username string) error {
+	team = strings.ToLower(team)
+	username = strings.ToLower(username)
+	if err := d.checkTeam(ctx, team); err != nil {
+		return err
+	}
+	if _, err := d.User(ctx, username); err != nil {
+		return err
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.AddTeamMemberByUsername(ctx, tx, team, username)
+		}),
+	); err != nil {
+		if errors.Is(err, db.ErrDuplicateKey) {
+			return proto.ErrTeamMemberExist
+		}
+		return err
+	}
+	return nil
+}
+
+// RemoveTeamMember removes a user from a team.
+func (d *Backend) RemoveTeamMember(ctx context.Context, team string, username string) error {
+	team = strings.ToLower(team)
+	username = strings.ToLower(username)
+	if err := d.checkTeam(ctx, team); err != nil {
+		return err
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.RemoveTeamMemberByUsername(ctx, tx, team, username)
+		}),
+	); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return proto.ErrTeamMemberNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// TeamMembers returns the usernames of the members of a team ordered by
+// username.
+func (d *Backend) TeamMembers(ctx context.Context, team string) ([]string, error) {
+	team = strings.ToLower(team)
+	if err := d.checkTeam(ctx, team); err != nil {
+		return nil, err
+	}
+
+	var users []models.User
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		users, err = d.store.ListTeamMembersAsUsers(ctx, tx, team)
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	usernames := make([]string, len(users))
+	for i, u := range users {
+		usernames[i] = u.Username
+	}
+	return usernames, nil
+}
+
+// AddTeamRepo gives a team access to a repository. The members of the team
+// get the highest access level of their teams and collaborations.
+func (d *Backend) AddTeamRepo(ctx context.Context, team string, repo string, level access.AccessLevel) error {
+	team = strings.ToLower(team)
+	repo = utils.SanitizeRepo(repo)
+	if err := d.checkTeam(ctx, team); err != nil {
+		return err
+	}
+	if _, err := d.Repository(ctx, repo); err != nil {
+		return err
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.AddTeamRepo(ctx, tx, team, repo, level)
+		}),
+	); err != nil {
+		if errors.Is(err, db.ErrDuplicateKey) {
+			return proto.ErrTeamRepoExist
+		}
+		return err
+	}
+	return nil
+}
+
+// RemoveTeamRepo removes the access of a team to a repository.
+func (d *Backend) RemoveTeamRepo(ctx context.Context, team string, repo string) error {
+	team = strings.ToLower(team)
+	repo = utils.SanitizeRepo(repo)
+	if err := d.checkTeam(ctx, team); err != nil {
+		return err
+	}
+
+	if err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.RemoveTeamRepo(ctx, tx, team, repo)
+		}),
+	); err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return proto.ErrTeamRepoNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// TeamRepos returns the access of a team to repositories ordered by
+// repository name.
+func (d *Backend) TeamRepos(ctx context.Context, team string) ([]TeamAccess, error) {
+	team = strings.ToLower(team)
+	if err := d.checkTeam(ctx, team); err != nil {
+		return nil, err
+	}
+	return d.teamAccess(ctx, func(tx *db.Tx) ([]models.TeamRepo, error) {
+		return d.store.ListTeamReposByTeam(ctx, tx, team)
+	})
+}
+
+// RepoTeams returns the access of teams to a repository ordered by team name.
+func (d *Backend) RepoTeams(ctx context.Context, repo string) ([]TeamAccess, error) {
+	repo = utils.SanitizeRepo(repo)
+	return d.teamAccess(ctx, func(tx *db.Tx) ([]models.TeamRepo, error) {
+		return d.store.ListTeamReposByRepo(ctx, tx, repo)
+	})
+}
+
+// UserTeamRepos returns the access of the teams a user is a member of to
+// repositories, ordered by team and repository names.
+func (d *Backend) UserTeamRepos(ctx context.Context, user proto.User) ([]TeamAccess, error) {
+	if user == nil {
+		return nil, nil
+	}
+	return d.teamAccess(ctx, func(tx *db.Tx) ([]models.TeamRepo, error) {
+		return d.store.ListTeamReposByUserID(ctx, tx, user.ID())
+	})
+}
+
+// teamAccessLevel returns the highest access level the teams of a user give
+// them to a repository, and false if none of their teams has access to it.
+func (d *Backend) teamAccessLevel(ctx context.Context, repo string, user proto.User) (access.AccessLevel, bool) {
+	tas, err := d.UserTeamRepos(ctx, user)
+	if err != nil {
+		d.logger.Error("error finding team access", "repo", repo, "err", err)
+		return -1, false
+	}
+
+	level, ok := access.AccessLevel(-1), false
+	for _, ta := range tas {
+		if ta.Repo == repo && ta.AccessLevel > level {
+			level, ok = ta.AccessLevel, true
+		}
+	}
+	return level, ok
+}
+
+// teamAccess runs a query of the access of teams to repositories.
+func (d *Backend) teamAccess(ctx context.Context, query func(tx *db.Tx) ([]models.TeamRepo, error)) ([]TeamAccess, error) {
+	var ms []models.TeamRepo
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		ms, err = query(tx)
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	tas := make([]TeamAccess, len(ms))
+	for i, m := range ms {
+		tas[i] = TeamAccess{
+			Team:        m.TeamName,
+			Repo:        m.RepoName,
+			AccessLevel: m.AccessLevel,
+		}
+	}
+	return tas, nil
+}
+
+// checkTeam returns ErrTeamNotFound if the team doesn't exist.
+func (d *Backend) checkTeam(ctx context.Context, team string) error {
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		_, err := d.store.GetTeamByName(ctx, tx, team)
+		return err
+	}); err != nil {
+		err = db.WrapError(err)
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return proto.ErrTeamNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// teamNames returns the names of the teams.
+func teamNames(ms []models.Team) []string {
+	names := make([]string, len(ms))
+	for i, m := range ms {
+		names[i] = m.Name
+	}
+	return names
+}
@@ -0,0 +1,154 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// ErrTemplateNotFound is returned when a repository template isn't defined in
+// the config, or the user can't read its repository.
+var ErrTemplateNotFound = errors.New("repository template not found")
+
+// RepoTemplates returns the repository templates of the config the user can
+// create repositories from.
+func (d *Backend) RepoTemplates(ctx context.Context, user proto.User) []config.RepoTemplate {
+	templates := make([]config.RepoTemplate, 0, len(d.cfg.Repo.Templates))
+	for _, t := range d.cfg.Repo.Templates {
+		if t.Repo != "" && d.AccessLevelForUser(ctx, utils.SanitizeRepo(t.Repo), user) < access.ReadOnlyAccess {
+			continue
+		}
+		templates = append(templates, t)
+	}
+	return templates
+}
+
+// CheckRepoTemplate returns an error if the files of a template can't be
+// read: its repository or directory doesn't exist, or its ref can't be
+// resolved.
+func (d *Backend) CheckRepoTemplate(ctx context.Context, t config.RepoTemplate) error {
+	if t.Path != "" {
+		fi, err := os.Stat(t.Path)
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() {
+			return fmt.Errorf("%s is not a directory", t.Path)
+		}
+		return nil
+	}
+
+	r, err := d.Repository(ctx, utils.SanitizeRepo(t.Repo))
+	if err != nil {
+		return err
+	}
+	rr, err := r.Open()
+	if err != nil {
+		return err
+	}
+	if _, err := rr.RevParse(templateRef(t) + "^{tree}"); err != nil {
+		return fmt.Errorf("%w: %q", git.ErrRevisionNotExist, templateRef(t))
+	}
+	return nil
+}
+
+// CreateRepositoryFromTemplate creates a new repository whose initial commit
+// has the files of a template. The repository starts its own history, the
+// one of the template repository isn't copied.
+func (d *Backend) CreateRepositoryFromTemplate(ctx context.Context, name string, template string, user proto.User, opts proto.RepositoryOptions) (proto.Repository, error) {
+	t, ok := d.cfg.Repo.Template(template)
+	if !ok || (t.Repo != "" && d.AccessLevelForUser(ctx, utils.SanitizeRepo(t.Repo), user) < access.ReadOnlyAccess) {
+		return nil, fmt.Errorf("%w: %q", ErrTemplateNotFound, template)
+	}
+	if err := d.CheckRepoTemplate(ctx, t); err != nil {
+		return nil, fmt.Errorf("template %q: %w", t.Name, err)
+	}
+
+	r, err := d.CreateRepository(ctx, name, user, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.applyRepoTemplate(ctx, r.Name(), t, user); err != nil {
+		d.logger.Error("failed to apply repository template", "template", t.Name, "name", r.Name(), "err", err)
+		// Cleanup the mess!
+		if derr := d.DeleteRepository(ctx, r.Name()); derr != nil {
+			err = errors.Join(err, derr)
+		}
+		return nil, fmt.Errorf("template %q: %w", t.Name, err)
+	}
+
+	d.cache.Delete(r.Name())
+	return d.Repository(ctx, r.Name())
+}
+
+// applyRepoTemplate makes the initial commit of the new repository from the
+// files of the template. The commit is made by the user who created it.
+func (d *Backend) applyRepoTemplate(ctx context.Context, name string, t config.RepoTemplate, user proto.User) error {
+	dir := t.Path
+	if t.Repo != "" {
+		tmp, err := os.MkdirTemp("", "soft-serve-template-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmp) // nolint: errcheck
+
+		src := utils.SanitizeRepo(t.Repo)
+		sp := filepath.Join(d.reposPath(), src+".git")
+		if err := d.WithRepository(ctx, src, false, func() error {
+			return git.ExportTree(ctx, sp, templateRef(t), tmp)
+		}); err != nil {
+			return err
+		}
+		dir = tmp
+	}
+
+	rp := filepath.Join(d.reposPath(), name+".git")
+	return d.WithRepository(ctx, name, true, func() error {
+		_, err := git.CommitDir(ctx, rp, dir, t.Branch, t.Message, d.templateSignature(user))
+		return err
+	})
+}
+
+// templateSignature returns the signature of the initial commits of the
+// repositories the user creates from templates. The email address is made of
+// the username and the host of the server.
+func (d *Backend) templateSignature(user proto.User) *git.Signature {
+	name, username := "Soft Serve", "soft-serve"
+	if user != nil {
+		name, username = user.Username(), user.Username()
+		if n := user.Name(); n != "" {
+			name = n
+		}
+	}
+
+	host := "localhost"
+	if u, err := url.Parse(d.cfg.SSH.PublicURL); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+
+	return &git.Signature{
+		Name:  name,
+		Email: username + "@" + host,
+		When:  time.Now(),
+	}
+}
+
+// templateRef returns the ref of the template repository the files come
+// from.
+func templateRef(t config.RepoTemplate) string {
+	if t.Ref != "" {
+		return t.Ref
+	}
+	return "HEAD"
+}
@@ -0,0 +1,73 @@
+package backend
+
+import (
+	"context"
+	"errors"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/webhook"
+)
+
+// ErrAlreadyOwner is returned when a repository is transferred to its owner.
+var ErrAlreadyOwner = errors.New("user already owns the repository")
+
+// TransferRepository makes the given user the owner of a repository. The
+// previous owner stays a collaborator with the keep access level, or loses
+// the access they had as the owner with access.NoAccess. The new owner isn't
+// a collaborator anymore, owners have admin access. It returns the previous
+// owner, nil for repositories without one.
+func (d *Backend) TransferRepository(ctx context.Context, name string, owner proto.User, keep access.AccessLevel) (proto.User, error) {
+	r, err := d.repoModel(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if r.UserID() == owner.ID() {
+		return nil, ErrAlreadyOwner
+	}
+
+	var prev proto.User
+	if id := r.UserID(); id > 0 {
+		prev, err = d.UserByID(ctx, id)
+		if err != nil && !errors.Is(err, proto.ErrUserNotFound) {
+			return nil, err
+		}
+	}
+
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		// Delete cache
+		defer d.cache.Delete(r.name)
+
+		if err := d.store.SetRepoUserIDByName(ctx, tx, r.name, owner.ID()); err != nil {
+			return err
+		}
+		if err := d.store.RemoveCollabByUsernameAndRepo(ctx, tx, owner.Username(), r.name); err != nil {
+			return err
+		}
+		if prev == nil {
+			return nil
+		}
+		if err := d.store.RemoveCollabByUsernameAndRepo(ctx, tx, prev.Username(), r.name); err != nil {
+			return err
+		}
+		if keep <= access.NoAccess {
+			return nil
+		}
+		return d.store.AddCollabByUsernameAndRepo(ctx, tx, prev.Username(), r.name, keep)
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	repo, err := d.Repository(ctx, r.name)
+	if err != nil {
+		return prev, err
+	}
+
+	wh, err := webhook.NewRepositoryEvent(ctx, proto.UserFromContext(ctx), repo, webhook.RepositoryEventActionTransfer)
+	if err != nil {
+		return prev, err
+	}
+
+	return prev, webhook.SendEvent(ctx, wh)
+}
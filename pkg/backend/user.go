@@ -42,8 +42,19 @@ func (d *Backend) AccessLevelByPublicKey(ctx context.Context, repo string, pk ss
 }
 
 // AccessLevelForUser returns the access level of a user for a repository.
-// TODO: user repository ownership
+// The options of the public key the user is connected with limit it, see
+// proto.KeyOptions.
 func (d *Backend) AccessLevelForUser(ctx context.Context, repo string, user proto.User) access.AccessLevel {
+	level := d.accessLevelForUser(ctx, repo, user)
+	if su := proto.UserFromContext(ctx); su != nil && user != nil && su.ID() == user.ID() {
+		level = proto.KeyOptionsFromContext(ctx).AccessLevel(utils.SanitizeRepo(repo), level)
+	}
+	return level
+}
+
+// accessLevelForUser returns the access level of a user for a repository.
+// TODO: user repository ownership
+func (d *Backend) accessLevelForUser(ctx context.Context, repo string, user proto.User) access.AccessLevel {
 	var username string
 	anon := d.AnonAccess(ctx)
 	if user != nil {
@@ -69,13 +80,19 @@ func (d *Backend) AccessLevelForUser(ctx context.Context, repo string, user prot
 			}
 		}
 
-		// If the user is a collaborator, they have return their access level.
+		// If the user is a collaborator, or a member of teams with access to
+		// the repository, they have the highest of their access levels.
 		collabAccess, isCollab, _ := d.IsCollaborator(ctx, repo, username)
-		if isCollab {
-			if anon > collabAccess {
-				return anon
+		teamAccess, inTeam := d.teamAccessLevel(ctx, r.Name(), user)
+		if isCollab || inTeam {
+			level := anon
+			if isCollab && collabAccess > level {
+				level = collabAccess
+			}
+			if inTeam && teamAccess > level {
+				level = teamAccess
 			}
-			return collabAccess
+			return level
 		}
 
 		// If the repository is private, the user has no access.
@@ -167,10 +184,26 @@ func (d *Backend) UserByID(ctx context.Context, id int64) (proto.User, error) {
 	}, nil
 }
 
-// UserByPublicKey finds a user by public key.
+// UserByPublicKey finds a user by public key. User certificates are validated
+// and matched by principal, see UserByCertificate. Other keys are synced from
+// the identity source first, see IdentityResolver.
 //
 // It implements backend.Backend.
 func (d *Backend) UserByPublicKey(ctx context.Context, pk ssh.PublicKey) (proto.User, error) {
+	if cert, ok := pk.(*ssh.Certificate); ok {
+		return d.UserByCertificate(ctx, cert)
+	}
+
+	if err := d.syncIdentity(ctx, pk); err != nil {
+		d.logger.Error("error syncing identity", "pk", sshutils.MarshalAuthorizedKey(pk), "error", err)
+		return nil, err
+	}
+
+	return d.userByStoredKey(ctx, pk)
+}
+
+// userByStoredKey finds the user a public key is stored with.
+func (d *Backend) userByStoredKey(ctx context.Context, pk ssh.PublicKey) (proto.User, error) {
 	var m models.User
 	var pks []ssh.PublicKey
 	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
@@ -322,6 +355,54 @@ func (d *Backend) RemovePublicKey(ctx context.Context, username string, pk ssh.P
 	)
 }
 
+// PublicKeyOptions returns the options of a public key, see proto.KeyOptions.
+// Unknown keys and certificates have no options.
+func (d *Backend) PublicKeyOptions(ctx context.Context, pk ssh.PublicKey) (proto.KeyOptions, error) {
+	if _, ok := pk.(*ssh.Certificate); ok {
+		return proto.KeyOptions{}, nil
+	}
+
+	var opts string
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		opts, err = d.store.GetPublicKeyOptions(ctx, tx, pk)
+		return err
+	}); err != nil {
+		err = db.WrapError(err)
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return proto.KeyOptions{}, nil
+		}
+		return proto.KeyOptions{}, err
+	}
+
+	return proto.ParseKeyOptions(opts)
+}
+
+// SetPublicKeyOptions sets the options of a public key of a user, zero
+// options remove them.
+func (d *Backend) SetPublicKeyOptions(ctx context.Context, username string, pk ssh.PublicKey, opts proto.KeyOptions) error {
+	username = strings.ToLower(username)
+	if err := utils.ValidateUsername(username); err != nil {
+		return err
+	}
+	if opts.Repo != "" {
+		opts.Repo = utils.SanitizeRepo(opts.Repo)
+		if err := utils.ValidateRepo(opts.Repo); err != nil {
+			return err
+		}
+	}
+
+	err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.SetPublicKeyOptionsByUsername(ctx, tx, username, pk, opts.String())
+		}),
+	)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return proto.ErrPublicKeyNotFound
+	}
+	return err
+}
+
 // ListPublicKeys lists the public keys of a user.
 func (d *Backend) ListPublicKeys(ctx context.Context, username string) ([]ssh.PublicKey, error) {
 	username = strings.ToLower(username)
@@ -357,6 +438,32 @@ func (d *Backend) SetUsername(ctx context.Context, username string, newUsername
 	)
 }
 
+// SetName sets the display name of a user.
+// Display names are unique across users.
+//
+// It implements backend.Backend.
+func (d *Backend) SetName(ctx context.Context, username string, name string) error {
+	username = strings.ToLower(username)
+	if err := utils.ValidateUsername(username); err != nil {
+		return err
+	}
+
+	if err := utils.ValidateName(name); err != nil {
+		return err
+	}
+
+	err := db.WrapError(
+		d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+			return d.store.SetNameByUsername(ctx, tx, username, name)
+		}),
+	)
+	if errors.Is(err, db.ErrDuplicateKey) {
+		return proto.ErrNameTaken
+	}
+
+	return err
+}
+
 // SetAdmin sets the admin flag of a user.
 //
 // It implements backend.Backend.
@@ -414,6 +521,15 @@ func (u *user) Username() string {
 	return u.user.Username
 }
 
+// Name implements proto.User.
+func (u *user) Name() string {
+	if u.user.Name.Valid {
+		return u.user.Name.String
+	}
+
+	return ""
+}
+
 // ID implements proto.User.
 func (u *user) ID() int64 {
 	return u.user.ID
@@ -1,6 +1,14 @@
 package backend
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
 	"github.com/charmbracelet/soft-serve/git"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
 )
@@ -15,9 +23,169 @@ func LatestFile(r proto.Repository, ref *git.Reference, pattern string) (string,
 	return git.LatestFile(repo, ref, pattern)
 }
 
+// readmePattern matches the README of a repository.
+const readmePattern = "[rR][eE][aA][dD][mM][eE]*"
+
 // Readme returns the repository's README.
 func Readme(r proto.Repository, ref *git.Reference) (readme string, path string, err error) {
-	pattern := "[rR][eE][aA][dD][mM][eE]*"
-	readme, path, err = LatestFile(r, ref, pattern)
+	readme, path, err = LatestFile(r, ref, readmePattern)
 	return
 }
+
+// ReadmeFile returns the file at the given path of the repository as its
+// README, and its path. The repository's README is returned instead when the
+// file is empty or doesn't exist.
+func ReadmeFile(r proto.Repository, ref *git.Reference, file string) (readme string, fp string, err error) {
+	if file != "" {
+		repo, err := r.Open()
+		if err != nil {
+			return "", "", err
+		}
+		if readme, err := fileAt(repo, ref, file); err == nil {
+			return readme, file, nil
+		}
+	}
+	return Readme(r, ref)
+}
+
+// DefaultReadmeMaxSize is the size, in bytes, of the largest README shown
+// whole unless the view settings of the repository say otherwise. Larger
+// READMEs are truncated until they're asked for whole.
+const DefaultReadmeMaxSize = 1 << 20
+
+// ReadmeFileLimit is ReadmeFile reading at most max bytes of the README when
+// max is positive, cut after its last whole line. It also returns the size of
+// the whole README, read before its content: the README is truncated when
+// it's larger than max.
+func ReadmeFileLimit(r proto.Repository, ref *git.Reference, file string, max int64) (readme string, fp string, size int64, err error) {
+	repo, err := r.Open()
+	if err != nil {
+		return "", "", 0, err
+	}
+	var te *git.TreeEntry
+	if file != "" {
+		if te, err = fileEntryAt(repo, ref, file); err == nil {
+			fp = file
+		}
+	}
+	if te == nil {
+		te, fp, err = git.LatestFileEntry(repo, ref, readmePattern)
+		if err != nil {
+			return "", "", 0, err
+		}
+	}
+	readme, err = readPrefix(te, max)
+	if err != nil {
+		return "", "", 0, err
+	}
+	return readme, fp, te.Size(), nil
+}
+
+// readPrefix returns the content of a file, only its first max bytes cut
+// after the last whole line when max is positive. Only the first bytes are
+// read from the repository.
+func readPrefix(te *git.TreeEntry, max int64) (string, error) {
+	if max <= 0 || te.Size() <= max {
+		bts, err := te.Contents()
+		return string(bts), err
+	}
+	buf := make([]byte, max)
+	n, err := te.File().ReadAt(buf, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	buf = buf[:n]
+	if i := bytes.LastIndexByte(buf, '\n'); i >= 0 {
+		buf = buf[:i+1]
+	}
+	return string(buf), nil
+}
+
+// CleanReadmePath cleans the path of a file shown as the README of a
+// repository. It's relative to the root of the repository and can't leave it.
+func CleanReadmePath(p string) (string, error) {
+	p = path.Clean("/" + strings.TrimSpace(p))[1:]
+	if p == "" {
+		return "", fmt.Errorf("readme path is empty")
+	}
+	return p, nil
+}
+
+// fileAt returns the content of the file at the given path of the reference,
+// or of HEAD when it's nil. It follows the symlinks of the repository.
+func fileAt(repo *git.Repository, ref *git.Reference, fp string) (string, error) {
+	te, err := fileEntryAt(repo, ref, fp)
+	if err != nil {
+		return "", err
+	}
+	bts, err := te.Contents()
+	if err != nil {
+		return "", err
+	}
+	return string(bts), nil
+}
+
+// fileEntryAt returns the tree entry of the file at the given path of the
+// reference, or of HEAD when it's nil, see fileAt.
+func fileEntryAt(repo *git.Repository, ref *git.Reference, fp string) (*git.TreeEntry, error) {
+	if ref == nil {
+		head, err := repo.HEAD()
+		if err != nil {
+			return nil, err
+		}
+		ref = head
+	}
+	dir, name := path.Split(fp)
+	t, err := repo.TreePath(ref, dir)
+	if err != nil {
+		return nil, err
+	}
+	te, err := t.TreeEntry(name)
+	if err != nil {
+		return nil, err
+	}
+	if te.IsTree() || te.IsCommit() {
+		return nil, fmt.Errorf("%s is not a file", fp)
+	}
+	if te.IsSymlink() {
+		target, err := te.Contents()
+		if err != nil {
+			return nil, err
+		}
+		te, err = t.TreeEntry(string(target))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return te, nil
+}
+
+// ReadmeDiff returns the diff of the repository's README between two
+// references. A README that only exists at one of the references shows up as
+// added or deleted, and a renamed README as a rename. It returns a nil diff if
+// neither reference has a README. The given file, if any, is the README at the
+// references it exists at, see ReadmeFile.
+func ReadmeDiff(r proto.Repository, base, head *git.Reference, file string) (*git.Diff, error) {
+	repo, err := r.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, 2)
+	for _, ref := range []*git.Reference{base, head} {
+		p := file
+		if _, err := fileAt(repo, ref, file); file == "" || err != nil {
+			if _, p, err = git.LatestFile(repo, ref, readmePattern); err != nil {
+				continue
+			}
+		}
+		if !slices.Contains(paths, p) {
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	return repo.DiffPaths(base.ID, head.ID, paths...)
+}
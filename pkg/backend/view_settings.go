@@ -0,0 +1,311 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/dustin/go-humanize"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// MaxViewSettings is the maximum size of the view settings file of a
+	// repository in bytes.
+	MaxViewSettings = 4096
+
+	// ViewSettingsFile is the file in the default branch of a repository its
+	// view settings are read from.
+	ViewSettingsFile = ".soft-serve/view.yaml"
+)
+
+// diffWhitespaceModes are the names of the whitespace modes of diffs in the
+// view settings.
+var diffWhitespaceModes = map[string]git.DiffWhitespace{
+	"show":                       git.DiffWhitespaceShow,
+	"ignore-all":                 git.DiffWhitespaceIgnoreAll,
+	"ignore-blank-lines":         git.DiffWhitespaceIgnoreBlankLines,
+	"ignore-all-and-blank-lines": git.DiffWhitespaceIgnoreAllAndBlankLines,
+}
+
+// ViewSettings are the defaults a repository declares for viewing it in the
+// TUI. Unset settings are nil, the defaults of the server apply.
+type ViewSettings struct {
+	// DiffContext is the number of context lines of the diffs.
+	DiffContext *int
+	// DiffWhitespace is how the diffs handle whitespace changes.
+	DiffWhitespace *git.DiffWhitespace
+	// Wrap wraps the long lines of files, they're cut otherwise.
+	Wrap *bool
+	// Theme is the Chroma style files are highlighted with.
+	Theme string
+	// Readme is the path of the file shown as the README.
+	Readme string
+	// ReadmeMaxSize is the size, in bytes, of the largest README shown
+	// whole, 0 when unset. See DefaultReadmeMaxSize.
+	ReadmeMaxSize int64
+}
+
+// DiffWhitespaceName returns the name of a whitespace mode of diffs in the
+// view settings.
+func DiffWhitespaceName(ws git.DiffWhitespace) string {
+	for name, w := range diffWhitespaceModes {
+		if w == ws {
+			return name
+		}
+	}
+	return "show"
+}
+
+// viewSettingsKeys are the keys of a view settings file, the keys of
+// sections after a dot.
+var viewSettingsKeys = map[string]bool{
+	"diff":            true,
+	"diff.context":    true,
+	"diff.whitespace": true,
+	"wrap":            true,
+	"theme":           true,
+	"readme":          true,
+	"readme_max_size": true,
+}
+
+// ParseViewSettings parses a view settings file, e.g.
+//
+//	diff:
+//	  context: 10
+//	  whitespace: ignore-all
+//	wrap: false
+//	theme: dracula
+//	readme: docs/OVERVIEW.md
+//	readme_max_size: 256KB
+//
+// Errors start with the line of the offending setting.
+func ParseViewSettings(data []byte) (ViewSettings, error) {
+	var file struct {
+		Diff struct {
+			Context    *int   `yaml:"context"`
+			Whitespace string `yaml:"whitespace"`
+		} `yaml:"diff"`
+		Wrap          *bool  `yaml:"wrap"`
+		Theme         string `yaml:"theme"`
+		Readme        string `yaml:"readme"`
+		ReadmeMaxSize string `yaml:"readme_max_size"`
+	}
+	var s ViewSettings
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return s, err
+	}
+	if root.Kind == 0 {
+		// An empty file.
+		return s, nil
+	}
+	if err := root.Decode(&file); err != nil {
+		return s, err
+	}
+
+	if c := file.Diff.Context; c != nil {
+		if *c < 0 || *c > git.MaxDiffContext {
+			return s, settingError(&root, "diff.context", "diff context must be between 0 and %d", git.MaxDiffContext)
+		}
+		s.DiffContext = c
+	}
+	if name := file.Diff.Whitespace; name != "" {
+		ws, ok := diffWhitespaceModes[name]
+		if !ok {
+			return s, settingError(&root, "diff.whitespace", "unknown diff whitespace mode %q", name)
+		}
+		s.DiffWhitespace = &ws
+	}
+	if file.Theme != "" {
+		if _, ok := styles.Registry[file.Theme]; !ok {
+			return s, settingError(&root, "theme", "unknown theme %q", file.Theme)
+		}
+		s.Theme = file.Theme
+	}
+	if file.Readme != "" {
+		p, err := CleanReadmePath(file.Readme)
+		if err != nil {
+			return s, settingError(&root, "readme", "%v", err)
+		}
+		s.Readme = p
+	}
+	if file.ReadmeMaxSize != "" {
+		n, err := humanize.ParseBytes(file.ReadmeMaxSize)
+		if err != nil || n == 0 || n > math.MaxInt64 {
+			return s, settingError(&root, "readme_max_size", "invalid readme max size %q: must be a positive size like 256KB", file.ReadmeMaxSize)
+		}
+		s.ReadmeMaxSize = int64(n)
+	}
+	s.Wrap = file.Wrap
+	return s, nil
+}
+
+// UnknownViewSettings returns the keys of a view settings file that aren't
+// settings, e.g. misspelled ones, prefixed with their line. They're ignored
+// by ParseViewSettings.
+func UnknownViewSettings(data []byte) []string {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil || len(root.Content) == 0 {
+		return nil
+	}
+	var unknown []string
+	var walk func(n *yaml.Node, prefix string)
+	walk = func(n *yaml.Node, prefix string) {
+		if n.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			name := prefix + k.Value
+			if !viewSettingsKeys[name] {
+				unknown = append(unknown, fmt.Sprintf("line %d: %s", k.Line, name))
+				continue
+			}
+			walk(v, name+".")
+		}
+	}
+	walk(root.Content[0], "")
+	return unknown
+}
+
+// settingError returns an error about a setting of a view settings file, the
+// keys of sections separated by dots, prefixed with its line.
+func settingError(root *yaml.Node, setting string, format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+	n := root
+	if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		n = n.Content[0]
+	}
+	for _, key := range strings.Split(setting, ".") {
+		var next *yaml.Node
+		if n.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if n.Content[i].Value == key {
+					next = n.Content[i+1]
+				}
+			}
+		}
+		if next == nil {
+			return err
+		}
+		n = next
+	}
+	return fmt.Errorf("line %d: %w", n.Line, err)
+}
+
+// viewSettingsEntry returns the view settings file of the tree of a
+// revision, nil when there's none.
+func viewSettingsEntry(gr *git.Repository, rev string) (*git.TreeEntry, error) {
+	tree, err := gr.LsTree(rev)
+	if err != nil {
+		return nil, err
+	}
+	te, err := tree.TreeEntry(ViewSettingsFile)
+	if err != nil || te.IsTree() {
+		return nil, nil
+	}
+	return te, nil
+}
+
+// ViewSettings returns the view settings of a repository, read from the
+// ViewSettingsFile of its default branch. Repositories without the file have
+// no settings.
+func (d *Backend) ViewSettings(ctx context.Context, name string) (ViewSettings, error) {
+	var s ViewSettings
+	r, err := d.repoModel(ctx, name)
+	if err != nil {
+		return s, err
+	}
+
+	gr, err := r.Open()
+	if err != nil {
+		return s, err
+	}
+	ref, err := gr.HEAD()
+	if err != nil {
+		// Empty repositories have no file.
+		return s, nil
+	}
+	te, err := viewSettingsEntry(gr, ref.ID)
+	if err != nil || te == nil {
+		return s, err
+	}
+	if te.Size() > MaxViewSettings {
+		d.logger.Warn("view settings file is too large", "repo", r.name, "size", te.Size())
+		return s, nil
+	}
+	data, err := te.Contents()
+	if err != nil {
+		return s, err
+	}
+
+	s, err = ParseViewSettings(data)
+	if err != nil {
+		return s, fmt.Errorf("%s: %w", ViewSettingsFile, err)
+	}
+	return s, nil
+}
+
+// ViewSettingsCheck is the result of checking the view settings file of a
+// repository at a revision.
+type ViewSettingsCheck struct {
+	// Settings are the settings of the file.
+	Settings ViewSettings
+	// Unknown are the keys of the file that aren't settings, see
+	// UnknownViewSettings.
+	Unknown []string
+	// ReadmeOverride is the readme set with SetReadmePath, it wins over the
+	// readme of the file.
+	ReadmeOverride string
+}
+
+// CheckViewSettings reads the ViewSettingsFile of a repository at a
+// revision, HEAD when it's empty, whether or not the settings apply yet. A
+// missing file is proto.ErrFileNotFound, and a file the settings can't be
+// read from, e.g. a too large one, is an error unlike with ViewSettings.
+func (d *Backend) CheckViewSettings(ctx context.Context, name string, rev string) (ViewSettingsCheck, error) {
+	var c ViewSettingsCheck
+	r, err := d.repoModel(ctx, name)
+	if err != nil {
+		return c, err
+	}
+
+	gr, err := r.Open()
+	if err != nil {
+		return c, err
+	}
+	if rev == "" {
+		rev = "HEAD"
+	}
+	commit, err := gr.CommitByRevision(rev)
+	if err != nil {
+		return c, err
+	}
+	te, err := viewSettingsEntry(gr, commit.ID.String())
+	if err != nil {
+		return c, err
+	}
+	if te == nil {
+		return c, proto.ErrFileNotFound
+	}
+	if te.Size() > MaxViewSettings {
+		return c, fmt.Errorf("%s is larger than %d bytes", ViewSettingsFile, MaxViewSettings)
+	}
+	data, err := te.Contents()
+	if err != nil {
+		return c, err
+	}
+
+	c.Settings, err = ParseViewSettings(data)
+	if err != nil {
+		return c, fmt.Errorf("%s: %w", ViewSettingsFile, err)
+	}
+	c.Unknown = UnknownViewSettings(data)
+	c.ReadmeOverride = r.repo.ReadmePath
+	return c, nil
+}
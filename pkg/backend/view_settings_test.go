@@ -0,0 +1,108 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/git"
+)
+
+func TestParseViewSettings(t *testing.T) {
+	s, err := ParseViewSettings([]byte("diff:\n  context: 10\n  whitespace: ignore-all\nwrap: false\ntheme: dracula\n"))
+	if err != nil {
+		t.Fatalf("ParseViewSettings() error = %v", err)
+	}
+	if s.DiffContext == nil || *s.DiffContext != 10 {
+		t.Errorf("DiffContext = %v, want 10", s.DiffContext)
+	}
+	if s.DiffWhitespace == nil || *s.DiffWhitespace != git.DiffWhitespaceIgnoreAll {
+		t.Errorf("DiffWhitespace = %v, want ignore-all", s.DiffWhitespace)
+	}
+	if s.Wrap == nil || *s.Wrap {
+		t.Errorf("Wrap = %v, want false", s.Wrap)
+	}
+	if s.Theme != "dracula" {
+		t.Errorf("Theme = %q, want dracula", s.Theme)
+	}
+
+	s, err = ParseViewSettings([]byte("wrap: true\n"))
+	if err != nil {
+		t.Fatalf("ParseViewSettings() error = %v", err)
+	}
+	if s.DiffContext != nil || s.DiffWhitespace != nil || s.Theme != "" || s.Readme != "" {
+		t.Errorf("ParseViewSettings() = %+v, want only wrap", s)
+	}
+
+	for _, data := range []string{
+		"diff:\n  context: -1\n",
+		"diff:\n  context: 51\n",
+		"diff:\n  whitespace: nope\n",
+		"theme: nope\n",
+		"wrap: [\n",
+		"readme: /\n",
+		"readme_max_size: 0\n",
+		"readme_max_size: lots\n",
+	} {
+		if _, err := ParseViewSettings([]byte(data)); err == nil {
+			t.Errorf("ParseViewSettings(%q) error = nil, want an error", data)
+		}
+	}
+}
+
+func TestParseViewSettingsReadme(t *testing.T) {
+	for data, want := range map[string]string{
+		"readme: OVERVIEW.md\n":           "OVERVIEW.md",
+		"readme: /docs/./OVERVIEW.md\n":   "docs/OVERVIEW.md",
+		"readme: ../../etc/OVERVIEW.md\n": "etc/OVERVIEW.md",
+	} {
+		s, err := ParseViewSettings([]byte(data))
+		if err != nil {
+			t.Fatalf("ParseViewSettings(%q) error = %v", data, err)
+		}
+		if s.Readme != want {
+			t.Errorf("ParseViewSettings(%q) Readme = %q, want %q", data, s.Readme, want)
+		}
+	}
+}
+
+func TestParseViewSettingsReadmeMaxSize(t *testing.T) {
+	for data, want := range map[string]int64{
+		"readme_max_size: 4096\n":  4096,
+		"readme_max_size: 256KB\n": 256000,
+		"readme_max_size: 2 MiB\n": 2 << 20,
+		"readme: OVERVIEW.md\n":    0,
+	} {
+		s, err := ParseViewSettings([]byte(data))
+		if err != nil {
+			t.Fatalf("ParseViewSettings(%q) error = %v", data, err)
+		}
+		if s.ReadmeMaxSize != want {
+			t.Errorf("ParseViewSettings(%q) ReadmeMaxSize = %d, want %d", data, s.ReadmeMaxSize, want)
+		}
+	}
+}
+
+func TestParseViewSettingsLines(t *testing.T) {
+	for data, want := range map[string]string{
+		"wrap: true\ndiff:\n  context: 51\n": "line 3: diff context must be between 0 and 50",
+		"\n\ntheme: nope\n":                  `line 3: unknown theme "nope"`,
+		"wrap: true\nwrap: [\n":              "yaml: line 2: did not find expected node content",
+		"diff:\n  context: ten\n":            "yaml: unmarshal errors:\n  line 2: cannot unmarshal !!str `ten` into int",
+	} {
+		_, err := ParseViewSettings([]byte(data))
+		if err == nil || err.Error() != want {
+			t.Errorf("ParseViewSettings(%q) error = %v, want %q", data, err, want)
+		}
+	}
+
+	if s, err := ParseViewSettings(nil); err != nil || s.Wrap != nil {
+		t.Errorf("ParseViewSettings(nil) = %+v, %v, want no settings", s, err)
+	}
+}
+
+func TestUnknownViewSettings(t *testing.T) {
+	got := UnknownViewSettings([]byte("diff:\n  contxt: 10\nwrap: false\nthem: dracula\n"))
+	want := []string{"line 2: diff.contxt", "line 4: them"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("UnknownViewSettings() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+package backend
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"golang.org/x/crypto/ssh"
+)
+
+// WatchedReposPreference is the name of the preference that holds the
+// comma-separated repositories a public key watches the pushes to in the
+// terminal UI.
+const WatchedReposPreference = "ui.watched"
+
+// Watcher is a watcher of a repository, a user, or a public key without a
+// user for anonymous watchers.
+type Watcher struct {
+	User      proto.User
+	PublicKey ssh.PublicKey
+}
+
+// String returns the username of the watcher, or the fingerprint of its key.
+func (w Watcher) String() string {
+	if w.User != nil {
+		return w.User.Username()
+	}
+	return ssh.FingerprintSHA256(w.PublicKey)
+}
+
+// Watchers returns the watchers of a repository, users first ordered by
+// username. The keys of a user count once, and keys that can't read the
+// repository anymore aren't watchers.
+func (d *Backend) Watchers(ctx context.Context, repo string) ([]Watcher, error) {
+	repo = utils.SanitizeRepo(repo)
+	var ms []models.Preference
+	if err := d.db.TransactionContext(ctx, func(tx *db.Tx) error {
+		var err error
+		ms, err = d.store.ListPreferencesByName(ctx, tx, WatchedReposPreference)
+		return err
+	}); err != nil {
+		return nil, db.WrapError(err)
+	}
+
+	users := make(map[int64]bool)
+	watchers := make([]Watcher, 0)
+	for _, m := range ms {
+		if !isWatched(m.Value, repo) {
+			continue
+		}
+		pk, _, err := sshutils.ParseAuthorizedKey(m.PublicKey)
+		if err != nil {
+			d.logger.Debug("error parsing watcher key", "repo", repo, "err", err)
+			continue
+		}
+		if d.AccessLevelByPublicKey(ctx, repo, pk) < access.ReadOnlyAccess {
+			continue
+		}
+
+		w := Watcher{PublicKey: pk}
+		if user, err := d.UserByPublicKey(ctx, pk); err == nil {
+			if users[user.ID()] {
+				continue
+			}
+			users[user.ID()] = true
+			w.User = user
+		}
+		watchers = append(watchers, w)
+	}
+
+	sort.SliceStable(watchers, func(i, j int) bool {
+		wi, wj := watchers[i], watchers[j]
+		if (wi.User == nil) != (wj.User == nil) {
+			return wi.User != nil
+		}
+		return wi.String() < wj.String()
+	})
+	return watchers, nil
+}
+
+// isWatched returns whether the repository is in the comma-separated list of
+// watched repositories.
+func isWatched(spec string, repo string) bool {
+	for _, name := range strings.Split(spec, ",") {
+		if strings.TrimSpace(name) == repo {
+			return true
+		}
+	}
+	return false
+}
@@ -1,15 +1,24 @@
 package config
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
+	"unicode/utf8"
 
 	"github.com/caarlos0/env/v11"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"github.com/charmbracelet/soft-serve/pkg/storage"
 	"golang.org/x/crypto/ssh"
 	"gopkg.in/yaml.v3"
 )
@@ -24,6 +33,12 @@ type SSHConfig struct {
 	// PublicURL is the public URL of the SSH server.
 	PublicURL string `env:"PUBLIC_URL" yaml:"public_url"`
 
+	// CloneURL is the template of the clone URLs over SSH, e.g.
+	// "ssh://git@{host}:{port}/{repo}". The {scheme}, {host}, and {port}
+	// variables come from the public URL. The public URL followed by the
+	// repository is used when it's empty.
+	CloneURL string `env:"CLONE_URL" yaml:"clone_url"`
+
 	// KeyPath is the path to the SSH server's private key.
 	KeyPath string `env:"KEY_PATH" yaml:"key_path"`
 
@@ -35,16 +50,63 @@ type SSHConfig struct {
 
 	// IdleTimeout is the number of seconds a connection can be idle before it is closed.
 	IdleTimeout int `env:"IDLE_TIMEOUT" yaml:"idle_timeout"`
+
+	// TrustedUserCAKeys is a list of certificate authority public keys, or
+	// paths to files containing them, that are trusted to sign user
+	// certificates.
+	TrustedUserCAKeys []string `env:"TRUSTED_USER_CA_KEYS" envSeparator:"\n" yaml:"trusted_user_ca_keys"`
+
+	// RevokedCertificates is a list of revoked user certificates. An entry
+	// matches a certificate serial number, key ID, or the SHA256 fingerprint
+	// of the certified key.
+	RevokedCertificates []string `env:"REVOKED_CERTIFICATES" envSeparator:"\n" yaml:"revoked_certificates"`
+
+	// Interactive is what interactive sessions without a command run by
+	// default, the terminal UI or a command shell. Valid values are "tui"
+	// and "shell".
+	Interactive string `env:"INTERACTIVE" yaml:"interactive"`
+
+	// Pager is when the output of the commands of interactive sessions is
+	// shown in a pager: "auto" when it doesn't fit the terminal, "always",
+	// or "never". The output of non-interactive sessions is never paged.
+	Pager string `env:"PAGER" yaml:"pager"`
 }
 
+const (
+	// InteractiveTUI runs the terminal UI in interactive sessions.
+	InteractiveTUI = "tui"
+
+	// InteractiveShell runs a command shell in interactive sessions.
+	InteractiveShell = "shell"
+)
+
+const (
+	// PagerAuto pages the output of commands that doesn't fit the terminal.
+	PagerAuto = "auto"
+
+	// PagerAlways pages the output of all the commands.
+	PagerAlways = "always"
+
+	// PagerNever never pages the output of commands.
+	PagerNever = "never"
+)
+
 // GitConfig is the Git daemon configuration for the server.
 type GitConfig struct {
+	// Enabled enables the Git daemon. It serves public repositories read-only
+	// over the anonymous git:// protocol.
+	Enabled bool `env:"ENABLED" yaml:"enabled"`
+
 	// ListenAddr is the address on which the Git daemon will listen.
 	ListenAddr string `env:"LISTEN_ADDR" yaml:"listen_addr"`
 
 	// PublicURL is the public URL of the Git daemon server.
 	PublicURL string `env:"PUBLIC_URL" yaml:"public_url"`
 
+	// CloneURL is the template of the clone URLs over the Git daemon, like
+	// the SSH clone URL.
+	CloneURL string `env:"CLONE_URL" yaml:"clone_url"`
+
 	// MaxTimeout is the maximum number of seconds a connection can take.
 	MaxTimeout int `env:"MAX_TIMEOUT" yaml:"max_timeout"`
 
@@ -53,6 +115,10 @@ type GitConfig struct {
 
 	// MaxConnections is the maximum number of concurrent connections.
 	MaxConnections int `env:"MAX_CONNECTIONS" yaml:"max_connections"`
+
+	// RateLimit is the maximum number of connections per minute from a
+	// client address. A value of 0 means no limit.
+	RateLimit int `env:"RATE_LIMIT" yaml:"rate_limit"`
 }
 
 // HTTPConfig is the HTTP configuration for the server.
@@ -68,6 +134,37 @@ type HTTPConfig struct {
 
 	// PublicURL is the public URL of the HTTP server.
 	PublicURL string `env:"PUBLIC_URL" yaml:"public_url"`
+
+	// CloneURL is the template of the clone URLs over HTTP, like the SSH
+	// clone URL.
+	CloneURL string `env:"CLONE_URL" yaml:"clone_url"`
+
+	// CORS is the CORS configuration of the HTTP server.
+	CORS CORSConfig `envPrefix:"CORS_" yaml:"cors"`
+
+	// API is the configuration of the read-only JSON API.
+	API APIConfig `envPrefix:"API_" yaml:"api"`
+}
+
+// APIConfig is the configuration of the read-only JSON API of the HTTP server.
+type APIConfig struct {
+	// Path is the base path of the API, e.g. "/api". The API is served under
+	// its versions, like "/api/v1". It shadows the repositories under the
+	// path. An empty path, the default, disables the API.
+	Path string `env:"PATH" yaml:"path"`
+
+	// RateLimit is the maximum number of API requests per minute from a
+	// client address. A value of 0 means no limit.
+	RateLimit int `env:"RATE_LIMIT" yaml:"rate_limit"`
+}
+
+// CORSConfig is the CORS configuration of the HTTP server. Browsers on the
+// allowed origins can read public repositories, never private ones.
+type CORSConfig struct {
+	// AllowedOrigins are the origins, e.g. "https://example.com", allowed to
+	// make cross-origin requests. "*" allows any origin. No origin is allowed
+	// by default.
+	AllowedOrigins []string `env:"ALLOWED_ORIGINS" yaml:"allowed_origins"`
 }
 
 // StatsConfig is the configuration for the stats server.
@@ -82,15 +179,35 @@ type LogConfig struct {
 	// Valid values are "json", "logfmt", and "text".
 	Format string `env:"FORMAT" yaml:"format"`
 
+	// Level is the minimum level of the logs.
+	// Valid values are "debug", "info", "warn", and "error". Debug mode
+	// always logs at the debug level.
+	Level string `env:"LEVEL" yaml:"level"`
+
 	// Time format for the log `ts` field.
 	// Format must be described in Golang's time format.
 	TimeFormat string `env:"TIME_FORMAT" yaml:"time_format"`
 
-	// Path to a file to write logs to.
+	// Path to a file to write logs to, or "stdout" or "stderr".
 	// If not set, logs will be written to stderr.
 	Path string `env:"PATH" yaml:"path"`
 }
 
+// Log level values.
+const (
+	// LogLevelDebug logs everything, including the timing of operations.
+	LogLevelDebug = "debug"
+
+	// LogLevelInfo logs the operations of the server.
+	LogLevelInfo = "info"
+
+	// LogLevelWarn logs warnings and errors.
+	LogLevelWarn = "warn"
+
+	// LogLevelError logs errors only.
+	LogLevelError = "error"
+)
+
 // DBConfig is the database connection configuration.
 type DBConfig struct {
 	// Driver is the driver for the database.
@@ -108,11 +225,997 @@ type LFSConfig struct {
 	// SSHEnabled is whether or not Git LFS over SSH is enabled.
 	// This is only used if LFS is enabled.
 	SSHEnabled bool `env:"SSH_ENABLED" yaml:"ssh_enabled"`
+
+	// ArchiveObjects is whether repository exports include the LFS objects
+	// instead of their pointers by default.
+	ArchiveObjects bool `env:"ARCHIVE_OBJECTS" yaml:"archive_objects"`
 }
 
 // JobsConfig is the configuration for cron jobs.
 type JobsConfig struct {
 	MirrorPull string `env:"MIRROR_PULL" yaml:"mirror_pull"`
+
+	// CommitGraph is the schedule of the commit-graph updates of the
+	// repositories, see RepoConfig.CommitGraph.
+	CommitGraph string `env:"COMMIT_GRAPH" yaml:"commit_graph"`
+
+	// Housekeeping is the schedule of the housekeeping of the repositories,
+	// see HousekeepingConfig.
+	Housekeeping string `env:"HOUSEKEEPING" yaml:"housekeeping"`
+
+	// Archive is the schedule of the archival of the idle repositories, see
+	// RepoConfig.ArchiveAfter.
+	Archive string `env:"ARCHIVE" yaml:"archive"`
+
+	// FollowerSync is the schedule of the syncs of a follower with its
+	// primary, see FollowerConfig.
+	FollowerSync string `env:"FOLLOWER_SYNC" yaml:"follower_sync"`
+
+	// RepoSizes is the schedule of the snapshots of the size of the
+	// repositories, see "repo info --health".
+	RepoSizes string `env:"REPO_SIZES" yaml:"repo_sizes"`
+}
+
+// Housekeeping tasks.
+const (
+	// HousekeepingGC runs git gc, which does all of the other tasks at once.
+	HousekeepingGC = "gc"
+
+	// HousekeepingCommitGraph writes the commit-graph.
+	HousekeepingCommitGraph = "commit-graph"
+
+	// HousekeepingPrune removes the unreachable loose objects older than two
+	// weeks.
+	HousekeepingPrune = "prune"
+
+	// HousekeepingRepack packs the loose objects and removes the redundant
+	// packs, without rewriting the existing packs.
+	HousekeepingRepack = "repack"
+
+	// HousekeepingPackRefs packs the references into the packed-refs file.
+	HousekeepingPackRefs = "pack-refs"
+
+	// HousekeepingFsck verifies the objects with git fsck. It fails when
+	// objects are missing or corrupt, see HousekeepingConfig.Quarantine.
+	HousekeepingFsck = "fsck"
+)
+
+// HousekeepingTasks are the valid housekeeping tasks in the order they run.
+var HousekeepingTasks = []string{
+	HousekeepingFsck,
+	HousekeepingGC,
+	HousekeepingRepack,
+	HousekeepingPrune,
+	HousekeepingPackRefs,
+	HousekeepingCommitGraph,
+}
+
+// HousekeepingConfig is the configuration of the housekeeping of the
+// repositories, the Git maintenance tasks that run on the schedule of
+// JobsConfig.Housekeeping.
+type HousekeepingConfig struct {
+	// Tasks are the tasks run on every repository. Housekeeping is disabled
+	// when it's empty.
+	Tasks []string `env:"TASKS" yaml:"tasks"`
+
+	// Jitter is the maximum number of seconds each repository waits for
+	// before its tasks run, so that they don't all run at once.
+	Jitter int `env:"JITTER" yaml:"jitter"`
+
+	// Quarantine archives the repositories the fsck task finds missing or
+	// corrupt objects in, so that they're read-only until an admin looks at
+	// them, instead of only failing the task.
+	Quarantine bool `env:"QUARANTINE" yaml:"quarantine"`
+
+	// Repos are the tasks of the repositories whose names match a glob, see
+	// TasksFor. They can only be set in the config file.
+	Repos []HousekeepingRepo `yaml:"repos"`
+}
+
+// HousekeepingRepo are the housekeeping tasks of the repositories whose names
+// match a glob.
+type HousekeepingRepo struct {
+	// Match is a glob repository names must match, e.g. "internal/*". A "*"
+	// doesn't match slashes.
+	Match string `yaml:"match"`
+
+	// Tasks are the tasks run on the repositories instead of the global ones.
+	// An empty list disables housekeeping for them.
+	Tasks []string `yaml:"tasks"`
+}
+
+// TasksFor returns the housekeeping tasks of a repository in the order they
+// run. The last entry of Repos whose glob matches the name wins over the
+// global tasks.
+func (c HousekeepingConfig) TasksFor(name string) []string {
+	tasks := c.Tasks
+	for _, r := range c.Repos {
+		if ok, _ := path.Match(r.Match, name); ok {
+			tasks = r.Tasks
+		}
+	}
+	var ordered []string
+	for _, t := range HousekeepingTasks {
+		if slices.Contains(tasks, t) {
+			ordered = append(ordered, t)
+		}
+	}
+	return ordered
+}
+
+// validHousekeepingTasks returns an error if a task isn't a housekeeping
+// task.
+func validHousekeepingTasks(tasks []string) error {
+	for _, t := range tasks {
+		if !slices.Contains(HousekeepingTasks, t) {
+			return fmt.Errorf("invalid housekeeping task %q: must be one of %s", t, strings.Join(HousekeepingTasks, ", "))
+		}
+	}
+	return nil
+}
+
+// validAuditSink returns an error if the sink isn't valid.
+func validAuditSink(s AuditSink) error {
+	switch s.Type {
+	case AuditSinkFile, AuditSinkWebhook:
+		if s.Address == "" {
+			return fmt.Errorf("%s sink address must not be empty", s.Type)
+		}
+	case AuditSinkSyslog:
+	default:
+		return fmt.Errorf("invalid type %q: must be one of %s, %s, %s", s.Type, AuditSinkFile, AuditSinkSyslog, AuditSinkWebhook)
+	}
+	switch s.Type {
+	case AuditSinkSyslog:
+		if s.Address == "" {
+			break
+		}
+		u, err := url.Parse(s.Address)
+		if err != nil || !slices.Contains([]string{"udp", "tcp", "unix", "unixgram"}, u.Scheme) {
+			return fmt.Errorf("invalid syslog address %q: must be a udp, tcp, unix, or unixgram url", s.Address)
+		}
+	case AuditSinkWebhook:
+		if u, err := url.Parse(s.Address); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("invalid webhook url %q: must be an http or https url", s.Address)
+		}
+	}
+	switch s.Format {
+	case "", AuditFormatJSON, AuditFormatCEF:
+	default:
+		return fmt.Errorf("invalid format %q: must be %s or %s", s.Format, AuditFormatJSON, AuditFormatCEF)
+	}
+	types := proto.EventTypes()
+	for _, t := range s.Events {
+		if !slices.Contains(types, t) {
+			return fmt.Errorf("invalid event type %q: must be one of %s", t, strings.Join(types, ", "))
+		}
+	}
+	return nil
+}
+
+// Repository visibility values.
+const (
+	// PublicVisibility makes new repositories public.
+	PublicVisibility = "public"
+
+	// PrivateVisibility makes new repositories private.
+	PrivateVisibility = "private"
+)
+
+// RepoConfig is the configuration for repositories.
+type RepoConfig struct {
+	// DefaultVisibility is the visibility of newly created repositories.
+	// Valid values are "public" and "private".
+	DefaultVisibility string `env:"DEFAULT_VISIBILITY" yaml:"default_visibility"`
+
+	// OperationTimeout is the maximum number of seconds an expensive
+	// repository operation, like blame or archive, can take. Zero means no
+	// limit.
+	OperationTimeout int `env:"OPERATION_TIMEOUT" yaml:"operation_timeout"`
+
+	// PackCompression is the zlib compression level, from 0 to 9, of the
+	// packs sent to clients that fetch or clone. -1 uses the default of git.
+	// Lower levels use less CPU and send more data.
+	PackCompression int `env:"PACK_COMPRESSION" yaml:"pack_compression"`
+
+	// KeepAlive is the number of seconds between the keepalive messages sent
+	// to clients that fetch or clone while the server prepares the pack, so
+	// that slow clones of large repositories aren't dropped as idle. 0 uses
+	// the default of git, 5 seconds, and -1 disables them.
+	KeepAlive int `env:"KEEPALIVE" yaml:"keepalive"`
+
+	// Name are the rules repository names must follow when a repository is
+	// created, imported, or renamed.
+	Name RepoNameConfig `envPrefix:"NAME_" yaml:"name"`
+
+	// CommitMessage is the policy the messages of the commits pushed to any
+	// repository must follow.
+	CommitMessage CommitMessageConfig `envPrefix:"COMMIT_MESSAGE_" yaml:"commit_message"`
+
+	// Refs are the limits of the references of any repository, enforced on
+	// pushes.
+	Refs RefsConfig `envPrefix:"REFS_" yaml:"refs"`
+
+	// CommitGraph keeps commit-graph files up to date on the schedule of the
+	// commit-graph job, and makes gc write them along with pack bitmap
+	// indexes. They speed up reading the history of large repositories.
+	CommitGraph bool `env:"COMMIT_GRAPH" yaml:"commit_graph"`
+
+	// Storage is the name of the storage repositories are kept in, see
+	// storage.RegisterRepoStorage. The default "local" keeps them in the
+	// repos directory of the data path.
+	Storage string `env:"STORAGE" yaml:"storage"`
+
+	// Concurrency limits the git operations running at the same time.
+	Concurrency ConcurrencyConfig `envPrefix:"CONCURRENCY_" yaml:"concurrency"`
+
+	// ArchiveAfter is the number of days without pushes after which
+	// repositories are archived on the schedule of the archive job. Archived
+	// repositories are read-only. Zero disables it.
+	ArchiveAfter int `env:"ARCHIVE_AFTER" yaml:"archive_after"`
+
+	// Defaults are the settings of new repositories whose names match a glob,
+	// see DefaultsFor. They can only be set in the config file.
+	Defaults []RepoDefaults `yaml:"defaults"`
+
+	// Templates are the templates new repositories can be created from with
+	// "repo create --template". They can only be set in the config file.
+	Templates []RepoTemplate `yaml:"templates"`
+}
+
+// DefaultTemplateMessage is the message of the initial commit of the
+// repositories created from a template that doesn't set one.
+const DefaultTemplateMessage = "Initial commit"
+
+// RepoTemplate is a named set of files, like a README, a LICENSE, and a
+// .gitignore, the initial commit of new repositories is made of. The files
+// come from either a hosted repository or a directory of the server.
+type RepoTemplate struct {
+	// Name is the name the template is created from.
+	Name string `yaml:"name"`
+
+	// Description describes the template in the list of templates.
+	Description string `yaml:"description"`
+
+	// Repo is the hosted repository the files come from, as of Ref, HEAD by
+	// default. The history of the repository isn't copied.
+	Repo string `yaml:"repo"`
+	Ref  string `yaml:"ref"`
+
+	// Path is the directory the files come from, relative to the data
+	// directory unless absolute.
+	Path string `yaml:"path"`
+
+	// Branch is the default branch of the new repositories, the one the
+	// initial commit is on. The default branch of new repositories is used
+	// when it's empty.
+	Branch string `yaml:"branch"`
+
+	// Message is the message of the initial commit, DefaultTemplateMessage
+	// when it's empty.
+	Message string `yaml:"message"`
+}
+
+// validBranchName returns whether name is a valid branch name, following the
+// rules of git-check-ref-format(1).
+func validBranchName(name string) bool {
+	if name == "" || name == "@" || strings.HasPrefix(name, "-") ||
+		strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/") ||
+		strings.HasSuffix(name, ".") || strings.HasSuffix(name, ".lock") ||
+		strings.Contains(name, "..") || strings.Contains(name, "//") ||
+		strings.Contains(name, "@{") || strings.Contains(name, "/.") ||
+		strings.HasPrefix(name, ".") {
+		return false
+	}
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(" ~^:?*[\\", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Template returns the repository template with the given name.
+func (c RepoConfig) Template(name string) (RepoTemplate, bool) {
+	for _, t := range c.Templates {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return RepoTemplate{}, false
+}
+
+// ConcurrencyConfig limits the git operations, fetches, clones, archives,
+// and pushes, running at the same time over SSH, HTTP, and the Git daemon.
+// The operations over the limits wait for the others to finish, then give up.
+// Admins are exempt.
+type ConcurrencyConfig struct {
+	// Max is the maximum number of git operations running on the server.
+	// Zero means no limit.
+	Max int `env:"MAX" yaml:"max"`
+
+	// PerRepo is the maximum number of git operations running on a
+	// repository. Zero means no limit.
+	PerRepo int `env:"PER_REPO" yaml:"per_repo"`
+
+	// QueueTimeout is the number of seconds an operation waits for others to
+	// finish before it's rejected. Zero rejects it right away.
+	QueueTimeout int `env:"QUEUE_TIMEOUT" yaml:"queue_timeout"`
+}
+
+// RepoDefaults are the default settings of the new repositories whose names
+// match a glob. Empty settings are left unchanged.
+type RepoDefaults struct {
+	// Match is a glob repository names must match, e.g. "internal/*". A "*"
+	// doesn't match slashes.
+	Match string `yaml:"match"`
+
+	// Visibility is the visibility of the repositories, "public" or
+	// "private".
+	Visibility string `yaml:"visibility"`
+
+	// Description is a template of the description. {{ .Repo }} is the name
+	// of the repository.
+	Description string `yaml:"description"`
+
+	// ProjectName is a template of the project name, like Description.
+	ProjectName string `yaml:"project_name"`
+
+	// Hidden hides the repositories from the UI.
+	Hidden bool `yaml:"hidden"`
+}
+
+// Private returns true if the repository is private.
+func (d RepoDefaults) Private() bool {
+	return d.Visibility == PrivateVisibility
+}
+
+// DefaultsFor returns the settings of a new repository. Every default whose
+// glob matches the name applies in order, so later ones override the earlier
+// ones. The visibility falls back to DefaultVisibility, and the templates are
+// rendered.
+func (c RepoConfig) DefaultsFor(name string) (RepoDefaults, error) {
+	d := RepoDefaults{Visibility: c.DefaultVisibility}
+	for _, r := range c.Defaults {
+		if ok, _ := path.Match(r.Match, name); !ok {
+			continue
+		}
+		if r.Visibility != "" {
+			d.Visibility = r.Visibility
+		}
+		if r.Description != "" {
+			d.Description = r.Description
+		}
+		if r.ProjectName != "" {
+			d.ProjectName = r.ProjectName
+		}
+		d.Hidden = d.Hidden || r.Hidden
+	}
+
+	for _, v := range []*string{&d.Description, &d.ProjectName} {
+		s, err := renderRepoDefault(*v, name)
+		if err != nil {
+			return RepoDefaults{}, err
+		}
+		*v = s
+	}
+
+	return d, nil
+}
+
+// renderRepoDefault renders a template of a repository default.
+func renderRepoDefault(text, name string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := template.New("default").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, struct {
+		Repo string
+	}{name}); err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
+
+// RepoNameConfig are the rules repository names must follow on top of the
+// allowed characters. No rule is enforced by default.
+type RepoNameConfig struct {
+	// Pattern is a regular expression repository names must match, e.g.
+	// "^[a-z0-9-]+$". Anchor it to match whole names.
+	Pattern string `env:"PATTERN" yaml:"pattern"`
+
+	// MaxLength is the maximum number of characters of repository names.
+	// Zero means no limit.
+	MaxLength int `env:"MAX_LENGTH" yaml:"max_length"`
+
+	// Prefixes are the prefixes repository names must start with one of, e.g.
+	// "team-a/".
+	Prefixes []string `env:"PREFIXES" yaml:"prefixes"`
+
+	// Lowercase requires repository names to be lower case.
+	Lowercase bool `env:"LOWERCASE" yaml:"lowercase"`
+}
+
+// RefsConfig are the limits of the references of repositories. They keep
+// listing the branches and tags of a repository fast, and pushes from
+// creating millions of references. No limit is enforced by default.
+type RefsConfig struct {
+	// MaxCount is the maximum number of references of a repository.
+	// Repositories already over it keep their references but can't get new
+	// ones. Zero means no limit.
+	MaxCount int `env:"MAX_COUNT" yaml:"max_count"`
+
+	// MaxNameLength is the maximum number of bytes of the full name of a new
+	// reference, e.g. "refs/heads/main". Zero means no limit.
+	MaxNameLength int `env:"MAX_NAME_LENGTH" yaml:"max_name_length"`
+}
+
+// Check returns an error citing the first rule the repository name breaks.
+func (c RepoNameConfig) Check(name string) error {
+	if c.Lowercase && name != strings.ToLower(name) {
+		return fmt.Errorf("must be lower case")
+	}
+
+	if c.MaxLength > 0 && utf8.RuneCountInString(name) > c.MaxLength {
+		return fmt.Errorf("must be at most %d characters long", c.MaxLength)
+	}
+
+	if len(c.Prefixes) > 0 {
+		ok := false
+		for _, p := range c.Prefixes {
+			if strings.HasPrefix(name, p) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			quoted := make([]string, len(c.Prefixes))
+			for i, p := range c.Prefixes {
+				quoted[i] = strconv.Quote(p)
+			}
+			return fmt.Errorf("must start with %s", strings.Join(quoted, " or "))
+		}
+	}
+
+	if c.Pattern != "" {
+		re, err := regexp.Compile(c.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", c.Pattern, err)
+		}
+		if !re.MatchString(name) {
+			return fmt.Errorf("must match %q", c.Pattern)
+		}
+	}
+
+	return nil
+}
+
+// CommitMessageExemptions are the kinds of commits that can be exempt from
+// the commit message policy.
+var CommitMessageExemptions = []string{"merges", "reverts", "fixups"}
+
+// CommitMessageConfig is the policy the messages of pushed commits must
+// follow. It's checked by the pre-receive hook, only on the commits a push
+// adds. No policy is enforced by default.
+type CommitMessageConfig struct {
+	// Pattern is a regular expression commit messages must match, e.g.
+	// `^(feat|fix|docs|chore)(\(.+\))?!?: ` for Conventional Commits or
+	// `[A-Z]+-[0-9]+` for a ticket id. It's matched against the whole
+	// message, "^" and "$" match at lines with the (?m) flag.
+	Pattern string `env:"PATTERN" yaml:"pattern"`
+
+	// Hint is shown to the pushers of rejected commits, e.g. a link to the
+	// guidelines.
+	Hint string `env:"HINT" yaml:"hint"`
+
+	// Exempt are the kinds of commits the policy doesn't apply to, out of
+	// CommitMessageExemptions: merge commits, commits made by git revert,
+	// and fixup! or squash! commits.
+	Exempt []string `env:"EXEMPT" yaml:"exempt"`
+}
+
+// IsExempt returns true if the commit with the given message and number of
+// parents is exempt from the policy.
+func (c CommitMessageConfig) IsExempt(message string, parents int) bool {
+	for _, e := range c.Exempt {
+		switch e {
+		case "merges":
+			if parents > 1 {
+				return true
+			}
+		case "reverts":
+			if strings.HasPrefix(message, "Revert \"") {
+				return true
+			}
+		case "fixups":
+			if strings.HasPrefix(message, "fixup! ") || strings.HasPrefix(message, "squash! ") ||
+				strings.HasPrefix(message, "amend! ") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Check returns an error citing the rule the commit message breaks.
+func (c CommitMessageConfig) Check(message string, parents int) error {
+	if c.Pattern == "" || c.IsExempt(message, parents) {
+		return nil
+	}
+	re, err := regexp.Compile(c.Pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", c.Pattern, err)
+	}
+	if !re.MatchString(message) {
+		return fmt.Errorf("must match %q", c.Pattern)
+	}
+	return nil
+}
+
+// DefaultPrivate returns true if new repositories should be private by
+// default.
+func (c RepoConfig) DefaultPrivate() bool {
+	return c.DefaultVisibility == PrivateVisibility
+}
+
+// OperationContext returns a cancelable context for an expensive repository
+// operation that expires after OperationTimeout.
+func (c RepoConfig) OperationContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.OperationTimeout > 0 {
+		return context.WithTimeout(ctx, time.Duration(c.OperationTimeout)*time.Second)
+	}
+	return context.WithCancel(ctx)
+}
+
+// GitConfig returns the git configuration git services run with, as
+// "key=value" pairs.
+func (c RepoConfig) GitConfig() []string {
+	var cfg []string
+	if c.PackCompression >= 0 {
+		cfg = append(cfg, fmt.Sprintf("pack.compression=%d", c.PackCompression))
+	}
+	if c.KeepAlive != 0 {
+		cfg = append(cfg, fmt.Sprintf("uploadpack.keepAlive=%d", max(c.KeepAlive, 0)))
+	}
+	return cfg
+}
+
+// defaultKeepAlive is the default number of seconds between the keepalive
+// packets of git upload-pack.
+const defaultKeepAlive = 5
+
+// KeepAliveInterval returns the time between the keepalive messages sent to
+// clients that fetch or clone, zero if they're disabled.
+func (c RepoConfig) KeepAliveInterval() time.Duration {
+	switch {
+	case c.KeepAlive < 0:
+		return 0
+	case c.KeepAlive == 0:
+		return defaultKeepAlive * time.Second
+	default:
+		return time.Duration(c.KeepAlive) * time.Second
+	}
+}
+
+// DeployConfig is the configuration of the deploy scripts repositories run
+// after pushes, see the repo deploy command.
+type DeployConfig struct {
+	// Timeout is the maximum number of seconds a deploy script can run before
+	// it's killed. Zero uses the default.
+	Timeout int `env:"TIMEOUT" yaml:"timeout"`
+
+	// MaxOutput is the maximum number of bytes of the output of a deploy kept
+	// in the activity feed. The end of the output is kept. Zero uses the
+	// default.
+	MaxOutput int `env:"MAX_OUTPUT" yaml:"max_output"`
+
+	// MaxMemory is the maximum virtual memory, in megabytes, of a deploy
+	// script. Zero means no limit. It isn't enforced on Windows.
+	MaxMemory int `env:"MAX_MEMORY" yaml:"max_memory"`
+
+	// MaxCPU is the maximum CPU time, in seconds, of a deploy script. Zero
+	// means no limit. It isn't enforced on Windows.
+	MaxCPU int `env:"MAX_CPU" yaml:"max_cpu"`
+
+	// Scripts are the deploy scripts admins can bind to the branches of
+	// repositories. They can only be set in the config file.
+	Scripts []DeployScript `yaml:"scripts"`
+}
+
+// DeployScript is a named executable run after pushes to a branch.
+type DeployScript struct {
+	// Name is the name repositories refer to the script by.
+	Name string `yaml:"name"`
+
+	// Path is the path of the executable, relative to the data directory
+	// unless absolute.
+	Path string `yaml:"path"`
+}
+
+// Script returns the deploy script with the given name.
+func (c DeployConfig) Script(name string) (DeployScript, bool) {
+	for _, s := range c.Scripts {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return DeployScript{}, false
+}
+
+// AttestationConfig is the configuration of the attestations the server signs,
+// see the repo attestation command. An attestation states the references a
+// repository had when the server signed it, not who authored them.
+type AttestationConfig struct {
+	// KeyPath is the path to the SSH private key attestations are signed
+	// with, relative to the data directory unless absolute. An ed25519 key is
+	// generated if it doesn't exist. Empty disables attestations.
+	KeyPath string `env:"KEY_PATH" yaml:"key_path"`
+}
+
+// Audit sink types.
+const (
+	// AuditSinkFile appends the events to a file.
+	AuditSinkFile = "file"
+	// AuditSinkSyslog sends the events to a syslog server.
+	AuditSinkSyslog = "syslog"
+	// AuditSinkWebhook posts the events to a URL.
+	AuditSinkWebhook = "webhook"
+)
+
+// Audit event formats.
+const (
+	// AuditFormatJSON formats the events as JSON objects, one per line.
+	AuditFormatJSON = "json"
+	// AuditFormatCEF formats the events in the ArcSight Common Event Format.
+	AuditFormatCEF = "cef"
+)
+
+// AuditConfig is the configuration of the export of the events of the
+// server, see the repo watch command, to external sinks like a SIEM. The
+// events are still kept in the activity feed.
+type AuditConfig struct {
+	// BufferSize is the number of events buffered per sink while it can't be
+	// reached. The oldest events are dropped once it's full. Zero uses the
+	// default.
+	BufferSize int `env:"BUFFER_SIZE" yaml:"buffer_size"`
+
+	// Sinks are the sinks the events are exported to. They can only be set
+	// in the config file.
+	Sinks []AuditSink `yaml:"sinks"`
+}
+
+// AuditSink is an external sink of the events of the server.
+type AuditSink struct {
+	// Type is the type of the sink, one of AuditSinkFile, AuditSinkSyslog, or
+	// AuditSinkWebhook.
+	Type string `yaml:"type"`
+
+	// Address is the path of the file, relative to the data directory unless
+	// absolute, the address of the syslog server, e.g. "udp://host:514",
+	// empty for the local one, or the URL of the webhook.
+	Address string `yaml:"address"`
+
+	// Format is the format of the events, AuditFormatJSON or AuditFormatCEF.
+	// Empty uses JSON.
+	Format string `yaml:"format"`
+
+	// Events are the types of the events exported to the sink. Empty exports
+	// all of them.
+	Events []string `yaml:"events"`
+}
+
+// Exports returns whether the sink exports events of the given type.
+func (s AuditSink) Exports(typ string) bool {
+	return len(s.Events) == 0 || slices.Contains(s.Events, typ)
+}
+
+// FollowerConfig is the configuration of the follower mode. A follower is a
+// read replica of a primary server: it mirrors the repositories of the
+// primary, serves clones, fetches, and the UI, and rejects writes with a
+// message pointing at the primary.
+type FollowerConfig struct {
+	// Primary is the SSH URL of the primary, e.g.
+	// "ssh://git.example.com:23231". The follower mode is disabled when it's
+	// empty. The follower connects with its client key, which must have
+	// read access to the repositories of the primary.
+	Primary string `env:"PRIMARY" yaml:"primary"`
+}
+
+// Identity sources.
+const (
+	// IdentitySourceStore resolves public keys from the users of the server.
+	IdentitySourceStore = "store"
+	// IdentitySourceFile resolves public keys from a YAML file.
+	IdentitySourceFile = "file"
+)
+
+// IdentityConfig is the configuration of the source public keys are resolved
+// to users from when they authenticate. Users, their names, and their team
+// memberships are synced from the source.
+type IdentityConfig struct {
+	// Source is the source of the identities, IdentitySourceStore or
+	// IdentitySourceFile. Empty uses the store.
+	Source string `env:"SOURCE" yaml:"source"`
+
+	// File is the path of the identities file of IdentitySourceFile,
+	// relative to the data directory unless absolute.
+	File string `env:"FILE" yaml:"file"`
+
+	// TTL is the time in seconds a resolved key is cached for. Zero resolves
+	// the key on every authentication.
+	TTL int `env:"TTL" yaml:"ttl"`
+}
+
+// Command roles.
+const (
+	// CommandRoleAdmin is the role of the server admins.
+	CommandRoleAdmin = "admin"
+	// CommandRoleUser is the role of the registered users.
+	CommandRoleUser = "user"
+)
+
+// CommandsConfig is the configuration of who can run the commands of the
+// server, on top of the access they require.
+type CommandsConfig struct {
+	// Rules restrict commands to some users, keys, or roles. They can only
+	// be set in the config file.
+	Rules []CommandRule `yaml:"rules"`
+
+	// EnforceAdmins applies the rules to the server admins too. Admins can
+	// run any command otherwise.
+	EnforceAdmins bool `env:"ENFORCE_ADMINS" yaml:"enforce_admins"`
+}
+
+// CommandRule restricts a command, and its subcommands, to the listed users,
+// keys, and roles.
+type CommandRule struct {
+	// Command is the path of the command, e.g. "repo import".
+	Command string `yaml:"command"`
+
+	// Users are the usernames of the users allowed to run the command.
+	Users []string `yaml:"users"`
+
+	// Keys are the public keys allowed to run the command, in the
+	// authorized_keys format.
+	Keys []string `yaml:"keys"`
+
+	// Roles are the roles allowed to run the command, CommandRoleAdmin or
+	// CommandRoleUser.
+	Roles []string `yaml:"roles"`
+}
+
+// PublicKeys returns the public keys of the rule.
+func (r CommandRule) PublicKeys() []ssh.PublicKey {
+	return parseAuthKeys(r.Keys)
+}
+
+// Rule returns the rule of the command with the given path, e.g. "repo
+// import", nil if it isn't restricted. Rules apply to the subcommands of
+// their command, the rule of the longest command wins.
+func (c CommandsConfig) Rule(path string) *CommandRule {
+	words := strings.Fields(path)
+	var rule *CommandRule
+	var n int
+	for i, r := range c.Rules {
+		rw := strings.Fields(r.Command)
+		if len(rw) <= n || len(rw) > len(words) || !slices.Equal(rw, words[:len(rw)]) {
+			continue
+		}
+		rule, n = &c.Rules[i], len(rw)
+	}
+	return rule
+}
+
+// RepoTabs are the tabs of a repository in the UI, in their default order.
+// The stash tab is only shown for repositories with a stash.
+var RepoTabs = []string{"readme", "files", "commits", "stash", "branches", "tags", "releases", "insights"}
+
+// UIConfig is the configuration for the SSH terminal UI.
+type UIConfig struct {
+	// HideCloneURL hides the clone command in the repository header. The
+	// command can still be copied to the clipboard.
+	HideCloneURL bool `env:"HIDE_CLONE_URL" yaml:"hide_clone_url"`
+
+	// RecentRepos is the number of recently viewed repositories kept in the
+	// quick switcher of a session. Set it to 0 to disable the switcher.
+	RecentRepos int `env:"RECENT_REPOS" yaml:"recent_repos"`
+
+	// HighlightCache is the size, in megabytes, of the cache of highlighted
+	// files shared by all the sessions. The least recently viewed files are
+	// evicted first. Set it to 0 to disable the cache.
+	HighlightCache int `env:"HIGHLIGHT_CACHE" yaml:"highlight_cache"`
+
+	// BlameHeatmap are the colors of the blame heatmap from the most recent
+	// to the oldest changes. Colors are ANSI 256 color numbers or hex colors
+	// like "#ff8700". The scale of the theme is used when it's empty.
+	BlameHeatmap []string `env:"BLAME_HEATMAP" yaml:"blame_heatmap"`
+
+	// Tabs are the tabs of a repository in the order they're shown, see
+	// RepoTabs. The tabs that aren't listed are hidden. All the tabs are shown
+	// when it's empty.
+	Tabs []string `env:"TABS" yaml:"tabs"`
+
+	// TabLabels are the names shown in place of the default names of tabs,
+	// by tab, e.g. "commits: History".
+	TabLabels map[string]string `env:"TAB_LABELS" yaml:"tab_labels"`
+
+	// StatusBar are the segments of the status bar of a repository in the
+	// order they're shown, see StatusBarSegments. A segment followed by
+	// ":WIDTH", e.g. "time:100", is only shown when the terminal is at least
+	// WIDTH columns wide. DefaultStatusBar is used when it's empty.
+	StatusBar []string `env:"STATUS_BAR" yaml:"status_bar"`
+
+	// FileMarkers are the patterns of the markers, like TODO comments, listed
+	// in the file viewer. They're regular expressions matched against every
+	// line. DefaultFileMarkers are used when it's empty.
+	FileMarkers []string `env:"FILE_MARKERS" yaml:"file_markers"`
+
+	// FileIcons are the icons shown before the names of the files of a
+	// repository: "none", "unicode" for symbols every terminal shows, or
+	// "nerd" for the icons of Nerd Fonts. Users can choose other icons.
+	FileIcons string `env:"FILE_ICONS" yaml:"file_icons"`
+
+	// Welcome is the repository whose README welcomes the users above the
+	// repository list.
+	Welcome WelcomeConfig `envPrefix:"WELCOME_" yaml:"welcome"`
+
+	// Empty are the messages shown when there is nothing to show. Empty
+	// messages are replaced by the defaults.
+	Empty EmptyConfig `envPrefix:"EMPTY_" yaml:"empty"`
+}
+
+const (
+	// FileIconsNone shows no icons in the file list.
+	FileIconsNone = "none"
+
+	// FileIconsUnicode shows Unicode symbols in the file list.
+	FileIconsUnicode = "unicode"
+
+	// FileIconsNerd shows the icons of Nerd Fonts in the file list.
+	FileIconsNerd = "nerd"
+)
+
+// WelcomeConfig is the configuration of the welcome message of the server,
+// the README of a repository shown above the repository list, e.g. to post
+// announcements.
+type WelcomeConfig struct {
+	// Repo is the repository whose README is shown. The welcome message is
+	// disabled when it's empty. Users only see it if they can read the
+	// repository.
+	Repo string `env:"REPO" yaml:"repo"`
+
+	// Height is the maximum number of lines of the welcome message, longer
+	// READMEs are cut. DefaultWelcomeHeight is used when it's 0.
+	Height int `env:"HEIGHT" yaml:"height"`
+}
+
+// DefaultWelcomeHeight is the maximum number of lines of the welcome message
+// by default.
+const DefaultWelcomeHeight = 10
+
+// StatusBarSegments are the segments the status bar of a repository can
+// show: the repository name, the status of the active tab and its details,
+// the selected ref, the hash of its commit, the access level of the user, and
+// the time.
+var StatusBarSegments = []string{"key", "value", "info", "branch", "sha", "access", "time"}
+
+// DefaultFileMarkers are the patterns of the markers listed in the file
+// viewer by default.
+var DefaultFileMarkers = []string{`\bTODO\b`, `\bFIXME\b`, `\bHACK\b`}
+
+// DefaultStatusBar are the segments of the status bar by default.
+var DefaultStatusBar = []string{"key", "value", "info", "branch"}
+
+// ParseStatusBarSegment parses a segment of the status bar, its name
+// optionally followed by ":WIDTH", the minimum width of the terminal it's
+// shown at.
+func ParseStatusBarSegment(s string) (name string, minWidth int, err error) {
+	name, width, ok := strings.Cut(s, ":")
+	if !slices.Contains(StatusBarSegments, name) {
+		return "", 0, fmt.Errorf("invalid status bar segment %q: must be one of %s", name, strings.Join(StatusBarSegments, ", "))
+	}
+	if ok {
+		minWidth, err = strconv.Atoi(width)
+		if err != nil || minWidth <= 0 {
+			return "", 0, fmt.Errorf("invalid status bar segment %q: width must be a positive number", s)
+		}
+	}
+	return name, minWidth, nil
+}
+
+// TabLabel returns the name shown for a tab, its label or its default name.
+func (c UIConfig) TabLabel(tab, name string) string {
+	if label := c.TabLabels[tab]; label != "" {
+		return label
+	}
+	return name
+}
+
+// tabLabelsEnv returns the tab labels in the format of their environment
+// variable, sorted by tab.
+func (c UIConfig) tabLabelsEnv() string {
+	labels := make([]string, 0, len(c.TabLabels))
+	for tab, label := range c.TabLabels {
+		labels = append(labels, tab+":"+label)
+	}
+	slices.Sort(labels)
+	return strings.Join(labels, ",")
+}
+
+// DefaultEmptyRepoMessage is the default message shown in the readme tab of
+// empty repositories.
+const DefaultEmptyRepoMessage = `# Quick Start
+
+Get started by cloning this repository, add your files, commit, and push.
+
+## Clone this repository.
+
+` + "```" + `sh
+git clone {{ .CloneURL }}
+` + "```" + `
+
+## Creating a new repository on the command line
+
+` + "```" + `sh
+touch README.md
+git init
+git add README.md
+git branch -M main
+git commit -m "first commit"
+git remote add origin {{ .CloneURL }}
+git push -u origin main
+` + "```" + `
+
+## Pushing an existing repository from the command line
+
+` + "```" + `sh
+git remote add origin {{ .CloneURL }}
+git push -u origin main
+` + "```" + `
+`
+
+// EmptyConfig is the configuration of the messages shown in the SSH terminal
+// UI when there is nothing to show.
+type EmptyConfig struct {
+	// Readme is shown in the readme tab of repositories without a readme.
+	Readme string `env:"README" yaml:"readme"`
+
+	// Files is shown in the files tab when there are no files.
+	Files string `env:"FILES" yaml:"files"`
+
+	// Log is shown in the log tab when there are no commits.
+	Log string `env:"LOG" yaml:"log"`
+
+	// Repo is shown in the readme tab of empty repositories. It's a Markdown
+	// template where {{ .Repo }} is the repository name and {{ .CloneURL }}
+	// its clone URL.
+	Repo string `env:"REPO" yaml:"repo"`
+}
+
+// RepoMessage returns the message shown in the readme tab of an empty
+// repository.
+func (c EmptyConfig) RepoMessage(repo, cloneURL string) (string, error) {
+	tmpl, err := template.New("empty").Parse(c.Repo)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, struct {
+		Repo     string
+		CloneURL string
+	}{repo, cloneURL}); err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
 }
 
 // Config is the configuration for Soft Serve.
@@ -144,6 +1247,36 @@ type Config struct {
 	// Jobs is the configuration for cron jobs
 	Jobs JobsConfig `envPrefix:"JOBS_" yaml:"jobs"`
 
+	// Housekeeping is the configuration of the housekeeping of the
+	// repositories.
+	Housekeeping HousekeepingConfig `envPrefix:"HOUSEKEEPING_" yaml:"housekeeping"`
+
+	// Repo is the configuration for repositories.
+	Repo RepoConfig `envPrefix:"REPO_" yaml:"repo"`
+
+	// Deploy is the configuration of deploy scripts.
+	Deploy DeployConfig `envPrefix:"DEPLOY_" yaml:"deploy"`
+
+	// Attestation is the configuration of the attestations of repositories.
+	Attestation AttestationConfig `envPrefix:"ATTESTATION_" yaml:"attestation"`
+
+	// Audit is the configuration of the export of the events of the server.
+	Audit AuditConfig `envPrefix:"AUDIT_" yaml:"audit"`
+
+	// Follower is the configuration of the follower mode.
+	Follower FollowerConfig `envPrefix:"FOLLOWER_" yaml:"follower"`
+
+	// Commands is the configuration of who can run the commands of the
+	// server.
+	Commands CommandsConfig `envPrefix:"COMMANDS_" yaml:"commands"`
+
+	// Identity is the configuration of the source of the users of public
+	// keys.
+	Identity IdentityConfig `envPrefix:"IDENTITY_" yaml:"identity"`
+
+	// UI is the configuration for the SSH terminal UI.
+	UI UIConfig `envPrefix:"UI_" yaml:"ui"`
+
 	// InitialAdminKeys is a list of public keys that will be added to the list of admins.
 	InitialAdminKeys []string `env:"INITIAL_ADMIN_KEYS" envSeparator:"\n" yaml:"initial_admin_keys"`
 
@@ -167,32 +1300,111 @@ func (c *Config) Environ() []string {
 		fmt.Sprintf("SOFT_SERVE_INITIAL_ADMIN_KEYS=%s", strings.Join(c.InitialAdminKeys, "\n")),
 		fmt.Sprintf("SOFT_SERVE_SSH_LISTEN_ADDR=%s", c.SSH.ListenAddr),
 		fmt.Sprintf("SOFT_SERVE_SSH_PUBLIC_URL=%s", c.SSH.PublicURL),
+		fmt.Sprintf("SOFT_SERVE_SSH_CLONE_URL=%s", c.SSH.CloneURL),
 		fmt.Sprintf("SOFT_SERVE_SSH_KEY_PATH=%s", c.SSH.KeyPath),
 		fmt.Sprintf("SOFT_SERVE_SSH_CLIENT_KEY_PATH=%s", c.SSH.ClientKeyPath),
 		fmt.Sprintf("SOFT_SERVE_SSH_MAX_TIMEOUT=%d", c.SSH.MaxTimeout),
 		fmt.Sprintf("SOFT_SERVE_SSH_IDLE_TIMEOUT=%d", c.SSH.IdleTimeout),
+		fmt.Sprintf("SOFT_SERVE_SSH_TRUSTED_USER_CA_KEYS=%s", strings.Join(c.SSH.TrustedUserCAKeys, "\n")),
+		fmt.Sprintf("SOFT_SERVE_SSH_REVOKED_CERTIFICATES=%s", strings.Join(c.SSH.RevokedCertificates, "\n")),
+		fmt.Sprintf("SOFT_SERVE_SSH_INTERACTIVE=%s", c.SSH.Interactive),
+		fmt.Sprintf("SOFT_SERVE_SSH_PAGER=%s", c.SSH.Pager),
+		fmt.Sprintf("SOFT_SERVE_GIT_ENABLED=%t", c.Git.Enabled),
 		fmt.Sprintf("SOFT_SERVE_GIT_LISTEN_ADDR=%s", c.Git.ListenAddr),
 		fmt.Sprintf("SOFT_SERVE_GIT_PUBLIC_URL=%s", c.Git.PublicURL),
+		fmt.Sprintf("SOFT_SERVE_GIT_CLONE_URL=%s", c.Git.CloneURL),
 		fmt.Sprintf("SOFT_SERVE_GIT_MAX_TIMEOUT=%d", c.Git.MaxTimeout),
 		fmt.Sprintf("SOFT_SERVE_GIT_IDLE_TIMEOUT=%d", c.Git.IdleTimeout),
 		fmt.Sprintf("SOFT_SERVE_GIT_MAX_CONNECTIONS=%d", c.Git.MaxConnections),
+		fmt.Sprintf("SOFT_SERVE_GIT_RATE_LIMIT=%d", c.Git.RateLimit),
 		fmt.Sprintf("SOFT_SERVE_HTTP_LISTEN_ADDR=%s", c.HTTP.ListenAddr),
 		fmt.Sprintf("SOFT_SERVE_HTTP_TLS_KEY_PATH=%s", c.HTTP.TLSKeyPath),
 		fmt.Sprintf("SOFT_SERVE_HTTP_TLS_CERT_PATH=%s", c.HTTP.TLSCertPath),
 		fmt.Sprintf("SOFT_SERVE_HTTP_PUBLIC_URL=%s", c.HTTP.PublicURL),
+		fmt.Sprintf("SOFT_SERVE_HTTP_CLONE_URL=%s", c.HTTP.CloneURL),
+		fmt.Sprintf("SOFT_SERVE_HTTP_CORS_ALLOWED_ORIGINS=%s", strings.Join(c.HTTP.CORS.AllowedOrigins, ",")),
+		fmt.Sprintf("SOFT_SERVE_HTTP_API_PATH=%s", c.HTTP.API.Path),
+		fmt.Sprintf("SOFT_SERVE_HTTP_API_RATE_LIMIT=%d", c.HTTP.API.RateLimit),
 		fmt.Sprintf("SOFT_SERVE_STATS_LISTEN_ADDR=%s", c.Stats.ListenAddr),
 		fmt.Sprintf("SOFT_SERVE_LOG_FORMAT=%s", c.Log.Format),
+		fmt.Sprintf("SOFT_SERVE_LOG_LEVEL=%s", c.Log.Level),
 		fmt.Sprintf("SOFT_SERVE_LOG_TIME_FORMAT=%s", c.Log.TimeFormat),
 		fmt.Sprintf("SOFT_SERVE_DB_DRIVER=%s", c.DB.Driver),
 		fmt.Sprintf("SOFT_SERVE_DB_DATA_SOURCE=%s", c.DB.DataSource),
 		fmt.Sprintf("SOFT_SERVE_LFS_ENABLED=%t", c.LFS.Enabled),
 		fmt.Sprintf("SOFT_SERVE_LFS_SSH_ENABLED=%t", c.LFS.SSHEnabled),
+		fmt.Sprintf("SOFT_SERVE_LFS_ARCHIVE_OBJECTS=%t", c.LFS.ArchiveObjects),
 		fmt.Sprintf("SOFT_SERVE_JOBS_MIRROR_PULL=%s", c.Jobs.MirrorPull),
+		fmt.Sprintf("SOFT_SERVE_JOBS_COMMIT_GRAPH=%s", c.Jobs.CommitGraph),
+		fmt.Sprintf("SOFT_SERVE_JOBS_HOUSEKEEPING=%s", c.Jobs.Housekeeping),
+		fmt.Sprintf("SOFT_SERVE_JOBS_ARCHIVE=%s", c.Jobs.Archive),
+		fmt.Sprintf("SOFT_SERVE_JOBS_FOLLOWER_SYNC=%s", c.Jobs.FollowerSync),
+		fmt.Sprintf("SOFT_SERVE_JOBS_REPO_SIZES=%s", c.Jobs.RepoSizes),
+		fmt.Sprintf("SOFT_SERVE_HOUSEKEEPING_TASKS=%s", strings.Join(c.Housekeeping.Tasks, ",")),
+		fmt.Sprintf("SOFT_SERVE_HOUSEKEEPING_JITTER=%d", c.Housekeeping.Jitter),
+		fmt.Sprintf("SOFT_SERVE_HOUSEKEEPING_QUARANTINE=%t", c.Housekeeping.Quarantine),
+		fmt.Sprintf("SOFT_SERVE_REPO_DEFAULT_VISIBILITY=%s", c.Repo.DefaultVisibility),
+		fmt.Sprintf("SOFT_SERVE_REPO_OPERATION_TIMEOUT=%d", c.Repo.OperationTimeout),
+		fmt.Sprintf("SOFT_SERVE_REPO_PACK_COMPRESSION=%d", c.Repo.PackCompression),
+		fmt.Sprintf("SOFT_SERVE_REPO_KEEPALIVE=%d", c.Repo.KeepAlive),
+		fmt.Sprintf("SOFT_SERVE_REPO_NAME_PATTERN=%s", c.Repo.Name.Pattern),
+		fmt.Sprintf("SOFT_SERVE_REPO_NAME_MAX_LENGTH=%d", c.Repo.Name.MaxLength),
+		fmt.Sprintf("SOFT_SERVE_REPO_NAME_PREFIXES=%s", strings.Join(c.Repo.Name.Prefixes, ",")),
+		fmt.Sprintf("SOFT_SERVE_REPO_NAME_LOWERCASE=%t", c.Repo.Name.Lowercase),
+		fmt.Sprintf("SOFT_SERVE_REPO_COMMIT_MESSAGE_PATTERN=%s", c.Repo.CommitMessage.Pattern),
+		fmt.Sprintf("SOFT_SERVE_REPO_COMMIT_MESSAGE_HINT=%s", c.Repo.CommitMessage.Hint),
+		fmt.Sprintf("SOFT_SERVE_REPO_COMMIT_MESSAGE_EXEMPT=%s", strings.Join(c.Repo.CommitMessage.Exempt, ",")),
+		fmt.Sprintf("SOFT_SERVE_REPO_REFS_MAX_COUNT=%d", c.Repo.Refs.MaxCount),
+		fmt.Sprintf("SOFT_SERVE_REPO_REFS_MAX_NAME_LENGTH=%d", c.Repo.Refs.MaxNameLength),
+		fmt.Sprintf("SOFT_SERVE_REPO_COMMIT_GRAPH=%t", c.Repo.CommitGraph),
+		fmt.Sprintf("SOFT_SERVE_REPO_STORAGE=%s", c.Repo.Storage),
+		fmt.Sprintf("SOFT_SERVE_REPO_CONCURRENCY_MAX=%d", c.Repo.Concurrency.Max),
+		fmt.Sprintf("SOFT_SERVE_REPO_CONCURRENCY_PER_REPO=%d", c.Repo.Concurrency.PerRepo),
+		fmt.Sprintf("SOFT_SERVE_REPO_CONCURRENCY_QUEUE_TIMEOUT=%d", c.Repo.Concurrency.QueueTimeout),
+		fmt.Sprintf("SOFT_SERVE_REPO_ARCHIVE_AFTER=%d", c.Repo.ArchiveAfter),
+		fmt.Sprintf("SOFT_SERVE_DEPLOY_TIMEOUT=%d", c.Deploy.Timeout),
+		fmt.Sprintf("SOFT_SERVE_DEPLOY_MAX_OUTPUT=%d", c.Deploy.MaxOutput),
+		fmt.Sprintf("SOFT_SERVE_DEPLOY_MAX_MEMORY=%d", c.Deploy.MaxMemory),
+		fmt.Sprintf("SOFT_SERVE_DEPLOY_MAX_CPU=%d", c.Deploy.MaxCPU),
+		fmt.Sprintf("SOFT_SERVE_AUDIT_BUFFER_SIZE=%d", c.Audit.BufferSize),
+		fmt.Sprintf("SOFT_SERVE_FOLLOWER_PRIMARY=%s", c.Follower.Primary),
+		fmt.Sprintf("SOFT_SERVE_COMMANDS_ENFORCE_ADMINS=%t", c.Commands.EnforceAdmins),
+		fmt.Sprintf("SOFT_SERVE_IDENTITY_SOURCE=%s", c.Identity.Source),
+		fmt.Sprintf("SOFT_SERVE_IDENTITY_FILE=%s", c.Identity.File),
+		fmt.Sprintf("SOFT_SERVE_IDENTITY_TTL=%d", c.Identity.TTL),
+		fmt.Sprintf("SOFT_SERVE_ATTESTATION_KEY_PATH=%s", c.Attestation.KeyPath),
+		fmt.Sprintf("SOFT_SERVE_UI_HIDE_CLONE_URL=%t", c.UI.HideCloneURL),
+		fmt.Sprintf("SOFT_SERVE_UI_RECENT_REPOS=%d", c.UI.RecentRepos),
+		fmt.Sprintf("SOFT_SERVE_UI_HIGHLIGHT_CACHE=%d", c.UI.HighlightCache),
+		fmt.Sprintf("SOFT_SERVE_UI_BLAME_HEATMAP=%s", strings.Join(c.UI.BlameHeatmap, ",")),
+		fmt.Sprintf("SOFT_SERVE_UI_TABS=%s", strings.Join(c.UI.Tabs, ",")),
+		fmt.Sprintf("SOFT_SERVE_UI_TAB_LABELS=%s", c.UI.tabLabelsEnv()),
+		fmt.Sprintf("SOFT_SERVE_UI_STATUS_BAR=%s", strings.Join(c.UI.StatusBar, ",")),
+		fmt.Sprintf("SOFT_SERVE_UI_FILE_MARKERS=%s", strings.Join(c.UI.FileMarkers, ",")),
+		fmt.Sprintf("SOFT_SERVE_UI_FILE_ICONS=%s", c.UI.FileIcons),
+		fmt.Sprintf("SOFT_SERVE_UI_WELCOME_REPO=%s", c.UI.Welcome.Repo),
+		fmt.Sprintf("SOFT_SERVE_UI_WELCOME_HEIGHT=%d", c.UI.Welcome.Height),
+		fmt.Sprintf("SOFT_SERVE_UI_EMPTY_README=%s", c.UI.Empty.Readme),
+		fmt.Sprintf("SOFT_SERVE_UI_EMPTY_FILES=%s", c.UI.Empty.Files),
+		fmt.Sprintf("SOFT_SERVE_UI_EMPTY_LOG=%s", c.UI.Empty.Log),
+		fmt.Sprintf("SOFT_SERVE_UI_EMPTY_REPO=%s", c.UI.Empty.Repo),
 	}...)
 
 	return envs
 }
 
+// isColor returns true if s is an ANSI 256 color number or a hex color.
+func isColor(s string) bool {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n >= 0 && n <= 255
+	}
+	if !strings.HasPrefix(s, "#") || (len(s) != 4 && len(s) != 7) {
+		return false
+	}
+	_, err := strconv.ParseUint(s[1:], 16, 32)
+	return err == nil
+}
+
 // IsDebug returns true if the server is running in debug mode.
 func IsDebug() bool {
 	debug, _ := strconv.ParseBool(os.Getenv("SOFT_SERVE_DEBUG"))
@@ -324,6 +1536,8 @@ func DefaultConfig() *Config {
 			ClientKeyPath: filepath.Join("ssh", "soft_serve_client_ed25519"),
 			MaxTimeout:    0,
 			IdleTimeout:   10 * 60, // 10 minutes
+			Interactive:   InteractiveTUI,
+			Pager:         PagerAuto,
 		},
 		Git: GitConfig{
 			ListenAddr:     ":9418",
@@ -331,16 +1545,21 @@ func DefaultConfig() *Config {
 			MaxTimeout:     0,
 			IdleTimeout:    3,
 			MaxConnections: 32,
+			RateLimit:      60,
 		},
 		HTTP: HTTPConfig{
 			ListenAddr: ":23232",
 			PublicURL:  "http://localhost:23232",
+			API: APIConfig{
+				RateLimit: 60,
+			},
 		},
 		Stats: StatsConfig{
 			ListenAddr: "localhost:23233",
 		},
 		Log: LogConfig{
 			Format:     "text",
+			Level:      LogLevelInfo,
 			TimeFormat: time.DateTime,
 		},
 		DB: DBConfig{
@@ -353,7 +1572,55 @@ func DefaultConfig() *Config {
 			SSHEnabled: false,
 		},
 		Jobs: JobsConfig{
-			MirrorPull: "@every 10m",
+			MirrorPull:   "@every 10m",
+			CommitGraph:  "@every 1h",
+			Housekeeping: "@every 24h",
+			Archive:      "@every 24h",
+			FollowerSync: "@every 5m",
+			RepoSizes:    "@every 24h",
+		},
+		Audit: AuditConfig{
+			BufferSize: 1000,
+		},
+		Identity: IdentityConfig{
+			Source: IdentitySourceStore,
+			File:   "identities.yaml",
+			TTL:    5 * 60, // 5 minutes
+		},
+		Housekeeping: HousekeepingConfig{
+			Tasks: []string{HousekeepingRepack, HousekeepingPrune, HousekeepingPackRefs},
+			// Spread the repositories over ten minutes.
+			Jitter: 600,
+		},
+		Repo: RepoConfig{
+			DefaultVisibility: PublicVisibility,
+			OperationTimeout:  60,
+			PackCompression:   -1,
+			Storage:           storage.LocalRepoStorageName,
+			Concurrency: ConcurrencyConfig{
+				QueueTimeout: 30,
+			},
+		},
+		Deploy: DeployConfig{
+			Timeout:   10 * 60, // 10 minutes
+			MaxOutput: 64 * 1024,
+		},
+		UI: UIConfig{
+			RecentRepos:    10,
+			HighlightCache: 64,
+			Tabs:           slices.Clone(RepoTabs),
+			StatusBar:      slices.Clone(DefaultStatusBar),
+			FileMarkers:    slices.Clone(DefaultFileMarkers),
+			FileIcons:      FileIconsNone,
+			Welcome: WelcomeConfig{
+				Height: DefaultWelcomeHeight,
+			},
+			Empty: EmptyConfig{
+				Readme: "No readme found.",
+				Files:  "No items.",
+				Log:    "No items.",
+				Repo:   DefaultEmptyRepoMessage,
+			},
 		},
 	}
 }
@@ -373,6 +1640,16 @@ func (c *Config) Validate() error {
 	c.SSH.PublicURL = strings.TrimSuffix(c.SSH.PublicURL, "/")
 	c.HTTP.PublicURL = strings.TrimSuffix(c.HTTP.PublicURL, "/")
 
+	for _, u := range []struct{ name, tmpl string }{
+		{"ssh", c.SSH.CloneURL},
+		{"http", c.HTTP.CloneURL},
+		{"git", c.Git.CloneURL},
+	} {
+		if err := validCloneURL(u.tmpl); err != nil {
+			return fmt.Errorf("invalid %s clone url %q: %w", u.name, u.tmpl, err)
+		}
+	}
+
 	if c.SSH.KeyPath != "" && !filepath.IsAbs(c.SSH.KeyPath) {
 		c.SSH.KeyPath = filepath.Join(c.DataPath, c.SSH.KeyPath)
 	}
@@ -381,6 +1658,10 @@ func (c *Config) Validate() error {
 		c.SSH.ClientKeyPath = filepath.Join(c.DataPath, c.SSH.ClientKeyPath)
 	}
 
+	if c.Attestation.KeyPath != "" && !filepath.IsAbs(c.Attestation.KeyPath) {
+		c.Attestation.KeyPath = filepath.Join(c.DataPath, c.Attestation.KeyPath)
+	}
+
 	if c.HTTP.TLSKeyPath != "" && !filepath.IsAbs(c.HTTP.TLSKeyPath) {
 		c.HTTP.TLSKeyPath = filepath.Join(c.DataPath, c.HTTP.TLSKeyPath)
 	}
@@ -389,6 +1670,394 @@ func (c *Config) Validate() error {
 		c.HTTP.TLSCertPath = filepath.Join(c.DataPath, c.HTTP.TLSCertPath)
 	}
 
+	for i, origin := range c.HTTP.CORS.AllowedOrigins {
+		origin = strings.TrimSuffix(origin, "/")
+		if origin != "*" {
+			u, err := url.Parse(origin)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" {
+				return fmt.Errorf("invalid CORS allowed origin %q: must be \"*\" or a URL like \"https://example.com\"", c.HTTP.CORS.AllowedOrigins[i])
+			}
+		}
+		c.HTTP.CORS.AllowedOrigins[i] = origin
+	}
+
+	if p := c.HTTP.API.Path; p != "" {
+		p = "/" + strings.Trim(p, "/")
+		if p == "/" {
+			return fmt.Errorf("invalid HTTP API path %q: must not be the root path", c.HTTP.API.Path)
+		}
+		c.HTTP.API.Path = p
+	}
+
+	if c.HTTP.API.RateLimit < 0 {
+		return fmt.Errorf("invalid HTTP API rate limit %d: must be zero or positive", c.HTTP.API.RateLimit)
+	}
+
+	switch strings.ToLower(c.Log.Level) {
+	case "":
+		c.Log.Level = LogLevelInfo
+	case LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+		c.Log.Level = strings.ToLower(c.Log.Level)
+	default:
+		return fmt.Errorf("invalid log level %q: must be %q, %q, %q, or %q",
+			c.Log.Level, LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError)
+	}
+
+	switch c.SSH.Interactive {
+	case "":
+		c.SSH.Interactive = InteractiveTUI
+	case InteractiveTUI, InteractiveShell:
+	default:
+		return fmt.Errorf("invalid ssh interactive %q: must be %q or %q",
+			c.SSH.Interactive, InteractiveTUI, InteractiveShell)
+	}
+
+	switch c.SSH.Pager {
+	case "":
+		c.SSH.Pager = PagerAuto
+	case PagerAuto, PagerAlways, PagerNever:
+	default:
+		return fmt.Errorf("invalid ssh pager %q: must be %q, %q, or %q",
+			c.SSH.Pager, PagerAuto, PagerAlways, PagerNever)
+	}
+
+	switch c.Repo.DefaultVisibility {
+	case "":
+		c.Repo.DefaultVisibility = PublicVisibility
+	case PublicVisibility, PrivateVisibility:
+	default:
+		return fmt.Errorf("invalid repo default visibility %q: must be %q or %q",
+			c.Repo.DefaultVisibility, PublicVisibility, PrivateVisibility)
+	}
+
+	if c.Repo.Storage == "" {
+		c.Repo.Storage = storage.LocalRepoStorageName
+	}
+	if !slices.Contains(storage.RepoStorages(), c.Repo.Storage) {
+		return fmt.Errorf("invalid repo storage %q: must be one of %s",
+			c.Repo.Storage, strings.Join(storage.RepoStorages(), ", "))
+	}
+
+	if c.Git.RateLimit < 0 {
+		return fmt.Errorf("invalid git rate limit %d: must be zero or positive", c.Git.RateLimit)
+	}
+
+	if c.Repo.OperationTimeout < 0 {
+		return fmt.Errorf("invalid repo operation timeout %d: must be zero or positive", c.Repo.OperationTimeout)
+	}
+
+	if c.Repo.PackCompression < -1 || c.Repo.PackCompression > 9 {
+		return fmt.Errorf("invalid repo pack compression %d: must be between -1 and 9", c.Repo.PackCompression)
+	}
+
+	if c.Repo.KeepAlive < -1 {
+		return fmt.Errorf("invalid repo keepalive %d: must be -1 or more", c.Repo.KeepAlive)
+	}
+
+	if c.Repo.ArchiveAfter < 0 {
+		return fmt.Errorf("invalid repo archive after %d: must be zero or positive", c.Repo.ArchiveAfter)
+	}
+
+	if c.Repo.Concurrency.Max < 0 {
+		return fmt.Errorf("invalid repo concurrency max %d: must be zero or positive", c.Repo.Concurrency.Max)
+	}
+	if c.Repo.Concurrency.PerRepo < 0 {
+		return fmt.Errorf("invalid repo concurrency per repo %d: must be zero or positive", c.Repo.Concurrency.PerRepo)
+	}
+	if c.Repo.Concurrency.QueueTimeout < 0 {
+		return fmt.Errorf("invalid repo concurrency queue timeout %d: must be zero or positive", c.Repo.Concurrency.QueueTimeout)
+	}
+
+	if _, err := regexp.Compile(c.Repo.Name.Pattern); err != nil {
+		return fmt.Errorf("invalid repo name pattern %q: %w", c.Repo.Name.Pattern, err)
+	}
+
+	if c.Repo.Name.MaxLength < 0 {
+		return fmt.Errorf("invalid repo name max length %d: must be zero or positive", c.Repo.Name.MaxLength)
+	}
+
+	for _, p := range c.Repo.Name.Prefixes {
+		if p == "" {
+			return fmt.Errorf("invalid repo name prefix: must not be empty")
+		}
+	}
+
+	if _, err := regexp.Compile(c.Repo.CommitMessage.Pattern); err != nil {
+		return fmt.Errorf("invalid repo commit message pattern %q: %w", c.Repo.CommitMessage.Pattern, err)
+	}
+
+	for _, e := range c.Repo.CommitMessage.Exempt {
+		if !slices.Contains(CommitMessageExemptions, e) {
+			return fmt.Errorf("invalid repo commit message exemption %q: must be one of %s", e, strings.Join(CommitMessageExemptions, ", "))
+		}
+	}
+
+	if c.Repo.Refs.MaxCount < 0 {
+		return fmt.Errorf("invalid repo refs max count %d: must be zero or positive", c.Repo.Refs.MaxCount)
+	}
+
+	if c.Repo.Refs.MaxNameLength < 0 {
+		return fmt.Errorf("invalid repo refs max name length %d: must be zero or positive", c.Repo.Refs.MaxNameLength)
+	}
+
+	for _, d := range c.Repo.Defaults {
+		if d.Match == "" {
+			return fmt.Errorf("invalid repo defaults: match must not be empty")
+		}
+		if _, err := path.Match(d.Match, ""); err != nil {
+			return fmt.Errorf("invalid repo defaults match %q: %w", d.Match, err)
+		}
+		switch d.Visibility {
+		case "", PublicVisibility, PrivateVisibility:
+		default:
+			return fmt.Errorf("invalid repo defaults visibility %q: must be %q or %q",
+				d.Visibility, PublicVisibility, PrivateVisibility)
+		}
+		for _, t := range []string{d.Description, d.ProjectName} {
+			if _, err := renderRepoDefault(t, "repo"); err != nil {
+				return fmt.Errorf("invalid repo defaults of %q: %w", d.Match, err)
+			}
+		}
+	}
+
+	templates := make(map[string]bool, len(c.Repo.Templates))
+	for i, t := range c.Repo.Templates {
+		if t.Name == "" || strings.ContainsAny(t.Name, " \t\n/") {
+			return fmt.Errorf("invalid repo template name %q: must not be empty or have spaces or slashes", t.Name)
+		}
+		if templates[t.Name] {
+			return fmt.Errorf("invalid repo template name %q: already used by another template", t.Name)
+		}
+		templates[t.Name] = true
+		if (t.Repo == "") == (t.Path == "") {
+			return fmt.Errorf("invalid repo template %q: one of repo or path must be set", t.Name)
+		}
+		if t.Ref != "" && t.Repo == "" {
+			return fmt.Errorf("invalid repo template %q: ref needs a repo", t.Name)
+		}
+		if t.Branch != "" && !validBranchName(t.Branch) {
+			return fmt.Errorf("invalid repo template %q branch %q", t.Name, t.Branch)
+		}
+		if t.Path != "" && !filepath.IsAbs(t.Path) {
+			c.Repo.Templates[i].Path = filepath.Join(c.DataPath, t.Path)
+		}
+		if t.Message == "" {
+			c.Repo.Templates[i].Message = DefaultTemplateMessage
+		}
+	}
+
+	if err := validHousekeepingTasks(c.Housekeeping.Tasks); err != nil {
+		return err
+	}
+
+	if c.Housekeeping.Jitter < 0 {
+		return fmt.Errorf("invalid housekeeping jitter %d: must be positive", c.Housekeeping.Jitter)
+	}
+
+	for _, r := range c.Housekeeping.Repos {
+		if r.Match == "" {
+			return fmt.Errorf("invalid housekeeping repos: match must not be empty")
+		}
+		if _, err := path.Match(r.Match, ""); err != nil {
+			return fmt.Errorf("invalid housekeeping repos match %q: %w", r.Match, err)
+		}
+		if err := validHousekeepingTasks(r.Tasks); err != nil {
+			return err
+		}
+	}
+
+	if c.Deploy.Timeout < 0 {
+		return fmt.Errorf("invalid deploy timeout %d: must be positive", c.Deploy.Timeout)
+	} else if c.Deploy.Timeout == 0 {
+		c.Deploy.Timeout = DefaultConfig().Deploy.Timeout
+	}
+
+	if c.Deploy.MaxOutput < 0 {
+		return fmt.Errorf("invalid deploy max output %d: must be positive", c.Deploy.MaxOutput)
+	} else if c.Deploy.MaxOutput == 0 {
+		c.Deploy.MaxOutput = DefaultConfig().Deploy.MaxOutput
+	}
+
+	if c.Deploy.MaxMemory < 0 {
+		return fmt.Errorf("invalid deploy max memory %d: must be zero or positive", c.Deploy.MaxMemory)
+	}
+
+	if c.Deploy.MaxCPU < 0 {
+		return fmt.Errorf("invalid deploy max cpu %d: must be zero or positive", c.Deploy.MaxCPU)
+	}
+
+	scripts := make(map[string]bool, len(c.Deploy.Scripts))
+	for i, s := range c.Deploy.Scripts {
+		if s.Name == "" || strings.ContainsAny(s.Name, " \t\n/") {
+			return fmt.Errorf("invalid deploy script name %q: must not be empty or have spaces or slashes", s.Name)
+		}
+		if scripts[s.Name] {
+			return fmt.Errorf("invalid deploy script name %q: already used by another script", s.Name)
+		}
+		scripts[s.Name] = true
+		if s.Path == "" {
+			return fmt.Errorf("invalid deploy script %q: path must not be empty", s.Name)
+		}
+		if !filepath.IsAbs(s.Path) {
+			c.Deploy.Scripts[i].Path = filepath.Join(c.DataPath, s.Path)
+		}
+	}
+
+	if c.Audit.BufferSize < 0 {
+		return fmt.Errorf("invalid audit buffer size %d: must be positive", c.Audit.BufferSize)
+	} else if c.Audit.BufferSize == 0 {
+		c.Audit.BufferSize = DefaultConfig().Audit.BufferSize
+	}
+
+	for i, s := range c.Audit.Sinks {
+		if err := validAuditSink(s); err != nil {
+			return fmt.Errorf("invalid audit sink %d: %w", i+1, err)
+		}
+		if s.Type == AuditSinkFile && !filepath.IsAbs(s.Address) {
+			c.Audit.Sinks[i].Address = filepath.Join(c.DataPath, s.Address)
+		}
+	}
+
+	if c.Follower.Primary != "" {
+		u, err := url.Parse(c.Follower.Primary)
+		if err != nil {
+			return fmt.Errorf("invalid follower primary %q: %w", c.Follower.Primary, err)
+		}
+		if u.Scheme != "ssh" || u.Host == "" {
+			return fmt.Errorf("invalid follower primary %q: must be an SSH URL like ssh://host:port", c.Follower.Primary)
+		}
+		if u.Path != "" && u.Path != "/" {
+			return fmt.Errorf("invalid follower primary %q: must not have a path", c.Follower.Primary)
+		}
+		c.Follower.Primary = strings.TrimSuffix(c.Follower.Primary, "/")
+	}
+
+	for i, r := range c.Commands.Rules {
+		if len(strings.Fields(r.Command)) == 0 {
+			return fmt.Errorf("invalid command rule %d: command is required", i+1)
+		}
+		for _, role := range r.Roles {
+			if role != CommandRoleAdmin && role != CommandRoleUser {
+				return fmt.Errorf("invalid command rule %d: unknown role %q, must be one of %s, %s", i+1, role, CommandRoleAdmin, CommandRoleUser)
+			}
+		}
+		for _, k := range r.Keys {
+			if len(parseAuthKeys([]string{k})) == 0 {
+				return fmt.Errorf("invalid command rule %d: invalid public key %q", i+1, k)
+			}
+		}
+	}
+
+	switch c.Identity.Source {
+	case "":
+		c.Identity.Source = IdentitySourceStore
+	case IdentitySourceStore, IdentitySourceFile:
+	default:
+		return fmt.Errorf("invalid identity source %q: must be one of %s, %s", c.Identity.Source, IdentitySourceStore, IdentitySourceFile)
+	}
+	if c.Identity.Source == IdentitySourceFile && c.Identity.File == "" {
+		return fmt.Errorf("invalid identity file: path must not be empty")
+	}
+	if c.Identity.File != "" && !filepath.IsAbs(c.Identity.File) {
+		c.Identity.File = filepath.Join(c.DataPath, c.Identity.File)
+	}
+	if c.Identity.TTL < 0 {
+		return fmt.Errorf("invalid identity ttl %d: must be zero or positive", c.Identity.TTL)
+	}
+
+	if c.UI.RecentRepos < 0 {
+		return fmt.Errorf("invalid number of recent repos %d: must be zero or positive", c.UI.RecentRepos)
+	}
+
+	if c.UI.HighlightCache < 0 {
+		return fmt.Errorf("invalid highlight cache size %d: must be zero or positive", c.UI.HighlightCache)
+	}
+
+	for _, color := range c.UI.BlameHeatmap {
+		if !isColor(color) {
+			return fmt.Errorf("invalid blame heatmap color %q: must be an ANSI 256 color number or a hex color", color)
+		}
+	}
+
+	if len(c.UI.Tabs) == 0 {
+		c.UI.Tabs = slices.Clone(RepoTabs)
+	}
+	for i, tab := range c.UI.Tabs {
+		if !slices.Contains(RepoTabs, tab) {
+			return fmt.Errorf("invalid ui tab %q: must be one of %s", tab, strings.Join(RepoTabs, ", "))
+		}
+		if slices.Contains(c.UI.Tabs[:i], tab) {
+			return fmt.Errorf("invalid ui tab %q: listed twice", tab)
+		}
+	}
+	for tab, label := range c.UI.TabLabels {
+		if !slices.Contains(RepoTabs, tab) {
+			return fmt.Errorf("invalid ui tab label %q: must be one of %s", tab, strings.Join(RepoTabs, ", "))
+		}
+		if strings.TrimSpace(label) == "" {
+			return fmt.Errorf("invalid ui tab label of %q: must not be empty", tab)
+		}
+	}
+
+	if len(c.UI.FileMarkers) == 0 {
+		c.UI.FileMarkers = slices.Clone(DefaultFileMarkers)
+	}
+	for _, m := range c.UI.FileMarkers {
+		if _, err := regexp.Compile(m); err != nil {
+			return fmt.Errorf("invalid file marker %q: %w", m, err)
+		}
+	}
+
+	switch c.UI.FileIcons {
+	case "":
+		c.UI.FileIcons = FileIconsNone
+	case FileIconsNone, FileIconsUnicode, FileIconsNerd:
+	default:
+		return fmt.Errorf("invalid ui file icons %q: must be %q, %q, or %q",
+			c.UI.FileIcons, FileIconsNone, FileIconsUnicode, FileIconsNerd)
+	}
+
+	if c.UI.Welcome.Height == 0 {
+		c.UI.Welcome.Height = DefaultWelcomeHeight
+	}
+	if c.UI.Welcome.Height < 0 {
+		return fmt.Errorf("invalid welcome height %d: must be positive", c.UI.Welcome.Height)
+	}
+
+	if len(c.UI.StatusBar) == 0 {
+		c.UI.StatusBar = slices.Clone(DefaultStatusBar)
+	}
+	seen := map[string]bool{}
+	for _, seg := range c.UI.StatusBar {
+		name, _, err := ParseStatusBarSegment(seg)
+		if err != nil {
+			return err
+		}
+		if seen[name] {
+			return fmt.Errorf("invalid status bar segment %q: listed twice", name)
+		}
+		seen[name] = true
+	}
+
+	defaults := DefaultConfig().UI.Empty
+	for _, m := range []struct {
+		v   *string
+		def string
+	}{
+		{&c.UI.Empty.Readme, defaults.Readme},
+		{&c.UI.Empty.Files, defaults.Files},
+		{&c.UI.Empty.Log, defaults.Log},
+		{&c.UI.Empty.Repo, defaults.Repo},
+	} {
+		if *m.v == "" {
+			*m.v = m.def
+		}
+	}
+
+	if _, err := c.UI.Empty.RepoMessage("repo", "ssh://localhost/repo.git"); err != nil {
+		return fmt.Errorf("invalid empty repo message: %w", err)
+	}
+
 	if strings.HasPrefix(c.DB.Driver, "sqlite") && !filepath.IsAbs(c.DB.DataSource) {
 		c.DB.DataSource = filepath.Join(c.DataPath, c.DB.DataSource)
 	}
@@ -402,6 +2071,28 @@ func (c *Config) Validate() error {
 
 	c.InitialAdminKeys = pks
 
+	// Validate user certificate authorities
+	cas := make([]string, 0)
+	for _, key := range c.SSH.TrustedUserCAKeys {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		if len(parseAuthKeys([]string{key})) == 0 {
+			return fmt.Errorf("invalid trusted user CA key %q", key)
+		}
+		cas = append(cas, key)
+	}
+	c.SSH.TrustedUserCAKeys = cas
+
+	revoked := make([]string, 0)
+	for _, cert := range c.SSH.RevokedCertificates {
+		if cert = strings.TrimSpace(cert); cert != "" {
+			revoked = append(revoked, cert)
+		}
+	}
+	c.SSH.RevokedCertificates = revoked
+
 	return nil
 }
 
@@ -430,6 +2121,12 @@ func (c *Config) AdminKeys() []ssh.PublicKey {
 	return parseAuthKeys(c.InitialAdminKeys)
 }
 
+// UserCAKeys returns the certificate authority keys trusted to sign user
+// certificates.
+func (c *Config) UserCAKeys() []ssh.PublicKey {
+	return parseAuthKeys(c.SSH.TrustedUserCAKeys)
+}
+
 func init() {
 	if ex, err := os.Executable(); err == nil {
 		binPath = filepath.ToSlash(ex)
@@ -1,8 +1,12 @@
 package config
 
 import (
+	"context"
 	"os"
+	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/matryer/is"
 )
@@ -79,3 +83,747 @@ func TestCustomConfigLocation(t *testing.T) {
 	cfg = DefaultConfig()
 	is.Equal(cfg.Name, "Soft Serve")
 }
+
+func TestRepoDefaultVisibility(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Repo.DefaultPrivate(), false)
+
+	cfg.Repo.DefaultVisibility = PrivateVisibility
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Repo.DefaultPrivate(), true)
+
+	cfg.Repo.DefaultVisibility = ""
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Repo.DefaultVisibility, PublicVisibility)
+
+	cfg.Repo.DefaultVisibility = "internal"
+	is.True(cfg.Validate() != nil)
+}
+
+func TestSSHInteractive(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.SSH.Interactive, InteractiveTUI)
+
+	cfg.SSH.Interactive = ""
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.SSH.Interactive, InteractiveTUI)
+
+	cfg.SSH.Interactive = InteractiveShell
+	is.NoErr(cfg.Validate())
+
+	cfg.SSH.Interactive = "bash"
+	is.True(cfg.Validate() != nil)
+}
+
+func TestLogLevel(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Log.Level, LogLevelInfo)
+
+	cfg.Log.Level = ""
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Log.Level, LogLevelInfo)
+
+	cfg.Log.Level = "WARN"
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Log.Level, LogLevelWarn)
+
+	cfg.Log.Level = "verbose"
+	is.True(cfg.Validate() != nil)
+}
+
+func TestRepoOperationTimeout(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+
+	ctx, cancel := cfg.Repo.OperationContext(context.Background())
+	defer cancel()
+	_, ok := ctx.Deadline()
+	is.True(ok)
+
+	cfg.Repo.OperationTimeout = 0
+	is.NoErr(cfg.Validate())
+	ctx, cancel = cfg.Repo.OperationContext(context.Background())
+	defer cancel()
+	_, ok = ctx.Deadline()
+	is.True(!ok)
+
+	cfg.Repo.OperationTimeout = -1
+	is.True(cfg.Validate() != nil)
+}
+
+func TestRepoPackCompression(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.Equal(len(cfg.Repo.GitConfig()), 0)
+
+	cfg.Repo.PackCompression = 1
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Repo.GitConfig(), []string{"pack.compression=1"})
+
+	cfg.Repo.PackCompression = 10
+	is.True(cfg.Validate() != nil)
+	cfg.Repo.PackCompression = -2
+	is.True(cfg.Validate() != nil)
+}
+
+func TestRepoKeepAlive(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Repo.KeepAliveInterval(), 5*time.Second)
+
+	cfg.Repo.KeepAlive = 15
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Repo.GitConfig(), []string{"uploadpack.keepAlive=15"})
+	is.Equal(cfg.Repo.KeepAliveInterval(), 15*time.Second)
+
+	cfg.Repo.KeepAlive = -1
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Repo.GitConfig(), []string{"uploadpack.keepAlive=0"})
+	is.Equal(cfg.Repo.KeepAliveInterval(), time.Duration(0))
+
+	cfg.Repo.KeepAlive = -2
+	is.True(cfg.Validate() != nil)
+}
+
+func TestFileMarkers(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	cfg.UI.FileMarkers = nil
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.UI.FileMarkers, DefaultFileMarkers)
+
+	cfg.UI.FileMarkers = []string{"XXX", `\bNOTE\b`}
+	is.NoErr(cfg.Validate())
+
+	cfg.UI.FileMarkers = []string{"TODO("}
+	is.True(cfg.Validate() != nil)
+}
+
+func TestAuditSinks(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	cfg.Audit.Sinks = []AuditSink{
+		{Type: AuditSinkFile, Address: "audit.log"},
+		{Type: AuditSinkSyslog, Format: AuditFormatCEF, Events: []string{"push"}},
+		{Type: AuditSinkWebhook, Address: "https://siem.example.com/events"},
+	}
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Audit.BufferSize, 1000)
+	is.Equal(cfg.Audit.Sinks[0].Address, filepath.Join(cfg.DataPath, "audit.log"))
+	is.True(cfg.Audit.Sinks[0].Exports("repo_delete"))
+	is.True(cfg.Audit.Sinks[1].Exports("push"))
+	is.True(!cfg.Audit.Sinks[1].Exports("repo_delete"))
+
+	for _, s := range []AuditSink{
+		{Type: "kafka", Address: "localhost:9092"},
+		{Type: AuditSinkFile},
+		{Type: AuditSinkSyslog, Address: "localhost:514"},
+		{Type: AuditSinkWebhook, Address: "ftp://siem.example.com"},
+		{Type: AuditSinkFile, Address: "audit.log", Format: "xml"},
+		{Type: AuditSinkFile, Address: "audit.log", Events: []string{"pull"}},
+	} {
+		cfg := DefaultConfig()
+		cfg.Audit.Sinks = []AuditSink{s}
+		is.True(cfg.Validate() != nil)
+	}
+
+	cfg = DefaultConfig()
+	cfg.Audit.BufferSize = -1
+	is.True(cfg.Validate() != nil)
+}
+
+func TestIdentity(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Identity.Source, IdentitySourceStore)
+	is.Equal(cfg.Identity.File, filepath.Join(cfg.DataPath, "identities.yaml"))
+	is.Equal(cfg.Identity.TTL, 300)
+
+	cfg = DefaultConfig()
+	cfg.Identity.Source = ""
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Identity.Source, IdentitySourceStore)
+
+	cfg = DefaultConfig()
+	cfg.Identity.Source = "ldap"
+	is.True(cfg.Validate() != nil)
+
+	cfg = DefaultConfig()
+	cfg.Identity.Source = IdentitySourceFile
+	cfg.Identity.File = ""
+	is.True(cfg.Validate() != nil)
+
+	cfg = DefaultConfig()
+	cfg.Identity.TTL = -1
+	is.True(cfg.Validate() != nil)
+}
+
+func TestFollowerPrimary(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Follower.Primary, "")
+
+	cfg.Follower.Primary = "ssh://git.example.com:23231/"
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Follower.Primary, "ssh://git.example.com:23231")
+
+	for _, primary := range []string{
+		"git.example.com:23231",
+		"https://git.example.com",
+		"ssh://git.example.com:23231/repo1",
+	} {
+		cfg := DefaultConfig()
+		cfg.Follower.Primary = primary
+		is.True(cfg.Validate() != nil)
+	}
+}
+
+func TestRepoStorage(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Repo.Storage, "local")
+
+	cfg.Repo.Storage = ""
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Repo.Storage, "local")
+
+	cfg.Repo.Storage = "s3"
+	is.True(cfg.Validate() != nil)
+}
+
+func TestRepoConcurrency(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Repo.Concurrency, ConcurrencyConfig{QueueTimeout: 30})
+
+	cfg.Repo.Concurrency = ConcurrencyConfig{Max: 16, PerRepo: 4}
+	is.NoErr(cfg.Validate())
+
+	cfg.Repo.Concurrency.Max = -1
+	is.True(cfg.Validate() != nil)
+	cfg.Repo.Concurrency = ConcurrencyConfig{PerRepo: -1}
+	is.True(cfg.Validate() != nil)
+	cfg.Repo.Concurrency = ConcurrencyConfig{QueueTimeout: -1}
+	is.True(cfg.Validate() != nil)
+}
+
+func TestRepoTemplates(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	cfg.DataPath = "/data"
+	cfg.Repo.Templates = []RepoTemplate{
+		{Name: "go", Repo: "templates/go", Ref: "main", Branch: "main"},
+		{Name: "docs", Path: "templates/docs", Message: "Start the docs"},
+	}
+	is.NoErr(cfg.Validate())
+
+	tmpl, ok := cfg.Repo.Template("go")
+	is.True(ok)
+	is.Equal(tmpl.Message, DefaultTemplateMessage)
+	tmpl, ok = cfg.Repo.Template("docs")
+	is.True(ok)
+	is.Equal(tmpl.Path, filepath.Join("/data", "templates", "docs"))
+	is.Equal(tmpl.Message, "Start the docs")
+	_, ok = cfg.Repo.Template("rust")
+	is.True(!ok)
+
+	for _, templates := range [][]RepoTemplate{
+		{{Name: "", Path: "a"}},
+		{{Name: "a/b", Path: "a"}},
+		{{Name: "a"}},
+		{{Name: "a", Path: "a", Repo: "a"}},
+		{{Name: "a", Path: "a", Ref: "main"}},
+		{{Name: "a", Path: "a"}, {Name: "a", Repo: "b"}},
+		{{Name: "a", Path: "a", Branch: "a..b"}},
+		{{Name: "a", Path: "a", Branch: "a b"}},
+		{{Name: "a", Path: "a", Branch: "a.lock"}},
+	} {
+		cfg := DefaultConfig()
+		cfg.Repo.Templates = templates
+		is.True(cfg.Validate() != nil)
+	}
+}
+
+func TestDeployScripts(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	cfg.DataPath = "/data"
+	cfg.Deploy.Scripts = []DeployScript{
+		{Name: "site", Path: "deploy/site.sh"},
+		{Name: "docs", Path: "/usr/local/bin/docs"},
+	}
+	is.NoErr(cfg.Validate())
+
+	s, ok := cfg.Deploy.Script("site")
+	is.True(ok)
+	is.Equal(s.Path, filepath.Join("/data", "deploy", "site.sh"))
+	s, ok = cfg.Deploy.Script("docs")
+	is.True(ok)
+	is.Equal(s.Path, "/usr/local/bin/docs")
+	_, ok = cfg.Deploy.Script("api")
+	is.True(!ok)
+
+	for _, scripts := range [][]DeployScript{
+		{{Name: "", Path: "a"}},
+		{{Name: "a b", Path: "a"}},
+		{{Name: "a", Path: ""}},
+		{{Name: "a", Path: "a"}, {Name: "a", Path: "b"}},
+	} {
+		cfg := DefaultConfig()
+		cfg.Deploy.Scripts = scripts
+		is.True(cfg.Validate() != nil)
+	}
+
+	cfg = DefaultConfig()
+	cfg.Deploy.Timeout = 0
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Deploy.Timeout, DefaultConfig().Deploy.Timeout)
+	cfg.Deploy.Timeout = -1
+	is.True(cfg.Validate() != nil)
+	cfg = DefaultConfig()
+	cfg.Deploy.MaxMemory = -1
+	is.True(cfg.Validate() != nil)
+}
+
+func TestRepoDefaults(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	cfg.Repo.Defaults = []RepoDefaults{
+		{Match: "internal/*", Visibility: PrivateVisibility, Description: "Internal {{ .Repo }}"},
+		{Match: "internal/tmp-*", Hidden: true, ProjectName: "Scratch"},
+	}
+	is.NoErr(cfg.Validate())
+
+	d, err := cfg.Repo.DefaultsFor("internal/tmp-1")
+	is.NoErr(err)
+	is.Equal(d, RepoDefaults{
+		Visibility:  PrivateVisibility,
+		Description: "Internal internal/tmp-1",
+		ProjectName: "Scratch",
+		Hidden:      true,
+	})
+
+	d, err = cfg.Repo.DefaultsFor("internal/a/b")
+	is.NoErr(err)
+	is.Equal(d, RepoDefaults{Visibility: PublicVisibility})
+	is.True(!d.Private())
+
+	for _, d := range []RepoDefaults{
+		{Visibility: PrivateVisibility},
+		{Match: "[a"},
+		{Match: "a/*", Visibility: "internal"},
+		{Match: "a/*", Description: "{{ .Repo"},
+	} {
+		cfg.Repo.Defaults = []RepoDefaults{d}
+		is.True(cfg.Validate() != nil)
+	}
+}
+
+func TestRepoNameRules(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.NoErr(cfg.Repo.Name.Check("Some/Repo_1.0"))
+
+	cfg.Repo.Name = RepoNameConfig{
+		Pattern:   "^[a-z0-9/-]+$",
+		MaxLength: 12,
+		Prefixes:  []string{"team-a/", "team-b/"},
+		Lowercase: true,
+	}
+	is.NoErr(cfg.Validate())
+	is.NoErr(cfg.Repo.Name.Check("team-a/repo"))
+
+	for name, want := range map[string]string{
+		"team-a/Repo":      "must be lower case",
+		"team-a/long-repo": "must be at most 12 characters long",
+		"repo":             `must start with "team-a/" or "team-b/"`,
+		"team-b/re.po":     `must match "^[a-z0-9/-]+$"`,
+	} {
+		err := cfg.Repo.Name.Check(name)
+		is.True(err != nil)
+		is.Equal(err.Error(), want)
+	}
+
+	cfg.Repo.Name = RepoNameConfig{Pattern: "[a-"}
+	is.True(cfg.Validate() != nil)
+
+	cfg.Repo.Name = RepoNameConfig{MaxLength: -1}
+	is.True(cfg.Validate() != nil)
+
+	cfg.Repo.Name = RepoNameConfig{Prefixes: []string{""}}
+	is.True(cfg.Validate() != nil)
+}
+
+func TestRepoRefs(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Repo.Refs, RefsConfig{})
+
+	cfg.Repo.Refs = RefsConfig{MaxCount: 1000, MaxNameLength: 255}
+	is.NoErr(cfg.Validate())
+
+	cfg.Repo.Refs = RefsConfig{MaxCount: -1}
+	is.True(cfg.Validate() != nil)
+
+	cfg.Repo.Refs = RefsConfig{MaxNameLength: -1}
+	is.True(cfg.Validate() != nil)
+}
+
+func TestRepoCommitMessage(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.NoErr(cfg.Repo.CommitMessage.Check("anything goes", 1))
+
+	cfg.Repo.CommitMessage = CommitMessageConfig{
+		Pattern: `^(feat|fix|docs)(\(.+\))?: `,
+		Exempt:  []string{"merges", "fixups"},
+	}
+	is.NoErr(cfg.Validate())
+	for msg, parents := range map[string]int{
+		"feat(ui): add a releases tab":       1,
+		"fix: close the pipe\n\nwith a body": 1,
+		"Merge branch 'feature'":             2,
+		"fixup! feat(ui): add a tab":         1,
+	} {
+		is.NoErr(cfg.Repo.CommitMessage.Check(msg, parents)) // msg
+	}
+
+	for msg, parents := range map[string]int{
+		"add a releases tab":                      1,
+		"Revert \"feat(ui): add a releases tab\"": 1,
+		"chore: bump deps":                        0,
+	} {
+		err := cfg.Repo.CommitMessage.Check(msg, parents)
+		is.True(err != nil) // msg
+		is.Equal(err.Error(), `must match "^(feat|fix|docs)(\\(.+\\))?: "`)
+	}
+
+	cfg.Repo.CommitMessage = CommitMessageConfig{Pattern: "[a-"}
+	is.True(cfg.Validate() != nil)
+
+	cfg.Repo.CommitMessage = CommitMessageConfig{Exempt: []string{"tags"}}
+	is.True(cfg.Validate() != nil)
+}
+
+func TestUIRecentRepos(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.UI.RecentRepos, 10)
+
+	cfg.UI.RecentRepos = 0
+	is.NoErr(cfg.Validate())
+
+	cfg.UI.RecentRepos = -1
+	is.True(cfg.Validate() != nil)
+}
+
+func TestUIHighlightCache(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.UI.HighlightCache, 64)
+
+	cfg.UI.HighlightCache = 0
+	is.NoErr(cfg.Validate())
+
+	cfg.UI.HighlightCache = -1
+	is.True(cfg.Validate() != nil)
+}
+
+func TestUIBlameHeatmap(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.Equal(len(cfg.UI.BlameHeatmap), 0)
+
+	cfg.UI.BlameHeatmap = []string{"196", "#ff8700", "#0af"}
+	is.NoErr(cfg.Validate())
+
+	for _, color := range []string{"256", "-1", "red", "#ff87", "#gggggg"} {
+		cfg.UI.BlameHeatmap = []string{color}
+		is.True(cfg.Validate() != nil)
+	}
+}
+
+func TestUITabs(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.UI.Tabs, RepoTabs)
+
+	cfg.UI.Tabs = nil
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.UI.Tabs, RepoTabs)
+
+	cfg.UI.Tabs = []string{"files", "readme"}
+	cfg.UI.TabLabels = map[string]string{"files": "Code"}
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.UI.TabLabel("files", "Files"), "Code")
+	is.Equal(cfg.UI.TabLabel("readme", "Readme"), "Readme")
+
+	cfg.UI.Tabs = []string{"files", "files"}
+	is.True(cfg.Validate() != nil)
+	cfg.UI.Tabs = []string{"issues"}
+	is.True(cfg.Validate() != nil)
+
+	cfg.UI.Tabs = nil
+	cfg.UI.TabLabels = map[string]string{"issues": "Issues"}
+	is.True(cfg.Validate() != nil)
+	cfg.UI.TabLabels = map[string]string{"files": " "}
+	is.True(cfg.Validate() != nil)
+
+	t.Setenv("SOFT_SERVE_UI_TABS", "commits,files")
+	t.Setenv("SOFT_SERVE_UI_TAB_LABELS", "commits:History,files:Code")
+	cfg = DefaultConfig()
+	is.NoErr(cfg.ParseEnv())
+	is.Equal(cfg.UI.Tabs, []string{"commits", "files"})
+	is.Equal(cfg.UI.TabLabels, map[string]string{"commits": "History", "files": "Code"})
+	is.True(slices.Contains(cfg.Environ(), "SOFT_SERVE_UI_TAB_LABELS=commits:History,files:Code"))
+}
+
+func TestUIStatusBar(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.UI.StatusBar, DefaultStatusBar)
+
+	cfg.UI.StatusBar = nil
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.UI.StatusBar, DefaultStatusBar)
+
+	cfg.UI.StatusBar = []string{"key", "value", "sha:100", "time"}
+	is.NoErr(cfg.Validate())
+	name, width, err := ParseStatusBarSegment("sha:100")
+	is.NoErr(err)
+	is.Equal(name, "sha")
+	is.Equal(width, 100)
+
+	for _, bar := range [][]string{
+		{"key", "key"},
+		{"key", "key:80"},
+		{"commit"},
+		{"sha:"},
+		{"sha:0"},
+		{"sha:wide"},
+	} {
+		cfg.UI.StatusBar = bar
+		is.True(cfg.Validate() != nil)
+	}
+
+	t.Setenv("SOFT_SERVE_UI_STATUS_BAR", "key,access:120")
+	cfg = DefaultConfig()
+	is.NoErr(cfg.ParseEnv())
+	is.Equal(cfg.UI.StatusBar, []string{"key", "access:120"})
+	is.True(slices.Contains(cfg.Environ(), "SOFT_SERVE_UI_STATUS_BAR=key,access:120"))
+}
+
+func TestUIWelcome(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.UI.Welcome.Repo, "")
+	is.Equal(cfg.UI.Welcome.Height, 10)
+
+	cfg.UI.Welcome.Height = 0
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.UI.Welcome.Height, DefaultWelcomeHeight)
+	cfg.UI.Welcome.Height = -1
+	is.True(cfg.Validate() != nil)
+
+	t.Setenv("SOFT_SERVE_UI_WELCOME_REPO", "announcements")
+	t.Setenv("SOFT_SERVE_UI_WELCOME_HEIGHT", "5")
+	cfg = DefaultConfig()
+	is.NoErr(cfg.ParseEnv())
+	is.Equal(cfg.UI.Welcome, WelcomeConfig{Repo: "announcements", Height: 5})
+	is.True(slices.Contains(cfg.Environ(), "SOFT_SERVE_UI_WELCOME_REPO=announcements"))
+}
+
+func TestGitDaemon(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.True(!cfg.Git.Enabled)
+	is.Equal(cfg.Git.RateLimit, 60)
+
+	cfg.Git.RateLimit = 0
+	is.NoErr(cfg.Validate())
+
+	cfg.Git.RateLimit = -1
+	is.True(cfg.Validate() != nil)
+}
+
+func TestCORSAllowedOrigins(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.Equal(len(cfg.HTTP.CORS.AllowedOrigins), 0)
+
+	cfg.HTTP.CORS.AllowedOrigins = []string{"*", "https://example.com/", "http://localhost:8080"}
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.HTTP.CORS.AllowedOrigins, []string{"*", "https://example.com", "http://localhost:8080"})
+
+	for _, origin := range []string{"example.com", "ftp://example.com", "https://example.com/dashboard"} {
+		cfg.HTTP.CORS.AllowedOrigins = []string{origin}
+		is.True(cfg.Validate() != nil)
+	}
+}
+
+func TestHTTPAPI(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.HTTP.API.Path, "")
+	is.Equal(cfg.HTTP.API.RateLimit, 60)
+
+	cfg.HTTP.API.Path = "dash/api/"
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.HTTP.API.Path, "/dash/api")
+
+	cfg.HTTP.API.Path = ""
+	is.NoErr(cfg.Validate())
+
+	cfg.HTTP.API.Path = "/"
+	is.True(cfg.Validate() != nil)
+
+	cfg.HTTP.API.Path = "/api"
+	cfg.HTTP.API.RateLimit = -1
+	is.True(cfg.Validate() != nil)
+}
+
+func TestUIEmptyMessages(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	cfg.UI.Empty.Readme = ""
+	cfg.UI.Empty.Repo = "Push to {{ .CloneURL }} to create {{ .Repo }}."
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.UI.Empty.Readme, "No readme found.")
+
+	msg, err := cfg.UI.Empty.RepoMessage("repo1", "ssh://localhost/repo1.git")
+	is.NoErr(err)
+	is.Equal(msg, "Push to ssh://localhost/repo1.git to create repo1.")
+
+	cfg.UI.Empty.Repo = "{{ .CloneURL"
+	is.True(cfg.Validate() != nil)
+	cfg.UI.Empty.Repo = "{{ .Owner }}"
+	is.True(cfg.Validate() != nil)
+}
+
+func TestTrustedUserCAKeys(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	cfg.SSH.TrustedUserCAKeys = []string{
+		"testdata/k1.pub",
+		"",
+		"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFxIobhwtfdwN7m1TFt9wx3PsfvcAkISGPxmbmbauST8 ca@b",
+	}
+	cfg.SSH.RevokedCertificates = []string{" 42 ", ""}
+	is.NoErr(cfg.Validate())
+	is.Equal(len(cfg.UserCAKeys()), 2)
+	is.Equal(cfg.SSH.RevokedCertificates, []string{"42"})
+
+	cfg.SSH.TrustedUserCAKeys = []string{"abc"}
+	is.True(cfg.Validate() != nil)
+}
+
+func TestCommandRules(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	cfg.Commands.Rules = []CommandRule{
+		{Command: "repo", Roles: []string{"user"}},
+		{Command: " repo  import ", Users: []string{"alice"}},
+		{Command: "repo import-all", Keys: []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFxIobhwtfdwN7m1TFt9wx3PsfvcAkISGPxmbmbauST8 a@b"}},
+	}
+	is.NoErr(cfg.Validate())
+	is.True(cfg.Commands.Rule("info") == nil)
+	is.True(cfg.Commands.Rule("repos") == nil)
+	is.Equal(cfg.Commands.Rule("repo list").Command, "repo")
+	is.Equal(cfg.Commands.Rule("repo import").Command, " repo  import ")
+	is.Equal(cfg.Commands.Rule("repo import-all").Command, "repo import-all")
+	is.Equal(len(cfg.Commands.Rules[2].PublicKeys()), 1)
+
+	cfg.Commands.Rules[0].Roles = []string{"owner"}
+	is.True(cfg.Validate() != nil)
+
+	cfg.Commands.Rules[0].Roles = nil
+	cfg.Commands.Rules[2].Keys = []string{"abc"}
+	is.True(cfg.Validate() != nil)
+
+	cfg = DefaultConfig()
+	cfg.Commands.Rules = []CommandRule{{Command: " ", Roles: []string{"admin"}}}
+	is.True(cfg.Validate() != nil)
+}
+
+func TestHousekeeping(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Housekeeping.TasksFor("repo1"), []string{"repack", "prune", "pack-refs"})
+
+	cfg.Housekeeping.Repos = []HousekeepingRepo{
+		{Match: "mirrors/*", Tasks: []string{"commit-graph", "gc"}},
+		{Match: "mirrors/big", Tasks: nil},
+	}
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Housekeeping.TasksFor("mirrors/small"), []string{"gc", "commit-graph"})
+	is.Equal(len(cfg.Housekeeping.TasksFor("mirrors/big")), 0)
+	is.Equal(cfg.Housekeeping.TasksFor("mirrors/a/b"), []string{"repack", "prune", "pack-refs"})
+
+	cfg.Housekeeping.Repos[0].Tasks = []string{"compact"}
+	is.True(cfg.Validate() != nil)
+
+	cfg = DefaultConfig()
+	cfg.Housekeeping.Tasks = []string{"gc", "compact"}
+	is.True(cfg.Validate() != nil)
+
+	// fsck runs first, before the objects are rewritten.
+	cfg.Housekeeping.Tasks = []string{"gc", "fsck"}
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.Housekeeping.TasksFor("repo1"), []string{"fsck", "gc"})
+
+	cfg = DefaultConfig()
+	cfg.Housekeeping.Jitter = -1
+	is.True(cfg.Validate() != nil)
+
+	cfg = DefaultConfig()
+	cfg.Housekeeping.Repos = []HousekeepingRepo{{Tasks: []string{"gc"}}}
+	is.True(cfg.Validate() != nil)
+}
+
+func TestCloneURL(t *testing.T) {
+	is := is.New(t)
+	cfg := DefaultConfig()
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.SSHCloneURL("org/repo1"), "ssh://localhost:23231/org/repo1.git")
+	is.Equal(cfg.HTTPCloneURL("repo1"), "http://localhost:23232/repo1.git")
+	is.Equal(cfg.GitCloneURL("repo1"), "git://localhost/repo1.git")
+
+	cfg.SSH.CloneURL = "ssh://git@{host}:{port}/{repo}"
+	cfg.HTTP.CloneURL = "https://{host}/git/{repo}.git"
+	cfg.Git.CloneURL = "{scheme}://{host}:{port}/{repo}"
+	is.NoErr(cfg.Validate())
+	is.Equal(cfg.SSHCloneURL("org/repo1"), "ssh://git@localhost:23231/org/repo1")
+	is.Equal(cfg.HTTPCloneURL("repo1"), "https://localhost/git/repo1.git")
+	is.Equal(cfg.GitCloneURL("repo1"), "git://localhost:9418/repo1")
+
+	for _, tmpl := range []string{"ssh://{host}/{name}", "ssh://{host}/repo"} {
+		cfg.SSH.CloneURL = tmpl
+		is.True(cfg.Validate() != nil)
+	}
+}
@@ -15,10 +15,14 @@ name: "{{ .Name }}"
 log:
   # Log format to use. Valid values are "json", "logfmt", and "text".
   format: "{{ .Log.Format }}"
+  # Minimum level of the logs. Valid values are "debug", "info", "warn", and
+  # "error".
+  level: "{{ .Log.Level }}"
   # Time format for the log "timestamp" field.
   # Should be described in Golang's time format.
   time_format: "{{ .Log.TimeFormat }}"
-  # Path to the log file. Leave empty to write to stderr.
+  # Path to the log file, or "stdout" or "stderr". Leave empty to write to
+  # stderr.
   #path: "{{ .Log.Path }}"
 
 # The SSH server configuration.
@@ -30,6 +34,11 @@ ssh:
   # This is the address that will be used to clone repositories.
   public_url: "{{ .SSH.PublicURL }}"
 
+  # The template of the clone URLs, e.g. "ssh://git@{host}:{port}/{repo}".
+  # {scheme}, {host}, and {port} come from the public URL, {repo} is the
+  # repository. Leave it empty to use the public URL.
+  clone_url: "{{ .SSH.CloneURL }}"
+
   # The path to the SSH server's private key.
   key_path: {{ .SSH.KeyPath }}
 
@@ -45,8 +54,32 @@ ssh:
   # A value of 0 means no timeout.
   idle_timeout: {{ .SSH.IdleTimeout }}
 
+  # The certificate authority public keys, or paths to files containing them,
+  # that are trusted to sign user certificates. A user certificate principal
+  # that matches a username authenticates as that user.
+  # trusted_user_ca_keys:
+  #   - "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOx... ca@example.com"
+
+  # The revoked user certificates. An entry matches a certificate serial
+  # number, key ID, or the SHA256 fingerprint of the certified key.
+  # revoked_certificates:
+  #   - "42"
+
+  # What interactive sessions without a command run by default, the terminal
+  # UI "tui" or the command shell "shell". Users can change it for themselves
+  # with "prefs interactive".
+  interactive: "{{ .SSH.Interactive }}"
+
+  # When the output of commands run in interactive sessions is shown in a
+  # pager: "auto" when it doesn't fit the terminal, "always", or "never".
+  # Users can change it for themselves with "prefs pager".
+  pager: "{{ .SSH.Pager }}"
+
 # The Git daemon configuration.
 git:
+  # Serve public repositories read-only over the anonymous git:// protocol.
+  enabled: {{ .Git.Enabled }}
+
   # The address on which the Git daemon will listen.
   listen_addr: "{{ .Git.ListenAddr }}"
 
@@ -54,6 +87,9 @@ git:
   # This is the address that will be used to clone repositories.
   public_url: "{{ .Git.PublicURL }}"
 
+  # The template of the clone URLs, like the SSH one.
+  clone_url: "{{ .Git.CloneURL }}"
+
   # The maximum number of seconds a connection can take.
   # A value of 0 means no timeout.
   max_timeout: {{ .Git.MaxTimeout }}
@@ -64,6 +100,10 @@ git:
   # The maximum number of concurrent connections.
   max_connections: {{ .Git.MaxConnections }}
 
+  # The maximum number of connections per minute from a client address.
+  # A value of 0 means no limit.
+  rate_limit: {{ .Git.RateLimit }}
+
 # The HTTP server configuration.
 http:
   # The address on which the HTTP server will listen.
@@ -80,6 +120,27 @@ http:
   # Make sure to use https:// if you are using TLS.
   public_url: "{{ .HTTP.PublicURL }}"
 
+  # The template of the clone URLs, like the SSH one.
+  clone_url: "{{ .HTTP.CloneURL }}"
+
+  # The CORS configuration.
+  cors:
+    # The origins allowed to read public repositories from a browser, e.g.
+    # a web dashboard. Use "*" to allow any origin. Private repositories are
+    # never shared across origins.
+    allowed_origins:{{ range .HTTP.CORS.AllowedOrigins }}
+      - "{{ . }}"{{ else }} []{{ end }}
+
+  # The read-only JSON API configuration.
+  api:
+    # The base path of the API, e.g. "/api". The API shadows the repositories
+    # under the path. Leave it empty to disable the API.
+    path: "{{ .HTTP.API.Path }}"
+
+    # The maximum number of API requests per minute from a client address.
+    # Set it to 0 to disable the limit.
+    rate_limit: {{ .HTTP.API.RateLimit }}
+
 # The stats server configuration.
 stats:
   # The address on which the stats server will listen.
@@ -101,16 +162,361 @@ lfs:
   enabled: {{ .LFS.Enabled }}
   # Enable Git SSH transfer.
   ssh_enabled: {{ .LFS.SSHEnabled }}
+  # Include the LFS objects instead of their pointers in "repo export"
+  # archives by default.
+  archive_objects: {{ .LFS.ArchiveObjects }}
 
 # Cron job configuration
 jobs:
   mirror_pull: "{{ .Jobs.MirrorPull }}"
+  # How often the commit-graphs of repositories are updated, when enabled
+  # with "repo.commit_graph".
+  commit_graph: "{{ .Jobs.CommitGraph }}"
+  # How often the housekeeping tasks of the repositories run, see
+  # "housekeeping".
+  housekeeping: "{{ .Jobs.Housekeeping }}"
+  # How often the idle repositories are archived, see "repo.archive_after".
+  archive: "{{ .Jobs.Archive }}"
+  # How often a follower syncs with its primary, see "follower.primary".
+  follower_sync: "{{ .Jobs.FollowerSync }}"
+  # How often the sizes of the repositories are recorded, see
+  # "repo info --health" and "admin sizes".
+  repo_sizes: "{{ .Jobs.RepoSizes }}"
+
+# The housekeeping of the repositories, Git maintenance tasks that keep them
+# fast and small. The tasks run on the schedule of "jobs.housekeeping", and
+# repositories that are being pushed to are skipped until the next run. Admins
+# see the recent runs with "admin housekeeping".
+housekeeping:
+  # The tasks run on every repository, in this order:
+  #   fsck: verify the objects with git fsck
+  #   gc: git gc, which does all of the other tasks at once
+  #   repack: pack the loose objects and remove the redundant packs
+  #   prune: remove the unreachable loose objects older than two weeks
+  #   pack-refs: pack the references
+  #   commit-graph: write the commit-graph
+  # Leave it empty to disable housekeeping.
+  tasks:{{ range .Housekeeping.Tasks }}
+    - "{{ . }}"{{ else }} []{{ end }}
+  # The maximum number of seconds each repository waits for before its tasks
+  # run, so that they don't all run at once.
+  jitter: {{ .Housekeeping.Jitter }}
+  # Archive the repositories the fsck task finds missing or corrupt objects in,
+  # so that they're read-only until an admin looks at them.
+  quarantine: {{ .Housekeeping.Quarantine }}
+  # The tasks of the repositories whose names match a glob, instead of the
+  # global ones. The last matching entry wins, and an empty list disables
+  # housekeeping for the repositories.
+  #   - match: "mirrors/*"
+  #     tasks: ["gc"]
+  repos:{{ range .Housekeeping.Repos }}
+    - match: {{ printf "%q" .Match }}
+      tasks:{{ range .Tasks }}
+        - "{{ . }}"{{ else }} []{{ end }}{{ else }} []{{ end }}
+
+# Repository configuration.
+repo:
+  # The visibility of new repositories created with "repo create" or by
+  # pushing to a non-existent repository.
+  # Valid values are "public" and "private".
+  default_visibility: "{{ .Repo.DefaultVisibility }}"
+  # The maximum number of seconds an expensive operation, like blame or
+  # archive, can take before it's canceled. Set to 0 to disable.
+  operation_timeout: {{ .Repo.OperationTimeout }}
+  # The zlib compression level, from 0 (none) to 9 (best), of the packs sent
+  # to clients that fetch or clone. Lower levels use less CPU and send more
+  # data, which suits fast networks and busy servers. Objects that are already
+  # packed are sent as they are. Set to -1 to use the default of git.
+  pack_compression: {{ .Repo.PackCompression }}
+  # The number of seconds between the keepalive messages sent to clients
+  # while the server prepares the pack of a fetch or clone, so that slow
+  # clones of large repositories aren't dropped as idle. Set to 0 to use the
+  # default of git, 5 seconds, or -1 to disable them.
+  keepalive: {{ .Repo.KeepAlive }}
+  # Maintain commit-graph files and pack bitmaps to speed up reading the
+  # history of large repositories, like the log and commit counts.
+  commit_graph: {{ .Repo.CommitGraph }}
+  # The storage repositories are kept in. "local" keeps them in the repos
+  # directory of the data path, other storages are registered by builds that
+  # include them.
+  storage: "{{ .Repo.Storage }}"
+  # The git operations, fetches, clones, archives, and pushes, running at the
+  # same time. Operations over the limits wait up to "queue_timeout" seconds
+  # for the others to finish, then are rejected. Admins are exempt. Set the
+  # limits to 0 to disable them.
+  concurrency:
+    max: {{ .Repo.Concurrency.Max }}
+    per_repo: {{ .Repo.Concurrency.PerRepo }}
+    queue_timeout: {{ .Repo.Concurrency.QueueTimeout }}
+  # The number of days without pushes after which repositories are archived.
+  # Archived repositories can be cloned but reject pushes, and are listed
+  # last. Unarchive them with "repo archive-flag". Set to 0 to disable.
+  archive_after: {{ .Repo.ArchiveAfter }}
+
+  # The default settings of new repositories whose names match a glob, e.g.
+  # "internal/*". Every matching entry applies in order, and the settings
+  # passed to "repo create" or "repo import" override them. The description
+  # and project name are templates, {{"{{"}} .Repo {{"}}"}} is the repository name.
+  #   - match: "internal/*"
+  #     visibility: "private"
+  #     description: "Internal {{"{{"}} .Repo {{"}}"}} repository"
+  #     project_name: ""
+  #     hidden: false
+  defaults:{{ range .Repo.Defaults }}
+    - match: {{ printf "%q" .Match }}
+      visibility: {{ printf "%q" .Visibility }}
+      description: {{ printf "%q" .Description }}
+      project_name: {{ printf "%q" .ProjectName }}
+      hidden: {{ .Hidden }}{{ else }} []{{ end }}
+
+  # The templates new repositories can be created from with "repo create
+  # --template". The initial commit of the repository has the files of either
+  # a hosted repository, as of "ref", or a directory, relative to the data
+  # directory unless absolute. "branch" is the default branch of the new
+  # repositories, the default one of git when it's empty.
+  #   - name: "go"
+  #     description: "Go module with a README and a LICENSE"
+  #     repo: "templates/go"
+  #     ref: "main"
+  #     path: ""
+  #     branch: "main"
+  #     message: "Initial commit"
+  templates:{{ range .Repo.Templates }}
+    - name: {{ printf "%q" .Name }}
+      description: {{ printf "%q" .Description }}
+      repo: {{ printf "%q" .Repo }}
+      ref: {{ printf "%q" .Ref }}
+      path: {{ printf "%q" .Path }}
+      branch: {{ printf "%q" .Branch }}
+      message: {{ printf "%q" .Message }}{{ else }} []{{ end }}
+
+  # The rules repository names must follow when a repository is created,
+  # imported, renamed, or created by a push.
+  name:
+    # A regular expression names must match, e.g. "^[a-z0-9/-]+$".
+    pattern: {{ printf "%q" .Repo.Name.Pattern }}
+    # The maximum number of characters of a name. Set to 0 to disable.
+    max_length: {{ .Repo.Name.MaxLength }}
+    # The prefixes names must start with one of, e.g. "team-a/".
+    prefixes:{{ range .Repo.Name.Prefixes }}
+      - "{{ . }}"{{ else }} []{{ end }}
+    # Require names to be lower case.
+    lowercase: {{ .Repo.Name.Lowercase }}
+
+  # The policy the messages of pushed commits must follow, checked on the
+  # commits a push adds before any reference is updated.
+  commit_message:
+    # A regular expression commit messages must match, e.g.
+    # "^(feat|fix|docs|chore)(\\(.+\\))?!?: " for Conventional Commits. Leave
+    # it empty to accept any message.
+    pattern: {{ printf "%q" .Repo.CommitMessage.Pattern }}
+    # Shown to the pushers of rejected commits, e.g. a link to the guidelines.
+    hint: {{ printf "%q" .Repo.CommitMessage.Hint }}
+    # The commits the policy doesn't apply to, out of "merges", "reverts", and
+    # "fixups".
+    exempt:{{ range .Repo.CommitMessage.Exempt }}
+      - "{{ . }}"{{ else }} []{{ end }}
+
+  # The limits of the references of repositories, checked on the references a
+  # push creates. Repositories already over the limits keep their references.
+  refs:
+    # The maximum number of references of a repository. Set to 0 to disable.
+    max_count: {{ .Repo.Refs.MaxCount }}
+    # The maximum number of bytes of the full name of a reference, e.g.
+    # "refs/heads/main". Set to 0 to disable.
+    max_name_length: {{ .Repo.Refs.MaxNameLength }}
+
+# The deploy scripts configuration. Admins bind scripts to the branches of a
+# repository with "repo deploy set", and pushes to those branches run them
+# once the references are updated. The output is shown to the pusher and kept
+# in the activity feed.
+deploy:
+  # The maximum number of seconds a deploy script can run before it's killed.
+  timeout: {{ .Deploy.Timeout }}
+  # The maximum number of bytes of the output kept in the activity feed.
+  max_output: {{ .Deploy.MaxOutput }}
+  # The maximum virtual memory, in megabytes, and CPU time, in seconds, of a
+  # deploy script. Set to 0 to disable. They aren't enforced on Windows.
+  max_memory: {{ .Deploy.MaxMemory }}
+  max_cpu: {{ .Deploy.MaxCPU }}
+
+  # The scripts repositories can run. Relative paths are relative to the data
+  # directory. Scripts run in the repository with only the environment
+  # variables SOFT_SERVE_REPO_NAME, SOFT_SERVE_REPO_PATH,
+  # SOFT_SERVE_DEPLOY_SCRIPT, SOFT_SERVE_DEPLOY_REF, SOFT_SERVE_DEPLOY_BRANCH,
+  # SOFT_SERVE_DEPLOY_OLD_SHA, SOFT_SERVE_DEPLOY_NEW_SHA, PATH, and HOME, and
+  # the push options in GIT_PUSH_OPTION_COUNT and GIT_PUSH_OPTION_<n>.
+  #   - name: "site"
+  #     path: "deploy/site.sh"
+  scripts:{{ range .Deploy.Scripts }}
+    - name: {{ printf "%q" .Name }}
+      path: {{ printf "%q" .Path }}{{ else }} []{{ end }}
+
+# The attestations of repositories. The server signs the references of mirrors
+# after every sync, and admins can sign any repository with "repo attestation
+# sign". An attestation states what the server held, not who authored it.
+attestation:
+  # The SSH private key attestations are signed with. An ed25519 key is
+  # generated if it doesn't exist. Leave empty to disable attestations.
+  key_path: "{{ .Attestation.KeyPath }}"
+
+# The export of the events of the server, the ones "repo watch" streams, to
+# external sinks like a SIEM. Delivery is best-effort: events are buffered
+# while a sink can't be reached, and the oldest are dropped once the buffer is
+# full.
+audit:
+  # The number of events buffered per sink.
+  buffer_size: {{ .Audit.BufferSize }}
+
+  # The sinks. The type is "file", "syslog", or "webhook", the address the
+  # path of the file, relative to the data directory, the address of the
+  # syslog server, empty for the local one, or the URL of the webhook. The
+  # format is "json" or "cef", and events filters the types of the events
+  # exported, all of them if empty.
+  #   - type: "syslog"
+  #     address: "udp://siem.example.com:514"
+  #     format: "cef"
+  #     events: ["push", "repo_delete"]
+  sinks:{{ range .Audit.Sinks }}
+    - type: {{ printf "%q" .Type }}
+      address: {{ printf "%q" .Address }}
+      format: {{ printf "%q" .Format }}
+      events:{{ range .Events }}
+        - {{ printf "%q" . }}{{ else }} []{{ end }}{{ else }} []{{ end }}
+
+# The follower mode. A follower is a read replica of a primary server: it
+# mirrors the repositories of the primary on the schedule of
+# "jobs.follower_sync", serves clones, fetches, and the UI, and rejects writes
+# with a message pointing at the primary. Users and collaborators are managed
+# on each server.
+follower:
+  # The SSH URL of the primary, e.g. "ssh://git.example.com:23231". The
+  # follower mode is disabled when it's empty. The client key of the server
+  # must have read access to the repositories of the primary.
+  primary: "{{ .Follower.Primary }}"
+
+# Who can run the commands of the server, on top of the access they require.
+commands:
+  # Apply the rules to the admins too. Admins can run any command otherwise.
+  enforce_admins: {{ .Commands.EnforceAdmins }}
+
+  # The rules. A rule restricts a command, and its subcommands, to the listed
+  # users, keys, and roles, "admin" or "user" for any registered user. The
+  # rule of the longest command wins.
+  #   - command: "repo import"
+  #     users: ["alice"]
+  #     keys: ["ssh-ed25519 AAAA..."]
+  #     roles: ["admin"]
+  rules:{{ range .Commands.Rules }}
+    - command: {{ printf "%q" .Command }}
+      users:{{ range .Users }}
+        - {{ printf "%q" . }}{{ else }} []{{ end }}
+      keys:{{ range .Keys }}
+        - {{ printf "%q" . }}{{ else }} []{{ end }}
+      roles:{{ range .Roles }}
+        - {{ printf "%q" . }}{{ else }} []{{ end }}{{ else }} []{{ end }}
+
+# The source public keys are resolved to users from when they authenticate.
+# Users, their names, and their team memberships are created and synced from
+# the source, teams must exist already. Keys and memberships removed from the
+# source are removed from the server once their cache expires.
+identity:
+  # The source, "store" for the users of the server, or "file" for a YAML
+  # file listing the users with their keys:
+  #   users:
+  #     - username: "alice"
+  #       name: "Alice"
+  #       teams: ["devs"]
+  #       keys: ["ssh-ed25519 AAAA..."]
+  source: "{{ .Identity.Source }}"
+
+  # The path of the identities file, relative to the data directory.
+  file: "{{ .Identity.File }}"
+
+  # The time in seconds a resolved key is cached for, 0 to resolve it on
+  # every authentication.
+  ttl: {{ .Identity.TTL }}
+
+# The SSH terminal UI configuration.
+ui:
+  # Hide the clone command in the repository header. It can still be copied
+  # to the clipboard.
+  hide_clone_url: {{ .UI.HideCloneURL }}
+
+  # The number of recently viewed repositories shown in the quick switcher
+  # (ctrl+r). Set it to 0 to disable the switcher.
+  recent_repos: {{ .UI.RecentRepos }}
+
+  # The size, in megabytes, of the cache of highlighted files shared by all
+  # the sessions. Set it to 0 to disable the cache.
+  highlight_cache: {{ .UI.HighlightCache }}
+
+  # The colors of the blame heatmap (H in the blame view) from the most recent
+  # to the oldest changes, as ANSI 256 color numbers or hex colors. Leave it
+  # empty to use the colors of the theme.
+  blame_heatmap:{{ range .UI.BlameHeatmap }}
+    - "{{ . }}"{{ else }} []{{ end }}
+
+  # The tabs of a repository in the order they're shown, out of "readme",
+  # "files", "commits", "stash", "branches", "tags", "releases", and
+  # "insights". The tabs that aren't listed are hidden, the stash tab only
+  # shows when there is a stash, and the releases tab when there are tags.
+  tabs:{{ range .UI.Tabs }}
+    - "{{ . }}"{{ else }} []{{ end }}
+  # The names shown in place of the default names of tabs, by tab, e.g.
+  # commits: "History".
+  tab_labels:{{ range $tab, $label := .UI.TabLabels }}
+    {{ $tab }}: {{ printf "%q" $label }}{{ else }} {}{{ end }}
+
+  # The segments of the status bar of a repository in the order they're
+  # shown, out of "key", "value", "info", "branch", "sha", "access", and
+  # "time". A segment followed by ":WIDTH", e.g. "time:100", only shows when
+  # the terminal is at least WIDTH columns wide.
+  status_bar:{{ range .UI.StatusBar }}
+    - "{{ . }}"{{ else }} []{{ end }}
+
+  # The patterns of the markers, like TODO comments, listed in the file viewer
+  # (m), as regular expressions matched against every line.
+  file_markers:{{ range .UI.FileMarkers }}
+    - {{ printf "%q" . }}{{ else }} []{{ end }}
+
+  # The icons shown before the names of files (prefs file-icons): "none",
+  # "unicode" for symbols every terminal shows, or "nerd" for the icons of
+  # Nerd Fonts (https://www.nerdfonts.com).
+  file_icons: {{ printf "%q" .UI.FileIcons }}
+
+  # The welcome message shown above the repository list, the README of a
+  # repository, e.g. to post announcements. Users can dismiss it until the
+  # README changes.
+  welcome:
+    # The repository whose README is shown, empty to disable the message.
+    repo: {{ printf "%q" .UI.Welcome.Repo }}
+    # The maximum number of lines of the message.
+    height: {{ .UI.Welcome.Height }}
+
+  # The messages shown when there is nothing to show. The empty repository
+  # message is a Markdown template where {{"{{"}} .Repo }} is the repository
+  # name and {{"{{"}} .CloneURL }} its clone URL.
+  empty:
+    # The readme tab of repositories without a readme.
+    readme: {{ printf "%q" .UI.Empty.Readme }}
+    # The files tab when there are no files.
+    files: {{ printf "%q" .UI.Empty.Files }}
+    # The log tab when there are no commits.
+    log: {{ printf "%q" .UI.Empty.Log }}
+    # The readme tab of empty repositories.
+    repo: {{ printf "%q" .UI.Empty.Repo }}
 
 # Additional admin keys.
 #initial_admin_keys:
 #  - "ssh-rsa AAAAB3NzaC1yc2..."
 `))
 
+// Dump returns the configuration as a YAML config file.
+func (c *Config) Dump() string {
+	return newConfigFile(c)
+}
+
 func newConfigFile(cfg *Config) string {
 	var b bytes.Buffer
 	configFileTmpl.Execute(&b, cfg) // nolint: errcheck
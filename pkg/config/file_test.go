@@ -1,6 +1,10 @@
 package config
 
-import "testing"
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
 
 func TestNewConfigFile(t *testing.T) {
 	for _, cfg := range []*Config{
@@ -13,3 +17,16 @@ func TestNewConfigFile(t *testing.T) {
 		}
 	}
 }
+
+func TestConfigFileEmptyMessages(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.UI.Empty.Readme = "No readme.\n\nSee \"CONTRIBUTING.md\"."
+
+	var got Config
+	if err := yaml.Unmarshal([]byte(newConfigFile(cfg)), &got); err != nil {
+		t.Fatalf("yaml.Unmarshal() => %v", err)
+	}
+	if got.UI.Empty != cfg.UI.Empty {
+		t.Errorf("empty messages => %#v, want %#v", got.UI.Empty, cfg.UI.Empty)
+	}
+}
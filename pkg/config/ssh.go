@@ -26,3 +26,18 @@ func KeyPair(cfg *Config) (*keygen.SSHKeyPair, error) {
 
 	return keygen.New(cfg.SSH.KeyPath, keygen.WithKeyType(keygen.Ed25519))
 }
+
+// AttestationKeyPair returns the key pair attestations are signed with. An
+// ed25519 key pair is generated if it doesn't exist. It returns
+// ErrEmptySSHKeyPath when attestations are disabled.
+func AttestationKeyPair(cfg *Config) (*keygen.SSHKeyPair, error) {
+	if cfg == nil {
+		return nil, ErrNilConfig
+	}
+
+	if cfg.Attestation.KeyPath == "" {
+		return nil, ErrEmptySSHKeyPath
+	}
+
+	return keygen.New(cfg.Attestation.KeyPath, keygen.WithKeyType(keygen.Ed25519), keygen.WithWrite())
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// cloneURLVar matches the variables of clone URL templates, e.g. "{host}".
+var cloneURLVar = regexp.MustCompile(`\{[^{}]*\}`)
+
+// cloneURLVars are the variables of clone URL templates.
+var cloneURLVars = []string{"{scheme}", "{host}", "{port}", "{repo}"}
+
+// defaultPorts are the ports of the public URL schemes that leave them out.
+var defaultPorts = map[string]string{
+	"ssh":   "22",
+	"http":  "80",
+	"https": "443",
+	"git":   "9418",
+}
+
+// SSHCloneURL returns the URL to clone a repository over SSH.
+func (c *Config) SSHCloneURL(repo string) string {
+	return cloneURL(c.SSH.CloneURL, c.SSH.PublicURL, repo)
+}
+
+// HTTPCloneURL returns the URL to clone a repository over HTTP.
+func (c *Config) HTTPCloneURL(repo string) string {
+	return cloneURL(c.HTTP.CloneURL, c.HTTP.PublicURL, repo)
+}
+
+// GitCloneURL returns the URL to clone a repository over the Git daemon.
+func (c *Config) GitCloneURL(repo string) string {
+	return cloneURL(c.Git.CloneURL, c.Git.PublicURL, repo)
+}
+
+// cloneURL renders a clone URL template with the scheme, host, and port of
+// the public URL and the path of the repository. Without a template, the
+// clone URL is the public URL followed by the repository path.
+func cloneURL(tmpl, publicURL, repo string) string {
+	repo = utils.SanitizeRepo(repo)
+	if tmpl == "" {
+		return fmt.Sprintf("%s/%s.git", publicURL, repo)
+	}
+
+	var scheme, host, port string
+	if u, err := url.Parse(publicURL); err == nil {
+		scheme, host, port = u.Scheme, u.Hostname(), u.Port()
+		if port == "" {
+			port = defaultPorts[scheme]
+		}
+	}
+	return strings.NewReplacer(
+		"{scheme}", scheme,
+		"{host}", host,
+		"{port}", port,
+		"{repo}", repo,
+	).Replace(tmpl)
+}
+
+// validCloneURL returns an error if a clone URL template uses an unknown
+// variable or leaves the repository out.
+func validCloneURL(tmpl string) error {
+	if tmpl == "" {
+		return nil
+	}
+	for _, v := range cloneURLVar.FindAllString(tmpl, -1) {
+		if !slices.Contains(cloneURLVars, v) {
+			return fmt.Errorf("unknown variable %s: must be one of %s", v, strings.Join(cloneURLVars, ", "))
+		}
+	}
+	if !strings.Contains(tmpl, "{repo}") {
+		return fmt.Errorf("must contain {repo}")
+	}
+	return nil
+}
@@ -15,6 +15,8 @@ import (
 	"github.com/charmbracelet/soft-serve/pkg/backend"
 	"github.com/charmbracelet/soft-serve/pkg/config"
 	"github.com/charmbracelet/soft-serve/pkg/git"
+	logr "github.com/charmbracelet/soft-serve/pkg/log"
+	"github.com/charmbracelet/soft-serve/pkg/ratelimit"
 	"github.com/charmbracelet/soft-serve/pkg/utils"
 	"github.com/go-git/go-git/v5/plumbing/format/pktline"
 	"github.com/prometheus/client_golang/prometheus"
@@ -47,6 +49,7 @@ type GitDaemon struct {
 	addr     string
 	finished chan struct{}
 	conns    connections
+	limiter  *ratelimit.Limiter
 	cfg      *config.Config
 	be       *backend.Backend
 	wg       sync.WaitGroup
@@ -65,6 +68,7 @@ func NewGitDaemon(ctx context.Context) (*GitDaemon, error) {
 		cfg:      cfg,
 		be:       backend.FromContext(ctx),
 		conns:    connections{m: make(map[net.Conn]struct{})},
+		limiter:  ratelimit.New(cfg.Git.RateLimit),
 		logger:   log.FromContext(ctx).WithPrefix("gitdaemon"),
 	}
 	listener, err := net.Listen("tcp", d.addr)
@@ -114,6 +118,13 @@ func (d *GitDaemon) Start() error {
 			continue
 		}
 
+		// Close connection if the client is making too many connections.
+		if host, _, err := net.SplitHostPort(conn.RemoteAddr().String()); err == nil && !d.limiter.Allow(host) {
+			d.logger.Debugf("git: rate limit reached, closing %s", conn.RemoteAddr())
+			d.fatal(conn, git.ErrRateLimited)
+			continue
+		}
+
 		d.wg.Add(1)
 		go func() {
 			d.handleClient(conn)
@@ -132,6 +143,8 @@ func (d *GitDaemon) fatal(c net.Conn, err error) {
 // handleClient handles a git protocol client.
 func (d *GitDaemon) handleClient(conn net.Conn) {
 	ctx, cancel := context.WithCancel(context.Background())
+	logger := d.logger.With("conn", logr.NewID())
+	ctx = log.WithContext(ctx, logger)
 	idleTimeout := time.Duration(d.cfg.Git.IdleTimeout) * time.Second
 	c := &serverConn{
 		Conn:          conn,
@@ -162,7 +175,7 @@ func (d *GitDaemon) handleClient(conn net.Conn) {
 	select {
 	case <-ctx.Done():
 		if err := ctx.Err(); err != nil {
-			d.logger.Debugf("git: connection context error: %v", err)
+			logger.Debugf("git: connection context error: %v", err)
 			d.fatal(c, git.ErrTimeout)
 		}
 		return
@@ -171,7 +184,7 @@ func (d *GitDaemon) handleClient(conn net.Conn) {
 			d.fatal(c, git.ErrTimeout)
 			return
 		} else if err != nil {
-			d.logger.Debugf("git: error scanning pktline: %v", err)
+			logger.Debugf("git: error scanning pktline: %v", err)
 			d.fatal(c, git.ErrSystemMalfunction)
 			return
 		}
@@ -214,7 +227,7 @@ func (d *GitDaemon) handleClient(conn net.Conn) {
 
 				kv := strings.SplitN(opt, "=", 2)
 				if len(kv) != 2 {
-					d.logger.Errorf("git: invalid option %q", opt)
+					logger.Errorf("git: invalid option %q", opt)
 					continue
 				}
 
@@ -223,7 +236,7 @@ func (d *GitDaemon) handleClient(conn net.Conn) {
 
 			version := extraParams["version"]
 			if version != "" {
-				d.logger.Debugf("git: protocol version %s", version)
+				logger.Debugf("git: protocol version %s", version)
 			}
 		}
 
@@ -234,15 +247,15 @@ func (d *GitDaemon) handleClient(conn net.Conn) {
 		}
 
 		name := utils.SanitizeRepo(string(opts[0]))
-		d.logger.Debugf("git: connect %s %s %s", c.RemoteAddr(), service, name)
-		defer d.logger.Debugf("git: disconnect %s %s %s", c.RemoteAddr(), service, name)
+		logger.Debugf("git: connect %s %s %s", c.RemoteAddr(), service, name)
+		defer logger.Debugf("git: disconnect %s %s %s", c.RemoteAddr(), service, name)
 
 		// git bare repositories should end in ".git"
 		// https://git-scm.com/docs/gitrepository-layout
 		repo := name + ".git"
 		reposDir := filepath.Join(d.cfg.DataPath, "repos")
 		if err := git.EnsureWithin(reposDir, repo); err != nil {
-			d.logger.Debugf("git: error ensuring repo path: %v", err)
+			logger.Debugf("git: error ensuring repo path: %v", err)
 			d.fatal(c, git.ErrInvalidRepo)
 			return
 		}
@@ -286,10 +299,29 @@ func (d *GitDaemon) handleClient(conn net.Conn) {
 			Stderr: c,
 			Env:    envs,
 			Dir:    filepath.Join(reposDir, repo),
+			Config: d.cfg.Repo.GitConfig(),
+		}
+
+		done, err := be.StartOperation(ctx, name, nil, nil)
+		if err != nil {
+			d.fatal(c, err)
+			return
 		}
+		defer done()
+
+		release, err := be.AcquireRepository(ctx, name, false)
+		if err != nil {
+			d.fatal(c, git.ErrSystemMalfunction)
+			return
+		}
+		defer func() {
+			if err := release(); err != nil {
+				logger.Error("git: failed to release repository", "repo", name, "err", err)
+			}
+		}()
 
 		if err := service.Handler(ctx, cmd); err != nil {
-			d.logger.Debugf("git: error handling request: %v", err)
+			logger.Debugf("git: error handling request: %v", err)
 			d.fatal(c, err)
 			return
 		}
@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	userNamesName    = "user_names"
+	userNamesVersion = 4
+)
+
+var userNames = Migration{
+	Name:    userNamesName,
+	Version: userNamesVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, userNamesVersion, userNamesName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, userNamesVersion, userNamesName)
+	},
+}
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS users_name_unique;
+
+ALTER TABLE users DROP COLUMN name;
@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN name TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_name_unique ON users (name);
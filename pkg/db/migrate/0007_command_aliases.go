@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	commandAliasesName    = "command_aliases"
+	commandAliasesVersion = 7
+)
+
+var commandAliases = Migration{
+	Name:    commandAliasesName,
+	Version: commandAliasesVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, commandAliasesVersion, commandAliasesName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, commandAliasesVersion, commandAliasesName)
+	},
+}
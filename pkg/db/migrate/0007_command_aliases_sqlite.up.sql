@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS command_aliases (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  public_key TEXT NOT NULL,
+  name TEXT NOT NULL,
+  command TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME NOT NULL,
+  UNIQUE (public_key, name)
+);
@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	maintenanceModeName    = "maintenance_mode"
+	maintenanceModeVersion = 8
+)
+
+var maintenanceMode = Migration{
+	Name:    maintenanceModeName,
+	Version: maintenanceModeVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, maintenanceModeVersion, maintenanceModeName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, maintenanceModeVersion, maintenanceModeName)
+	},
+}
@@ -0,0 +1,5 @@
+INSERT INTO settings (key, value, updated_at) VALUES
+  ('maintenance', 'false', CURRENT_TIMESTAMP),
+  ('maintenance_message', '', CURRENT_TIMESTAMP),
+  ('maintenance_admins_exempt', 'true', CURRENT_TIMESTAMP)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1 @@
+DELETE FROM settings WHERE key IN ('maintenance', 'maintenance_message', 'maintenance_admins_exempt');
@@ -0,0 +1,4 @@
+INSERT OR IGNORE INTO settings (key, value, updated_at) VALUES
+  ('maintenance', 'false', CURRENT_TIMESTAMP),
+  ('maintenance_message', '', CURRENT_TIMESTAMP),
+  ('maintenance_admins_exempt', 'true', CURRENT_TIMESTAMP);
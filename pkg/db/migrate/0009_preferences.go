@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	preferencesName    = "preferences"
+	preferencesVersion = 9
+)
+
+var preferences = Migration{
+	Name:    preferencesName,
+	Version: preferencesVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, preferencesVersion, preferencesName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, preferencesVersion, preferencesName)
+	},
+}
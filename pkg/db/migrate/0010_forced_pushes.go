@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	forcedPushesName    = "forced_pushes"
+	forcedPushesVersion = 10
+)
+
+var forcedPushes = Migration{
+	Name:    forcedPushesName,
+	Version: forcedPushesVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, forcedPushesVersion, forcedPushesName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, forcedPushesVersion, forcedPushesName)
+	},
+}
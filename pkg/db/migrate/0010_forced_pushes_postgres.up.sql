@@ -0,0 +1 @@
+ALTER TABLE push_events ADD COLUMN forced BOOLEAN NOT NULL DEFAULT false;
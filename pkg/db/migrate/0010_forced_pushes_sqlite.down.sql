@@ -0,0 +1 @@
+ALTER TABLE push_events DROP COLUMN forced;
@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	landingTabsName    = "landing_tabs"
+	landingTabsVersion = 11
+)
+
+var landingTabs = Migration{
+	Name:    landingTabsName,
+	Version: landingTabsVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, landingTabsVersion, landingTabsName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, landingTabsVersion, landingTabsName)
+	},
+}
@@ -0,0 +1 @@
+ALTER TABLE repos ADD COLUMN landing_tab TEXT NOT NULL DEFAULT '';
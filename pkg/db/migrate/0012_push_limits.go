@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	pushLimitsName    = "push_limits"
+	pushLimitsVersion = 12
+)
+
+var pushLimits = Migration{
+	Name:    pushLimitsName,
+	Version: pushLimitsVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, pushLimitsVersion, pushLimitsName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, pushLimitsVersion, pushLimitsName)
+	},
+}
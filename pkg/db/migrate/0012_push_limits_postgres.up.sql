@@ -0,0 +1,2 @@
+ALTER TABLE repos ADD COLUMN max_blob_size BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE repos ADD COLUMN max_tree_depth INTEGER NOT NULL DEFAULT 0;
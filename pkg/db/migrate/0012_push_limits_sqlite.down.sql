@@ -0,0 +1,2 @@
+ALTER TABLE repos DROP COLUMN max_tree_depth;
+ALTER TABLE repos DROP COLUMN max_blob_size;
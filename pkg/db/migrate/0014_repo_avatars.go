@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	repoAvatarsName    = "repo_avatars"
+	repoAvatarsVersion = 14
+)
+
+var repoAvatars = Migration{
+	Name:    repoAvatarsName,
+	Version: repoAvatarsVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, repoAvatarsVersion, repoAvatarsName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, repoAvatarsVersion, repoAvatarsName)
+	},
+}
@@ -0,0 +1 @@
+ALTER TABLE repos ADD COLUMN avatar TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	deploysName    = "deploys"
+	deploysVersion = 15
+)
+
+var deploys = Migration{
+	Name:    deploysName,
+	Version: deploysVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, deploysVersion, deploysName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, deploysVersion, deploysName)
+	},
+}
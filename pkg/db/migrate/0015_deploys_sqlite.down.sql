@@ -0,0 +1,4 @@
+ALTER TABLE push_events DROP COLUMN deploy_output;
+ALTER TABLE push_events DROP COLUMN deploy_status;
+ALTER TABLE push_events DROP COLUMN deploy_script;
+DROP TABLE IF EXISTS deploys;
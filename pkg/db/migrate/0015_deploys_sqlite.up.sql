@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS deploys (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  repo_id INTEGER NOT NULL,
+  branch TEXT NOT NULL,
+  script TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME NOT NULL,
+  UNIQUE (repo_id, branch),
+  CONSTRAINT repo_id_fk
+  FOREIGN KEY(repo_id) REFERENCES repos(id)
+  ON DELETE CASCADE
+  ON UPDATE CASCADE
+);
+ALTER TABLE push_events ADD COLUMN deploy_script TEXT NOT NULL DEFAULT '';
+ALTER TABLE push_events ADD COLUMN deploy_status TEXT NOT NULL DEFAULT '';
+ALTER TABLE push_events ADD COLUMN deploy_output TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE repos DROP COLUMN mirror_error;
+ALTER TABLE repos DROP COLUMN mirror_synced_at;
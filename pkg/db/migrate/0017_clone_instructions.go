@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	cloneInstructionsName    = "clone_instructions"
+	cloneInstructionsVersion = 17
+)
+
+var cloneInstructions = Migration{
+	Name:    cloneInstructionsName,
+	Version: cloneInstructionsVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, cloneInstructionsVersion, cloneInstructionsName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, cloneInstructionsVersion, cloneInstructionsName)
+	},
+}
@@ -0,0 +1 @@
+ALTER TABLE repos ADD COLUMN clone_instructions TEXT NOT NULL DEFAULT '';
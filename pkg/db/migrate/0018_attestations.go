@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	attestationsName    = "attestations"
+	attestationsVersion = 18
+)
+
+var attestations = Migration{
+	Name:    attestationsName,
+	Version: attestationsVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, attestationsVersion, attestationsName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, attestationsVersion, attestationsName)
+	},
+}
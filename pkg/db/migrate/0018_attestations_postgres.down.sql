@@ -0,0 +1,2 @@
+ALTER TABLE repos DROP COLUMN attestation_signature;
+ALTER TABLE repos DROP COLUMN attestation;
@@ -0,0 +1,2 @@
+ALTER TABLE repos ADD COLUMN attestation TEXT NOT NULL DEFAULT '';
+ALTER TABLE repos ADD COLUMN attestation_signature TEXT NOT NULL DEFAULT '';
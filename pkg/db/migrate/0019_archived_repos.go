@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	archivedReposName    = "archived_repos"
+	archivedReposVersion = 19
+)
+
+var archivedRepos = Migration{
+	Name:    archivedReposName,
+	Version: archivedReposVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, archivedReposVersion, archivedReposName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, archivedReposVersion, archivedReposName)
+	},
+}
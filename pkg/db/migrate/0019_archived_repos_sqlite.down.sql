@@ -0,0 +1,2 @@
+ALTER TABLE repos DROP COLUMN archive_changed_at;
+ALTER TABLE repos DROP COLUMN archived;
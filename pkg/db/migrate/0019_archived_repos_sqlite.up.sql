@@ -0,0 +1,2 @@
+ALTER TABLE repos ADD COLUMN archived BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE repos ADD COLUMN archive_changed_at DATETIME;
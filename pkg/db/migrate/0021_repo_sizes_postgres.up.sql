@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS repo_sizes (
+  id SERIAL PRIMARY KEY,
+  repo_id INTEGER NOT NULL,
+  size BIGINT NOT NULL,
+  objects BIGINT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT repo_id_fk
+  FOREIGN KEY(repo_id) REFERENCES repos(id)
+  ON DELETE CASCADE
+  ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS repo_sizes_repo_id_idx ON repo_sizes (repo_id);
@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	readmePathsName    = "readme_paths"
+	readmePathsVersion = 22
+)
+
+var readmePaths = Migration{
+	Name:    readmePathsName,
+	Version: readmePathsVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, readmePathsVersion, readmePathsName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, readmePathsVersion, readmePathsName)
+	},
+}
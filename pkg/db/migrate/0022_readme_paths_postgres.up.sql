@@ -0,0 +1 @@
+ALTER TABLE repos ADD COLUMN readme_path TEXT NOT NULL DEFAULT '';
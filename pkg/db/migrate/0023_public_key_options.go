@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	publicKeyOptionsName    = "public_key_options"
+	publicKeyOptionsVersion = 23
+)
+
+var publicKeyOptions = Migration{
+	Name:    publicKeyOptionsName,
+	Version: publicKeyOptionsVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, publicKeyOptionsVersion, publicKeyOptionsName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, publicKeyOptionsVersion, publicKeyOptionsName)
+	},
+}
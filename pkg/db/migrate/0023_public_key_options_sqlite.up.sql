@@ -0,0 +1 @@
+ALTER TABLE public_keys ADD COLUMN options TEXT NOT NULL DEFAULT '';
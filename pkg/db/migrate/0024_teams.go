@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	teamsName    = "teams"
+	teamsVersion = 24
+)
+
+var teams = Migration{
+	Name:    teamsName,
+	Version: teamsVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, teamsVersion, teamsName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, teamsVersion, teamsName)
+	},
+}
@@ -0,0 +1,23 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+const (
+	identitySyncName    = "identity_sync"
+	identitySyncVersion = 25
+)
+
+var identitySync = Migration{
+	Name:    identitySyncName,
+	Version: identitySyncVersion,
+	Migrate: func(ctx context.Context, tx *db.Tx) error {
+		return migrateUp(ctx, tx, identitySyncVersion, identitySyncName)
+	},
+	Rollback: func(ctx context.Context, tx *db.Tx) error {
+		return migrateDown(ctx, tx, identitySyncVersion, identitySyncName)
+	},
+}
@@ -0,0 +1,2 @@
+ALTER TABLE team_members DROP COLUMN synced;
+ALTER TABLE public_keys DROP COLUMN synced;
@@ -0,0 +1,2 @@
+ALTER TABLE public_keys ADD COLUMN synced BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE team_members ADD COLUMN synced BOOLEAN NOT NULL DEFAULT false;
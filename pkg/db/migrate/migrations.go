@@ -18,6 +18,28 @@ var migrations = []Migration{
 	createTables,
 	webhooks,
 	migrateLfsObjects,
+	userNames,
+	commitStatuses,
+	pushEvents,
+	commandAliases,
+	maintenanceMode,
+	preferences,
+	forcedPushes,
+	landingTabs,
+	pushLimits,
+	branchProtections,
+	repoAvatars,
+	deploys,
+	mirrorSyncs,
+	cloneInstructions,
+	attestations,
+	archivedRepos,
+	repoForks,
+	repoSizes,
+	readmePaths,
+	publicKeyOptions,
+	teams,
+	identitySync,
 }
 
 func execMigration(ctx context.Context, tx *db.Tx, version int, name string, down bool) error {
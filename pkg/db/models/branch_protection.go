@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// BranchProtection is a protection rule of the branches of a repository.
+type BranchProtection struct {
+	ID               int64     `db:"id"`
+	RepoID           int64     `db:"repo_id"`
+	Pattern          string    `db:"pattern"`
+	RequiredStatuses string    `db:"required_statuses"`
+	FastForward      bool      `db:"fast_forward"`
+	CreatedAt        time.Time `db:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at"`
+}
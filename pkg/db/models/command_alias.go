@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// CommandAlias is a command alias defined by a public key.
+type CommandAlias struct {
+	ID        int64     `db:"id"`
+	PublicKey string    `db:"public_key"`
+	Name      string    `db:"name"`
+	Command   string    `db:"command"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
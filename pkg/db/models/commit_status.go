@@ -0,0 +1,19 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// CommitStatus is a status reported for a repository commit.
+type CommitStatus struct {
+	ID          int64          `db:"id"`
+	RepoID      int64          `db:"repo_id"`
+	SHA         string         `db:"sha"`
+	Context     string         `db:"context"`
+	State       string         `db:"state"`
+	Description sql.NullString `db:"description"`
+	TargetURL   sql.NullString `db:"target_url"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+}
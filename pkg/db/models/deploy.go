@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Deploy binds a deploy script to a branch of a repository.
+type Deploy struct {
+	ID        int64     `db:"id"`
+	RepoID    int64     `db:"repo_id"`
+	Branch    string    `db:"branch"`
+	Script    string    `db:"script"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
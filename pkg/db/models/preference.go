@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Preference is a preference of a public key, e.g. the columns of the log.
+type Preference struct {
+	ID        int64     `db:"id"`
+	PublicKey string    `db:"public_key"`
+	Name      string    `db:"name"`
+	Value     string    `db:"value"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
@@ -5,6 +5,8 @@ type PublicKey struct {
 	ID        int64  `db:"id"`
 	UserID    int64  `db:"user_id"`
 	PublicKey string `db:"public_key"`
+	Options   string `db:"options"`
+	Synced    bool   `db:"synced"`
 	CreatedAt string `db:"created_at"`
 	UpdatedAt string `db:"updated_at"`
 }
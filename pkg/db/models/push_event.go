@@ -0,0 +1,29 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PushEvent is a reference update pushed to a repository.
+type PushEvent struct {
+	ID        int64         `db:"id"`
+	RepoID    int64         `db:"repo_id"`
+	UserID    sql.NullInt64 `db:"user_id"`
+	RefName   string        `db:"ref_name"`
+	OldSHA    string        `db:"old_sha"`
+	NewSHA    string        `db:"new_sha"`
+	Commits   int64         `db:"commits"`
+	Forced    bool          `db:"forced"`
+	CreatedAt time.Time     `db:"created_at"`
+
+	// DeployScript, DeployStatus, and DeployOutput describe the deploy the
+	// push ran, if any.
+	DeployScript string `db:"deploy_script"`
+	DeployStatus string `db:"deploy_status"`
+	DeployOutput string `db:"deploy_output"`
+
+	// RepoName and Username are joined from the repos and users tables.
+	RepoName string         `db:"repo_name"`
+	Username sql.NullString `db:"username"`
+}
@@ -7,14 +7,55 @@ import (
 
 // Repo is a database model for a repository.
 type Repo struct {
-	ID          int64         `db:"id"`
-	Name        string        `db:"name"`
-	ProjectName string        `db:"project_name"`
-	Description string        `db:"description"`
-	Private     bool          `db:"private"`
-	Mirror      bool          `db:"mirror"`
-	Hidden      bool          `db:"hidden"`
-	UserID      sql.NullInt64 `db:"user_id"`
-	CreatedAt   time.Time     `db:"created_at"`
-	UpdatedAt   time.Time     `db:"updated_at"`
+	ID                int64  `db:"id"`
+	Name              string `db:"name"`
+	ProjectName       string `db:"project_name"`
+	Description       string `db:"description"`
+	Private           bool   `db:"private"`
+	Mirror            bool   `db:"mirror"`
+	Hidden            bool   `db:"hidden"`
+	Archived          bool   `db:"archived"`
+	LandingTab        string `db:"landing_tab"`
+	ReadmePath        string `db:"readme_path"`
+	Avatar            string `db:"avatar"`
+	CloneInstructions string `db:"clone_instructions"`
+	PushLimits
+	MirrorSync
+	Attestation
+	// ArchiveChangedAt is the time the repository was last archived or
+	// unarchived.
+	ArchiveChangedAt sql.NullTime `db:"archive_changed_at"`
+	// ParentID is the repository a fork was forked from, and shares the
+	// objects of.
+	ParentID sql.NullInt64 `db:"parent_id"`
+
+	UserID    sql.NullInt64 `db:"user_id"`
+	CreatedAt time.Time     `db:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at"`
+}
+
+// PushLimits are the limits of the objects pushed to a repository. Zero means
+// no limit.
+type PushLimits struct {
+	// MaxBlobSize is the maximum size of a file in bytes.
+	MaxBlobSize int64 `db:"max_blob_size"`
+	// MaxTreeDepth is the maximum number of nested directories.
+	MaxTreeDepth int `db:"max_tree_depth"`
+}
+
+// MirrorSync is the state of the syncs of a mirror with its remotes.
+type MirrorSync struct {
+	// SyncedAt is the time of the last successful sync.
+	SyncedAt sql.NullTime `db:"mirror_synced_at"`
+	// Error is the error of the last sync, empty if it succeeded.
+	Error string `db:"mirror_error"`
+}
+
+// Attestation is the last attestation the server signed of the references
+// of a repository.
+type Attestation struct {
+	// Statement is the signed statement, the references and their targets.
+	Statement string `db:"attestation"`
+	// Signature is the armored SSH signature of the statement.
+	Signature string `db:"attestation_signature"`
 }
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// RepoSize is a snapshot of the size and the number of objects of a
+// repository.
+type RepoSize struct {
+	ID        int64     `db:"id"`
+	RepoID    int64     `db:"repo_id"`
+	Size      int64     `db:"size"`
+	Objects   int64     `db:"objects"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+)
+
+// Team represents a team of users.
+type Team struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// TeamRepo represents the access of a team to a repository. TeamName and
+// RepoName are the names of the team and the repository.
+type TeamRepo struct {
+	ID          int64              `db:"id"`
+	TeamID      int64              `db:"team_id"`
+	RepoID      int64              `db:"repo_id"`
+	AccessLevel access.AccessLevel `db:"access_level"`
+	CreatedAt   time.Time          `db:"created_at"`
+	UpdatedAt   time.Time          `db:"updated_at"`
+
+	TeamName string `db:"team_name"`
+	RepoName string `db:"repo_name"`
+}
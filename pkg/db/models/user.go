@@ -9,6 +9,7 @@ import (
 type User struct {
 	ID        int64          `db:"id"`
 	Username  string         `db:"username"`
+	Name      sql.NullString `db:"name"`
 	Admin     bool           `db:"admin"`
 	Password  sql.NullString `db:"password"`
 	CreatedAt time.Time      `db:"created_at"`
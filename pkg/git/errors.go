@@ -18,6 +18,13 @@ var (
 	// ErrMaxConnections represents a maximum connection limit being reached.
 	ErrMaxConnections = errors.New("too many connections, try again later")
 
+	// ErrRateLimited represents a client making too many connections.
+	ErrRateLimited = errors.New("rate limit exceeded, try again later")
+
 	// ErrTimeout is returned when the maximum read timeout is exceeded.
 	ErrTimeout = errors.New("I/O timeout reached")
+
+	// ErrOperationTimeout is returned when an operation takes longer than the
+	// configured operation timeout.
+	ErrOperationTimeout = errors.New("operation took too long and was canceled")
 )
@@ -0,0 +1,181 @@
+package git
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+)
+
+// maxReportMessages is the number of messages a push report keeps, the
+// others are dropped.
+const maxReportMessages = 100
+
+// PushReporter collects the report of a push from the output of
+// git-receive-pack as it's sent to the client. It understands the
+// report-status of the protocol, with or without side-band, and ignores the
+// rest, like the reference advertisement. It must be written to in order.
+type PushReporter struct {
+	mu     sync.Mutex
+	repo   string
+	buf    []byte
+	band   []byte
+	msg    []byte
+	status bool
+	report proto.PushReport
+}
+
+// NewPushReporter returns a PushReporter for a push to the given repository.
+func NewPushReporter(repo string) *PushReporter {
+	return &PushReporter{repo: repo}
+}
+
+// Write implements io.Writer. It never fails.
+func (p *PushReporter) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.buf = append(p.buf, b...)
+	var pkt []byte
+	var ok bool
+	for {
+		if pkt, p.buf, ok = nextPktLine(p.buf); !ok {
+			break
+		}
+		p.packet(pkt)
+	}
+	return len(b), nil
+}
+
+// Stderr returns a writer for the standard error of git-receive-pack. The
+// hooks write their output there when the client doesn't use side-band.
+func (p *PushReporter) Stderr() io.Writer {
+	return reportMessages{p}
+}
+
+// SetError records the error the push failed with.
+func (p *PushReporter) SetError(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err != nil {
+		p.report.Err = err.Error()
+	}
+}
+
+// Report returns the report of the push so far.
+func (p *PushReporter) Report() proto.PushReport {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	r := p.report
+	r.Repo = p.repo
+	r.Refs = append([]proto.RefStatus(nil), r.Refs...)
+	r.Messages = append([]string(nil), r.Messages...)
+	if line := strings.TrimSpace(string(p.msg)); line != "" && len(r.Messages) < maxReportMessages {
+		r.Messages = append(r.Messages, line)
+	}
+	r.Time = time.Now()
+	return r
+}
+
+// packet handles a pkt-line, nil for special packets like flush. The lock
+// must be held.
+func (p *PushReporter) packet(pkt []byte) {
+	if len(pkt) == 0 {
+		return
+	}
+	switch pkt[0] {
+	case 1:
+		// The report-status is made of pkt-lines carried by the packets of
+		// the first band.
+		p.band = append(p.band, pkt[1:]...)
+		var line []byte
+		var ok bool
+		for {
+			if line, p.band, ok = nextPktLine(p.band); !ok {
+				break
+			}
+			p.statusLine(line)
+		}
+	case 2:
+		p.message(pkt[1:])
+	case 3:
+		p.report.Err = strings.TrimSpace(string(pkt[1:]))
+	default:
+		p.statusLine(pkt)
+	}
+}
+
+// statusLine handles a line of the report-status. The lines before it, like
+// the reference advertisement, are ignored. The lock must be held.
+func (p *PushReporter) statusLine(line []byte) {
+	s := strings.TrimSuffix(string(line), "\n")
+	if unpack, ok := strings.CutPrefix(s, "unpack "); ok {
+		p.status = true
+		p.report.Unpack = unpack
+		return
+	}
+	if !p.status {
+		return
+	}
+	if ref, ok := strings.CutPrefix(s, "ok "); ok {
+		p.report.Refs = append(p.report.Refs, proto.RefStatus{Ref: ref, OK: true})
+	} else if rest, ok := strings.CutPrefix(s, "ng "); ok {
+		ref, reason, _ := strings.Cut(rest, " ")
+		p.report.Refs = append(p.report.Refs, proto.RefStatus{Ref: ref, Reason: reason})
+	}
+}
+
+// message handles the text of a message. Progress lines that are redrawn
+// with a carriage return only keep their last state. The lock must be held.
+func (p *PushReporter) message(b []byte) {
+	for _, c := range b {
+		switch c {
+		case '\r':
+			p.msg = p.msg[:0]
+		case '\n':
+			line := strings.TrimSpace(string(p.msg))
+			p.msg = p.msg[:0]
+			if line != "" && len(p.report.Messages) < maxReportMessages {
+				p.report.Messages = append(p.report.Messages, line)
+			}
+		default:
+			p.msg = append(p.msg, c)
+		}
+	}
+}
+
+// reportMessages writes messages to a PushReporter.
+type reportMessages struct {
+	p *PushReporter
+}
+
+// Write implements io.Writer.
+func (w reportMessages) Write(b []byte) (int, error) {
+	w.p.mu.Lock()
+	defer w.p.mu.Unlock()
+	w.p.message(b)
+	return len(b), nil
+}
+
+// nextPktLine returns the payload of the first pkt-line of b, nil for special
+// packets, and what follows it. It returns false if b doesn't hold a whole
+// pkt-line yet. Invalid data is dropped.
+func nextPktLine(b []byte) ([]byte, []byte, bool) {
+	if len(b) < 4 {
+		return nil, b, false
+	}
+	n, err := strconv.ParseUint(string(b[:4]), 16, 16)
+	switch {
+	case err != nil:
+		return nil, nil, false
+	case n < 4:
+		// flush-pkt, delim-pkt, or response-end-pkt.
+		return nil, b[4:], true
+	case len(b) < int(n):
+		return nil, b, false
+	}
+	return bytes.Clone(b[4:n]), b[n:], true
+}
@@ -0,0 +1,60 @@
+package git
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/go-git/go-git/v5/plumbing/format/pktline"
+	"github.com/matryer/is"
+)
+
+func TestPushReporter(t *testing.T) {
+	is := is.New(t)
+
+	var status bytes.Buffer
+	writePkt(&status, "unpack ok\n")
+	writePkt(&status, "ok refs/heads/main\n")
+	writePkt(&status, "ng refs/heads/locked pre-receive hook declined\n")
+	status.WriteString("0000")
+
+	var out bytes.Buffer
+	// The reference advertisement is ignored.
+	writePkt(&out, "0000000000000000000000000000000000000000 capabilities^{}\x00report-status side-band-64k")
+	out.WriteString("0000")
+	writePkt(&out, "\x02Counting: 50%\rCounting: 100%, done.\nbranch is")
+	writePkt(&out, "\x02 protected\n")
+	// Split the report-status across packets of the first band.
+	sb := status.Bytes()
+	writePkt(&out, "\x01"+string(sb[:10]))
+	writePkt(&out, "\x01"+string(sb[10:]))
+	out.WriteString("0000")
+
+	p := NewPushReporter("repo1")
+	// Write it byte by byte, like a slow connection.
+	for _, b := range out.Bytes() {
+		p.Write([]byte{b}) // nolint: errcheck
+	}
+	r := p.Report()
+	is.Equal(r.Repo, "repo1")
+	is.Equal(r.Unpack, "ok")
+	is.Equal(r.Refs, []proto.RefStatus{
+		{Ref: "refs/heads/main", OK: true},
+		{Ref: "refs/heads/locked", Reason: "pre-receive hook declined"},
+	})
+	is.Equal(r.Messages, []string{"Counting: 100%, done.", "branch is protected"})
+	is.Equal(r.Rejected(), 1)
+
+	// Without side-band, the report-status is sent as is and the hooks write
+	// to stderr.
+	p = NewPushReporter("repo1")
+	p.Write(status.Bytes())                    // nolint: errcheck
+	p.Stderr().Write([]byte("hook says hi\n")) // nolint: errcheck
+	r = p.Report()
+	is.Equal(len(r.Refs), 2)
+	is.Equal(r.Messages, []string{"hook says hi"})
+}
+
+func writePkt(w *bytes.Buffer, s string) {
+	pktline.NewEncoder(w).EncodeString(s) // nolint: errcheck
+}
@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/charmbracelet/log"
 )
@@ -39,8 +40,15 @@ func (s Service) Name() string {
 	return strings.TrimPrefix(s.String(), "git-")
 }
 
-// Handler is the service handler.
-func (s Service) Handler(ctx context.Context, cmd ServiceCommand) error {
+// Handler is the service handler. It logs the duration of the service at the
+// debug level.
+func (s Service) Handler(ctx context.Context, cmd ServiceCommand) (err error) {
+	logger := log.FromContext(ctx).WithPrefix("git")
+	start := time.Now()
+	defer func() {
+		logger.Debug("service finished", "service", s, "dir", cmd.Dir, "duration", time.Since(start), "err", err)
+	}()
+
 	switch s {
 	case UploadPackService, UploadArchiveService, ReceivePackService:
 		return gitServiceHandler(ctx, s, cmd)
@@ -63,12 +71,19 @@ func gitServiceHandler(ctx context.Context, svc Service, scmd ServiceCommand) er
 	cmd.Args = append(cmd.Args, []string{
 		// Enable partial clones
 		"-c", "uploadpack.allowFilter=true",
+		// Let clients fetch any commit they can reach, so that a large
+		// repository can be cloned in steps, e.g. shallow then deepened, or
+		// up to a given commit first.
+		"-c", "uploadpack.allowReachableSHA1InWant=true",
 		// Enable push options
 		"-c", "receive.advertisePushOptions=true",
 		// Disable LFS filters
 		"-c", "filter.lfs.required=", "-c", "filter.lfs.smudge=", "-c", "filter.lfs.clean=",
-		svc.Name(),
 	}...)
+	for _, c := range scmd.Config {
+		cmd.Args = append(cmd.Args, "-c", c)
+	}
+	cmd.Args = append(cmd.Args, svc.Name())
 	if len(scmd.Args) > 0 {
 		cmd.Args = append(cmd.Args, scmd.Args...)
 	}
@@ -182,6 +197,10 @@ type ServiceCommand struct {
 	Env    []string
 	Args   []string
 
+	// Config are the extra git configuration pairs, "key=value", the service
+	// runs with.
+	Config []string
+
 	// Modifier functions
 	CmdFunc func(*exec.Cmd)
 }
@@ -0,0 +1,67 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// The environment variables git passes the push options to the pre-receive
+// and post-receive hooks with.
+const (
+	pushOptionCountEnv  = "GIT_PUSH_OPTION_COUNT"
+	pushOptionEnvPrefix = "GIT_PUSH_OPTION_"
+)
+
+// ContextKeyPushOptions is the context key for the push options of a push.
+var ContextKeyPushOptions = &struct{ string }{"push-options"}
+
+// PushOptions are the options of a push, the ones given with `git push -o`,
+// in order. They're passed on as they are, options the server doesn't know
+// are meant for hooks and deploy scripts.
+type PushOptions []string
+
+// PushOptionsFromEnv returns the push options git passes to the pre-receive
+// and post-receive hooks in the environment, looked up with getenv.
+func PushOptionsFromEnv(getenv func(string) string) PushOptions {
+	n, err := strconv.Atoi(getenv(pushOptionCountEnv))
+	if err != nil || n <= 0 {
+		return nil
+	}
+
+	opts := make(PushOptions, n)
+	for i := range opts {
+		opts[i] = getenv(fmt.Sprintf("%s%d", pushOptionEnvPrefix, i))
+	}
+	return opts
+}
+
+// Environ returns the environment variables git passes the push options to
+// hooks with, none when there are no options.
+func (o PushOptions) Environ() []string {
+	if len(o) == 0 {
+		return nil
+	}
+
+	env := make([]string, 0, len(o)+1)
+	env = append(env, fmt.Sprintf("%s=%d", pushOptionCountEnv, len(o)))
+	for i, opt := range o {
+		env = append(env, fmt.Sprintf("%s%d=%s", pushOptionEnvPrefix, i, opt))
+	}
+	return env
+}
+
+// PushOptionsFromContext returns the push options of the push of the hook,
+// nil when there are none.
+func PushOptionsFromContext(ctx context.Context) PushOptions {
+	if o, ok := ctx.Value(ContextKeyPushOptions).(PushOptions); ok {
+		return o
+	}
+	return nil
+}
+
+// WithPushOptions returns a new context with the push options of the push of
+// the hook.
+func WithPushOptions(ctx context.Context, o PushOptions) context.Context {
+	return context.WithValue(ctx, ContextKeyPushOptions, o)
+}
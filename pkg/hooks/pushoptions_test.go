@@ -0,0 +1,47 @@
+package hooks
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPushOptionsFromEnv(t *testing.T) {
+	cases := []struct {
+		name string
+		env  map[string]string
+		want PushOptions
+	}{
+		{
+			name: "none",
+		},
+		{
+			name: "invalid count",
+			env:  map[string]string{"GIT_PUSH_OPTION_COUNT": "x"},
+		},
+		{
+			name: "options",
+			env: map[string]string{
+				"GIT_PUSH_OPTION_COUNT": "3",
+				"GIT_PUSH_OPTION_0":     "ci.skip",
+				"GIT_PUSH_OPTION_1":     "deploy.env=staging",
+				"GIT_PUSH_OPTION_2":     "note=a=b",
+			},
+			want: PushOptions{"ci.skip", "deploy.env=staging", "note=a=b"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := PushOptionsFromEnv(func(k string) string { return c.env[k] })
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("PushOptionsFromEnv() = %q, want %q", got, c.want)
+			}
+			if len(got) > 0 {
+				env := got.Environ()
+				if env[0] != "GIT_PUSH_OPTION_COUNT=3" || env[2] != "GIT_PUSH_OPTION_1=deploy.env=staging" {
+					t.Errorf("Environ() = %q", env)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+)
+
+func init() {
+	Register("archive", archive{})
+}
+
+type archive struct{}
+
+// Spec derives the spec used to archive the idle repositories and implements
+// Runner.
+func (a archive) Spec(ctx context.Context) string {
+	cfg := config.FromContext(ctx)
+	if cfg.Jobs.Archive != "" {
+		return cfg.Jobs.Archive
+	}
+	return "@every 24h"
+}
+
+// Func archives the idle repositories and implements Runner. It does nothing
+// unless the archival of idle repositories is enabled.
+func (a archive) Func(ctx context.Context) func() {
+	cfg := config.FromContext(ctx)
+	logger := log.FromContext(ctx).WithPrefix("jobs.archive")
+	b := backend.FromContext(ctx)
+	return func() {
+		if cfg.Repo.ArchiveAfter <= 0 {
+			return
+		}
+
+		archived, err := b.ArchiveIdleRepositories(ctx)
+		for _, name := range archived {
+			logger.Info("archived idle repository", "repo", name, "days", cfg.Repo.ArchiveAfter)
+		}
+		if err != nil {
+			logger.Error("error archiving idle repositories", "err", err)
+		}
+	}
+}
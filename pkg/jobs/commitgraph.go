@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"context"
+	"runtime"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/sync"
+)
+
+func init() {
+	Register("commit-graph", commitGraph{})
+}
+
+type commitGraph struct{}
+
+// Spec derives the spec used to update commit-graphs and implements Runner.
+func (c commitGraph) Spec(ctx context.Context) string {
+	cfg := config.FromContext(ctx)
+	if cfg.Jobs.CommitGraph != "" {
+		return cfg.Jobs.CommitGraph
+	}
+	return "@every 1h"
+}
+
+// Func runs the commit-graph job task and implements Runner. It does nothing
+// unless commit-graphs are enabled for repositories.
+func (c commitGraph) Func(ctx context.Context) func() {
+	cfg := config.FromContext(ctx)
+	logger := log.FromContext(ctx).WithPrefix("jobs.commit-graph")
+	b := backend.FromContext(ctx)
+	return func() {
+		if !cfg.Repo.CommitGraph {
+			return
+		}
+
+		repos, err := b.Repositories(ctx)
+		if err != nil {
+			logger.Error("error getting repositories", "err", err)
+			return
+		}
+
+		wq := sync.NewWorkPool(ctx, runtime.GOMAXPROCS(0),
+			sync.WithWorkPoolLogger(logger.Errorf),
+		)
+
+		logger.Debug("updating commit-graphs")
+		for _, repo := range repos {
+			name := repo.Name()
+			wq.Add(name, func() {
+				if err := b.WithRepository(ctx, name, true, func() error {
+					r, err := repo.Open()
+					if err != nil {
+						return err
+					}
+
+					// Empty repositories have no commits to write.
+					if _, err := r.HEAD(); err != nil {
+						return nil
+					}
+
+					return git.WriteCommitGraph(ctx, r.Path)
+				}); err != nil {
+					logger.Error("error writing commit-graph", "repo", name, "err", err)
+				}
+			})
+		}
+
+		wq.Run()
+	}
+}
@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+)
+
+func init() {
+	Register("follower-sync", followerSync{})
+}
+
+type followerSync struct{}
+
+// Spec derives the spec used to sync a follower with its primary and
+// implements Runner.
+func (f followerSync) Spec(ctx context.Context) string {
+	cfg := config.FromContext(ctx)
+	if cfg.Jobs.FollowerSync != "" {
+		return cfg.Jobs.FollowerSync
+	}
+	return "@every 5m"
+}
+
+// Func syncs a follower with its primary and implements Runner. It does
+// nothing unless the server is a follower.
+func (f followerSync) Func(ctx context.Context) func() {
+	logger := log.FromContext(ctx).WithPrefix("jobs.follower")
+	b := backend.FromContext(ctx)
+	return func() {
+		if !b.IsFollower() {
+			return
+		}
+
+		logger.Debug("syncing with the primary", "primary", b.Primary())
+		if err := b.SyncFollower(ctx); err != nil {
+			logger.Error("error syncing with the primary", "primary", b.Primary(), "err", err)
+		}
+	}
+}
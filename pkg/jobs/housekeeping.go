@@ -0,0 +1,113 @@
+package jobs
+
+import (
+	"context"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+)
+
+func init() {
+	Register("housekeeping", &housekeeping{})
+}
+
+type housekeeping struct {
+	// running is held while a run is in progress, so that runs don't pile up
+	// when one takes longer than the schedule.
+	running sync.Mutex
+}
+
+// Spec derives the spec used to run the housekeeping tasks and implements
+// Runner.
+func (h *housekeeping) Spec(ctx context.Context) string {
+	cfg := config.FromContext(ctx)
+	if cfg.Jobs.Housekeeping != "" {
+		return cfg.Jobs.Housekeeping
+	}
+	return "@every 24h"
+}
+
+// Func runs the housekeeping tasks of every repository and implements Runner.
+// Each repository starts after a random delay of up to the configured jitter,
+// and a few of them run at once.
+func (h *housekeeping) Func(ctx context.Context) func() {
+	cfg := config.FromContext(ctx)
+	logger := log.FromContext(ctx).WithPrefix("jobs.housekeeping")
+	b := backend.FromContext(ctx)
+	return func() {
+		if !h.running.TryLock() {
+			logger.Warn("skipping housekeeping, the previous run is still in progress")
+			return
+		}
+		defer h.running.Unlock()
+
+		repos, err := b.Repositories(ctx)
+		if err != nil {
+			logger.Error("error getting repositories", "err", err)
+			return
+		}
+
+		type job struct {
+			name  string
+			tasks []string
+			delay time.Duration
+		}
+		var jobs []job
+		for _, repo := range repos {
+			name := repo.Name()
+			tasks := cfg.Housekeeping.TasksFor(name)
+			if len(tasks) == 0 {
+				continue
+			}
+
+			// Empty repositories have nothing to clean up.
+			r, err := repo.Open()
+			if err != nil {
+				logger.Error("error opening repository", "repo", name, "err", err)
+				continue
+			}
+			if _, err := r.HEAD(); err != nil {
+				continue
+			}
+
+			var delay time.Duration
+			if cfg.Housekeeping.Jitter > 0 {
+				delay = time.Duration(rand.Int63n(int64(cfg.Housekeeping.Jitter) * int64(time.Second))) // nolint: gosec
+			}
+			jobs = append(jobs, job{name: name, tasks: tasks, delay: delay})
+		}
+		sort.Slice(jobs, func(i, j int) bool {
+			return jobs[i].delay < jobs[j].delay
+		})
+
+		logger.Debug("running housekeeping", "repos", len(jobs))
+		start := time.Now()
+		sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+		var wg sync.WaitGroup
+		for _, j := range jobs {
+			select {
+			case <-ctx.Done():
+				wg.Wait()
+				return
+			case <-time.After(time.Until(start.Add(j.delay))):
+			}
+
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(j job) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				b.Housekeep(ctx, j.name, j.tasks)
+			}(j)
+		}
+		wg.Wait()
+	}
+}
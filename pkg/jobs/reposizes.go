@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+)
+
+func init() {
+	Register("repo-sizes", repoSizes{})
+}
+
+type repoSizes struct{}
+
+// Spec derives the spec used to record the sizes of the repositories and
+// implements Runner.
+func (repoSizes) Spec(ctx context.Context) string {
+	cfg := config.FromContext(ctx)
+	if cfg.Jobs.RepoSizes != "" {
+		return cfg.Jobs.RepoSizes
+	}
+	return "@every 24h"
+}
+
+// Func records a snapshot of the size and the number of objects of every
+// repository and implements Runner. Counting the objects only reads the
+// object database, it's cheap.
+func (repoSizes) Func(ctx context.Context) func() {
+	logger := log.FromContext(ctx).WithPrefix("jobs.repo-sizes")
+	b := backend.FromContext(ctx)
+	return func() {
+		repos, err := b.Repositories(ctx)
+		if err != nil {
+			logger.Error("error getting repositories", "err", err)
+			return
+		}
+
+		logger.Debug("recording repository sizes", "repos", len(repos))
+		for _, repo := range repos {
+			if ctx.Err() != nil {
+				return
+			}
+			if err := b.RecordRepoSize(ctx, repo.Name()); err != nil {
+				logger.Error("error recording repository size", "repo", repo.Name(), "err", err)
+			}
+		}
+	}
+}
@@ -15,6 +15,10 @@ import (
 const (
 	blobSizeCutoff = 1024
 
+	// MaxPointerSize is the size of the largest LFS pointer file, larger
+	// files are never pointers.
+	MaxPointerSize = blobSizeCutoff
+
 	// HashAlgorithmSHA256 is the hash algorithm used for Git LFS.
 	HashAlgorithmSHA256 = "sha256"
 
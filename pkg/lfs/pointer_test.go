@@ -4,6 +4,7 @@ import (
 	"errors"
 	"path"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -93,3 +94,20 @@ size abc
 		})
 	}
 }
+
+func TestParsePointer(t *testing.T) {
+	valid := Pointer{
+		Oid:  "1234567890123456789012345678901234567890123456789012345678901234",
+		Size: 12345678,
+	}
+	if p, ok := ParsePointer([]byte(valid.String())); !ok || p != valid {
+		t.Errorf("ParsePointer() = %v, %v, want %v, true", p, ok, valid)
+	}
+	if _, ok := ParsePointer([]byte("package main\n")); ok {
+		t.Errorf("Expected a file that isn't a pointer")
+	}
+	tooLarge := valid.String() + strings.Repeat("#", MaxPointerSize)
+	if _, ok := ParsePointer([]byte(tooLarge)); ok {
+		t.Errorf("Expected files larger than %d bytes to not be pointers", MaxPointerSize)
+	}
+}
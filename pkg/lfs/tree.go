@@ -0,0 +1,45 @@
+package lfs
+
+import (
+	"github.com/charmbracelet/soft-serve/git"
+)
+
+// ParsePointer returns the pointer the content of a file is, if it's one.
+func ParsePointer(content []byte) (Pointer, bool) {
+	if len(content) > MaxPointerSize {
+		return Pointer{}, false
+	}
+	p, err := ReadPointerFromBuffer(content)
+	if err != nil || !p.IsValid() {
+		return Pointer{}, false
+	}
+	return p, true
+}
+
+// TreePointers returns the given blobs of a tree that are pointers keyed by
+// path. Only the blobs small enough to be pointers are read, in one pass.
+func TreePointers(r *git.Repository, blobs []git.TreeBlob) (map[string]Pointer, error) {
+	contents, err := r.SmallBlobs(blobs, MaxPointerSize)
+	if err != nil {
+		return nil, err
+	}
+	pointers := make(map[string]Pointer)
+	for path, c := range contents {
+		if p, ok := ParsePointer(c); ok {
+			pointers[path] = p
+		}
+	}
+	return pointers, nil
+}
+
+// FilePointer returns the pointer the file is, if it's one.
+func FilePointer(f *git.File) (Pointer, bool) {
+	if f.Size() > MaxPointerSize {
+		return Pointer{}, false
+	}
+	c, err := f.Bytes()
+	if err != nil {
+		return Pointer{}, false
+	}
+	return ParsePointer(c)
+}
@@ -1,6 +1,8 @@
 package log
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"os"
 	"strings"
 	"time"
@@ -19,6 +21,10 @@ func NewLogger(cfg *config.Config) (*log.Logger, *os.File, error) {
 		TimeFormat:      time.DateOnly,
 	})
 
+	if lvl, err := log.ParseLevel(cfg.Log.Level); err == nil {
+		logger.SetLevel(lvl)
+	}
+
 	switch {
 	case config.IsVerbose():
 		logger.SetReportCaller(true)
@@ -39,7 +45,11 @@ func NewLogger(cfg *config.Config) (*log.Logger, *os.File, error) {
 	}
 
 	var f *os.File
-	if cfg.Log.Path != "" {
+	switch cfg.Log.Path {
+	case "", "stderr":
+	case "stdout":
+		logger.SetOutput(os.Stdout)
+	default:
 		var err error
 		f, err = os.OpenFile(cfg.Log.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
 		if err != nil {
@@ -50,3 +60,13 @@ func NewLogger(cfg *config.Config) (*log.Logger, *os.File, error) {
 
 	return logger, f, nil
 }
+
+// NewID returns a random id that ties together the logs of a session or a
+// request.
+func NewID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
@@ -4,6 +4,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/charmbracelet/log"
 	"github.com/charmbracelet/soft-serve/pkg/config"
 )
 
@@ -12,6 +13,7 @@ func TestGoodNewLogger(t *testing.T) {
 		config.DefaultConfig(),
 		{},
 		{Log: config.LogConfig{Path: filepath.Join(t.TempDir(), "logfile.txt")}},
+		{Log: config.LogConfig{Path: "stdout", Level: config.LogLevelWarn}},
 	} {
 		_, f, err := NewLogger(c)
 		if err != nil {
@@ -41,3 +43,37 @@ func TestBadNewLogger(t *testing.T) {
 		}
 	}
 }
+
+func TestNewLoggerLevel(t *testing.T) {
+	t.Setenv("SOFT_SERVE_DEBUG", "")
+	for level, want := range map[string]log.Level{
+		"":                   log.InfoLevel,
+		config.LogLevelDebug: log.DebugLevel,
+		config.LogLevelWarn:  log.WarnLevel,
+		config.LogLevelError: log.ErrorLevel,
+	} {
+		logger, _, err := NewLogger(&config.Config{Log: config.LogConfig{Level: level}})
+		if err != nil {
+			t.Fatalf("expected nil got %v", err)
+		}
+		if got := logger.GetLevel(); got != want {
+			t.Errorf("level %q: expected %v got %v", level, want, got)
+		}
+	}
+
+	t.Setenv("SOFT_SERVE_DEBUG", "true")
+	logger, _, err := NewLogger(&config.Config{Log: config.LogConfig{Level: config.LogLevelError}})
+	if err != nil {
+		t.Fatalf("expected nil got %v", err)
+	}
+	if got := logger.GetLevel(); got != log.DebugLevel {
+		t.Errorf("debug mode: expected %v got %v", log.DebugLevel, got)
+	}
+}
+
+func TestNewID(t *testing.T) {
+	a, b := NewID(), NewID()
+	if len(a) != 16 || a == b {
+		t.Errorf("expected two different 16 characters ids got %q and %q", a, b)
+	}
+}
@@ -0,0 +1,16 @@
+package proto
+
+// BranchProtection is a rule that pushes to the matching branches of a
+// repository must follow.
+type BranchProtection struct {
+	// Pattern is a glob of the branch names the rule applies to, like "main"
+	// or "release/*". Patterns that start with "refs/" match full reference
+	// names instead.
+	Pattern string `json:"pattern"`
+	// RequiredStatuses are the status contexts that must have succeeded on
+	// the commit a matching branch is updated to.
+	RequiredStatuses []string `json:"required_statuses,omitempty"`
+	// FastForward is true if matching branches can only be updated with
+	// fast-forward pushes.
+	FastForward bool `json:"fast_forward,omitempty"`
+}
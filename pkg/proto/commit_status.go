@@ -0,0 +1,73 @@
+package proto
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CommitState is the state of a commit status.
+type CommitState string
+
+const (
+	// CommitStatePending is a commit status that is still running.
+	CommitStatePending CommitState = "pending"
+	// CommitStateSuccess is a commit status that succeeded.
+	CommitStateSuccess CommitState = "success"
+	// CommitStateFailure is a commit status that failed.
+	CommitStateFailure CommitState = "failure"
+	// CommitStateError is a commit status that errored.
+	CommitStateError CommitState = "error"
+)
+
+// String returns the string representation of the commit state.
+func (s CommitState) String() string {
+	return string(s)
+}
+
+// ParseCommitState parses a commit state string.
+func ParseCommitState(s string) (CommitState, error) {
+	switch st := CommitState(strings.ToLower(s)); st {
+	case CommitStatePending, CommitStateSuccess, CommitStateFailure, CommitStateError:
+		return st, nil
+	default:
+		return "", fmt.Errorf("invalid commit state: %q", s)
+	}
+}
+
+// CommitStatus is a status reported for a commit, usually by a CI system.
+type CommitStatus struct {
+	// SHA is the commit hash.
+	SHA string `json:"sha"`
+	// Context distinguishes statuses reported by different systems.
+	Context string `json:"context"`
+	// State is the state of the status.
+	State CommitState `json:"state"`
+	// Description is a short description of the status.
+	Description string `json:"description,omitempty"`
+	// TargetURL is a link to the status details.
+	TargetURL string `json:"target_url,omitempty"`
+	// UpdatedAt is the time the status was last updated.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CombinedCommitState returns a single state for a set of statuses. It
+// returns failure if any of the statuses failed or errored, pending if any of
+// them is pending, and success if all of them succeeded. It returns an empty
+// state if there are no statuses.
+func CombinedCommitState(statuses []CommitStatus) CommitState {
+	var state CommitState
+	for _, s := range statuses {
+		switch s.State {
+		case CommitStateFailure, CommitStateError:
+			return CommitStateFailure
+		case CommitStatePending:
+			state = CommitStatePending
+		case CommitStateSuccess:
+			if state == "" {
+				state = CommitStateSuccess
+			}
+		}
+	}
+	return state
+}
@@ -0,0 +1,25 @@
+package proto
+
+// Deploy statuses.
+const (
+	// DeployRunning is the status of a deploy script that is running.
+	DeployRunning = "running"
+	// DeploySucceeded is the status of a deploy script that exited with a
+	// zero status.
+	DeploySucceeded = "succeeded"
+	// DeployFailed is the status of a deploy script that exited with a
+	// non-zero status or couldn't be started.
+	DeployFailed = "failed"
+	// DeployTimedOut is the status of a deploy script that was killed
+	// because it ran for too long.
+	DeployTimedOut = "timed out"
+)
+
+// Deploy is a deploy script bound to a branch of a repository. Pushes to the
+// branch run the script.
+type Deploy struct {
+	// Branch is the name of the branch.
+	Branch string `json:"branch"`
+	// Script is the name of the deploy script.
+	Script string `json:"script"`
+}
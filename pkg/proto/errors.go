@@ -13,8 +13,13 @@ var (
 	ErrRepoNotFound = errors.New("repository not found")
 	// ErrRepoExist is returned when a repository already exists.
 	ErrRepoExist = errors.New("repository already exists")
+	// ErrInvalidRepoName is returned when a repository name breaks one of
+	// the configured naming rules.
+	ErrInvalidRepoName = errors.New("invalid repository name")
 	// ErrUserNotFound is returned when a user is not found.
 	ErrUserNotFound = errors.New("user not found")
+	// ErrNameTaken is returned when a display name is already used by another user.
+	ErrNameTaken = errors.New("name already taken")
 	// ErrTokenNotFound is returned when a token is not found.
 	ErrTokenNotFound = errors.New("token not found")
 	// ErrTokenExpired is returned when a token is expired.
@@ -23,4 +28,43 @@ var (
 	ErrCollaboratorNotFound = errors.New("collaborator not found")
 	// ErrCollaboratorExist is returned when a collaborator already exists.
 	ErrCollaboratorExist = errors.New("collaborator already exists")
+	// ErrTeamNotFound is returned when a team is not found.
+	ErrTeamNotFound = errors.New("team not found")
+	// ErrTeamExist is returned when a team already exists.
+	ErrTeamExist = errors.New("team already exists")
+	// ErrTeamMemberNotFound is returned when a user isn't a member of a team.
+	ErrTeamMemberNotFound = errors.New("team member not found")
+	// ErrTeamMemberExist is returned when a user is already a member of a
+	// team.
+	ErrTeamMemberExist = errors.New("team member already exists")
+	// ErrTeamRepoNotFound is returned when a team has no access to a
+	// repository.
+	ErrTeamRepoNotFound = errors.New("team has no access to the repository")
+	// ErrTeamRepoExist is returned when a team already has access to a
+	// repository.
+	ErrTeamRepoExist = errors.New("team already has access to the repository")
+	// ErrAliasNotFound is returned when a command alias is not found.
+	ErrAliasNotFound = errors.New("alias not found")
+	// ErrPublicKeyNotFound is returned when a public key of a user is not
+	// found.
+	ErrPublicKeyNotFound = errors.New("public key not found")
+	// ErrMaintenance is returned when a write is rejected because the server
+	// is in maintenance mode.
+	ErrMaintenance = errors.New("server is in read-only maintenance mode")
+	// ErrFollower is returned when a write is rejected because the server is
+	// a read-only follower of a primary.
+	ErrFollower = errors.New("server is a read-only follower")
+	// ErrRepoArchived is returned when a write to an archived repository is
+	// rejected.
+	ErrRepoArchived = errors.New("repository is archived and read-only")
+	// ErrNotMirror is returned when a repository is not a mirror.
+	ErrNotMirror = errors.New("repository is not a mirror")
+	// ErrRemoteNotFound is returned when a remote of a mirror is not found.
+	ErrRemoteNotFound = errors.New("remote not found")
+	// ErrUntrustedCertificate is returned when a user certificate isn't
+	// signed by a trusted certificate authority.
+	ErrUntrustedCertificate = errors.New("certificate is not signed by a trusted authority")
+	// ErrInvalidCertificate is returned when a user certificate is expired,
+	// revoked, or otherwise invalid.
+	ErrInvalidCertificate = errors.New("invalid certificate")
 )
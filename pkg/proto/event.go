@@ -0,0 +1,56 @@
+package proto
+
+import "time"
+
+// Event types of the event stream.
+const (
+	// EventPush is a reference updated by a push.
+	EventPush = "push"
+	// EventRefUpdate is a reference updated outside of a push, e.g. a branch
+	// deleted with the "branch delete" command.
+	EventRefUpdate = "ref_update"
+	// EventRepoCreate is a repository created, imported, or created by a
+	// push.
+	EventRepoCreate = "repo_create"
+	// EventRepoDelete is a repository deleted.
+	EventRepoDelete = "repo_delete"
+	// EventRepoRename is a repository renamed.
+	EventRepoRename = "repo_rename"
+)
+
+// EventTypes returns all the event types of the event stream.
+func EventTypes() []string {
+	return []string{
+		EventPush,
+		EventRefUpdate,
+		EventRepoCreate,
+		EventRepoDelete,
+		EventRepoRename,
+	}
+}
+
+// Event is an event of the event stream.
+type Event struct {
+	// Type is the type of the event.
+	Type string `json:"type"`
+	// Repo is the name of the repository.
+	Repo string `json:"repo"`
+	// OldRepo is the previous name of a renamed repository.
+	OldRepo string `json:"old_repo,omitempty"`
+	// Ref is the full name of the updated reference.
+	Ref string `json:"ref,omitempty"`
+	// Before is the commit hash the reference pointed to before the update.
+	Before string `json:"before,omitempty"`
+	// After is the commit hash the reference points to after the update. It's
+	// the zero hash for deleted references.
+	After string `json:"after,omitempty"`
+	// Commits is the number of new commits of a push.
+	Commits int64 `json:"commits,omitempty"`
+	// Forced is true if the push rewrote the history of the reference.
+	Forced bool `json:"forced,omitempty"`
+	// User is the username of the user who caused the event, empty if
+	// unknown.
+	User string `json:"user,omitempty"`
+	// Time is the time of the event.
+	Time time.Time `json:"time"`
+}
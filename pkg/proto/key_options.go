@@ -0,0 +1,171 @@
+package proto
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+)
+
+// Key options.
+const (
+	// KeyOptionCommand is the option of the command a key runs instead of
+	// the one it asks for.
+	KeyOptionCommand = "command"
+	// KeyOptionNoPTY is the option of keys that can't open interactive
+	// sessions.
+	KeyOptionNoPTY = "no-pty"
+	// KeyOptionReadOnly is the option of keys that can only read
+	// repositories.
+	KeyOptionReadOnly = "read-only"
+	// KeyOptionRepo is the option of keys that can only access a single
+	// repository, like deploy keys.
+	KeyOptionRepo = "repo"
+)
+
+// ContextKeyKeyOptions is the context key for the options of the public key
+// of the session.
+var ContextKeyKeyOptions = &struct{ string }{"key-options"}
+
+// KeyOptions are the restrictions of a public key on top of the access of its
+// user. They're written like the options of an authorized key, e.g.
+// `repo="icecream",read-only` for a deploy key.
+type KeyOptions struct {
+	// Command is the command the key runs whatever it asks for.
+	Command string
+	// NoPTY is true if the key can't open interactive sessions, the UI and
+	// the shell.
+	NoPTY bool
+	// ReadOnly is true if the key has read-only access at most.
+	ReadOnly bool
+	// Repo is the only repository the key can access, if any.
+	Repo string
+}
+
+// ParseKeyOptions parses comma separated key options. The values of options
+// are quoted, with backslashes escaping quotes, like in authorized keys.
+func ParseKeyOptions(s string) (KeyOptions, error) {
+	var o KeyOptions
+	for _, opt := range splitKeyOptions(s) {
+		name, value, hasValue := strings.Cut(opt, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if hasValue {
+			value = unquoteKeyOption(value)
+		}
+		switch name {
+		case "":
+			continue
+		case KeyOptionCommand, KeyOptionRepo:
+			if !hasValue || value == "" {
+				return KeyOptions{}, fmt.Errorf("key option %q needs a value, e.g. %s=\"...\"", name, name)
+			}
+			if name == KeyOptionCommand {
+				o.Command = value
+			} else {
+				o.Repo = value
+			}
+		case KeyOptionNoPTY, KeyOptionReadOnly:
+			if hasValue {
+				return KeyOptions{}, fmt.Errorf("key option %q doesn't take a value", name)
+			}
+			if name == KeyOptionNoPTY {
+				o.NoPTY = true
+			} else {
+				o.ReadOnly = true
+			}
+		default:
+			return KeyOptions{}, fmt.Errorf("unknown key option %q, must be one of %s, %s, %s, or %s",
+				name, KeyOptionCommand, KeyOptionNoPTY, KeyOptionReadOnly, KeyOptionRepo)
+		}
+	}
+	return o, nil
+}
+
+// splitKeyOptions splits options at the commas that aren't quoted.
+func splitKeyOptions(s string) []string {
+	opts := make([]string, 0)
+	var cur strings.Builder
+	quoted, escaped := false, false
+	for _, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && quoted:
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			opts = append(opts, cur.String())
+			cur.Reset()
+			continue
+		}
+		cur.WriteRune(r)
+	}
+	return append(opts, cur.String())
+}
+
+// unquoteKeyOption returns the value of an option without its quotes and
+// escapes.
+func unquoteKeyOption(v string) string {
+	if len(v) >= 2 && strings.HasPrefix(v, `"`) && strings.HasSuffix(v, `"`) {
+		v = v[1 : len(v)-1]
+	}
+	return strings.ReplaceAll(v, `\"`, `"`)
+}
+
+// quoteKeyOption quotes the value of an option.
+func quoteKeyOption(v string) string {
+	return `"` + strings.ReplaceAll(v, `"`, `\"`) + `"`
+}
+
+// IsZero returns true if the key has no options.
+func (o KeyOptions) IsZero() bool {
+	return o == KeyOptions{}
+}
+
+// String returns the options like in an authorized key, an empty string when
+// there are none.
+func (o KeyOptions) String() string {
+	opts := make([]string, 0, 4)
+	if o.Command != "" {
+		opts = append(opts, KeyOptionCommand+"="+quoteKeyOption(o.Command))
+	}
+	if o.NoPTY {
+		opts = append(opts, KeyOptionNoPTY)
+	}
+	if o.ReadOnly {
+		opts = append(opts, KeyOptionReadOnly)
+	}
+	if o.Repo != "" {
+		opts = append(opts, KeyOptionRepo+"="+quoteKeyOption(o.Repo))
+	}
+	return strings.Join(opts, ",")
+}
+
+// AccessLevel returns the access level of a key with the options to the
+// repository with the given name, given the access level of its user.
+func (o KeyOptions) AccessLevel(repo string, level access.AccessLevel) access.AccessLevel {
+	if o.Repo != "" && o.Repo != repo {
+		return access.NoAccess
+	}
+	if o.ReadOnly && level > access.ReadOnlyAccess {
+		return access.ReadOnlyAccess
+	}
+	return level
+}
+
+// KeyOptionsFromContext returns the options of the public key of the session.
+// They're zero for sessions without a key.
+func KeyOptionsFromContext(ctx context.Context) KeyOptions {
+	if o, ok := ctx.Value(ContextKeyKeyOptions).(KeyOptions); ok {
+		return o
+	}
+	return KeyOptions{}
+}
+
+// WithKeyOptionsContext returns a new context with the options of the public
+// key of the session.
+func WithKeyOptionsContext(ctx context.Context, o KeyOptions) context.Context {
+	return context.WithValue(ctx, ContextKeyKeyOptions, o)
+}
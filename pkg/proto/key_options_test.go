@@ -0,0 +1,45 @@
+package proto
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/matryer/is"
+)
+
+func TestParseKeyOptions(t *testing.T) {
+	is := is.New(t)
+
+	o, err := ParseKeyOptions(`command="repo info \"ice,cream\"",no-pty,READ-ONLY,repo="icecream"`)
+	is.NoErr(err)
+	is.Equal(o, KeyOptions{
+		Command:  `repo info "ice,cream"`,
+		NoPTY:    true,
+		ReadOnly: true,
+		Repo:     "icecream",
+	})
+	is.Equal(o.String(), `command="repo info \"ice,cream\"",no-pty,read-only,repo="icecream"`)
+
+	o, err = ParseKeyOptions("")
+	is.NoErr(err)
+	is.True(o.IsZero())
+	is.Equal(o.String(), "")
+
+	_, err = ParseKeyOptions("restrict")
+	is.True(err != nil)
+	_, err = ParseKeyOptions("read-only=yes")
+	is.True(err != nil)
+	_, err = ParseKeyOptions("repo")
+	is.True(err != nil)
+}
+
+func TestKeyOptionsAccessLevel(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(KeyOptions{}.AccessLevel("icecream", access.AdminAccess), access.AdminAccess)
+	is.Equal(KeyOptions{ReadOnly: true}.AccessLevel("icecream", access.ReadWriteAccess), access.ReadOnlyAccess)
+	is.Equal(KeyOptions{ReadOnly: true}.AccessLevel("icecream", access.NoAccess), access.NoAccess)
+	is.Equal(KeyOptions{Repo: "icecream"}.AccessLevel("icecream", access.ReadWriteAccess), access.ReadWriteAccess)
+	is.Equal(KeyOptions{Repo: "icecream"}.AccessLevel("cones", access.AdminAccess), access.NoAccess)
+	is.Equal(KeyOptions{Repo: "icecream"}.AccessLevel("", access.AdminAccess), access.NoAccess)
+}
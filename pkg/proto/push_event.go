@@ -0,0 +1,31 @@
+package proto
+
+import "time"
+
+// PushEvent is a reference update pushed to a repository.
+type PushEvent struct {
+	// Repo is the name of the repository.
+	Repo string `json:"repo"`
+	// Ref is the full name of the updated reference.
+	Ref string `json:"ref"`
+	// Before is the commit hash the reference pointed to before the push.
+	Before string `json:"before"`
+	// After is the commit hash the reference points to after the push.
+	After string `json:"after"`
+	// Commits is the number of new commits.
+	Commits int64 `json:"commits"`
+	// Forced is true if the push rewrote the history of the reference, i.e.
+	// it wasn't a fast-forward.
+	Forced bool `json:"forced,omitempty"`
+	// Pusher is the username of the user who pushed, empty if unknown.
+	Pusher string `json:"pusher,omitempty"`
+	// DeployScript is the name of the deploy script the push ran, empty if it
+	// didn't run one.
+	DeployScript string `json:"deploy_script,omitempty"`
+	// DeployStatus is the status of the deploy, one of the Deploy statuses.
+	DeployStatus string `json:"deploy_status,omitempty"`
+	// DeployOutput is the end of the output of the deploy script.
+	DeployOutput string `json:"deploy_output,omitempty"`
+	// CreatedAt is the time of the push.
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,49 @@
+package proto
+
+import "time"
+
+// PushReport is what a push reported back to the client: the status of the
+// references it updated and the messages of the hooks.
+type PushReport struct {
+	// Repo is the name of the repository.
+	Repo string
+	// Unpack is the status of unpacking the pushed objects, "ok" or the
+	// error. It's empty if the push sent no commands.
+	Unpack string
+	// Refs is the status of the references, in the order they were pushed.
+	Refs []RefStatus
+	// Messages is the output of the hooks and of receive-pack, one line per
+	// message.
+	Messages []string
+	// Err is the error the push failed with, if any.
+	Err string
+	// Time is the time the push was done.
+	Time time.Time
+}
+
+// RefStatus is the status of a reference updated by a push.
+type RefStatus struct {
+	// Ref is the full name of the reference.
+	Ref string
+	// OK is true if the reference was updated.
+	OK bool
+	// Reason is why the update was rejected.
+	Reason string
+}
+
+// Rejected returns the number of rejected references.
+func (r PushReport) Rejected() int {
+	n := 0
+	for _, s := range r.Refs {
+		if !s.OK {
+			n++
+		}
+	}
+	return n
+}
+
+// Empty returns true if the push updated nothing and had nothing to say, like
+// a push of references that are up to date.
+func (r PushReport) Empty() bool {
+	return len(r.Refs) == 0 && len(r.Messages) == 0 && r.Err == ""
+}
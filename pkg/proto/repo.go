@@ -22,6 +22,8 @@ type Repository interface {
 	IsMirror() bool
 	// IsHidden returns whether the repository is hidden.
 	IsHidden() bool
+	// IsArchived returns whether the repository is archived, and read-only.
+	IsArchived() bool
 	// UserID returns the ID of the user who owns the repository.
 	// It returns 0 if the repository is not owned by a user.
 	UserID() int64
@@ -34,6 +36,16 @@ type Repository interface {
 	Open() (*git.Repository, error)
 }
 
+// RepositoryListing is a repository as listed by "repo list --json", which is
+// how followers learn the repositories of their primary.
+type RepositoryListing struct {
+	Name        string `json:"name"`
+	ProjectName string `json:"project_name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Private     bool   `json:"private"`
+	Hidden      bool   `json:"hidden"`
+}
+
 // RepositoryOptions are options for creating a new repository.
 type RepositoryOptions struct {
 	Private     bool
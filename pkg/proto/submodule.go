@@ -0,0 +1,47 @@
+package proto
+
+import "fmt"
+
+// Submodule is a submodule of a repository.
+type Submodule struct {
+	// Name is the name of the submodule in .gitmodules.
+	Name string
+	// Path is the path of the submodule in the tree.
+	Path string
+	// URL is the configured URL of the submodule.
+	URL string
+	// Branch is the configured branch of the submodule, if any.
+	Branch string
+	// Commit is the commit the submodule is pinned to.
+	Commit string
+	// Repo is the name of the repository the submodule points to when it's
+	// hosted on this server. It's empty otherwise.
+	Repo string
+	// Missing is true when the pinned commit doesn't exist in Repo.
+	Missing bool
+	// Behind is the number of commits the pinned commit is behind the
+	// submodule branch in Repo.
+	Behind int64
+}
+
+// Local returns true if the submodule is hosted on this server.
+func (s Submodule) Local() bool {
+	return s.Repo != ""
+}
+
+// Status returns a short description of the state of the pinned commit. It
+// returns an empty string for submodules that aren't hosted on this server.
+func (s Submodule) Status() string {
+	switch {
+	case s.Commit == "":
+		return "not in tree"
+	case !s.Local():
+		return ""
+	case s.Missing:
+		return "missing commit"
+	case s.Behind == 0:
+		return "up to date"
+	default:
+		return fmt.Sprintf("%d behind", s.Behind)
+	}
+}
@@ -8,6 +8,8 @@ type User interface {
 	ID() int64
 	// Username returns the user's username.
 	Username() string
+	// Name returns the user's display name.
+	Name() string
 	// IsAdmin returns whether the user is an admin.
 	IsAdmin() bool
 	// PublicKeys returns the user's public keys.
@@ -16,6 +18,19 @@ type User interface {
 	Password() string
 }
 
+// DisplayName returns a human friendly identity for the user. It's the user's
+// display name followed by their username, or just the username when the user
+// doesn't have a display name.
+func DisplayName(u User) string {
+	if u == nil {
+		return ""
+	}
+	if name := u.Name(); name != "" {
+		return name + " (" + u.Username() + ")"
+	}
+	return u.Username()
+}
+
 // UserOptions are options for creating a user.
 type UserOptions struct {
 	// Admin is whether the user is an admin.
@@ -0,0 +1,79 @@
+// Package ratelimit limits the number of requests per minute from clients.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter limits the number of requests per minute from a client address.
+// Every address has a bucket of limit tokens that refills over a minute, and
+// every request takes a token.
+type Limiter struct {
+	mu      sync.Mutex
+	limit   int
+	buckets map[string]*bucket
+	cleaned time.Time
+	now     func() time.Time
+}
+
+// bucket is the token bucket of a client address.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// New returns a rate limiter that allows limit requests per minute from an
+// address. A limit of 0 or less allows every request.
+func New(limit int) *Limiter {
+	return &Limiter{
+		limit:   limit,
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow reports whether a new request from the address is allowed.
+func (l *Limiter) Allow(addr string) bool {
+	if l.limit <= 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.cleanup(now)
+
+	b, ok := l.buckets[addr]
+	if !ok {
+		b = &bucket{tokens: float64(l.limit), last: now}
+		l.buckets[addr] = b
+	}
+
+	b.tokens += now.Sub(b.last).Minutes() * float64(l.limit)
+	if max := float64(l.limit); b.tokens > max {
+		b.tokens = max
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// cleanup removes the buckets that have been refilled, at most once a minute,
+// so that the limiter doesn't grow with every address it has seen.
+func (l *Limiter) cleanup(now time.Time) {
+	if now.Sub(l.cleaned) < time.Minute {
+		return
+	}
+	l.cleaned = now
+	for addr, b := range l.buckets {
+		if now.Sub(b.last) >= time.Minute {
+			delete(l.buckets, addr)
+		}
+	}
+}
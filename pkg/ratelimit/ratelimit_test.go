@@ -0,0 +1,31 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+	l := New(2)
+	l.now = func() time.Time { return now }
+	if !l.Allow("a") || !l.Allow("a") {
+		t.Fatal("expected the first connections to be allowed")
+	}
+	if l.Allow("a") {
+		t.Error("expected the third connection to be rate limited")
+	}
+	if !l.Allow("b") {
+		t.Error("expected another address to be allowed")
+	}
+	now = now.Add(30 * time.Second)
+	if !l.Allow("a") {
+		t.Error("expected a connection to be allowed after refilling")
+	}
+	if l.Allow("a") {
+		t.Error("expected the connection to be rate limited again")
+	}
+	if !New(0).Allow("a") {
+		t.Error("expected no limit")
+	}
+}
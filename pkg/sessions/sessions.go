@@ -0,0 +1,136 @@
+// Package sessions keeps track of the active SSH sessions of the server.
+package sessions
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ContextKey is the key for the session registry in the context.
+var ContextKey = &struct{ string }{"sessions"}
+
+// SessionContextKey is the key for the current session in the context.
+var SessionContextKey = &struct{ string }{"current-session"}
+
+// FromContext returns the session registry from a context.
+func FromContext(ctx context.Context) *Registry {
+	if r, ok := ctx.Value(ContextKey).(*Registry); ok {
+		return r
+	}
+
+	return nil
+}
+
+// SessionFromContext returns the current session from a context.
+func SessionFromContext(ctx context.Context) *Session {
+	if s, ok := ctx.Value(SessionContextKey).(*Session); ok {
+		return s
+	}
+
+	return nil
+}
+
+// Session is an active SSH session.
+type Session struct {
+	// ID identifies the session while it's active.
+	ID int64
+	// User is the username of the user, or empty for anonymous users.
+	User string
+	// PublicKey is the fingerprint of the public key of the user, if any.
+	PublicKey string
+	// RemoteAddr is the address the session comes from.
+	RemoteAddr string
+	// ConnectedAt is when the session started.
+	ConnectedAt time.Time
+
+	mu       sync.Mutex
+	activity string
+	close    func() error
+}
+
+// Activity returns what the session is doing, e.g. the page of the UI or the
+// git operation.
+func (s *Session) Activity() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.activity
+}
+
+// SetActivity sets what the session is doing.
+func (s *Session) SetActivity(activity string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.activity = activity
+}
+
+// Close terminates the session.
+func (s *Session) Close() error {
+	if s.close == nil {
+		return nil
+	}
+	return s.close()
+}
+
+// Registry is the list of the active sessions.
+type Registry struct {
+	mu       sync.Mutex
+	lastID   int64
+	sessions map[int64]*Session
+	now      func() time.Time
+}
+
+// NewRegistry returns an empty session registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		sessions: make(map[int64]*Session),
+		now:      time.Now,
+	}
+}
+
+// Add registers a new session and returns it. close terminates the session.
+func (r *Registry) Add(user, publicKey, remoteAddr string, close func() error) *Session {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastID++
+	s := &Session{
+		ID:          r.lastID,
+		User:        user,
+		PublicKey:   publicKey,
+		RemoteAddr:  remoteAddr,
+		ConnectedAt: r.now(),
+		close:       close,
+	}
+	r.sessions[s.ID] = s
+	return s
+}
+
+// Remove unregisters the session with the given ID.
+func (r *Registry) Remove(id int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sessions, id)
+}
+
+// Get returns the session with the given ID.
+func (r *Registry) Get(id int64) (*Session, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[id]
+	return s, ok
+}
+
+// List returns the active sessions, oldest first.
+func (r *Registry) List() []*Session {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	list := make([]*Session, 0, len(r.sessions))
+	for _, s := range r.sessions {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
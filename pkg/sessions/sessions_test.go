@@ -0,0 +1,44 @@
+package sessions
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func TestRegistry(t *testing.T) {
+	is := is.New(t)
+	r := NewRegistry()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+
+	errClosed := errors.New("closed")
+	s1 := r.Add("admin", "SHA256:abc", "127.0.0.1:1234", func() error { return errClosed })
+	s2 := r.Add("", "", "127.0.0.1:5678", nil)
+	is.Equal(s1.ID, int64(1))
+	is.Equal(s2.ID, int64(2))
+	is.Equal(s1.ConnectedAt, now)
+
+	s2.SetActivity("tui")
+	is.Equal(s2.Activity(), "tui")
+
+	list := r.List()
+	is.Equal(len(list), 2)
+	is.Equal(list[0], s1)
+	is.Equal(list[1], s2)
+
+	s, ok := r.Get(1)
+	is.True(ok)
+	is.Equal(s.Close(), errClosed)
+	is.NoErr(s2.Close())
+
+	r.Remove(1)
+	_, ok = r.Get(1)
+	is.True(!ok)
+	is.Equal(len(r.List()), 1)
+
+	// IDs aren't reused.
+	is.Equal(r.Add("", "", "", nil).ID, int64(3))
+}
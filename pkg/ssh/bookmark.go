@@ -0,0 +1,164 @@
+package ssh
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/ui/pages/repo"
+	"github.com/charmbracelet/soft-serve/pkg/ui/pages/selection"
+)
+
+var deleteBookmark = key.NewBinding(
+	key.WithKeys("d"),
+	key.WithHelp("d", "delete"),
+)
+
+// bookmarkList is the list of the bookmarks of the user.
+type bookmarkList struct {
+	cursor int
+}
+
+// loadBookmarks loads the files bookmarked by the user.
+func (ui *UI) loadBookmarks() {
+	ui.bookmarks = nil
+	be := ui.common.Backend()
+	pk := ui.common.PublicKey()
+	if be == nil || pk == nil {
+		return
+	}
+
+	spec, err := be.Preference(ui.common.Context(), pk, common.BookmarksPreference)
+	if err != nil {
+		ui.common.Logger.Debugf("ui: failed to load bookmarks: %v", err)
+	}
+	ui.bookmarks = common.ParseBookmarks(spec)
+}
+
+// saveBookmarks saves the bookmarks of the user. Keyless users keep their
+// bookmarks until they disconnect.
+func (ui *UI) saveBookmarks() error {
+	be := ui.common.Backend()
+	pk := ui.common.PublicKey()
+	if be == nil || pk == nil {
+		return nil
+	}
+
+	ctx := ui.common.Context()
+	if len(ui.bookmarks) == 0 {
+		return be.DeletePreference(ctx, pk, common.BookmarksPreference)
+	}
+	return be.SetPreference(ctx, pk, common.BookmarksPreference, common.FormatBookmarks(ui.bookmarks))
+}
+
+// toggleBookmark bookmarks a file, or removes its bookmark.
+func (ui *UI) toggleBookmark(b common.Bookmark) tea.Cmd {
+	var added bool
+	ui.bookmarks, added = common.ToggleBookmark(ui.bookmarks, b)
+	loc := b.Path
+	if b.Line > 0 {
+		loc = fmt.Sprintf("%s:%d", loc, b.Line)
+	}
+	status := fmt.Sprintf("Removed the bookmark of %s", loc)
+	if added {
+		status = fmt.Sprintf("Bookmarked %s", loc)
+	}
+	if err := ui.saveBookmarks(); err != nil {
+		ui.common.Logger.Debugf("ui: failed to save bookmarks: %v", err)
+		status = "Failed to save the bookmarks"
+	}
+	return func() tea.Msg { return repo.StatusMsg(status) }
+}
+
+// updateBookmarkList handles key presses while the bookmarks are listed.
+func (ui *UI) updateBookmarkList(msg tea.KeyMsg) tea.Cmd {
+	bl := ui.bookmarkList
+	switch {
+	case key.Matches(msg, ui.common.KeyMap.Back),
+		key.Matches(msg, ui.common.KeyMap.Bookmarks):
+		ui.bookmarkList = nil
+	case key.Matches(msg, ui.common.KeyMap.Up):
+		if bl.cursor > 0 {
+			bl.cursor--
+		}
+	case key.Matches(msg, ui.common.KeyMap.Down):
+		if bl.cursor < len(ui.bookmarks)-1 {
+			bl.cursor++
+		}
+	case key.Matches(msg, deleteBookmark):
+		ui.bookmarks = append(ui.bookmarks[:bl.cursor:bl.cursor], ui.bookmarks[bl.cursor+1:]...)
+		if err := ui.saveBookmarks(); err != nil {
+			ui.common.Logger.Debugf("ui: failed to save bookmarks: %v", err)
+		}
+		if bl.cursor >= len(ui.bookmarks) {
+			bl.cursor = len(ui.bookmarks) - 1
+		}
+		if len(ui.bookmarks) == 0 {
+			ui.bookmarkList = nil
+		}
+	case key.Matches(msg, ui.common.KeyMap.Select):
+		ui.bookmarkList = nil
+		return ui.setBookmarkCmd(ui.bookmarks[bl.cursor])
+	}
+	return nil
+}
+
+// bookmarkListHelp returns the key bindings of the list of bookmarks.
+func (ui *UI) bookmarkListHelp() []key.Binding {
+	back := ui.common.KeyMap.Back
+	back.SetHelp("esc", "close")
+	return []key.Binding{
+		ui.common.KeyMap.UpDown,
+		ui.common.KeyMap.Select,
+		deleteBookmark,
+		back,
+	}
+}
+
+// renderBookmarkList renders the list of bookmarks in the middle of the page.
+func (ui *UI) renderBookmarkList(width, height int) string {
+	st := ui.common.Styles.RepoSelector
+	var sb strings.Builder
+	sb.WriteString(st.Normal.Title.Render("Bookmarks"))
+	sb.WriteString("\n")
+	for i, b := range ui.bookmarks {
+		line := common.TruncateString(b.String(), width-6)
+		sb.WriteString("\n")
+		if i == ui.bookmarkList.cursor {
+			sb.WriteString(st.Active.Title.Render("> " + line))
+		} else {
+			sb.WriteString(st.Normal.Desc.Render("  " + line))
+		}
+	}
+	box := ui.common.Renderer.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.common.Styles.InactiveBorderColor).
+		Padding(0, 1).
+		Render(sb.String())
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// setBookmarkCmd opens the repository of the bookmark at its reference, and
+// its file in the Files tab once the reference is loaded.
+func (ui *UI) setBookmarkCmd(b common.Bookmark) tea.Cmd {
+	onRepo := ui.activePage == repoPage
+	return func() tea.Msg {
+		r, err := ui.openRepo(b.Repo)
+		if err != nil {
+			status := fmt.Sprintf("%s no longer exists", b.Repo)
+			if onRepo {
+				return repo.StatusMsg(status)
+			}
+			return selection.StatusMsg(status)
+		}
+		return repoRefMsg{
+			repo: r,
+			ref:  b.Ref,
+			tab:  "files",
+			file: &fileLink{ref: b.Ref, path: b.Path, start: b.Line},
+		}
+	}
+}
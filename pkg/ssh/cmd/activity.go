@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/spf13/cobra"
+)
+
+// activityCommand returns a command that lists recent pushes.
+func activityCommand() *cobra.Command {
+	var opts outputOptions
+
+	cmd := &cobra.Command{
+		Use:               "activity [REPOSITORY]",
+		Short:             "List recent pushes",
+		Long:              "List recent pushes to a repository, or to all the repositories you can access.",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return nil
+			}
+			return checkIfReadable(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			user := proto.UserFromContext(ctx)
+
+			var repo proto.Repository
+			if len(args) > 0 {
+				var err error
+				repo, err = be.Repository(ctx, args[0])
+				if err != nil {
+					return err
+				}
+			}
+
+			limit := 0
+			if opts.limit > 0 {
+				limit = opts.offset + opts.limit
+			}
+			events, err := be.PushEvents(ctx, user, repo, limit)
+			if err != nil {
+				return err
+			}
+
+			out := opts.newOutput(cmd)
+			for _, ev := range events {
+				if out.Done() {
+					break
+				}
+				out.Println(formatPushEvent(ev))
+			}
+			return nil
+		},
+	}
+
+	opts.addFlags(cmd)
+
+	return cmd
+}
+
+// formatPushEvent returns a one line summary of a push event. Forced updates
+// are marked with "!" followed by the old and new commit hashes, and deploys
+// are followed by their script and status.
+func formatPushEvent(ev proto.PushEvent) string {
+	pusher := ev.Pusher
+	if pusher == "" {
+		pusher = "unknown"
+	}
+
+	var change string
+	switch {
+	case git.IsZeroHash(ev.After):
+		change = "deleted"
+	case ev.Commits == 1:
+		change = "1 commit"
+	default:
+		change = fmt.Sprintf("%d commits", ev.Commits)
+	}
+	if ev.Forced {
+		change += fmt.Sprintf(" ! forced update %s...%s", ev.Before[:7], ev.After[:7])
+	}
+	if ev.DeployScript != "" {
+		change += fmt.Sprintf(", deploy %s %s", ev.DeployScript, ev.DeployStatus)
+	}
+
+	return fmt.Sprintf("%s %s %s %s %s",
+		ev.CreatedAt.UTC().Format(time.RFC3339),
+		ev.Repo,
+		git.ReferenceName(ev.Ref).Short(),
+		pusher,
+		change,
+	)
+}
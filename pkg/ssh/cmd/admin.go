@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caarlos0/tablewriter"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/sessions"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+// AdminCommand returns the admin subcommand.
+func AdminCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "admin",
+		Short:             "Server administration",
+		PersistentPreRunE: checkIfAdmin,
+	}
+
+	cmd.AddCommand(
+		adminExportMetaCommand(),
+		adminFsckCommand(),
+		adminHousekeepingCommand(),
+		adminImportMetaCommand(),
+		adminReflogCommand(),
+		adminRepoConfigCommand(),
+		adminSessionsCommand(),
+		adminSizesCommand(),
+	)
+
+	return cmd
+}
+
+func adminRepoConfigCommand() *cobra.Command {
+	var value string
+	cmd := &cobra.Command{
+		Use:   "repo-config REPOSITORY [KEY]",
+		Short: "Get or set the Git configuration of a repository",
+		Long: `Get or set the Git configuration of a repository.
+
+Only the core, gc, pack, and receive sections can be read and set, except for
+keys that run commands or point outside of the repository. Use --set to set
+the value of KEY.`,
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completeRepo(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			rn := args[0]
+			var key string
+			if len(args) > 1 {
+				key = args[1]
+			}
+
+			if cmd.Flags().Changed("set") {
+				if key == "" {
+					return exitErrorf(ExitUsage, "a key is required to set a value")
+				}
+				return be.SetRepoConfig(ctx, rn, key, value)
+			}
+
+			entries, err := be.RepoConfig(ctx, rn, key)
+			if err != nil {
+				return err
+			}
+
+			if key != "" && len(entries) == 0 {
+				return exitErrorf(ExitNotFound, "config key %q not found", key)
+			}
+
+			for _, e := range entries {
+				cmd.Printf("%s=%s\n", e.Key, e.Value)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&value, "set", "", "set the value of the key")
+
+	return cmd
+}
+
+func adminHousekeepingCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "housekeeping [REPOSITORY]",
+		Short: "List the recent housekeeping runs",
+		Long: `List the recent housekeeping runs, the newest first, of all repositories or
+of REPOSITORY. Housekeeping runs Git maintenance tasks on the repositories on
+a schedule, see the housekeeping section of the config.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeRepo(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			be := backend.FromContext(cmd.Context())
+			var rn string
+			if len(args) > 0 {
+				rn = args[0]
+			}
+
+			return tablewriter.Render(
+				cmd.OutOrStdout(),
+				be.HousekeepingRuns(rn),
+				[]string{"Repository", "Task", "Started", "Duration", "Status"},
+				func(r backend.HousekeepingRun) ([]string, error) {
+					return []string{
+						r.Repo,
+						r.Task,
+						humanize.Time(r.StartedAt),
+						r.Duration.Round(time.Millisecond).String(),
+						r.Status(),
+					}, nil
+				},
+			)
+		},
+	}
+
+	var dryRun bool
+	runCmd := &cobra.Command{
+		Use:   "run REPOSITORY [TASK...]",
+		Short: "Run housekeeping tasks on a repository now",
+		Long: fmt.Sprintf(`Run housekeeping tasks on a repository now, the configured tasks of the
+repository unless TASK is given. Valid tasks are %s.
+
+Use --dry-run to list what the tasks would do, and the space they would free
+at most, without running them.`, strings.Join(config.HousekeepingTasks, ", ")),
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeRepo(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			cfg := config.FromContext(ctx)
+			rn := args[0]
+			if _, err := be.Repository(ctx, rn); err != nil {
+				return err
+			}
+
+			tasks := args[1:]
+			for _, t := range tasks {
+				if !slices.Contains(config.HousekeepingTasks, t) {
+					return exitErrorf(ExitUsage, "invalid task %q: must be one of %s", t, strings.Join(config.HousekeepingTasks, ", "))
+				}
+			}
+			if len(tasks) == 0 {
+				tasks = cfg.Housekeeping.TasksFor(utils.SanitizeRepo(rn))
+			}
+			if len(tasks) == 0 {
+				return fmt.Errorf("no housekeeping tasks for repository %q", rn)
+			}
+
+			if dryRun {
+				changes, err := be.HousekeepDryRun(ctx, rn, tasks)
+				if err != nil {
+					return err
+				}
+				return printChanges(cmd, changes)
+			}
+
+			var failed bool
+			for _, r := range be.Housekeep(ctx, rn, tasks) {
+				cmd.Printf("%s: %s\n", r.Task, r.Status())
+				failed = failed || r.Err != nil
+			}
+			if failed {
+				return fmt.Errorf("housekeeping failed")
+			}
+
+			return nil
+		},
+	}
+
+	runCmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "List what the tasks would do without running them")
+	cmd.AddCommand(runCmd)
+
+	return cmd
+}
+
+func adminFsckCommand() *cobra.Command {
+	var all, quarantine, quiet bool
+	cmd := &cobra.Command{
+		Use:   "fsck [REPOSITORY...]",
+		Short: "Verify the objects of repositories",
+		Long: `Verify the connectivity and validity of the objects of repositories with git
+fsck. Its output is printed as it goes, followed by a summary of the missing,
+corrupt, and dangling objects of each repository. Dangling objects are
+harmless, housekeeping prunes them eventually. Use --all to verify all of the
+repositories.
+
+The command fails if any repository has missing or corrupt objects, unless
+--quarantine is given, which archives them instead so that they reject pushes
+until an admin looks at them. The runs are listed with "admin housekeeping",
+and fsck can also run on a schedule as a housekeeping task.`,
+		ValidArgsFunction: completeRepo(),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if all == (len(args) > 0) {
+				return exitErrorf(ExitUsage, "either repositories or --all are required")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			names := args
+			if all {
+				repos, err := be.Repositories(ctx)
+				if err != nil {
+					return err
+				}
+				names = make([]string, 0, len(repos))
+				for _, r := range repos {
+					names = append(names, r.Name())
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			if quiet {
+				out = io.Discard
+			}
+			var broken int
+			for _, rn := range names {
+				res, err := be.Fsck(ctx, rn, out, quarantine)
+				switch {
+				case err == nil:
+					cmd.Printf("%s: ok, %s\n", rn, res)
+				case errors.Is(err, backend.ErrRepoCorrupt):
+					broken++
+					cmd.Printf("%s: %s\n", rn, err)
+				default:
+					return err
+				}
+			}
+			if broken > 0 && !quarantine {
+				return fmt.Errorf("%d of %d repositories have missing or corrupt objects", broken, len(names))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "Verify all repositories")
+	cmd.Flags().BoolVar(&quarantine, "quarantine", false, "Archive the repositories with missing or corrupt objects instead of failing")
+	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "Only print the summary of each repository")
+
+	return streamOutput(cmd)
+}
+
+// printOperationStats prints the git operations running and queued, and the
+// limits they're under.
+func printOperationStats(cmd *cobra.Command) {
+	st := backend.FromContext(cmd.Context()).OperationStats()
+	limit := func(n int) string {
+		if n <= 0 {
+			return "no limit"
+		}
+		return strconv.Itoa(n)
+	}
+	cmd.Printf("\nGit operations: %d running, %d queued (max %s, per repository %s)\n",
+		st.Running, st.Queued, limit(st.Max), limit(st.PerRepo))
+}
+
+func adminSessionsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "List active SSH sessions",
+		Long: `List active SSH sessions, followed by the git operations running and queued
+on the server over SSH, HTTP, and the Git daemon.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			reg := sessions.FromContext(cmd.Context())
+			if reg == nil {
+				return fmt.Errorf("sessions are not available")
+			}
+
+			if err := tablewriter.Render(
+				cmd.OutOrStdout(),
+				reg.List(),
+				[]string{"ID", "User", "Public Key", "Address", "Connected", "Activity"},
+				func(s *sessions.Session) ([]string, error) {
+					user := s.User
+					if user == "" {
+						user = "anonymous"
+					}
+					pk := s.PublicKey
+					if pk == "" {
+						pk = "-"
+					}
+					activity := s.Activity()
+					if activity == "" {
+						activity = "-"
+					}
+
+					return []string{
+						strconv.FormatInt(s.ID, 10),
+						user,
+						pk,
+						s.RemoteAddr,
+						humanize.Time(s.ConnectedAt),
+						activity,
+					}, nil
+				},
+			); err != nil {
+				return err
+			}
+
+			printOperationStats(cmd)
+			return nil
+		},
+	}
+
+	terminateCmd := &cobra.Command{
+		Use:     "terminate ID",
+		Aliases: []string{"kill"},
+		Short:   "Terminate an active SSH session",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			reg := sessions.FromContext(cmd.Context())
+			if reg == nil {
+				return fmt.Errorf("sessions are not available")
+			}
+
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return exitErrorf(ExitUsage, "invalid session id %q", args[0])
+			}
+
+			s, ok := reg.Get(id)
+			if !ok {
+				return exitErrorf(ExitNotFound, "session %d not found", id)
+			}
+
+			if cur := sessions.SessionFromContext(cmd.Context()); cur != nil && cur.ID == s.ID {
+				return fmt.Errorf("cannot terminate the current session")
+			}
+
+			if err := s.Close(); err != nil {
+				return err
+			}
+
+			cmd.PrintErrln("Session terminated")
+			return nil
+		},
+	}
+
+	cmd.AddCommand(terminateCmd)
+
+	return cmd
+}
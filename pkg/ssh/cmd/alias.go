@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/anmitsu/go-shlex"
+	"github.com/caarlos0/tablewriter"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"github.com/spf13/cobra"
+)
+
+// maxAliasDepth is the maximum number of nested alias expansions.
+const maxAliasDepth = 10
+
+var aliasWordRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// AliasCommand returns a command that manages command aliases.
+func AliasCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias",
+		Short: "Manage command aliases",
+		Long: `Manage command aliases.
+
+Aliases are expanded before running a command and any arguments after the
+alias are appended to the expanded command. An alias name can have multiple
+words, for example "repo all", but it can't shadow a built-in command.`,
+	}
+
+	setCmd := &cobra.Command{
+		Use:   "set NAME COMMAND [ARGS...]",
+		Short: "Create or replace a command alias",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			pk := sshutils.PublicKeyFromContext(ctx)
+
+			words := strings.Fields(args[0])
+			if len(words) == 0 {
+				return exitErrorf(ExitUsage, "invalid alias name %q", args[0])
+			}
+			for _, w := range words {
+				if !aliasWordRe.MatchString(w) {
+					return exitErrorf(ExitUsage, "invalid alias name %q", args[0])
+				}
+			}
+			name := strings.Join(words, " ")
+			if isBuiltinCommand(cmd.Root(), words) {
+				return exitErrorf(ExitExist, "alias %q shadows a built-in command", name)
+			}
+
+			command := make([]string, len(args)-1)
+			for i, arg := range args[1:] {
+				if arg == "" || strings.ContainsAny(arg, " \t\n\"'\\") {
+					arg = strconv.Quote(arg)
+				}
+				command[i] = arg
+			}
+
+			return be.SetCommandAlias(ctx, pk, name, strings.Join(command, " "))
+		},
+	}
+
+	// Everything after the alias name belongs to the aliased command.
+	setCmd.Flags().SetInterspersed(false)
+
+	removeCmd := &cobra.Command{
+		Use:     "remove NAME",
+		Aliases: []string{"rm", "delete"},
+		Short:   "Remove a command alias",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			pk := sshutils.PublicKeyFromContext(ctx)
+			name := strings.Join(strings.Fields(args[0]), " ")
+
+			return be.DeleteCommandAlias(ctx, pk, name)
+		},
+	}
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List command aliases",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			pk := sshutils.PublicKeyFromContext(ctx)
+
+			aliases, err := be.CommandAliases(ctx, pk)
+			if err != nil {
+				return err
+			}
+
+			if len(aliases) == 0 {
+				cmd.Println("No aliases found")
+				return nil
+			}
+
+			return tablewriter.Render(
+				cmd.OutOrStdout(),
+				aliases,
+				[]string{"Name", "Command"},
+				func(a models.CommandAlias) ([]string, error) {
+					return []string{a.Name, a.Command}, nil
+				},
+			)
+		},
+	}
+
+	cmd.AddCommand(
+		setCmd,
+		removeCmd,
+		listCmd,
+	)
+
+	return cmd
+}
+
+// ExpandAliases expands the command aliases of the session public key at the
+// beginning of args. The arguments that follow an alias are passed through.
+// Aliases can refer to other aliases up to maxAliasDepth levels deep.
+// Built-in commands always take precedence over aliases.
+func ExpandAliases(ctx context.Context, root *cobra.Command, args []string) ([]string, error) {
+	pk := sshutils.PublicKeyFromContext(ctx)
+	if pk == nil || len(args) == 0 || strings.HasPrefix(args[0], "git-") {
+		// Don't slow down Git transfers.
+		return args, nil
+	}
+
+	be := backend.FromContext(ctx)
+	aliases, err := be.CommandAliases(ctx, pk)
+	if err != nil || len(aliases) == 0 {
+		return args, err
+	}
+
+	for depth := 0; ; depth++ {
+		alias, n := matchAlias(aliases, args)
+		if alias == nil || isBuiltinCommand(root, args[:n]) {
+			return args, nil
+		}
+		if depth >= maxAliasDepth {
+			return nil, fmt.Errorf("alias %q expands more than %d times, is it recursive?", alias.Name, maxAliasDepth)
+		}
+
+		expanded, err := shlex.Split(alias.Command, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid alias %q: %w", alias.Name, err)
+		}
+		args = append(expanded, args[n:]...)
+	}
+}
+
+// matchAlias returns the longest alias that matches the beginning of args and
+// its number of words.
+func matchAlias(aliases []models.CommandAlias, args []string) (*models.CommandAlias, int) {
+	var match *models.CommandAlias
+	var words int
+	for i, a := range aliases {
+		ws := strings.Fields(a.Name)
+		if len(ws) <= words || len(ws) > len(args) {
+			continue
+		}
+		ok := true
+		for j, w := range ws {
+			if args[j] != w {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			match, words = &aliases[i], len(ws)
+		}
+	}
+	return match, words
+}
+
+// isBuiltinCommand returns true if all the given words name a built-in
+// command.
+func isBuiltinCommand(root *cobra.Command, words []string) bool {
+	c, rest, err := root.Find(words)
+	return err == nil && c != root && len(rest) == 0
+}
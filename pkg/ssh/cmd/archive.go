@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"strconv"
+
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/spf13/cobra"
+)
+
+func archiveFlagCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "archive-flag REPOSITORY [TRUE|FALSE]",
+		Short: "Archive or unarchive a repository",
+		Long: `Archive or unarchive a repository.
+
+Archived repositories are read-only: they can be cloned and fetched, but reject
+pushes. They're dimmed and listed last in the UI. Repositories without pushes
+for "repo.archive_after" days are archived automatically when it's set, and get
+as many days again once unarchived.`,
+		Aliases:           []string{"archived"},
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completeRepo(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			repo := args[0]
+			switch len(args) {
+			case 1:
+				if err := checkIfReadable(cmd, args); err != nil {
+					return err
+				}
+
+				archived, err := be.IsArchived(ctx, repo)
+				if err != nil {
+					return err
+				}
+
+				cmd.Println(archived)
+			case 2:
+				if err := checkIfCollab(cmd, args); err != nil {
+					return err
+				}
+
+				archived, err := strconv.ParseBool(args[1])
+				if err != nil {
+					return exitErrorf(ExitUsage, "invalid value %q: must be true or false", args[1])
+				}
+				if err := be.SetArchived(ctx, repo, archived); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"github.com/spf13/cobra"
+)
+
+func attestationCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "attestation",
+		Aliases: []string{"attest"},
+		Short:   "Manage the attestations of repositories",
+		Long: fmt.Sprintf(`Manage the attestations of repositories.
+
+An attestation is a statement of the commits the references of a repository
+pointed to, signed by the server with its attestation key. Mirrors are attested
+after every sync, and admins can attest any repository. An attestation shows
+that the content came through this server, it says nothing about who authored
+the commits, unlike their own signatures.
+
+Attestations are SSH signatures in the %q namespace, they can also
+be verified with "ssh-keygen -Y verify" and the key of "repo attestation key".`, backend.AttestationNamespace),
+	}
+
+	cmd.AddCommand(
+		attestationKeyCommand(),
+		attestationShowCommand(),
+		attestationSignCommand(),
+		attestationVerifyCommand(),
+	)
+
+	return cmd
+}
+
+func attestationKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "key",
+		Short:             "Print the public key attestations are signed with",
+		Args:              cobra.NoArgs,
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			be := backend.FromContext(cmd.Context())
+			pk, err := be.AttestationKey()
+			if err != nil {
+				return err
+			}
+
+			cmd.Println(sshutils.MarshalAuthorizedKey(pk))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func attestationShowCommand() *cobra.Command {
+	var statement, signature bool
+	cmd := &cobra.Command{
+		Use:               "show REPOSITORY",
+		Short:             "Print the last attestation of a repository",
+		Long:              "Print the statement of the last attestation of a repository, followed by its signature.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			att, err := be.Attestation(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			// Print them as they are, the signature is of the exact bytes.
+			out := cmd.OutOrStdout()
+			switch {
+			case statement:
+				fmt.Fprint(out, att.Statement) // nolint: errcheck
+			case signature:
+				fmt.Fprint(out, att.Signature) // nolint: errcheck
+			default:
+				fmt.Fprint(out, att.Statement) // nolint: errcheck
+				fmt.Fprintln(out)              // nolint: errcheck
+				fmt.Fprint(out, att.Signature) // nolint: errcheck
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&statement, "statement", false, "Only print the statement")
+	cmd.Flags().BoolVar(&signature, "signature", false, "Only print the signature")
+	cmd.MarkFlagsMutuallyExclusive("statement", "signature")
+
+	return cmd
+}
+
+func attestationSignCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "sign REPOSITORY",
+		Short:             "Attest the current references of a repository",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfRepoAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			if _, err := be.Attest(ctx, args[0]); err != nil {
+				return err
+			}
+
+			cmd.Println("Attested", args[0])
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func attestationVerifyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify REPOSITORY",
+		Short: "Verify the last attestation of a repository",
+		Long: `Verify the signature of the last attestation of a repository, and that its
+references still point to the attested commits. Changed references are listed,
+and the command fails if there is any.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			changed, err := be.VerifyAttestation(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			if len(changed) == 0 {
+				cmd.Println("Good attestation of", args[0])
+				return nil
+			}
+
+			for _, ref := range changed {
+				switch {
+				case ref.Attested == "":
+					cmd.Printf("%s: created %s\n", ref.Name, ref.Current)
+				case ref.Current == "":
+					cmd.Printf("%s: deleted, was %s\n", ref.Name, ref.Attested)
+				default:
+					cmd.Printf("%s: %s, was %s\n", ref.Name, ref.Current, ref.Attested)
+				}
+			}
+			return fmt.Errorf("%d references changed since the attestation", len(changed))
+		},
+	}
+
+	return cmd
+}
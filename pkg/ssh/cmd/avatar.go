@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/avatar"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+func avatarCommand() *cobra.Command {
+	var upload, reset bool
+	cmd := &cobra.Command{
+		Use:   "avatar REPOSITORY [PATH]",
+		Short: "Set or get the avatar of a repository",
+		Long: fmt.Sprintf(`Set or get the avatar of a repository.
+
+The avatar is shown next to the repository in the terminal UI. PATH is an
+image in the default branch of the repository, it's read again after pushes.
+Use --upload to read the image from the standard input instead. Images must be
+PNG, JPEG, or GIF files of at most %s and %dx%d pixels.
+
+Use --reset to remove the avatar. Repositories without an avatar get an
+identicon generated from their name.`, humanize.IBytes(avatar.MaxSize), avatar.MaxDimension, avatar.MaxDimension),
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completeRepo(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			rn := strings.TrimSuffix(args[0], ".git")
+			if len(args) == 1 && !upload && !reset {
+				if err := checkIfReadable(cmd, args); err != nil {
+					return err
+				}
+
+				path, uploaded, err := be.AvatarPath(ctx, rn)
+				if err != nil {
+					return err
+				}
+				switch {
+				case uploaded:
+					path = "uploaded"
+				case path == "":
+					path = "none"
+				}
+
+				cmd.Println(path)
+				return nil
+			}
+
+			if err := checkIfCollab(cmd, args); err != nil {
+				return err
+			}
+
+			switch {
+			case reset:
+				if len(args) > 1 {
+					return exitErrorf(ExitUsage, "--reset doesn't take a path")
+				}
+				return be.DeleteAvatar(ctx, rn)
+			case upload:
+				if len(args) > 1 {
+					return exitErrorf(ExitUsage, "--upload reads the image from the standard input, it doesn't take a path")
+				}
+				data, err := io.ReadAll(io.LimitReader(cmd.InOrStdin(), avatar.MaxSize+1))
+				if err != nil {
+					return err
+				}
+				return be.SetAvatar(ctx, rn, data)
+			default:
+				return be.SetAvatarPath(ctx, rn, strings.TrimPrefix(args[1], "/"))
+			}
+		},
+	}
+
+	cmd.Flags().BoolVarP(&upload, "upload", "u", false, "Read the image from the standard input")
+	cmd.Flags().BoolVarP(&reset, "reset", "r", false, "Remove the avatar")
+	cmd.MarkFlagsMutuallyExclusive("upload", "reset")
+
+	return cmd
+}
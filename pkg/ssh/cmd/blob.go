@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/soft-serve/git"
@@ -16,6 +17,7 @@ func blobCommand(renderer *lipgloss.Renderer) *cobra.Command {
 	var linenumber bool
 	var color bool
 	var raw bool
+	var opts outputOptions
 
 	styles := styles.DefaultStyles(renderer)
 	cmd := &cobra.Command{
@@ -23,6 +25,7 @@ func blobCommand(renderer *lipgloss.Renderer) *cobra.Command {
 		Aliases:           []string{"cat", "show"},
 		Short:             "Print out the contents of file at path",
 		Args:              cobra.RangeArgs(1, 3),
+		ValidArgsFunction: completeRepo(revisionArg),
 		PersistentPreRunE: checkIfReadable,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
@@ -95,7 +98,13 @@ func blobCommand(renderer *lipgloss.Renderer) *cobra.Command {
 					c, _ = common.FormatLineNumber(styles, c, color)
 				}
 
-				cmd.Println(c)
+				out := opts.newOutput(cmd)
+				for _, line := range strings.Split(c, "\n") {
+					if out.Done() {
+						break
+					}
+					out.Println(line)
+				}
 			}
 			return nil
 		},
@@ -104,6 +113,7 @@ func blobCommand(renderer *lipgloss.Renderer) *cobra.Command {
 	cmd.Flags().BoolVarP(&raw, "raw", "r", false, "Print raw contents")
 	cmd.Flags().BoolVarP(&linenumber, "linenumber", "l", false, "Print line numbers")
 	cmd.Flags().BoolVarP(&color, "color", "c", false, "Colorize output")
+	opts.addFlags(cmd)
 
 	return cmd
 }
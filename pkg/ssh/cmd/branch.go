@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -22,6 +23,8 @@ func branchCommand() *cobra.Command {
 		branchListCommand(),
 		branchDefaultCommand(),
 		branchDeleteCommand(),
+		branchMergeCheckCommand(),
+		branchProtectionCommand(),
 	)
 
 	return cmd
@@ -32,6 +35,7 @@ func branchListCommand() *cobra.Command {
 		Use:               "list REPOSITORY",
 		Short:             "List repository branches",
 		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRepo(),
 		PersistentPreRunE: checkIfReadable,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
@@ -61,9 +65,10 @@ func branchListCommand() *cobra.Command {
 
 func branchDefaultCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "default REPOSITORY [BRANCH]",
-		Short: "Set or get the default branch",
-		Args:  cobra.RangeArgs(1, 2),
+		Use:               "default REPOSITORY [BRANCH]",
+		Short:             "Set or get the default branch",
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completeRepo(branchArg),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			be := backend.FromContext(ctx)
@@ -99,29 +104,32 @@ func branchDefaultCommand() *cobra.Command {
 					return err
 				}
 
-				r, err := rr.Open()
-				if err != nil {
-					return err
-				}
-
 				branch := args[1]
-				branches, _ := r.Branches()
-				var exists bool
-				for _, b := range branches {
-					if branch == b {
-						exists = true
-						break
+				if err := be.WithRepository(ctx, rr.Name(), true, func() error {
+					r, err := rr.Open()
+					if err != nil {
+						return err
 					}
-				}
 
-				if !exists {
-					return git.ErrReferenceNotExist
-				}
+					branches, _ := r.Branches()
+					var exists bool
+					for _, b := range branches {
+						if branch == b {
+							exists = true
+							break
+						}
+					}
+
+					if !exists {
+						return git.ErrReferenceNotExist
+					}
 
-				if _, err := r.SymbolicRef(git.HEAD, gitm.RefsHeads+branch, gitm.SymbolicRefOptions{
-					CommandOptions: gitm.CommandOptions{
-						Context: ctx,
-					},
+					_, err = r.SymbolicRef(git.HEAD, gitm.RefsHeads+branch, gitm.SymbolicRefOptions{
+						CommandOptions: gitm.CommandOptions{
+							Context: ctx,
+						},
+					})
+					return err
 				}); err != nil {
 					return err
 				}
@@ -149,6 +157,7 @@ func branchDeleteCommand() *cobra.Command {
 		Aliases:           []string{"remove", "rm", "del"},
 		Short:             "Delete a branch",
 		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeRepo(branchArg),
 		PersistentPreRunE: checkIfCollab,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
@@ -159,51 +168,154 @@ func branchDeleteCommand() *cobra.Command {
 				return err
 			}
 
-			r, err := rr.Open()
-			if err != nil {
-				return err
+			if rr.IsArchived() {
+				return proto.ErrRepoArchived
 			}
 
 			branch := args[1]
-			branches, _ := r.Branches()
-			var exists bool
-			for _, b := range branches {
-				if branch == b {
-					exists = true
-					break
+			var branchCommit *git.Commit
+			if err := be.WithRepository(ctx, rr.Name(), true, func() error {
+				r, err := rr.Open()
+				if err != nil {
+					return err
+				}
+
+				branches, _ := r.Branches()
+				var exists bool
+				for _, b := range branches {
+					if branch == b {
+						exists = true
+						break
+					}
+				}
+
+				if !exists {
+					return git.ErrReferenceNotExist
 				}
-			}
 
-			if !exists {
-				return git.ErrReferenceNotExist
+				head, err := r.HEAD()
+				if err != nil {
+					return err
+				}
+
+				if head.Name().Short() == branch {
+					return fmt.Errorf("cannot delete the default branch")
+				}
+
+				branchCommit, err = r.BranchCommit(branch)
+				if err != nil {
+					return err
+				}
+
+				return r.DeleteBranch(branch, gitm.DeleteBranchOptions{Force: true})
+			}); err != nil {
+				return err
 			}
 
-			head, err := r.HEAD()
+			be.PublishRefUpdate(ctx, rr.Name(), git.RefsHeads+branch, branchCommit.ID.String(), git.ZeroID)
+
+			wh, err := webhook.NewBranchTagEvent(ctx, proto.UserFromContext(ctx), rr, git.RefsHeads+branch, branchCommit.ID.String(), git.ZeroID)
 			if err != nil {
 				return err
 			}
 
-			if head.Name().Short() == branch {
-				return fmt.Errorf("cannot delete the default branch")
-			}
+			return webhook.SendEvent(ctx, wh)
+		},
+	}
 
-			branchCommit, err := r.BranchCommit(branch)
+	return cmd
+}
+
+func branchMergeCheckCommand() *cobra.Command {
+	var quiet bool
+	var show string
+
+	cmd := &cobra.Command{
+		Use:               "merge-check REPOSITORY BRANCH [BASE]",
+		Short:             "Check whether a branch merges cleanly into another",
+		Long:              "Check whether a branch merges cleanly into the base branch, defaults to the default branch, and list the conflicting paths. Nothing in the repository is changed.",
+		Args:              cobra.RangeArgs(2, 3),
+		ValidArgsFunction: completeRepo(branchArg, branchArg),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			rn := strings.TrimSuffix(args[0], ".git")
+			rr, err := be.Repository(ctx, rn)
 			if err != nil {
 				return err
 			}
 
-			if err := r.DeleteBranch(branch, gitm.DeleteBranchOptions{Force: true}); err != nil {
+			r, err := rr.Open()
+			if err != nil {
 				return err
 			}
 
-			wh, err := webhook.NewBranchTagEvent(ctx, proto.UserFromContext(ctx), rr, git.RefsHeads+branch, branchCommit.ID.String(), git.ZeroID)
+			branch := args[1]
+			var base string
+			if len(args) > 2 {
+				base = args[2]
+			} else {
+				head, err := r.HEAD()
+				if err != nil {
+					return err
+				}
+				base = head.Name().Short()
+			}
+
+			branches, _ := r.Branches()
+			for _, b := range []string{branch, base} {
+				var exists bool
+				for _, bb := range branches {
+					if b == bb {
+						exists = true
+						break
+					}
+				}
+				if !exists {
+					return git.ErrReferenceNotExist
+				}
+			}
+
+			res, err := r.TrialMerge(gitm.RefsHeads+base, gitm.RefsHeads+branch, git.MergeOptions{
+				Quick: quiet && show == "",
+			})
+			if errors.Is(err, git.ErrNoMergeBase) {
+				return fmt.Errorf("%s and %s have unrelated histories", branch, base)
+			}
 			if err != nil {
 				return err
 			}
 
-			return webhook.SendEvent(ctx, wh)
+			if show != "" {
+				bts, err := r.ConflictContent(res, show)
+				if err != nil {
+					return err
+				}
+				cmd.Print(string(bts))
+				return nil
+			}
+
+			if res.Clean() {
+				if !quiet {
+					cmd.Printf("%s merges cleanly into %s\n", branch, base)
+				}
+				return nil
+			}
+
+			if !quiet {
+				cmd.Printf("%s conflicts with %s:\n", branch, base)
+				for _, p := range res.Conflicts {
+					cmd.Println(p)
+				}
+			}
+
+			return fmt.Errorf("merge has conflicts")
 		},
 	}
 
+	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "only report whether the merge is clean using the exit status")
+	cmd.Flags().StringVarP(&show, "show", "s", "", "print the three-way merge of a conflicting path")
+
 	return cmd
 }
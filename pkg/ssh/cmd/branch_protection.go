@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/spf13/cobra"
+)
+
+func branchProtectionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "protection",
+		Aliases: []string{"protections", "protect"},
+		Short:   "Manage branch protection rules",
+		Long: `Manage the protection rules of the branches of a repository.
+
+A rule applies to the branches its PATTERN matches, a glob like "main" or
+"release/*". Patterns that start with "refs/" match full reference names, like
+"refs/tags/v*". Pushes can't delete protected branches, and are rejected when
+they break the rule of a branch.`,
+	}
+
+	cmd.AddCommand(
+		branchProtectionListCommand(),
+		branchProtectionSetCommand(),
+		branchProtectionDeleteCommand(),
+	)
+
+	return cmd
+}
+
+func branchProtectionListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "list REPOSITORY",
+		Short:             "List branch protection rules",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			rules, err := be.BranchProtections(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			for _, rule := range rules {
+				cmd.Printf("%s\t%s\n", rule.Pattern, formatBranchProtection(rule))
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func branchProtectionSetCommand() *cobra.Command {
+	var statuses []string
+	var fastForward bool
+	cmd := &cobra.Command{
+		Use:   "set REPOSITORY PATTERN",
+		Short: "Create or replace a branch protection rule",
+		Long: `Create or replace the protection rule of the branches PATTERN matches.
+
+Use --require-status to only accept pushes that update a branch to a commit
+with a successful status for the context, reported with the status API. Use
+--fast-forward to reject pushes that rewrite the history of a branch.`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfRepoAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+
+			return be.SetBranchProtection(ctx, args[0], proto.BranchProtection{
+				Pattern:          args[1],
+				RequiredStatuses: statuses,
+				FastForward:      fastForward,
+			})
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&statuses, "require-status", "s", nil, "Status context that must succeed on the pushed commit")
+	cmd.Flags().BoolVarP(&fastForward, "fast-forward", "f", false, "Only accept fast-forward pushes")
+
+	return cmd
+}
+
+func branchProtectionDeleteCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "delete REPOSITORY PATTERN",
+		Aliases:           []string{"remove", "rm", "del"},
+		Short:             "Delete a branch protection rule",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfRepoAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+
+			return be.DeleteBranchProtection(ctx, args[0], args[1])
+		},
+	}
+
+	return cmd
+}
+
+// formatBranchProtection returns what the rule enforces besides protecting
+// branches from deletion.
+func formatBranchProtection(rule proto.BranchProtection) string {
+	var checks []string
+	if rule.FastForward {
+		checks = append(checks, "fast-forward only")
+	}
+	for _, c := range rule.RequiredStatuses {
+		checks = append(checks, "requires "+c)
+	}
+	if len(checks) == 0 {
+		return "no deletion"
+	}
+	return strings.Join(checks, ", ")
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/spf13/cobra"
+)
+
+// catFileCommand returns a command that prints the type, size, or content of
+// a repository object.
+func catFileCommand() *cobra.Command {
+	var typ, size, pretty bool
+	cmd := &cobra.Command{
+		Use:   "cat-file REPOSITORY OBJECT",
+		Short: "Print the type, size, or content of an object",
+		Long: `Print the type, size, or content of a repository object.
+
+The object can be an abbreviated hash or any revision Git understands, e.g.
+"main:README.md".`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeRepo(revisionArg),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			rr, err := be.Repository(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			r, err := rr.Open()
+			if err != nil {
+				return err
+			}
+
+			info, err := r.ObjectInfo(args[1])
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case typ:
+				cmd.Println(info.Type)
+			case size:
+				cmd.Println(info.Size)
+			case pretty:
+				return r.CatObject(info.ID, cmd.OutOrStdout())
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&typ, "type", "t", false, "Print the object type")
+	cmd.Flags().BoolVarP(&size, "size", "s", false, "Print the object size")
+	cmd.Flags().BoolVarP(&pretty, "pretty", "p", false, "Pretty-print the object content")
+	cmd.MarkFlagsMutuallyExclusive("type", "size", "pretty")
+	cmd.MarkFlagsOneRequired("type", "size", "pretty")
+
+	return streamOutput(cmd)
+}
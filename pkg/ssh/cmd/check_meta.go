@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"strconv"
+
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+// checkMetaCommand returns a command that checks the view settings file of a
+// repository at a revision.
+func checkMetaCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check-meta REPOSITORY [REFERENCE]",
+		Short: "Check the " + backend.ViewSettingsFile + " file of a repository",
+		Long: `Check the ` + backend.ViewSettingsFile + ` file of a repository at a reference,
+HEAD by default, and print the settings it declares. Only the file of the
+default branch applies, check the file of another branch before merging it.
+
+Invalid files print the error with the line of the offending setting. Keys
+that aren't settings, e.g. misspelled ones, are listed, they're ignored.`,
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completeRepo(revisionArg),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			var rev string
+			if len(args) > 1 {
+				rev = args[1]
+			}
+
+			c, err := be.CheckViewSettings(ctx, args[0], rev)
+			if err != nil {
+				return err
+			}
+
+			s := c.Settings
+			ctxLines := "default"
+			if s.DiffContext != nil {
+				ctxLines = strconv.Itoa(*s.DiffContext)
+			}
+			ws := "default"
+			if s.DiffWhitespace != nil {
+				ws = backend.DiffWhitespaceName(*s.DiffWhitespace)
+			}
+			wrap := "default"
+			if s.Wrap != nil {
+				wrap = strconv.FormatBool(*s.Wrap)
+			}
+			theme := s.Theme
+			if theme == "" {
+				theme = "default"
+			}
+			readme := s.Readme
+			if readme == "" {
+				readme = "default"
+			}
+			if c.ReadmeOverride != "" {
+				readme += " (overridden by repo readme: " + c.ReadmeOverride + ")"
+			}
+			readmeMax := "default"
+			if s.ReadmeMaxSize > 0 {
+				readmeMax = humanize.Bytes(uint64(s.ReadmeMaxSize))
+			}
+
+			cmd.Println("Diff context:", ctxLines)
+			cmd.Println("Diff whitespace:", ws)
+			cmd.Println("Wrap:", wrap)
+			cmd.Println("Theme:", theme)
+			cmd.Println("Readme:", readme)
+			cmd.Println("Readme max size:", readmeMax)
+			for _, k := range c.Unknown {
+				cmd.Printf("Unknown setting, ignored: %s\n", k)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
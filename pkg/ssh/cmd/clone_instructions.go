@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/spf13/cobra"
+)
+
+func cloneInstructionsCommand() *cobra.Command {
+	var reset bool
+	cmd := &cobra.Command{
+		Use:     "clone-instructions REPOSITORY [INSTRUCTIONS]",
+		Aliases: []string{"clone-steps"},
+		Short:   "Set or get the clone instructions of a repository",
+		Long: fmt.Sprintf(`Set or get the clone instructions of a repository.
+
+The clone instructions are the steps to set up a clone of the repository, like
+fetching Git LFS objects or submodules, in Markdown. The terminal UI shows them
+with the clone command. Use "-" as INSTRUCTIONS to read them from the standard
+input, they can be at most %d bytes.
+
+Repositories without instructions use the %s file of their default branch.
+Use --reset to remove the instructions and use the file again.`, backend.MaxCloneInstructions, backend.CloneInstructionsFile),
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeRepo(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			rn := strings.TrimSuffix(args[0], ".git")
+			if len(args) == 1 && !reset {
+				if err := checkIfReadable(cmd, args); err != nil {
+					return err
+				}
+
+				instructions, _, err := be.CloneInstructions(ctx, rn)
+				if err != nil {
+					return err
+				}
+
+				if instructions != "" {
+					cmd.Println(instructions)
+				}
+				return nil
+			}
+
+			if err := checkIfCollab(cmd, args); err != nil {
+				return err
+			}
+
+			if reset {
+				if len(args) > 1 {
+					return exitErrorf(ExitUsage, "--reset doesn't take instructions")
+				}
+				return be.SetCloneInstructions(ctx, rn, "")
+			}
+
+			instructions := strings.Join(args[1:], " ")
+			if instructions == "-" {
+				data, err := io.ReadAll(io.LimitReader(cmd.InOrStdin(), backend.MaxCloneInstructions+1))
+				if err != nil {
+					return err
+				}
+				if len(data) > backend.MaxCloneInstructions {
+					return backend.ErrCloneInstructionsTooLong
+				}
+				instructions = string(data)
+			}
+			if strings.TrimSpace(instructions) == "" {
+				return exitErrorf(ExitUsage, "instructions must not be empty, use --reset to remove them")
+			}
+
+			return be.SetCloneInstructions(ctx, rn, instructions)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&reset, "reset", "r", false, "Remove the instructions")
+
+	return cmd
+}
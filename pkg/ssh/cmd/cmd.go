@@ -137,7 +137,30 @@ func IsPublicKeyAdmin(cfg *config.Config, pk ssh.PublicKey) bool {
 	return false
 }
 
-func checkIfAdmin(cmd *cobra.Command, args []string) error {
+// checkIfAdmin checks that the user is an admin of the server. Keys with
+// options can't run the admin commands, their options only limit the access
+// to repositories.
+func checkIfAdmin(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	cfg := config.FromContext(ctx)
+	pk := sshutils.PublicKeyFromContext(ctx)
+	if IsPublicKeyAdmin(cfg, pk) {
+		return nil
+	}
+
+	user := proto.UserFromContext(ctx)
+	if user == nil || !user.IsAdmin() {
+		return proto.ErrUnauthorized
+	}
+	if !proto.KeyOptionsFromContext(ctx).IsZero() {
+		return fmt.Errorf("%w: keys with options can't run admin commands", proto.ErrUnauthorized)
+	}
+	return nil
+}
+
+// checkIfRepoAdmin checks that the user is an admin of the repository, the
+// first argument. The options of the key limit the access of admins too.
+func checkIfRepoAdmin(cmd *cobra.Command, args []string) error {
 	var repo string
 	if len(args) > 0 {
 		repo = args[0]
@@ -157,10 +180,6 @@ func checkIfAdmin(cmd *cobra.Command, args []string) error {
 		return proto.ErrUnauthorized
 	}
 
-	if user.IsAdmin() {
-		return nil
-	}
-
 	auth := be.AccessLevelForUser(cmd.Context(), rn, user)
 	if auth >= access.AdminAccess {
 		return nil
@@ -169,7 +188,45 @@ func checkIfAdmin(cmd *cobra.Command, args []string) error {
 	return proto.ErrUnauthorized
 }
 
+func checkIfUser(cmd *cobra.Command, _ []string) error {
+	user := proto.UserFromContext(cmd.Context())
+	if user == nil {
+		return proto.ErrUnauthorized
+	}
+	return nil
+}
+
+// checkIfUnrestricted checks that the user is connected with a key without
+// options. Keys with options can't manage the keys and tokens of their user,
+// which would lift their restrictions.
+func checkIfUnrestricted(cmd *cobra.Command, args []string) error {
+	if err := checkIfUser(cmd, args); err != nil {
+		return err
+	}
+	if !proto.KeyOptionsFromContext(cmd.Context()).IsZero() {
+		return fmt.Errorf("%w: keys with options can't manage keys and tokens", proto.ErrUnauthorized)
+	}
+	return nil
+}
+
+// checkIfCollab checks that the user can write to the repository. Writes are
+// rejected on followers, they go to the primary.
 func checkIfCollab(cmd *cobra.Command, args []string) error {
+	if err := checkCollabAccess(cmd, args); err != nil {
+		return err
+	}
+
+	var repo string
+	if len(args) > 0 {
+		repo = args[0]
+	}
+	return backend.FromContext(cmd.Context()).CheckFollower(repo)
+}
+
+// checkCollabAccess checks that the user has read-write access to the
+// repository, even on followers, for the commands that only change the data
+// of this server like the collaborators.
+func checkCollabAccess(cmd *cobra.Command, args []string) error {
 	var repo string
 	if len(args) > 0 {
 		repo = args[0]
@@ -3,6 +3,7 @@ package cmd
 import (
 	"github.com/charmbracelet/soft-serve/pkg/access"
 	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/spf13/cobra"
 )
 
@@ -28,7 +29,8 @@ func collabAddCommand() *cobra.Command {
 		Short:             "Add a collaborator to a repo",
 		Long:              "Add a collaborator to a repo. LEVEL can be one of: no-access, read-only, read-write, or admin-access. Defaults to read-write.",
 		Args:              cobra.RangeArgs(2, 3),
-		PersistentPreRunE: checkIfCollab,
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkCollabAccess,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			be := backend.FromContext(ctx)
@@ -53,8 +55,9 @@ func collabRemoveCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:               "remove REPOSITORY USERNAME",
 		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeRepo(),
 		Short:             "Remove a collaborator from a repo",
-		PersistentPreRunE: checkIfCollab,
+		PersistentPreRunE: checkCollabAccess,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			be := backend.FromContext(ctx)
@@ -73,7 +76,8 @@ func collabListCommand() *cobra.Command {
 		Use:               "list REPOSITORY",
 		Short:             "List collaborators for a repo",
 		Args:              cobra.ExactArgs(1),
-		PersistentPreRunE: checkIfCollab,
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkCollabAccess,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			be := backend.FromContext(ctx)
@@ -84,6 +88,9 @@ func collabListCommand() *cobra.Command {
 			}
 
 			for _, c := range collabs {
+				if u, err := be.User(ctx, c); err == nil {
+					c = proto.DisplayName(u)
+				}
 				cmd.Println(c)
 			}
 
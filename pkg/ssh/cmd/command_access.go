@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+)
+
+// CheckCommandAccess returns an error if the user of the session isn't allowed
+// to run the command with the given path, e.g. "repo import", by the command
+// rules of the config. Admins can run any command unless the rules are
+// enforced for them too.
+func CheckCommandAccess(ctx context.Context, path string) error {
+	cfg := config.FromContext(ctx)
+	if cfg == nil {
+		return nil
+	}
+	rule := cfg.Commands.Rule(path)
+	if rule == nil {
+		return nil
+	}
+
+	pk := sshutils.PublicKeyFromContext(ctx)
+	user := proto.UserFromContext(ctx)
+	admin := (pk != nil && IsPublicKeyAdmin(cfg, pk)) || (user != nil && user.IsAdmin())
+	if admin && !cfg.Commands.EnforceAdmins {
+		return nil
+	}
+
+	switch {
+	case admin && slices.Contains(rule.Roles, config.CommandRoleAdmin),
+		user != nil && slices.Contains(rule.Roles, config.CommandRoleUser),
+		user != nil && slices.Contains(rule.Users, user.Username()):
+		return nil
+	}
+	if pk != nil {
+		for _, k := range rule.PublicKeys() {
+			if sshutils.KeysEqual(pk, k) {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("%w: the server restricts who can run %q", proto.ErrUnauthorized, rule.Command)
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/soft-serve/git"
 	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
 	"github.com/charmbracelet/soft-serve/pkg/ui/styles"
 	"github.com/spf13/cobra"
@@ -18,11 +20,20 @@ import (
 func commitCommand(renderer *lipgloss.Renderer) *cobra.Command {
 	var color bool
 	var patchOnly bool
+	var parent int
+	var resolution bool
 
 	cmd := &cobra.Command{
-		Use:               "commit SHA",
-		Short:             "Print out the contents of a diff",
+		Use:   "commit SHA",
+		Short: "Print out the contents of a diff",
+		Long: `Print out the contents of a diff.
+
+Merge commits are compared to their first parent, use --parent to compare them
+to another one. --resolution compares a merge of two commits to the automatic
+merge of its parents instead. It shows how the conflicts were resolved, and
+flags the files edited while merging, changes that come from neither parent.`,
 		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeRepo(revisionArg),
 		PersistentPreRunE: checkIfReadable,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
@@ -45,17 +56,40 @@ func commitCommand(renderer *lipgloss.Renderer) *cobra.Command {
 				return err
 			}
 
-			patch, err := r.Patch(commit)
+			mm, err := r.Mailmap(r.CommitReference(commit.ID.String()))
 			if err != nil {
 				return err
 			}
-
-			diff, err := r.Diff(commit)
+			mm.MapCommits(commit)
+
+			opts := git.DiffOptions{Context: git.DefaultDiffContext}
+			var diff *git.Diff
+			var res *git.MergeResolution
+			switch {
+			case resolution:
+				res, err = r.MergeResolution(commit, opts)
+				if errors.Is(err, git.ErrNotMergeCommit) {
+					return exitErrorf(ExitUsage, "commit %s isn't a merge of two commits", commitSHA)
+				} else if err != nil {
+					return err
+				}
+				diff = res.Diff
+			case parent > 0:
+				if parent > commit.ParentsCount() {
+					return exitErrorf(ExitUsage, "commit %s has %d parent(s)", commitSHA, commit.ParentsCount())
+				}
+				opts.Parent = parent - 1
+				diff, err = r.DiffWithOptions(commit, opts)
+			default:
+				diff, err = r.Diff(commit)
+			}
 			if err != nil {
 				return err
 			}
+			patch := diff.Patch()
 
 			commonStyle := styles.DefaultStyles(renderer)
+			commonStyle.SetDiffColors(common.LoadDiffColors(ctx, be, sshutils.PublicKeyFromContext(ctx)))
 			style := commonStyle.Log
 
 			s := strings.Builder{}
@@ -64,7 +98,7 @@ func commitCommand(renderer *lipgloss.Renderer) *cobra.Command {
 			dateLine := "Date:   " + commit.Committer.When.UTC().Format(time.UnixDate)
 			msgLine := strings.ReplaceAll(commit.Message, "\r\n", "\n")
 			statsLine := renderStats(diff, commonStyle, color)
-			diffLine := renderDiff(patch, color)
+			diffLine := renderDiff(patch, commonStyle, color)
 
 			if patchOnly {
 				cmd.Println(
@@ -89,6 +123,10 @@ func commitCommand(renderer *lipgloss.Renderer) *cobra.Command {
 				))
 			}
 
+			if res != nil {
+				s.WriteString("\n" + renderResolution(res, commonStyle, color))
+			}
+
 			s.WriteString(fmt.Sprintf("\n%s\n%s",
 				statsLine,
 				diffLine,
@@ -104,11 +142,14 @@ func commitCommand(renderer *lipgloss.Renderer) *cobra.Command {
 
 	cmd.Flags().BoolVarP(&color, "color", "c", false, "Colorize output")
 	cmd.Flags().BoolVarP(&patchOnly, "patch", "p", false, "Output patch only")
+	cmd.Flags().IntVar(&parent, "parent", 0, "compare a merge commit to its Nth parent")
+	cmd.Flags().BoolVar(&resolution, "resolution", false, "compare a merge commit to the automatic merge of its parents")
+	cmd.MarkFlagsMutuallyExclusive("parent", "resolution")
 
 	return cmd
 }
 
-func renderDiff(patch string, color bool) string {
+func renderDiff(patch string, commonStyle *styles.Styles, color bool) string {
 	c := patch
 
 	if color {
@@ -120,7 +161,7 @@ func renderDiff(patch string, color bool) string {
 			Language: "diff",
 		}
 
-		err := diffChroma.Render(&pr, common.StyleRenderer())
+		err := diffChroma.Render(&pr, common.DiffStyleRenderer(commonStyle.DiffColors))
 
 		if err != nil {
 			s.WriteString(fmt.Sprintf("\n%s", err.Error()))
@@ -156,3 +197,23 @@ func renderStats(diff *git.Diff, commonStyle *styles.Styles, color bool) string
 
 	return c
 }
+
+// renderResolution renders the conflicts a merge resolved and the files edited
+// while merging.
+func renderResolution(res *git.MergeResolution, commonStyle *styles.Styles, color bool) string {
+	var s strings.Builder
+	if len(res.Files) == 0 {
+		s.WriteString("Clean merge, nothing changed on top of the automatic merge\n")
+	}
+	if len(res.Conflicts) > 0 {
+		s.WriteString("Resolved conflicts: " + strings.Join(res.Conflicts, ", ") + "\n")
+	}
+	if edits := res.Edits(); len(edits) > 0 {
+		line := "Edited while merging: " + strings.Join(edits, ", ")
+		if color {
+			line = commonStyle.Log.MergeEdit.Render(line)
+		}
+		s.WriteString(line + "\n")
+	}
+	return s.String()
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// refArg is the kind of reference a command argument expects.
+type refArg int
+
+const (
+	// revisionArg is any revision, i.e. a branch, a tag, or an abbreviated
+	// commit hash.
+	revisionArg refArg = iota
+	// branchArg is a branch name.
+	branchArg
+	// tagArg is a tag name.
+	tagArg
+)
+
+// recentCommitsLimit is the number of the latest commits whose abbreviated
+// hashes are completed.
+const recentCommitsLimit = 100
+
+// completeRepo returns a completion function for commands that take a
+// repository as their first argument, followed by the given kinds of
+// references.
+//
+// Completion runs without the command hooks, so the access checks are done
+// here: only repositories the user can read are completed.
+func completeRepo(refs ...refArg) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		switch {
+		case len(args) == 0:
+			return completeRepositories(cmd, toComplete), cobra.ShellCompDirectiveNoFileComp
+		case len(args) <= len(refs):
+			return completeRefs(cmd, args[0], refs[len(args)-1], toComplete), cobra.ShellCompDirectiveNoFileComp
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeRepositories returns the names of the repositories the user can
+// read that start with toComplete. Hidden repositories are left out like in
+// the repository list.
+func completeRepositories(cmd *cobra.Command, toComplete string) []string {
+	ctx := cmd.Context()
+	be := backend.FromContext(ctx)
+	user := proto.UserFromContext(ctx)
+	repos, err := be.Repositories(ctx)
+	if err != nil {
+		return nil
+	}
+
+	comps := make([]string, 0)
+	for _, r := range repos {
+		if r.IsHidden() || !strings.HasPrefix(r.Name(), toComplete) {
+			continue
+		}
+		if be.AccessLevelForUser(ctx, r.Name(), user) >= access.ReadOnlyAccess {
+			comps = append(comps, completion(r.Name(), r.Description()))
+		}
+	}
+	return comps
+}
+
+// completeRefs returns the references of the repository that start with
+// toComplete. Abbreviated hashes of the latest commits are only completed
+// for revisions once at least one hexadecimal digit is typed.
+func completeRefs(cmd *cobra.Command, repo string, kind refArg, toComplete string) []string {
+	ctx := cmd.Context()
+	be := backend.FromContext(ctx)
+	rn := utils.SanitizeRepo(repo)
+	if be.AccessLevelForUser(ctx, rn, proto.UserFromContext(ctx)) < access.ReadOnlyAccess {
+		return nil
+	}
+
+	rr, err := be.Repository(ctx, rn)
+	if err != nil {
+		return nil
+	}
+
+	r, err := rr.Open()
+	if err != nil {
+		return nil
+	}
+
+	comps := make([]string, 0)
+	if refs, err := r.References(); err == nil {
+		for _, ref := range refs {
+			var desc string
+			switch {
+			case ref.IsBranch() && kind != tagArg:
+				desc = "branch"
+			case ref.IsTag() && kind != branchArg:
+				desc = "tag"
+			default:
+				continue
+			}
+			if name := ref.Name().Short(); strings.HasPrefix(name, toComplete) {
+				comps = append(comps, completion(name, desc))
+			}
+		}
+	}
+
+	if kind == revisionArg && isHexPrefix(toComplete) {
+		if commits, err := r.RecentCommits(recentCommitsLimit); err == nil {
+			for _, c := range commits {
+				if strings.HasPrefix(c.Hash, strings.ToLower(toComplete)) {
+					comps = append(comps, completion(c.Hash, c.Subject))
+				}
+			}
+		}
+	}
+
+	return comps
+}
+
+// completion returns a completion with the first line of its description.
+func completion(value, desc string) string {
+	desc, _, _ = strings.Cut(strings.TrimSpace(desc), "\n")
+	if desc == "" {
+		return value
+	}
+	return value + "\t" + desc
+}
+
+// isHexPrefix returns true if s is a non-empty hexadecimal string.
+func isHexPrefix(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range strings.ToLower(s) {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// CompletionArgs returns the arguments of a completion request. SSH clients
+// join the arguments of the command with spaces, so an empty word to complete
+// only shows up as trailing whitespace in the raw command.
+func CompletionArgs(raw string, args []string) []string {
+	if len(args) == 0 || args[0] != cobra.ShellCompRequestCmd {
+		return args
+	}
+	if strings.TrimRightFunc(raw, unicode.IsSpace) != raw {
+		args = append(args, "")
+	}
+	return args
+}
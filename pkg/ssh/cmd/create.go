@@ -1,20 +1,21 @@
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/charmbracelet/soft-serve/pkg/backend"
 	"github.com/charmbracelet/soft-serve/pkg/config"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
 // createCommand is the command for creating a new repository.
 func createCommand() *cobra.Command {
 	var private bool
+	var public bool
 	var description string
 	var projectName string
 	var hidden bool
+	var template string
 
 	cmd := &cobra.Command{
 		Use:               "create REPOSITORY",
@@ -27,17 +28,39 @@ func createCommand() *cobra.Command {
 			be := backend.FromContext(ctx)
 			user := proto.UserFromContext(ctx)
 			name := args[0]
-			r, err := be.CreateRepository(ctx, name, user, proto.RepositoryOptions{
+			defaults, err := cfg.Repo.DefaultsFor(utils.SanitizeRepo(name))
+			if err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed("private") && !public {
+				private = defaults.Private()
+			}
+			if !cmd.Flags().Changed("description") {
+				description = defaults.Description
+			}
+			if !cmd.Flags().Changed("name") {
+				projectName = defaults.ProjectName
+			}
+			if !cmd.Flags().Changed("hidden") {
+				hidden = defaults.Hidden
+			}
+			opts := proto.RepositoryOptions{
 				Private:     private,
 				Description: description,
 				ProjectName: projectName,
 				Hidden:      hidden,
-			})
+			}
+			var r proto.Repository
+			if template != "" {
+				r, err = be.CreateRepositoryFromTemplate(ctx, name, template, user, opts)
+			} else {
+				r, err = be.CreateRepository(ctx, name, user, opts)
+			}
 			if err != nil {
 				return err
 			}
 
-			cloneurl := fmt.Sprintf("%s/%s.git", cfg.SSH.PublicURL, r.Name())
+			cloneurl := cfg.SSHCloneURL(r.Name())
 			cmd.PrintErrf("Created repository %s\n", r.Name())
 			cmd.Println(cloneurl)
 
@@ -46,9 +69,12 @@ func createCommand() *cobra.Command {
 	}
 
 	cmd.Flags().BoolVarP(&private, "private", "p", false, "make the repository private")
+	cmd.Flags().BoolVar(&public, "public", false, "make the repository public")
 	cmd.Flags().StringVarP(&description, "description", "d", "", "set the repository description")
 	cmd.Flags().StringVarP(&projectName, "name", "n", "", "set the project name")
 	cmd.Flags().BoolVarP(&hidden, "hidden", "H", false, "hide the repository from the UI")
+	cmd.Flags().StringVarP(&template, "template", "t", "", "make the initial commit from a template, see \"repo templates\"")
+	cmd.MarkFlagsMutuallyExclusive("private", "public")
 
 	return cmd
 }
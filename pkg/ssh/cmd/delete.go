@@ -6,20 +6,33 @@ import (
 )
 
 func deleteCommand() *cobra.Command {
+	var dryRun bool
 	cmd := &cobra.Command{
-		Use:               "delete REPOSITORY",
-		Aliases:           []string{"del", "remove", "rm"},
-		Short:             "Delete a repository",
+		Use:     "delete REPOSITORY",
+		Aliases: []string{"del", "remove", "rm"},
+		Short:   "Delete a repository",
+		Long: `Delete a repository, with its references, LFS objects, collaborators, and
+webhooks. Use --dry-run to list what would be deleted first.`,
 		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRepo(),
 		PersistentPreRunE: checkIfCollab,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			be := backend.FromContext(ctx)
 			name := args[0]
+			if dryRun {
+				changes, err := be.DeleteRepositoryDryRun(ctx, name)
+				if err != nil {
+					return err
+				}
+				return printChanges(cmd, changes)
+			}
 
 			return be.DeleteRepository(ctx, name)
 		},
 	}
 
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "List what would be deleted without deleting it")
+
 	return cmd
 }
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/spf13/cobra"
+)
+
+func deployCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "deploy",
+		Aliases: []string{"deploys"},
+		Short:   "Manage deploy scripts",
+		Long: `Manage the deploy scripts run after pushes to the branches of a repository.
+
+Deploy scripts are registered by the server admin in the config file. Pushes
+to a branch a script is bound to run the script once the references are
+updated, with a timeout and resource limits. Its output is shown to the
+pusher, and kept with its status in the activity of the repository.`,
+	}
+
+	cmd.AddCommand(
+		deployScriptsCommand(),
+		deployListCommand(),
+		deploySetCommand(),
+		deployDeleteCommand(),
+		deployLogCommand(),
+	)
+
+	return cmd
+}
+
+func deployScriptsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "scripts",
+		Short:             "List the deploy scripts of the server",
+		Args:              cobra.NoArgs,
+		PersistentPreRunE: checkIfUser,
+		Run: func(cmd *cobra.Command, _ []string) {
+			cfg := config.FromContext(cmd.Context())
+			for _, s := range cfg.Deploy.Scripts {
+				cmd.Println(s.Name)
+			}
+		},
+	}
+
+	return cmd
+}
+
+func deployListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "list REPOSITORY",
+		Short:             "List the deploy scripts bound to branches",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			deploys, err := be.Deploys(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			for _, dp := range deploys {
+				cmd.Printf("%s\t%s\n", dp.Branch, dp.Script)
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func deploySetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "set REPOSITORY BRANCH SCRIPT",
+		Short:             "Run a deploy script after pushes to a branch",
+		Long:              "Bind a deploy script of the server to a branch, replacing the script bound to it.",
+		Args:              cobra.ExactArgs(3),
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfRepoAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+
+			return be.SetDeploy(ctx, args[0], args[1], args[2])
+		},
+	}
+
+	return cmd
+}
+
+func deployDeleteCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "delete REPOSITORY BRANCH",
+		Aliases:           []string{"remove", "rm", "del"},
+		Short:             "Stop running a deploy script after pushes to a branch",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfRepoAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+
+			return be.DeleteDeploy(ctx, args[0], args[1])
+		},
+	}
+
+	return cmd
+}
+
+func deployLogCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "log REPOSITORY [BRANCH]",
+		Short:             "Show the output of the last deploy",
+		Long:              "Show the status and the output of the last deploy of a repository, or of one of its branches.",
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			user := proto.UserFromContext(ctx)
+			repo, err := be.Repository(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			var branch string
+			if len(args) > 1 {
+				branch = strings.TrimPrefix(args[1], git.RefsHeads)
+			}
+
+			events, err := be.PushEvents(ctx, user, repo, 0)
+			if err != nil {
+				return err
+			}
+
+			for _, ev := range events {
+				if ev.DeployScript == "" || (branch != "" && ev.Ref != git.RefsHeads+branch) {
+					continue
+				}
+				cmd.Printf("%s %s %s %s %s\n",
+					ev.CreatedAt.UTC().Format(time.RFC3339),
+					git.ReferenceName(ev.Ref).Short(),
+					ev.After[:7],
+					ev.DeployScript,
+					ev.DeployStatus,
+				)
+				cmd.Print(ev.DeployOutput)
+				return nil
+			}
+
+			return fmt.Errorf("no deploys found")
+		},
+	}
+
+	return cmd
+}
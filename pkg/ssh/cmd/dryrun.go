@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"github.com/caarlos0/tablewriter"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+// printChanges prints the changes of a dry run, and what they free in total.
+func printChanges(cmd *cobra.Command, changes []backend.Change) error {
+	var total int64
+	if err := tablewriter.Render(
+		cmd.OutOrStdout(),
+		changes,
+		[]string{"Action", "Target", "Size", "Detail"},
+		func(c backend.Change) ([]string, error) {
+			total += c.Size
+			var size string
+			if c.Size > 0 {
+				size = humanize.IBytes(uint64(c.Size))
+			}
+			return []string{c.Action, c.Target, size, c.Detail}, nil
+		},
+	); err != nil {
+		return err
+	}
+
+	cmd.PrintErrf("Dry run, nothing changed: %d changes, %s freed\n", len(changes), humanize.IBytes(uint64(total)))
+	return nil
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/avatar"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/webhook"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of the commands. They're part of the command line interface, so
+// scripts can tell the kinds of errors apart.
+const (
+	// ExitError is the exit code of an unexpected error.
+	ExitError = 1
+	// ExitUsage is the exit code of invalid arguments or flags.
+	ExitUsage = 2
+	// ExitPermissionDenied is the exit code of a command the user isn't
+	// allowed to run.
+	ExitPermissionDenied = 3
+	// ExitNotFound is the exit code of a missing repository, user, or other
+	// resource.
+	ExitNotFound = 4
+	// ExitExist is the exit code of a resource that already exists.
+	ExitExist = 5
+)
+
+// exitError is an error with the exit code of the command.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// exitErrorf returns an error with the given exit code.
+func exitErrorf(code int, format string, args ...interface{}) error {
+	return &exitError{code: code, err: fmt.Errorf(format, args...)}
+}
+
+// ExitCode returns the exit code of the command that returned err.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var eerr *exitError
+	if errors.As(err, &eerr) {
+		return eerr.code
+	}
+
+	var nerr *strconv.NumError
+	if errors.As(err, &nerr) {
+		return ExitUsage
+	}
+
+	switch {
+	case errors.Is(err, proto.ErrInvalidRepoName),
+		errors.Is(err, proto.ErrNotMirror),
+		errors.Is(err, access.ErrInvalidAccessLevel),
+		errors.Is(err, webhook.ErrInvalidEvent),
+		errors.Is(err, webhook.ErrInvalidContentType),
+		errors.Is(err, backend.ErrInvalidBranchPattern),
+		errors.Is(err, backend.ErrInvalidStatusContext),
+		errors.Is(err, backend.ErrCloneInstructionsTooLong),
+		errors.Is(err, backend.ErrAttestationsDisabled),
+		errors.Is(err, avatar.ErrInvalidImage),
+		errors.Is(err, avatar.ErrTooLarge):
+		return ExitUsage
+	case errors.Is(err, proto.ErrUnauthorized),
+		errors.Is(err, proto.ErrRepoArchived),
+		errors.Is(err, proto.ErrUntrustedCertificate),
+		errors.Is(err, proto.ErrInvalidCertificate),
+		errors.Is(err, backend.ErrRepoConfigKeyNotAllowed):
+		return ExitPermissionDenied
+	case errors.Is(err, proto.ErrRepoNotFound),
+		errors.Is(err, proto.ErrFileNotFound),
+		errors.Is(err, proto.ErrUserNotFound),
+		errors.Is(err, proto.ErrTokenNotFound),
+		errors.Is(err, proto.ErrCollaboratorNotFound),
+		errors.Is(err, proto.ErrTeamNotFound),
+		errors.Is(err, proto.ErrTeamMemberNotFound),
+		errors.Is(err, proto.ErrTeamRepoNotFound),
+		errors.Is(err, proto.ErrAliasNotFound),
+		errors.Is(err, proto.ErrPublicKeyNotFound),
+		errors.Is(err, proto.ErrRemoteNotFound),
+		errors.Is(err, backend.ErrDeployScriptNotFound),
+		errors.Is(err, backend.ErrTemplateNotFound),
+		errors.Is(err, backend.ErrNoAttestation),
+		errors.Is(err, git.ErrFileNotFound),
+		errors.Is(err, git.ErrDirectoryNotFound),
+		errors.Is(err, git.ErrReferenceNotExist),
+		errors.Is(err, git.ErrRevisionNotExist),
+		errors.Is(err, git.ErrObjectNotFound),
+		errors.Is(err, db.ErrRecordNotFound):
+		return ExitNotFound
+	case errors.Is(err, proto.ErrRepoExist),
+		errors.Is(err, proto.ErrCollaboratorExist),
+		errors.Is(err, proto.ErrTeamExist),
+		errors.Is(err, proto.ErrTeamMemberExist),
+		errors.Is(err, proto.ErrTeamRepoExist),
+		errors.Is(err, proto.ErrNameTaken),
+		errors.Is(err, backend.ErrAlreadyOwner),
+		errors.Is(err, db.ErrDuplicateKey):
+		return ExitExist
+	}
+
+	return ExitError
+}
+
+// SetUsageErrors makes the flag and argument validation errors of the command
+// and its subcommands exit with ExitUsage.
+func SetUsageErrors(c *cobra.Command) {
+	c.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return &exitError{code: ExitUsage, err: err}
+	})
+	setUsageArgs(c)
+}
+
+func setUsageArgs(c *cobra.Command) {
+	if args := c.Args; args != nil {
+		c.Args = func(cmd *cobra.Command, a []string) error {
+			if err := args(cmd, a); err != nil {
+				return &exitError{code: ExitUsage, err: err}
+			}
+			return nil
+		}
+	}
+	for _, sc := range c.Commands() {
+		setUsageArgs(sc)
+	}
+}
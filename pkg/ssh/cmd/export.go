@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// exportCommand returns a command that writes an archive of a repository.
+func exportCommand() *cobra.Command {
+	var format, prefix, lfsMode string
+	cmd := &cobra.Command{
+		Use:   "export REPOSITORY [TREE-ISH]",
+		Short: "Write an archive of a repository",
+		Long: `Write an archive of the files of a repository to stdout, of its default branch
+or of any tree-ish, e.g. a tag.
+
+Use --lfs to choose whether the files tracked with Git LFS are archived as
+"pointers", or replaced with the "objects" they point to. It defaults to
+"lfs.archive_objects". git archive --remote always archives the pointers.`,
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completeRepo(revisionArg),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			cfg := config.FromContext(ctx)
+			be := backend.FromContext(ctx)
+			if !slices.Contains(git.ArchiveFormats, format) {
+				return exitErrorf(ExitUsage, "invalid format %q: must be one of %s", format, strings.Join(git.ArchiveFormats, ", "))
+			}
+			objects := cfg.LFS.ArchiveObjects
+			switch lfsMode {
+			case "":
+			case "objects":
+				objects = true
+			case "pointers":
+				objects = false
+			default:
+				return exitErrorf(ExitUsage, "invalid lfs mode %q: must be objects or pointers", lfsMode)
+			}
+
+			treeish := "HEAD"
+			if len(args) > 1 {
+				treeish = args[1]
+			}
+			return be.ExportRepository(ctx, args[0], treeish, cmd.OutOrStdout(), git.ArchiveOptions{
+				Format: format,
+				Prefix: prefix,
+			}, objects)
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "tar", "archive format, one of "+strings.Join(git.ArchiveFormats, ", "))
+	cmd.Flags().StringVar(&prefix, "prefix", "", "prepend a prefix to the paths in the archive, e.g. \"icecream/\"")
+	cmd.Flags().StringVar(&lfsMode, "lfs", "", "archive LFS \"objects\" or \"pointers\"")
+
+	return streamOutput(cmd)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/spf13/cobra"
+)
+
+// forkCommand is the command for forking a repository.
+func forkCommand() *cobra.Command {
+	var private bool
+	var description string
+	var projectName string
+	var hidden bool
+
+	cmd := &cobra.Command{
+		Use:   "fork SOURCE REPOSITORY",
+		Short: "Fork a repository",
+		Long: `Fork a repository.
+
+The fork gets the branches and tags of the source repository, and shares its
+objects instead of copying them. Forks of private repositories are private.`,
+		Args: cobra.ExactArgs(2),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkIfReadable(cmd, args[:1]); err != nil {
+				return err
+			}
+			return checkIfCollab(cmd, args[1:])
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			cfg := config.FromContext(ctx)
+			be := backend.FromContext(ctx)
+			user := proto.UserFromContext(ctx)
+			r, err := be.ForkRepository(ctx, args[0], args[1], user, proto.RepositoryOptions{
+				Private:     private,
+				Description: description,
+				ProjectName: projectName,
+				Hidden:      hidden,
+			})
+			if err != nil {
+				return err
+			}
+
+			cloneurl := cfg.SSHCloneURL(r.Name())
+			cmd.PrintErrf("Forked repository %s to %s\n", args[0], r.Name())
+			cmd.Println(cloneurl)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&private, "private", "p", false, "make the fork private")
+	cmd.Flags().StringVarP(&description, "description", "d", "", "set the fork description, defaults to the source one")
+	cmd.Flags().StringVarP(&projectName, "name", "n", "", "set the project name")
+	cmd.Flags().BoolVarP(&hidden, "hidden", "H", false, "hide the fork from the UI")
+
+	return cmd
+}
+
+// forksCommand is the command for listing the forks of a repository.
+func forksCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "forks REPOSITORY",
+		Short:             "List the forks of a repository",
+		Long:              "List the forks of a repository, the ones you can read.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			user := proto.UserFromContext(ctx)
+			rn := args[0]
+			if _, err := be.Repository(ctx, rn); err != nil {
+				return err
+			}
+
+			forks, err := be.Forks(ctx, rn)
+			if err != nil {
+				return err
+			}
+			for _, fork := range forks {
+				if be.AccessLevelForUser(ctx, fork, user) >= access.ReadOnlyAccess {
+					cmd.Println(fork)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
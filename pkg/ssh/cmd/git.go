@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
 	"path/filepath"
 	"strings"
 	"time"
@@ -13,11 +16,14 @@ import (
 	"github.com/charmbracelet/soft-serve/pkg/git"
 	"github.com/charmbracelet/soft-serve/pkg/lfs"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sessions"
 	"github.com/charmbracelet/soft-serve/pkg/sshutils"
 	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/charmbracelet/ssh"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/spf13/cobra"
+	gossh "golang.org/x/crypto/ssh"
 )
 
 var (
@@ -109,7 +115,7 @@ func GitUploadPackCommand() *cobra.Command {
 		RunE:   gitRunE,
 	}
 
-	return cmd
+	return streamOutput(cmd)
 }
 
 // GitUploadArchiveCommand returns a cobra command for git-upload-archive.
@@ -122,7 +128,7 @@ func GitUploadArchiveCommand() *cobra.Command {
 		RunE:   gitRunE,
 	}
 
-	return cmd
+	return streamOutput(cmd)
 }
 
 // GitReceivePackCommand returns a cobra command for git-receive-pack.
@@ -135,7 +141,7 @@ func GitReceivePackCommand() *cobra.Command {
 		RunE:   gitRunE,
 	}
 
-	return cmd
+	return streamOutput(cmd)
 }
 
 // GitLFSAuthenticateCommand returns a cobra command for git-lfs-authenticate.
@@ -148,7 +154,7 @@ func GitLFSAuthenticateCommand() *cobra.Command {
 		RunE:   gitRunE,
 	}
 
-	return cmd
+	return streamOutput(cmd)
 }
 
 // GitLFSTransfer returns a cobra command for git-lfs-transfer.
@@ -161,7 +167,7 @@ func GitLFSTransfer() *cobra.Command {
 		RunE:   gitRunE,
 	}
 
-	return cmd
+	return streamOutput(cmd)
 }
 
 func gitRunE(cmd *cobra.Command, args []string) error {
@@ -226,6 +232,7 @@ func gitRunE(cmd *cobra.Command, args []string) error {
 		Stderr: stderr,
 		Env:    envs,
 		Dir:    repoPath,
+		Config: cfg.Repo.GitConfig(),
 	}
 
 	switch service {
@@ -237,16 +244,62 @@ func gitRunE(cmd *cobra.Command, args []string) error {
 		if accessLevel < access.ReadWriteAccess {
 			return git.ErrNotAuthed
 		}
+		if err := be.CheckFollower(name); err != nil {
+			return err
+		}
+		if err := be.CheckMaintenance(ctx, user); err != nil {
+			return err
+		}
+		if repo != nil && repo.IsArchived() {
+			return proto.ErrRepoArchived
+		}
 		if repo == nil {
-			if _, err := be.CreateRepository(ctx, name, user, proto.RepositoryOptions{Private: false}); err != nil {
+			defaults, err := cfg.Repo.DefaultsFor(name)
+			if err != nil {
+				return err
+			}
+			if _, err := be.CreateRepository(ctx, name, user, proto.RepositoryOptions{
+				Private:     defaults.Private(),
+				Description: defaults.Description,
+				ProjectName: defaults.ProjectName,
+				Hidden:      defaults.Hidden,
+			}); err != nil {
 				log.Errorf("failed to create repo: %s", err)
 				return err
 			}
 			createRepoCounter.WithLabelValues(name).Inc()
 		}
 
+		// The report of the push is shown in the interactive sessions of the
+		// pusher.
+		reporter := git.NewPushReporter(name)
+		scmd.Stdout = io.MultiWriter(stdout, reporter)
+		scmd.Stderr = io.MultiWriter(stderr, reporter.Stderr())
+		defer func() {
+			if r := reporter.Report(); !r.Empty() {
+				be.PublishPushReport(user, pk, r)
+			}
+		}()
+
+		done, err := startOperation(ctx, be, name, user, stderr)
+		if err != nil {
+			reporter.SetError(err)
+			return err
+		}
+		defer done()
+
+		// Housekeeping skips the repository until the push is done.
+		defer be.StartPush(name)()
+
+		release, err := be.AcquireRepository(ctx, name, true)
+		if err != nil {
+			return git.ErrSystemMalfunction
+		}
+		defer releaseRepository(logger, name, release)
+
 		if err := service.Handler(ctx, scmd); err != nil {
 			logger.Error("failed to handle git service", "service", service, "err", err, "repo", name)
+			reporter.SetError(git.ErrSystemMalfunction)
 			defer func() {
 				if repo == nil {
 					// If the repo was created, but the request failed, delete it.
@@ -280,6 +333,11 @@ func gitRunE(cmd *cobra.Command, args []string) error {
 			defer func() {
 				uploadArchiveSeconds.WithLabelValues(name).Add(time.Since(start).Seconds())
 			}()
+
+			// Archiving a large repository can take a while.
+			var cancel context.CancelFunc
+			ctx, cancel = cfg.Repo.OperationContext(ctx)
+			defer cancel()
 		default:
 			uploadPackCounter.WithLabelValues(name).Inc()
 			defer func() {
@@ -287,9 +345,28 @@ func gitRunE(cmd *cobra.Command, args []string) error {
 			}()
 		}
 
-		err := service.Handler(ctx, scmd)
+		done, err := startOperation(ctx, be, name, user, stderr)
+		if err != nil {
+			return err
+		}
+		defer done()
+
+		release, err := be.AcquireRepository(ctx, name, false)
+		if err != nil {
+			return git.ErrSystemMalfunction
+		}
+		defer releaseRepository(logger, name, release)
+
+		// Keep the connection alive while git prepares the pack of a large
+		// repository and sends nothing.
+		defer keepAlive(ctx, cfg.Repo.KeepAliveInterval())()
+
+		err = service.Handler(ctx, scmd)
 		if errors.Is(err, git.ErrInvalidRepo) {
 			return git.ErrInvalidRepo
+		} else if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			logger.Error("git service timed out", "service", service, "repo", name)
+			return git.ErrOperationTimeout
 		} else if err != nil {
 			logger.Error("failed to handle git service", "service", service, "err", err, "repo", name)
 			return git.ErrSystemMalfunction
@@ -297,6 +374,12 @@ func gitRunE(cmd *cobra.Command, args []string) error {
 
 		return nil
 	case git.LFSTransferService, git.LFSAuthenticateService:
+		// The token of git-lfs-authenticate carries the full access of the
+		// user over HTTP, keys with options use git-lfs-transfer instead.
+		if service == git.LFSAuthenticateService && !proto.KeyOptionsFromContext(ctx).IsZero() {
+			return fmt.Errorf("%w: keys with options can't authenticate over HTTP", proto.ErrUnauthorized)
+		}
+
 		operation := args[1]
 		switch operation {
 		case lfs.OperationDownload:
@@ -307,6 +390,15 @@ func gitRunE(cmd *cobra.Command, args []string) error {
 			if accessLevel < access.ReadWriteAccess {
 				return git.ErrNotAuthed
 			}
+			if err := be.CheckFollower(name); err != nil {
+				return err
+			}
+			if err := be.CheckMaintenance(ctx, user); err != nil {
+				return err
+			}
+			if repo != nil && repo.IsArchived() {
+				return proto.ErrRepoArchived
+			}
 		default:
 			return git.ErrInvalidRequest
 		}
@@ -343,3 +435,63 @@ func gitRunE(cmd *cobra.Command, args []string) error {
 
 	return errors.New("unsupported git service")
 }
+
+// startOperation starts a git operation under the concurrency limits. The
+// client is told when it has to wait for other operations to finish.
+func startOperation(ctx context.Context, be *backend.Backend, name string, user proto.User, stderr io.Writer) (func(), error) {
+	sess := sessions.SessionFromContext(ctx)
+	var activity string
+	done, err := be.StartOperation(ctx, name, user, func(ahead int, wait time.Duration) {
+		msg := "Server busy, waiting for other git operations to finish"
+		if wait > 0 {
+			msg += fmt.Sprintf(", about %s", max(wait, time.Second).Round(time.Second))
+		}
+		fmt.Fprintf(stderr, "%s (%d ahead)...\n", msg, ahead) // nolint: errcheck
+		if sess != nil {
+			activity = sess.Activity()
+			sess.SetActivity(activity + " (queued)")
+		}
+	})
+	if sess != nil && activity != "" {
+		sess.SetActivity(activity)
+	}
+	return done, err
+}
+
+// keepAlive sends keepalive requests to the SSH client every interval until
+// the returned function is called, so that NATs, proxies, and the idle
+// timeout of the server don't close the connection of a client that waits
+// for the server. It does nothing if interval is zero.
+func keepAlive(ctx context.Context, interval time.Duration) func() {
+	conn, ok := ctx.Value(ssh.ContextKeyConn).(gossh.Conn)
+	if !ok || interval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, _, err := conn.SendRequest("keepalive@openssh.com", true, nil); err != nil {
+					return
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// releaseRepository releases a repository acquired for a git service. The
+// response was already sent, a failure is only logged.
+func releaseRepository(logger *log.Logger, name string, release func() error) {
+	if err := release(); err != nil {
+		logger.Error("failed to release repository", "repo", name, "err", err)
+	}
+}
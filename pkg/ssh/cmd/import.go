@@ -4,8 +4,10 @@ import (
 	"errors"
 
 	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/task"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -26,10 +28,27 @@ func importCommand() *cobra.Command {
 		PersistentPreRunE: checkIfCollab,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
+			cfg := config.FromContext(ctx)
 			be := backend.FromContext(ctx)
 			user := proto.UserFromContext(ctx)
 			name := args[0]
 			remote := args[1]
+			defaults, err := cfg.Repo.DefaultsFor(utils.SanitizeRepo(name))
+			if err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed("private") {
+				private = defaults.Private()
+			}
+			if !cmd.Flags().Changed("description") {
+				description = defaults.Description
+			}
+			if !cmd.Flags().Changed("name") {
+				projectName = defaults.ProjectName
+			}
+			if !cmd.Flags().Changed("hidden") {
+				hidden = defaults.Hidden
+			}
 			if _, err := be.ImportRepository(ctx, name, user, remote, proto.RepositoryOptions{
 				Private:     private,
 				Description: description,
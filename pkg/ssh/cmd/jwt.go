@@ -17,6 +17,8 @@ func JWTCommand() *cobra.Command {
 		Use:   "jwt [repository1 repository2...]",
 		Short: "Generate a JSON Web Token",
 		Args:  cobra.MinimumNArgs(0),
+		// Tokens aren't restricted by the options of the key.
+		PersistentPreRunE: checkIfUnrestricted,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			cfg := config.FromContext(ctx)
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/spf13/cobra"
+)
+
+func landingTabCommand() *cobra.Command {
+	var reset bool
+	cmd := &cobra.Command{
+		Use:   "landing-tab REPOSITORY [TAB]",
+		Short: "Set or get the tab a repository opens on",
+		Long: `Set or get the tab a repository opens on in the terminal UI.
+
+TAB is one of ` + strings.Join(common.LandingTabs, ", ") + `. Repositories open on
+the readme by default. Use --reset to go back to the default.`,
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completeRepo(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			rn := strings.TrimSuffix(args[0], ".git")
+			if len(args) == 1 && !reset {
+				if err := checkIfReadable(cmd, args); err != nil {
+					return err
+				}
+
+				tab, err := be.LandingTab(ctx, rn)
+				if err != nil {
+					return err
+				}
+				if tab == "" {
+					tab = "default"
+				}
+
+				cmd.Println(tab)
+				return nil
+			}
+
+			if err := checkIfCollab(cmd, args); err != nil {
+				return err
+			}
+			if _, err := be.Repository(ctx, rn); err != nil {
+				return err
+			}
+
+			var tab string
+			if !reset {
+				var err error
+				tab, err = common.ParseLandingTab(args[1])
+				if err != nil {
+					return err
+				}
+			}
+
+			return be.SetLandingTab(ctx, rn, tab)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&reset, "reset", "r", false, "Open the repository on the default tab")
+
+	return cmd
+}
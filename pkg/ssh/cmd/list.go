@@ -1,21 +1,29 @@
 package cmd
 
 import (
+	"encoding/json"
+
 	"github.com/charmbracelet/soft-serve/pkg/access"
 	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/sshutils"
 	"github.com/spf13/cobra"
 )
 
 // listCommand returns a command that list file or directory at path.
 func listCommand() *cobra.Command {
-	var all bool
+	var all, asJSON bool
+	var opts outputOptions
 
 	listCmd := &cobra.Command{
 		Use:     "list",
 		Aliases: []string{"ls"},
 		Short:   "List repositories",
-		Args:    cobra.NoArgs,
+		Long: `List repositories.
+
+Use --json to print one JSON object per repository, with its name, project
+name, description, and visibility.`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			ctx := cmd.Context()
 			be := backend.FromContext(ctx)
@@ -24,11 +32,30 @@ func listCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			out := opts.newOutput(cmd)
 			for _, r := range repos {
+				if out.Done() {
+					break
+				}
 				if be.AccessLevelByPublicKey(ctx, r.Name(), pk) >= access.ReadOnlyAccess {
-					if !r.IsHidden() || all {
-						cmd.Println(r.Name())
+					if r.IsHidden() && !all {
+						continue
+					}
+					if !asJSON {
+						out.Println(r.Name())
+						continue
+					}
+					b, err := json.Marshal(proto.RepositoryListing{
+						Name:        r.Name(),
+						ProjectName: r.ProjectName(),
+						Description: r.Description(),
+						Private:     r.IsPrivate(),
+						Hidden:      r.IsHidden(),
+					})
+					if err != nil {
+						return err
 					}
+					out.Println(string(b))
 				}
 			}
 			return nil
@@ -36,6 +63,8 @@ func listCommand() *cobra.Command {
 	}
 
 	listCmd.Flags().BoolVarP(&all, "all", "a", false, "List all repositories")
+	listCmd.Flags().BoolVar(&asJSON, "json", false, "Print the repositories as JSON")
+	opts.addFlags(listCmd)
 
 	return listCmd
 }
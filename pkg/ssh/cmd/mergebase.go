@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/spf13/cobra"
+)
+
+// mergeBaseCommand returns a command that prints the best common ancestor of
+// two revisions.
+func mergeBaseCommand() *cobra.Command {
+	var all bool
+	cmd := &cobra.Command{
+		Use:   "merge-base REPOSITORY REV1 REV2",
+		Short: "Print the best common ancestor of two revisions",
+		Long: `Print the best common ancestor of two revisions.
+
+The revisions can be branches, tags, hashes, or any revision Git understands.
+Use --all to print all the best common ancestors, one per line.`,
+		Args:              cobra.ExactArgs(3),
+		ValidArgsFunction: completeRepo(revisionArg, revisionArg),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			rr, err := be.Repository(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			r, err := rr.Open()
+			if err != nil {
+				return err
+			}
+
+			ids, err := r.MergeBase(args[1], args[2], all)
+			switch {
+			case errors.Is(err, git.ErrNoMergeBase):
+				return fmt.Errorf("%s and %s have no common ancestor", args[1], args[2])
+			case err != nil:
+				return err
+			}
+
+			for _, id := range ids {
+				cmd.Println(id)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "Print all the best common ancestors")
+
+	return cmd
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/spf13/cobra"
+)
+
+func adminExportMetaCommand() *cobra.Command {
+	var repos []string
+	var all bool
+	cmd := &cobra.Command{
+		Use:   "export-meta",
+		Short: "Export the metadata of repositories as JSON",
+		Long: `Export the metadata of repositories as JSON, for backups and migrations to
+another server with "admin import-meta". The metadata is what Soft Serve keeps
+outside of the Git repositories: the project name, description, visibility,
+owner, collaborators, and webhooks, secrets included. Users are exported with
+the fingerprints of their public keys.
+
+Use --repo for the repositories to export, or --all to export all of them.`,
+		Args: cobra.NoArgs,
+		PreRunE: func(*cobra.Command, []string) error {
+			if all == (len(repos) > 0) {
+				return exitErrorf(ExitUsage, "either --repo or --all is required")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			names := repos
+			if all {
+				rs, err := be.Repositories(ctx)
+				if err != nil {
+					return err
+				}
+				names = make([]string, 0, len(rs))
+				for _, r := range rs {
+					names = append(names, r.Name())
+				}
+			}
+
+			meta := backend.Meta{
+				Version: backend.MetaVersion,
+				Repos:   make([]backend.RepoMeta, 0, len(names)),
+			}
+			for _, rn := range names {
+				m, err := be.ExportRepoMeta(ctx, rn)
+				if err != nil {
+					return fmt.Errorf("%s: %w", rn, err)
+				}
+				meta.Repos = append(meta.Repos, m)
+			}
+
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(meta)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&repos, "repo", "r", nil, "Export the metadata of the repository, can be repeated")
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "Export the metadata of all repositories")
+
+	return cmd
+}
+
+func adminImportMetaCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-meta",
+		Short: "Import the metadata of repositories",
+		Long: `Import the metadata of repositories exported with "admin export-meta" from
+the standard input. The repositories must exist already, push or import them
+first. Users are matched by the fingerprints of their public keys, and by
+username when they have no keys.
+
+Settings a repository doesn't have yet are set, while the ones it has with a
+different value are reported as conflicts and kept. The command fails when
+there are conflicts, after importing everything else.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+
+			var meta backend.Meta
+			if err := json.NewDecoder(cmd.InOrStdin()).Decode(&meta); err != nil {
+				return fmt.Errorf("invalid metadata: %w", err)
+			}
+
+			conflicts, err := be.ImportMeta(ctx, meta)
+			for _, c := range conflicts {
+				cmd.PrintErrf("Conflict: %s\n", c)
+			}
+			if err != nil {
+				return err
+			}
+			if len(conflicts) > 0 {
+				return fmt.Errorf("%d settings not imported because of conflicts", len(conflicts))
+			}
+
+			cmd.PrintErrf("Imported the metadata of %d repositories\n", len(meta.Repos))
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -1,7 +1,11 @@
 package cmd
 
 import (
+	"errors"
+
 	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/task"
+	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 )
 
@@ -10,6 +14,7 @@ func mirrorCommand() *cobra.Command {
 		Use:               "is-mirror REPOSITORY",
 		Short:             "Whether a repository is a mirror",
 		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRepo(),
 		PersistentPreRunE: checkIfReadable,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
@@ -28,3 +33,60 @@ func mirrorCommand() *cobra.Command {
 
 	return cmd
 }
+
+func syncCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync REPOSITORY [REMOTE]",
+		Short: "Sync a mirror with its remotes now",
+		Long: `Sync a mirror with its remotes now, or only with REMOTE, and show the progress
+of the fetches. A failed sync leaves the mirror as it was.`,
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfCollab,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			rn := args[0]
+			var remote string
+			if len(args) > 1 {
+				remote = args[1]
+			}
+
+			if err := be.SyncMirror(ctx, rn, remote, cmd.ErrOrStderr()); err != nil {
+				if errors.Is(err, task.ErrAlreadyStarted) {
+					return errors.New("sync already in progress")
+				}
+
+				return err
+			}
+
+			cmd.Println("Synced", rn)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// printMirror prints the remotes of a mirror and the state of its syncs.
+func printMirror(cmd *cobra.Command, be *backend.Backend, name string) {
+	ctx := cmd.Context()
+	if remotes, err := be.MirrorRemotes(ctx, name); err == nil && len(remotes) > 0 {
+		cmd.Println("Remotes:")
+		for _, r := range remotes {
+			cmd.Println("  -", r.Name, r.URL)
+		}
+	}
+	sync, err := be.MirrorSync(ctx, name)
+	if err != nil {
+		return
+	}
+	if sync.SyncedAt.Valid {
+		cmd.Println("Last Sync:", humanize.Time(sync.SyncedAt.Time))
+	} else {
+		cmd.Println("Last Sync: never")
+	}
+	if sync.Error != "" {
+		cmd.Println("Sync Error:", sync.Error)
+	}
+}
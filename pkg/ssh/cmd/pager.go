@@ -0,0 +1,380 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/ui/components/code"
+	"github.com/charmbracelet/ssh"
+	bm "github.com/charmbracelet/wish/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/spf13/cobra"
+)
+
+// PagerPreference is the name of the preference that holds when the output
+// of the commands of interactive sessions is paged, overriding ssh.pager.
+const PagerPreference = "ssh.pager"
+
+// streamAnnotation is the annotation of the commands whose output is never
+// paged, e.g. because it doesn't end or isn't text.
+const streamAnnotation = "soft-serve.stream"
+
+// streamOutput marks the output of the command as streamed, it's written as
+// it goes even in interactive sessions.
+func streamOutput(cmd *cobra.Command) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[streamAnnotation] = "true"
+	return cmd
+}
+
+// IsStreamed returns true if the output of the command is never paged.
+func IsStreamed(cmd *cobra.Command) bool {
+	return cmd.Annotations[streamAnnotation] == "true"
+}
+
+// outputOptions are the flags of commands that can produce long output.
+type outputOptions struct {
+	limit   int
+	offset  int
+	noPager bool
+}
+
+// addFlags adds the output flags to the given command.
+func (o *outputOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&o.limit, "limit", 0, "maximum number of lines to print, 0 means no limit")
+	cmd.Flags().IntVar(&o.offset, "offset", 0, "number of lines to skip")
+	cmd.Flags().BoolVar(&o.noPager, "no-pager", false, "don't page the output of interactive sessions")
+}
+
+// output writes the output of a command line by line while applying the
+// --limit and --offset flags.
+type output struct {
+	cmd   *cobra.Command
+	opts  *outputOptions
+	lines int
+}
+
+// newOutput returns a new output for the given command. The output isn't
+// paged with --no-pager.
+func (o *outputOptions) newOutput(cmd *cobra.Command) *output {
+	if p := pagerFromContext(cmd.Context()); p != nil && o.noPager {
+		p.Stream() // nolint: errcheck
+	}
+	return &output{
+		cmd:  cmd,
+		opts: o,
+	}
+}
+
+// Done returns true when no more lines will be written because the limit
+// has been reached. Use it to stop producing output early.
+func (o *output) Done() bool {
+	return o.opts.limit > 0 && o.lines >= o.opts.offset+o.opts.limit
+}
+
+// Println writes a line.
+func (o *output) Println(s string) {
+	if o.Done() {
+		return
+	}
+	o.lines++
+	if o.lines <= o.opts.offset {
+		return
+	}
+	o.cmd.Println(s)
+}
+
+// Printf formats and writes a line.
+func (o *output) Printf(format string, args ...interface{}) {
+	o.Println(fmt.Sprintf(format, args...))
+}
+
+type pagerContextKey struct{}
+
+// WithPager returns a context of commands whose output goes to the pager.
+func WithPager(ctx context.Context, p *Pager) context.Context {
+	return context.WithValue(ctx, pagerContextKey{}, p)
+}
+
+// pagerFromContext returns the pager of the output of the command, if any.
+func pagerFromContext(ctx context.Context) *Pager {
+	if p, ok := ctx.Value(pagerContextKey{}).(*Pager); ok {
+		return p
+	}
+	return nil
+}
+
+// Pager buffers the output of a command of an interactive session to show it
+// in a pager once the command is done, depending on its mode, see
+// config.PagerAuto. Output that isn't paged is written as is.
+type Pager struct {
+	s      ssh.Session
+	mode   string
+	out    io.Writer
+	buf    bytes.Buffer
+	stream bool
+}
+
+// NewPager returns a pager of the output of the commands of the interactive
+// session s that writes to out.
+func NewPager(s ssh.Session, mode string, out io.Writer) *Pager {
+	return &Pager{
+		s:      s,
+		mode:   mode,
+		out:    out,
+		stream: mode == config.PagerNever,
+	}
+}
+
+// Write implements io.Writer.
+func (p *Pager) Write(b []byte) (int, error) {
+	if p.stream {
+		return p.out.Write(b)
+	}
+	return p.buf.Write(b)
+}
+
+// Stream writes the buffered output, and the rest of the output as it goes.
+func (p *Pager) Stream() error {
+	p.stream = true
+	return p.flushRaw()
+}
+
+// flushRaw writes the buffered output as is.
+func (p *Pager) flushRaw() error {
+	if p.buf.Len() == 0 {
+		return nil
+	}
+	_, err := p.out.Write(p.buf.Bytes())
+	p.buf.Reset()
+	return err
+}
+
+// Flush shows the buffered output in a pager, or writes it as is when it fits
+// the terminal in auto mode.
+func (p *Pager) Flush() error {
+	if p.stream || p.buf.Len() == 0 {
+		return nil
+	}
+	pty, winch, ok := p.s.Pty()
+	text := strings.TrimSuffix(ansi.Strip(p.buf.String()), "\n")
+	if !ok || (p.mode != config.PagerAlways && strings.Count(text, "\n") < pty.Window.Height-1) {
+		return p.flushRaw()
+	}
+	p.buf.Reset()
+
+	ctx := p.s.Context()
+	c := common.NewCommon(ctx, bm.MakeRenderer(p.s), pty.Window.Width, pty.Window.Height)
+	m := newPagerModel(c, text)
+	opts := append(bm.MakeOptions(p.s),
+		tea.WithAltScreen(),
+		tea.WithContext(ctx),
+	)
+	prog := tea.NewProgram(m, opts...)
+	go func() {
+		for w := range winch {
+			prog.Send(tea.WindowSizeMsg{Width: w.Width, Height: w.Height})
+		}
+	}()
+
+	_, err := prog.Run()
+	return err
+}
+
+var (
+	pagerQuit = key.NewBinding(
+		key.WithKeys("q", "esc", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	)
+	pagerSearch = key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "search"),
+	)
+	pagerNext = key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n/N", "next/prev match"),
+	)
+	pagerPrev = key.NewBinding(
+		key.WithKeys("N"),
+	)
+)
+
+// pagerModel is a model that pages command output using the code viewer. The
+// last line shows the search or the keys.
+type pagerModel struct {
+	common common.Common
+	code   *code.Code
+
+	// searching is true while the search is typed in input. query is the
+	// last search.
+	searching bool
+	input     textinput.Model
+	query     string
+	status    string
+}
+
+// newPagerModel returns a pager of the given text.
+func newPagerModel(c common.Common, text string) *pagerModel {
+	ti := textinput.New()
+	ti.Prompt = "/"
+	m := &pagerModel{
+		common: c,
+		code:   code.New(c, text, ".txt"),
+		input:  ti,
+	}
+	m.setSize(c.Width, c.Height)
+	return m
+}
+
+// setSize sets the size of the pager, the viewer takes all the lines but the
+// status line.
+func (p *pagerModel) setSize(width, height int) {
+	p.common.SetSize(width, height)
+	p.code.SetSize(width, max(1, height-1))
+	p.input.Width = width - lipgloss.Width(p.input.Prompt) - 1
+}
+
+// Init implements tea.Model.
+func (p *pagerModel) Init() tea.Cmd {
+	return p.code.Init()
+}
+
+// Update implements tea.Model.
+func (p *pagerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.setSize(msg.Width, msg.Height)
+	case tea.KeyMsg:
+		if p.searching {
+			switch msg.Type {
+			case tea.KeyEnter:
+				p.searching = false
+				p.input.Blur()
+				p.query = p.input.Value()
+				p.find(1, true)
+				return p, nil
+			case tea.KeyEsc, tea.KeyCtrlC:
+				p.searching = false
+				p.input.Blur()
+				return p, nil
+			}
+			var cmd tea.Cmd
+			p.input, cmd = p.input.Update(msg)
+			return p, cmd
+		}
+		switch {
+		case key.Matches(msg, pagerQuit):
+			return p, tea.Quit
+		case key.Matches(msg, pagerSearch):
+			p.searching = true
+			p.status = ""
+			p.input.SetValue("")
+			return p, p.input.Focus()
+		case key.Matches(msg, pagerNext):
+			p.find(1, false)
+			return p, nil
+		case key.Matches(msg, pagerPrev):
+			p.find(-1, false)
+			return p, nil
+		}
+	}
+	m, cmd := p.code.Update(msg)
+	p.code = m.(*code.Code)
+	return p, cmd
+}
+
+// find selects the next line, or the previous one when dir is negative, that
+// contains the query regardless of case. The search starts at the top of the
+// view when from is true, after the selected line otherwise, and wraps
+// around.
+func (p *pagerModel) find(dir int, from bool) {
+	if p.query == "" {
+		return
+	}
+	q := strings.ToLower(p.query)
+	match := func(l string) bool {
+		return strings.Contains(strings.ToLower(l), q)
+	}
+	matches := make([]int, 0)
+	for i := p.code.FindLine(0, match); i >= 0; i = p.code.FindLine(i+1, match) {
+		matches = append(matches, i)
+	}
+	if len(matches) == 0 {
+		p.status = fmt.Sprintf("No match for %q", p.query)
+		return
+	}
+
+	cur := p.code.YOffset
+	if start, _, ok := p.code.Selection(); ok && !from {
+		cur = start
+	}
+	n := -1
+	switch {
+	case dir > 0 && from:
+		for i, l := range matches {
+			if l >= cur {
+				n = i
+				break
+			}
+		}
+		if n < 0 {
+			n = 0
+		}
+	case dir > 0:
+		n = 0
+		for i, l := range matches {
+			if l > cur {
+				n = i
+				break
+			}
+		}
+	default:
+		n = len(matches) - 1
+		for i := len(matches) - 1; i >= 0; i-- {
+			if matches[i] < cur {
+				n = i
+				break
+			}
+		}
+	}
+
+	l := matches[n]
+	p.code.Select(l, l)
+	if l < p.code.YOffset || l >= p.code.YOffset+p.code.Height {
+		p.code.SetYOffset(max(0, l-p.code.Height/2))
+	}
+	p.status = fmt.Sprintf("%q %d/%d", p.query, n+1, len(matches))
+}
+
+// View implements tea.Model.
+func (p *pagerModel) View() string {
+	var status string
+	switch {
+	case p.searching:
+		status = p.input.View()
+	case p.status != "":
+		status = p.status
+	default:
+		status = p.common.Styles.HelpKey.Render("q") + " " +
+			p.common.Styles.HelpValue.Render("quit") + "  " +
+			p.common.Styles.HelpKey.Render("/") + " " +
+			p.common.Styles.HelpValue.Render("search") + "  " +
+			p.common.Styles.HelpKey.Render("n/N") + " " +
+			p.common.Styles.HelpValue.Render("next/prev match")
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		p.code.View(),
+		common.TruncateString(status, p.common.Width),
+	)
+}
@@ -0,0 +1,416 @@
+package cmd
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/ui/styles"
+	"github.com/spf13/cobra"
+)
+
+// InteractivePreference is the name of the preference that holds what the
+// interactive sessions of the public key run, overriding ssh.interactive.
+const InteractivePreference = "ssh.interactive"
+
+// PrefsCommand returns a command that manages the preferences of the public
+// key.
+func PrefsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prefs",
+		Short: "Manage your preferences",
+	}
+
+	var reset bool
+	logColumnsCmd := &cobra.Command{
+		Use:   "log-columns [COLUMNS...]",
+		Short: "Set or get the columns of the log",
+		Long: `Set or get the columns of the log in the terminal UI.
+
+COLUMNS is a comma or space separated list of hash, author, date, age, and
+subject in the order they're shown. Every column can have a width after a colon, for
+example "hash:10,author:12,age,subject". The subject takes the rest of the
+line unless it has a width. Use --reset to go back to the default log.`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			pk := sshutils.PublicKeyFromContext(ctx)
+
+			switch {
+			case reset:
+				return be.DeletePreference(ctx, pk, common.LogColumnsPreference)
+			case len(args) == 0:
+				spec, err := be.Preference(ctx, pk, common.LogColumnsPreference)
+				if err != nil {
+					return err
+				}
+				if spec == "" {
+					spec = "default"
+				}
+				cmd.Println(spec)
+				return nil
+			}
+
+			cols, err := common.ParseLogColumns(strings.Join(args, ","))
+			if err != nil {
+				return &exitError{code: ExitUsage, err: err}
+			}
+
+			return be.SetPreference(ctx, pk, common.LogColumnsPreference, common.FormatLogColumns(cols))
+		},
+	}
+
+	logColumnsCmd.Flags().BoolVarP(&reset, "reset", "r", false, "Use the default log columns")
+
+	var resetFilter bool
+	repoFilterCmd := &cobra.Command{
+		Use:   "repo-filter [FILTER...]",
+		Short: "Set or get the default filter of the repository list",
+		Long: `Set or get the filter applied to the repository list of the terminal UI
+when you connect. Press F in the list to show every repository.
+
+FILTER is a comma or space separated list of name, visibility, and archived
+fields, for example "name:team-a/*,visibility:private". The name is a glob
+repository names must match, the visibility is public or private, and archived
+is true to only list archived repositories or false to leave them out. Use
+--reset to show every repository by default.`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			pk := sshutils.PublicKeyFromContext(ctx)
+
+			switch {
+			case resetFilter:
+				return be.DeletePreference(ctx, pk, common.RepoFilterPreference)
+			case len(args) == 0:
+				spec, err := be.Preference(ctx, pk, common.RepoFilterPreference)
+				if err != nil {
+					return err
+				}
+				if spec == "" {
+					spec = "none"
+				}
+				cmd.Println(spec)
+				return nil
+			}
+
+			f, err := common.ParseRepoFilter(strings.Join(args, ","))
+			if err != nil {
+				return &exitError{code: ExitUsage, err: err}
+			}
+
+			return be.SetPreference(ctx, pk, common.RepoFilterPreference, f.String())
+		},
+	}
+
+	repoFilterCmd.Flags().BoolVarP(&resetFilter, "reset", "r", false, "Show every repository by default")
+
+	bellCmd := &cobra.Command{
+		Use:   "bell [true|false]",
+		Short: "Set or get whether notifications ring the terminal bell",
+		Long: `Set or get whether notifications ring the terminal bell. Press W on a
+repository in the terminal UI to get notified of its pushes.`,
+		Args: cobra.RangeArgs(0, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			pk := sshutils.PublicKeyFromContext(ctx)
+
+			if len(args) == 0 {
+				v, err := be.Preference(ctx, pk, common.BellPreference)
+				if err != nil {
+					return err
+				}
+				cmd.Println(v != "false")
+				return nil
+			}
+
+			v, err := strconv.ParseBool(args[0])
+			if err != nil {
+				return exitErrorf(ExitUsage, "invalid value %q: must be true or false", args[0])
+			}
+			if v {
+				return be.DeletePreference(ctx, pk, common.BellPreference)
+			}
+			return be.SetPreference(ctx, pk, common.BellPreference, "false")
+		},
+	}
+
+	pushPanelCmd := &cobra.Command{
+		Use:   "push-panel [true|false]",
+		Short: "Set or get whether the terminal UI shows the report of your pushes",
+		Long: `Set or get whether the terminal UI shows the report of your pushes. While
+you're connected, the updated refs, the output of the hooks, and the rejections
+of the pushes you make over SSH or HTTP show up in a panel for a few seconds.`,
+		Args: cobra.RangeArgs(0, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			pk := sshutils.PublicKeyFromContext(ctx)
+
+			if len(args) == 0 {
+				v, err := be.Preference(ctx, pk, common.PushPanelPreference)
+				if err != nil {
+					return err
+				}
+				cmd.Println(v != "false")
+				return nil
+			}
+
+			v, err := strconv.ParseBool(args[0])
+			if err != nil {
+				return exitErrorf(ExitUsage, "invalid value %q: must be true or false", args[0])
+			}
+			if v {
+				return be.DeletePreference(ctx, pk, common.PushPanelPreference)
+			}
+			return be.SetPreference(ctx, pk, common.PushPanelPreference, "false")
+		},
+	}
+
+	logCommitterCmd := &cobra.Command{
+		Use:   "log-committer [true|false]",
+		Short: "Set or get whether the log shows committers instead of authors",
+		Long: `Set or get whether the log of the terminal UI shows the names and dates of
+the committers of the commits instead of their authors. Press a in the log to
+switch between them.`,
+		Args: cobra.RangeArgs(0, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			pk := sshutils.PublicKeyFromContext(ctx)
+
+			if len(args) == 0 {
+				v, err := be.Preference(ctx, pk, common.LogCommitterPreference)
+				if err != nil {
+					return err
+				}
+				cmd.Println(v == "true")
+				return nil
+			}
+
+			v, err := strconv.ParseBool(args[0])
+			if err != nil {
+				return exitErrorf(ExitUsage, "invalid value %q: must be true or false", args[0])
+			}
+			if !v {
+				return be.DeletePreference(ctx, pk, common.LogCommitterPreference)
+			}
+			return be.SetPreference(ctx, pk, common.LogCommitterPreference, "true")
+		},
+	}
+
+	var resetInteractive bool
+	interactiveCmd := &cobra.Command{
+		Use:   "interactive [tui|shell]",
+		Short: "Set or get what interactive sessions run",
+		Long: `Set or get what interactive sessions without a command run, the terminal
+UI or a command shell. Run tui in the shell to open the UI, and exit to quit.
+Use --reset to go back to the server default.`,
+		Args: cobra.RangeArgs(0, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			pk := sshutils.PublicKeyFromContext(ctx)
+
+			switch {
+			case resetInteractive:
+				return be.DeletePreference(ctx, pk, InteractivePreference)
+			case len(args) == 0:
+				v, err := be.Preference(ctx, pk, InteractivePreference)
+				if err != nil {
+					return err
+				}
+				if v == "" {
+					v = config.FromContext(ctx).SSH.Interactive
+				}
+				cmd.Println(v)
+				return nil
+			}
+
+			switch v := strings.ToLower(args[0]); v {
+			case config.InteractiveTUI, config.InteractiveShell:
+				return be.SetPreference(ctx, pk, InteractivePreference, v)
+			}
+			return exitErrorf(ExitUsage, "invalid value %q: must be %s or %s", args[0], config.InteractiveTUI, config.InteractiveShell)
+		},
+	}
+
+	interactiveCmd.Flags().BoolVarP(&resetInteractive, "reset", "r", false, "Use the server default")
+
+	var resetPager bool
+	pagerCmd := &cobra.Command{
+		Use:   "pager [auto|always|never]",
+		Short: "Set or get when the output of commands is paged",
+		Long: `Set or get when the output of the commands of interactive sessions is shown
+in a pager: auto when it doesn't fit the terminal, always, or never. Sessions
+without a terminal always get the output as is. Use --reset to go back to the
+server default.`,
+		Args: cobra.RangeArgs(0, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			pk := sshutils.PublicKeyFromContext(ctx)
+
+			switch {
+			case resetPager:
+				return be.DeletePreference(ctx, pk, PagerPreference)
+			case len(args) == 0:
+				v, err := be.Preference(ctx, pk, PagerPreference)
+				if err != nil {
+					return err
+				}
+				if v == "" {
+					v = config.FromContext(ctx).SSH.Pager
+				}
+				cmd.Println(v)
+				return nil
+			}
+
+			switch v := strings.ToLower(args[0]); v {
+			case config.PagerAuto, config.PagerAlways, config.PagerNever:
+				return be.SetPreference(ctx, pk, PagerPreference, v)
+			}
+			return exitErrorf(ExitUsage, "invalid value %q: must be %s, %s, or %s",
+				args[0], config.PagerAuto, config.PagerAlways, config.PagerNever)
+		},
+	}
+
+	pagerCmd.Flags().BoolVarP(&resetPager, "reset", "r", false, "Use the server default")
+
+	var resetIcons bool
+	fileIconsCmd := &cobra.Command{
+		Use:   "file-icons [none|unicode|nerd]",
+		Short: "Set or get the icons of the file list",
+		Long: `Set or get the icons shown before the names of files in the terminal UI:
+none, unicode for symbols every terminal shows, or nerd for the icons of Nerd
+Fonts. Use --reset to go back to the server default.`,
+		Args: cobra.RangeArgs(0, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			pk := sshutils.PublicKeyFromContext(ctx)
+
+			switch {
+			case resetIcons:
+				return be.DeletePreference(ctx, pk, common.FileIconsPreference)
+			case len(args) == 0:
+				v, err := be.Preference(ctx, pk, common.FileIconsPreference)
+				if err != nil {
+					return err
+				}
+				if v == "" {
+					v = config.FromContext(ctx).UI.FileIcons
+				}
+				cmd.Println(v)
+				return nil
+			}
+
+			switch v := strings.ToLower(args[0]); v {
+			case config.FileIconsNone, config.FileIconsUnicode, config.FileIconsNerd:
+				return be.SetPreference(ctx, pk, common.FileIconsPreference, v)
+			}
+			return exitErrorf(ExitUsage, "invalid value %q: must be %s, %s, or %s",
+				args[0], config.FileIconsNone, config.FileIconsUnicode, config.FileIconsNerd)
+		},
+	}
+
+	fileIconsCmd.Flags().BoolVarP(&resetIcons, "reset", "r", false, "Use the server default")
+
+	var resetDiffColors bool
+	diffColorsCmd := &cobra.Command{
+		Use:   "diff-colors [classic|colorblind|monochrome]",
+		Short: "Set or get the colors of diffs",
+		Long: `Set or get the color scheme of the added and deleted lines of diffs and of
+their stats, in the terminal UI and in "repo commit --color": classic red and
+green, colorblind blue and orange, or monochrome bold and italic. Press C in
+the diff view to switch schemes. Use --reset to go back to classic.`,
+		Args: cobra.RangeArgs(0, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			pk := sshutils.PublicKeyFromContext(ctx)
+
+			switch {
+			case resetDiffColors:
+				return be.DeletePreference(ctx, pk, common.DiffColorsPreference)
+			case len(args) == 0:
+				cmd.Println(common.LoadDiffColors(ctx, be, pk))
+				return nil
+			}
+
+			c, ok := styles.ParseDiffColors(args[0])
+			if !ok {
+				return exitErrorf(ExitUsage, "invalid value %q: must be %s, %s, or %s",
+					args[0], styles.DiffColorsClassic, styles.DiffColorsColorblind, styles.DiffColorsMonochrome)
+			}
+			return be.SetPreference(ctx, pk, common.DiffColorsPreference, string(c))
+		},
+	}
+
+	diffColorsCmd.Flags().BoolVarP(&resetDiffColors, "reset", "r", false, "Use the classic colors")
+
+	var resetRef bool
+	repoRefCmd := &cobra.Command{
+		Use:   "repo-ref REPOSITORY [REF]",
+		Short: "Set or get the ref a repository opens on",
+		Long: `Set or get the ref a repository opens on in the terminal UI, instead of its
+HEAD. REF is a full reference name, or the name of a branch or tag. Press P on
+a branch or tag in the UI to pin it. If the ref is deleted, the repository
+opens on HEAD with a notice. Use --reset to open the repository on HEAD.`,
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completeRepo(revisionArg),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			pk := sshutils.PublicKeyFromContext(ctx)
+			rr, err := be.Repository(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			pref := common.PinnedRefPreferenceName(rr.Name())
+
+			switch {
+			case resetRef:
+				return be.DeletePreference(ctx, pk, pref)
+			case len(args) == 1:
+				v, err := be.Preference(ctx, pk, pref)
+				if err != nil {
+					return err
+				}
+				if v == "" {
+					v = "HEAD"
+				}
+				cmd.Println(v)
+				return nil
+			}
+
+			r, err := rr.Open()
+			if err != nil {
+				return err
+			}
+			ref, err := r.ReferenceFullName(args[1])
+			if errors.Is(err, git.ErrReferenceNotExist) {
+				return exitErrorf(ExitUsage, "reference %q not found", args[1])
+			} else if err != nil {
+				return err
+			}
+
+			return be.SetPreference(ctx, pk, pref, ref)
+		},
+	}
+
+	repoRefCmd.Flags().BoolVarP(&resetRef, "reset", "r", false, "Open the repository on HEAD")
+
+	cmd.AddCommand(logColumnsCmd, logCommitterCmd, repoFilterCmd, repoRefCmd, fileIconsCmd, diffColorsCmd, bellCmd, pushPanelCmd, interactiveCmd, pagerCmd)
+
+	return cmd
+}
@@ -9,10 +9,11 @@ import (
 
 func projectName() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "project-name REPOSITORY [NAME]",
-		Aliases: []string{"project"},
-		Short:   "Set or get the project name for a repository",
-		Args:    cobra.MinimumNArgs(1),
+		Use:               "project-name REPOSITORY [NAME]",
+		Aliases:           []string{"project"},
+		Short:             "Set or get the project name for a repository",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeRepo(),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			be := backend.FromContext(ctx)
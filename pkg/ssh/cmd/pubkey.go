@@ -4,22 +4,29 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/sshutils"
 	"github.com/spf13/cobra"
+	gossh "golang.org/x/crypto/ssh"
 )
 
 // PubkeyCommand returns a command that manages user public keys.
 func PubkeyCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "pubkey",
-		Aliases: []string{"pubkeys", "publickey", "publickeys"},
+		Aliases: []string{"pubkeys", "publickey", "publickeys", "key", "keys"},
 		Short:   "Manage your public keys",
+		Long: `Manage the public keys you connect with. All your keys share your username
+and access, add one for every machine you connect from.`,
 	}
 
 	pubkeyAddCommand := &cobra.Command{
 		Use:   "add AUTHORIZED_KEY",
 		Short: "Add a public key",
-		Args:  cobra.MinimumNArgs(1),
+		Long: `Add a public key. Options in front of the key restrict it, like in
+authorized keys, see "pubkey options".`,
+		Args:              cobra.MinimumNArgs(1),
+		PersistentPreRunE: checkIfUnrestricted,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			be := backend.FromContext(ctx)
@@ -29,19 +36,21 @@ func PubkeyCommand() *cobra.Command {
 				return err
 			}
 
-			apk, _, err := sshutils.ParseAuthorizedKey(strings.Join(args, " "))
-			if err != nil {
-				return err
-			}
-
-			return be.AddPublicKey(ctx, user.Username(), apk)
+			return addPublicKey(cmd, user.Username(), strings.Join(args, " "))
 		},
 	}
 
+	var force bool
 	pubkeyRemoveCommand := &cobra.Command{
-		Use:   "remove AUTHORIZED_KEY",
+		Use:   "remove AUTHORIZED_KEY|FINGERPRINT",
 		Args:  cobra.MinimumNArgs(1),
 		Short: "Remove a public key",
+		Long: `Remove one of your public keys, given as an authorized key or as its SHA256
+fingerprint, as printed by "pubkey list --fingerprints".
+
+Removing the only key you have would lock you out, use --force to remove it
+anyway.`,
+		PersistentPreRunE: checkIfUnrestricted,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			be := backend.FromContext(ctx)
@@ -51,15 +60,36 @@ func PubkeyCommand() *cobra.Command {
 				return err
 			}
 
-			apk, _, err := sshutils.ParseAuthorizedKey(strings.Join(args, " "))
-			if err != nil {
-				return err
+			arg := strings.Join(args, " ")
+			var apk gossh.PublicKey
+			if fp, ok := parseFingerprint(arg); ok {
+				apk, err = userPublicKey(user, fp)
+				if err != nil {
+					return err
+				}
+			} else {
+				apk, _, err = sshutils.ParseAuthorizedKey(arg)
+				if err != nil {
+					return err
+				}
 			}
 
-			return be.RemovePublicKey(ctx, user.Username(), apk)
+			current := isCurrentKey(pk, apk)
+			if current && len(user.PublicKeys()) == 1 && !force {
+				return exitErrorf(ExitUsage, "this is your only public key, removing it would lock you out: add another key first, or use --force")
+			}
+			if err := be.RemovePublicKey(ctx, user.Username(), apk); err != nil {
+				return err
+			}
+			if current {
+				cmd.PrintErrln("Removed the key you're connected with, connect with another one of your keys from now on")
+			}
+			return nil
 		},
 	}
+	pubkeyRemoveCommand.Flags().BoolVarP(&force, "force", "f", false, "remove your only public key")
 
+	var fingerprints bool
 	pubkeyListCommand := &cobra.Command{
 		Use:     "list",
 		Aliases: []string{"ls"},
@@ -75,19 +105,154 @@ func PubkeyCommand() *cobra.Command {
 			}
 
 			pks := user.PublicKeys()
-			for _, pk := range pks {
-				cmd.Println(sshutils.MarshalAuthorizedKey(pk))
+			for _, k := range pks {
+				opts, err := be.PublicKeyOptions(ctx, k)
+				if err != nil {
+					return err
+				}
+				if !fingerprints {
+					line := sshutils.MarshalAuthorizedKey(k)
+					if !opts.IsZero() {
+						line = opts.String() + " " + line
+					}
+					cmd.Println(line)
+					continue
+				}
+				line := gossh.FingerprintSHA256(k) + " " + k.Type()
+				if !opts.IsZero() {
+					line += " " + opts.String()
+				}
+				if isCurrentKey(pk, k) {
+					line += " (current)"
+				}
+				cmd.Println(line)
 			}
 
 			return nil
 		},
 	}
+	pubkeyListCommand.Flags().BoolVarP(&fingerprints, "fingerprints", "l", false, "print the fingerprints of the keys")
+
+	var clearOptions bool
+	pubkeyOptionsCommand := &cobra.Command{
+		Use:   "options FINGERPRINT [OPTIONS]",
+		Short: "Show or set the options of a public key",
+		Long: `Show or set the options of one of your public keys, given as its SHA256
+fingerprint. Options restrict what a key can do on top of your access:
+
+  command="COMMAND"  run COMMAND whatever the key asks for
+  no-pty             don't open the UI or the shell
+  read-only          only read repositories
+  repo="REPO"        only access the REPO repository, e.g. a deploy key
+
+Options are comma separated, like in authorized keys, e.g.
+'repo="icecream",read-only'. Use --clear to remove them. Keys with options
+can't manage keys and tokens.`,
+		Args:              cobra.RangeArgs(1, 2),
+		PersistentPreRunE: checkIfUnrestricted,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			pk := sshutils.PublicKeyFromContext(ctx)
+			user, err := be.UserByPublicKey(ctx, pk)
+			if err != nil {
+				return err
+			}
+
+			fp, ok := parseFingerprint(args[0])
+			if !ok {
+				return exitErrorf(ExitUsage, "invalid fingerprint %q, see \"pubkey list --fingerprints\"", args[0])
+			}
+			apk, err := userPublicKey(user, fp)
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case clearOptions:
+				if len(args) > 1 {
+					return exitErrorf(ExitUsage, "--clear doesn't take options")
+				}
+				return be.SetPublicKeyOptions(ctx, user.Username(), apk, proto.KeyOptions{})
+			case len(args) > 1:
+				opts, err := proto.ParseKeyOptions(args[1])
+				if err != nil {
+					return exitErrorf(ExitUsage, "%v", err)
+				}
+				return be.SetPublicKeyOptions(ctx, user.Username(), apk, opts)
+			}
+
+			opts, err := be.PublicKeyOptions(ctx, apk)
+			if err != nil {
+				return err
+			}
+			if !opts.IsZero() {
+				cmd.Println(opts.String())
+			}
+			return nil
+		},
+	}
+	pubkeyOptionsCommand.Flags().BoolVar(&clearOptions, "clear", false, "remove the options of the key")
 
 	cmd.AddCommand(
 		pubkeyAddCommand,
 		pubkeyRemoveCommand,
 		pubkeyListCommand,
+		pubkeyOptionsCommand,
 	)
 
 	return cmd
 }
+
+// addPublicKey adds a public key to a user with the options in front of it,
+// if any.
+func addPublicKey(cmd *cobra.Command, username string, ak string) error {
+	ctx := cmd.Context()
+	be := backend.FromContext(ctx)
+	pk, o, err := sshutils.ParseAuthorizedKeyOptions(ak)
+	if err != nil {
+		return err
+	}
+	opts, err := proto.ParseKeyOptions(o)
+	if err != nil {
+		return exitErrorf(ExitUsage, "%v", err)
+	}
+
+	if err := be.AddPublicKey(ctx, username, pk); err != nil {
+		return err
+	}
+	if opts.IsZero() {
+		return nil
+	}
+	return be.SetPublicKeyOptions(ctx, username, pk, opts)
+}
+
+// userPublicKey returns the public key of the user with the given SHA256
+// fingerprint.
+func userPublicKey(user proto.User, fp string) (gossh.PublicKey, error) {
+	for _, k := range user.PublicKeys() {
+		if gossh.FingerprintSHA256(k) == fp {
+			return k, nil
+		}
+	}
+	return nil, exitErrorf(ExitNotFound, "no public key with fingerprint %s", fp)
+}
+
+// parseFingerprint returns the SHA256 fingerprint s is, with its "SHA256:"
+// prefix.
+func parseFingerprint(s string) (string, bool) {
+	fp, ok := strings.CutPrefix(s, "SHA256:")
+	if !ok || fp == "" || strings.ContainsAny(fp, " \t") {
+		return "", false
+	}
+	return "SHA256:" + fp, true
+}
+
+// isCurrentKey returns true if the user is connected with the given key, or
+// with a certificate of it.
+func isCurrentKey(current, pk gossh.PublicKey) bool {
+	if cert, ok := current.(*gossh.Certificate); ok {
+		current = cert.Key
+	}
+	return current != nil && sshutils.KeysEqual(current, pk)
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+func pushLimitsCommand() *cobra.Command {
+	var maxBlobSize string
+	var maxTreeDepth int
+	cmd := &cobra.Command{
+		Use:   "push-limits REPOSITORY",
+		Short: "Set or get the limits of pushed files",
+		Long: `Set or get the limits of the files pushed to a repository.
+
+Pushes that add a file larger than --max-blob-size, e.g. "10MB", or a directory
+nested deeper than --max-tree-depth are rejected. A limit of 0 removes it.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRepo(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			rn := strings.TrimSuffix(args[0], ".git")
+			blobChanged := cmd.Flags().Changed("max-blob-size")
+			depthChanged := cmd.Flags().Changed("max-tree-depth")
+			if !blobChanged && !depthChanged {
+				if err := checkIfReadable(cmd, args); err != nil {
+					return err
+				}
+
+				limits, err := be.PushLimits(ctx, rn)
+				if err != nil {
+					return err
+				}
+
+				size := "unlimited"
+				if limits.MaxBlobSize > 0 {
+					size = humanize.Bytes(uint64(limits.MaxBlobSize))
+				}
+				depth := "unlimited"
+				if limits.MaxTreeDepth > 0 {
+					depth = fmt.Sprint(limits.MaxTreeDepth)
+				}
+
+				cmd.Println("Max blob size:", size)
+				cmd.Println("Max tree depth:", depth)
+				return nil
+			}
+
+			if err := checkIfRepoAdmin(cmd, args); err != nil {
+				return err
+			}
+			if _, err := be.Repository(ctx, rn); err != nil {
+				return err
+			}
+
+			limits, err := be.PushLimits(ctx, rn)
+			if err != nil {
+				return err
+			}
+			if blobChanged {
+				size, err := humanize.ParseBytes(maxBlobSize)
+				if err != nil {
+					return exitErrorf(ExitUsage, "invalid max blob size %q", maxBlobSize)
+				}
+				limits.MaxBlobSize = int64(size)
+			}
+			if depthChanged {
+				if maxTreeDepth < 0 {
+					return exitErrorf(ExitUsage, "invalid max tree depth %d", maxTreeDepth)
+				}
+				limits.MaxTreeDepth = maxTreeDepth
+			}
+
+			return be.SetPushLimits(ctx, rn, limits)
+		},
+	}
+
+	cmd.Flags().StringVar(&maxBlobSize, "max-blob-size", "", "Maximum size of a pushed file, 0 for no limit")
+	cmd.Flags().IntVar(&maxTreeDepth, "max-tree-depth", 0, "Maximum number of nested directories, 0 for no limit")
+
+	return cmd
+}
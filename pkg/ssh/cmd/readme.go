@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/spf13/cobra"
+)
+
+func readmeCommand() *cobra.Command {
+	var reset bool
+	cmd := &cobra.Command{
+		Use:   "readme REPOSITORY [PATH]",
+		Short: "Set or get the file a repository shows as its readme",
+		Long: `Set or get the file the terminal UI shows as the readme of a repository.
+
+PATH is relative to the root of the repository, e.g. docs/OVERVIEW.md. The
+README at the root is shown when there's no such file. Without a path set, the
+readme of the ` + backend.ViewSettingsFile + ` file of the repository is used, if
+any. Use --reset to go back to it.`,
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completeRepo(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			rn := strings.TrimSuffix(args[0], ".git")
+			if len(args) == 1 && !reset {
+				if err := checkIfReadable(cmd, args); err != nil {
+					return err
+				}
+
+				rp, err := be.ReadmePath(ctx, rn)
+				if err != nil {
+					return err
+				}
+				if rp == "" {
+					rp = "default"
+				}
+
+				cmd.Println(rp)
+				return nil
+			}
+
+			if err := checkIfCollab(cmd, args); err != nil {
+				return err
+			}
+			if _, err := be.Repository(ctx, rn); err != nil {
+				return err
+			}
+
+			var rp string
+			if !reset {
+				var err error
+				rp, err = backend.CleanReadmePath(args[1])
+				if err != nil {
+					return err
+				}
+			}
+
+			return be.SetReadmePath(ctx, rn, rp)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&reset, "reset", "r", false, "Show the readme of the view settings, or the README")
+
+	return cmd
+}
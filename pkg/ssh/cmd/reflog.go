@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/caarlos0/tablewriter"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+// reflogEntryRe matches a reflog entry, e.g. "main@{2}".
+var reflogEntryRe = regexp.MustCompile(`^(.+)@\{(\d+)\}$`)
+
+func adminReflogCommand() *cobra.Command {
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "reflog REPOSITORY [REF[@{N}]]",
+		Short: "Browse the reflogs of a repository",
+		Long: `Browse the reflogs of a repository to recover commits lost to a force push.
+
+Without REF, the references that have a reflog are listed. With REF, e.g. main
+or refs/heads/main, the updates of the reference are listed, the latest first,
+with the commits it pointed to before and after, and forced updates marked.
+REF@{N}, e.g. main@{1}, shows the full hashes of an entry and the changes
+from the old to the new commit.
+
+References only have a reflog once core.logAllRefUpdates is set, with
+"admin repo-config REPOSITORY core.logAllRefUpdates --set true". Nothing is
+ever changed.`,
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completeRepo(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			rn := args[0]
+
+			if len(args) == 1 {
+				refs, err := be.ReflogRefs(ctx, rn)
+				if err != nil {
+					return err
+				}
+				if len(refs) == 0 {
+					cmd.Println("No reflogs, set core.logAllRefUpdates to record them.")
+					return nil
+				}
+				for _, ref := range refs {
+					cmd.Println(ref)
+				}
+				return nil
+			}
+
+			ref, index := args[1], -1
+			if m := reflogEntryRe.FindStringSubmatch(ref); m != nil {
+				ref = m[1]
+				index, _ = strconv.Atoi(m[2])
+			}
+			full, entries, err := be.Reflog(ctx, rn, ref)
+			if errors.Is(err, git.ErrReferenceNotExist) {
+				return exitErrorf(ExitNotFound, "no reflog for %q", ref)
+			} else if err != nil {
+				return err
+			}
+			name := git.ReferenceName(full).Short()
+
+			if index < 0 {
+				if limit > 0 && len(entries) > limit {
+					entries = entries[:limit]
+				}
+				return tablewriter.Render(
+					cmd.OutOrStdout(),
+					entries,
+					[]string{"Entry", "Old", "New", "Operation", "By", "When"},
+					func(e backend.ReflogEntry) ([]string, error) {
+						return []string{
+							fmt.Sprintf("%s@{%d}", name, e.Index),
+							shortHash(e.Old),
+							shortHash(e.New),
+							reflogOperation(e),
+							e.Name,
+							humanize.Time(e.When),
+						}, nil
+					},
+				)
+			}
+
+			if index >= len(entries) {
+				return exitErrorf(ExitNotFound, "no reflog entry %s@{%d}, %s has %d", name, index, name, len(entries))
+			}
+			e := entries[index]
+			cmd.Printf("entry     %s@{%d}\n", name, e.Index)
+			cmd.Printf("old       %s\n", e.Old)
+			cmd.Printf("new       %s\n", e.New)
+			cmd.Printf("operation %s\n", reflogOperation(e))
+			cmd.Printf("by        %s <%s>\n", e.Name, e.Email)
+			cmd.Printf("date      %s\n", e.When.UTC().Format(time.UnixDate))
+
+			diff, err := be.ReflogDiff(ctx, rn, e)
+			if errors.Is(err, backend.ErrReflogNoDiff) {
+				return nil
+			} else if err != nil {
+				return fmt.Errorf("failed to compare %s and %s: %w", shortHash(e.Old), shortHash(e.New), err)
+			}
+			cmd.Printf("\n%s\n%s", diff.Stats().String(), diff.Patch())
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 0, "maximum number of entries to list, 0 means no limit")
+
+	return cmd
+}
+
+// reflogOperation returns the operation of a reflog entry, its message and
+// whether the reference was created, deleted, or forced.
+func reflogOperation(e backend.ReflogEntry) string {
+	op := e.Message
+	if op == "" {
+		op = "update"
+	}
+	switch {
+	case git.IsZeroHash(e.Old):
+		op += " (created)"
+	case git.IsZeroHash(e.New):
+		op += " (deleted)"
+	case e.Forced:
+		op += " (forced)"
+	}
+	return op
+}
+
+// shortHash returns the abbreviated hash, or "-" for the zero hash.
+func shortHash(h string) string {
+	if git.IsZeroHash(h) {
+		return "-"
+	}
+	return h[:min(7, len(h))]
+}
@@ -6,21 +6,32 @@ import (
 )
 
 func renameCommand() *cobra.Command {
+	var dryRun bool
 	cmd := &cobra.Command{
 		Use:               "rename REPOSITORY NEW_NAME",
 		Aliases:           []string{"mv", "move"},
 		Short:             "Rename an existing repository",
 		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeRepo(),
 		PersistentPreRunE: checkIfCollab,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			be := backend.FromContext(ctx)
 			oldName := args[0]
 			newName := args[1]
+			if dryRun {
+				changes, err := be.RenameRepositoryDryRun(ctx, oldName, newName)
+				if err != nil {
+					return err
+				}
+				return printChanges(cmd, changes)
+			}
 
 			return be.RenameRepository(ctx, oldName, newName)
 		},
 	}
 
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "List what would change without renaming")
+
 	return cmd
 }
@@ -5,8 +5,12 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/access"
 	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 )
 
@@ -19,90 +23,195 @@ func RepoCommand(renderer *lipgloss.Renderer) *cobra.Command {
 	}
 
 	cmd.AddCommand(
+		activityCommand(),
+		archiveFlagCommand(),
+		attestationCommand(),
+		avatarCommand(),
 		blobCommand(renderer),
 		branchCommand(),
+		catFileCommand(),
+		checkMetaCommand(),
+		cloneInstructionsCommand(),
 		collabCommand(),
+		repoTeamCommand(),
 		commitCommand(renderer),
 		createCommand(),
 		deleteCommand(),
+		deployCommand(),
 		descriptionCommand(),
+		exportCommand(),
+		forkCommand(),
+		forksCommand(),
 		hiddenCommand(),
 		importCommand(),
+		landingTabCommand(),
 		listCommand(),
+		mergeBaseCommand(),
 		mirrorCommand(),
 		privateCommand(),
 		projectName(),
+		pushLimitsCommand(),
+		readmeCommand(),
 		renameCommand(),
+		submodulesCommand(),
+		syncCommand(),
 		tagCommand(),
+		templatesCommand(),
+		transferCommand(),
 		treeCommand(),
+		watchCommand(),
+		watchersCommand(),
 		webhookCommand(),
 	)
 
-	cmd.AddCommand(
-		&cobra.Command{
-			Use:               "info REPOSITORY",
-			Short:             "Get information about a repository",
-			Args:              cobra.ExactArgs(1),
-			PersistentPreRunE: checkIfReadable,
-			RunE: func(cmd *cobra.Command, args []string) error {
-				ctx := cmd.Context()
-				be := backend.FromContext(ctx)
-				rn := args[0]
-				rr, err := be.Repository(ctx, rn)
-				if err != nil {
-					return err
-				}
+	var health bool
+	infoCmd := &cobra.Command{
+		Use:   "info REPOSITORY",
+		Short: "Get information about a repository",
+		Long: `Get information about a repository.
 
-				r, err := rr.Open()
-				if err != nil {
-					return err
-				}
+Use --health to also show the objects of the repository and whether it needs
+to be garbage collected. It's only available to admins.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if health {
+				return checkIfRepoAdmin(cmd, args)
+			}
+			return checkIfReadable(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			rn := args[0]
+			rr, err := be.Repository(ctx, rn)
+			if err != nil {
+				return err
+			}
+
+			r, err := rr.Open()
+			if err != nil {
+				return err
+			}
 
-				head, err := r.HEAD()
+			head, err := r.HEAD()
+			if err != nil {
+				return err
+			}
+
+			var owner proto.User
+			if rr.UserID() > 0 {
+				owner, err = be.UserByID(ctx, rr.UserID())
 				if err != nil {
 					return err
 				}
+			}
 
-				var owner proto.User
-				if rr.UserID() > 0 {
-					owner, err = be.UserByID(ctx, rr.UserID())
-					if err != nil {
-						return err
-					}
-				}
+			branches, _ := r.Branches()
+			tags, _ := r.Tags()
 
-				branches, _ := r.Branches()
-				tags, _ := r.Tags()
-
-				// project name and description are optional, handle trailing
-				// whitespace to avoid breaking tests.
-				cmd.Println(strings.TrimSpace(fmt.Sprint("Project Name: ", rr.ProjectName())))
-				cmd.Println("Repository:", rr.Name())
-				cmd.Println(strings.TrimSpace(fmt.Sprint("Description: ", rr.Description())))
-				cmd.Println("Private:", rr.IsPrivate())
-				cmd.Println("Hidden:", rr.IsHidden())
-				cmd.Println("Mirror:", rr.IsMirror())
-				if owner != nil {
-					cmd.Println(strings.TrimSpace(fmt.Sprint("Owner: ", owner.Username())))
+			// project name and description are optional, handle trailing
+			// whitespace to avoid breaking tests.
+			cmd.Println(strings.TrimSpace(fmt.Sprint("Project Name: ", rr.ProjectName())))
+			cmd.Println("Repository:", rr.Name())
+			cmd.Println(strings.TrimSpace(fmt.Sprint("Description: ", rr.Description())))
+			cmd.Println("Private:", rr.IsPrivate())
+			cmd.Println("Hidden:", rr.IsHidden())
+			cmd.Println("Archived:", rr.IsArchived())
+			cmd.Println("Mirror:", rr.IsMirror())
+			if rr.IsMirror() {
+				printMirror(cmd, be, rn)
+			}
+			// The parent isn't shown to users who can't read it.
+			if parent, err := be.ForkParent(ctx, rn); err == nil && parent != "" &&
+				be.AccessLevelForUser(ctx, parent, proto.UserFromContext(ctx)) >= access.ReadOnlyAccess {
+				cmd.Println("Fork of:", parent)
+			}
+			if owner != nil {
+				cmd.Println(strings.TrimSpace(fmt.Sprint("Owner: ", owner.Username())))
+			} else {
+				cmd.Println("Owner: unknown")
+			}
+			cmd.Println("Default Branch:", head.Name().Short())
+			cmd.Println("Clone URL:", config.FromContext(ctx).SSHCloneURL(rr.Name()))
+			if watchers, err := be.Watchers(ctx, rr.Name()); err == nil {
+				cmd.Println("Watchers:", len(watchers))
+			}
+			if len(branches) > 0 {
+				cmd.Println("Branches:")
+				for _, b := range branches {
+					cmd.Println("  -", b)
 				}
-				cmd.Println("Default Branch:", head.Name().Short())
-				if len(branches) > 0 {
-					cmd.Println("Branches:")
-					for _, b := range branches {
-						cmd.Println("  -", b)
+			}
+			if len(tags) > 0 {
+				// Show where tags that point to other tags end up.
+				chains := map[string][]git.AnnotatedTag{}
+				if refs, err := r.ReferencesInfo(git.RefsTags); err == nil {
+					for _, ref := range refs {
+						if len(ref.TagChain) > 0 {
+							chains[ref.Name().Short()] = ref.TagChain
+						}
 					}
 				}
-				if len(tags) > 0 {
-					cmd.Println("Tags:")
-					for _, t := range tags {
+
+				cmd.Println("Tags:")
+				for _, t := range tags {
+					if chain, ok := chains[t]; ok {
+						cmd.Println("  -", t, tagChainString(chain))
+					} else {
 						cmd.Println("  -", t)
 					}
 				}
+			}
 
-				return nil
-			},
+			if health {
+				if err := printRepoHealth(cmd, be, rr.Name(), r); err != nil {
+					return err
+				}
+			}
+
+			return nil
 		},
-	)
+	}
+
+	infoCmd.Flags().BoolVar(&health, "health", false, "show the objects of the repository and whether it needs gc")
+	cmd.AddCommand(infoCmd)
 
 	return cmd
 }
+
+// printRepoHealth prints the number and size of the objects of the repository
+// and whether git gc would run on it, along with how they grew over the
+// recorded sizes.
+func printRepoHealth(cmd *cobra.Command, be *backend.Backend, name string, r *git.Repository) error {
+	stats, err := r.ObjectStats()
+	if err != nil {
+		return err
+	}
+
+	cmd.Println("Health:")
+	cmd.Println("  Loose Objects:", stats.Loose, fmt.Sprintf("(%s)", humanize.Bytes(uint64(stats.LooseSize))))
+	cmd.Println("  Packed Objects:", stats.Packed)
+	cmd.Println("  Packs:", stats.Packs, fmt.Sprintf("(%s)", humanize.Bytes(uint64(stats.PackSize))))
+	cmd.Println("  Garbage:", stats.Garbage)
+	cmd.Println("  Size:", humanize.Bytes(uint64(stats.Size())))
+	sizes, err := be.RepoSizes(cmd.Context(), name)
+	if err != nil {
+		return err
+	}
+	if len(sizes) > 0 {
+		g := repoGrowth{name: name, sizes: sizes}
+		first, last := g.first(), g.last()
+		since := humanize.Time(first.At)
+		sizeTrend, objectsTrend := g.trends()
+		cmd.Println("  Size History:", sizeTrend, fmt.Sprintf("(%s since %s)", byteGrowth(last.Size-first.Size), since))
+		cmd.Println("  Objects History:", objectsTrend, fmt.Sprintf("(%+d since %s)", last.Objects-first.Objects, since))
+	}
+	if reasons := stats.GCReasons(r.GCThresholds()); len(reasons) > 0 {
+		cmd.Println("  Recommendation: run gc,", strings.Join(reasons, ", "))
+	} else {
+		cmd.Println("  Recommendation: none")
+	}
+
+	return nil
+}
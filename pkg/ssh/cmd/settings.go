@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"fmt"
 	"strconv"
 
 	"github.com/charmbracelet/soft-serve/pkg/access"
@@ -57,7 +56,7 @@ func SettingsCommand() *cobra.Command {
 				case 1:
 					al := access.ParseAccessLevel(args[0])
 					if al < 0 {
-						return fmt.Errorf("invalid access level: %s. Please choose one of the following: %s", args[0], als)
+						return exitErrorf(ExitUsage, "invalid access level: %s. Please choose one of the following: %s", args[0], als)
 					}
 					if err := be.SetAnonAccess(ctx, al); err != nil {
 						return err
@@ -69,5 +68,71 @@ func SettingsCommand() *cobra.Command {
 		},
 	)
 
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:               "maintenance [true|false]",
+			Short:             "Set or get read-only maintenance mode",
+			Long:              "Set or get read-only maintenance mode. While it's enabled, pushes are rejected but clones, fetches, and browsing keep working.",
+			Args:              cobra.RangeArgs(0, 1),
+			PersistentPreRunE: checkIfAdmin,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				be := backend.FromContext(ctx)
+				switch len(args) {
+				case 0:
+					cmd.Println(be.MaintenanceMode(ctx))
+				case 1:
+					v, _ := strconv.ParseBool(args[0])
+					if err := be.SetMaintenanceMode(ctx, v); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:               "maintenance-message [MESSAGE]",
+			Short:             "Set or get the message shown while in maintenance mode",
+			Args:              cobra.RangeArgs(0, 1),
+			PersistentPreRunE: checkIfAdmin,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				be := backend.FromContext(ctx)
+				switch len(args) {
+				case 0:
+					cmd.Println(be.MaintenanceMessage(ctx))
+				case 1:
+					if err := be.SetMaintenanceMessage(ctx, args[0]); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:               "maintenance-admins [true|false]",
+			Short:             "Set or get whether admins can push while in maintenance mode",
+			Args:              cobra.RangeArgs(0, 1),
+			PersistentPreRunE: checkIfAdmin,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				be := backend.FromContext(ctx)
+				switch len(args) {
+				case 0:
+					cmd.Println(be.MaintenanceAdminsExempt(ctx))
+				case 1:
+					v, _ := strconv.ParseBool(args[0])
+					if err := be.SetMaintenanceAdminsExempt(ctx, v); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			},
+		},
+	)
+
 	return cmd
 }
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/caarlos0/tablewriter"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+)
+
+// sparkTicks are the bars of sparklines, from the lowest value to the
+// highest.
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws the values as bars scaled between the lowest and the
+// highest of them.
+func sparkline(values []int64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	var sb strings.Builder
+	for _, v := range values {
+		i := 0
+		if hi > lo {
+			i = int((v - lo) * int64(len(sparkTicks)-1) / (hi - lo))
+		}
+		sb.WriteRune(sparkTicks[i])
+	}
+	return sb.String()
+}
+
+// byteGrowth formats a change of size in bytes, e.g. "+3.3 MB".
+func byteGrowth(d int64) string {
+	if d < 0 {
+		return "-" + humanize.Bytes(uint64(-d))
+	}
+	return "+" + humanize.Bytes(uint64(d))
+}
+
+// repoGrowth is how much a repository grew over its recorded sizes.
+type repoGrowth struct {
+	name  string
+	sizes []backend.RepoSize
+}
+
+func (g repoGrowth) first() backend.RepoSize { return g.sizes[0] }
+func (g repoGrowth) last() backend.RepoSize  { return g.sizes[len(g.sizes)-1] }
+
+// trends returns the sparklines of the sizes and of the numbers of objects.
+func (g repoGrowth) trends() (string, string) {
+	sizes := make([]int64, len(g.sizes))
+	objects := make([]int64, len(g.sizes))
+	for i, s := range g.sizes {
+		sizes[i], objects[i] = s.Size, s.Objects
+	}
+	return sparkline(sizes), sparkline(objects)
+}
+
+func adminSizesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sizes",
+		Short: "List the growth of the repositories",
+		Long: `List the size and the number of objects of the repositories, and how much
+they grew since the oldest recorded size, the fastest growing first. The sizes
+are recorded on the schedule of "jobs.repo_sizes", see "repo info --health"
+for the history of a repository.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			repos, err := be.Repositories(ctx)
+			if err != nil {
+				return err
+			}
+
+			var growths []repoGrowth
+			for _, r := range repos {
+				sizes, err := be.RepoSizes(ctx, r.Name())
+				if err != nil {
+					return err
+				}
+				if len(sizes) > 0 {
+					growths = append(growths, repoGrowth{name: r.Name(), sizes: sizes})
+				}
+			}
+			if len(growths) == 0 {
+				cmd.Println("No repository sizes recorded yet")
+				return nil
+			}
+			sort.SliceStable(growths, func(i, j int) bool {
+				gi := growths[i].last().Size - growths[i].first().Size
+				gj := growths[j].last().Size - growths[j].first().Size
+				if gi != gj {
+					return gi > gj
+				}
+				return growths[i].name < growths[j].name
+			})
+
+			return tablewriter.Render(
+				cmd.OutOrStdout(),
+				growths,
+				[]string{"Repository", "Size", "Objects", "Growth", "Since", "Trend"},
+				func(g repoGrowth) ([]string, error) {
+					first, last := g.first(), g.last()
+					trend, _ := g.trends()
+					return []string{
+						g.name,
+						humanize.Bytes(uint64(last.Size)),
+						fmt.Sprint(last.Objects),
+						fmt.Sprintf("%s, %+d objects", byteGrowth(last.Size-first.Size), last.Objects-first.Objects),
+						humanize.Time(first.At),
+						trend,
+					}, nil
+				},
+			)
+		},
+	}
+
+	return cmd
+}
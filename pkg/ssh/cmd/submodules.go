@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/caarlos0/tablewriter"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/spf13/cobra"
+)
+
+// submodulesCommand returns a command that lists the submodules of a
+// repository.
+func submodulesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "submodules REPOSITORY [REFERENCE]",
+		Short: "List repository submodules",
+		Long: `List repository submodules and their pinned commits.
+
+Submodules hosted on this server show whether the pinned commit exists and how
+many commits it's behind the submodule branch.`,
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completeRepo(revisionArg),
+		PersistentPreRunE: checkIfReadable,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			rr, err := be.Repository(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			r, err := rr.Open()
+			if err != nil {
+				return err
+			}
+
+			var rev string
+			if len(args) > 1 {
+				c, err := r.CatFileCommit(args[1])
+				if err != nil {
+					return git.ErrRevisionNotExist
+				}
+				rev = c.ID.String()
+			} else {
+				head, err := r.HEAD()
+				if err != nil {
+					if bs, err := r.Branches(); err != nil && len(bs) == 0 {
+						return fmt.Errorf("repository is empty")
+					}
+					return err
+				}
+				rev = head.ID
+			}
+
+			subs, err := be.Submodules(ctx, proto.UserFromContext(ctx), rr, rev)
+			if err != nil {
+				return err
+			}
+
+			if len(subs) == 0 {
+				cmd.Println("No submodules found")
+				return nil
+			}
+
+			return tablewriter.Render(
+				cmd.OutOrStdout(),
+				subs,
+				[]string{"Path", "URL", "Commit", "Status"},
+				func(s proto.Submodule) ([]string, error) {
+					commit := "-"
+					if s.Commit != "" {
+						commit = s.Commit[:7]
+					}
+					status := s.Status()
+					if status == "" {
+						status = "-"
+					}
+					return []string{s.Path, s.URL, commit, status}, nil
+				},
+			)
+		},
+	}
+
+	return cmd
+}
@@ -31,6 +31,7 @@ func tagListCommand() *cobra.Command {
 		Aliases:           []string{"ls"},
 		Short:             "List repository tags",
 		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRepo(),
 		PersistentPreRunE: checkIfReadable,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
@@ -64,6 +65,7 @@ func tagDeleteCommand() *cobra.Command {
 		Aliases:           []string{"remove", "rm", "del"},
 		Short:             "Delete a tag",
 		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeRepo(tagArg),
 		PersistentPreRunE: checkIfCollab,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
@@ -74,38 +76,50 @@ func tagDeleteCommand() *cobra.Command {
 				return err
 			}
 
-			r, err := rr.Open()
-			if err != nil {
-				log.Errorf("failed to open repo: %s", err)
-				return err
+			if rr.IsArchived() {
+				return proto.ErrRepoArchived
 			}
 
 			tag := args[1]
-			tags, _ := r.Tags()
-			var exists bool
-			for _, t := range tags {
-				if tag == t {
-					exists = true
-					break
+			var tagCommit *git.Commit
+			if err := be.WithRepository(ctx, rr.Name(), true, func() error {
+				r, err := rr.Open()
+				if err != nil {
+					log.Errorf("failed to open repo: %s", err)
+					return err
 				}
-			}
 
-			if !exists {
-				log.Errorf("failed to get tag: tag %s does not exist", tag)
-				return git.ErrReferenceNotExist
-			}
+				tags, _ := r.Tags()
+				var exists bool
+				for _, t := range tags {
+					if tag == t {
+						exists = true
+						break
+					}
+				}
 
-			tagCommit, err := r.TagCommit(tag)
-			if err != nil {
-				log.Errorf("failed to get tag commit: %s", err)
-				return err
-			}
+				if !exists {
+					log.Errorf("failed to get tag: tag %s does not exist", tag)
+					return git.ErrReferenceNotExist
+				}
 
-			if err := r.DeleteTag(tag); err != nil {
-				log.Errorf("failed to delete tag: %s", err)
+				tagCommit, err = r.TagCommit(tag)
+				if err != nil {
+					log.Errorf("failed to get tag commit: %s", err)
+					return err
+				}
+
+				if err := r.DeleteTag(tag); err != nil {
+					log.Errorf("failed to delete tag: %s", err)
+					return err
+				}
+				return nil
+			}); err != nil {
 				return err
 			}
 
+			be.PublishRefUpdate(ctx, rr.Name(), git.RefsTags+tag, tagCommit.ID.String(), git.ZeroID)
+
 			wh, err := webhook.NewBranchTagEvent(ctx, proto.UserFromContext(ctx), rr, git.RefsTags+tag, tagCommit.ID.String(), git.ZeroID)
 			if err != nil {
 				log.Error("failed to create branch_tag webhook", "err", err)
@@ -118,3 +132,19 @@ func tagDeleteCommand() *cobra.Command {
 
 	return cmd
 }
+
+// tagChainString returns the tags a nested tag points to followed by the
+// final object, i.e. "-> v1.0.0 -> commit 1a2b3c4".
+func tagChainString(chain []git.AnnotatedTag) string {
+	var sb strings.Builder
+	for _, t := range chain[1:] {
+		sb.WriteString("-> " + t.Name + " ")
+	}
+	last := chain[len(chain)-1]
+	obj := last.Object
+	if len(obj) > 7 {
+		obj = obj[:7]
+	}
+	sb.WriteString("-> " + last.ObjectType + " " + obj)
+	return sb.String()
+}
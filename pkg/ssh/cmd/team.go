@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"slices"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/spf13/cobra"
+)
+
+// TeamCommand returns the team subcommand.
+func TeamCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "team",
+		Aliases: []string{"teams"},
+		Short:   "Manage teams",
+		Long: `Manage teams of users.
+
+Admins create teams and manage their members. Teams are given access to
+repositories with "repo team add", and their members get the highest access
+level of their teams and collaborations. The repositories of your teams are
+listed first in the UI.`,
+	}
+
+	teamCreateCommand := &cobra.Command{
+		Use:               "create TEAM",
+		Short:             "Create a team",
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: checkIfAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			return be.CreateTeam(ctx, args[0])
+		},
+	}
+
+	teamDeleteCommand := &cobra.Command{
+		Use:               "delete TEAM",
+		Short:             "Delete a team",
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: checkIfAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			return be.DeleteTeam(ctx, args[0])
+		},
+	}
+
+	teamListCommand := &cobra.Command{
+		Use:               "list",
+		Aliases:           []string{"ls"},
+		Short:             "List teams",
+		Long:              "List teams. Admins see every team, users the teams they're a member of.",
+		Args:              cobra.NoArgs,
+		PersistentPreRunE: checkIfUser,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			var teams []string
+			var err error
+			if checkIfAdmin(cmd, nil) == nil {
+				teams, err = be.Teams(ctx)
+			} else {
+				teams, err = be.UserTeams(ctx, proto.UserFromContext(ctx))
+			}
+			if err != nil {
+				return err
+			}
+
+			for _, t := range teams {
+				cmd.Println(t)
+			}
+			return nil
+		},
+	}
+
+	teamInfoCommand := &cobra.Command{
+		Use:               "info TEAM",
+		Short:             "Show the members and repositories of a team",
+		Long:              "Show the members and repositories of a team. Admins see every team, users the teams they're a member of.",
+		Args:              cobra.ExactArgs(1),
+		PersistentPreRunE: checkIfUser,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			team := args[0]
+			members, err := be.TeamMembers(ctx, team)
+			if err != nil {
+				return err
+			}
+			user := proto.UserFromContext(ctx)
+			if checkIfAdmin(cmd, nil) != nil && !slices.Contains(members, user.Username()) {
+				return proto.ErrTeamNotFound
+			}
+			repos, err := be.TeamRepos(ctx, team)
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("Members:\n")
+			for _, m := range members {
+				if u, err := be.User(ctx, m); err == nil {
+					m = proto.DisplayName(u)
+				}
+				cmd.Printf("  %s\n", m)
+			}
+			cmd.Printf("Repositories:\n")
+			for _, r := range repos {
+				cmd.Printf("  %s %s\n", r.Repo, r.AccessLevel)
+			}
+			return nil
+		},
+	}
+
+	teamAddMemberCommand := &cobra.Command{
+		Use:               "add-member TEAM USERNAME",
+		Short:             "Add a user to a team",
+		Args:              cobra.ExactArgs(2),
+		PersistentPreRunE: checkIfAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			return be.AddTeamMember(ctx, args[0], args[1])
+		},
+	}
+
+	teamRemoveMemberCommand := &cobra.Command{
+		Use:               "remove-member TEAM USERNAME",
+		Short:             "Remove a user from a team",
+		Args:              cobra.ExactArgs(2),
+		PersistentPreRunE: checkIfAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			return be.RemoveTeamMember(ctx, args[0], args[1])
+		},
+	}
+
+	cmd.AddCommand(
+		teamCreateCommand,
+		teamDeleteCommand,
+		teamListCommand,
+		teamInfoCommand,
+		teamAddMemberCommand,
+		teamRemoveMemberCommand,
+	)
+
+	return cmd
+}
+
+func repoTeamCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "team",
+		Aliases: []string{"teams"},
+		Short:   "Manage the teams with access to a repo",
+	}
+
+	cmd.AddCommand(
+		repoTeamAddCommand(),
+		repoTeamRemoveCommand(),
+		repoTeamListCommand(),
+	)
+
+	return cmd
+}
+
+func repoTeamAddCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "add REPOSITORY TEAM [LEVEL]",
+		Short:             "Give a team access to a repo",
+		Long:              "Give a team access to a repo. LEVEL can be one of: no-access, read-only, read-write, or admin-access. Defaults to read-write.",
+		Args:              cobra.RangeArgs(2, 3),
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkCollabAccess,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			level := access.ReadWriteAccess
+			if len(args) > 2 {
+				level = access.ParseAccessLevel(args[2])
+				if level < 0 {
+					return access.ErrInvalidAccessLevel
+				}
+			}
+
+			return be.AddTeamRepo(ctx, args[1], args[0], level)
+		},
+	}
+
+	return cmd
+}
+
+func repoTeamRemoveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "remove REPOSITORY TEAM",
+		Short:             "Remove the access of a team to a repo",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkCollabAccess,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			return be.RemoveTeamRepo(ctx, args[1], args[0])
+		},
+	}
+
+	return cmd
+}
+
+func repoTeamListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "list REPOSITORY",
+		Short:             "List the teams with access to a repo",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkCollabAccess,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			tas, err := be.RepoTeams(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			for _, ta := range tas {
+				cmd.Printf("%s %s\n", ta.Team, ta.AccessLevel)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/spf13/cobra"
+)
+
+func templatesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "templates",
+		Short: "List the repository templates of the server",
+		Long: `List the templates repositories can be created from with "repo create --template".
+
+Templates are defined by the server admin in the config file. Each line has
+the name of a template, the repository its files come from or "directory",
+and its description. Templates whose files can't be read are only listed to
+admins, with the reason on stderr.`,
+		Args:              cobra.NoArgs,
+		PersistentPreRunE: checkIfUser,
+		Run: func(cmd *cobra.Command, _ []string) {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			user := proto.UserFromContext(ctx)
+			for _, t := range be.RepoTemplates(ctx, user) {
+				if err := be.CheckRepoTemplate(ctx, t); err != nil {
+					if !user.IsAdmin() {
+						continue
+					}
+					cmd.PrintErrf("Template %s is unavailable: %v\n", t.Name, err)
+				}
+				source := t.Repo
+				if source == "" {
+					source = "directory"
+				}
+				cmd.Printf("%s\t%s\t%s\n", t.Name, source, t.Description)
+			}
+		},
+	}
+
+	return cmd
+}
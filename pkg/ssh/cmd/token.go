@@ -23,9 +23,10 @@ func TokenCommand() *cobra.Command {
 
 	var createExpiresIn string
 	createCmd := &cobra.Command{
-		Use:   "create NAME",
-		Short: "Create a new access token",
-		Args:  cobra.MinimumNArgs(1),
+		Use:               "create NAME",
+		Short:             "Create a new access token",
+		Args:              cobra.MinimumNArgs(1),
+		PersistentPreRunE: checkIfUnrestricted,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			be := backend.FromContext(ctx)
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func transferCommand() *cobra.Command {
+	var keep string
+	cmd := &cobra.Command{
+		Use:   "transfer REPOSITORY USERNAME|AUTHORIZED_KEY",
+		Short: "Transfer a repository to another owner",
+		Long: `Make another user the owner of a repository, given by username or by one of
+their public keys. Only admins and the owner of the repository can transfer it.
+
+The previous owner loses the access they had as the owner, use --keep-access
+to keep them as a collaborator with the given access level.`,
+		Args:              cobra.MinimumNArgs(2),
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfOwner,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			rn := utils.SanitizeRepo(args[0])
+			level := access.NoAccess
+			if keep != "" {
+				level = access.ParseAccessLevel(keep)
+				if level < 0 {
+					return access.ErrInvalidAccessLevel
+				}
+			}
+
+			owner, err := transferTarget(cmd, args[1:])
+			if err != nil {
+				return err
+			}
+
+			prev, err := be.TransferRepository(ctx, rn, owner, level)
+			if err != nil {
+				return err
+			}
+
+			from := "no owner"
+			if prev != nil {
+				from = prev.Username()
+			}
+			cmd.Printf("Transferred %s from %s to %s\n", rn, from, owner.Username())
+			if prev != nil && level > access.NoAccess {
+				cmd.Printf("%s stays a collaborator with %s access\n", prev.Username(), level)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&keep, "keep-access", "", "keep the previous owner as a collaborator with this access level")
+
+	return cmd
+}
+
+// transferTarget returns the user the arguments are the username or the
+// authorized key of.
+func transferTarget(cmd *cobra.Command, args []string) (proto.User, error) {
+	ctx := cmd.Context()
+	be := backend.FromContext(ctx)
+	arg := strings.Join(args, " ")
+	if !strings.Contains(arg, " ") {
+		return be.User(ctx, arg)
+	}
+	pk, _, err := sshutils.ParseAuthorizedKey(arg)
+	if err != nil {
+		return nil, exitErrorf(ExitUsage, "invalid username or public key: %v", err)
+	}
+	return be.UserByPublicKey(ctx, pk)
+}
+
+// checkIfOwner checks that the user is an admin or the owner of the
+// repository. The options of the key limit their access too. Transfers are
+// rejected on followers, they go to the primary.
+func checkIfOwner(cmd *cobra.Command, args []string) error {
+	var repo string
+	if len(args) > 0 {
+		repo = args[0]
+	}
+
+	ctx := cmd.Context()
+	cfg := config.FromContext(ctx)
+	be := backend.FromContext(ctx)
+	rn := utils.SanitizeRepo(repo)
+	if !IsPublicKeyAdmin(cfg, sshutils.PublicKeyFromContext(ctx)) {
+		user := proto.UserFromContext(ctx)
+		if user == nil {
+			return proto.ErrUnauthorized
+		}
+		if be.AccessLevelForUser(ctx, rn, user) < access.AdminAccess {
+			return proto.ErrUnauthorized
+		}
+		if !user.IsAdmin() {
+			r, err := be.Repository(ctx, rn)
+			if err != nil || r.UserID() != user.ID() {
+				return proto.ErrUnauthorized
+			}
+		}
+	}
+
+	return be.CheckFollower(repo)
+}
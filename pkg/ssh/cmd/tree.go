@@ -5,6 +5,7 @@ import (
 
 	"github.com/charmbracelet/soft-serve/git"
 	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/lfs"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
 	"github.com/dustin/go-humanize"
@@ -13,10 +14,13 @@ import (
 
 // treeCommand returns a command that list file or directory at path.
 func treeCommand() *cobra.Command {
+	var opts outputOptions
+
 	cmd := &cobra.Command{
 		Use:               "tree REPOSITORY [REFERENCE] [PATH]",
 		Short:             "Print repository tree at path",
 		Args:              cobra.RangeArgs(1, 3),
+		ValidArgsFunction: completeRepo(revisionArg),
 		PersistentPreRunE: checkIfReadable,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
@@ -86,18 +90,42 @@ func treeCommand() *cobra.Command {
 				}
 			}
 			ents.Sort()
+			if err := r.LoadSizes(ents); err != nil {
+				return err
+			}
+			// LFS pointers show the size of the files they point to.
+			blobs := make([]git.TreeBlob, 0, len(ents))
 			for _, ent := range ents {
+				if ent.IsBlob() {
+					blobs = append(blobs, git.TreeBlob{Path: ent.Name(), ID: ent.ID().String(), Size: ent.Size()})
+				}
+			}
+			pointers, err := lfs.TreePointers(r, blobs)
+			if err != nil {
+				return err
+			}
+			out := opts.newOutput(cmd)
+			for _, ent := range ents {
+				if out.Done() {
+					break
+				}
 				size := ent.Size()
+				if p, ok := pointers[ent.Name()]; ok {
+					size = p.Size
+				}
 				ssize := ""
 				if size == 0 {
 					ssize = "-"
 				} else {
 					ssize = humanize.Bytes(uint64(size))
 				}
-				cmd.Printf("%s\t%s\t %s\n", ent.Mode(), ssize, common.UnquoteFilename(ent.Name()))
+				out.Printf("%s\t%s\t %s", ent.Mode(), ssize, common.UnquoteFilename(ent.Name()))
 			}
 			return nil
 		},
 	}
+
+	opts.addFlags(cmd)
+
 	return cmd
 }
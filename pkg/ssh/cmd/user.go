@@ -91,21 +91,14 @@ func UserCommand() *cobra.Command {
 	}
 
 	userAddPubkeyCommand := &cobra.Command{
-		Use:               "add-pubkey USERNAME AUTHORIZED_KEY",
-		Short:             "Add a public key to a user",
+		Use:   "add-pubkey USERNAME AUTHORIZED_KEY",
+		Short: "Add a public key to a user",
+		Long: `Add a public key to a user. Options in front of the key restrict it, like in
+authorized keys, see "pubkey options".`,
 		Args:              cobra.MinimumNArgs(2),
 		PersistentPreRunE: checkIfAdmin,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := cmd.Context()
-			be := backend.FromContext(ctx)
-			username := args[0]
-			pubkey := strings.Join(args[1:], " ")
-			pk, _, err := sshutils.ParseAuthorizedKey(pubkey)
-			if err != nil {
-				return err
-			}
-
-			return be.AddPublicKey(ctx, username, pk)
+			return addPublicKey(cmd, args[0], strings.Join(args[1:], " "))
 		},
 	}
 
@@ -160,10 +153,20 @@ func UserCommand() *cobra.Command {
 			isAdmin := user.IsAdmin()
 
 			cmd.Printf("Username: %s\n", user.Username())
+			if name := user.Name(); name != "" {
+				cmd.Printf("Name: %s\n", name)
+			}
 			cmd.Printf("Admin: %t\n", isAdmin)
 			cmd.Printf("Public keys:\n")
 			for _, pk := range user.PublicKeys() {
-				cmd.Printf("  %s\n", sshutils.MarshalAuthorizedKey(pk))
+				line := sshutils.MarshalAuthorizedKey(pk)
+				if opts, err := be.PublicKeyOptions(ctx, pk); err == nil && !opts.IsZero() {
+					line = opts.String() + " " + line
+				}
+				cmd.Printf("  %s\n", line)
+			}
+			if teams, err := be.UserTeams(ctx, user); err == nil && len(teams) > 0 {
+				cmd.Printf("Teams: %s\n", strings.Join(teams, ", "))
 			}
 
 			return nil
@@ -185,6 +188,37 @@ func UserCommand() *cobra.Command {
 		},
 	}
 
+	var nameUser string
+	userSetNameCommand := &cobra.Command{
+		Use:   "set-name NAME",
+		Short: "Set your display name",
+		Long: `Set your display name.
+
+Admins can set the display name of another user with --user.`,
+		Args:              cobra.MinimumNArgs(1),
+		PersistentPreRunE: checkIfUser,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			user := proto.UserFromContext(ctx)
+			if nameUser != "" && nameUser != user.Username() {
+				if err := checkIfAdmin(cmd, nil); err != nil {
+					return err
+				}
+
+				var err error
+				user, err = be.User(ctx, nameUser)
+				if err != nil {
+					return err
+				}
+			}
+
+			return be.SetName(ctx, user.Username(), strings.Join(args, " "))
+		},
+	}
+
+	userSetNameCommand.Flags().StringVarP(&nameUser, "user", "u", "", "set the display name of another user (admin only)")
+
 	cmd.AddCommand(
 		userCreateCommand,
 		userAddPubkeyCommand,
@@ -193,6 +227,7 @@ func UserCommand() *cobra.Command {
 		userDeleteCommand,
 		userRemovePubkeyCommand,
 		userSetAdminCommand,
+		userSetNameCommand,
 		userSetUsernameCommand,
 	)
 
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// watchCommand returns a command that streams repository events.
+func watchCommand() *cobra.Command {
+	var types []string
+	var count int
+
+	cmd := &cobra.Command{
+		Use:   "watch [REPOSITORY...]",
+		Short: "Stream repository events",
+		Long: `Stream the events of the repositories you can access as newline-delimited JSON until you disconnect.
+
+The event types are:
+  push         a reference updated by a push
+  ref_update   a reference updated outside of a push, like a deleted branch
+  repo_create  a repository created
+  repo_delete  a repository deleted
+  repo_rename  a repository renamed
+
+Pass repositories to only stream their events. Events of hidden repositories
+are only streamed when they're passed.`,
+		ValidArgsFunction: func(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeRepositories(cmd, toComplete), cobra.ShellCompDirectiveNoFileComp
+		},
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			for _, rn := range args {
+				if err := checkIfReadable(cmd, []string{rn}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			user := proto.UserFromContext(ctx)
+
+			for _, t := range types {
+				if !slices.Contains(proto.EventTypes(), t) {
+					return exitErrorf(ExitUsage, "invalid event type %q: must be one of %s", t, strings.Join(proto.EventTypes(), ", "))
+				}
+			}
+
+			repos := make(map[string]bool)
+			for _, rn := range args {
+				repos[utils.SanitizeRepo(rn)] = true
+			}
+
+			// isVisible returns true if the user can read the repository.
+			// Deleted repositories can't be checked anymore, so visible
+			// keeps track of the repositories seen so far.
+			isVisible := func(name string) bool {
+				r, err := be.Repository(ctx, name)
+				if err != nil || (r.IsHidden() && !repos[name]) {
+					return false
+				}
+				return be.AccessLevelForUser(ctx, name, user) >= access.ReadOnlyAccess
+			}
+
+			// Subscribe first so that no event is missed while looking up the
+			// repositories.
+			events, err := be.SubscribeEvents(ctx)
+			if err != nil {
+				return err
+			}
+
+			rs, err := be.Repositories(ctx)
+			if err != nil {
+				return err
+			}
+
+			visible := make(map[string]bool, len(rs))
+			for _, r := range rs {
+				visible[r.Name()] = isVisible(r.Name())
+			}
+
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			var n int
+			for ev := range events {
+				var ok bool
+				switch ev.Type {
+				case proto.EventRepoDelete:
+					ok = visible[ev.Repo]
+					delete(visible, ev.Repo)
+				case proto.EventRepoRename:
+					delete(visible, ev.OldRepo)
+					ok = isVisible(ev.Repo)
+					visible[ev.Repo] = ok
+				default:
+					ok = isVisible(ev.Repo)
+					visible[ev.Repo] = ok
+				}
+
+				if !ok ||
+					(len(types) > 0 && !slices.Contains(types, ev.Type)) ||
+					(len(repos) > 0 && !repos[ev.Repo] && !repos[ev.OldRepo]) {
+					continue
+				}
+
+				if err := enc.Encode(ev); err != nil {
+					return err
+				}
+
+				n++
+				if count > 0 && n >= count {
+					break
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&types, "event", "e", nil, "only stream events of the given types")
+	cmd.Flags().IntVarP(&count, "count", "n", 0, "exit after streaming the given number of events")
+
+	return streamOutput(cmd)
+}
+
+// watchersCommand returns a command that lists the watchers of a repository.
+func watchersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watchers REPOSITORY",
+		Short: "List the watchers of a repository",
+		Long: `List the users watching the pushes to a repository in the UI, and the key
+fingerprints of anonymous watchers. It's only available to the admins of the repository, others
+see the number of watchers in "repo info" and the UI.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfRepoAdmin,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			if _, err := be.Repository(ctx, args[0]); err != nil {
+				return err
+			}
+
+			watchers, err := be.Watchers(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			for _, w := range watchers {
+				cmd.Println(w)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -46,7 +46,8 @@ func webhookListCommand() *cobra.Command {
 		Use:               "list REPOSITORY",
 		Short:             "List repository webhooks",
 		Args:              cobra.ExactArgs(1),
-		PersistentPreRunE: checkIfAdmin,
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfRepoAdmin,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			be := backend.FromContext(ctx)
@@ -97,7 +98,8 @@ func webhookCreateCommand() *cobra.Command {
 		Use:               "create REPOSITORY URL",
 		Short:             "Create a repository webhook",
 		Args:              cobra.ExactArgs(2),
-		PersistentPreRunE: checkIfAdmin,
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfRepoAdmin,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			be := backend.FromContext(ctx)
@@ -110,7 +112,7 @@ func webhookCreateCommand() *cobra.Command {
 			for _, e := range events {
 				ev, err := webhook.ParseEvent(e)
 				if err != nil {
-					return fmt.Errorf("invalid event: %w", err)
+					return exitErrorf(ExitUsage, "invalid event: %w", err)
 				}
 
 				evs = append(evs, ev)
@@ -143,7 +145,8 @@ func webhookDeleteCommand() *cobra.Command {
 		Use:               "delete REPOSITORY WEBHOOK_ID",
 		Short:             "Delete a repository webhook",
 		Args:              cobra.ExactArgs(2),
-		PersistentPreRunE: checkIfAdmin,
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfRepoAdmin,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			be := backend.FromContext(ctx)
@@ -154,7 +157,7 @@ func webhookDeleteCommand() *cobra.Command {
 
 			id, err := strconv.ParseInt(args[1], 10, 64)
 			if err != nil {
-				return fmt.Errorf("invalid webhook ID: %w", err)
+				return exitErrorf(ExitUsage, "invalid webhook ID: %w", err)
 			}
 
 			return be.DeleteWebhook(ctx, repo, id)
@@ -174,7 +177,8 @@ func webhookUpdateCommand() *cobra.Command {
 		Use:               "update REPOSITORY WEBHOOK_ID",
 		Short:             "Update a repository webhook",
 		Args:              cobra.ExactArgs(2),
-		PersistentPreRunE: checkIfAdmin,
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfRepoAdmin,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			be := backend.FromContext(ctx)
@@ -185,7 +189,7 @@ func webhookUpdateCommand() *cobra.Command {
 
 			id, err := strconv.ParseInt(args[1], 10, 64)
 			if err != nil {
-				return fmt.Errorf("invalid webhook ID: %w", err)
+				return exitErrorf(ExitUsage, "invalid webhook ID: %w", err)
 			}
 
 			wh, err := be.Webhook(ctx, repo, id)
@@ -207,7 +211,7 @@ func webhookUpdateCommand() *cobra.Command {
 			if active != "" {
 				active, err := strconv.ParseBool(active)
 				if err != nil {
-					return fmt.Errorf("invalid active value: %w", err)
+					return exitErrorf(ExitUsage, "invalid active value: %w", err)
 				}
 
 				newActive = active
@@ -233,7 +237,7 @@ func webhookUpdateCommand() *cobra.Command {
 				for _, e := range events {
 					ev, err := webhook.ParseEvent(e)
 					if err != nil {
-						return fmt.Errorf("invalid event: %w", err)
+						return exitErrorf(ExitUsage, "invalid event: %w", err)
 					}
 
 					evs = append(evs, ev)
@@ -276,13 +280,14 @@ func webhookDeliveriesListCommand() *cobra.Command {
 		Use:               "list REPOSITORY WEBHOOK_ID",
 		Short:             "List webhook deliveries",
 		Args:              cobra.ExactArgs(2),
-		PersistentPreRunE: checkIfAdmin,
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfRepoAdmin,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			be := backend.FromContext(ctx)
 			id, err := strconv.ParseInt(args[1], 10, 64)
 			if err != nil {
-				return fmt.Errorf("invalid webhook ID: %w", err)
+				return exitErrorf(ExitUsage, "invalid webhook ID: %w", err)
 			}
 
 			dels, err := be.ListWebhookDeliveries(ctx, id)
@@ -318,7 +323,8 @@ func webhookDeliveriesRedeliverCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:               "redeliver REPOSITORY WEBHOOK_ID DELIVERY_ID",
 		Short:             "Redeliver a webhook delivery",
-		PersistentPreRunE: checkIfAdmin,
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfRepoAdmin,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			be := backend.FromContext(ctx)
@@ -329,12 +335,12 @@ func webhookDeliveriesRedeliverCommand() *cobra.Command {
 
 			id, err := strconv.ParseInt(args[1], 10, 64)
 			if err != nil {
-				return fmt.Errorf("invalid webhook ID: %w", err)
+				return exitErrorf(ExitUsage, "invalid webhook ID: %w", err)
 			}
 
 			delID, err := uuid.Parse(args[2])
 			if err != nil {
-				return fmt.Errorf("invalid delivery ID: %w", err)
+				return exitErrorf(ExitUsage, "invalid delivery ID: %w", err)
 			}
 
 			return be.RedeliverWebhookDelivery(ctx, repo, id, delID)
@@ -348,18 +354,19 @@ func webhookDeliveriesGetCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:               "get REPOSITORY WEBHOOK_ID DELIVERY_ID",
 		Short:             "Get a webhook delivery",
-		PersistentPreRunE: checkIfAdmin,
+		ValidArgsFunction: completeRepo(),
+		PersistentPreRunE: checkIfRepoAdmin,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			be := backend.FromContext(ctx)
 			id, err := strconv.ParseInt(args[1], 10, 64)
 			if err != nil {
-				return fmt.Errorf("invalid webhook ID: %w", err)
+				return exitErrorf(ExitUsage, "invalid webhook ID: %w", err)
 			}
 
 			delID, err := uuid.Parse(args[2])
 			if err != nil {
-				return fmt.Errorf("invalid delivery ID: %w", err)
+				return exitErrorf(ExitUsage, "invalid delivery ID: %w", err)
 			}
 
 			del, err := be.WebhookDelivery(ctx, id, delID)
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"github.com/spf13/cobra"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// WhoamiCommand returns a command that shows how the server identifies the
+// current connection.
+func WhoamiCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "whoami",
+		Short: "Show how the server identifies you",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			be := backend.FromContext(ctx)
+			cfg := config.FromContext(ctx)
+			pk := sshutils.PublicKeyFromContext(ctx)
+			user := proto.UserFromContext(ctx)
+
+			if cert, ok := pk.(*gossh.Certificate); ok {
+				cmd.Printf("Public key: %s\n", gossh.FingerprintSHA256(cert.Key))
+				cmd.Printf("Certificate: %q serial %d\n", cert.KeyId, cert.Serial)
+				cmd.Printf("Certificate authority: %s\n", gossh.FingerprintSHA256(cert.SignatureKey))
+				if len(cert.ValidPrincipals) > 0 {
+					cmd.Printf("Principals: %s\n", strings.Join(cert.ValidPrincipals, ", "))
+				}
+				if cert.ValidBefore != gossh.CertTimeInfinity {
+					cmd.Printf("Valid until: %s\n", time.Unix(int64(cert.ValidBefore), 0).UTC().Format(time.RFC3339))
+				}
+			} else if pk != nil {
+				cmd.Printf("Public key: %s\n", gossh.FingerprintSHA256(pk))
+			} else {
+				cmd.Printf("Public key: none\n")
+			}
+
+			if user != nil {
+				cmd.Printf("Username: %s\n", user.Username())
+				if name := user.Name(); name != "" {
+					cmd.Printf("Name: %s\n", name)
+				}
+				cmd.Printf("Admin: %t\n", user.IsAdmin() || IsPublicKeyAdmin(cfg, pk))
+				teams, err := be.UserTeams(ctx, user)
+				if err != nil {
+					return err
+				}
+				if len(teams) > 0 {
+					cmd.Printf("Teams: %s\n", strings.Join(teams, ", "))
+				}
+			} else {
+				cmd.Printf("Username: anonymous\n")
+				cmd.Printf("Admin: %t\n", pk != nil && IsPublicKeyAdmin(cfg, pk))
+			}
+
+			repos, err := be.Repositories(ctx)
+			if err != nil {
+				return err
+			}
+
+			var readable, writable int
+			for _, r := range repos {
+				switch auth := be.AccessLevelForUser(ctx, r.Name(), user); {
+				case auth >= access.ReadWriteAccess:
+					writable++
+					fallthrough
+				case auth >= access.ReadOnlyAccess:
+					readable++
+				}
+			}
+
+			cmd.Printf("Repositories: %d readable, %d writable\n", readable, writable)
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,178 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/charmbracelet/ssh"
+)
+
+// deepLink is the repository, and optionally the tab and the reference, the
+// UI opens on. path is the file or directory the Files tab opens, with the
+// lines from start to end of the file selected. notice is shown on the
+// selection page instead when the link can't be opened.
+type deepLink struct {
+	repo   string
+	tab    string
+	ref    string
+	path   string
+	start  int
+	end    int
+	notice string
+}
+
+// parseDeepLink parses a link to a repository the user can read. Links are
+// the path of the repository, optionally followed by the name of a tab and a
+// reference, e.g. "repo/commits/main". Clone URLs like
+// "ssh://host/repo.git" work too. Repositories can be nested, so the longest
+// leading path that names a repository is the repository. The reference is
+// the full name of a reference, or the name of a branch or tag.
+//
+// Links to the files tab can also name a commit instead, and a path with a
+// range of lines, like permalinks e.g. "repo/blob/<sha>/main.go#L10-L20".
+// "blob" and "tree" are the files tab.
+func parseDeepLink(ctx context.Context, be *backend.Backend, pk ssh.PublicKey, link string) (deepLink, error) {
+	p, lines, _ := strings.Cut(link, "#")
+	if i := strings.Index(p, "://"); i >= 0 {
+		// Drop the scheme and the host.
+		p = p[i+len("://"):]
+		if j := strings.Index(p, "/"); j >= 0 {
+			p = p[j:]
+		} else {
+			p = ""
+		}
+	}
+	parts := strings.FieldsFunc(p, func(r rune) bool { return r == '/' })
+	if len(parts) == 0 {
+		return deepLink{}, nil
+	}
+
+	var repo proto.Repository
+	var dl deepLink
+	for i := len(parts); i > 0; i-- {
+		name := utils.SanitizeRepo(strings.Join(parts[:i], "/"))
+		if be.AccessLevelByPublicKey(ctx, name, pk) < access.ReadOnlyAccess {
+			continue
+		}
+		r, err := be.Repository(ctx, name)
+		if err != nil {
+			continue
+		}
+		repo = r
+		dl.repo = r.Name()
+		parts = parts[i:]
+		break
+	}
+	if repo == nil {
+		// Don't tell repositories the user can't read apart from missing
+		// ones.
+		return deepLink{}, proto.ErrRepoNotFound
+	}
+	if len(parts) == 0 {
+		return dl, nil
+	}
+
+	tab := parts[0]
+	if tab == "blob" || tab == "tree" {
+		tab = "files"
+	}
+	tab, err := common.ParseLandingTab(tab)
+	if err != nil {
+		return deepLink{}, err
+	}
+	dl.tab = tab
+	if len(parts) == 1 {
+		return dl, nil
+	}
+
+	if tab != "files" {
+		ref, err := linkReference(repo, strings.Join(parts[1:], "/"))
+		if err != nil {
+			return deepLink{}, err
+		}
+		dl.ref = ref
+		return dl, nil
+	}
+
+	dl.ref, dl.path, err = linkFile(repo, parts[1:])
+	if err != nil {
+		return deepLink{}, err
+	}
+	if lines != "" {
+		if dl.path == "" {
+			return deepLink{}, fmt.Errorf("line range %q without a file", lines)
+		}
+		dl.start, dl.end, err = parseLineRange(lines)
+		if err != nil {
+			return deepLink{}, err
+		}
+	}
+	return dl, nil
+}
+
+// linkReference returns the full name of the reference of a link. The name is
+// a full reference name, or the name of a branch or tag.
+func linkReference(repo proto.Repository, name string) (string, error) {
+	r, err := repo.Open()
+	if err != nil {
+		return "", err
+	}
+	full, err := r.ReferenceFullName(name)
+	if errors.Is(err, git.ErrReferenceNotExist) {
+		return "", fmt.Errorf("reference %q not found", name)
+	}
+	return full, err
+}
+
+// linkFile returns the reference and the path of a link to the files tab,
+// the parts of the link after the tab. Reference names can have slashes too,
+// so the longest leading path that names a reference is the reference. A
+// commit hash, full or abbreviated, is the full hash of the commit.
+func linkFile(repo proto.Repository, parts []string) (string, string, error) {
+	r, err := repo.Open()
+	if err != nil {
+		return "", "", err
+	}
+	for i := len(parts); i > 0; i-- {
+		full, err := r.ReferenceFullName(strings.Join(parts[:i], "/"))
+		if err == nil {
+			return full, strings.Join(parts[i:], "/"), nil
+		}
+		if !errors.Is(err, git.ErrReferenceNotExist) {
+			return "", "", err
+		}
+	}
+	rev := parts[0]
+	if !strings.HasPrefix(rev, "-") {
+		if id, err := r.RevParse(rev + "^{commit}"); err == nil && strings.HasPrefix(id, rev) {
+			return id, strings.Join(parts[1:], "/"), nil
+		}
+	}
+	return "", "", fmt.Errorf("reference %q not found", rev)
+}
+
+// parseLineRange parses the line range of a link, "L10" or "L10-L20".
+func parseLineRange(s string) (int, int, error) {
+	from, to, isRange := strings.Cut(s, "-")
+	start, err := strconv.Atoi(strings.TrimPrefix(from, "L"))
+	if err != nil || start < 1 || !strings.HasPrefix(from, "L") {
+		return 0, 0, fmt.Errorf("invalid line range %q", s)
+	}
+	if !isRange {
+		return start, start, nil
+	}
+	end, err := strconv.Atoi(strings.TrimPrefix(to, "L"))
+	if err != nil || end < start {
+		return 0, 0, fmt.Errorf("invalid line range %q", s)
+	}
+	return start, end, nil
+}
@@ -0,0 +1,25 @@
+package ssh
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestParseLineRange(t *testing.T) {
+	is := is.New(t)
+	for s, want := range map[string][2]int{
+		"L10":     {10, 10},
+		"L10-L20": {10, 20},
+		"L10-20":  {10, 20},
+	} {
+		start, end, err := parseLineRange(s)
+		is.NoErr(err)
+		is.Equal([2]int{start, end}, want)
+	}
+
+	for _, s := range []string{"10", "L0", "L-1", "L20-L10", "L1-", "Lx"} {
+		_, _, err := parseLineRange(s)
+		is.True(err != nil) // invalid range
+	}
+}
@@ -0,0 +1,145 @@
+package ssh
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/ui/pages/repo"
+)
+
+// historyLimit is the number of locations the navigation history keeps.
+const historyLimit = 100
+
+// location is a place of the UI the navigation history goes back to. The
+// repository is empty on the repository list.
+type location struct {
+	repo string
+	ref  string
+	repo.Location
+}
+
+// sameView returns true if both locations show the same thing, regardless of
+// how it's scrolled.
+func (l location) sameView(o location) bool {
+	return l.repo == o.repo && l.ref == o.ref && l.Tab == o.Tab &&
+		l.Path == o.Path && l.Commit == o.Commit
+}
+
+// navHistory is the navigation history of a session, the locations the user
+// went through, oldest first. cursor is the current one.
+type navHistory struct {
+	locations []location
+	cursor    int
+
+	// target is the location being restored. The locations the UI goes
+	// through until it gets there aren't recorded.
+	target *location
+}
+
+// record records the current location. It's added after the current one,
+// replacing the locations that were gone back from, unless it shows the same
+// thing, e.g. a file that was scrolled. It returns true if it was added.
+func (h *navHistory) record(loc location, limit int) bool {
+	if t := h.target; t != nil {
+		if !loc.sameView(*t) {
+			return false
+		}
+		h.target = nil
+	}
+	if len(h.locations) > 0 && h.locations[h.cursor].sameView(loc) {
+		h.locations[h.cursor] = loc
+		return false
+	}
+	if len(h.locations) > 0 {
+		h.locations = h.locations[:h.cursor+1]
+	}
+	h.locations = append(h.locations, loc)
+	if n := len(h.locations) - limit; n > 0 {
+		h.locations = h.locations[n:]
+	}
+	h.cursor = len(h.locations) - 1
+	return true
+}
+
+// move moves the cursor by n locations and returns the location to restore.
+// It returns false at either end of the history.
+func (h *navHistory) move(n int) (location, bool) {
+	i := h.cursor + n
+	if len(h.locations) == 0 || i < 0 || i >= len(h.locations) {
+		return location{}, false
+	}
+	h.cursor = i
+	loc := h.locations[i]
+	h.target = &loc
+	return loc, true
+}
+
+// settle stops restoring a location the UI didn't get to, e.g. a file that
+// doesn't exist anymore. The current location takes its place.
+func (h *navHistory) settle(loc location, ok bool) {
+	if h.target == nil {
+		return
+	}
+	h.target = nil
+	if ok && len(h.locations) > 0 {
+		h.locations[h.cursor] = loc
+	}
+}
+
+// location returns the current location of the UI. It's false while the
+// repository page is loading.
+func (ui *UI) location() (location, bool) {
+	if ui.activePage != repoPage {
+		return location{}, true
+	}
+	rn := ui.currentRepo()
+	ref := ui.refs[rn]
+	if rn == "" || ref == "" {
+		return location{}, false
+	}
+	loc, ok := ui.pages[repoPage].(*repo.Repo).Location()
+	if !ok {
+		return location{}, false
+	}
+	return location{repo: rn, ref: ref, Location: loc}, true
+}
+
+// recordLocation records the current location in the navigation history.
+func (ui *UI) recordLocation() {
+	if ui.state != readyState {
+		return
+	}
+	if loc, ok := ui.location(); ok {
+		ui.history.record(loc, historyLimit)
+	}
+}
+
+// goHistory goes back, or forward, n locations in the navigation history.
+func (ui *UI) goHistory(n int) tea.Cmd {
+	// Leave the location that is being restored where the UI got to.
+	ui.history.settle(ui.location())
+	loc, ok := ui.history.move(n)
+	if !ok {
+		return nil
+	}
+	if loc.repo == "" {
+		ui.activePage = selectionPage
+		// Always show the footer on selection page.
+		ui.showFooter = true
+		return nil
+	}
+	if loc.repo == ui.currentRepo() && loc.ref == ui.refs[loc.repo] {
+		return repo.GoToLocationCmd(loc.Location)
+	}
+	msg := repoRefMsg{ref: loc.ref, tab: loc.Tab, commit: loc.Commit, exactTab: true}
+	if loc.Path != "" {
+		msg.file = &fileLink{ref: loc.ref, path: loc.Path, start: loc.Line}
+	}
+	return func() tea.Msg {
+		r, err := ui.openRepo(loc.repo)
+		if err != nil {
+			return common.ErrorMsg(err)
+		}
+		msg.repo = r
+		return msg
+	}
+}
@@ -0,0 +1,58 @@
+package ssh
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/soft-serve/pkg/ui/pages/repo"
+	"github.com/matryer/is"
+)
+
+func TestNavHistory(t *testing.T) {
+	is := is.New(t)
+	list := location{}
+	readme := location{repo: "a", ref: "refs/heads/main", Location: repo.Location{Tab: "readme"}}
+	file := location{repo: "a", ref: "refs/heads/main", Location: repo.Location{Tab: "files", Path: "main.go"}}
+	commit := location{repo: "a", ref: "refs/heads/main", Location: repo.Location{Tab: "commits", Commit: "abc"}}
+
+	var h navHistory
+	is.True(h.record(list, 3))
+	is.True(h.record(readme, 3))
+	is.True(h.record(file, 3))
+
+	// Scrolling updates the current location.
+	scrolled := file
+	scrolled.Line = 10
+	is.True(!h.record(scrolled, 3))
+	is.Equal(h.locations, []location{list, readme, scrolled})
+
+	loc, ok := h.move(-1)
+	is.True(ok)
+	is.Equal(loc, readme)
+
+	// Nothing is recorded until the location is restored.
+	is.True(!h.record(list, 3))
+	is.True(!h.record(readme, 3))
+	loc, ok = h.move(1)
+	is.True(ok)
+	is.Equal(loc, scrolled)
+	is.True(!h.record(file, 3))
+	_, ok = h.move(1)
+	is.True(!ok)
+
+	// Going somewhere else drops the locations that were gone back from,
+	// and the oldest one.
+	h.settle(file, true)
+	h.move(-1)
+	h.settle(readme, true)
+	is.True(h.record(commit, 3))
+	is.Equal(h.locations, []location{list, readme, commit})
+	is.True(h.record(file, 3))
+	is.Equal(h.locations, []location{readme, commit, file})
+	is.Equal(h.cursor, 2)
+
+	// A location that isn't reached is replaced by the one the UI got to.
+	h.move(-2)
+	h.settle(list, true)
+	is.Equal(h.locations[0], list)
+	is.True(h.target == nil)
+}
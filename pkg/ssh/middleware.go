@@ -1,15 +1,21 @@
 package ssh
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/anmitsu/go-shlex"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
 	"github.com/charmbracelet/soft-serve/pkg/backend"
 	"github.com/charmbracelet/soft-serve/pkg/config"
 	"github.com/charmbracelet/soft-serve/pkg/db"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sessions"
 	"github.com/charmbracelet/soft-serve/pkg/ssh/cmd"
 	"github.com/charmbracelet/soft-serve/pkg/sshutils"
 	"github.com/charmbracelet/soft-serve/pkg/store"
@@ -65,7 +71,43 @@ func ContextMiddleware(cfg *config.Config, dbx *db.DB, datastore store.Store, be
 			ctx.SetValue(db.ContextKey, dbx)
 			ctx.SetValue(store.ContextKey, datastore)
 			ctx.SetValue(backend.ContextKey, be)
-			ctx.SetValue(log.ContextKey, logger.WithPrefix("ssh"))
+			ctx.SetValue(log.ContextKey, logger.WithPrefix("ssh").With("session", sessionID(ctx.SessionID())))
+			sh(s)
+		}
+	}
+}
+
+// SessionsMiddleware keeps track of the active sessions so that admins can
+// list and terminate them. This middleware must be run after the
+// ContextMiddleware.
+func SessionsMiddleware(reg *sessions.Registry) func(ssh.Handler) ssh.Handler {
+	return func(sh ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			ctx := s.Context()
+			var username, fp string
+			if user := proto.UserFromContext(ctx); user != nil {
+				username = user.Username()
+			}
+			if pk := s.PublicKey(); pk != nil {
+				fp = gossh.FingerprintSHA256(pk)
+			}
+
+			sess := reg.Add(username, fp, s.RemoteAddr().String(), func() error {
+				// Close the connection, not only the channel, so that the UI
+				// and git operations stop right away.
+				if conn, ok := ctx.Value(ssh.ContextKeyConn).(gossh.Conn); ok {
+					return conn.Close()
+				}
+				return s.Close()
+			})
+			defer reg.Remove(sess.ID)
+
+			if _, _, ptyReq := s.Pty(); ptyReq && len(s.Command()) < 2 {
+				sess.SetActivity("tui")
+			}
+
+			ctx.SetValue(sessions.ContextKey, reg)
+			ctx.SetValue(sessions.SessionContextKey, sess)
 			sh(s)
 		}
 	}
@@ -82,72 +124,184 @@ var cliCommandCounter = promauto.NewCounterVec(prometheus.CounterOpts{
 // This middleware must be run after the ContextMiddleware.
 func CommandMiddleware(sh ssh.Handler) ssh.Handler {
 	return func(s ssh.Session) {
+		// Keys with a forced command run it whatever the session asks for.
+		args := cmd.CompletionArgs(s.RawCommand(), s.Command())
+		opts := proto.KeyOptionsFromContext(s.Context())
+		if opts.Command != "" {
+			var err error
+			args, err = shlex.Split(opts.Command, true)
+			if err != nil || len(args) == 0 {
+				log.FromContext(s.Context()).Error("invalid forced command", "command", opts.Command, "err", err)
+				wish.Fatalln(s, ErrPermissionDenied)
+				return
+			}
+			log.FromContext(s.Context()).Debug("forced command", "command", cmd.CommandName(args), "requested", cmd.CommandName(s.Command()))
+		}
+
+		// Interactive sessions run the UI unless they ask for a command, a
+		// single argument is the repository to open in the UI.
 		_, _, ptyReq := s.Pty()
-		if ptyReq {
+		if ptyReq && len(s.Command()) < 2 && opts.Command == "" {
+			if opts.NoPTY {
+				wish.Fatalln(s, "Error: this key can't open interactive sessions")
+				return
+			}
+			if len(s.Command()) == 0 && interactiveMode(s) == config.InteractiveShell {
+				runShell(s, sh)
+				return
+			}
 			sh(s)
 			return
 		}
 
-		ctx := s.Context()
-		cfg := config.FromContext(ctx)
-
 		renderer := bm.MakeRenderer(s)
 		if testrun, ok := os.LookupEnv("SOFT_SERVE_NO_COLOR"); ok && testrun == "1" {
 			// Disable colors when running tests.
 			renderer.SetColorProfile(termenv.Ascii)
 		}
 
-		args := s.Command()
-		cliCommandCounter.WithLabelValues(cmd.CommandName(args)).Inc()
-		rootCmd := &cobra.Command{
-			Short:        "Soft Serve is a self-hostable Git server for the command line.",
-			SilenceUsage: true,
+		if code := runCommand(s, renderer, args, s, s, s.Stderr()); code != 0 {
+			s.Exit(code) // nolint: errcheck
 		}
-		rootCmd.CompletionOptions.DisableDefaultCmd = true
+	}
+}
 
-		rootCmd.SetUsageTemplate(cmd.UsageTemplate)
-		rootCmd.SetUsageFunc(cmd.UsageFunc)
+// newRootCommand returns the root command of the CLI commands of the session.
+func newRootCommand(ctx context.Context, renderer *lipgloss.Renderer) *cobra.Command {
+	cfg := config.FromContext(ctx)
+	rootCmd := &cobra.Command{
+		Short:        "Soft Serve is a self-hostable Git server for the command line.",
+		SilenceUsage: true,
+	}
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+
+	rootCmd.SetUsageTemplate(cmd.UsageTemplate)
+	rootCmd.SetUsageFunc(cmd.UsageFunc)
+	rootCmd.AddCommand(
+		cmd.GitUploadPackCommand(),
+		cmd.GitUploadArchiveCommand(),
+		cmd.GitReceivePackCommand(),
+		cmd.RepoCommand(renderer),
+		cmd.SettingsCommand(),
+		cmd.UserCommand(),
+		cmd.TeamCommand(),
+		cmd.InfoCommand(),
+		cmd.WhoamiCommand(),
+		cmd.PubkeyCommand(),
+		cmd.SetUsernameCommand(),
+		cmd.JWTCommand(),
+		cmd.TokenCommand(),
+		cmd.AliasCommand(),
+		cmd.PrefsCommand(),
+		cmd.AdminCommand(),
+	)
+
+	if cfg.LFS.Enabled {
 		rootCmd.AddCommand(
-			cmd.GitUploadPackCommand(),
-			cmd.GitUploadArchiveCommand(),
-			cmd.GitReceivePackCommand(),
-			cmd.RepoCommand(renderer),
-			cmd.SettingsCommand(),
-			cmd.UserCommand(),
-			cmd.InfoCommand(),
-			cmd.PubkeyCommand(),
-			cmd.SetUsernameCommand(),
-			cmd.JWTCommand(),
-			cmd.TokenCommand(),
+			cmd.GitLFSAuthenticateCommand(),
 		)
 
-		if cfg.LFS.Enabled {
+		if cfg.LFS.SSHEnabled {
 			rootCmd.AddCommand(
-				cmd.GitLFSAuthenticateCommand(),
+				cmd.GitLFSTransfer(),
 			)
+		}
+	}
 
-			if cfg.LFS.SSHEnabled {
-				rootCmd.AddCommand(
-					cmd.GitLFSTransfer(),
-				)
-			}
+	return rootCmd
+}
+
+// pagerMode returns when the output of the commands of the session is paged,
+// the preference of its public key or the server default.
+func pagerMode(s ssh.Session) string {
+	ctx := s.Context()
+	if pk := s.PublicKey(); pk != nil {
+		be := backend.FromContext(ctx)
+		if v, err := be.Preference(ctx, pk, cmd.PagerPreference); err == nil && v != "" {
+			return v
 		}
+	}
+	return config.FromContext(ctx).SSH.Pager
+}
 
-		rootCmd.SetArgs(args)
-		if len(args) == 0 {
-			// otherwise it'll default to os.Args, which is not what we want.
-			rootCmd.SetArgs([]string{"--help"})
+// runCommand runs a CLI command of the session and returns its exit code.
+func runCommand(s ssh.Session, renderer *lipgloss.Renderer, args []string, in io.Reader, out, errOut io.Writer) (code int) {
+	ctx := s.Context()
+	logger := log.FromContext(ctx)
+	name := cmd.CommandName(args)
+	cliCommandCounter.WithLabelValues(name).Inc()
+	rootCmd := newRootCommand(ctx, renderer)
+
+	var err error
+	start := time.Now()
+	defer func() {
+		if code == cmd.ExitError {
+			logger.Error("command failed", "command", name, "err", err)
 		}
-		rootCmd.SetIn(s)
-		rootCmd.SetOut(s)
-		rootCmd.SetErr(s.Stderr())
-		rootCmd.SetContext(ctx)
+		logger.Debug("command finished", "command", name, "code", code, "duration", time.Since(start))
+	}()
 
-		if err := rootCmd.ExecuteContext(ctx); err != nil {
-			s.Exit(1) // nolint: errcheck
-			return
+	args, err = cmd.ExpandAliases(ctx, rootCmd, args)
+	if err != nil {
+		fmt.Fprintln(errOut, "Error:", err)
+		return cmd.ExitCode(err)
+	}
+
+	// Log the path of the command, its arguments may hold secrets.
+	name = commandActivity(rootCmd, args)
+	logger.Debug("command", "command", name)
+
+	if c, _, ferr := rootCmd.Find(args); ferr == nil && c != rootCmd {
+		if err = cmd.CheckCommandAccess(ctx, strings.TrimSpace(c.CommandPath())); err != nil {
+			logger.Info("command denied", "command", name)
+			fmt.Fprintln(errOut, "Error:", err)
+			return cmd.ExitCode(err)
+		}
+	}
+	if sess := sessions.SessionFromContext(ctx); sess != nil {
+		sess.SetActivity(name)
+	}
+
+	// The output of interactive sessions is paged once the command is done,
+	// unless it's streamed.
+	var cmdCtx context.Context = ctx
+	var pager *cmd.Pager
+	if _, _, isPty := s.Pty(); isPty {
+		if c, _, ferr := rootCmd.Find(args); ferr != nil || !cmd.IsStreamed(c) {
+			pager = cmd.NewPager(s, pagerMode(s), out)
+			out = pager
+			cmdCtx = cmd.WithPager(ctx, pager)
 		}
 	}
+
+	rootCmd.SetArgs(args)
+	if len(args) == 0 {
+		// otherwise it'll default to os.Args, which is not what we want.
+		rootCmd.SetArgs([]string{"--help"})
+	}
+	rootCmd.SetIn(in)
+	rootCmd.SetOut(out)
+	rootCmd.SetErr(errOut)
+	rootCmd.SetContext(cmdCtx)
+	cmd.SetUsageErrors(rootCmd)
+
+	var c *cobra.Command
+	c, err = rootCmd.ExecuteContextC(cmdCtx)
+	if pager != nil {
+		if perr := pager.Flush(); perr != nil {
+			logger.Debug("failed to page the output", "command", name, "err", perr)
+		}
+	}
+	if err != nil {
+		if c == rootCmd {
+			// The root command isn't runnable, so its errors are unknown
+			// commands or flags.
+			return cmd.ExitUsage
+		}
+		return cmd.ExitCode(err)
+	}
+
+	return 0
 }
 
 // LoggingMiddleware logs the ssh connection and command.
@@ -189,9 +343,31 @@ func LoggingMiddleware(sh ssh.Handler) ssh.Handler {
 			)
 		}
 
+		if cert, ok := s.PublicKey().(*gossh.Certificate); ok {
+			logArgs = append(logArgs,
+				"cert-id", cert.KeyId,
+				"cert-serial", cert.Serial,
+			)
+		}
+
 		msg := fmt.Sprintf("user %q", s.User())
 		logger.Debug(msg+" connected", logArgs...)
 		sh(s)
 		logger.Debug(msg+" disconnected", append(logArgs, "duration", time.Since(ct))...)
 	}
 }
+
+// commandActivity returns the activity of a session that runs a command. It's
+// the path of the command without its arguments, which may hold secrets, and
+// the repository of git operations.
+func commandActivity(root *cobra.Command, args []string) string {
+	c, rest, err := root.Find(args)
+	if err != nil || c == root {
+		return cmd.CommandName(args)
+	}
+	activity := strings.TrimSpace(c.CommandPath())
+	if strings.HasPrefix(c.Name(), "git-") && len(rest) > 0 {
+		activity += " " + rest[0]
+	}
+	return activity
+}
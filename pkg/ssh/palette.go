@@ -0,0 +1,214 @@
+package ssh
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+)
+
+var (
+	paletteUp = key.NewBinding(
+		key.WithKeys("up", "ctrl+p"),
+		key.WithHelp("↑", "up"),
+	)
+	paletteDown = key.NewBinding(
+		key.WithKeys("down", "ctrl+n"),
+		key.WithHelp("↓", "down"),
+	)
+	paletteRun = key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "run"),
+	)
+	paletteClose = key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "close"),
+	)
+)
+
+// paletteHeight is the number of actions the command palette shows at once.
+const paletteHeight = 10
+
+// commandPalette lists the actions of the current context, the key bindings
+// of the help, filtered as the user types. Running one presses its key.
+type commandPalette struct {
+	input   textinput.Model
+	actions []key.Binding
+	// matches are the indexes of the actions that match the input, the best
+	// match first.
+	matches []int
+	cursor  int
+}
+
+// paletteActions returns the actions of the command palette: the enabled key
+// bindings of the full help, which only has the ones that are valid for the
+// active page and tab, and the access level of the user. Summaries of several
+// bindings, like "↑/↓", are left out, as they aren't one action.
+func (ui *UI) paletteActions() []key.Binding {
+	actions := make([]key.Binding, 0)
+	seen := map[string]bool{}
+	for _, col := range ui.FullHelp() {
+		for _, b := range col {
+			h := b.Help()
+			if !b.Enabled() || h.Desc == "" || len(b.Keys()) == 0 {
+				continue
+			}
+			summary := len(h.Key) > 1 && strings.Contains(h.Key, "/")
+			if summary || key.Matches(keyMsg(b.Keys()[0]), ui.common.KeyMap.CommandPalette) {
+				continue
+			}
+			if id := h.Key + " " + h.Desc; !seen[id] {
+				seen[id] = true
+				actions = append(actions, b)
+			}
+		}
+	}
+	return actions
+}
+
+// openPalette opens the command palette with the actions of the current
+// context.
+func (ui *UI) openPalette() tea.Cmd {
+	ti := textinput.New()
+	ti.Prompt = "> "
+	ti.Placeholder = "Type to filter actions"
+	ti.PromptStyle = ui.common.Styles.RepoSelector.Active.Title
+	ti.PlaceholderStyle = ui.common.Styles.RepoSelector.Normal.Desc.Faint(true)
+	p := &commandPalette{input: ti, actions: ui.paletteActions()}
+	p.filter()
+	ui.palette = p
+	return ui.palette.input.Focus()
+}
+
+// filter matches the actions with the input, fuzzily on their description and
+// key.
+func (p *commandPalette) filter() {
+	p.cursor = 0
+	p.matches = p.matches[:0]
+	term := strings.TrimSpace(p.input.Value())
+	if term == "" {
+		for i := range p.actions {
+			p.matches = append(p.matches, i)
+		}
+		return
+	}
+	targets := make([]string, len(p.actions))
+	for i, b := range p.actions {
+		targets[i] = b.Help().Desc + " " + b.Help().Key
+	}
+	for _, rank := range list.DefaultFilter(term, targets) {
+		p.matches = append(p.matches, rank.Index)
+	}
+}
+
+// updatePalette handles key presses while the command palette is open.
+func (ui *UI) updatePalette(msg tea.KeyMsg) tea.Cmd {
+	p := ui.palette
+	switch {
+	case key.Matches(msg, paletteClose), key.Matches(msg, ui.common.KeyMap.CommandPalette):
+		ui.palette = nil
+	case key.Matches(msg, paletteUp):
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case key.Matches(msg, paletteDown):
+		if p.cursor < len(p.matches)-1 {
+			p.cursor++
+		}
+	case key.Matches(msg, paletteRun):
+		ui.palette = nil
+		if len(p.matches) == 0 {
+			return nil
+		}
+		km := keyMsg(p.actions[p.matches[p.cursor]].Keys()[0])
+		return func() tea.Msg { return km }
+	default:
+		var cmd tea.Cmd
+		value := p.input.Value()
+		p.input, cmd = p.input.Update(msg)
+		if p.input.Value() != value {
+			p.filter()
+		}
+		return cmd
+	}
+	return nil
+}
+
+// paletteHelp returns the key bindings of the command palette.
+func (ui *UI) paletteHelp() []key.Binding {
+	return []key.Binding{paletteUp, paletteDown, paletteRun, paletteClose}
+}
+
+// renderPalette renders the command palette at the top of the page.
+func (ui *UI) renderPalette(width, height int) string {
+	st := ui.common.Styles.RepoSelector
+	p := ui.palette
+	boxWidth := min(width-4, 60)
+	p.input.Width = boxWidth - 6
+	var sb strings.Builder
+	sb.WriteString(st.Normal.Title.Render("Commands"))
+	sb.WriteString("\n")
+	sb.WriteString(p.input.View())
+	sb.WriteString("\n")
+	if len(p.matches) == 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.Normal.Desc.Render("  No matching actions"))
+	}
+	// Scroll to keep the cursor in view.
+	start := max(0, p.cursor-paletteHeight+1)
+	end := min(len(p.matches), start+paletteHeight)
+	for i := start; i < end; i++ {
+		h := p.actions[p.matches[i]].Help()
+		keys := boxWidth - 6 - lipgloss.Width(h.Desc)
+		line := common.TruncateString(h.Desc, boxWidth-6)
+		if keys > len(h.Key) {
+			line += fmt.Sprintf("%*s", keys, h.Key)
+		}
+		sb.WriteString("\n")
+		if i == p.cursor {
+			sb.WriteString(st.Active.Title.Render("> " + line))
+		} else {
+			sb.WriteString(st.Normal.Desc.Render("  " + line))
+		}
+	}
+	box := ui.common.Renderer.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.common.Styles.InactiveBorderColor).
+		Padding(0, 1).
+		Width(boxWidth).
+		Render(sb.String())
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Top, box)
+}
+
+// keyNames are the key types by their name, e.g. "enter" or "ctrl+r".
+var keyNames = func() map[string]tea.KeyType {
+	names := map[string]tea.KeyType{}
+	for t := tea.KeyType(-200); t < 200; t++ {
+		if s := t.String(); s != "" && t != tea.KeyRunes {
+			names[s] = t
+		}
+	}
+	return names
+}()
+
+// keyMsg returns the key press of a key binding's key, e.g. "enter", "ctrl+r",
+// "alt+enter", or "n".
+func keyMsg(k string) tea.KeyMsg {
+	var msg tea.KeyMsg
+	if rest, ok := strings.CutPrefix(k, "alt+"); ok && rest != "" {
+		msg.Alt = true
+		k = rest
+	}
+	if t, ok := keyNames[k]; ok {
+		msg.Type = t
+		return msg
+	}
+	msg.Type = tea.KeyRunes
+	msg.Runes = []rune(k)
+	return msg
+}
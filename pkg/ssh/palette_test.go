@@ -0,0 +1,40 @@
+package ssh
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/matryer/is"
+)
+
+func TestKeyMsg(t *testing.T) {
+	is := is.New(t)
+	// The key presses match the keys they're made from.
+	for _, k := range []string{"enter", "esc", "tab", "shift+tab", "ctrl+r", "up", "pgdown", "alt+enter", " ", "n", "N", "}", "/"} {
+		is.Equal(keyMsg(k).String(), k)
+	}
+}
+
+func TestPaletteFilter(t *testing.T) {
+	is := is.New(t)
+	p := &commandPalette{input: textinput.New(), actions: []key.Binding{
+		key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "copy clone command")),
+		key.NewBinding(key.WithKeys("W"), key.WithHelp("W", "watch")),
+		key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "switch tab")),
+	}}
+	p.filter()
+	is.Equal(p.matches, []int{0, 1, 2})
+
+	p.input.SetValue("swtab")
+	p.filter()
+	is.Equal(p.matches, []int{2})
+
+	p.input.SetValue("clone")
+	p.filter()
+	is.Equal(p.matches, []int{0})
+
+	p.input.SetValue("zzz")
+	p.filter()
+	is.Equal(len(p.matches), 0)
+}
@@ -0,0 +1,152 @@
+package ssh
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+)
+
+// pushPanelTimeout is how long the report of a push is shown.
+var pushPanelTimeout = 15 * time.Second
+
+// pushPanelLines is the number of lines of a push report shown, the others
+// are summed up.
+const pushPanelLines = 6
+
+// pushReportMsg is a message that contains the report of a push of the user.
+type pushReportMsg proto.PushReport
+
+// pushPanelExpiredMsg is a message to hide the push report with the given
+// sequence number.
+type pushPanelExpiredMsg int
+
+// pushPanel is the report of the last push of the user, shown above the
+// footer until it expires or is dismissed.
+type pushPanel struct {
+	report proto.PushReport
+	seq    int
+}
+
+// subscribePushReportsCmd subscribes to the reports of the pushes of the user,
+// unless they turned them off, and waits for the next one.
+func (ui *UI) subscribePushReportsCmd() tea.Cmd {
+	be, pk := ui.common.Backend(), ui.common.PublicKey()
+	if be == nil {
+		return nil
+	}
+	ctx := ui.common.Context()
+	if pk != nil {
+		v, err := be.Preference(ctx, pk, common.PushPanelPreference)
+		if err != nil {
+			ui.common.Logger.Debugf("ui: failed to load push panel preference: %v", err)
+		}
+		if v == "false" {
+			return nil
+		}
+	}
+	ui.pushReports = be.SubscribePushReports(ctx, ui.common.User(), pk)
+	return ui.nextPushReportCmd()
+}
+
+// nextPushReportCmd waits for the report of the next push of the user. It
+// returns nil once the session is closed.
+func (ui *UI) nextPushReportCmd() tea.Cmd {
+	reports := ui.pushReports
+	if reports == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		r, ok := <-reports
+		if !ok {
+			return nil
+		}
+		return pushReportMsg(r)
+	}
+}
+
+// showPushReport shows the report of a push until it expires.
+func (ui *UI) showPushReport(r pushReportMsg) tea.Cmd {
+	ui.pushSeq++
+	seq := ui.pushSeq
+	ui.pushPanel = &pushPanel{report: proto.PushReport(r), seq: seq}
+	return tea.Tick(pushPanelTimeout, func(time.Time) tea.Msg {
+		return pushPanelExpiredMsg(seq)
+	})
+}
+
+// pushPanelKey returns the key that dismisses the push report.
+func (ui *UI) pushPanelKey() key.Binding {
+	k := ui.common.KeyMap.Back
+	k.SetHelp("esc", "dismiss push")
+	return k
+}
+
+// pushPanelHeight returns the height of the push report, zero if it's not
+// shown.
+func (ui *UI) pushPanelHeight(width int) int {
+	if ui.pushPanel == nil {
+		return 0
+	}
+	return lipgloss.Height(ui.renderPushPanel(width))
+}
+
+// renderPushPanel renders the report of the last push: the status of the
+// references, the hook messages, and the error if the push failed.
+func (ui *UI) renderPushPanel(width int) string {
+	r := ui.pushPanel.report
+	st := ui.common.Styles
+	inner := max(width-4, 1)
+
+	var title string
+	switch n := r.Rejected(); {
+	case r.Err != "" || (r.Unpack != "" && r.Unpack != "ok"):
+		title = st.CommitStatus.Failure.Render(fmt.Sprintf("✗ Push to %s failed", r.Repo))
+	case n > 0:
+		title = st.CommitStatus.Failure.Render(fmt.Sprintf("✗ Push to %s: %d of %d refs rejected", r.Repo, n, len(r.Refs)))
+	default:
+		title = st.CommitStatus.Success.Render(fmt.Sprintf("✓ Pushed to %s", r.Repo))
+	}
+
+	lines := make([]string, 0, len(r.Refs)+len(r.Messages)+2)
+	for _, ref := range r.Refs {
+		name := git.ReferenceName(ref.Ref).Short()
+		if ref.OK {
+			lines = append(lines, st.CommitStatus.Success.Render("✓ ")+name)
+		} else {
+			lines = append(lines, st.CommitStatus.Failure.Render("✗ ")+name+st.RepoSelector.Normal.Desc.Render(" "+ref.Reason))
+		}
+	}
+	if r.Unpack != "" && r.Unpack != "ok" {
+		lines = append(lines, st.CommitStatus.Failure.Render("unpack: "+r.Unpack))
+	}
+	for _, msg := range r.Messages {
+		lines = append(lines, st.RepoSelector.Normal.Desc.Render("remote: "+msg))
+	}
+	if r.Err != "" {
+		lines = append(lines, st.CommitStatus.Failure.Render(r.Err))
+	}
+	if len(lines) > pushPanelLines {
+		more := len(lines) - pushPanelLines + 1
+		lines = append(lines[:pushPanelLines-1], st.RepoSelector.Normal.Desc.Render(fmt.Sprintf("… and %d more lines", more)))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(common.TruncateString(title, inner))
+	for _, line := range lines {
+		sb.WriteString("\n")
+		sb.WriteString(common.TruncateString(line, inner))
+	}
+	return ui.common.Renderer.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.common.Styles.InactiveBorderColor).
+		Padding(0, 1).
+		Width(max(width-2, 1)).
+		Render(sb.String())
+}
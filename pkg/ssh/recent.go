@@ -0,0 +1,126 @@
+package ssh
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+)
+
+// recentSwitcher is the quick switcher of the recently viewed repositories.
+type recentSwitcher struct {
+	cursor int
+}
+
+// addRecent moves the repository to the front of the recently viewed
+// repositories and keeps at most limit of them.
+func addRecent(recent []string, name string, limit int) []string {
+	if limit <= 0 {
+		return recent[:0]
+	}
+	names := make([]string, 0, len(recent)+1)
+	names = append(names, name)
+	for _, n := range recent {
+		if n != name {
+			names = append(names, n)
+		}
+	}
+	if len(names) > limit {
+		names = names[:limit]
+	}
+	return names
+}
+
+// recentLimit returns the number of recently viewed repositories to keep.
+func (ui *UI) recentLimit() int {
+	cfg := ui.common.Config()
+	if cfg == nil {
+		return 0
+	}
+	return cfg.UI.RecentRepos
+}
+
+// currentRepo returns the name of the repository that is shown, if any. It's
+// always the most recently viewed one.
+func (ui *UI) currentRepo() string {
+	if ui.activePage != repoPage || len(ui.recent) == 0 {
+		return ""
+	}
+	return ui.recent[0]
+}
+
+// openSwitcher opens the quick switcher. The cursor starts on the previous
+// repository so that ctrl+r and enter hop back and forth between two of them.
+func (ui *UI) openSwitcher() {
+	sw := &recentSwitcher{}
+	if len(ui.recent) > 1 && ui.recent[0] == ui.currentRepo() {
+		sw.cursor = 1
+	}
+	ui.switcher = sw
+}
+
+// updateSwitcher handles key presses while the quick switcher is open.
+func (ui *UI) updateSwitcher(msg tea.KeyMsg) tea.Cmd {
+	sw := ui.switcher
+	switch {
+	case key.Matches(msg, ui.common.KeyMap.Back),
+		key.Matches(msg, ui.common.KeyMap.RecentRepos):
+		ui.switcher = nil
+	case key.Matches(msg, ui.common.KeyMap.Up):
+		if sw.cursor > 0 {
+			sw.cursor--
+		}
+	case key.Matches(msg, ui.common.KeyMap.Down):
+		if sw.cursor < len(ui.recent)-1 {
+			sw.cursor++
+		}
+	case key.Matches(msg, ui.common.KeyMap.Select):
+		ui.switcher = nil
+		if name := ui.recent[sw.cursor]; name != ui.currentRepo() {
+			return ui.setRepoCmd(name)
+		}
+	}
+	return nil
+}
+
+// switcherHelp returns the key bindings of the quick switcher.
+func (ui *UI) switcherHelp() []key.Binding {
+	back := ui.common.KeyMap.Back
+	back.SetHelp("esc", "close")
+	return []key.Binding{
+		ui.common.KeyMap.UpDown,
+		ui.common.KeyMap.Select,
+		back,
+	}
+}
+
+// renderSwitcher renders the quick switcher in the middle of the page.
+func (ui *UI) renderSwitcher(width, height int) string {
+	st := ui.common.Styles.RepoSelector
+	current := ui.currentRepo()
+	var sb strings.Builder
+	sb.WriteString(st.Normal.Title.Render("Recent repositories"))
+	sb.WriteString("\n")
+	for i, name := range ui.recent {
+		line := fmt.Sprintf("%d %s", i+1, name)
+		if name == current {
+			line += " (current)"
+		}
+		line = common.TruncateString(line, width-6)
+		sb.WriteString("\n")
+		if i == ui.switcher.cursor {
+			sb.WriteString(st.Active.Title.Render("> " + line))
+		} else {
+			sb.WriteString(st.Normal.Desc.Render("  " + line))
+		}
+	}
+	box := ui.common.Renderer.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.common.Styles.InactiveBorderColor).
+		Padding(0, 1).
+		Render(sb.String())
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
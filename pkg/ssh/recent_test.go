@@ -0,0 +1,26 @@
+package ssh
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestAddRecent(t *testing.T) {
+	is := is.New(t)
+	var recent []string
+	recent = addRecent(recent, "a", 3)
+	recent = addRecent(recent, "b", 3)
+	recent = addRecent(recent, "c", 3)
+	is.Equal(recent, []string{"c", "b", "a"})
+
+	// Viewing a repository again moves it to the front.
+	recent = addRecent(recent, "a", 3)
+	is.Equal(recent, []string{"a", "c", "b"})
+
+	// The oldest repository is dropped.
+	recent = addRecent(recent, "d", 3)
+	is.Equal(recent, []string{"d", "a", "c"})
+
+	is.Equal(len(addRecent(recent, "e", 0)), 0)
+}
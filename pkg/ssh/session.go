@@ -1,17 +1,15 @@
 package ssh
 
 import (
+	"fmt"
 	"os"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/soft-serve/pkg/access"
 	"github.com/charmbracelet/soft-serve/pkg/backend"
 	"github.com/charmbracelet/soft-serve/pkg/config"
-	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
 	"github.com/charmbracelet/ssh"
-	"github.com/charmbracelet/wish"
 	bm "github.com/charmbracelet/wish/bubbletea"
 	"github.com/muesli/termenv"
 	"github.com/prometheus/client_golang/prometheus"
@@ -44,13 +42,13 @@ func SessionHandler(s ssh.Session) *tea.Program {
 	be := backend.FromContext(ctx)
 	cfg := config.FromContext(ctx)
 	cmd := s.Command()
-	initialRepo := ""
+	var link deepLink
 	if len(cmd) == 1 {
-		initialRepo = cmd[0]
-		auth := be.AccessLevelByPublicKey(ctx, initialRepo, s.PublicKey())
-		if auth < access.ReadOnlyAccess {
-			wish.Fatalln(s, proto.ErrUnauthorized)
-			return nil
+		var err error
+		link, err = parseDeepLink(ctx, be, s.PublicKey(), cmd[0])
+		if err != nil {
+			// Land on the repository list instead.
+			link = deepLink{notice: fmt.Sprintf("Can't open %s: %v", cmd[0], err)}
 		}
 	}
 
@@ -62,7 +60,8 @@ func SessionHandler(s ssh.Session) *tea.Program {
 
 	c := common.NewCommon(ctx, renderer, pty.Window.Width, pty.Window.Height)
 	c.SetValue(common.ConfigKey, cfg)
-	m := NewUI(c, initialRepo)
+	c.Styles.SetDiffColors(common.LoadDiffColors(ctx, be, s.PublicKey()))
+	m := NewUI(c, link)
 	opts := bm.MakeOptions(s)
 	opts = append(opts,
 		tea.WithAltScreen(),
@@ -72,12 +71,12 @@ func SessionHandler(s ssh.Session) *tea.Program {
 	)
 	p := tea.NewProgram(m, opts...)
 
-	tuiSessionCounter.WithLabelValues(initialRepo, pty.Term).Inc()
+	tuiSessionCounter.WithLabelValues(link.repo, pty.Term).Inc()
 
 	start := time.Now()
 	go func() {
 		<-ctx.Done()
-		tuiSessionDuration.WithLabelValues(initialRepo, pty.Term).Add(time.Since(start).Seconds())
+		tuiSessionDuration.WithLabelValues(link.repo, pty.Term).Add(time.Since(start).Seconds())
 	}()
 
 	return p
@@ -0,0 +1,207 @@
+package ssh
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/anmitsu/go-shlex"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/sessions"
+	"github.com/charmbracelet/soft-serve/pkg/ssh/cmd"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	bm "github.com/charmbracelet/wish/bubbletea"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const shellPrompt = "soft> "
+
+// shellCommands are the commands of the shell besides the CLI commands.
+var shellCommands = []string{"exit", "tui"}
+
+// interactiveMode returns what the interactive session runs, the preference
+// of its public key or the server default.
+func interactiveMode(s ssh.Session) string {
+	ctx := s.Context()
+	cfg := config.FromContext(ctx)
+	if pk := s.PublicKey(); pk != nil {
+		be := backend.FromContext(ctx)
+		if v, err := be.Preference(ctx, pk, cmd.InteractivePreference); err == nil && v != "" {
+			return v
+		}
+	}
+	return cfg.SSH.Interactive
+}
+
+// commandSession is a session with another command. The tui command of the
+// shell opens the UI on its repository with it.
+type commandSession struct {
+	ssh.Session
+	command []string
+}
+
+// Command implements ssh.Session.
+func (s *commandSession) Command() []string {
+	return s.command
+}
+
+// runShell runs a command shell on the session. It reads the CLI commands
+// with history and completion, and tui opens the UI with sh.
+func runShell(s ssh.Session, sh ssh.Handler) {
+	ctx := s.Context()
+	rw, restore, err := shellTerminal(s)
+	if err != nil {
+		wish.Fatalln(s, err)
+		return
+	}
+	defer restore()
+
+	renderer := bm.MakeRenderer(s)
+	if testrun, ok := os.LookupEnv("SOFT_SERVE_NO_COLOR"); ok && testrun == "1" {
+		// Disable colors when running tests.
+		renderer.SetColorProfile(termenv.Ascii)
+	}
+
+	t := term.NewTerminal(rw, shellPrompt)
+	t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		return completeShellLine(s, renderer, t, line, pos)
+	}
+
+	sess := sessions.SessionFromContext(ctx)
+	fmt.Fprintln(t, "Type help for the commands, tui [REPO[/TAB[/REF[/PATH]]]] to open the UI, and exit to quit.")
+	for {
+		if sess != nil {
+			sess.SetActivity("shell")
+		}
+		if pty, _, ok := s.Pty(); ok {
+			t.SetSize(pty.Window.Width, pty.Window.Height) // nolint: errcheck
+		}
+
+		line, err := t.ReadLine()
+		if err != nil {
+			// Ctrl-D or a closed session.
+			return
+		}
+
+		args, err := shlex.Split(line, true)
+		if err != nil {
+			fmt.Fprintln(t, "Error:", err)
+			continue
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		switch args[0] {
+		case "exit":
+			return
+		case "tui":
+			if len(args) > 2 {
+				fmt.Fprintln(t, "Error: usage: tui [REPO[/TAB[/REF[/PATH]]]]")
+				continue
+			}
+			if len(args) == 2 {
+				be := backend.FromContext(ctx)
+				if _, err := parseDeepLink(ctx, be, s.PublicKey(), args[1]); err != nil {
+					fmt.Fprintln(t, "Error:", err)
+					continue
+				}
+			}
+			if sess != nil {
+				sess.SetActivity("tui")
+			}
+			sh(&commandSession{Session: s, command: args[1:]})
+			continue
+		}
+
+		runCommand(s, renderer, args, rw, t, t)
+	}
+}
+
+// completeShellLine completes the word under the cursor with the completions
+// of the __complete command. Several completions are printed above the prompt
+// and the word is completed up to their common prefix.
+func completeShellLine(s ssh.Session, renderer *lipgloss.Renderer, w io.Writer, line string, pos int) (string, int, bool) {
+	head, tail := line[:pos], line[pos:]
+	args, err := shlex.Split(head, true)
+	if err != nil {
+		return "", 0, false
+	}
+	toComplete := ""
+	if len(args) > 0 && strings.TrimRightFunc(head, unicode.IsSpace) == head {
+		toComplete = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	comps := shellCompletions(s, renderer, args, toComplete)
+	if len(args) == 0 {
+		for _, c := range shellCommands {
+			if strings.HasPrefix(c, toComplete) {
+				comps = append(comps, c)
+			}
+		}
+		sort.Strings(comps)
+	}
+
+	switch len(comps) {
+	case 0:
+		return "", 0, false
+	case 1:
+		head = head[:len(head)-len(toComplete)] + comps[0] + " "
+		return head + tail, len(head), true
+	}
+
+	prefix := comps[0]
+	for _, c := range comps[1:] {
+		for !strings.HasPrefix(c, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	if len(prefix) > len(toComplete) {
+		head = head[:len(head)-len(toComplete)] + prefix
+		return head + tail, len(head), true
+	}
+
+	fmt.Fprintln(w, strings.Join(comps, "  "))
+	return line, pos, true
+}
+
+// shellCompletions returns the completions of toComplete after the arguments,
+// without their descriptions.
+func shellCompletions(s ssh.Session, renderer *lipgloss.Renderer, args []string, toComplete string) []string {
+	ctx := s.Context()
+	rootCmd := newRootCommand(ctx, renderer)
+
+	var out bytes.Buffer
+	rootCmd.SetArgs(append(append([]string{cobra.ShellCompRequestCmd}, args...), toComplete))
+	rootCmd.SetIn(strings.NewReader(""))
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(io.Discard)
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		return nil
+	}
+
+	comps := make([]string, 0)
+	for _, line := range strings.Split(out.String(), "\n") {
+		if strings.HasPrefix(line, ":") {
+			// The directive ends the completions.
+			break
+		}
+		if c, _, _ := strings.Cut(line, "\t"); c != "" {
+			comps = append(comps, c)
+		}
+	}
+	return comps
+}
@@ -0,0 +1,27 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package ssh
+
+import (
+	"io"
+
+	"github.com/charmbracelet/ssh"
+	"golang.org/x/term"
+)
+
+// shellTerminal returns the terminal of the command shell of the session in
+// raw mode, and a function that restores it.
+func shellTerminal(s ssh.Session) (io.ReadWriter, func(), error) {
+	pty, _, _ := s.Pty()
+	if s.EmulatedPty() || pty.Slave == nil {
+		return s, func() {}, nil
+	}
+
+	fd := int(pty.Slave.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pty.Slave, func() { term.Restore(fd, state) }, nil // nolint: errcheck
+}
@@ -2,6 +2,7 @@ package ssh
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"net"
 	"os"
@@ -15,6 +16,7 @@ import (
 	"github.com/charmbracelet/soft-serve/pkg/config"
 	"github.com/charmbracelet/soft-serve/pkg/db"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sessions"
 	"github.com/charmbracelet/soft-serve/pkg/store"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
 	"github.com/charmbracelet/ssh"
@@ -49,6 +51,9 @@ type SSHServer struct { // nolint: revive
 	be     *backend.Backend
 	ctx    context.Context
 	logger *log.Logger
+
+	// sessions holds the active sessions.
+	sessions *sessions.Registry
 }
 
 // NewSSHServer returns a new SSHServer.
@@ -61,10 +66,11 @@ func NewSSHServer(ctx context.Context) (*SSHServer, error) {
 
 	var err error
 	s := &SSHServer{
-		cfg:    cfg,
-		ctx:    ctx,
-		be:     be,
-		logger: logger,
+		cfg:      cfg,
+		ctx:      ctx,
+		be:       be,
+		logger:   logger,
+		sessions: sessions.NewRegistry(),
 	}
 
 	mw := []wish.Middleware{
@@ -76,6 +82,8 @@ func NewSSHServer(ctx context.Context) (*SSHServer, error) {
 			CommandMiddleware,
 			// Logging middleware.
 			LoggingMiddleware,
+			// Sessions middleware.
+			SessionsMiddleware(s.sessions),
 			// Context middleware.
 			ContextMiddleware(cfg, dbx, datastore, be, logger),
 			// Authentication middleware.
@@ -103,11 +111,16 @@ func NewSSHServer(ctx context.Context) (*SSHServer, error) {
 		return nil, err
 	}
 
-	if config.IsDebug() {
+	if logger.GetLevel() <= log.DebugLevel {
 		s.srv.ServerConfigCallback = func(_ ssh.Context) *gossh.ServerConfig {
 			return &gossh.ServerConfig{
 				AuthLogCallback: func(conn gossh.ConnMetadata, method string, err error) {
-					logger.Debug("authentication", "user", conn.User(), "method", method, "err", err)
+					logger.Debug("authentication",
+						"session", sessionID(hex.EncodeToString(conn.SessionID())),
+						"user", conn.User(),
+						"addr", conn.RemoteAddr(),
+						"method", method,
+						"err", err)
 				},
 			}
 		}
@@ -176,11 +189,41 @@ func (s *SSHServer) PublicKeyHandler(ctx ssh.Context, pk ssh.PublicKey) (allowed
 		publicKeyCounter.WithLabelValues(strconv.FormatBool(*allowed)).Inc()
 	}(&allowed)
 
+	if cert, ok := pk.(*gossh.Certificate); ok {
+		// Reject expired, revoked, and untrusted certificates instead of
+		// treating them as anonymous keys.
+		if err := s.be.CheckCertificate(cert); err != nil {
+			s.logger.Info("rejected certificate", "session", sessionID(ctx.SessionID()), "key-id", cert.KeyId, "serial", cert.Serial, "err", err)
+			allowed = false
+			return
+		}
+	}
+
 	user, _ := s.be.UserByPublicKey(ctx, pk)
 	if user != nil {
 		ctx.SetValue(proto.ContextKeyUser, user)
 	}
 
+	// The options of the key restrict what the session can do, reject the
+	// key if they can't be read rather than ignoring them.
+	opts, err := s.be.PublicKeyOptions(ctx, pk)
+	if err != nil {
+		s.logger.Error("error reading public key options", "session", sessionID(ctx.SessionID()), "fingerprint", gossh.FingerprintSHA256(pk), "err", err)
+		allowed = false
+		return
+	}
+	ctx.SetValue(proto.ContextKeyKeyOptions, opts)
+
+	logArgs := []interface{}{
+		"session", sessionID(ctx.SessionID()),
+		"user", ctx.User(),
+		"fingerprint", gossh.FingerprintSHA256(pk),
+	}
+	if user != nil {
+		logArgs = append(logArgs, "username", user.Username())
+	}
+	s.logger.Debug("public key authentication", logArgs...)
+
 	// XXX: store the first "approved" public-key fingerprint in the
 	// permissions block to use for authentication later.
 	initializePermissions(ctx)
@@ -198,6 +241,7 @@ func (s *SSHServer) PublicKeyHandler(ctx ssh.Context, pk ssh.PublicKey) (allowed
 func (s *SSHServer) KeyboardInteractiveHandler(ctx ssh.Context, _ gossh.KeyboardInteractiveChallenge) bool {
 	ac := s.be.AllowKeyless(ctx)
 	keyboardInteractiveCounter.WithLabelValues(strconv.FormatBool(ac)).Inc()
+	s.logger.Debug("keyboard interactive authentication", "session", sessionID(ctx.SessionID()), "user", ctx.User(), "allowed", ac)
 
 	// If we're allowing keyless access, reset the public key fingerprint
 	if ac {
@@ -211,3 +255,12 @@ func (s *SSHServer) KeyboardInteractiveHandler(ctx ssh.Context, _ gossh.Keyboard
 	}
 	return ac
 }
+
+// sessionID returns the short form of the id of an SSH connection that ties
+// its logs together.
+func sessionID(id string) string {
+	if len(id) > 16 {
+		return id[:16]
+	}
+	return id
+}
@@ -2,13 +2,17 @@ package ssh
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/config"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/sessions"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
 	"github.com/charmbracelet/soft-serve/pkg/ui/components/footer"
 	"github.com/charmbracelet/soft-serve/pkg/ui/components/header"
@@ -34,31 +38,110 @@ const (
 
 // UI is the main UI model.
 type UI struct {
-	serverName  string
-	initialRepo string
-	common      common.Common
-	pages       []common.Component
-	activePage  page
-	state       sessionState
-	header      *header.Header
-	footer      *footer.Footer
-	showFooter  bool
-	error       error
+	serverName string
+	link       deepLink
+	common     common.Common
+	pages      []common.Component
+	activePage page
+	state      sessionState
+	header     *header.Header
+	footer     *footer.Footer
+	showFooter bool
+	error      error
+
+	// pendingRef and pendingTab are the reference and the tab to open once
+	// the selected repository is loaded.
+	pendingRef string
+	pendingTab string
+
+	// pendingExact is true when the pending tab is opened even if it has
+	// nothing to show, e.g. when going back to it.
+	pendingExact bool
+
+	// refs is the full name of the last reference browsed in each
+	// repository, restored when the repository is viewed again.
+	refs map[string]string
+
+	// recent is the list of recently viewed repositories, most recent first.
+	// switcher is the quick switcher to open one of them.
+	recent   []string
+	switcher *recentSwitcher
+
+	// bookmarks are the files bookmarked by the user, listed in bookmarkList
+	// when it's open. pendingFile is the file of a bookmark or a link to open
+	// once its repository is loaded.
+	bookmarks    []common.Bookmark
+	bookmarkList *bookmarkList
+	pendingFile  *fileLink
+
+	// pendingCommit is the commit to show the diff of once its repository is
+	// loaded.
+	pendingCommit string
+
+	// history is the navigation history, gone through with the back and
+	// forward keys. It only lasts as long as the session.
+	history navHistory
+
+	// palette is the command palette, open when it's set.
+	palette *commandPalette
+
+	// watched are the repositories whose pushes are notified, bell rings the
+	// terminal bell on notifications. events is the event stream, subscribed
+	// once a repository is watched.
+	watched map[string]bool
+	bell    bool
+	events  <-chan proto.Event
+
+	// pushReports are the reports of the pushes of the user. pushPanel is the
+	// last one, shown until it expires or is dismissed, pushSeq numbers them.
+	pushReports <-chan proto.PushReport
+	pushPanel   *pushPanel
+	pushSeq     int
+
+	// times re-renders the relative times of the active page.
+	times common.RelativeTimesTicker
+}
+
+// repoRefMsg is a message to open a repository at a reference and on a tab.
+// Either can be empty.
+type repoRefMsg struct {
+	repo proto.Repository
+	ref  string
+	tab  string
+
+	// file is the file to open, if any. commit is the hash of the commit to
+	// show the diff of, if any.
+	file   *fileLink
+	commit string
+
+	// exactTab is true to open the tab even if it has nothing to show.
+	exactTab bool
 }
 
-// NewUI returns a new UI model.
-func NewUI(c common.Common, initialRepo string) *UI {
+// fileLink is a file to open in the Files tab at a reference, with the lines
+// from start to end selected. end is 0 to scroll to start without selecting.
+type fileLink struct {
+	ref   string
+	path  string
+	start int
+	end   int
+}
+
+// NewUI returns a new UI model that opens on the linked repository, or on the
+// repository list.
+func NewUI(c common.Common, link deepLink) *UI {
 	serverName := c.Config().Name
 	h := header.New(c, serverName)
 	ui := &UI{
-		serverName:  serverName,
-		common:      c,
-		pages:       make([]common.Component, 2), // selection & repo
-		activePage:  selectionPage,
-		state:       loadingState,
-		header:      h,
-		initialRepo: initialRepo,
-		showFooter:  true,
+		serverName: serverName,
+		common:     c,
+		pages:      make([]common.Component, 2), // selection & repo
+		activePage: selectionPage,
+		state:      loadingState,
+		header:     h,
+		link:       link,
+		showFooter: true,
+		refs:       make(map[string]string),
 	}
 	ui.footer = footer.New(c, ui)
 	return ui
@@ -74,6 +157,7 @@ func (ui *UI) getMargins() (wm, hm int) {
 	}
 	wm += style.GetHorizontalFrameSize()
 	hm += style.GetVerticalFrameSize()
+	hm += ui.pushPanelHeight(ui.common.Width - wm)
 	if ui.showFooter {
 		// NOTE: we don't use the footer's style to determine the margins
 		// because footer.Height() is the height of the footer after applying
@@ -90,6 +174,18 @@ func (ui *UI) ShortHelp() []key.Binding {
 	case errorState:
 		b = append(b, ui.common.KeyMap.Back)
 	case readyState:
+		if ui.palette != nil {
+			return ui.paletteHelp()
+		}
+		if ui.switcher != nil {
+			return ui.switcherHelp()
+		}
+		if ui.bookmarkList != nil {
+			return ui.bookmarkListHelp()
+		}
+		if ui.pushPanel != nil {
+			b = append(b, ui.pushPanelKey())
+		}
 		b = append(b, ui.pages[ui.activePage].ShortHelp()...)
 	}
 	if !ui.IsFiltering() {
@@ -106,6 +202,15 @@ func (ui *UI) FullHelp() [][]key.Binding {
 	case errorState:
 		b = append(b, []key.Binding{ui.common.KeyMap.Back})
 	case readyState:
+		if ui.palette != nil {
+			return [][]key.Binding{ui.paletteHelp()}
+		}
+		if ui.switcher != nil {
+			return [][]key.Binding{ui.switcherHelp()}
+		}
+		if ui.bookmarkList != nil {
+			return [][]key.Binding{ui.bookmarkListHelp()}
+		}
 		b = append(b, ui.pages[ui.activePage].FullHelp()...)
 	}
 	h := []key.Binding{
@@ -113,6 +218,21 @@ func (ui *UI) FullHelp() [][]key.Binding {
 	}
 	if !ui.IsFiltering() {
 		h = append(h, ui.common.KeyMap.Quit)
+		if ui.recentLimit() > 0 && len(ui.recent) > 0 {
+			h = append(h, ui.common.KeyMap.RecentRepos)
+		}
+		if len(ui.bookmarks) > 0 {
+			h = append(h, ui.common.KeyMap.Bookmarks)
+		}
+		if ui.history.cursor > 0 {
+			h = append(h, ui.common.KeyMap.HistoryBack)
+		}
+		if ui.history.cursor < len(ui.history.locations)-1 {
+			h = append(h, ui.common.KeyMap.HistoryForward)
+		}
+		if ui.state == readyState {
+			h = append(h, ui.common.KeyMap.CommandPalette)
+		}
 	}
 	b = append(b, h)
 	return b
@@ -131,36 +251,75 @@ func (ui *UI) SetSize(width, height int) {
 	}
 }
 
+// repoTabs returns the tabs of the repository page, in the order of the
+// config. Tabs that aren't in the config aren't created at all.
+func (ui *UI) repoTabs() []common.TabComponent {
+	names := config.RepoTabs
+	if cfg := ui.common.Config(); cfg != nil && len(cfg.UI.Tabs) > 0 {
+		names = cfg.UI.Tabs
+	}
+	tabs := make([]common.TabComponent, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case "readme":
+			tabs = append(tabs, repo.NewReadme(ui.common))
+		case "files":
+			tabs = append(tabs, repo.NewFiles(ui.common))
+		case "commits":
+			tabs = append(tabs, repo.NewLog(ui.common))
+		case "stash":
+			tabs = append(tabs, repo.NewStash(ui.common))
+		case "branches":
+			tabs = append(tabs, repo.NewRefs(ui.common, git.RefsHeads))
+		case "tags":
+			tabs = append(tabs, repo.NewRefs(ui.common, git.RefsTags))
+		case "releases":
+			tabs = append(tabs, repo.NewReleases(ui.common))
+		case "insights":
+			tabs = append(tabs, repo.NewInsights(ui.common))
+		}
+	}
+	return tabs
+}
+
 // Init implements tea.Model.
 func (ui *UI) Init() tea.Cmd {
 	ui.pages[selectionPage] = selection.New(ui.common)
-	ui.pages[repoPage] = repo.New(ui.common,
-		repo.NewReadme(ui.common),
-		repo.NewFiles(ui.common),
-		repo.NewLog(ui.common),
-		repo.NewRefs(ui.common, git.RefsHeads),
-		repo.NewRefs(ui.common, git.RefsTags),
-	)
+	ui.pages[repoPage] = repo.New(ui.common, ui.repoTabs()...)
 	ui.SetSize(ui.common.Width, ui.common.Height)
 	cmds := make([]tea.Cmd, 0)
 	cmds = append(cmds,
+		ui.header.Init(),
 		ui.pages[selectionPage].Init(),
 		ui.pages[repoPage].Init(),
 	)
-	if ui.initialRepo != "" {
-		cmds = append(cmds, ui.initialRepoCmd(ui.initialRepo))
+	if ui.link.repo != "" {
+		cmds = append(cmds, ui.initialRepoCmd(ui.link))
+	} else if notice := ui.link.notice; notice != "" {
+		cmds = append(cmds, func() tea.Msg {
+			return selection.StatusMsg(notice)
+		})
 	}
+	ui.loadWatched()
+	ui.loadBookmarks()
+	cmds = append(cmds, ui.watchEventsCmd(), ui.subscribePushReportsCmd())
 	ui.state = readyState
 	ui.SetSize(ui.common.Width, ui.common.Height)
 	return tea.Batch(cmds...)
 }
 
-// IsFiltering returns true if the selection page is filtering.
+// IsFiltering returns true if the selection page is filtering or the repo
+// page is editing the repository description.
 func (ui *UI) IsFiltering() bool {
-	if ui.activePage == selectionPage {
+	switch ui.activePage {
+	case selectionPage:
 		if s, ok := ui.pages[selectionPage].(*selection.Selection); ok && s.FilterState() == list.Filtering {
 			return true
 		}
+	case repoPage:
+		if r, ok := ui.pages[repoPage].(*repo.Repo); ok && r.IsEditing() {
+			return true
+		}
 	}
 	return false
 }
@@ -182,13 +341,47 @@ func (ui *UI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg, tea.MouseMsg:
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
+			if ui.palette != nil {
+				return ui, ui.updatePalette(msg)
+			}
+			if ui.switcher != nil && !key.Matches(msg, ui.common.KeyMap.Quit) {
+				return ui, ui.updateSwitcher(msg)
+			}
+			if ui.bookmarkList != nil && !key.Matches(msg, ui.common.KeyMap.Quit) {
+				return ui, ui.updateBookmarkList(msg)
+			}
+			if ui.pushPanel != nil && key.Matches(msg, ui.common.KeyMap.Back) {
+				ui.pushPanel = nil
+				ui.SetSize(ui.common.Width, ui.common.Height)
+				return ui, nil
+			}
+			// Stop restoring a location of the history once the user moves
+			// on.
+			ui.history.settle(ui.location())
 			switch {
+			case key.Matches(msg, ui.common.KeyMap.HistoryBack) &&
+				ui.state == readyState && !ui.IsFiltering():
+				return ui, ui.goHistory(-1)
+			case key.Matches(msg, ui.common.KeyMap.HistoryForward) &&
+				ui.state == readyState && !ui.IsFiltering():
+				return ui, ui.goHistory(1)
+			case key.Matches(msg, ui.common.KeyMap.CommandPalette) &&
+				ui.state == readyState && !ui.IsFiltering():
+				return ui, ui.openPalette()
+			case key.Matches(msg, ui.common.KeyMap.RecentRepos) &&
+				ui.state == readyState && !ui.IsFiltering() && len(ui.recent) > 0:
+				ui.openSwitcher()
+				return ui, nil
+			case key.Matches(msg, ui.common.KeyMap.Bookmarks) &&
+				ui.state == readyState && !ui.IsFiltering() && len(ui.bookmarks) > 0:
+				ui.bookmarkList = &bookmarkList{}
+				return ui, nil
 			case key.Matches(msg, ui.common.KeyMap.Back) && ui.error != nil:
 				ui.error = nil
 				ui.state = readyState
 				// Always show the footer on error.
 				ui.showFooter = ui.footer.ShowAll()
-			case key.Matches(msg, ui.common.KeyMap.Help):
+			case key.Matches(msg, ui.common.KeyMap.Help) && !ui.IsFiltering():
 				cmds = append(cmds, footer.ToggleFooterCmd)
 			case key.Matches(msg, ui.common.KeyMap.Quit):
 				if !ui.IsFiltering() {
@@ -197,6 +390,7 @@ func (ui *UI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return ui, tea.Quit
 				}
 			case ui.activePage == repoPage &&
+				!ui.IsFiltering() &&
 				ui.pages[ui.activePage].(*repo.Repo).Path() == "" &&
 				key.Matches(msg, ui.common.KeyMap.Back):
 				ui.activePage = selectionPage
@@ -221,22 +415,90 @@ func (ui *UI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if ui.error == nil && ui.activePage == repoPage {
 			ui.showFooter = !ui.showFooter
 		}
+	case repoRefMsg:
+		ui.pendingRef = msg.ref
+		ui.pendingTab = msg.tab
+		ui.pendingExact = msg.exactTab
+		ui.pendingFile = msg.file
+		ui.pendingCommit = msg.commit
+		cmds = append(cmds, func() tea.Msg {
+			return repo.RepoMsg(msg.repo)
+		})
 	case repo.RepoMsg:
+		ui.recent = addRecent(ui.recent, msg.Name(), ui.recentLimit())
 		ui.common.SetValue(common.RepoKey, msg)
 		ui.activePage = repoPage
 		// Show the footer on repo page if show all is set.
 		ui.showFooter = ui.footer.ShowAll()
-		cmds = append(cmds, repo.UpdateRefCmd(msg))
+		if tab := ui.pendingTab; tab != "" {
+			ui.pendingTab = ""
+			if ui.pendingExact {
+				ui.pages[repoPage].(*repo.Repo).SetRestoreTab(tab)
+			} else {
+				ui.pages[repoPage].(*repo.Repo).SetLinkTab(tab)
+			}
+		}
+		if ref := ui.pendingRef; ref != "" {
+			ui.pendingRef = ""
+			cmds = append(cmds, repo.UpdateRefNameCmd(msg, ref))
+		} else if ref, ok := ui.refs[msg.Name()]; ok {
+			cmds = append(cmds, repo.RestoreRefCmd(msg, ref))
+		} else if ref := ui.common.PinnedRef(msg.Name()); ref != "" {
+			cmds = append(cmds, repo.PinnedRefCmd(msg, ref))
+		} else {
+			cmds = append(cmds, repo.UpdateRefCmd(msg))
+		}
+	case repo.EmptyRepoMsg:
+		ui.pendingFile = nil
+		ui.pendingCommit = ""
+	case repo.OpenRepoMsg:
+		cmds = append(cmds, ui.setRepoCmd(msg.Repo))
+	case repo.ToggleWatchMsg:
+		cmds = append(cmds, ui.toggleWatch(msg.Repo))
+	case repo.ToggleBookmarkMsg:
+		cmds = append(cmds, ui.toggleBookmark(msg.Bookmark))
+	case watchEventMsg:
+		cmds = append(cmds, ui.notifyEvent(msg), ui.nextEventCmd())
+	case pushReportMsg:
+		cmds = append(cmds, ui.showPushReport(msg), ui.nextPushReportCmd())
+	case pushPanelExpiredMsg:
+		if ui.pushPanel != nil && ui.pushPanel.seq == int(msg) {
+			ui.pushPanel = nil
+		}
+	case repo.RefMsg:
+		if r, ok := ui.common.Context().Value(common.RepoKey).(proto.Repository); ok && msg != nil {
+			ui.refs[r.Name()] = (*git.Reference)(msg).Name().String()
+		}
+		if f := ui.pendingFile; f != nil {
+			ui.pendingFile = nil
+			cmds = append(cmds, repo.OpenFileRangeCmd(f.ref, f.path, f.start, f.end))
+		}
+		if c := ui.pendingCommit; c != "" {
+			ui.pendingCommit = ""
+			cmds = append(cmds, repo.OpenCommitCmd(c))
+		}
 	case common.ErrorMsg:
 		ui.error = msg
 		ui.state = errorState
 		ui.showFooter = true
 	case selector.SelectMsg:
-		switch msg.IdentifiableItem.(type) {
+		switch item := msg.IdentifiableItem.(type) {
 		case selection.Item:
 			if ui.activePage == selectionPage {
 				cmds = append(cmds, ui.setRepoCmd(msg.ID()))
 			}
+		case selection.PushItem:
+			if ui.activePage == selectionPage {
+				cmds = append(cmds, ui.setRepoRefCmd(item.Repo, item.Ref))
+			}
+		}
+	}
+	if ui.palette != nil {
+		// Keep the cursor of the palette blinking.
+		var cmd tea.Cmd
+		ui.palette.input, cmd = ui.palette.input.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
 		}
 	}
 	h, cmd := ui.header.Update(msg)
@@ -258,9 +520,28 @@ func (ui *UI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 	// This fixes determining the height margin of the footer.
 	ui.SetSize(ui.common.Width, ui.common.Height)
+	ui.recordLocation()
+	ui.updateActivity()
+	if ui.state != loadingState {
+		cmds = append(cmds, ui.times.Update(msg, ui.pages[ui.activePage]))
+	}
 	return ui, tea.Batch(cmds...)
 }
 
+// updateActivity shows the page of the UI in the list of sessions.
+func (ui *UI) updateActivity() {
+	sess := sessions.SessionFromContext(ui.common.Context())
+	if sess == nil {
+		return
+	}
+	activity := "tui"
+	if rn := ui.currentRepo(); rn != "" {
+		tab := ui.pages[repoPage].(*repo.Repo).TabName()
+		activity = fmt.Sprintf("tui %s %s", rn, strings.ToLower(tab))
+	}
+	sess.SetActivity(activity)
+}
+
 // View implements tea.Model.
 func (ui *UI) View() string {
 	var view string
@@ -281,6 +562,18 @@ func (ui *UI) View() string {
 			Render(err)
 	case readyState:
 		view = ui.pages[ui.activePage].View()
+		if ui.switcher != nil {
+			view = ui.renderSwitcher(ui.common.Width-wm, ui.common.Height-hm)
+		}
+		if ui.bookmarkList != nil {
+			view = ui.renderBookmarkList(ui.common.Width-wm, ui.common.Height-hm)
+		}
+		if ui.palette != nil {
+			view = ui.renderPalette(ui.common.Width-wm, ui.common.Height-hm)
+		}
+		if ui.pushPanel != nil {
+			view = lipgloss.JoinVertical(lipgloss.Left, view, ui.renderPushPanel(ui.common.Width-wm))
+		}
 	default:
 		view = "Unknown state :/ this is a bug!"
 	}
@@ -326,12 +619,26 @@ func (ui *UI) setRepoCmd(rn string) tea.Cmd {
 	}
 }
 
-func (ui *UI) initialRepoCmd(rn string) tea.Cmd {
+func (ui *UI) setRepoRefCmd(rn, ref string) tea.Cmd {
 	return func() tea.Msg {
 		r, err := ui.openRepo(rn)
+		if err != nil {
+			return common.ErrorMsg(err)
+		}
+		return repoRefMsg{repo: r, ref: ref, tab: "commits"}
+	}
+}
+
+func (ui *UI) initialRepoCmd(link deepLink) tea.Cmd {
+	return func() tea.Msg {
+		r, err := ui.openRepo(link.repo)
 		if err != nil {
 			return nil
 		}
-		return repo.RepoMsg(r)
+		msg := repoRefMsg{repo: r, ref: link.ref, tab: link.tab}
+		if link.path != "" {
+			msg.file = &fileLink{ref: link.ref, path: link.path, start: link.start, end: link.end}
+		}
+		return msg
 	}
 }
@@ -0,0 +1,135 @@
+package ssh
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/ui/pages/repo"
+	"github.com/charmbracelet/soft-serve/pkg/ui/pages/selection"
+)
+
+// watchEventMsg is a message that contains an event of the event stream.
+type watchEventMsg proto.Event
+
+// loadWatched loads the repositories watched by the user and whether the
+// bell rings on notifications.
+func (ui *UI) loadWatched() {
+	ui.watched = make(map[string]bool)
+	ui.bell = true
+	be := ui.common.Backend()
+	pk := ui.common.PublicKey()
+	if be == nil || pk == nil {
+		return
+	}
+
+	ctx := ui.common.Context()
+	spec, err := be.Preference(ctx, pk, common.WatchedReposPreference)
+	if err != nil {
+		ui.common.Logger.Debugf("ui: failed to load watched repos: %v", err)
+	}
+	ui.watched = common.ParseWatchedRepos(spec)
+
+	bell, err := be.Preference(ctx, pk, common.BellPreference)
+	if err != nil {
+		ui.common.Logger.Debugf("ui: failed to load bell preference: %v", err)
+	}
+	ui.bell = bell != "false"
+}
+
+// toggleWatch starts or stops watching the pushes to a repository. Keyless
+// users watch repositories until they disconnect.
+func (ui *UI) toggleWatch(name string) tea.Cmd {
+	ui.watched[name] = !ui.watched[name]
+	if !ui.watched[name] {
+		delete(ui.watched, name)
+	}
+
+	status := fmt.Sprintf("Stopped watching %s", name)
+	if ui.watched[name] {
+		status = fmt.Sprintf("Watching the pushes to %s", name)
+	}
+
+	if be, pk := ui.common.Backend(), ui.common.PublicKey(); be != nil && pk != nil {
+		ctx := ui.common.Context()
+		var err error
+		if len(ui.watched) == 0 {
+			err = be.DeletePreference(ctx, pk, common.WatchedReposPreference)
+		} else {
+			err = be.SetPreference(ctx, pk, common.WatchedReposPreference, common.FormatWatchedRepos(ui.watched))
+		}
+		if err != nil {
+			ui.common.Logger.Debugf("ui: failed to save watched repos: %v", err)
+			status = "Failed to save the watched repositories"
+		}
+	}
+
+	return tea.Batch(
+		func() tea.Msg { return repo.StatusMsg(status) },
+		func() tea.Msg { return repo.WatchedMsg{Repo: name} },
+		ui.watchEventsCmd(),
+	)
+}
+
+// watchEventsCmd subscribes to the event stream once a repository is
+// watched, and waits for the next event.
+func (ui *UI) watchEventsCmd() tea.Cmd {
+	if ui.events == nil {
+		be := ui.common.Backend()
+		if be == nil || len(ui.watched) == 0 {
+			return nil
+		}
+		events, err := be.SubscribeEvents(ui.common.Context())
+		if err != nil {
+			ui.common.Logger.Debugf("ui: failed to subscribe to events: %v", err)
+			return nil
+		}
+		ui.events = events
+		return ui.nextEventCmd()
+	}
+	return nil
+}
+
+// nextEventCmd waits for the next event of the event stream. It returns nil
+// once the session is closed.
+func (ui *UI) nextEventCmd() tea.Cmd {
+	events := ui.events
+	return func() tea.Msg {
+		ev, ok := <-events
+		if !ok {
+			return nil
+		}
+		return watchEventMsg(ev)
+	}
+}
+
+// notifyEvent notifies the pushes to the watched repositories the user can
+// still read, in the status bar of the active page. The bell rings unless the
+// user turned it off.
+func (ui *UI) notifyEvent(ev watchEventMsg) tea.Cmd {
+	if ev.Type != proto.EventPush || !ui.watched[ev.Repo] {
+		return nil
+	}
+
+	be := ui.common.Backend()
+	if be.AccessLevelByPublicKey(ui.common.Context(), ev.Repo, ui.common.PublicKey()) < access.ReadOnlyAccess {
+		return nil
+	}
+
+	status := fmt.Sprintf("New push to %s %s", ev.Repo, git.ReferenceName(ev.Ref).Short())
+	if ev.User != "" {
+		status += " by " + ev.User
+	}
+
+	if ui.bell && ui.common.Output != nil {
+		ui.common.Output.WriteString("\a") // nolint: errcheck
+	}
+
+	if ui.activePage == repoPage {
+		return func() tea.Msg { return repo.StatusMsg(status) }
+	}
+	return func() tea.Msg { return selection.StatusMsg(status) }
+}
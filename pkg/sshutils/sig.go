@@ -0,0 +1,155 @@
+package sshutils
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// SSH signatures follow the format of "ssh-keygen -Y sign", see PROTOCOL.sshsig
+// in OpenSSH, and can be verified with "ssh-keygen -Y verify".
+const (
+	sigMagic   = "SSHSIG"
+	sigVersion = 1
+	sigBegin   = "-----BEGIN SSH SIGNATURE-----"
+	sigEnd     = "-----END SSH SIGNATURE-----"
+)
+
+// ErrInvalidSignature is returned when an SSH signature can't be parsed or
+// doesn't match the message.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// sigBlob is an SSH signature, after its magic preamble.
+type sigBlob struct {
+	Version   uint32
+	PublicKey []byte
+	Namespace string
+	Reserved  string
+	HashAlg   string
+	Signature []byte
+}
+
+// signedData is what an SSH signature signs, after its magic preamble.
+type signedData struct {
+	Namespace string
+	Reserved  string
+	HashAlg   string
+	Hash      []byte
+}
+
+// Sign signs message with signer and returns the armored SSH signature. The
+// namespace scopes the signature, a signature is only valid in the namespace
+// it was made for.
+func Sign(signer gossh.Signer, namespace string, message []byte) ([]byte, error) {
+	data := sigData(namespace, "sha512", message)
+	var (
+		sig *gossh.Signature
+		err error
+	)
+	if as, ok := signer.(gossh.AlgorithmSigner); ok && signer.PublicKey().Type() == gossh.KeyAlgoRSA {
+		// SSH signatures don't allow SHA-1 RSA signatures.
+		sig, err = as.SignWithAlgorithm(rand.Reader, data, gossh.KeyAlgoRSASHA512)
+	} else {
+		sig, err = signer.Sign(rand.Reader, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	blob := append([]byte(sigMagic), gossh.Marshal(sigBlob{
+		Version:   sigVersion,
+		PublicKey: signer.PublicKey().Marshal(),
+		Namespace: namespace,
+		HashAlg:   "sha512",
+		Signature: gossh.Marshal(sig),
+	})...)
+
+	enc := base64.StdEncoding.EncodeToString(blob)
+	var b bytes.Buffer
+	b.WriteString(sigBegin + "\n")
+	for len(enc) > 70 {
+		b.WriteString(enc[:70] + "\n")
+		enc = enc[70:]
+	}
+	b.WriteString(enc + "\n")
+	b.WriteString(sigEnd + "\n")
+	return b.Bytes(), nil
+}
+
+// Verify verifies that the armored SSH signature sig of message was made by
+// pk in the given namespace.
+func Verify(pk gossh.PublicKey, namespace string, message, sig []byte) error {
+	key, err := VerifySigner(namespace, message, sig)
+	if err != nil {
+		return err
+	}
+	if !KeysEqual(key, pk) {
+		return fmt.Errorf("%w: signed by another key", ErrInvalidSignature)
+	}
+	return nil
+}
+
+// VerifySigner verifies the armored SSH signature sig of message in the given
+// namespace with the public key it embeds, and returns that key. It's up to
+// the caller to decide whether to trust the key, e.g. because it belongs to a
+// user.
+func VerifySigner(namespace string, message, sig []byte) (gossh.PublicKey, error) {
+	s := strings.TrimSpace(string(sig))
+	if !strings.HasPrefix(s, sigBegin) || !strings.HasSuffix(s, sigEnd) {
+		return nil, ErrInvalidSignature
+	}
+	s = strings.Join(strings.Fields(s[len(sigBegin):len(s)-len(sigEnd)]), "")
+	raw, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || !bytes.HasPrefix(raw, []byte(sigMagic)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var blob sigBlob
+	if err := gossh.Unmarshal(raw[len(sigMagic):], &blob); err != nil || blob.Version != sigVersion {
+		return nil, ErrInvalidSignature
+	}
+	if blob.Namespace != namespace {
+		return nil, fmt.Errorf("%w: namespace %q instead of %q", ErrInvalidSignature, blob.Namespace, namespace)
+	}
+	key, err := gossh.ParsePublicKey(blob.PublicKey)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	var ssig gossh.Signature
+	if err := gossh.Unmarshal(blob.Signature, &ssig); err != nil {
+		return nil, ErrInvalidSignature
+	}
+	if blob.HashAlg != "sha256" && blob.HashAlg != "sha512" {
+		return nil, fmt.Errorf("%w: unsupported hash %q", ErrInvalidSignature, blob.HashAlg)
+	}
+	if err := key.Verify(sigData(namespace, blob.HashAlg, message), &ssig); err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	return key, nil
+}
+
+// sigData returns the data an SSH signature of message signs.
+func sigData(namespace, hashAlg string, message []byte) []byte {
+	var h hash.Hash
+	if hashAlg == "sha256" {
+		h = sha256.New()
+	} else {
+		h = sha512.New()
+	}
+	h.Write(message) // nolint: errcheck
+	return append([]byte(sigMagic), gossh.Marshal(signedData{
+		Namespace: namespace,
+		HashAlg:   hashAlg,
+		Hash:      h.Sum(nil),
+	})...)
+}
@@ -0,0 +1,47 @@
+package sshutils
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSignVerify(t *testing.T) {
+	ed, rsa := generateKeys(t)
+	msg := []byte("refs/heads/main\n")
+	cases := []struct {
+		name   string
+		signer ssh.Signer
+		other  ssh.PublicKey
+	}{
+		{"ed25519", ed.Signer(), rsa.PublicKey()},
+		{"rsa", rsa.Signer(), ed.PublicKey()},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sig, err := Sign(c.signer, "test", msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := Verify(c.signer.PublicKey(), "test", msg, sig); err != nil {
+				t.Error(err)
+			}
+			if key, err := VerifySigner("test", msg, sig); err != nil || !KeysEqual(key, c.signer.PublicKey()) {
+				t.Errorf("signer not verified: %v", err)
+			}
+			if err := Verify(c.signer.PublicKey(), "other", msg, sig); !errors.Is(err, ErrInvalidSignature) {
+				t.Error("verified in another namespace")
+			}
+			if err := Verify(c.other, "test", msg, sig); !errors.Is(err, ErrInvalidSignature) {
+				t.Error("verified with another key")
+			}
+			if err := Verify(c.signer.PublicKey(), "test", []byte("changed"), sig); !errors.Is(err, ErrInvalidSignature) {
+				t.Error("verified another message")
+			}
+			if err := Verify(c.signer.PublicKey(), "test", msg, []byte("garbage")); !errors.Is(err, ErrInvalidSignature) {
+				t.Error("verified garbage")
+			}
+		})
+	}
+}
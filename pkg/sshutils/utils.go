@@ -3,6 +3,7 @@ package sshutils
 import (
 	"bytes"
 	"context"
+	"strings"
 
 	"github.com/charmbracelet/ssh"
 	gossh "golang.org/x/crypto/ssh"
@@ -14,6 +15,14 @@ func ParseAuthorizedKey(ak string) (gossh.PublicKey, string, error) {
 	return pk, c, err
 }
 
+// ParseAuthorizedKeyOptions parses an authorized key string into a public
+// key and the options in front of it, e.g. "read-only" in
+// "read-only ssh-ed25519 AAAA...". The options are comma separated.
+func ParseAuthorizedKeyOptions(ak string) (gossh.PublicKey, string, error) {
+	pk, _, opts, _, err := gossh.ParseAuthorizedKey([]byte(ak))
+	return pk, strings.Join(opts, ","), err
+}
+
 // MarshalAuthorizedKey marshals a public key into an authorized key string.
 //
 // This is the inverse of ParseAuthorizedKey.
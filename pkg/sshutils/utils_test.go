@@ -54,6 +54,25 @@ func TestParseAuthorizedKey(t *testing.T) {
 	}
 }
 
+func TestParseAuthorizedKeyOptions(t *testing.T) {
+	goodKey1, _ := generateKeys(t)
+	pk, opts, err := ParseAuthorizedKeyOptions(`read-only,repo="ice cream" ` + goodKey1.AuthorizedKey())
+	if err != nil {
+		t.Fatalf("ParseAuthorizedKeyOptions returned error: %v", err)
+	}
+	if !KeysEqual(pk, goodKey1.PublicKey()) {
+		t.Errorf("ParseAuthorizedKeyOptions returned the wrong key")
+	}
+	if opts != `read-only,repo="ice cream"` {
+		t.Errorf("ParseAuthorizedKeyOptions returned options %q", opts)
+	}
+
+	_, opts, err = ParseAuthorizedKeyOptions(goodKey1.AuthorizedKey())
+	if err != nil || opts != "" {
+		t.Errorf("ParseAuthorizedKeyOptions(%q) = %q, %v", goodKey1.AuthorizedKey(), opts, err)
+	}
+}
+
 func TestMarshalAuthorizedKey(t *testing.T) {
 	goodKey1, goodKey2 := generateKeys(t)
 	cases := []struct {
@@ -0,0 +1,70 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+)
+
+// healthTimeout is the maximum time a readiness check can take.
+const healthTimeout = 5 * time.Second
+
+// SetReady marks the server as ready, or not, to serve requests. The server
+// is ready once it's accepting connections and the repositories are loaded.
+func (s *StatsServer) SetReady(ready bool) {
+	s.ready.Store(ready)
+}
+
+// handleLiveness reports that the server is running. It's always 200.
+func (s *StatsServer) handleLiveness(w http.ResponseWriter, _ *http.Request) {
+	renderHealth(w, http.StatusOK, "ok")
+}
+
+// handleReadiness reports whether the server is ready to serve requests. It's
+// 200 when the server is ready, the database is reachable, and the
+// repositories are readable, and 503 during startup and maintenance.
+func (s *StatsServer) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	if !s.ready.Load() {
+		renderHealth(w, http.StatusServiceUnavailable, "starting")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), healthTimeout)
+	defer cancel()
+
+	logger := log.FromContext(s.ctx).WithPrefix("stats.health")
+	if dbx := db.FromContext(s.ctx); dbx != nil {
+		if err := dbx.PingContext(ctx); err != nil {
+			logger.Error("database is unavailable", "err", err)
+			renderHealth(w, http.StatusServiceUnavailable, "database unavailable")
+			return
+		}
+	}
+
+	if _, err := os.ReadDir(filepath.Join(s.cfg.DataPath, "repos")); err != nil && !os.IsNotExist(err) {
+		logger.Error("repositories are unreadable", "err", err)
+		renderHealth(w, http.StatusServiceUnavailable, "repositories unavailable")
+		return
+	}
+
+	if be := backend.FromContext(s.ctx); be != nil && be.MaintenanceMode(ctx) {
+		renderHealth(w, http.StatusServiceUnavailable, "maintenance")
+		return
+	}
+
+	renderHealth(w, http.StatusOK, "ok")
+}
+
+func renderHealth(w http.ResponseWriter, code int, status string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	fmt.Fprintln(w, status) // nolint: errcheck
+}
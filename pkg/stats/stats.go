@@ -3,6 +3,7 @@ package stats
 import (
 	"context"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/charmbracelet/soft-serve/pkg/config"
@@ -14,14 +15,14 @@ type StatsServer struct { //nolint:revive
 	ctx    context.Context
 	cfg    *config.Config
 	server *http.Server
+	ready  atomic.Bool
 }
 
 // NewStatsServer returns a new StatsServer.
 func NewStatsServer(ctx context.Context) (*StatsServer, error) {
 	cfg := config.FromContext(ctx)
 	mux := http.NewServeMux()
-	mux.Handle("/metrics", promhttp.Handler())
-	return &StatsServer{
+	s := &StatsServer{
 		ctx: ctx,
 		cfg: cfg,
 		server: &http.Server{
@@ -32,7 +33,11 @@ func NewStatsServer(ctx context.Context) (*StatsServer, error) {
 			WriteTimeout:      time.Second * 10,
 			MaxHeaderBytes:    http.DefaultMaxHeaderBytes,
 		},
-	}, nil
+	}
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", s.handleLiveness)
+	mux.HandleFunc("/readyz", s.handleReadiness)
+	return s, nil
 }
 
 // ListenAndServe starts the StatsServer.
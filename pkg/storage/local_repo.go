@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LocalRepoStorage is a repository storage that keeps repositories on the
+// local filesystem, in their local directory.
+type LocalRepoStorage struct {
+	root string
+}
+
+var _ RepoStorage = (*LocalRepoStorage)(nil)
+
+// NewLocalRepoStorage creates a new LocalRepoStorage.
+func NewLocalRepoStorage(root string) *LocalRepoStorage {
+	return &LocalRepoStorage{root: root}
+}
+
+// Acquire implements RepoStorage. Repositories are always available.
+func (l *LocalRepoStorage) Acquire(context.Context, string, bool) (func() error, error) {
+	return func() error { return nil }, nil
+}
+
+// Exists implements RepoStorage.
+func (l *LocalRepoStorage) Exists(_ context.Context, name string) (bool, error) {
+	_, err := os.Stat(l.path(name))
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return false, err
+}
+
+// Delete implements RepoStorage.
+func (l *LocalRepoStorage) Delete(_ context.Context, name string) error {
+	return os.RemoveAll(l.path(name))
+}
+
+// Rename implements RepoStorage.
+func (l *LocalRepoStorage) Rename(_ context.Context, oldName, newName string) error {
+	np := l.path(newName)
+	// Make sure the new repository parent directory exists.
+	if err := os.MkdirAll(filepath.Dir(np), os.ModePerm); err != nil {
+		return err
+	}
+
+	return os.Rename(l.path(oldName), np)
+}
+
+// Size implements RepoStorage.
+func (l *LocalRepoStorage) Size(ctx context.Context) (int64, error) {
+	return DirSize(ctx, l.root)
+}
+
+// path returns the directory of a repository.
+func (l *LocalRepoStorage) path(name string) string {
+	return filepath.Join(l.root, filepath.FromSlash(name)+".git")
+}
+
+// DirSize returns the number of bytes used by the regular files in a
+// directory and its subdirectories. A missing directory is empty.
+func DirSize(ctx context.Context, dir string) (int64, error) {
+	var size int64
+	if err := filepath.WalkDir(dir, func(_ string, de fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !de.Type().IsRegular() {
+			return nil
+		}
+		info, err := de.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				// The file was removed while walking, e.g. by git gc.
+				return nil
+			}
+			return err
+		}
+		size += info.Size()
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
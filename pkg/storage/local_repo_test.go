@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestLocalRepoStorage(t *testing.T) {
+	is := is.New(t)
+	ctx := context.TODO()
+	root := t.TempDir()
+	rs, err := OpenRepoStorage(ctx, LocalRepoStorageName, root)
+	is.NoErr(err)
+
+	ok, err := rs.Exists(ctx, "org/repo")
+	is.NoErr(err)
+	is.True(!ok)
+
+	release, err := rs.Acquire(ctx, "org/repo", true)
+	is.NoErr(err)
+	is.NoErr(os.MkdirAll(filepath.Join(root, "org", "repo.git"), os.ModePerm))
+	is.NoErr(os.WriteFile(filepath.Join(root, "org", "repo.git", "HEAD"), []byte("ref: refs/heads/main\n"), 0o644))
+	is.NoErr(release())
+
+	ok, err = rs.Exists(ctx, "org/repo")
+	is.NoErr(err)
+	is.True(ok)
+
+	size, err := rs.Size(ctx)
+	is.NoErr(err)
+	is.Equal(size, int64(len("ref: refs/heads/main\n")))
+
+	is.NoErr(rs.Rename(ctx, "org/repo", "other/repo"))
+	ok, err = rs.Exists(ctx, "other/repo")
+	is.NoErr(err)
+	is.True(ok)
+
+	is.NoErr(rs.Delete(ctx, "other/repo"))
+	ok, err = rs.Exists(ctx, "other/repo")
+	is.NoErr(err)
+	is.True(!ok)
+}
+
+func TestRegisterRepoStorage(t *testing.T) {
+	is := is.New(t)
+	_, err := OpenRepoStorage(context.TODO(), "test", t.TempDir())
+	is.True(err != nil)
+
+	RegisterRepoStorage("test", func(_ context.Context, root string) (RepoStorage, error) {
+		return NewLocalRepoStorage(root), nil
+	})
+	is.Equal(RepoStorages(), []string{LocalRepoStorageName, "test"})
+	_, err = OpenRepoStorage(context.TODO(), "test", t.TempDir())
+	is.NoErr(err)
+
+	defer func() {
+		is.True(recover() != nil)
+	}()
+	RegisterRepoStorage("test", nil)
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// LocalRepoStorageName is the name of the default repository storage, the
+// local filesystem.
+const LocalRepoStorageName = "local"
+
+// RepoStorage is an interface for storing Git repositories. Repositories are
+// named after their path, without the .git suffix, e.g. "org/repo".
+//
+// Git reads and writes repositories on the local filesystem, a repository is
+// always found at <root>/<name>.git, where root is the directory the storage
+// is opened with. A storage that keeps repositories elsewhere, like in an
+// object store, syncs that local copy when a repository is acquired and
+// stores the changes back when it's released.
+//
+// Repositories are only changed while they're acquired to write. Git
+// transports acquire them to read, other readers, like the UI and the web
+// pages, read the local copy as it was last synced.
+type RepoStorage interface {
+	// Acquire makes a repository available in its local directory until
+	// release is called. write is true when the repository is changed, the
+	// changes are stored by release. Repositories that don't exist yet can be
+	// acquired to create them.
+	Acquire(ctx context.Context, name string, write bool) (release func() error, err error)
+
+	// Exists returns true if the repository exists.
+	Exists(ctx context.Context, name string) (bool, error)
+
+	// Delete deletes a repository along with its local copy.
+	Delete(ctx context.Context, name string) error
+
+	// Rename renames a repository. The new name can be in another directory.
+	Rename(ctx context.Context, oldName, newName string) error
+
+	// Size returns the number of bytes used by all the repositories.
+	Size(ctx context.Context) (int64, error)
+}
+
+// RepoStorageFunc opens a repository storage whose repositories are copied in
+// root.
+type RepoStorageFunc func(ctx context.Context, root string) (RepoStorage, error)
+
+var (
+	repoStoragesMu sync.RWMutex
+	repoStorages   = map[string]RepoStorageFunc{
+		LocalRepoStorageName: func(_ context.Context, root string) (RepoStorage, error) {
+			return NewLocalRepoStorage(root), nil
+		},
+	}
+)
+
+// RegisterRepoStorage registers a repository storage with the given name, to
+// be used with the "repo.storage" setting. It's meant to be called from the
+// init function of the package of the storage, and panics if the name is
+// already registered.
+func RegisterRepoStorage(name string, fn RepoStorageFunc) {
+	repoStoragesMu.Lock()
+	defer repoStoragesMu.Unlock()
+	if _, ok := repoStorages[name]; ok {
+		panic(fmt.Sprintf("repository storage %q registered twice", name))
+	}
+	repoStorages[name] = fn
+}
+
+// RepoStorages returns the sorted names of the registered repository
+// storages.
+func RepoStorages() []string {
+	repoStoragesMu.RLock()
+	defer repoStoragesMu.RUnlock()
+	names := make([]string, 0, len(repoStorages))
+	for name := range repoStorages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OpenRepoStorage opens the repository storage registered with the given name.
+// Its repositories are copied in root.
+func OpenRepoStorage(ctx context.Context, name, root string) (RepoStorage, error) {
+	repoStoragesMu.RLock()
+	fn, ok := repoStorages[name]
+	repoStoragesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown repository storage %q", name)
+	}
+	return fn(ctx, root)
+}
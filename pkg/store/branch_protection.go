@@ -0,0 +1,23 @@
+package store
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+// BranchProtectionStore is an interface for managing the branch protection
+// rules of repositories.
+type BranchProtectionStore interface {
+	// GetBranchProtectionsByRepoID returns the branch protection rules of a
+	// repository ordered by pattern.
+	GetBranchProtectionsByRepoID(ctx context.Context, h db.Handler, repoID int64) ([]models.BranchProtection, error)
+	// SetBranchProtection creates or replaces the branch protection rule of a
+	// repository for a pattern. The required statuses are a comma-separated
+	// list of status contexts.
+	SetBranchProtection(ctx context.Context, h db.Handler, repoID int64, pattern string, requiredStatuses string, fastForward bool) error
+	// DeleteBranchProtection deletes the branch protection rule of a
+	// repository for a pattern.
+	DeleteBranchProtection(ctx context.Context, h db.Handler, repoID int64, pattern string) error
+}
@@ -0,0 +1,20 @@
+package store
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+// CommandAliasStore is an interface for managing the command aliases of public
+// keys.
+type CommandAliasStore interface {
+	// GetCommandAliases returns the command aliases of a public key ordered
+	// by name.
+	GetCommandAliases(ctx context.Context, h db.Handler, publicKey string) ([]models.CommandAlias, error)
+	// SetCommandAlias creates or replaces a command alias of a public key.
+	SetCommandAlias(ctx context.Context, h db.Handler, publicKey string, name string, command string) error
+	// DeleteCommandAlias deletes a command alias of a public key.
+	DeleteCommandAlias(ctx context.Context, h db.Handler, publicKey string, name string) error
+}
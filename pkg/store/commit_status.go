@@ -0,0 +1,18 @@
+package store
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+// CommitStatusStore is an interface for managing commit statuses.
+type CommitStatusStore interface {
+	// GetCommitStatusesBySHA returns all the statuses of a commit.
+	GetCommitStatusesBySHA(ctx context.Context, h db.Handler, repoID int64, sha string) ([]models.CommitStatus, error)
+	// GetCommitStatusesBySHAs returns all the statuses of the given commits.
+	GetCommitStatusesBySHAs(ctx context.Context, h db.Handler, repoID int64, shas []string) ([]models.CommitStatus, error)
+	// SetCommitStatus creates or updates the status of a commit for a context.
+	SetCommitStatus(ctx context.Context, h db.Handler, repoID int64, sha string, context string, state string, description string, targetURL string) error
+}
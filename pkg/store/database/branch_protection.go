@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+)
+
+type branchProtectionStore struct{}
+
+var _ store.BranchProtectionStore = (*branchProtectionStore)(nil)
+
+// GetBranchProtectionsByRepoID implements store.BranchProtectionStore.
+func (*branchProtectionStore) GetBranchProtectionsByRepoID(ctx context.Context, h db.Handler, repoID int64) ([]models.BranchProtection, error) {
+	var m []models.BranchProtection
+	query := h.Rebind(`SELECT * FROM branch_protections WHERE repo_id = ? ORDER BY pattern ASC;`)
+	err := h.SelectContext(ctx, &m, query, repoID)
+	return m, err
+}
+
+// SetBranchProtection implements store.BranchProtectionStore.
+func (*branchProtectionStore) SetBranchProtection(ctx context.Context, h db.Handler, repoID int64, pattern string, requiredStatuses string, fastForward bool) error {
+	query := h.Rebind(`INSERT INTO branch_protections (repo_id, pattern, required_statuses, fast_forward, updated_at)
+			VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+			ON CONFLICT (repo_id, pattern) DO UPDATE SET
+				required_statuses = excluded.required_statuses,
+				fast_forward = excluded.fast_forward,
+				updated_at = CURRENT_TIMESTAMP;`)
+	_, err := h.ExecContext(ctx, query, repoID, pattern, requiredStatuses, fastForward)
+	return err
+}
+
+// DeleteBranchProtection implements store.BranchProtectionStore.
+func (*branchProtectionStore) DeleteBranchProtection(ctx context.Context, h db.Handler, repoID int64, pattern string) error {
+	query := h.Rebind(`DELETE FROM branch_protections WHERE repo_id = ? AND pattern = ?;`)
+	res, err := h.ExecContext(ctx, query, repoID, pattern)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return db.ErrRecordNotFound
+	}
+	return nil
+}
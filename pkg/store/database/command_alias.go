@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+)
+
+type commandAliasStore struct{}
+
+var _ store.CommandAliasStore = (*commandAliasStore)(nil)
+
+// GetCommandAliases implements store.CommandAliasStore.
+func (*commandAliasStore) GetCommandAliases(ctx context.Context, h db.Handler, publicKey string) ([]models.CommandAlias, error) {
+	var m []models.CommandAlias
+	query := h.Rebind(`SELECT * FROM command_aliases WHERE public_key = ? ORDER BY name ASC;`)
+	err := h.SelectContext(ctx, &m, query, publicKey)
+	return m, err
+}
+
+// SetCommandAlias implements store.CommandAliasStore.
+func (*commandAliasStore) SetCommandAlias(ctx context.Context, h db.Handler, publicKey string, name string, command string) error {
+	query := h.Rebind(`INSERT INTO command_aliases (public_key, name, command, updated_at)
+			VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+			ON CONFLICT (public_key, name) DO UPDATE SET
+				command = excluded.command,
+				updated_at = CURRENT_TIMESTAMP;`)
+	_, err := h.ExecContext(ctx, query, publicKey, name, command)
+	return err
+}
+
+// DeleteCommandAlias implements store.CommandAliasStore.
+func (*commandAliasStore) DeleteCommandAlias(ctx context.Context, h db.Handler, publicKey string, name string) error {
+	query := h.Rebind(`DELETE FROM command_aliases WHERE public_key = ? AND name = ?;`)
+	res, err := h.ExecContext(ctx, query, publicKey, name)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return db.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+	"github.com/jmoiron/sqlx"
+)
+
+type commitStatusStore struct{}
+
+var _ store.CommitStatusStore = (*commitStatusStore)(nil)
+
+// GetCommitStatusesBySHA implements store.CommitStatusStore.
+func (*commitStatusStore) GetCommitStatusesBySHA(ctx context.Context, h db.Handler, repoID int64, sha string) ([]models.CommitStatus, error) {
+	var m []models.CommitStatus
+	query := h.Rebind(`SELECT * FROM commit_statuses WHERE repo_id = ? AND sha = ? ORDER BY context ASC;`)
+	err := h.SelectContext(ctx, &m, query, repoID, sha)
+	return m, err
+}
+
+// GetCommitStatusesBySHAs implements store.CommitStatusStore.
+func (*commitStatusStore) GetCommitStatusesBySHAs(ctx context.Context, h db.Handler, repoID int64, shas []string) ([]models.CommitStatus, error) {
+	var m []models.CommitStatus
+	if len(shas) == 0 {
+		return m, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT * FROM commit_statuses WHERE repo_id = ? AND sha IN (?) ORDER BY context ASC;`, repoID, shas)
+	if err != nil {
+		return nil, err
+	}
+
+	query = h.Rebind(query)
+	err = h.SelectContext(ctx, &m, query, args...)
+	return m, err
+}
+
+// SetCommitStatus implements store.CommitStatusStore.
+func (*commitStatusStore) SetCommitStatus(ctx context.Context, h db.Handler, repoID int64, sha string, context string, state string, description string, targetURL string) error {
+	query := h.Rebind(`INSERT INTO commit_statuses (repo_id, sha, context, state, description, target_url, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+			ON CONFLICT (repo_id, sha, context) DO UPDATE SET
+				state = excluded.state,
+				description = excluded.description,
+				target_url = excluded.target_url,
+				updated_at = CURRENT_TIMESTAMP;`)
+	_, err := h.ExecContext(ctx, query, repoID, sha, context, state, description, targetURL)
+	return err
+}
@@ -22,6 +22,14 @@ type datastore struct {
 	*lfsStore
 	*accessTokenStore
 	*webhookStore
+	*commitStatusStore
+	*pushEventStore
+	*commandAliasStore
+	*preferenceStore
+	*branchProtectionStore
+	*deployStore
+	*repoSizeStore
+	*teamStore
 }
 
 // New returns a new store.Store database.
@@ -35,12 +43,20 @@ func New(ctx context.Context, db *db.DB) store.Store {
 		db:     db,
 		logger: logger,
 
-		settingsStore:    &settingsStore{},
-		repoStore:        &repoStore{},
-		userStore:        &userStore{},
-		collabStore:      &collabStore{},
-		lfsStore:         &lfsStore{},
-		accessTokenStore: &accessTokenStore{},
+		settingsStore:         &settingsStore{},
+		repoStore:             &repoStore{},
+		userStore:             &userStore{},
+		collabStore:           &collabStore{},
+		lfsStore:              &lfsStore{},
+		accessTokenStore:      &accessTokenStore{},
+		commitStatusStore:     &commitStatusStore{},
+		pushEventStore:        &pushEventStore{},
+		commandAliasStore:     &commandAliasStore{},
+		preferenceStore:       &preferenceStore{},
+		branchProtectionStore: &branchProtectionStore{},
+		deployStore:           &deployStore{},
+		repoSizeStore:         &repoSizeStore{},
+		teamStore:             &teamStore{},
 	}
 
 	return s
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+)
+
+type deployStore struct{}
+
+var _ store.DeployStore = (*deployStore)(nil)
+
+// GetDeploysByRepoID implements store.DeployStore.
+func (*deployStore) GetDeploysByRepoID(ctx context.Context, h db.Handler, repoID int64) ([]models.Deploy, error) {
+	var m []models.Deploy
+	query := h.Rebind(`SELECT * FROM deploys WHERE repo_id = ? ORDER BY branch ASC;`)
+	err := h.SelectContext(ctx, &m, query, repoID)
+	return m, err
+}
+
+// SetDeploy implements store.DeployStore.
+func (*deployStore) SetDeploy(ctx context.Context, h db.Handler, repoID int64, branch string, script string) error {
+	query := h.Rebind(`INSERT INTO deploys (repo_id, branch, script, updated_at)
+			VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+			ON CONFLICT (repo_id, branch) DO UPDATE SET
+				script = excluded.script,
+				updated_at = CURRENT_TIMESTAMP;`)
+	_, err := h.ExecContext(ctx, query, repoID, branch, script)
+	return err
+}
+
+// DeleteDeploy implements store.DeployStore.
+func (*deployStore) DeleteDeploy(ctx context.Context, h db.Handler, repoID int64, branch string) error {
+	query := h.Rebind(`DELETE FROM deploys WHERE repo_id = ? AND branch = ?;`)
+	res, err := h.ExecContext(ctx, query, repoID, branch)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return db.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+)
+
+type preferenceStore struct{}
+
+var _ store.PreferenceStore = (*preferenceStore)(nil)
+
+// GetPreference implements store.PreferenceStore.
+func (*preferenceStore) GetPreference(ctx context.Context, h db.Handler, publicKey string, name string) (models.Preference, error) {
+	var m models.Preference
+	query := h.Rebind(`SELECT * FROM preferences WHERE public_key = ? AND name = ?;`)
+	err := h.GetContext(ctx, &m, query, publicKey, name)
+	return m, err
+}
+
+// SetPreference implements store.PreferenceStore.
+func (*preferenceStore) SetPreference(ctx context.Context, h db.Handler, publicKey string, name string, value string) error {
+	query := h.Rebind(`INSERT INTO preferences (public_key, name, value, updated_at)
+			VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+			ON CONFLICT (public_key, name) DO UPDATE SET
+				value = excluded.value,
+				updated_at = CURRENT_TIMESTAMP;`)
+	_, err := h.ExecContext(ctx, query, publicKey, name, value)
+	return err
+}
+
+// ListPreferencesByName implements store.PreferenceStore.
+func (*preferenceStore) ListPreferencesByName(ctx context.Context, h db.Handler, name string) ([]models.Preference, error) {
+	var ms []models.Preference
+	query := h.Rebind(`SELECT * FROM preferences WHERE name = ? ORDER BY id;`)
+	err := h.SelectContext(ctx, &ms, query, name)
+	return ms, err
+}
+
+// DeletePreference implements store.PreferenceStore.
+func (*preferenceStore) DeletePreference(ctx context.Context, h db.Handler, publicKey string, name string) error {
+	query := h.Rebind(`DELETE FROM preferences WHERE public_key = ? AND name = ?;`)
+	_, err := h.ExecContext(ctx, query, publicKey, name)
+	return err
+}
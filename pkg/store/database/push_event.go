@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+)
+
+type pushEventStore struct{}
+
+var _ store.PushEventStore = (*pushEventStore)(nil)
+
+const selectPushEvents = `SELECT push_events.*, repos.name AS repo_name, users.username AS username
+	FROM push_events
+	INNER JOIN repos ON repos.id = push_events.repo_id
+	LEFT JOIN users ON users.id = push_events.user_id`
+
+// CreatePushEvent implements store.PushEventStore.
+func (*pushEventStore) CreatePushEvent(ctx context.Context, h db.Handler, repoID int64, userID int64, refName string, oldSHA string, newSHA string, commits int64, forced bool) error {
+	var uid sql.NullInt64
+	if userID > 0 {
+		uid = sql.NullInt64{Int64: userID, Valid: true}
+	}
+	query := h.Rebind(`INSERT INTO push_events (repo_id, user_id, ref_name, old_sha, new_sha, commits, forced, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP);`)
+	_, err := h.ExecContext(ctx, query, repoID, uid, refName, oldSHA, newSHA, commits, forced)
+	return err
+}
+
+// SetPushEventDeploy implements store.PushEventStore.
+func (*pushEventStore) SetPushEventDeploy(ctx context.Context, h db.Handler, repoID int64, refName string, newSHA string, script string, status string, output string) error {
+	query := h.Rebind(`UPDATE push_events SET deploy_script = ?, deploy_status = ?, deploy_output = ?
+			WHERE id = (SELECT MAX(id) FROM push_events WHERE repo_id = ? AND ref_name = ? AND new_sha = ?);`)
+	_, err := h.ExecContext(ctx, query, script, status, output, repoID, refName, newSHA)
+	return err
+}
+
+// GetPushEvents implements store.PushEventStore.
+func (*pushEventStore) GetPushEvents(ctx context.Context, h db.Handler, limit int, offset int) ([]models.PushEvent, error) {
+	var m []models.PushEvent
+	query := h.Rebind(selectPushEvents + `
+		ORDER BY push_events.created_at DESC, push_events.id DESC
+		LIMIT ? OFFSET ?;`)
+	err := h.SelectContext(ctx, &m, query, limit, offset)
+	return m, err
+}
+
+// GetPushEventsByRepoID implements store.PushEventStore.
+func (*pushEventStore) GetPushEventsByRepoID(ctx context.Context, h db.Handler, repoID int64, limit int, offset int) ([]models.PushEvent, error) {
+	var m []models.PushEvent
+	query := h.Rebind(selectPushEvents + `
+		WHERE push_events.repo_id = ?
+		ORDER BY push_events.created_at DESC, push_events.id DESC
+		LIMIT ? OFFSET ?;`)
+	err := h.SelectContext(ctx, &m, query, repoID, limit, offset)
+	return m, err
+}
+
+// GetPushEventsAfterID implements store.PushEventStore.
+func (*pushEventStore) GetPushEventsAfterID(ctx context.Context, h db.Handler, id int64, limit int) ([]models.PushEvent, error) {
+	var m []models.PushEvent
+	query := h.Rebind(selectPushEvents + `
+		WHERE push_events.id > ?
+		ORDER BY push_events.id ASC
+		LIMIT ?;`)
+	err := h.SelectContext(ctx, &m, query, id, limit)
+	return m, err
+}
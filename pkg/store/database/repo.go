@@ -83,6 +83,15 @@ func (*repoStore) GetRepoIsHiddenByName(ctx context.Context, tx db.Handler, name
 	return isHidden, db.WrapError(err)
 }
 
+// GetRepoIsArchivedByName implements store.RepositoryStore.
+func (*repoStore) GetRepoIsArchivedByName(ctx context.Context, tx db.Handler, name string) (bool, error) {
+	var isArchived bool
+	name = utils.SanitizeRepo(name)
+	query := tx.Rebind("SELECT archived FROM repos WHERE name = ?;")
+	err := tx.GetContext(ctx, &isArchived, query, name)
+	return isArchived, db.WrapError(err)
+}
+
 // GetRepoIsMirrorByName implements store.RepositoryStore.
 func (*repoStore) GetRepoIsMirrorByName(ctx context.Context, tx db.Handler, name string) (bool, error) {
 	var isMirror bool
@@ -92,6 +101,62 @@ func (*repoStore) GetRepoIsMirrorByName(ctx context.Context, tx db.Handler, name
 	return isMirror, db.WrapError(err)
 }
 
+// GetRepoParentByName implements store.RepositoryStore.
+func (*repoStore) GetRepoParentByName(ctx context.Context, tx db.Handler, name string) (string, error) {
+	var parents []string
+	name = utils.SanitizeRepo(name)
+	query := tx.Rebind(`SELECT p.name FROM repos r
+		INNER JOIN repos p ON p.id = r.parent_id
+		WHERE r.name = ?;`)
+	if err := tx.SelectContext(ctx, &parents, query, name); err != nil || len(parents) == 0 {
+		return "", db.WrapError(err)
+	}
+	return parents[0], nil
+}
+
+// SetRepoParentByName implements store.RepositoryStore.
+func (*repoStore) SetRepoParentByName(ctx context.Context, tx db.Handler, name string, parent string) error {
+	name = utils.SanitizeRepo(name)
+	if parent == "" {
+		query := tx.Rebind("UPDATE repos SET parent_id = NULL WHERE name = ?;")
+		_, err := tx.ExecContext(ctx, query, name)
+		return db.WrapError(err)
+	}
+	query := tx.Rebind("UPDATE repos SET parent_id = (SELECT id FROM repos WHERE name = ?) WHERE name = ?;")
+	_, err := tx.ExecContext(ctx, query, utils.SanitizeRepo(parent), name)
+	return db.WrapError(err)
+}
+
+// GetRepoForksByName implements store.RepositoryStore.
+func (*repoStore) GetRepoForksByName(ctx context.Context, tx db.Handler, name string) ([]string, error) {
+	forks := make([]string, 0)
+	name = utils.SanitizeRepo(name)
+	query := tx.Rebind(`SELECT r.name FROM repos r
+		INNER JOIN repos p ON p.id = r.parent_id
+		WHERE p.name = ?
+		ORDER BY r.name;`)
+	err := tx.SelectContext(ctx, &forks, query, name)
+	return forks, db.WrapError(err)
+}
+
+// GetRepoLandingTabByName implements store.RepositoryStore.
+func (*repoStore) GetRepoLandingTabByName(ctx context.Context, tx db.Handler, name string) (string, error) {
+	var tab string
+	name = utils.SanitizeRepo(name)
+	query := tx.Rebind("SELECT landing_tab FROM repos WHERE name = ?;")
+	err := tx.GetContext(ctx, &tab, query, name)
+	return tab, db.WrapError(err)
+}
+
+// GetRepoReadmePathByName implements store.RepositoryStore.
+func (*repoStore) GetRepoReadmePathByName(ctx context.Context, tx db.Handler, name string) (string, error) {
+	var path string
+	name = utils.SanitizeRepo(name)
+	query := tx.Rebind("SELECT readme_path FROM repos WHERE name = ?;")
+	err := tx.GetContext(ctx, &path, query, name)
+	return path, db.WrapError(err)
+}
+
 // GetRepoIsPrivateByName implements store.RepositoryStore.
 func (*repoStore) GetRepoIsPrivateByName(ctx context.Context, tx db.Handler, name string) (bool, error) {
 	var isPrivate bool
@@ -126,6 +191,79 @@ func (*repoStore) SetRepoIsHiddenByName(ctx context.Context, tx db.Handler, name
 	return db.WrapError(err)
 }
 
+// SetRepoIsArchivedByName implements store.RepositoryStore.
+func (*repoStore) SetRepoIsArchivedByName(ctx context.Context, tx db.Handler, name string, isArchived bool) error {
+	name = utils.SanitizeRepo(name)
+	query := tx.Rebind("UPDATE repos SET archived = ?, archive_changed_at = CURRENT_TIMESTAMP WHERE name = ?;")
+	_, err := tx.ExecContext(ctx, query, isArchived, name)
+	return db.WrapError(err)
+}
+
+// SetRepoLandingTabByName implements store.RepositoryStore.
+func (*repoStore) SetRepoLandingTabByName(ctx context.Context, tx db.Handler, name string, tab string) error {
+	name = utils.SanitizeRepo(name)
+	query := tx.Rebind("UPDATE repos SET landing_tab = ? WHERE name = ?;")
+	_, err := tx.ExecContext(ctx, query, tab, name)
+	return db.WrapError(err)
+}
+
+// SetRepoReadmePathByName implements store.RepositoryStore.
+func (*repoStore) SetRepoReadmePathByName(ctx context.Context, tx db.Handler, name string, path string) error {
+	name = utils.SanitizeRepo(name)
+	query := tx.Rebind("UPDATE repos SET readme_path = ? WHERE name = ?;")
+	_, err := tx.ExecContext(ctx, query, path, name)
+	return db.WrapError(err)
+}
+
+// SetRepoAvatarByName implements store.RepositoryStore.
+func (*repoStore) SetRepoAvatarByName(ctx context.Context, tx db.Handler, name string, path string) error {
+	name = utils.SanitizeRepo(name)
+	query := tx.Rebind("UPDATE repos SET avatar = ? WHERE name = ?;")
+	_, err := tx.ExecContext(ctx, query, path, name)
+	return db.WrapError(err)
+}
+
+// SetRepoMirrorSyncByName implements store.RepositoryStore.
+func (*repoStore) SetRepoMirrorSyncByName(ctx context.Context, tx db.Handler, name string, sync models.MirrorSync) error {
+	name = utils.SanitizeRepo(name)
+	query := tx.Rebind("UPDATE repos SET mirror_synced_at = ?, mirror_error = ? WHERE name = ?;")
+	_, err := tx.ExecContext(ctx, query, sync.SyncedAt, sync.Error, name)
+	return db.WrapError(err)
+}
+
+// SetRepoCloneInstructionsByName implements store.RepositoryStore.
+func (*repoStore) SetRepoCloneInstructionsByName(ctx context.Context, tx db.Handler, name string, instructions string) error {
+	name = utils.SanitizeRepo(name)
+	query := tx.Rebind("UPDATE repos SET clone_instructions = ? WHERE name = ?;")
+	_, err := tx.ExecContext(ctx, query, instructions, name)
+	return db.WrapError(err)
+}
+
+// SetRepoAttestationByName implements store.RepositoryStore.
+func (*repoStore) SetRepoAttestationByName(ctx context.Context, tx db.Handler, name string, attestation models.Attestation) error {
+	name = utils.SanitizeRepo(name)
+	query := tx.Rebind("UPDATE repos SET attestation = ?, attestation_signature = ? WHERE name = ?;")
+	_, err := tx.ExecContext(ctx, query, attestation.Statement, attestation.Signature, name)
+	return db.WrapError(err)
+}
+
+// GetRepoPushLimitsByName implements store.RepositoryStore.
+func (*repoStore) GetRepoPushLimitsByName(ctx context.Context, tx db.Handler, name string) (models.PushLimits, error) {
+	var limits models.PushLimits
+	name = utils.SanitizeRepo(name)
+	query := tx.Rebind("SELECT max_blob_size, max_tree_depth FROM repos WHERE name = ?;")
+	err := tx.GetContext(ctx, &limits, query, name)
+	return limits, db.WrapError(err)
+}
+
+// SetRepoPushLimitsByName implements store.RepositoryStore.
+func (*repoStore) SetRepoPushLimitsByName(ctx context.Context, tx db.Handler, name string, limits models.PushLimits) error {
+	name = utils.SanitizeRepo(name)
+	query := tx.Rebind("UPDATE repos SET max_blob_size = ?, max_tree_depth = ? WHERE name = ?;")
+	_, err := tx.ExecContext(ctx, query, limits.MaxBlobSize, limits.MaxTreeDepth, name)
+	return db.WrapError(err)
+}
+
 // SetRepoIsPrivateByName implements store.RepositoryStore.
 func (*repoStore) SetRepoIsPrivateByName(ctx context.Context, tx db.Handler, name string, isPrivate bool) error {
 	name = utils.SanitizeRepo(name)
@@ -134,6 +272,14 @@ func (*repoStore) SetRepoIsPrivateByName(ctx context.Context, tx db.Handler, nam
 	return db.WrapError(err)
 }
 
+// SetRepoUserIDByName implements store.RepositoryStore.
+func (*repoStore) SetRepoUserIDByName(ctx context.Context, tx db.Handler, name string, userID int64) error {
+	name = utils.SanitizeRepo(name)
+	query := tx.Rebind("UPDATE repos SET user_id = ? WHERE name = ?;")
+	_, err := tx.ExecContext(ctx, query, userID, name)
+	return db.WrapError(err)
+}
+
 // SetRepoNameByName implements store.RepositoryStore.
 func (*repoStore) SetRepoNameByName(ctx context.Context, tx db.Handler, name string, newName string) error {
 	name = utils.SanitizeRepo(name)
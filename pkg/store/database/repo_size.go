@@ -0,0 +1,42 @@
+package database
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+)
+
+type repoSizeStore struct{}
+
+var _ store.RepoSizeStore = (*repoSizeStore)(nil)
+
+// CreateRepoSize implements store.RepoSizeStore.
+func (*repoSizeStore) CreateRepoSize(ctx context.Context, h db.Handler, repoID int64, size int64, objects int64) error {
+	query := h.Rebind(`INSERT INTO repo_sizes (repo_id, size, objects, created_at)
+			VALUES (?, ?, ?, CURRENT_TIMESTAMP);`)
+	_, err := h.ExecContext(ctx, query, repoID, size, objects)
+	return err
+}
+
+// GetRepoSizesByRepoID implements store.RepoSizeStore.
+func (*repoSizeStore) GetRepoSizesByRepoID(ctx context.Context, h db.Handler, repoID int64, limit int) ([]models.RepoSize, error) {
+	var m []models.RepoSize
+	query := h.Rebind(`SELECT * FROM repo_sizes
+		WHERE repo_id = ?
+		ORDER BY id DESC
+		LIMIT ?;`)
+	err := h.SelectContext(ctx, &m, query, repoID, limit)
+	return m, err
+}
+
+// DeleteOldRepoSizes implements store.RepoSizeStore.
+func (*repoSizeStore) DeleteOldRepoSizes(ctx context.Context, h db.Handler, repoID int64, keep int) error {
+	query := h.Rebind(`DELETE FROM repo_sizes
+		WHERE repo_id = ? AND id NOT IN (
+			SELECT id FROM repo_sizes WHERE repo_id = ? ORDER BY id DESC LIMIT ?
+		);`)
+	_, err := h.ExecContext(ctx, query, repoID, repoID, keep)
+	return err
+}
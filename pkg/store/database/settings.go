@@ -45,3 +45,54 @@ func (*settingsStore) SetAnonAccess(ctx context.Context, tx db.Handler, level ac
 	_, err := tx.ExecContext(ctx, query, level.String())
 	return db.WrapError(err)
 }
+
+// GetMaintenanceMode implements store.SettingStore.
+func (*settingsStore) GetMaintenanceMode(ctx context.Context, tx db.Handler) (bool, error) {
+	var enabled bool
+	query := tx.Rebind(`SELECT value FROM settings WHERE "key" = 'maintenance'`)
+	if err := tx.GetContext(ctx, &enabled, query); err != nil {
+		return false, db.WrapError(err)
+	}
+	return enabled, nil
+}
+
+// SetMaintenanceMode implements store.SettingStore.
+func (*settingsStore) SetMaintenanceMode(ctx context.Context, tx db.Handler, enabled bool) error {
+	query := tx.Rebind(`UPDATE settings SET value = ?, updated_at = CURRENT_TIMESTAMP WHERE "key" = 'maintenance'`)
+	_, err := tx.ExecContext(ctx, query, enabled)
+	return db.WrapError(err)
+}
+
+// GetMaintenanceMessage implements store.SettingStore.
+func (*settingsStore) GetMaintenanceMessage(ctx context.Context, tx db.Handler) (string, error) {
+	var message string
+	query := tx.Rebind(`SELECT value FROM settings WHERE "key" = 'maintenance_message'`)
+	if err := tx.GetContext(ctx, &message, query); err != nil {
+		return "", db.WrapError(err)
+	}
+	return message, nil
+}
+
+// SetMaintenanceMessage implements store.SettingStore.
+func (*settingsStore) SetMaintenanceMessage(ctx context.Context, tx db.Handler, message string) error {
+	query := tx.Rebind(`UPDATE settings SET value = ?, updated_at = CURRENT_TIMESTAMP WHERE "key" = 'maintenance_message'`)
+	_, err := tx.ExecContext(ctx, query, message)
+	return db.WrapError(err)
+}
+
+// GetMaintenanceAdminsExempt implements store.SettingStore.
+func (*settingsStore) GetMaintenanceAdminsExempt(ctx context.Context, tx db.Handler) (bool, error) {
+	var exempt bool
+	query := tx.Rebind(`SELECT value FROM settings WHERE "key" = 'maintenance_admins_exempt'`)
+	if err := tx.GetContext(ctx, &exempt, query); err != nil {
+		return false, db.WrapError(err)
+	}
+	return exempt, nil
+}
+
+// SetMaintenanceAdminsExempt implements store.SettingStore.
+func (*settingsStore) SetMaintenanceAdminsExempt(ctx context.Context, tx db.Handler, exempt bool) error {
+	query := tx.Rebind(`UPDATE settings SET value = ?, updated_at = CURRENT_TIMESTAMP WHERE "key" = 'maintenance_admins_exempt'`)
+	_, err := tx.ExecContext(ctx, query, exempt)
+	return db.WrapError(err)
+}
@@ -0,0 +1,254 @@
+package database
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/charmbracelet/soft-serve/pkg/store"
+)
+
+type teamStore struct{}
+
+var _ store.TeamStore = (*teamStore)(nil)
+
+// teamReposQuery selects the access of teams to repositories with the names
+// of the teams and the repositories.
+const teamReposQuery = `
+	SELECT
+		team_repos.*,
+		teams.name AS team_name,
+		repos.name AS repo_name
+	FROM
+		team_repos
+	INNER JOIN teams ON teams.id = team_repos.team_id
+	INNER JOIN repos ON repos.id = team_repos.repo_id
+`
+
+// CreateTeam implements store.TeamStore.
+func (*teamStore) CreateTeam(ctx context.Context, h db.Handler, name string) error {
+	query := h.Rebind(`INSERT INTO teams (name, updated_at) VALUES (?, CURRENT_TIMESTAMP);`)
+	_, err := h.ExecContext(ctx, query, name)
+	return err
+}
+
+// DeleteTeamByName implements store.TeamStore.
+func (*teamStore) DeleteTeamByName(ctx context.Context, h db.Handler, name string) error {
+	query := h.Rebind(`DELETE FROM teams WHERE name = ?;`)
+	res, err := h.ExecContext(ctx, query, name)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return db.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetTeamByName implements store.TeamStore.
+func (*teamStore) GetTeamByName(ctx context.Context, h db.Handler, name string) (models.Team, error) {
+	var m models.Team
+	query := h.Rebind(`SELECT * FROM teams WHERE name = ?;`)
+	err := h.GetContext(ctx, &m, query, name)
+	return m, err
+}
+
+// ListTeams implements store.TeamStore.
+func (*teamStore) ListTeams(ctx context.Context, h db.Handler) ([]models.Team, error) {
+	var m []models.Team
+	query := h.Rebind(`SELECT * FROM teams ORDER BY name ASC;`)
+	err := h.SelectContext(ctx, &m, query)
+	return m, err
+}
+
+// ListTeamsByUserID implements store.TeamStore.
+func (*teamStore) ListTeamsByUserID(ctx context.Context, h db.Handler, userID int64) ([]models.Team, error) {
+	var m []models.Team
+	query := h.Rebind(`
+		SELECT
+			teams.*
+		FROM
+			teams
+		INNER JOIN team_members ON team_members.team_id = teams.id
+		WHERE
+			team_members.user_id = ?
+		ORDER BY teams.name ASC
+	`)
+	err := h.SelectContext(ctx, &m, query, userID)
+	return m, err
+}
+
+// AddTeamMemberByUsername implements store.TeamStore.
+func (*teamStore) AddTeamMemberByUsername(ctx context.Context, h db.Handler, team string, username string) error {
+	query := h.Rebind(`INSERT INTO team_members (team_id, user_id, updated_at)
+			VALUES (
+				(
+					SELECT id FROM teams WHERE name = ?
+				),
+				(
+					SELECT id FROM users WHERE username = ?
+				),
+				CURRENT_TIMESTAMP
+			);`)
+	_, err := h.ExecContext(ctx, query, team, username)
+	return err
+}
+
+// RemoveTeamMemberByUsername implements store.TeamStore.
+func (*teamStore) RemoveTeamMemberByUsername(ctx context.Context, h db.Handler, team string, username string) error {
+	query := h.Rebind(`
+		DELETE FROM
+			team_members
+		WHERE
+			team_id = (
+				SELECT id FROM teams WHERE name = ?
+			) AND user_id = (
+				SELECT id FROM users WHERE username = ?
+			)
+	`)
+	res, err := h.ExecContext(ctx, query, team, username)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return db.ErrRecordNotFound
+	}
+	return nil
+}
+
+// SetTeamMemberSyncedByUsername implements store.TeamStore.
+func (*teamStore) SetTeamMemberSyncedByUsername(ctx context.Context, h db.Handler, team string, username string, synced bool) error {
+	query := h.Rebind(`
+		UPDATE
+			team_members
+		SET
+			synced = ?,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			team_id = (
+				SELECT id FROM teams WHERE name = ?
+			) AND user_id = (
+				SELECT id FROM users WHERE username = ?
+			)
+	`)
+	res, err := h.ExecContext(ctx, query, synced, team, username)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return db.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ListSyncedTeamsByUserID implements store.TeamStore.
+func (*teamStore) ListSyncedTeamsByUserID(ctx context.Context, h db.Handler, userID int64) ([]models.Team, error) {
+	var m []models.Team
+	query := h.Rebind(`
+		SELECT
+			teams.*
+		FROM
+			teams
+		INNER JOIN team_members ON team_members.team_id = teams.id
+		WHERE
+			team_members.user_id = ? AND team_members.synced = ?
+		ORDER BY teams.name ASC
+	`)
+	err := h.SelectContext(ctx, &m, query, userID, true)
+	return m, err
+}
+
+// ListTeamMembersAsUsers implements store.TeamStore.
+func (*teamStore) ListTeamMembersAsUsers(ctx context.Context, h db.Handler, team string) ([]models.User, error) {
+	var m []models.User
+	query := h.Rebind(`
+		SELECT
+			users.*
+		FROM
+			users
+		INNER JOIN team_members ON team_members.user_id = users.id
+		INNER JOIN teams ON teams.id = team_members.team_id
+		WHERE
+			teams.name = ?
+		ORDER BY users.username ASC
+	`)
+	err := h.SelectContext(ctx, &m, query, team)
+	return m, err
+}
+
+// AddTeamRepo implements store.TeamStore.
+func (*teamStore) AddTeamRepo(ctx context.Context, h db.Handler, team string, repo string, level access.AccessLevel) error {
+	query := h.Rebind(`INSERT INTO team_repos (access_level, team_id, repo_id, updated_at)
+			VALUES (
+				?,
+				(
+					SELECT id FROM teams WHERE name = ?
+				),
+				(
+					SELECT id FROM repos WHERE name = ?
+				),
+				CURRENT_TIMESTAMP
+			);`)
+	_, err := h.ExecContext(ctx, query, level, team, repo)
+	return err
+}
+
+// RemoveTeamRepo implements store.TeamStore.
+func (*teamStore) RemoveTeamRepo(ctx context.Context, h db.Handler, team string, repo string) error {
+	query := h.Rebind(`
+		DELETE FROM
+			team_repos
+		WHERE
+			team_id = (
+				SELECT id FROM teams WHERE name = ?
+			) AND repo_id = (
+				SELECT id FROM repos WHERE name = ?
+			)
+	`)
+	res, err := h.ExecContext(ctx, query, team, repo)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return db.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ListTeamReposByTeam implements store.TeamStore.
+func (*teamStore) ListTeamReposByTeam(ctx context.Context, h db.Handler, team string) ([]models.TeamRepo, error) {
+	var m []models.TeamRepo
+	query := h.Rebind(teamReposQuery + `
+		WHERE
+			teams.name = ?
+		ORDER BY repos.name ASC
+	`)
+	err := h.SelectContext(ctx, &m, query, team)
+	return m, err
+}
+
+// ListTeamReposByRepo implements store.TeamStore.
+func (*teamStore) ListTeamReposByRepo(ctx context.Context, h db.Handler, repo string) ([]models.TeamRepo, error) {
+	var m []models.TeamRepo
+	query := h.Rebind(teamReposQuery + `
+		WHERE
+			repos.name = ?
+		ORDER BY teams.name ASC
+	`)
+	err := h.SelectContext(ctx, &m, query, repo)
+	return m, err
+}
+
+// ListTeamReposByUserID implements store.TeamStore.
+func (*teamStore) ListTeamReposByUserID(ctx context.Context, h db.Handler, userID int64) ([]models.TeamRepo, error) {
+	var m []models.TeamRepo
+	query := h.Rebind(teamReposQuery + `
+		INNER JOIN team_members ON team_members.team_id = team_repos.team_id
+		WHERE
+			team_members.user_id = ?
+		ORDER BY teams.name ASC, repos.name ASC
+	`)
+	err := h.SelectContext(ctx, &m, query, userID)
+	return m, err
+}
@@ -193,6 +193,62 @@ func (*userStore) RemovePublicKeyByUsername(ctx context.Context, tx db.Handler,
 	return err
 }
 
+// GetPublicKeyOptions implements store.UserStore.
+func (*userStore) GetPublicKeyOptions(ctx context.Context, tx db.Handler, pk ssh.PublicKey) (string, error) {
+	var options string
+	query := tx.Rebind(`SELECT options FROM public_keys WHERE public_key = ?;`)
+	err := tx.GetContext(ctx, &options, query, sshutils.MarshalAuthorizedKey(pk))
+	return options, err
+}
+
+// SetPublicKeyOptionsByUsername implements store.UserStore.
+func (*userStore) SetPublicKeyOptionsByUsername(ctx context.Context, tx db.Handler, username string, pk ssh.PublicKey, options string) error {
+	username = strings.ToLower(username)
+	if err := utils.ValidateUsername(username); err != nil {
+		return err
+	}
+
+	query := tx.Rebind(`UPDATE public_keys SET options = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE user_id = (SELECT id FROM users WHERE username = ?)
+			AND public_key = ?;`)
+	res, err := tx.ExecContext(ctx, query, options, username, sshutils.MarshalAuthorizedKey(pk))
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return db.ErrRecordNotFound
+	}
+	return nil
+}
+
+// IsPublicKeySynced implements store.UserStore.
+func (*userStore) IsPublicKeySynced(ctx context.Context, tx db.Handler, pk ssh.PublicKey) (bool, error) {
+	var synced bool
+	query := tx.Rebind(`SELECT synced FROM public_keys WHERE public_key = ?;`)
+	err := tx.GetContext(ctx, &synced, query, sshutils.MarshalAuthorizedKey(pk))
+	return synced, err
+}
+
+// SetPublicKeySyncedByUsername implements store.UserStore.
+func (*userStore) SetPublicKeySyncedByUsername(ctx context.Context, tx db.Handler, username string, pk ssh.PublicKey, synced bool) error {
+	username = strings.ToLower(username)
+	if err := utils.ValidateUsername(username); err != nil {
+		return err
+	}
+
+	query := tx.Rebind(`UPDATE public_keys SET synced = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE user_id = (SELECT id FROM users WHERE username = ?)
+			AND public_key = ?;`)
+	res, err := tx.ExecContext(ctx, query, synced, username, sshutils.MarshalAuthorizedKey(pk))
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return db.ErrRecordNotFound
+	}
+	return nil
+}
+
 // SetAdminByUsername implements store.UserStore.
 func (*userStore) SetAdminByUsername(ctx context.Context, tx db.Handler, username string, isAdmin bool) error {
 	username = strings.ToLower(username)
@@ -222,6 +278,22 @@ func (*userStore) SetUsernameByUsername(ctx context.Context, tx db.Handler, user
 	return err
 }
 
+// SetNameByUsername implements store.UserStore.
+func (*userStore) SetNameByUsername(ctx context.Context, tx db.Handler, username string, name string) error {
+	username = strings.ToLower(username)
+	if err := utils.ValidateUsername(username); err != nil {
+		return err
+	}
+
+	if err := utils.ValidateName(name); err != nil {
+		return err
+	}
+
+	query := tx.Rebind(`UPDATE users SET name = ? WHERE username = ?;`)
+	_, err := tx.ExecContext(ctx, query, name, username)
+	return err
+}
+
 // SetUserPassword implements store.UserStore.
 func (*userStore) SetUserPassword(ctx context.Context, tx db.Handler, userID int64, password string) error {
 	query := tx.Rebind(`UPDATE users SET password = ? WHERE id = ?;`)
@@ -0,0 +1,21 @@
+package store
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+// DeployStore is an interface for managing the deploy scripts bound to the
+// branches of repositories.
+type DeployStore interface {
+	// GetDeploysByRepoID returns the deploy scripts bound to the branches of
+	// a repository ordered by branch.
+	GetDeploysByRepoID(ctx context.Context, h db.Handler, repoID int64) ([]models.Deploy, error)
+	// SetDeploy binds a deploy script to a branch of a repository, replacing
+	// the script bound to it.
+	SetDeploy(ctx context.Context, h db.Handler, repoID int64, branch string, script string) error
+	// DeleteDeploy unbinds the deploy script of a branch of a repository.
+	DeleteDeploy(ctx context.Context, h db.Handler, repoID int64, branch string) error
+}
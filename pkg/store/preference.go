@@ -0,0 +1,22 @@
+package store
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+// PreferenceStore is an interface for managing the preferences of public
+// keys.
+type PreferenceStore interface {
+	// GetPreference returns a preference of a public key.
+	GetPreference(ctx context.Context, h db.Handler, publicKey string, name string) (models.Preference, error)
+	// SetPreference creates or replaces a preference of a public key.
+	SetPreference(ctx context.Context, h db.Handler, publicKey string, name string, value string) error
+	// ListPreferencesByName returns the preferences with the given name of
+	// all the public keys.
+	ListPreferencesByName(ctx context.Context, h db.Handler, name string) ([]models.Preference, error)
+	// DeletePreference deletes a preference of a public key.
+	DeletePreference(ctx context.Context, h db.Handler, publicKey string, name string) error
+}
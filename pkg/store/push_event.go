@@ -0,0 +1,29 @@
+package store
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+// PushEventStore is an interface for managing the push activity of
+// repositories.
+type PushEventStore interface {
+	// CreatePushEvent records a reference update pushed to a repository. A
+	// zero userID means the pusher is unknown. Forced is true if the update
+	// isn't a fast-forward.
+	CreatePushEvent(ctx context.Context, h db.Handler, repoID int64, userID int64, refName string, oldSHA string, newSHA string, commits int64, forced bool) error
+	// SetPushEventDeploy sets the deploy script, status, and output of the
+	// latest push event of a repository that updated the reference to the
+	// given commit.
+	SetPushEventDeploy(ctx context.Context, h db.Handler, repoID int64, refName string, newSHA string, script string, status string, output string) error
+	// GetPushEvents returns the push events of all repositories, newest first.
+	GetPushEvents(ctx context.Context, h db.Handler, limit int, offset int) ([]models.PushEvent, error)
+	// GetPushEventsByRepoID returns the push events of a repository, newest
+	// first.
+	GetPushEventsByRepoID(ctx context.Context, h db.Handler, repoID int64, limit int, offset int) ([]models.PushEvent, error)
+	// GetPushEventsAfterID returns the push events recorded after the one
+	// with the given ID, oldest first.
+	GetPushEventsAfterID(ctx context.Context, h db.Handler, id int64, limit int) ([]models.PushEvent, error)
+}
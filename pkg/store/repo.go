@@ -15,6 +15,7 @@ type RepositoryStore interface {
 	CreateRepo(ctx context.Context, h db.Handler, name string, userID int64, projectName string, description string, isPrivate bool, isHidden bool, isMirror bool) error
 	DeleteRepoByName(ctx context.Context, h db.Handler, name string) error
 	SetRepoNameByName(ctx context.Context, h db.Handler, name string, newName string) error
+	SetRepoUserIDByName(ctx context.Context, h db.Handler, name string, userID int64) error
 
 	GetRepoProjectNameByName(ctx context.Context, h db.Handler, name string) (string, error)
 	SetRepoProjectNameByName(ctx context.Context, h db.Handler, name string, projectName string) error
@@ -24,5 +25,20 @@ type RepositoryStore interface {
 	SetRepoIsPrivateByName(ctx context.Context, h db.Handler, name string, isPrivate bool) error
 	GetRepoIsHiddenByName(ctx context.Context, h db.Handler, name string) (bool, error)
 	SetRepoIsHiddenByName(ctx context.Context, h db.Handler, name string, isHidden bool) error
+	GetRepoIsArchivedByName(ctx context.Context, h db.Handler, name string) (bool, error)
+	SetRepoIsArchivedByName(ctx context.Context, h db.Handler, name string, isArchived bool) error
 	GetRepoIsMirrorByName(ctx context.Context, h db.Handler, name string) (bool, error)
+	GetRepoParentByName(ctx context.Context, h db.Handler, name string) (string, error)
+	SetRepoParentByName(ctx context.Context, h db.Handler, name string, parent string) error
+	GetRepoForksByName(ctx context.Context, h db.Handler, name string) ([]string, error)
+	GetRepoLandingTabByName(ctx context.Context, h db.Handler, name string) (string, error)
+	SetRepoLandingTabByName(ctx context.Context, h db.Handler, name string, tab string) error
+	GetRepoReadmePathByName(ctx context.Context, h db.Handler, name string) (string, error)
+	SetRepoReadmePathByName(ctx context.Context, h db.Handler, name string, path string) error
+	GetRepoPushLimitsByName(ctx context.Context, h db.Handler, name string) (models.PushLimits, error)
+	SetRepoPushLimitsByName(ctx context.Context, h db.Handler, name string, limits models.PushLimits) error
+	SetRepoAvatarByName(ctx context.Context, h db.Handler, name string, path string) error
+	SetRepoMirrorSyncByName(ctx context.Context, h db.Handler, name string, sync models.MirrorSync) error
+	SetRepoCloneInstructionsByName(ctx context.Context, h db.Handler, name string, instructions string) error
+	SetRepoAttestationByName(ctx context.Context, h db.Handler, name string, attestation models.Attestation) error
 }
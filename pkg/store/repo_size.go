@@ -0,0 +1,22 @@
+package store
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+// RepoSizeStore is an interface for managing the size snapshots of
+// repositories.
+type RepoSizeStore interface {
+	// CreateRepoSize records the size and the number of objects of a
+	// repository.
+	CreateRepoSize(ctx context.Context, h db.Handler, repoID int64, size int64, objects int64) error
+	// GetRepoSizesByRepoID returns the latest size snapshots of a repository,
+	// newest first.
+	GetRepoSizesByRepoID(ctx context.Context, h db.Handler, repoID int64, limit int) ([]models.RepoSize, error)
+	// DeleteOldRepoSizes deletes the size snapshots of a repository but the
+	// newest keep ones.
+	DeleteOldRepoSizes(ctx context.Context, h db.Handler, repoID int64, keep int) error
+}
@@ -13,4 +13,10 @@ type SettingStore interface {
 	SetAnonAccess(ctx context.Context, h db.Handler, level access.AccessLevel) error
 	GetAllowKeylessAccess(ctx context.Context, h db.Handler) (bool, error)
 	SetAllowKeylessAccess(ctx context.Context, h db.Handler, allow bool) error
+	GetMaintenanceMode(ctx context.Context, h db.Handler) (bool, error)
+	SetMaintenanceMode(ctx context.Context, h db.Handler, enabled bool) error
+	GetMaintenanceMessage(ctx context.Context, h db.Handler) (string, error)
+	SetMaintenanceMessage(ctx context.Context, h db.Handler, message string) error
+	GetMaintenanceAdminsExempt(ctx context.Context, h db.Handler) (bool, error)
+	SetMaintenanceAdminsExempt(ctx context.Context, h db.Handler, exempt bool) error
 }
@@ -9,4 +9,12 @@ type Store interface {
 	LFSStore
 	AccessTokenStore
 	WebhookStore
+	CommitStatusStore
+	PushEventStore
+	CommandAliasStore
+	PreferenceStore
+	BranchProtectionStore
+	DeployStore
+	RepoSizeStore
+	TeamStore
 }
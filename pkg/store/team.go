@@ -0,0 +1,44 @@
+package store
+
+import (
+	"context"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/db"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+)
+
+// TeamStore is an interface for managing teams, their members, and their
+// access to repositories.
+type TeamStore interface {
+	CreateTeam(ctx context.Context, h db.Handler, name string) error
+	DeleteTeamByName(ctx context.Context, h db.Handler, name string) error
+	GetTeamByName(ctx context.Context, h db.Handler, name string) (models.Team, error)
+	// ListTeams returns the teams ordered by name.
+	ListTeams(ctx context.Context, h db.Handler) ([]models.Team, error)
+	// ListTeamsByUserID returns the teams a user is a member of ordered by
+	// name.
+	ListTeamsByUserID(ctx context.Context, h db.Handler, userID int64) ([]models.Team, error)
+
+	AddTeamMemberByUsername(ctx context.Context, h db.Handler, team string, username string) error
+	RemoveTeamMemberByUsername(ctx context.Context, h db.Handler, team string, username string) error
+	SetTeamMemberSyncedByUsername(ctx context.Context, h db.Handler, team string, username string, synced bool) error
+	// ListSyncedTeamsByUserID returns the teams a user is a member of from
+	// the identity source ordered by name.
+	ListSyncedTeamsByUserID(ctx context.Context, h db.Handler, userID int64) ([]models.Team, error)
+	// ListTeamMembersAsUsers returns the members of a team ordered by
+	// username.
+	ListTeamMembersAsUsers(ctx context.Context, h db.Handler, team string) ([]models.User, error)
+
+	AddTeamRepo(ctx context.Context, h db.Handler, team string, repo string, level access.AccessLevel) error
+	RemoveTeamRepo(ctx context.Context, h db.Handler, team string, repo string) error
+	// ListTeamReposByTeam returns the access of a team to repositories
+	// ordered by repository name.
+	ListTeamReposByTeam(ctx context.Context, h db.Handler, team string) ([]models.TeamRepo, error)
+	// ListTeamReposByRepo returns the access of teams to a repository
+	// ordered by team name.
+	ListTeamReposByRepo(ctx context.Context, h db.Handler, repo string) ([]models.TeamRepo, error)
+	// ListTeamReposByUserID returns the access of the teams a user is a
+	// member of to repositories.
+	ListTeamReposByUserID(ctx context.Context, h db.Handler, userID int64) ([]models.TeamRepo, error)
+}
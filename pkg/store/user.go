@@ -18,11 +18,18 @@ type UserStore interface {
 	CreateUser(ctx context.Context, h db.Handler, username string, isAdmin bool, pks []ssh.PublicKey) error
 	DeleteUserByUsername(ctx context.Context, h db.Handler, username string) error
 	SetUsernameByUsername(ctx context.Context, h db.Handler, username string, newUsername string) error
+	SetNameByUsername(ctx context.Context, h db.Handler, username string, name string) error
 	SetAdminByUsername(ctx context.Context, h db.Handler, username string, isAdmin bool) error
 	AddPublicKeyByUsername(ctx context.Context, h db.Handler, username string, pk ssh.PublicKey) error
 	RemovePublicKeyByUsername(ctx context.Context, h db.Handler, username string, pk ssh.PublicKey) error
 	ListPublicKeysByUserID(ctx context.Context, h db.Handler, id int64) ([]ssh.PublicKey, error)
 	ListPublicKeysByUsername(ctx context.Context, h db.Handler, username string) ([]ssh.PublicKey, error)
+	GetPublicKeyOptions(ctx context.Context, h db.Handler, pk ssh.PublicKey) (string, error)
+	SetPublicKeyOptionsByUsername(ctx context.Context, h db.Handler, username string, pk ssh.PublicKey, options string) error
+	// IsPublicKeySynced returns whether a public key was added from the
+	// identity source.
+	IsPublicKeySynced(ctx context.Context, h db.Handler, pk ssh.PublicKey) (bool, error)
+	SetPublicKeySyncedByUsername(ctx context.Context, h db.Handler, username string, pk ssh.PublicKey, synced bool) error
 	SetUserPassword(ctx context.Context, h db.Handler, userID int64, password string) error
 	SetUserPasswordByUsername(ctx context.Context, h db.Handler, username string, password string) error
 }
@@ -0,0 +1,74 @@
+package common
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/pkg/avatar"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/muesli/termenv"
+)
+
+// Avatar returns the avatar of the repository, size cells wide and half as
+// high. Avatar images need a terminal with at least 256 colors, other
+// terminals and repositories without an avatar get an identicon.
+func (c *Common) Avatar(repo proto.Repository, size int) string {
+	var img image.Image
+	if be := c.Backend(); be != nil && c.Renderer.ColorProfile() <= termenv.ANSI256 {
+		thumb, err := be.Avatar(c.ctx, repo.Name())
+		if err != nil {
+			c.Logger.Debugf("ui: error loading avatar of %s: %v", repo.Name(), err)
+		}
+		if thumb != nil {
+			img = avatar.Thumbnail(thumb, size)
+		}
+	}
+	if img == nil {
+		img = avatar.Identicon(repo.Name(), size)
+	}
+	return renderPixels(c.Renderer, img)
+}
+
+// renderPixels renders the image with half blocks, every cell shows two
+// pixels on top of each other. Transparent pixels are left blank.
+func renderPixels(r *lipgloss.Renderer, img image.Image) string {
+	b := img.Bounds()
+	lines := make([]string, 0, (b.Dy()+1)/2)
+	for y := b.Min.Y; y < b.Max.Y; y += 2 {
+		var s strings.Builder
+		for x := b.Min.X; x < b.Max.X; x++ {
+			top, topOk := pixelColor(img.At(x, y))
+			bottom, bottomOk := lipgloss.Color(""), false
+			if y+1 < b.Max.Y {
+				bottom, bottomOk = pixelColor(img.At(x, y+1))
+			}
+			switch {
+			case topOk && bottomOk && top == bottom:
+				s.WriteString(r.NewStyle().Foreground(top).Render("█"))
+			case topOk && bottomOk:
+				s.WriteString(r.NewStyle().Foreground(top).Background(bottom).Render("▀"))
+			case topOk:
+				s.WriteString(r.NewStyle().Foreground(top).Render("▀"))
+			case bottomOk:
+				s.WriteString(r.NewStyle().Foreground(bottom).Render("▄"))
+			default:
+				s.WriteString(" ")
+			}
+		}
+		lines = append(lines, s.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// pixelColor returns the color of a pixel, and false if it's mostly
+// transparent.
+func pixelColor(c color.Color) (lipgloss.Color, bool) {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if n.A < 0x80 {
+		return "", false
+	}
+	return lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B)), true
+}
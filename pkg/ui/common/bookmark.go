@@ -0,0 +1,78 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/git"
+)
+
+// BookmarksPreference is the name of the preference that holds the files the
+// user bookmarked in the terminal UI, one per line.
+const BookmarksPreference = "ui.bookmarks"
+
+// Bookmark is a file of a repository at a reference, and optionally one of
+// its lines.
+type Bookmark struct {
+	Repo string
+	// Ref is the full name of the reference, e.g. "refs/heads/main".
+	Ref  string
+	Path string
+	// Line is the one based line of the file, 0 for the whole file.
+	Line int
+}
+
+// String returns the bookmark as "repo@ref:path:line".
+func (b Bookmark) String() string {
+	s := fmt.Sprintf("%s@%s:%s", b.Repo, git.ReferenceName(b.Ref).Short(), b.Path)
+	if b.Line > 0 {
+		s += ":" + strconv.Itoa(b.Line)
+	}
+	return s
+}
+
+// ParseBookmarks returns the bookmarks of a preference. Each line holds the
+// repository, the reference, the path, and the line of a bookmark separated by
+// tabs. Malformed lines are skipped.
+func ParseBookmarks(spec string) []Bookmark {
+	var bms []Bookmark
+	for _, l := range strings.Split(spec, "\n") {
+		fields := strings.Split(l, "\t")
+		if len(fields) != 4 || fields[0] == "" || fields[1] == "" || fields[2] == "" {
+			continue
+		}
+		line, err := strconv.Atoi(fields[3])
+		if err != nil || line < 0 {
+			continue
+		}
+		bms = append(bms, Bookmark{
+			Repo: fields[0],
+			Ref:  fields[1],
+			Path: fields[2],
+			Line: line,
+		})
+	}
+	return bms
+}
+
+// FormatBookmarks returns the preference of the bookmarks, the inverse of
+// ParseBookmarks.
+func FormatBookmarks(bms []Bookmark) string {
+	lines := make([]string, 0, len(bms))
+	for _, b := range bms {
+		lines = append(lines, strings.Join([]string{b.Repo, b.Ref, b.Path, strconv.Itoa(b.Line)}, "\t"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ToggleBookmark removes the bookmark if it's in the list, or adds it to the
+// front of the list. It reports whether the bookmark was added.
+func ToggleBookmark(bms []Bookmark, b Bookmark) ([]Bookmark, bool) {
+	for i, o := range bms {
+		if o == b {
+			return append(bms[:i:i], bms[i+1:]...), false
+		}
+	}
+	return append([]Bookmark{b}, bms...), true
+}
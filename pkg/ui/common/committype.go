@@ -0,0 +1,58 @@
+package common
+
+import (
+	"regexp"
+	"strings"
+)
+
+// commitTypeRe matches the "type(scope)!: description" subjects of
+// Conventional Commits.
+var commitTypeRe = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()]*)\))?(!)?: +(\S.*)$`)
+
+// commitTypes are the commit types that get a badge.
+var commitTypes = map[string]bool{
+	"feat":     true,
+	"fix":      true,
+	"docs":     true,
+	"style":    true,
+	"refactor": true,
+	"perf":     true,
+	"test":     true,
+	"build":    true,
+	"ci":       true,
+	"chore":    true,
+	"revert":   true,
+}
+
+// CommitType is the type of a commit following the Conventional Commits
+// convention.
+type CommitType struct {
+	// Type is the lower case type, e.g. "feat" or "fix".
+	Type string
+	// Scope is the optional scope of the change.
+	Scope string
+	// Breaking is true if the subject marks a breaking change with "!".
+	Breaking bool
+	// Description is the subject without the type and scope.
+	Description string
+}
+
+// ParseCommitType parses the type and scope of a commit subject. It returns
+// false for subjects that don't follow the convention or that use an unknown
+// type.
+func ParseCommitType(subject string) (CommitType, bool) {
+	m := commitTypeRe.FindStringSubmatch(strings.TrimSpace(subject))
+	if m == nil {
+		return CommitType{}, false
+	}
+	typ := strings.ToLower(m[1])
+	if !commitTypes[typ] {
+		return CommitType{}, false
+	}
+	return CommitType{
+		Type:        typ,
+		Scope:       strings.TrimSpace(m[2]),
+		Breaking:    m[3] != "",
+		Description: m[4],
+	}, true
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/charmbracelet/soft-serve/git"
 	"github.com/charmbracelet/soft-serve/pkg/backend"
 	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/ui/keymap"
 	"github.com/charmbracelet/soft-serve/pkg/ui/styles"
 	"github.com/charmbracelet/ssh"
@@ -102,12 +103,17 @@ func (c *Common) PublicKey() ssh.PublicKey {
 	return nil
 }
 
+// User returns the authenticated user from the context.
+func (c *Common) User() proto.User {
+	return proto.UserFromContext(c.ctx)
+}
+
 // CloneCmd returns the clone command string.
-func (c *Common) CloneCmd(publicURL, name string) string {
+func (c *Common) CloneCmd(cfg *config.Config, name string) string {
 	if c.HideCloneCmd {
 		return ""
 	}
-	return fmt.Sprintf("git clone %s", RepoURL(publicURL, name))
+	return fmt.Sprintf("git clone %s", CloneURL(cfg, name))
 }
 
 // IsFileMarkdown returns true if the file is markdown.
@@ -1,8 +1,12 @@
 package common_test
 
 import (
+	"strings"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/pkg/config"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
 )
 
@@ -28,3 +32,425 @@ func TestIsFileMarkdown(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectEncoding(t *testing.T) {
+	cases := []struct {
+		name    string
+		content []byte
+		want    string
+	}{
+		{"empty", []byte{}, "UTF-8"},
+		{"ascii", []byte("hello world"), "UTF-8"},
+		{"utf-8", []byte("héllo wörld"), "UTF-8"},
+		{"utf-8 bom", []byte("\xef\xbb\xbfhello"), "UTF-8 BOM"},
+		{"utf-16le", []byte("\xff\xfeh\x00i\x00"), "UTF-16LE"},
+		{"utf-16be", []byte("\xfe\xff\x00h\x00i"), "UTF-16BE"},
+		{"latin1", []byte("h\xe9llo"), "latin1"},
+		{"cut rune", []byte(strings.Repeat("a", 8*1024-1) + "é"), "UTF-8"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := common.DetectEncoding(c.content); got != c.want {
+				t.Errorf("DetectEncoding() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestDetectLineEnding(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"none", "hello", ""},
+		{"lf", "hello\nworld\n", "LF"},
+		{"crlf", "hello\r\nworld\r\n", "CRLF"},
+		{"mixed", "hello\r\nworld\n", "mixed"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := common.DetectLineEnding([]byte(c.content)); got != c.want {
+				t.Errorf("DetectLineEnding() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestFileURL(t *testing.T) {
+	sha := "5ab5ee9a0d0a2d4a4a0cd3d4e3a8e2b21c7d1f5e"
+	cases := []struct {
+		name     string
+		httpAddr string
+		httpURL  string
+		sshURL   string
+		want     string
+	}{
+		{
+			name:     "http",
+			httpAddr: ":23232",
+			httpURL:  "https://git.example.com",
+			sshURL:   "ssh://git.example.com:23231",
+			want:     "https://git.example.com/repo1/blob/" + sha + "/dir/file.go",
+		},
+		{
+			name:   "ssh",
+			sshURL: "ssh://git.example.com:23231",
+			want:   "ssh -p 23231 git.example.com repo blob repo1 " + sha + " dir/file.go",
+		},
+		{
+			name:   "ssh default port",
+			sshURL: "ssh://git.example.com",
+			want:   "ssh git.example.com repo blob repo1 " + sha + " dir/file.go",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.HTTP.ListenAddr = c.httpAddr
+			cfg.HTTP.PublicURL = c.httpURL
+			cfg.SSH.PublicURL = c.sshURL
+			if got := common.FileURL(cfg, "repo1.git", sha, "/dir/file.go"); got != c.want {
+				t.Errorf("FileURL() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestFileLinesURL(t *testing.T) {
+	sha := "5ab5ee9a0d0a2d4a4a0cd3d4e3a8e2b21c7d1f5e"
+	cases := []struct {
+		name       string
+		httpAddr   string
+		start, end int
+		want       string
+	}{
+		{
+			name:     "http range",
+			httpAddr: ":23232",
+			start:    3,
+			end:      5,
+			want:     "https://git.example.com/repo1/blob/" + sha + "/file.go#L3-L5",
+		},
+		{
+			name:     "http line",
+			httpAddr: ":23232",
+			start:    3,
+			end:      3,
+			want:     "https://git.example.com/repo1/blob/" + sha + "/file.go#L3",
+		},
+		{
+			name:  "ssh range",
+			start: 3,
+			end:   5,
+			want:  "ssh -p 23231 git.example.com repo blob repo1 " + sha + " file.go --offset 2 --limit 3",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.HTTP.ListenAddr = c.httpAddr
+			cfg.HTTP.PublicURL = "https://git.example.com"
+			cfg.SSH.PublicURL = "ssh://git.example.com:23231"
+			if got := common.FileLinesURL(cfg, "repo1", sha, "file.go", c.start, c.end); got != c.want {
+				t.Errorf("FileLinesURL() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestParseLogColumns(t *testing.T) {
+	cols, err := common.ParseLogColumns("hash, Author:12,age,subject")
+	if err != nil {
+		t.Fatalf("ParseLogColumns() => %v, want nil error", err)
+	}
+	if got, want := common.FormatLogColumns(cols), "hash:7,author:12,age:14,subject"; got != want {
+		t.Errorf("FormatLogColumns() = %q, want %q", got, want)
+	}
+
+	for _, spec := range []string{
+		"",
+		"hash,commit",
+		"hash,hash",
+		"author:0",
+		"author:foo",
+		"hash:3",
+		"hash:41",
+	} {
+		if _, err := common.ParseLogColumns(spec); err == nil {
+			t.Errorf("ParseLogColumns(%q) => nil error, want error", spec)
+		}
+	}
+}
+
+func TestParseRepoFilter(t *testing.T) {
+	f, err := common.ParseRepoFilter("Name:team-a/*, visibility:Private")
+	if err != nil {
+		t.Fatalf("ParseRepoFilter() => %v, want nil error", err)
+	}
+	if got, want := f.String(), "name:team-a/*,visibility:private"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	f, err = common.ParseRepoFilter("archived:0")
+	if err != nil {
+		t.Fatalf("ParseRepoFilter() => %v, want nil error", err)
+	}
+	if got, want := f.String(), "archived:false"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	for _, spec := range []string{
+		"",
+		"team-a/*",
+		"name:",
+		"name:[a",
+		"visibility:hidden",
+		"archived:maybe",
+		"topic:go",
+	} {
+		if _, err := common.ParseRepoFilter(spec); err == nil {
+			t.Errorf("ParseRepoFilter(%q) => nil error, want error", spec)
+		}
+	}
+}
+
+func TestParseCommitType(t *testing.T) {
+	cases := []struct {
+		subject string
+		want    common.CommitType
+		ok      bool
+	}{
+		{"feat: add a thing", common.CommitType{Type: "feat", Description: "add a thing"}, true},
+		{"fix(ui): don't crash", common.CommitType{Type: "fix", Scope: "ui", Description: "don't crash"}, true},
+		{"Refactor(db)!: drop the table", common.CommitType{Type: "refactor", Scope: "db", Breaking: true, Description: "drop the table"}, true},
+		{"feat!: breaking", common.CommitType{Type: "feat", Breaking: true, Description: "breaking"}, true},
+		{"Merge branch 'main'", common.CommitType{}, false},
+		{"WIP: not a type", common.CommitType{}, false},
+		{"feat:no space", common.CommitType{}, false},
+		{"feat(ui: unbalanced", common.CommitType{}, false},
+		{"fix: ", common.CommitType{}, false},
+		{"", common.CommitType{}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.subject, func(t *testing.T) {
+			got, ok := common.ParseCommitType(c.subject)
+			if ok != c.ok || got != c.want {
+				t.Errorf("ParseCommitType(%q) = %+v, %v, want %+v, %v", c.subject, got, ok, c.want, c.ok)
+			}
+		})
+	}
+}
+
+func TestMarkdownHeadings(t *testing.T) {
+	md := strings.Join([]string{
+		"# Soft Serve",
+		"",
+		"A tasty, self-hostable Git server.",
+		"",
+		"## Installation ##",
+		"",
+		"```sh",
+		"# not a heading",
+		"```",
+		"",
+		"    # indented code",
+		"",
+		"### The [`soft`](https://example.com) *command*",
+		"",
+		"Setting up",
+		"==========",
+		"",
+		"A paragraph",
+		"---",
+		"",
+		"- a list item",
+		"---",
+		"",
+		"#hashtag",
+		"####### seven",
+		"#### snake_case_name",
+	}, "\n")
+	want := []common.Heading{
+		{1, "Soft Serve"},
+		{2, "Installation"},
+		{3, "The soft command"},
+		{1, "Setting up"},
+		{2, "A paragraph"},
+		{4, "snake_case_name"},
+	}
+	got := common.MarkdownHeadings(md)
+	if len(got) != len(want) {
+		t.Fatalf("MarkdownHeadings() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("MarkdownHeadings()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTruncatePath(t *testing.T) {
+	cases := []struct {
+		path  string
+		width int
+		want  string
+	}{
+		{"src/main.go", 20, "src/main.go"},
+		{"src/pkg/internal/deep/file.go", 20, "src/…/deep/file.go"},
+		{"src/pkg/internal/deep/file.go", 14, "src/…/file.go"},
+		{"src/pkg/internal/deep/file.go", 8, "…file.go"},
+		{"src/pkg/internal/deep/file.go", 0, ""},
+	}
+	for _, c := range cases {
+		if got := common.TruncatePath(c.path, c.width); got != c.want {
+			t.Errorf("TruncatePath(%q, %d) = %q, want %q", c.path, c.width, got, c.want)
+		}
+	}
+}
+
+func TestBookmarks(t *testing.T) {
+	a := common.Bookmark{Repo: "repo1", Ref: "refs/heads/main", Path: "cmd/main.go", Line: 12}
+	b := common.Bookmark{Repo: "repo2", Ref: "refs/tags/v1.0.0", Path: "my file.md"}
+
+	bms, added := common.ToggleBookmark(nil, a)
+	if !added {
+		t.Fatalf("ToggleBookmark() didn't add %v", a)
+	}
+	bms, _ = common.ToggleBookmark(bms, b)
+	spec := common.FormatBookmarks(bms)
+	got := common.ParseBookmarks(spec + "\nmalformed\nrepo\tref\tpath\tline")
+	if len(got) != 2 || got[0] != b || got[1] != a {
+		t.Fatalf("ParseBookmarks(%q) = %v, want [%v %v]", spec, got, b, a)
+	}
+	if s := a.String(); s != "repo1@main:cmd/main.go:12" {
+		t.Errorf("String() = %q", s)
+	}
+	if s := b.String(); s != "repo2@v1.0.0:my file.md" {
+		t.Errorf("String() = %q", s)
+	}
+
+	bms, added = common.ToggleBookmark(bms, b)
+	if added || len(bms) != 1 || bms[0] != a {
+		t.Errorf("ToggleBookmark() = %v, %v, want [%v], false", bms, added, a)
+	}
+}
+
+func TestPrepareMarkdown(t *testing.T) {
+	md := strings.Join([]string{
+		"Soft Serve[^serve] is tasty[^1], see `[^1]` and [^nope].",
+		"",
+		"| Key | Description |",
+		"|-----|:------------|",
+		"| `ssh.listen_addr` | The address \\| port the SSH server listens on |",
+		"| `name` | |",
+		"",
+		"```md",
+		"[^1]",
+		"| a | b |",
+		"|---|---|",
+		"```",
+		"",
+		"[^1]: A self-hostable",
+		"    Git server.",
+		"[^serve]: Served over SSH.",
+	}, "\n")
+
+	want := strings.Join([]string{
+		"Soft Serve¹ is tasty², see `[^1]` and [^nope].",
+		"",
+		"- **`ssh.listen_addr`**",
+		"  - **Description:** The address \\| port the SSH server listens on",
+		"- **`name`**",
+		"",
+		"```md",
+		"[^1]",
+		"| a | b |",
+		"|---|---|",
+		"```",
+		"",
+		"",
+		"---",
+		"",
+		"1. Served over SSH.",
+		"2. A self-hostable Git server.",
+	}, "\n")
+	if got := common.PrepareMarkdown(md, 40); got != want {
+		t.Errorf("PrepareMarkdown() = %q, want %q", got, want)
+	}
+
+	// Tables that fit are left alone.
+	got := common.PrepareMarkdown(md, 120)
+	if !strings.Contains(got, "| `name` | |") {
+		t.Errorf("PrepareMarkdown() = %q, want the table", got)
+	}
+}
+
+func TestFileIcon(t *testing.T) {
+	cases := []struct {
+		icons string
+		name  string
+		kind  common.FileKind
+		want  string
+	}{
+		{config.FileIconsNone, "main.go", common.FileKindFile, ""},
+		{config.FileIconsNerd, "main.go", common.FileKindFile, "\ue627  "},
+		{config.FileIconsNerd, "Makefile", common.FileKindFile, "\ue779  "},
+		{config.FileIconsNerd, "run.sh", common.FileKindExec, "\uf489  "},
+		{config.FileIconsNerd, "unknown", common.FileKindFile, "\uf15b  "},
+		{config.FileIconsNerd, "src.go", common.FileKindDir, "\uf07b  "},
+		{config.FileIconsUnicode, "README.md", common.FileKindFile, "📝 "},
+		{config.FileIconsUnicode, "docs", common.FileKindDir, "📁 "},
+		{config.FileIconsUnicode, "lib", common.FileKindSubmodule, "📦 "},
+		{config.FileIconsUnicode, "link", common.FileKindSymlink, "🔗 "},
+	}
+	for _, c := range cases {
+		t.Run(c.icons+"/"+c.name, func(t *testing.T) {
+			got := common.FileIcon(c.icons, c.name, c.kind)
+			if got != c.want {
+				t.Errorf("FileIcon(%q, %q) = %q, want %q", c.icons, c.name, got, c.want)
+			}
+			// Names line up whatever the width of the icon.
+			if got != "" && lipgloss.Width(got) != 3 {
+				t.Errorf("FileIcon(%q, %q) is %d cells wide, want 3", c.icons, c.name, lipgloss.Width(got))
+			}
+		})
+	}
+}
+
+type timesPage struct {
+	shows bool
+}
+
+func (p *timesPage) Init() tea.Cmd                       { return nil }
+func (p *timesPage) Update(tea.Msg) (tea.Model, tea.Cmd) { return p, nil }
+func (p *timesPage) View() string                        { return "" }
+func (p *timesPage) ShowsRelativeTimes() bool            { return p.shows }
+
+func TestRelativeTimesTicker(t *testing.T) {
+	var ticker common.RelativeTimesTicker
+	page := &timesPage{shows: true}
+
+	if ticker.Update(tea.KeyMsg{}, page) == nil {
+		t.Fatal("expected a tick on a page showing relative times")
+	}
+	if ticker.Update(tea.KeyMsg{}, page) != nil {
+		t.Error("expected no tick while one is pending")
+	}
+	if ticker.Update(common.RelativeTimesMsg{}, page) == nil {
+		t.Error("expected the next tick after a tick")
+	}
+
+	page.shows = false
+	if ticker.Update(common.RelativeTimesMsg{}, page) != nil {
+		t.Error("expected ticking to pause on a page without relative times")
+	}
+	page.shows = true
+	if ticker.Update(tea.KeyMsg{}, page) == nil {
+		t.Error("expected ticking to resume on a page showing relative times")
+	}
+}
@@ -1,10 +1,16 @@
 package common
 
 import (
+	"time"
+
 	"github.com/charmbracelet/bubbles/help"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// RelativeTimesInterval is how often the relative times of lists, e.g.
+// "3 minutes ago", are re-rendered so that they don't go stale.
+const RelativeTimesInterval = 30 * time.Second
+
 // Component represents a Bubble Tea model that implements a SetSize function.
 type Component interface {
 	tea.Model
@@ -32,3 +38,39 @@ type TabComponent interface {
 	// Path returns the hierarchical path of the tab.
 	Path() string
 }
+
+// RelativeTimes is implemented by the components that show relative times.
+// ShowsRelativeTimes returns whether they're on screen, they're only
+// re-rendered periodically then.
+type RelativeTimes interface {
+	ShowsRelativeTimes() bool
+}
+
+// RelativeTimesMsg is a message to re-render relative times. It carries
+// nothing, the view drawn after it is the re-render.
+type RelativeTimesMsg struct{}
+
+// RelativeTimesTicker re-renders the relative times of a page every
+// RelativeTimesInterval while it shows them. Ticking pauses when the page
+// doesn't, and resumes with the next message once it does again.
+type RelativeTimesTicker struct {
+	ticking bool
+}
+
+// Update returns the command of the next tick when the page shows relative
+// times and no tick is pending. It's called with every message.
+func (t *RelativeTimesTicker) Update(msg tea.Msg, page tea.Model) tea.Cmd {
+	if _, ok := msg.(RelativeTimesMsg); ok {
+		t.ticking = false
+	}
+	if t.ticking {
+		return nil
+	}
+	if p, ok := page.(RelativeTimes); !ok || !p.ShowsRelativeTimes() {
+		return nil
+	}
+	t.ticking = true
+	return tea.Tick(RelativeTimesInterval, func(time.Time) tea.Msg {
+		return RelativeTimesMsg{}
+	})
+}
@@ -0,0 +1,61 @@
+package common
+
+import (
+	"context"
+
+	"github.com/alecthomas/chroma/v2"
+	chromastyles "github.com/alecthomas/chroma/v2/styles"
+	gansi "github.com/charmbracelet/glamour/ansi"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/ui/styles"
+	"golang.org/x/crypto/ssh"
+)
+
+// DiffColorsPreference is the name of the preference that holds the color
+// scheme of diffs, one of styles.DiffColorSchemes.
+const DiffColorsPreference = "ui.diff-colors"
+
+// diffThemes are the chroma themes of the unified diffs in the color schemes
+// other than the classic one, which uses the colors of the code. Glamour
+// registers its chroma theme only once, so these are registered on their
+// own and looked up by name.
+var diffThemes = map[styles.DiffColors]*chroma.Style{
+	styles.DiffColorsColorblind: chromastyles.Register(chroma.MustNewStyle("soft-serve-diff-colorblind",
+		chroma.StyleEntries{
+			chroma.GenericInserted:   "#0087ff",
+			chroma.GenericDeleted:    "#ff8700",
+			chroma.GenericSubheading: "#777777",
+			chroma.GenericStrong:     "bold",
+		})),
+	styles.DiffColorsMonochrome: chromastyles.Register(chroma.MustNewStyle("soft-serve-diff-monochrome",
+		chroma.StyleEntries{
+			chroma.GenericInserted: "bold",
+			chroma.GenericDeleted:  "italic",
+			chroma.GenericStrong:   "bold",
+		})),
+}
+
+// DiffStyleRenderer returns a new Glamour renderer for diffs in the given
+// color scheme.
+func DiffStyleRenderer(c styles.DiffColors) gansi.RenderContext {
+	st := StyleConfig()
+	if theme, ok := diffThemes[c]; ok {
+		st.CodeBlock.Chroma = nil
+		st.CodeBlock.Theme = theme.Name
+	}
+	return StyleRendererWithStyles(st)
+}
+
+// LoadDiffColors returns the diff color scheme preferred by the public key,
+// the classic one when it has none.
+func LoadDiffColors(ctx context.Context, be *backend.Backend, pk ssh.PublicKey) styles.DiffColors {
+	if be == nil || pk == nil {
+		return styles.DiffColorsClassic
+	}
+	v, err := be.Preference(ctx, pk, DiffColorsPreference)
+	if err != nil {
+		return styles.DiffColorsClassic
+	}
+	c, _ := styles.ParseDiffColors(v)
+	return c
+}
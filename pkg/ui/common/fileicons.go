@@ -0,0 +1,175 @@
+package common
+
+import (
+	"path"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+)
+
+// FileIconsPreference is the name of the preference that holds the icons of
+// the file list, overriding ui.file_icons.
+const FileIconsPreference = "ui.file-icons"
+
+// fileIconWidth is the number of cells an icon takes in the file list. Icons
+// are padded to it so that names are aligned whatever the width of the
+// icons.
+const fileIconWidth = 2
+
+// FileKind is the kind of an entry of the file list.
+type FileKind int
+
+const (
+	// FileKindFile is a regular file.
+	FileKindFile FileKind = iota
+	// FileKindExec is an executable file.
+	FileKindExec
+	// FileKindDir is a directory.
+	FileKindDir
+	// FileKindSymlink is a symbolic link.
+	FileKindSymlink
+	// FileKindSubmodule is a submodule.
+	FileKindSubmodule
+)
+
+// fileIconSet is a set of icons by kind, by file name, and by extension.
+type fileIconSet struct {
+	kinds map[FileKind]string
+	names map[string]string
+	exts  map[string]string
+}
+
+// unicodeIcons are icons that terminals show without a special font.
+var unicodeIcons = fileIconSet{
+	kinds: map[FileKind]string{
+		FileKindFile:      "📄",
+		FileKindExec:      "⚡",
+		FileKindDir:       "📁",
+		FileKindSymlink:   "🔗",
+		FileKindSubmodule: "📦",
+	},
+	names: map[string]string{
+		"license":    "📜",
+		"license.md": "📜",
+		"copying":    "📜",
+	},
+	exts: map[string]string{
+		".md":   "📝",
+		".txt":  "📝",
+		".rst":  "📝",
+		".png":  "🎨",
+		".jpg":  "🎨",
+		".jpeg": "🎨",
+		".gif":  "🎨",
+		".svg":  "🎨",
+		".webp": "🎨",
+		".zip":  "📚",
+		".tar":  "📚",
+		".gz":   "📚",
+		".tgz":  "📚",
+		".xz":   "📚",
+		".pem":  "🔑",
+		".key":  "🔑",
+		".lock": "🔒",
+	},
+}
+
+// nerdIcons are the icons of Nerd Fonts, https://www.nerdfonts.com.
+var nerdIcons = fileIconSet{
+	kinds: map[FileKind]string{
+		FileKindFile:      "\uf15b", // nf-fa-file
+		FileKindExec:      "\uf489", // nf-oct-terminal
+		FileKindDir:       "\uf07b", // nf-fa-folder
+		FileKindSymlink:   "\uf481", // nf-oct-file_symlink_file
+		FileKindSubmodule: "\uf400", // nf-oct-file_submodule
+	},
+	names: map[string]string{
+		".gitignore":     "\ue702", // nf-dev-git
+		".gitattributes": "\ue702",
+		".gitmodules":    "\ue702",
+		"dockerfile":     "\uf308", // nf-linux-docker
+		"makefile":       "\ue779", // nf-dev-gnu
+		"license":        "\ue60a", // nf-seti-license
+		"license.md":     "\ue60a",
+		"copying":        "\ue60a",
+		"go.mod":         "\ue627", // nf-seti-go
+		"go.sum":         "\ue627",
+	},
+	exts: map[string]string{
+		".go":    "\ue627", // nf-seti-go
+		".rs":    "\ue7a8", // nf-dev-rust
+		".py":    "\ue606", // nf-seti-python
+		".js":    "\ue74e", // nf-dev-javascript
+		".mjs":   "\ue74e",
+		".ts":    "\ue628", // nf-seti-typescript
+		".tsx":   "\ue7ba", // nf-dev-react
+		".jsx":   "\ue7ba",
+		".json":  "\ue60b", // nf-seti-json
+		".md":    "\ue609", // nf-seti-markdown
+		".yml":   "\ue6a8", // nf-seti-yml
+		".yaml":  "\ue6a8",
+		".toml":  "\ue615", // nf-seti-config
+		".ini":   "\ue615",
+		".html":  "\ue736", // nf-dev-html5
+		".css":   "\ue749", // nf-dev-css3
+		".c":     "\ue61e", // nf-custom-c
+		".h":     "\ue61e",
+		".cpp":   "\ue61d", // nf-custom-cpp
+		".hpp":   "\ue61d",
+		".java":  "\ue738", // nf-dev-java
+		".rb":    "\ue739", // nf-dev-ruby
+		".php":   "\ue73d", // nf-dev-php
+		".lua":   "\ue620", // nf-seti-lua
+		".sh":    "\uf489", // nf-oct-terminal
+		".bash":  "\uf489",
+		".zsh":   "\uf489",
+		".txt":   "\uf15c", // nf-fa-file_text
+		".pdf":   "\uf1c1", // nf-fa-file_pdf_o
+		".png":   "\uf1c5", // nf-fa-file_image_o
+		".jpg":   "\uf1c5",
+		".jpeg":  "\uf1c5",
+		".gif":   "\uf1c5",
+		".svg":   "\uf1c5",
+		".webp":  "\uf1c5",
+		".zip":   "\uf410", // nf-oct-file_zip
+		".tar":   "\uf410",
+		".gz":    "\uf410",
+		".tgz":   "\uf410",
+		".xz":    "\uf410",
+		".lock":  "\uf023", // nf-fa-lock
+		".pem":   "\uf084", // nf-fa-key
+		".key":   "\uf084",
+		".sql":   "\uf1c0", // nf-fa-database
+		".proto": "\uf1c9", // nf-fa-file_code_o
+	},
+}
+
+// FileIcon returns the icon of a file of the file list followed by a space,
+// or an empty string when icons are config.FileIconsNone. Directories, symbolic links, and
+// submodules have the icon of their kind, files the icon of their name or
+// extension. Icons are padded so that they all take the same width.
+func FileIcon(icons string, name string, kind FileKind) string {
+	var set fileIconSet
+	switch icons {
+	case config.FileIconsUnicode:
+		set = unicodeIcons
+	case config.FileIconsNerd:
+		set = nerdIcons
+	default:
+		return ""
+	}
+	icon := set.kinds[kind]
+	if kind == FileKindFile || kind == FileKindExec {
+		lower := strings.ToLower(path.Base(name))
+		if i, ok := set.names[lower]; ok {
+			icon = i
+		} else if i, ok := set.exts[path.Ext(lower)]; ok {
+			icon = i
+		}
+	}
+	if w := lipgloss.Width(icon); w < fileIconWidth {
+		icon += strings.Repeat(" ", fileIconWidth-w)
+	}
+	return icon + " "
+}
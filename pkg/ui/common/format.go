@@ -1,9 +1,11 @@
 package common
 
 import (
+	"bytes"
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/alecthomas/chroma/v2/lexers"
 	gansi "github.com/charmbracelet/glamour/ansi"
@@ -69,3 +71,65 @@ func UnquoteFilename(s string) string {
 	name = strconv.Quote(name)
 	return strings.Trim(name, `"`)
 }
+
+// detectPrefixSize is the number of bytes inspected to detect the encoding and
+// line endings of a file.
+const detectPrefixSize = 8 * 1024
+
+func detectPrefix(b []byte) []byte {
+	if len(b) > detectPrefixSize {
+		b = b[:detectPrefixSize]
+	}
+	return b
+}
+
+// DetectEncoding returns a best guess of the text encoding of the given
+// content. Only a prefix of the content is inspected.
+func DetectEncoding(b []byte) string {
+	if len(b) > detectPrefixSize {
+		b = b[:detectPrefixSize]
+		// Drop the last rune if it was cut in half by the prefix.
+		for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+			if utf8.RuneStart(b[i]) {
+				if !utf8.FullRune(b[i:]) {
+					b = b[:i]
+				}
+				break
+			}
+		}
+	}
+
+	switch {
+	case bytes.HasPrefix(b, []byte{0xef, 0xbb, 0xbf}):
+		return "UTF-8 BOM"
+	case bytes.HasPrefix(b, []byte{0xff, 0xfe}):
+		return "UTF-16LE"
+	case bytes.HasPrefix(b, []byte{0xfe, 0xff}):
+		return "UTF-16BE"
+	}
+
+	if utf8.Valid(b) {
+		return "UTF-8"
+	}
+
+	return "latin1"
+}
+
+// DetectLineEnding returns the line ending style of the given content. It
+// returns "LF", "CRLF", "mixed", or an empty string if the content has no line
+// endings. Only a prefix of the content is inspected.
+func DetectLineEnding(b []byte) string {
+	b = detectPrefix(b)
+	crlf := bytes.Count(b, []byte("\r\n"))
+	lf := bytes.Count(b, []byte("\n")) - crlf
+	switch {
+	case crlf > 0 && lf > 0:
+		return "mixed"
+	case crlf > 0:
+		return "CRLF"
+	case lf > 0:
+		return "LF"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,98 @@
+package common
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Heading is a heading of a markdown document.
+type Heading struct {
+	// Level is the level of the heading, from 1 to 6.
+	Level int
+	// Title is the text of the heading without inline markup.
+	Title string
+}
+
+var (
+	mdImageRe    = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLinkRe     = regexp.MustCompile(`\[([^\]]*)\](?:\([^)]*\)|\[[^\]]*\])`)
+	mdHTMLRe     = regexp.MustCompile(`<[^>]+>`)
+	mdEmphasisRe = regexp.MustCompile(`(^|\W)[*_~]+|[*_~]+(\W|$)`)
+	mdClosingRe  = regexp.MustCompile(`(^|\s+)#+$`)
+	mdListRe     = regexp.MustCompile(`^([-*+]|\d+[.)])\s`)
+)
+
+// MarkdownHeadings returns the headings of a markdown document in order, both
+// the "#" and the underlined ones. Headings in code blocks are left out.
+func MarkdownHeadings(md string) []Heading {
+	var headings []Heading
+	var fence, prev string
+	for _, l := range strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n") {
+		t := strings.TrimSpace(l)
+		indented := strings.HasPrefix(strings.ReplaceAll(l, "\t", "    "), "    ")
+
+		if fence != "" {
+			if strings.HasPrefix(t, fence) && strings.Trim(t, fence[:1]) == "" {
+				fence = ""
+			}
+			continue
+		}
+
+		switch {
+		case indented && prev == "":
+			// Indented code block.
+			continue
+		case strings.HasPrefix(t, "```"), strings.HasPrefix(t, "~~~"):
+			fence = t[:len(t)-len(strings.TrimLeft(t, t[:1]))]
+			prev = ""
+			continue
+		case strings.HasPrefix(t, "#") && !indented:
+			level := len(t) - len(strings.TrimLeft(t, "#"))
+			rest := t[level:]
+			if level <= 6 && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+				rest = mdClosingRe.ReplaceAllString(strings.TrimSpace(rest), "")
+				if title := plainTitle(rest); title != "" {
+					headings = append(headings, Heading{Level: level, Title: title})
+				}
+				prev = ""
+				continue
+			}
+		case prev != "" && !indented && isUnderline(t):
+			level := 1
+			if t[0] == '-' {
+				level = 2
+			}
+			if title := plainTitle(prev); title != "" {
+				headings = append(headings, Heading{Level: level, Title: title})
+			}
+			prev = ""
+			continue
+		}
+
+		// Only the lines of a paragraph can be underlined as a heading.
+		if t == "" || mdListRe.MatchString(t) || strings.HasPrefix(t, ">") ||
+			strings.HasPrefix(t, "|") || strings.HasPrefix(t, "<") {
+			prev = ""
+		} else {
+			prev = t
+		}
+	}
+	return headings
+}
+
+// isUnderline returns true if the line underlines the line above it as a
+// heading.
+func isUnderline(t string) bool {
+	return t != "" && (strings.Trim(t, "=") == "" || strings.Trim(t, "-") == "")
+}
+
+// plainTitle returns the text of a heading without its inline markup, the
+// way it's shown once rendered.
+func plainTitle(s string) string {
+	s = mdImageRe.ReplaceAllString(s, "$1")
+	s = mdLinkRe.ReplaceAllString(s, "$1")
+	s = mdHTMLRe.ReplaceAllString(s, "")
+	s = strings.ReplaceAll(s, "`", "")
+	s = mdEmphasisRe.ReplaceAllString(s, "$1$2")
+	return strings.Join(strings.Fields(s), " ")
+}
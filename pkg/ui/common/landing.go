@@ -0,0 +1,23 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/config"
+)
+
+// LandingTabs are the names of the repository tabs a repository can be opened
+// on, in the order they're shown.
+var LandingTabs = config.RepoTabs
+
+// ParseLandingTab returns the name of the landing tab. It's case insensitive.
+func ParseLandingTab(tab string) (string, error) {
+	tab = strings.ToLower(strings.TrimSpace(tab))
+	for _, t := range LandingTabs {
+		if t == tab {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unknown tab %q, must be one of %s", tab, strings.Join(LandingTabs, ", "))
+}
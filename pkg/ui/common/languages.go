@@ -0,0 +1,184 @@
+package common
+
+import (
+	"hash/fnv"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/lfs"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// Language is the share of a language in a repository.
+type Language struct {
+	Name    string
+	Bytes   int64
+	Percent float64
+}
+
+var (
+	// languagesCache caches the languages of a repository keyed by
+	// repository path and commit.
+	languagesCache, _ = lru.New[string, []Language](100)
+
+	// lexerNames caches the lexer names keyed by file extension, or file name
+	// for files without an extension. Matching lexers by file name is slow.
+	lexerNames    = map[string]string{}
+	lexerNamesMtx sync.Mutex
+)
+
+// Languages returns the languages of the given ref by size, largest first. It
+// walks the whole tree once and honors the linguist-language,
+// linguist-vendored, linguist-generated, and linguist-documentation
+// attributes. LFS pointers count as the size of the files they point to. The
+// result is cached per commit.
+func Languages(r *git.Repository, ref *git.Reference) ([]Language, error) {
+	key := r.Path + "@" + ref.ID
+	if langs, ok := languagesCache.Get(key); ok {
+		return langs, nil
+	}
+
+	blobs, err := r.TreeBlobs(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, len(blobs))
+	for i, b := range blobs {
+		paths[i] = b.Path
+	}
+	attrs, err := r.CheckPathsAttributes(ref, paths)
+	if err != nil {
+		return nil, err
+	}
+	pointers, err := lfs.TreePointers(r, blobs)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := map[string]int64{}
+	var total int64
+	for _, b := range blobs {
+		name, skip := "", false
+		for _, a := range attrs[b.Path] {
+			switch a.Name {
+			case "linguist-vendored", "linguist-generated", "linguist-documentation":
+				skip = skip || a.IsSet()
+			case "linguist-language":
+				name = a.Value
+				if l := lexers.Get(name); l != nil {
+					name = l.Config().Name
+				}
+			}
+		}
+		if skip {
+			continue
+		}
+		if name == "" {
+			name = lexerName(b.Path)
+		}
+		size := b.Size
+		if p, ok := pointers[b.Path]; ok {
+			size = p.Size
+		}
+		if name == "" || size == 0 {
+			continue
+		}
+		sizes[name] += size
+		total += size
+	}
+
+	langs := make([]Language, 0, len(sizes))
+	for name, size := range sizes {
+		langs = append(langs, Language{
+			Name:    name,
+			Bytes:   size,
+			Percent: float64(size) / float64(total) * 100,
+		})
+	}
+	sort.Slice(langs, func(i, j int) bool {
+		if langs[i].Bytes == langs[j].Bytes {
+			return langs[i].Name < langs[j].Name
+		}
+		return langs[i].Bytes > langs[j].Bytes
+	})
+
+	languagesCache.Add(key, langs)
+	return langs, nil
+}
+
+// lexerName returns the name of the language of the given file. It returns an
+// empty string for unknown and plain text files.
+func lexerName(path string) string {
+	name := filepath.Base(path)
+	key := strings.ToLower(filepath.Ext(name))
+	if key != "" {
+		name = "file" + key
+	} else {
+		key = name
+	}
+
+	lexerNamesMtx.Lock()
+	defer lexerNamesMtx.Unlock()
+	if lang, ok := lexerNames[key]; ok {
+		return lang
+	}
+
+	var lang string
+	if l := lexers.Match(name); l != nil && l.Config() != nil {
+		lang = l.Config().Name
+	}
+	if lang == "plaintext" {
+		lang = ""
+	}
+	lexerNames[key] = lang
+	return lang
+}
+
+// languageColors are the colors of the most common languages.
+var languageColors = map[string]string{
+	"Bash":       "#89e051",
+	"C":          "#555555",
+	"C#":         "#178600",
+	"C++":        "#f34b7d",
+	"CSS":        "#563d7c",
+	"Dart":       "#00b4ab",
+	"Docker":     "#384d54",
+	"Elixir":     "#6e4a7e",
+	"Go":         "#00add8",
+	"HTML":       "#e34c26",
+	"Haskell":    "#5e5086",
+	"Java":       "#b07219",
+	"JavaScript": "#f1e05a",
+	"Kotlin":     "#a97bff",
+	"Lua":        "#000080",
+	"Makefile":   "#427819",
+	"Nix":        "#7e7eff",
+	"PHP":        "#4f5d95",
+	"Python":     "#3572a5",
+	"Ruby":       "#701516",
+	"Rust":       "#dea584",
+	"SCSS":       "#c6538c",
+	"Swift":      "#f05138",
+	"TypeScript": "#3178c6",
+	"Zig":        "#ec915c",
+	"markdown":   "#083fa1",
+}
+
+// LanguageColor returns the color of the given language. Languages without a
+// well-known color get a stable color from the 256 colors palette.
+func LanguageColor(name string) lipgloss.Color {
+	if c, ok := languageColors[name]; ok {
+		return lipgloss.Color(c)
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name)) // nolint: errcheck
+	// Skip the first 16 colors which depend on the terminal theme.
+	return lipgloss.Color(strconv.Itoa(16 + int(h.Sum32()%216)))
+}
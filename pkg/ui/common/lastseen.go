@@ -0,0 +1,14 @@
+package common
+
+import "github.com/charmbracelet/soft-serve/pkg/utils"
+
+// LastSeenPreference is the prefix of the names of the preferences that hold
+// the hash of the HEAD commit of a repository the last time the user opened
+// it, followed by the repository name.
+const LastSeenPreference = "repo.seen."
+
+// LastSeenPreferenceName returns the name of the preference that holds the
+// last seen HEAD commit of the repository.
+func LastSeenPreferenceName(repo string) string {
+	return LastSeenPreference + utils.SanitizeRepo(repo)
+}
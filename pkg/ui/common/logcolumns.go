@@ -0,0 +1,107 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LogColumnsPreference is the name of the preference that holds the columns
+// of the log.
+const LogColumnsPreference = "log.columns"
+
+// LogColumnKind is the kind of a log column.
+type LogColumnKind string
+
+const (
+	// LogColumnHash is the short commit hash.
+	LogColumnHash LogColumnKind = "hash"
+	// LogColumnAuthor is the author name.
+	LogColumnAuthor LogColumnKind = "author"
+	// LogColumnDate is the commit date, e.g. "Jan 02 2006".
+	LogColumnDate LogColumnKind = "date"
+	// LogColumnAge is the relative commit date, e.g. "3 days ago".
+	LogColumnAge LogColumnKind = "age"
+	// LogColumnSubject is the first line of the commit message.
+	LogColumnSubject LogColumnKind = "subject"
+)
+
+// defaultLogColumnWidths are the widths of the columns without an explicit
+// width. The subject takes the rest of the line.
+var defaultLogColumnWidths = map[LogColumnKind]int{
+	LogColumnHash:    7,
+	LogColumnAuthor:  16,
+	LogColumnDate:    11,
+	LogColumnAge:     14,
+	LogColumnSubject: 0,
+}
+
+// LogColumn is a column of the log.
+type LogColumn struct {
+	Kind LogColumnKind
+	// Width is the width of the column in cells. For the hash, it's the
+	// number of hexadecimal digits. A subject without a width takes the rest
+	// of the line.
+	Width int
+}
+
+// String returns the column in the form "kind:width".
+func (c LogColumn) String() string {
+	if c.Width == 0 {
+		return string(c.Kind)
+	}
+	return fmt.Sprintf("%s:%d", c.Kind, c.Width)
+}
+
+// ParseLogColumns parses a comma-separated list of log columns in the order
+// they're shown, e.g. "hash,author:12,age,subject". Every column can have a
+// width after a colon.
+func ParseLogColumns(spec string) ([]LogColumn, error) {
+	cols := make([]LogColumn, 0)
+	seen := map[LogColumnKind]bool{}
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		name, width, hasWidth := strings.Cut(field, ":")
+		kind := LogColumnKind(strings.ToLower(name))
+		w, ok := defaultLogColumnWidths[kind]
+		if !ok {
+			return nil, fmt.Errorf("unknown log column %q", name)
+		}
+		if seen[kind] {
+			return nil, fmt.Errorf("duplicate log column %q", name)
+		}
+		seen[kind] = true
+
+		if hasWidth {
+			n, err := strconv.Atoi(width)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid width %q of log column %q", width, name)
+			}
+			if kind == LogColumnHash && (n < 4 || n > 40) {
+				return nil, fmt.Errorf("invalid width %d of log column %q: must be between 4 and 40", n, name)
+			}
+			w = n
+		}
+
+		cols = append(cols, LogColumn{Kind: kind, Width: w})
+	}
+
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("no log columns")
+	}
+
+	return cols, nil
+}
+
+// FormatLogColumns returns the comma-separated list of the log columns.
+func FormatLogColumns(cols []LogColumn) string {
+	fields := make([]string, len(cols))
+	for i, c := range cols {
+		fields[i] = c.String()
+	}
+	return strings.Join(fields, ",")
+}
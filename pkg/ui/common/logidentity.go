@@ -0,0 +1,6 @@
+package common
+
+// LogCommitterPreference is the name of the preference that shows the
+// committers of the commits in the log instead of their authors. The log
+// shows the authors unless it's "true".
+const LogCommitterPreference = "log.committer"
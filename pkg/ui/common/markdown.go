@@ -0,0 +1,240 @@
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+var (
+	mdFootnoteDefRe = regexp.MustCompile(`^\[\^([^\]\s]+)\]:\s*(.*)$`)
+	mdFootnoteRefRe = regexp.MustCompile(`\[\^([^\]\s]+)\]`)
+	mdDelimiterRe   = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+)
+
+// PrepareMarkdown rewrites the GitHub-flavored markdown Glamour doesn't
+// render well before rendering it at width. Footnote references become
+// superscript numbers and their definitions a numbered list at the end of the
+// document, and tables too wide for width become lists, one item per row, so
+// that their cells wrap instead of being cut. Code blocks are left alone.
+func PrepareMarkdown(md string, width int) string {
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+
+	// Footnotes are numbered in the order they're referenced.
+	defs := map[string]string{}
+	var ids []string
+	body := make([]string, 0, len(lines))
+	var fence, prev, def string
+	for _, l := range lines {
+		t := strings.TrimSpace(l)
+		indented := strings.HasPrefix(strings.ReplaceAll(l, "\t", "    "), "    ")
+
+		switch {
+		case fence != "":
+			if strings.HasPrefix(t, fence) && strings.Trim(t, fence[:1]) == "" {
+				fence = ""
+			}
+		case def != "" && indented && t != "":
+			// Continuation of a footnote definition.
+			defs[def] += " " + t
+			continue
+		case indented && prev == "":
+			// Indented code block.
+			body = append(body, l)
+			continue
+		case strings.HasPrefix(t, "```"), strings.HasPrefix(t, "~~~"):
+			fence = t[:len(t)-len(strings.TrimLeft(t, t[:1]))]
+		case !indented && mdFootnoteDefRe.MatchString(t):
+			m := mdFootnoteDefRe.FindStringSubmatch(t)
+			def = m[1]
+			if _, ok := defs[def]; !ok {
+				ids = append(ids, def)
+			}
+			defs[def] = m[2]
+			continue
+		}
+		if t != "" {
+			def = ""
+		}
+		prev = t
+		body = append(body, l)
+	}
+
+	var order []string
+	numbers := map[string]int{}
+	if len(defs) > 0 {
+		fence = ""
+		for i, l := range body {
+			t := strings.TrimSpace(l)
+			if fence != "" || strings.HasPrefix(t, "```") || strings.HasPrefix(t, "~~~") {
+				if fence == "" {
+					fence = t[:len(t)-len(strings.TrimLeft(t, t[:1]))]
+				} else if strings.HasPrefix(t, fence) && strings.Trim(t, fence[:1]) == "" {
+					fence = ""
+				}
+				continue
+			}
+			body[i] = replaceOutsideCode(l, func(s string) string {
+				return mdFootnoteRefRe.ReplaceAllStringFunc(s, func(ref string) string {
+					id := mdFootnoteRefRe.FindStringSubmatch(ref)[1]
+					if _, ok := defs[id]; !ok {
+						return ref
+					}
+					if _, ok := numbers[id]; !ok {
+						order = append(order, id)
+						numbers[id] = len(order)
+					}
+					return superscript(numbers[id])
+				})
+			})
+		}
+		for _, id := range ids {
+			if _, ok := numbers[id]; !ok {
+				order = append(order, id)
+				numbers[id] = len(order)
+			}
+		}
+	}
+
+	body = wrapTables(body, width)
+	if len(order) > 0 {
+		body = append(body, "", "---", "")
+		for _, id := range order {
+			body = append(body, fmt.Sprintf("%d. %s", numbers[id], defs[id]))
+		}
+	}
+	return strings.Join(body, "\n")
+}
+
+// wrapTables turns the tables of the lines that don't fit in width into
+// lists. The first cell of a row is the item, the other ones are nested items
+// labeled with their column.
+func wrapTables(lines []string, width int) []string {
+	if width <= 0 {
+		return lines
+	}
+	var margin int
+	if m := StyleConfig().Document.Margin; m != nil {
+		margin = 2 * int(*m)
+	}
+
+	out := make([]string, 0, len(lines))
+	var fence string
+	for i := 0; i < len(lines); i++ {
+		l := lines[i]
+		t := strings.TrimSpace(l)
+		if fence != "" || strings.HasPrefix(t, "```") || strings.HasPrefix(t, "~~~") {
+			if fence == "" {
+				fence = t[:len(t)-len(strings.TrimLeft(t, t[:1]))]
+			} else if strings.HasPrefix(t, fence) && strings.Trim(t, fence[:1]) == "" {
+				fence = ""
+			}
+			out = append(out, l)
+			continue
+		}
+
+		if !strings.Contains(t, "|") || i+1 >= len(lines) ||
+			!mdDelimiterRe.MatchString(strings.TrimSpace(lines[i+1])) {
+			out = append(out, l)
+			continue
+		}
+		header := tableCells(t)
+		if len(header) != len(tableCells(strings.TrimSpace(lines[i+1]))) {
+			out = append(out, l)
+			continue
+		}
+
+		end := i + 2
+		var rows [][]string
+		for ; end < len(lines); end++ {
+			r := strings.TrimSpace(lines[end])
+			if r == "" || !strings.Contains(r, "|") {
+				break
+			}
+			rows = append(rows, tableCells(r))
+		}
+
+		// Every column is padded and separated from the next one.
+		need := 0
+		for c := range header {
+			w := ansi.StringWidth(header[c])
+			for _, r := range rows {
+				if c < len(r) {
+					w = max(w, ansi.StringWidth(r[c]))
+				}
+			}
+			need += w + 3
+		}
+		if need <= width-margin {
+			out = append(out, lines[i:end]...)
+			i = end - 1
+			continue
+		}
+
+		for _, r := range rows {
+			item := "-"
+			if len(r) > 0 && r[0] != "" {
+				item += " **" + r[0] + "**"
+			}
+			out = append(out, item)
+			for c := 1; c < len(header) && c < len(r); c++ {
+				if r[c] == "" {
+					continue
+				}
+				cell := "  - "
+				if header[c] != "" {
+					cell += "**" + header[c] + ":** "
+				}
+				out = append(out, cell+r[c])
+			}
+		}
+		i = end - 1
+	}
+	return out
+}
+
+// tableCells returns the trimmed cells of a table row. Escaped pipes don't
+// separate cells.
+func tableCells(row string) []string {
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, `\|`) {
+		row = row[:len(row)-1]
+	}
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '\\' && i+1 < len(row) && row[i+1] == '|':
+			cell.WriteString(`\|`)
+			i++
+		case row[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(row[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// replaceOutsideCode replaces the parts of the line that aren't code spans
+// with fn.
+func replaceOutsideCode(l string, fn func(string) string) string {
+	parts := strings.Split(l, "`")
+	for i := 0; i < len(parts); i += 2 {
+		parts[i] = fn(parts[i])
+	}
+	return strings.Join(parts, "`")
+}
+
+// superscript returns n in superscript digits.
+func superscript(n int) string {
+	digits := []rune("⁰¹²³⁴⁵⁶⁷⁸⁹")
+	var s strings.Builder
+	for _, d := range fmt.Sprint(n) {
+		s.WriteRune(digits[d-'0'])
+	}
+	return s.String()
+}
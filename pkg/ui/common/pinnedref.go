@@ -0,0 +1,29 @@
+package common
+
+import "github.com/charmbracelet/soft-serve/pkg/utils"
+
+// PinnedRefPreference is the prefix of the names of the preferences that hold
+// the full name of the ref a repository opens on, followed by the repository
+// name. Repositories open on their HEAD when it isn't set.
+const PinnedRefPreference = "repo.ref."
+
+// PinnedRefPreferenceName returns the name of the preference that holds the
+// pinned ref of the repository.
+func PinnedRefPreferenceName(repo string) string {
+	return PinnedRefPreference + utils.SanitizeRepo(repo)
+}
+
+// PinnedRef returns the full name of the ref the user pinned the repository
+// to, or an empty string.
+func (c *Common) PinnedRef(repo string) string {
+	be := c.Backend()
+	if be == nil {
+		return ""
+	}
+	v, err := be.Preference(c.Context(), c.PublicKey(), PinnedRefPreferenceName(repo))
+	if err != nil {
+		c.Logger.Debugf("ui: failed to load pinned ref preference: %v", err)
+		return ""
+	}
+	return v
+}
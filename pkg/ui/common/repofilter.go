@@ -0,0 +1,122 @@
+package common
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+)
+
+// RepoFilterPreference is the name of the preference that holds the default
+// filter of the repository list.
+const RepoFilterPreference = "repo.filter"
+
+// Repository visibilities a RepoFilter matches.
+const (
+	RepoFilterPublic  = "public"
+	RepoFilterPrivate = "private"
+)
+
+// RepoFilter is a filter of the repository list. Its zero value matches every
+// repository.
+type RepoFilter struct {
+	// Name is a glob repository names must match, e.g. "team-a/*".
+	Name string
+	// Visibility is the visibility repositories must have, "public" or
+	// "private". Any visibility matches when it's empty.
+	Visibility string
+	// Archived is "true" to only match the archived repositories, and
+	// "false" to leave them out. Any repository matches when it's empty.
+	Archived string
+}
+
+// IsZero returns true if the filter matches every repository.
+func (f RepoFilter) IsZero() bool {
+	return f == RepoFilter{}
+}
+
+// Match returns true if the repository passes the filter.
+func (f RepoFilter) Match(r proto.Repository) bool {
+	if f.Name != "" {
+		if ok, _ := path.Match(f.Name, r.Name()); !ok {
+			return false
+		}
+	}
+	switch f.Visibility {
+	case RepoFilterPublic:
+		if r.IsPrivate() {
+			return false
+		}
+	case RepoFilterPrivate:
+		if !r.IsPrivate() {
+			return false
+		}
+	}
+	if f.Archived != "" && r.IsArchived() != (f.Archived == "true") {
+		return false
+	}
+	return true
+}
+
+// String returns the filter in the form
+// "name:glob,visibility:private,archived:false".
+func (f RepoFilter) String() string {
+	fields := make([]string, 0, 3)
+	if f.Name != "" {
+		fields = append(fields, "name:"+f.Name)
+	}
+	if f.Visibility != "" {
+		fields = append(fields, "visibility:"+f.Visibility)
+	}
+	if f.Archived != "" {
+		fields = append(fields, "archived:"+f.Archived)
+	}
+	return strings.Join(fields, ",")
+}
+
+// ParseRepoFilter parses a comma-separated list of filter fields, e.g.
+// "name:team-a/*,visibility:private".
+func ParseRepoFilter(spec string) (RepoFilter, error) {
+	var f RepoFilter
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(field, ":")
+		if !ok || value == "" {
+			return RepoFilter{}, fmt.Errorf("invalid repository filter %q: must be in the form field:value", field)
+		}
+
+		switch strings.ToLower(name) {
+		case "name":
+			if _, err := path.Match(value, ""); err != nil {
+				return RepoFilter{}, fmt.Errorf("invalid name pattern %q: %w", value, err)
+			}
+			f.Name = value
+		case "visibility":
+			value = strings.ToLower(value)
+			if value != RepoFilterPublic && value != RepoFilterPrivate {
+				return RepoFilter{}, fmt.Errorf("invalid visibility %q: must be %s or %s", value, RepoFilterPublic, RepoFilterPrivate)
+			}
+			f.Visibility = value
+		case "archived":
+			archived, err := strconv.ParseBool(value)
+			if err != nil {
+				return RepoFilter{}, fmt.Errorf("invalid archived %q: must be true or false", value)
+			}
+			f.Archived = strconv.FormatBool(archived)
+		default:
+			return RepoFilter{}, fmt.Errorf("unknown repository filter field %q: must be name, visibility, or archived", name)
+		}
+	}
+
+	if f.IsZero() {
+		return RepoFilter{}, fmt.Errorf("no repository filter")
+	}
+
+	return f, nil
+}
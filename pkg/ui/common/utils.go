@@ -3,7 +3,10 @@ package common
 import (
 	"fmt"
 	"net/url"
+	"strings"
 
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/pkg/config"
 	"github.com/charmbracelet/soft-serve/pkg/utils"
 	"github.com/muesli/reflow/truncate"
 )
@@ -16,6 +19,39 @@ func TruncateString(s string, max int) string {
 	return truncate.StringWithTail(s, uint(max), "…")
 }
 
+// TruncatePath truncates a path to the given width in the middle, keeping
+// its first element and as many of its last ones as fit, i.e.
+// "src/…/deep/file.go". Paths that still don't fit keep their end.
+func TruncatePath(p string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if lipgloss.Width(p) <= width {
+		return p
+	}
+	parts := strings.Split(p, "/")
+	for i := 2; i < len(parts); i++ {
+		s := parts[0] + "/…/" + strings.Join(parts[i:], "/")
+		if lipgloss.Width(s) <= width {
+			return s
+		}
+	}
+	r := []rune(p)
+	for len(r) > 0 && lipgloss.Width(string(r))+1 > width {
+		r = r[1:]
+	}
+	return "…" + string(r)
+}
+
+// CloneURL returns the SSH clone URL of the repository. It's rendered from
+// the clone URL template of the server when there's one.
+func CloneURL(cfg *config.Config, name string) string {
+	if cfg.SSH.CloneURL != "" {
+		return cfg.SSHCloneURL(name)
+	}
+	return RepoURL(cfg.SSH.PublicURL, name)
+}
+
 // RepoURL returns the URL of the repository.
 func RepoURL(publicURL, name string) string {
 	name = utils.SanitizeRepo(name) + ".git"
@@ -33,3 +69,42 @@ func RepoURL(publicURL, name string) string {
 
 	return fmt.Sprintf("%s/%s", publicURL, name)
 }
+
+// FileURL returns a permalink to a file of the repository at the given
+// revision. It points to the HTTP server when it's enabled, otherwise it's an
+// SSH command that prints the file.
+func FileURL(cfg *config.Config, name, rev, path string) string {
+	return FileLinesURL(cfg, name, rev, path, 0, 0)
+}
+
+// FileLinesURL is like FileURL but it points to the lines from start to end,
+// one based. A zero start points to the whole file.
+func FileLinesURL(cfg *config.Config, name, rev, path string, start, end int) string {
+	name = utils.SanitizeRepo(name)
+	path = strings.TrimPrefix(path, "/")
+	end = max(start, end)
+	if cfg.HTTP.ListenAddr != "" && cfg.HTTP.PublicURL != "" {
+		link := fmt.Sprintf("%s/%s/blob/%s/%s", cfg.HTTP.PublicURL, name, rev, path)
+		switch {
+		case start <= 0:
+		case start == end:
+			link += fmt.Sprintf("#L%d", start)
+		default:
+			link += fmt.Sprintf("#L%d-L%d", start, end)
+		}
+		return link
+	}
+
+	sshCmd := "ssh"
+	if url, err := url.Parse(cfg.SSH.PublicURL); err == nil {
+		if port := url.Port(); port != "" && port != "22" {
+			sshCmd += " -p " + port
+		}
+		sshCmd += " " + url.Hostname()
+	}
+	link := fmt.Sprintf("%s repo blob %s %s %s", sshCmd, name, rev, path)
+	if start > 0 {
+		link += fmt.Sprintf(" --offset %d --limit %d", start-1, end-start+1)
+	}
+	return link
+}
@@ -0,0 +1,48 @@
+package common
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+)
+
+const (
+	// WatchedReposPreference is the name of the preference that holds the
+	// repositories whose pushes are notified in the terminal UI.
+	WatchedReposPreference = backend.WatchedReposPreference
+
+	// BellPreference is the name of the preference that rings the terminal
+	// bell on notifications. The bell rings unless it's "false".
+	BellPreference = "ui.bell"
+
+	// PushPanelPreference is the name of the preference that shows the report
+	// of the pushes of the user in the terminal UI. The report is shown
+	// unless it's "false".
+	PushPanelPreference = "ui.push-panel"
+)
+
+// ParseWatchedRepos returns the set of repositories of a comma-separated
+// list.
+func ParseWatchedRepos(spec string) map[string]bool {
+	repos := make(map[string]bool)
+	for _, name := range strings.Split(spec, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			repos[name] = true
+		}
+	}
+	return repos
+}
+
+// FormatWatchedRepos returns the sorted comma-separated list of the
+// repositories.
+func FormatWatchedRepos(repos map[string]bool) string {
+	names := make([]string, 0, len(repos))
+	for name, ok := range repos {
+		if ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,115 @@
+package code
+
+import (
+	"crypto/sha1" // nolint: gosec
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+)
+
+const (
+	// defaultCacheSize is the size, in bytes, of the render cache when there's
+	// no config.
+	defaultCacheSize = 64 << 20
+
+	// maxCacheEntries bounds the number of renders kept, the cache is bounded
+	// by the size of the renders before that.
+	maxCacheEntries = 1 << 16
+)
+
+// cache is the render cache shared by the Codes of all the sessions.
+var cache = newRenderCache(defaultCacheSize)
+
+// cachedRender is a rendered content and the line of the content each of its
+// lines belongs to.
+type cachedRender struct {
+	content     string
+	sourceLines []int
+}
+
+// size returns the approximate size of the render in bytes.
+func (c cachedRender) size() int {
+	return len(c.content) + 8*len(c.sourceLines)
+}
+
+// renderCache is a least recently used cache of rendered contents bounded by
+// their size. Renders are keyed by the object id of the content and the
+// options it's rendered with, so they never go stale.
+type renderCache struct {
+	mu    sync.Mutex
+	lru   *simplelru.LRU[string, cachedRender]
+	size  int
+	limit int
+}
+
+func newRenderCache(limit int) *renderCache {
+	c := &renderCache{limit: limit}
+	c.lru, _ = simplelru.NewLRU(maxCacheEntries, func(_ string, v cachedRender) {
+		c.size -= v.size()
+	})
+	return c
+}
+
+// setLimit sets the size of the cache in bytes, evicting the least recently
+// used renders that don't fit anymore. A limit of 0 disables the cache.
+func (c *renderCache) setLimit(limit int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.limit = limit
+	c.evict()
+}
+
+// get returns the render of the given key.
+func (c *renderCache) get(key string) (cachedRender, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Get(key)
+}
+
+// add adds a render to the cache. Renders larger than a quarter of the cache
+// aren't kept, so one huge file can't evict everything else.
+func (c *renderCache) add(key string, r cachedRender) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if r.size() > c.limit/4 {
+		return
+	}
+	c.lru.Remove(key)
+	c.lru.Add(key, r)
+	c.size += r.size()
+	c.evict()
+}
+
+// evict removes the least recently used renders until the cache fits its
+// limit. It must be called with the lock held.
+func (c *renderCache) evict() {
+	for c.size > c.limit && c.lru.Len() > 0 {
+		c.lru.RemoveOldest()
+	}
+}
+
+// objectID returns the git blob object id of the given content.
+func objectID(s string) string {
+	h := sha1.New()                       // nolint: gosec
+	fmt.Fprintf(h, "blob %d\x00", len(s)) // nolint: errcheck
+	h.Write([]byte(s))                    // nolint: errcheck
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheKey returns the key of the render of the content with the given
+// options. The key holds everything the render depends on, the terminal
+// width, the color profile of the session, and the side note among others.
+func (r *Code) cacheKey(o renderOptions) string {
+	var sidenote string
+	if o.sidenote != "" {
+		sidenote = objectID(o.sidenote)
+	}
+	return objectID(o.content) + ":" + sidenote + ":" +
+		strconv.Quote(o.extension) + ":" + strconv.Quote(o.language) + ":" + strconv.Quote(o.theme) + ":" +
+		fmt.Sprintf("%d:%d:%d:%g:%t:%t:%t:%t:%d",
+			o.width, o.viewWidth, o.tabWidth, o.sideNotePercent, o.lineNumbers, o.glamour, o.noWrap, o.raw,
+			r.common.Renderer.ColorProfile())
+}
@@ -0,0 +1,56 @@
+package code
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderCache(t *testing.T) {
+	c := newRenderCache(400)
+	r := cachedRender{content: strings.Repeat("a", 92), sourceLines: []int{0}}
+
+	c.add("a", r)
+	c.add("b", r)
+	c.add("c", r)
+	c.add("c", r)
+	if c.size != 300 {
+		t.Fatalf("expected size 300 got %d", c.size)
+	}
+
+	// Getting a render makes it the most recently used.
+	if _, ok := c.get("a"); !ok {
+		t.Fatal("expected a to be cached")
+	}
+	c.add("d", r)
+	c.add("e", r)
+	if _, ok := c.get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	for _, k := range []string{"a", "c", "d", "e"} {
+		if _, ok := c.get(k); !ok {
+			t.Errorf("expected %s to be cached", k)
+		}
+	}
+
+	// Renders larger than a quarter of the cache aren't kept.
+	c.add("big", cachedRender{content: strings.Repeat("a", 101)})
+	if _, ok := c.get("big"); ok {
+		t.Error("expected big not to be cached")
+	}
+
+	c.setLimit(0)
+	if c.size != 0 || c.lru.Len() != 0 {
+		t.Errorf("expected an empty cache got %d renders of %d bytes", c.lru.Len(), c.size)
+	}
+	c.add("a", r)
+	if _, ok := c.get("a"); ok {
+		t.Error("expected a disabled cache")
+	}
+}
+
+func TestObjectID(t *testing.T) {
+	// The object id of "hello\n", see git hash-object.
+	if got := objectID("hello\n"); got != "ce013625030ba8dba906f756967f9e9ca394464a" {
+		t.Errorf("unexpected object id %s", got)
+	}
+}
@@ -4,22 +4,62 @@ import (
 	"math"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
 	gansi "github.com/charmbracelet/glamour/ansi"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
 	vp "github.com/charmbracelet/soft-serve/pkg/ui/components/viewport"
+	"github.com/muesli/reflow/truncate"
 	"github.com/muesli/termenv"
 )
 
 const (
 	defaultTabWidth        = 4
 	defaultSideNotePercent = 0.3
+
+	// asyncRenderSize is the size of the content, in bytes, from which it's
+	// highlighted in the background while a spinner is shown, so that big
+	// files don't freeze the UI.
+	asyncRenderSize = 64 * 1024
 )
 
+// lastID is the last ID given to a Code.
+var lastID int64
+
+// RenderedMsg is a message that contains the content of a Code rendered in
+// the background. It must be passed to the Code even when it isn't visible,
+// a Code ignores the renders of other Codes and of outdated content.
+type RenderedMsg struct {
+	id          int
+	gen         int
+	content     string
+	sourceLines []int
+	err         error
+}
+
+// renderOptions are the settings the content is rendered with. They're copied
+// from the Code so that the content can be rendered in the background.
+type renderOptions struct {
+	width           int
+	viewWidth       int
+	content         string
+	extension       string
+	sidenote        string
+	language        string
+	tabWidth        int
+	sideNotePercent float64
+	lineNumbers     bool
+	glamour         bool
+	noWrap          bool
+	raw             bool
+	theme           string
+}
+
 // Code is a code snippet.
 type Code struct {
 	*vp.Viewport
@@ -31,11 +71,43 @@ type Code struct {
 	renderMutex   sync.Mutex
 	styleConfig   gansi.StyleConfig
 
+	// sourceLines maps the rendered lines to the lines of the content. It's
+	// nil when the rendered lines don't correspond to the content, like
+	// rendered markdown.
+	sourceLines []int
+
 	SideNotePercent float64
 	TabWidth        int
 	ShowLineNumber  bool
 	NoContentStyle  lipgloss.Style
 	UseGlamour      bool
+
+	// Language overrides the language used to highlight the content. The
+	// language is detected from the file name when it's empty.
+	Language string
+
+	// NoWrap cuts the long lines of the content instead of wrapping them.
+	// Theme is the Chroma style the content is highlighted with, the one of
+	// the styles is used when it's empty.
+	NoWrap bool
+	Theme  string
+
+	// Raw renders the content as it's stored, without highlighting or
+	// markdown rendering. It's the escape hatch for the content the
+	// highlighter and glamour choke on.
+	Raw bool
+
+	// id tells the renders of this Code apart, gen is incremented with every
+	// render. loading is true while the content is rendered in the
+	// background, and pending scrolls the viewport once it's done.
+	id      int
+	gen     int
+	loading bool
+	spinner spinner.Model
+	pending func()
+
+	// hex is the hex dump shown instead of the content, if any.
+	hex *hexView
 }
 
 // New returns a new Code.
@@ -48,11 +120,17 @@ func New(c common.Common, content, extension string) *Code {
 		SideNotePercent: defaultSideNotePercent,
 		Viewport:        vp.New(c),
 		NoContentStyle:  c.Styles.NoContent.SetString("No Content."),
+		id:              int(atomic.AddInt64(&lastID, 1)),
+		spinner: spinner.New(spinner.WithSpinner(spinner.Dot),
+			spinner.WithStyle(c.Styles.Spinner)),
 	}
 	st := common.StyleConfig()
 	r.styleConfig = st
 	r.renderContext = common.StyleRendererWithStyles(st)
 	r.SetSize(c.Width, c.Height)
+	if cfg := c.Config(); cfg != nil {
+		cache.setLimit(cfg.UI.HighlightCache << 20)
+	}
 	return r
 }
 
@@ -77,40 +155,115 @@ func (r *Code) SetSideNote(s string) tea.Cmd {
 
 // Init implements tea.Model.
 func (r *Code) Init() tea.Cmd {
-	w := r.common.Width
-	content := r.content
-	if content == "" {
-		r.Viewport.Model.SetContent(r.NoContentStyle.String())
+	r.gen++
+	r.pending = nil
+	r.sourceLines = nil
+	if r.content == "" {
+		r.loading = false
+		r.Viewport.SetContent(r.NoContentStyle.String())
 		return nil
 	}
 
+	o := renderOptions{
+		width:           r.common.Width,
+		viewWidth:       r.Model.Width,
+		content:         r.content,
+		extension:       r.extension,
+		sidenote:        r.sidenote,
+		language:        r.Language,
+		tabWidth:        r.TabWidth,
+		sideNotePercent: r.SideNotePercent,
+		lineNumbers:     r.ShowLineNumber,
+		glamour:         r.UseGlamour,
+		noWrap:          r.NoWrap,
+		raw:             r.Raw,
+		theme:           r.Theme,
+	}
+
+	// Renders are shared by all the sessions, reopening a file is instant.
+	key := r.cacheKey(o)
+	if c, ok := cache.get(key); ok {
+		r.loading = false
+		r.sourceLines = c.sourceLines
+		r.Viewport.SetContent(c.content)
+		return nil
+	}
+
+	if len(o.content) < asyncRenderSize {
+		r.loading = false
+		content, lines, err := r.render(o)
+		if err != nil {
+			return common.ErrorCmd(err)
+		}
+		cache.add(key, cachedRender{content: content, sourceLines: lines})
+		r.sourceLines = lines
+		r.Viewport.SetContent(content)
+		return nil
+	}
+
+	// Large content is highlighted in the background, the spinner is shown
+	// until it's done.
+	r.loading = true
+	id, gen := r.id, r.gen
+	return tea.Batch(r.spinner.Tick, func() tea.Msg {
+		content, lines, err := r.render(o)
+		if err == nil {
+			cache.add(key, cachedRender{content: content, sourceLines: lines})
+		}
+		return RenderedMsg{
+			id:          id,
+			gen:         gen,
+			content:     content,
+			sourceLines: lines,
+			err:         err,
+		}
+	})
+}
+
+// render renders the content with the given options. It returns the rendered
+// content and the line of the content each rendered line belongs to, nil when
+// they don't correspond, like rendered markdown. It's safe to call from a
+// command.
+func (r *Code) render(o renderOptions) (string, []int, error) {
+	w := o.width
+
 	// FIXME chroma & glamour might break wrapping when using tabs since tab
 	// width depends on the terminal. This is a workaround to replace tabs with
 	// 4-spaces.
-	content = strings.ReplaceAll(content, "\t", strings.Repeat(" ", r.TabWidth))
+	content := strings.ReplaceAll(o.content, "\t", strings.Repeat(" ", o.tabWidth))
 
-	if r.UseGlamour && common.IsFileMarkdown(content, r.extension) {
+	glamourized := o.glamour && !o.raw && common.IsFileMarkdown(content, o.extension)
+	switch {
+	case o.raw:
+		content = renderRaw(content)
+		if o.lineNumbers {
+			var ml int
+			content, ml = common.FormatLineNumber(r.common.Styles, content, false)
+			w -= ml
+		}
+	case glamourized:
 		md, err := r.glamourize(w, content)
 		if err != nil {
-			return common.ErrorCmd(err)
+			return "", nil, err
 		}
 		content = md
-	} else {
-		f, err := r.renderFile(r.extension, content)
+	default:
+		f, err := r.renderFile(o.extension, content, o.language, o.theme, o.lineNumbers)
 		if err != nil {
-			return common.ErrorCmd(err)
+			return "", nil, err
 		}
 		content = f
-		if r.ShowLineNumber {
+		if o.lineNumbers {
 			var ml int
 			content, ml = common.FormatLineNumber(r.common.Styles, content, true)
 			w -= ml
 		}
+		content = r.highlightConflicts(content, FindConflicts(o.content), o.lineNumbers)
 	}
 
-	if r.sidenote != "" {
-		lines := strings.Split(r.sidenote, "\n")
-		sideNoteWidth := int(math.Ceil(float64(r.Model.Width) * r.SideNotePercent))
+	if o.sidenote != "" {
+		lines := strings.Split(o.sidenote, "\n")
+		sideNoteWidth := int(math.Ceil(float64(o.viewWidth) * o.sideNotePercent))
 		for i, l := range lines {
 			lines[i] = common.TruncateString(l, sideNoteWidth)
 		}
@@ -121,20 +274,65 @@ func (r *Code) Init() tea.Cmd {
 	// https://github.com/muesli/reflow/issues/43
 	//
 	// TODO: solve this upstream in Glamour/Reflow.
-	content = r.common.Renderer.NewStyle().Width(w).Render(content)
+	st := r.common.Renderer.NewStyle().Width(w)
+	if glamourized {
+		return st.Render(content), nil, nil
+	}
 
-	r.Viewport.Model.SetContent(content)
+	// Wrap line by line to keep track of which line of the content each
+	// rendered line belongs to.
+	lines := strings.Split(content, "\n")
+	rendered := make([]string, 0, len(lines))
+	sourceLines := make([]int, 0, len(lines))
+	for i, l := range lines {
+		if o.noWrap {
+			l = truncate.String(l, uint(max(w, 0)))
+		}
+		for _, wl := range strings.Split(st.Render(l), "\n") {
+			rendered = append(rendered, wl)
+			sourceLines = append(sourceLines, i)
+		}
+	}
 
-	return nil
+	return strings.Join(rendered, "\n"), sourceLines, nil
 }
 
 // Update implements tea.Model.
 func (r *Code) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	cmds := make([]tea.Cmd, 0)
-	switch msg.(type) {
+	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		// Recalculate content width and line wrap.
 		cmds = append(cmds, r.Init())
+	case tea.KeyMsg, tea.MouseMsg:
+		if r.hex != nil {
+			r.updateHex(msg)
+			return r, nil
+		}
+	case RenderedMsg:
+		if msg.id != r.id || msg.gen != r.gen {
+			// The content changed since, the render is stale.
+			break
+		}
+		r.loading = false
+		if msg.err != nil {
+			r.Viewport.SetContent(r.common.Styles.NoContent.Render("Failed to render the content: " + msg.err.Error()))
+			break
+		}
+		r.sourceLines = msg.sourceLines
+		r.Viewport.SetContent(msg.content)
+		if r.pending != nil {
+			r.pending()
+			r.pending = nil
+		}
+	case spinner.TickMsg:
+		if r.loading {
+			s, cmd := r.spinner.Update(msg)
+			r.spinner = s
+			if cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
 	}
 	v, cmd := r.Viewport.Update(msg)
 	r.Viewport = v.(*vp.Viewport)
@@ -146,51 +344,204 @@ func (r *Code) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View implements tea.View.
 func (r *Code) View() string {
+	if r.loading {
+		return r.common.Styles.SpinnerContainer.
+			Height(r.common.Height).
+			Render(r.spinner.View() + " loading…")
+	}
+	if r.hex != nil {
+		return r.renderHex()
+	}
 	return r.Viewport.View()
 }
 
+// IsLoading returns true while the content is being rendered in the
+// background.
+func (r *Code) IsLoading() bool {
+	return r.loading
+}
+
+// SelectedLines returns the first and last selected lines of the content, one
+// based, and whether there is a selection.
+func (r *Code) SelectedLines() (start int, end int, ok bool) {
+	start, end, ok = r.Viewport.Selection()
+	if !ok || r.sourceLines == nil {
+		return 0, 0, false
+	}
+	return r.sourceLines[start] + 1, r.sourceLines[end] + 1, true
+}
+
+// SelectedContent returns the selected lines of the content. Rendered
+// markdown returns the selected text as shown.
+func (r *Code) SelectedContent() string {
+	start, end, ok := r.SelectedLines()
+	if !ok {
+		return r.Viewport.SelectedText()
+	}
+	lines := strings.Split(r.content, "\n")
+	start, end = min(start, len(lines)), min(end, len(lines))
+	return strings.Join(lines[start-1:end], "\n")
+}
+
+// CurrentLine returns the line of the content, one based, at the start of the
+// selection or at the top of the view when nothing is selected. It returns 0
+// when the rendered lines don't correspond to the content.
+func (r *Code) CurrentLine() int {
+	if start, _, ok := r.SelectedLines(); ok {
+		return start
+	}
+	if len(r.sourceLines) == 0 {
+		return 0
+	}
+	i := min(max(0, r.YOffset), len(r.sourceLines)-1)
+	return r.sourceLines[i] + 1
+}
+
+// GotoLine scrolls the view to the given line of the content, one based.
+func (r *Code) GotoLine(n int) {
+	if r.hex != nil {
+		return
+	}
+	if r.loading {
+		r.pending = func() { r.GotoLine(n) }
+		return
+	}
+	for i, l := range r.sourceLines {
+		if l >= n-1 {
+			r.SetYOffset(i)
+			return
+		}
+	}
+	r.GotoBottom()
+}
+
+// SelectLines selects the lines of the content from start to end, one based,
+// and scrolls the view to the first of them.
+func (r *Code) SelectLines(start, end int) {
+	if r.hex != nil {
+		return
+	}
+	if r.loading {
+		r.pending = func() { r.SelectLines(start, end) }
+		return
+	}
+	first, last := -1, -1
+	for i, l := range r.sourceLines {
+		if l < start-1 || l > end-1 {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		last = i
+	}
+	if first < 0 {
+		r.GotoLine(start)
+		return
+	}
+	r.Viewport.Select(first, last)
+	r.SetYOffset(first)
+}
+
 // GotoTop moves the viewport to the top of the log.
 func (r *Code) GotoTop() {
+	if r.hex != nil {
+		r.setHexTop(0)
+		return
+	}
+	if r.loading {
+		r.pending = r.Viewport.GotoTop
+		return
+	}
 	r.Viewport.GotoTop()
 }
 
+// SetYOffset scrolls the viewport to the given rendered line. While the
+// content is being rendered, the viewport scrolls once it's done.
+func (r *Code) SetYOffset(n int) {
+	if r.hex != nil {
+		r.setHexTop(int64(n))
+		return
+	}
+	if r.loading {
+		r.pending = func() { r.Viewport.SetYOffset(n) }
+		return
+	}
+	r.Viewport.SetYOffset(n)
+}
+
 // GotoBottom moves the viewport to the bottom of the log.
 func (r *Code) GotoBottom() {
+	if r.hex != nil {
+		r.setHexTop(r.hexRows())
+		return
+	}
 	r.Viewport.GotoBottom()
 }
 
 // HalfViewDown moves the viewport down by half the viewport height.
 func (r *Code) HalfViewDown() {
+	if r.hex != nil {
+		r.setHexTop(r.hex.top + int64(r.Viewport.Height/2))
+		return
+	}
 	r.Viewport.HalfViewDown()
 }
 
 // HalfViewUp moves the viewport up by half the viewport height.
 func (r *Code) HalfViewUp() {
+	if r.hex != nil {
+		r.setHexTop(r.hex.top - int64(r.Viewport.Height/2))
+		return
+	}
 	r.Viewport.HalfViewUp()
 }
 
 // ViewUp moves the viewport up by a page.
 func (r *Code) ViewUp() []string {
+	if r.hex != nil {
+		r.setHexTop(r.hex.top - int64(r.Viewport.Height))
+		return nil
+	}
 	return r.Viewport.ViewUp()
 }
 
 // ViewDown moves the viewport down by a page.
 func (r *Code) ViewDown() []string {
+	if r.hex != nil {
+		r.setHexTop(r.hex.top + int64(r.Viewport.Height))
+		return nil
+	}
 	return r.Viewport.ViewDown()
 }
 
 // LineUp moves the viewport up by the given number of lines.
 func (r *Code) LineUp(n int) []string {
+	if r.hex != nil {
+		r.setHexTop(r.hex.top - int64(n))
+		return nil
+	}
 	return r.Viewport.LineUp(n)
 }
 
 // LineDown moves the viewport down by the given number of lines.
 func (r *Code) LineDown(n int) []string {
+	if r.hex != nil {
+		r.setHexTop(r.hex.top + int64(n))
+		return nil
+	}
 	return r.Viewport.LineDown(n)
 }
 
 // ScrollPercent returns the viewport's scroll percentage.
 func (r *Code) ScrollPercent() float64 {
+	if r.hex != nil {
+		last := r.hexRows() - int64(r.Viewport.Height)
+		if last <= 0 {
+			return 1
+		}
+		return float64(r.hex.top) / float64(last)
+	}
 	return r.Viewport.ScrollPercent()
 }
 
@@ -216,18 +567,25 @@ func (r *Code) glamourize(w int, md string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	mdt, err := tr.Render(md)
+	mdt, err := tr.Render(common.PrepareMarkdown(md, w))
 	if err != nil {
 		return "", err
 	}
 	return mdt, nil
 }
 
-func (r *Code) renderFile(path, content string) (string, error) {
+func (r *Code) renderFile(path, content, language, theme string, lineNumbers bool) (string, error) {
+	r.renderMutex.Lock()
+	defer r.renderMutex.Unlock()
 	lexer := lexers.Match(path)
 	if path == "" {
 		lexer = lexers.Analyse(content)
 	}
+	if language != "" {
+		if l := lexers.Get(language); l != nil {
+			lexer = l
+		}
+	}
 	lang := ""
 	if lexer != nil && lexer.Config() != nil {
 		lang = lexer.Config().Name
@@ -239,10 +597,16 @@ func (r *Code) renderFile(path, content string) (string, error) {
 	}
 	s := strings.Builder{}
 	rc := r.renderContext
-	if r.ShowLineNumber {
+	if lineNumbers || theme != "" {
 		st := common.StyleConfig()
-		var m uint
-		st.CodeBlock.Margin = &m
+		if lineNumbers {
+			var m uint
+			st.CodeBlock.Margin = &m
+		}
+		if theme != "" {
+			st.CodeBlock.Chroma = nil
+			st.CodeBlock.Theme = theme
+		}
 		rc = gansi.NewRenderContext(gansi.Options{
 			ColorProfile: termenv.TrueColor,
 			Styles:       st,
@@ -0,0 +1,89 @@
+package code
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Conflict is a region of content between unresolved merge conflict markers.
+// Its lines are one based.
+type Conflict struct {
+	// Start is the line of the <<<<<<< marker and End the one of the >>>>>>>
+	// marker. Separator is the line of the ======= marker between both sides.
+	Start     int
+	Separator int
+	End       int
+	// Base is the line of the ||||||| marker of the diff3 style, which the
+	// common ancestor follows, 0 when there is none.
+	Base int
+}
+
+// conflictMarker returns whether the line is a conflict marker made of seven
+// times c, optionally followed by a label.
+func conflictMarker(line string, c byte, label bool) bool {
+	line = strings.TrimSuffix(line, "\r")
+	if len(line) < 7 || strings.Count(line[:7], string(c)) != 7 {
+		return false
+	}
+	if len(line) == 7 {
+		return true
+	}
+	return label && line[7] == ' '
+}
+
+// FindConflicts returns the regions of the content between unresolved merge
+// conflict markers. Markers that don't make a complete region are ignored.
+func FindConflicts(content string) []Conflict {
+	if !strings.Contains(content, "<<<<<<<") {
+		return nil
+	}
+	var conflicts []Conflict
+	var cur *Conflict
+	for i, l := range strings.Split(content, "\n") {
+		n := i + 1
+		switch {
+		case conflictMarker(l, '<', true):
+			cur = &Conflict{Start: n}
+		case cur == nil:
+		case cur.Separator == 0 && cur.Base == 0 && conflictMarker(l, '|', true):
+			cur.Base = n
+		case cur.Separator == 0 && conflictMarker(l, '=', false):
+			cur.Separator = n
+		case cur.Separator != 0 && conflictMarker(l, '>', true):
+			cur.End = n
+			conflicts = append(conflicts, *cur)
+			cur = nil
+		}
+	}
+	return conflicts
+}
+
+// highlightConflicts styles the rendered lines of the content that belong to
+// the conflicts. Markers stand out, and the line bars of both sides and of
+// the common ancestor are colored when there are line numbers.
+func (r *Code) highlightConflicts(content string, conflicts []Conflict, lineNumbers bool) string {
+	if len(conflicts) == 0 {
+		return content
+	}
+	s := r.common.Styles.Code
+	bar := s.LineBar.Render("│")
+	lines := strings.Split(content, "\n")
+	for _, c := range conflicts {
+		for n := c.Start; n <= c.End && n <= len(lines); n++ {
+			i := n - 1
+			switch {
+			case n == c.Start || n == c.Base || n == c.Separator || n == c.End:
+				lines[i] = s.ConflictMarker.Render(ansi.Strip(lines[i]))
+			case !lineNumbers:
+			case c.Base != 0 && n > c.Base && n < c.Separator:
+				lines[i] = strings.Replace(lines[i], bar, s.ConflictBase.Render("┃"), 1)
+			case n < c.Separator:
+				lines[i] = strings.Replace(lines[i], bar, s.ConflictOurs.Render("┃"), 1)
+			default:
+				lines[i] = strings.Replace(lines[i], bar, s.ConflictTheirs.Render("┃"), 1)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
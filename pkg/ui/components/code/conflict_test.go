@@ -0,0 +1,60 @@
+package code
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindConflicts(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    []Conflict
+	}{
+		{
+			name:    "none",
+			content: "a\nb\n",
+		},
+		{
+			name:    "merge",
+			content: "a\n<<<<<<< HEAD\nb\n=======\nc\n>>>>>>> feature\nd\n",
+			want:    []Conflict{{Start: 2, Separator: 4, End: 6}},
+		},
+		{
+			name:    "diff3",
+			content: "<<<<<<< ours\na\n||||||| base\nb\n=======\nc\n>>>>>>> theirs\n",
+			want:    []Conflict{{Start: 1, Base: 3, Separator: 5, End: 7}},
+		},
+		{
+			name:    "crlf and no labels",
+			content: "<<<<<<<\r\na\r\n=======\r\nb\r\n>>>>>>>\r\n",
+			want:    []Conflict{{Start: 1, Separator: 3, End: 5}},
+		},
+		{
+			name:    "several",
+			content: "<<<<<<< a\n=======\n>>>>>>> b\nx\n<<<<<<< a\ny\n=======\n>>>>>>> b\n",
+			want:    []Conflict{{Start: 1, Separator: 2, End: 3}, {Start: 5, Separator: 7, End: 8}},
+		},
+		{
+			name:    "incomplete",
+			content: "<<<<<<< a\nb\n>>>>>>> c\n=======\n",
+		},
+		{
+			name:    "restarted",
+			content: "<<<<<<< a\n<<<<<<< a\nb\n=======\nc\n>>>>>>> d\n",
+			want:    []Conflict{{Start: 2, Separator: 4, End: 6}},
+		},
+		{
+			name:    "not markers",
+			content: "<<<<<<<< a\n======== \n>>>>>>>> b\n<<<<<<<x\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := FindConflicts(c.content)
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("expected %+v got %+v", c.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package code
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// hexPageSize is the number of bytes of a hex source read at once.
+const hexPageSize = 16 * 1024
+
+// HexSource is the content of a hex view. It's read a page at a time as the
+// view is scrolled, big files aren't read in memory all at once.
+type HexSource interface {
+	io.ReaderAt
+	Size() int64
+}
+
+// hexView is a hex dump of a HexSource. top is the first visible row, and
+// page holds the bytes read from pageOff.
+type hexView struct {
+	src     HexSource
+	size    int64
+	top     int64
+	page    []byte
+	pageOff int64
+}
+
+// SetHex shows a hex dump of src instead of the content, or the content again
+// when src is nil. The dump starts at the top.
+func (r *Code) SetHex(src HexSource) {
+	if src == nil {
+		r.hex = nil
+		return
+	}
+	r.hex = &hexView{src: src, size: src.Size()}
+}
+
+// IsHex returns true while the hex dump is shown.
+func (r *Code) IsHex() bool {
+	return r.hex != nil
+}
+
+// updateHex scrolls the hex dump with the keys and the mouse wheel of the
+// viewport.
+func (r *Code) updateHex(msg tea.Msg) {
+	k := r.Viewport.KeyMap
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, k.Down):
+			r.LineDown(1)
+		case key.Matches(msg, k.Up):
+			r.LineUp(1)
+		case key.Matches(msg, k.PageDown):
+			r.ViewDown()
+		case key.Matches(msg, k.PageUp):
+			r.ViewUp()
+		case key.Matches(msg, k.HalfPageDown):
+			r.HalfViewDown()
+		case key.Matches(msg, k.HalfPageUp):
+			r.HalfViewUp()
+		case key.Matches(msg, r.common.KeyMap.GotoTop):
+			r.GotoTop()
+		case key.Matches(msg, r.common.KeyMap.GotoBottom):
+			r.GotoBottom()
+		}
+	case tea.MouseMsg:
+		switch msg.Button {
+		case tea.MouseButtonWheelDown:
+			r.LineDown(r.Viewport.MouseWheelDelta)
+		case tea.MouseButtonWheelUp:
+			r.LineUp(r.Viewport.MouseWheelDelta)
+		}
+	}
+}
+
+// hexWidth returns the number of bytes shown in a row, as many as fit in the
+// width of the view, 16 at most.
+func (r *Code) hexWidth() int {
+	digits := r.hexDigits()
+	for _, n := range []int{16, 8} {
+		// " offset │ bytes │ ascii ", with a space between groups of 8.
+		if 1+digits+3+n*3-1+(n/8-1)+3+n <= r.Viewport.Width {
+			return n
+		}
+	}
+	return 4
+}
+
+// hexDigits returns the number of hex digits of the offsets, 8 unless the
+// source is bigger.
+func (r *Code) hexDigits() int {
+	return max(8, len(fmt.Sprintf("%x", r.hex.size)))
+}
+
+// hexRows returns the number of rows of the hex dump.
+func (r *Code) hexRows() int64 {
+	n := int64(r.hexWidth())
+	return (r.hex.size + n - 1) / n
+}
+
+// setHexTop scrolls the hex dump to the given row, as far as the last row
+// stays at the bottom.
+func (r *Code) setHexTop(row int64) {
+	last := max(0, r.hexRows()-int64(r.Viewport.Height))
+	r.hex.top = min(max(0, row), last)
+}
+
+// hexBytes returns n bytes of the source from offset off, fewer at the end of
+// the source. The page they belong to is read if it isn't already.
+func (r *Code) hexBytes(off int64, n int) ([]byte, error) {
+	h := r.hex
+	end := min(off+int64(n), h.size)
+	if off < h.pageOff || end > h.pageOff+int64(len(h.page)) {
+		start := off - off%hexPageSize
+		size := min(h.size-start, (end-start+hexPageSize-1)/hexPageSize*hexPageSize)
+		page := make([]byte, size)
+		m, err := h.src.ReadAt(page, start)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		h.page, h.pageOff = page[:m], start
+	}
+	lo := min(off-h.pageOff, int64(len(h.page)))
+	hi := min(end-h.pageOff, int64(len(h.page)))
+	return h.page[lo:hi], nil
+}
+
+// renderHex renders the visible rows of the hex dump: the offset, the bytes,
+// and their printable characters.
+func (r *Code) renderHex() string {
+	if r.hex.size == 0 {
+		return r.NoContentStyle.String()
+	}
+	n := r.hexWidth()
+	digits := r.hexDigits()
+	st := r.common.Styles.Code
+	bar := st.LineBar.Render("│")
+	r.setHexTop(r.hex.top)
+
+	rows := make([]string, 0, r.Viewport.Height)
+	for row := r.hex.top; row < r.hexRows() && len(rows) < r.Viewport.Height; row++ {
+		off := row * int64(n)
+		b, err := r.hexBytes(off, n)
+		if err != nil {
+			return r.common.Styles.NoContent.Render("Failed to read the file: " + err.Error())
+		}
+
+		var hex, ascii strings.Builder
+		for i := 0; i < n; i++ {
+			switch {
+			case i > 0 && i%8 == 0:
+				hex.WriteString("  ")
+			case i > 0:
+				hex.WriteString(" ")
+			}
+			if i >= len(b) {
+				hex.WriteString("  ")
+				continue
+			}
+			fmt.Fprintf(&hex, "%02x", b[i])
+			if c := b[i]; c >= 0x20 && c < 0x7f {
+				ascii.WriteByte(c)
+			} else {
+				ascii.WriteByte('.')
+			}
+		}
+		offset := st.LineDigit.Render(fmt.Sprintf("%0*x", digits, off))
+		rows = append(rows, fmt.Sprintf(" %s %s %s %s %s", offset, bar, hex.String(), bar, ascii.String()))
+	}
+
+	return r.common.Renderer.NewStyle().
+		Height(r.Viewport.Height).
+		MaxHeight(r.Viewport.Height).
+		Render(strings.Join(rows, "\n"))
+}
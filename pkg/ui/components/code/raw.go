@@ -0,0 +1,37 @@
+package code
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// renderRaw returns the content as it's stored, without highlighting or
+// markdown rendering. Control characters are shown in caret notation, like
+// `cat -v`, so that they can't mess with the terminal, and invalid UTF-8 as
+// the replacement character.
+func renderRaw(content string) string {
+	var s strings.Builder
+	s.Grow(len(content))
+	for i := 0; i < len(content); {
+		r, size := utf8.DecodeRuneInString(content[i:])
+		i += size
+		switch {
+		case r == utf8.RuneError && size == 1:
+			s.WriteRune(utf8.RuneError)
+		case r == '\n':
+			s.WriteRune(r)
+		case r < 0x20:
+			s.WriteByte('^')
+			s.WriteByte(byte(r) + '@')
+		case r == 0x7f:
+			s.WriteString("^?")
+		case r >= 0x80 && r < 0xa0:
+			// C1 control characters.
+			s.WriteString("M-^")
+			s.WriteByte(byte(r-0x80) + '@')
+		default:
+			s.WriteRune(r)
+		}
+	}
+	return s.String()
+}
@@ -0,0 +1,45 @@
+package code
+
+import "testing"
+
+func TestRenderRaw(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "plain",
+			content: "# Title\n*emphasis*\n",
+			want:    "# Title\n*emphasis*\n",
+		},
+		{
+			name:    "escape sequences",
+			content: "\x1b[31mred\x1b[0m\n",
+			want:    "^[[31mred^[[0m\n",
+		},
+		{
+			name:    "crlf",
+			content: "a\r\nb\r\n",
+			want:    "a^M\nb^M\n",
+		},
+		{
+			name:    "nul and del",
+			content: "a\x00b\x7f",
+			want:    "a^@b^?",
+		},
+		{
+			name:    "c1 and invalid utf-8",
+			content: "\u009bé\xff",
+			want:    "M-^[é�",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := renderRaw(c.content); got != c.want {
+				t.Errorf("renderRaw(%q) = %q, want %q", c.content, got, c.want)
+			}
+		})
+	}
+}
@@ -2,15 +2,33 @@ package header
 
 import (
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
 )
 
+// maintenanceInterval is how often the maintenance mode is checked.
+const maintenanceInterval = 30 * time.Second
+
+// MaintenanceMsg is a message that reports whether the server is in
+// maintenance mode, or a read-only follower of a primary.
+type MaintenanceMsg struct {
+	Enabled bool
+	Message string
+	Primary string
+}
+
+// maintenanceTickMsg is a message to check the maintenance mode again.
+type maintenanceTickMsg struct{}
+
 // Header represents a header component.
 type Header struct {
-	common common.Common
-	text   string
+	common      common.Common
+	text        string
+	maintenance MaintenanceMsg
 }
 
 // New creates a new header component.
@@ -28,15 +46,64 @@ func (h *Header) SetSize(width, height int) {
 
 // Init implements tea.Model.
 func (h *Header) Init() tea.Cmd {
-	return nil
+	return h.maintenanceCmd
 }
 
 // Update implements tea.Model.
-func (h *Header) Update(_ tea.Msg) (tea.Model, tea.Cmd) {
+func (h *Header) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case MaintenanceMsg:
+		h.maintenance = msg
+		return h, tea.Tick(maintenanceInterval, func(time.Time) tea.Msg {
+			return maintenanceTickMsg{}
+		})
+	case maintenanceTickMsg:
+		return h, h.maintenanceCmd
+	}
 	return h, nil
 }
 
+// maintenanceCmd checks whether the server is in maintenance mode.
+func (h *Header) maintenanceCmd() tea.Msg {
+	be := h.common.Backend()
+	if be == nil {
+		return nil
+	}
+
+	ctx := h.common.Context()
+	return MaintenanceMsg{
+		Enabled: be.MaintenanceMode(ctx),
+		Message: be.MaintenanceMessage(ctx),
+		Primary: be.Primary(),
+	}
+}
+
 // View implements tea.Model.
 func (h *Header) View() string {
-	return h.common.Styles.ServerName.Render(strings.TrimSpace(h.text))
+	name := h.common.Styles.ServerName.Render(strings.TrimSpace(h.text))
+	if user := h.common.User(); user != nil {
+		name = lipgloss.JoinHorizontal(lipgloss.Top,
+			name,
+			h.common.Styles.ServerUser.Render(proto.DisplayName(user)),
+		)
+	}
+	var notice string
+	switch {
+	case h.maintenance.Enabled:
+		notice = "Read-only maintenance"
+		if h.maintenance.Message != "" {
+			notice += ": " + h.maintenance.Message
+		}
+	case h.maintenance.Primary != "":
+		notice = "Read-only follower of " + h.maintenance.Primary
+	}
+	if notice != "" {
+		width := h.common.Width - lipgloss.Width(name) -
+			h.common.Styles.Maintenance.GetHorizontalFrameSize()
+		name = lipgloss.JoinHorizontal(lipgloss.Top,
+			name,
+			h.common.Styles.Maintenance.Render(common.TruncateString(notice, width)),
+		)
+	}
+	return name
 }
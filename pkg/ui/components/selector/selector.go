@@ -1,11 +1,14 @@
 package selector
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
 )
 
@@ -16,6 +19,16 @@ type Selector struct {
 	active      int
 	filterState list.FilterState
 
+	// window holds the IDs of the items shown on the current page, to tell
+	// when it changes.
+	window string
+
+	// marked is the set of marked item IDs used for bulk actions.
+	marked map[string]struct{}
+
+	// emptyMessage replaces the list message shown when there are no items.
+	emptyMessage string
+
 	// XXX: we use a mutex to support concurrent access to the model. This is
 	// needed to implement pagination for the Log component. list.Model does
 	// not support item pagination so we hack it ourselves on top of
@@ -41,6 +54,19 @@ type SelectMsg struct{ IdentifiableItem }
 // ActiveMsg is a message that is sent when an item is active but not selected.
 type ActiveMsg struct{ IdentifiableItem }
 
+// WindowMsg is a message that is sent when the items shown on the current page
+// change, e.g. after moving to another page or filtering. Only the items of the
+// page are rendered, so expensive details of items can be loaded as they're
+// shown, and lists can hold placeholders for items that aren't loaded yet.
+//
+// Start and End are the bounds of the page in the visible items, and Items
+// holds its items, without the placeholders.
+type WindowMsg struct {
+	Start int
+	End   int
+	Items []IdentifiableItem
+}
+
 // New creates a new selector.
 func New(common common.Common, items []IdentifiableItem, delegate ItemDelegate) *Selector {
 	itms := make([]list.Item, len(items))
@@ -52,6 +78,7 @@ func New(common common.Common, items []IdentifiableItem, delegate ItemDelegate)
 	s := &Selector{
 		Model:  &l,
 		common: common,
+		marked: map[string]struct{}{},
 	}
 	s.SetSize(common.Width, common.Height)
 	return s
@@ -160,6 +187,14 @@ func (s *Selector) SetFilteringEnabled(enabled bool) {
 	s.Model.SetFilteringEnabled(enabled)
 }
 
+// SetEmptyMessage sets the message shown when there are no items. The list
+// default message is shown when it's empty.
+func (s *Selector) SetEmptyMessage(msg string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.emptyMessage = msg
+}
+
 // SetSize implements common.Component.
 func (s *Selector) SetSize(width, height int) {
 	s.mtx.Lock()
@@ -179,6 +214,51 @@ func (s *Selector) SetItems(items []IdentifiableItem) tea.Cmd {
 	return s.Model.SetItems(its)
 }
 
+// ToggleMarked marks the item with the given ID or unmarks it if it's already
+// marked.
+func (s *Selector) ToggleMarked(id string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, ok := s.marked[id]; ok {
+		delete(s.marked, id)
+	} else {
+		s.marked[id] = struct{}{}
+	}
+}
+
+// IsMarked returns true if the item with the given ID is marked.
+func (s *Selector) IsMarked(id string) bool {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	_, ok := s.marked[id]
+	return ok
+}
+
+// MarkedItems returns the marked items in the order they're listed. Marked
+// items that aren't in the selector anymore are ignored.
+func (s *Selector) MarkedItems() []IdentifiableItem {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	items := make([]IdentifiableItem, 0, len(s.marked))
+	for _, it := range s.Model.Items() {
+		i, ok := it.(IdentifiableItem)
+		if !ok {
+			continue
+		}
+		if _, ok := s.marked[i.ID()]; ok {
+			items = append(items, i)
+		}
+	}
+	return items
+}
+
+// ClearMarked unmarks all the items.
+func (s *Selector) ClearMarked() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.marked = map[string]struct{}{}
+}
+
 // Index returns the index of the selected item.
 func (s *Selector) Index() int {
 	s.mtx.RLock()
@@ -200,6 +280,27 @@ func (s *Selector) VisibleItems() []list.Item {
 	return s.Model.VisibleItems()
 }
 
+// WindowItems returns the items shown on the current page.
+func (s *Selector) WindowItems() []IdentifiableItem {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.windowItems()
+}
+
+// windowItems returns the items shown on the current page. It must be called
+// with the lock held.
+func (s *Selector) windowItems() []IdentifiableItem {
+	visible := s.Model.VisibleItems()
+	start, end := s.Model.Paginator.GetSliceBounds(len(visible))
+	items := make([]IdentifiableItem, 0, end-start)
+	for _, it := range visible[start:end] {
+		if i, ok := it.(IdentifiableItem); ok {
+			items = append(items, i)
+		}
+	}
+	return items
+}
+
 // FilterState returns the filter state.
 func (s *Selector) FilterState() list.FilterState {
 	s.mtx.RLock()
@@ -240,15 +341,20 @@ func (s *Selector) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case tea.MouseButtonWheelDown:
 			s.CursorDown()
 		case tea.MouseButtonLeft:
+			// Only the items of the current page are rendered, so they're the
+			// only ones that can be clicked.
 			curIdx := s.Index()
-			for i, item := range s.Items() {
-				item, _ := item.(IdentifiableItem)
+			s.mtx.RLock()
+			start, _ := s.Model.Paginator.GetSliceBounds(len(s.Model.VisibleItems()))
+			items := s.windowItems()
+			s.mtx.RUnlock()
+			for i, item := range items {
 				// Check each item to see if it's in bounds.
-				if item != nil && s.common.Zone.Get(item.ID()).InBounds(msg) {
-					if i == curIdx {
+				if s.common.Zone.Get(item.ID()).InBounds(msg) {
+					if start+i == curIdx {
 						cmds = append(cmds, s.SelectItemCmd)
 					} else {
-						s.Select(i)
+						s.Select(start + i)
 					}
 					break
 				}
@@ -287,11 +393,21 @@ func (s *Selector) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, s.activeCmd)
 	}
 	s.active = s.Index()
+	// Send WindowMsg when the items of the page change.
+	if cmd := s.windowCmd(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
 	return s, tea.Batch(cmds...)
 }
 
 // View implements tea.Model.
 func (s *Selector) View() string {
+	if s.emptyMessage != "" && len(s.Model.Items()) == 0 {
+		st := s.Model.Styles.NoItems
+		return lipgloss.NewStyle().
+			Height(s.Model.Height()).
+			Render(st.Width(s.Model.Width() - st.GetHorizontalMargins()).Render(s.emptyMessage))
+	}
 	return s.Model.View()
 }
 
@@ -305,6 +421,28 @@ func (s *Selector) activeCmd() tea.Msg {
 	return ActiveMsg{item}
 }
 
+// windowCmd returns a command that sends a WindowMsg if the items of the page
+// changed since the last one.
+func (s *Selector) windowCmd() tea.Cmd {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	start, end := s.Model.Paginator.GetSliceBounds(len(s.Model.VisibleItems()))
+	items := s.windowItems()
+	// The bounds tell apart pages that only hold placeholders.
+	ids := []string{fmt.Sprintf("%d-%d", start, end)}
+	for _, it := range items {
+		ids = append(ids, it.ID())
+	}
+	window := strings.Join(ids, "\n")
+	if window == s.window {
+		return nil
+	}
+	s.window = window
+	return func() tea.Msg {
+		return WindowMsg{Start: start, End: end, Items: items}
+	}
+}
+
 func (s *Selector) activeFilterCmd() tea.Msg {
 	// Here we use VisibleItems because when list.FilterMatchesMsg is sent,
 	// VisibleItems is the only way to get the list of filtered items. The list
@@ -1,10 +1,15 @@
 package statusbar
 
 import (
+	"slices"
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/pkg/config"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
 	"github.com/muesli/reflow/truncate"
+	"github.com/muesli/reflow/wrap"
 )
 
 // Model is a status bar model.
@@ -14,6 +19,16 @@ type Model struct {
 	value  string
 	info   string
 	extra  string
+	// fields are the values of the other segments, e.g. "sha", by segment.
+	fields map[string]string
+
+	// compact stacks the status bar on two lines for narrow terminals.
+	compact bool
+
+	// path truncates the value in the middle, it's a path.
+	path bool
+	// expanded shows the whole path above the status bar.
+	expanded bool
 }
 
 // New creates a new status bar component.
@@ -46,6 +61,46 @@ func (s *Model) SetStatus(key, value, info, extra string) {
 	}
 }
 
+// SetFields sets the values of the segments other than the key, value,
+// info, and extra, e.g. "sha" and "access", by segment.
+func (s *Model) SetFields(fields map[string]string) {
+	s.fields = fields
+}
+
+// SetCompact sets whether the status bar is stacked on two lines, the key
+// and extra sharing the first one with the help, and the value and info on
+// the second one.
+func (s *Model) SetCompact(compact bool) {
+	s.compact = compact
+}
+
+// SetPath sets whether the value is a path, truncated in the middle to keep
+// its first and last elements, and whether the whole path is shown above the
+// status bar.
+func (s *Model) SetPath(path, expanded bool) {
+	s.path = path
+	s.expanded = path && expanded
+}
+
+// SetNotice shows a short notice in place of the value, e.g. "Command
+// copied to clipboard". It's not a path, even if the value was.
+func (s *Model) SetNotice(notice string) {
+	s.value = notice
+	s.SetPath(false, false)
+}
+
+// Height returns the number of lines of the status bar.
+func (s *Model) Height() int {
+	h := s.common.Styles.StatusBar.GetHeight()
+	if s.compact {
+		h *= 2
+	}
+	if s.expanded {
+		h += lipgloss.Height(s.pathView())
+	}
+	return h
+}
+
 // Init implements tea.Model.
 func (s *Model) Init() tea.Cmd {
 	return nil
@@ -68,26 +123,148 @@ func (s *Model) View() string {
 		"repo-help",
 		st.StatusBarHelp.Render("? Help"),
 	)
-	key := st.StatusBarKey.Render(s.key)
-	info := ""
-	if s.info != "" {
-		info = st.StatusBarInfo.Render(s.info)
-	}
-	branch := st.StatusBarBranch.Render(s.extra)
-	maxWidth := s.common.Width - w(key) - w(info) - w(branch) - w(help)
-	v := truncate.StringWithTail(s.value, uint(maxWidth-st.StatusBarValue.GetHorizontalFrameSize()), "…")
-	value := st.StatusBarValue.
-		Width(maxWidth).
-		Render(v)
+	layout := s.layout()
+	var path string
+	if s.expanded {
+		path = s.pathView()
+	}
+	if s.compact {
+		// The value and info go on the second line, the other segments
+		// share the first one with the help.
+		top := make([]string, 0, len(layout))
+		bottom := make([]string, 0, 2)
+		for _, name := range layout {
+			switch name {
+			case "value", "info":
+				bottom = append(bottom, name)
+			default:
+				top = append(top, s.segment(name))
+			}
+		}
+		if !slices.Contains(bottom, "value") {
+			bottom = append(bottom, "value")
+		}
+		fill := st.StatusBarValue.
+			Width(max(s.common.Width-joinedWidth(top)-w(help), 0)).
+			Render("")
+		if len(top) > 0 {
+			top = append(top[:1], append([]string{fill}, top[1:]...)...)
+		} else {
+			top = append(top, fill)
+		}
+		top = append(top, help)
+		return s.common.Renderer.NewStyle().MaxWidth(s.common.Width).
+			Render(
+				joinPath(path,
+					lipgloss.JoinHorizontal(lipgloss.Top, top...),
+					s.join(bottom, s.common.Width),
+				),
+			)
+	}
 
+	if !slices.Contains(layout, "value") {
+		// Keep the help on the right.
+		layout = append(layout, "value")
+	}
 	return s.common.Renderer.NewStyle().MaxWidth(s.common.Width).
 		Render(
-			lipgloss.JoinHorizontal(lipgloss.Top,
-				key,
-				value,
-				info,
-				branch,
-				help,
+			joinPath(path,
+				lipgloss.JoinHorizontal(lipgloss.Top,
+					s.join(layout, s.common.Width-w(help)),
+					help,
+				),
 			),
 		)
 }
+
+// pathView renders the whole path, wrapped to the width of the status bar.
+func (s *Model) pathView() string {
+	st := s.common.Styles.StatusBarValue
+	width := max(s.common.Width-st.GetHorizontalFrameSize(), 1)
+	return st.Width(s.common.Width).Render(wrap.String(s.value, width))
+}
+
+// joinPath stacks the expanded path, if any, above the status bar lines.
+func joinPath(path string, lines ...string) string {
+	if path != "" {
+		lines = append([]string{path}, lines...)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// layout returns the names of the segments shown at the current width, in
+// order.
+func (s *Model) layout() []string {
+	segments := config.DefaultStatusBar
+	if cfg := s.common.Config(); cfg != nil && len(cfg.UI.StatusBar) > 0 {
+		segments = cfg.UI.StatusBar
+	}
+	names := make([]string, 0, len(segments))
+	for _, seg := range segments {
+		name, minWidth, err := config.ParseStatusBarSegment(seg)
+		if err != nil || s.common.Width < minWidth {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// join renders the given segments on a line of the given width. The value
+// takes the room the others leave and is truncated to fit.
+func (s *Model) join(names []string, width int) string {
+	st := s.common.Styles
+	parts := make([]string, len(names))
+	valueAt := -1
+	for i, name := range names {
+		if name == "value" {
+			valueAt = i
+			continue
+		}
+		parts[i] = s.segment(name)
+	}
+	if valueAt >= 0 {
+		maxWidth := max(width-joinedWidth(parts), 0)
+		vw := max(maxWidth-st.StatusBarValue.GetHorizontalFrameSize(), 0)
+		v := truncate.StringWithTail(s.value, uint(vw), "…")
+		if s.path {
+			v = common.TruncatePath(s.value, vw)
+		}
+		parts[valueAt] = st.StatusBarValue.
+			Width(maxWidth).
+			Render(v)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, parts...)
+}
+
+// segment renders a segment other than the value. Segments with nothing to
+// show are left out, except for the key and branch that keep their place.
+func (s *Model) segment(name string) string {
+	st := s.common.Styles
+	var text string
+	switch name {
+	case "key":
+		return st.StatusBarKey.Render(s.key)
+	case "branch":
+		return st.StatusBarBranch.Render(s.extra)
+	case "info":
+		text = s.info
+	case "time":
+		text = time.Now().Format("15:04")
+	default:
+		text = s.fields[name]
+	}
+	if text == "" {
+		return ""
+	}
+	return st.StatusBarInfo.Render(text)
+}
+
+// joinedWidth returns the width of the given segments side by side.
+func joinedWidth(parts []string) int {
+	width := 0
+	for _, p := range parts {
+		width += lipgloss.Width(p)
+	}
+	return width
+}
@@ -1,6 +1,7 @@
 package tabs
 
 import (
+	"fmt"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -18,12 +19,16 @@ type ActiveTabMsg int
 type Tabs struct {
 	common       common.Common
 	tabs         []string
+	hidden       map[int]bool
 	activeTab    int
 	TabSeparator lipgloss.Style
 	TabInactive  lipgloss.Style
 	TabActive    lipgloss.Style
 	TabDot       lipgloss.Style
 	UseDot       bool
+	// Compact shows the active tab and its position instead of all the
+	// tabs, for narrow terminals.
+	Compact bool
 }
 
 // New creates a new Tabs component.
@@ -31,6 +36,7 @@ func New(c common.Common, tabs []string) *Tabs {
 	r := &Tabs{
 		common:       c,
 		tabs:         tabs,
+		hidden:       make(map[int]bool),
 		activeTab:    0,
 		TabSeparator: c.Styles.TabSeparator,
 		TabInactive:  c.Styles.TabInactive,
@@ -44,12 +50,44 @@ func (t *Tabs) SetSize(width, height int) {
 	t.common.SetSize(width, height)
 }
 
-// Init implements tea.Model.
+// SetHidden hides or shows a tab. Hidden tabs aren't shown, and are skipped
+// when switching tabs.
+func (t *Tabs) SetHidden(tab int, hidden bool) {
+	t.hidden[tab] = hidden
+}
+
+// Hidden returns whether a tab is hidden.
+func (t *Tabs) Hidden(tab int) bool {
+	return t.hidden[tab]
+}
+
+// Active returns the index of the active tab.
+func (t *Tabs) Active() int {
+	return t.activeTab
+}
+
+// Init implements tea.Model. The first tab that isn't hidden is active.
 func (t *Tabs) Init() tea.Cmd {
 	t.activeTab = 0
+	if t.hidden[0] {
+		t.activeTab = t.step(1)
+	}
 	return nil
 }
 
+// step returns the tab n tabs away from the active tab, skipping the hidden
+// tabs. The active tab is returned when all the others are hidden.
+func (t *Tabs) step(n int) int {
+	tab := t.activeTab
+	for range t.tabs {
+		tab = (tab + n + len(t.tabs)) % len(t.tabs)
+		if !t.hidden[tab] {
+			return tab
+		}
+	}
+	return t.activeTab
+}
+
 // Update implements tea.Model.
 func (t *Tabs) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	cmds := make([]tea.Cmd, 0)
@@ -57,11 +95,11 @@ func (t *Tabs) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "tab":
-			t.activeTab = (t.activeTab + 1) % len(t.tabs)
-			cmds = append(cmds, t.activeTabCmd)
+			t.activeTab = t.step(1)
+			cmds = append(cmds, t.activeTabCmd())
 		case "shift+tab":
-			t.activeTab = (t.activeTab - 1 + len(t.tabs)) % len(t.tabs)
-			cmds = append(cmds, t.activeTabCmd)
+			t.activeTab = t.step(-1)
+			cmds = append(cmds, t.activeTabCmd())
 		}
 	case tea.MouseMsg:
 		if msg.Action != tea.MouseActionPress {
@@ -69,16 +107,24 @@ func (t *Tabs) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		switch msg.Button {
 		case tea.MouseButtonLeft:
+			switch {
+			case t.common.Zone.Get("tabs-prev").InBounds(msg):
+				t.activeTab = t.step(-1)
+				cmds = append(cmds, t.activeTabCmd())
+			case t.common.Zone.Get("tabs-next").InBounds(msg):
+				t.activeTab = t.step(1)
+				cmds = append(cmds, t.activeTabCmd())
+			}
 			for i, tab := range t.tabs {
-				if t.common.Zone.Get(tab).InBounds(msg) {
+				if !t.hidden[i] && t.common.Zone.Get(tab).InBounds(msg) {
 					t.activeTab = i
-					cmds = append(cmds, t.activeTabCmd)
+					cmds = append(cmds, t.activeTabCmd())
 				}
 			}
 		}
 	case SelectTabMsg:
 		tab := int(msg)
-		if tab >= 0 && tab < len(t.tabs) {
+		if tab >= 0 && tab < len(t.tabs) && !t.hidden[tab] {
 			t.activeTab = int(msg)
 		}
 	}
@@ -87,9 +133,20 @@ func (t *Tabs) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View implements tea.Model.
 func (t *Tabs) View() string {
+	if t.Compact {
+		return t.compactView()
+	}
 	s := strings.Builder{}
 	sep := t.TabSeparator
+	first := true
 	for i, tab := range t.tabs {
+		if t.hidden[i] {
+			continue
+		}
+		if !first {
+			s.WriteString(sep.String())
+		}
+		first = false
 		style := t.TabInactive
 		prefix := "  "
 		if i == t.activeTab {
@@ -105,17 +162,47 @@ func (t *Tabs) View() string {
 				style.Render(tab),
 			),
 		)
-		if i != len(t.tabs)-1 {
-			s.WriteString(sep.String())
-		}
 	}
 	return t.common.Renderer.NewStyle().
 		MaxWidth(t.common.Width).
 		Render(s.String())
 }
 
-func (t *Tabs) activeTabCmd() tea.Msg {
-	return ActiveTabMsg(t.activeTab)
+// compactView renders the active tab between arrows to switch tabs, followed
+// by its position, e.g. "‹ Files › 2/6".
+func (t *Tabs) compactView() string {
+	if len(t.tabs) == 0 {
+		return ""
+	}
+	tab := t.tabs[t.activeTab]
+	var pos, total int
+	for i := range t.tabs {
+		if t.hidden[i] {
+			continue
+		}
+		total++
+		if i <= t.activeTab {
+			pos++
+		}
+	}
+	s := strings.Join([]string{
+		t.common.Zone.Mark("tabs-prev", t.TabInactive.Render("‹")),
+		t.common.Zone.Mark(tab, t.TabActive.Render(tab)),
+		t.common.Zone.Mark("tabs-next", t.TabInactive.Render("›")),
+		t.TabInactive.Faint(true).Render(fmt.Sprintf("%d/%d", pos, total)),
+	}, " ")
+	return t.common.Renderer.NewStyle().
+		MaxWidth(t.common.Width).
+		Render(s)
+}
+
+// activeTabCmd returns a command that reports the active tab. The tab is read
+// right away since commands run in their own goroutine.
+func (t *Tabs) activeTabCmd() tea.Cmd {
+	tab := t.activeTab
+	return func() tea.Msg {
+		return ActiveTabMsg(tab)
+	}
 }
 
 // SelectTabCmd is a bubbletea command that selects the tab at the given index.
@@ -1,16 +1,27 @@
 package viewport
 
 import (
+	"strings"
+
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // Viewport represents a viewport component.
 type Viewport struct {
 	common common.Common
 	*viewport.Model
+
+	// lines are the lines of the content. selecting is true while a range of
+	// lines is selected from anchor to cursor.
+	lines     []string
+	selecting bool
+	anchor    int
+	cursor    int
 }
 
 // New returns a new Viewport.
@@ -40,6 +51,27 @@ func (v *Viewport) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
+		case key.Matches(msg, v.common.KeyMap.SelectLines):
+			if v.selecting {
+				v.ClearSelection()
+			} else {
+				v.startSelection()
+			}
+			return v, nil
+		case key.Matches(msg, v.common.KeyMap.SelectUp):
+			v.startSelection()
+			v.moveCursor(-1)
+			return v, nil
+		case key.Matches(msg, v.common.KeyMap.SelectDown):
+			v.startSelection()
+			v.moveCursor(1)
+			return v, nil
+		case v.selecting && key.Matches(msg, v.common.KeyMap.Up):
+			v.moveCursor(-1)
+			return v, nil
+		case v.selecting && key.Matches(msg, v.common.KeyMap.Down):
+			v.moveCursor(1)
+			return v, nil
 		case key.Matches(msg, v.common.KeyMap.GotoTop):
 			v.GotoTop()
 		case key.Matches(msg, v.common.KeyMap.GotoBottom):
@@ -53,12 +85,121 @@ func (v *Viewport) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View implements tea.Model.
 func (v *Viewport) View() string {
-	return v.Model.View()
+	if !v.selecting || v.Model.HighPerformanceRendering {
+		return v.Model.View()
+	}
+
+	// Render the visible lines ourselves to highlight the selected ones. This
+	// mirrors viewport.Model.View.
+	start, end, _ := v.Selection()
+	top := max(0, v.YOffset)
+	bottom := min(top+v.Height, len(v.lines))
+	lines := make([]string, 0, bottom-top)
+	for i := top; i < bottom; i++ {
+		l := v.lines[i]
+		if i >= start && i <= end {
+			l = v.common.Styles.Code.Selection.
+				Width(v.Width).
+				Render(ansi.Strip(l))
+		}
+		lines = append(lines, l)
+	}
+
+	w := v.Width - v.Style.GetHorizontalFrameSize()
+	h := v.Height - v.Style.GetVerticalFrameSize()
+	contents := lipgloss.NewStyle().
+		Width(w).
+		Height(h).
+		MaxHeight(h).
+		MaxWidth(w).
+		Render(strings.Join(lines, "\n"))
+	return v.Style.Render(contents)
 }
 
-// SetContent sets the viewport's content.
+// SetContent sets the viewport's content. The selection is kept as long as
+// the selected lines still exist.
 func (v *Viewport) SetContent(content string) {
 	v.Model.SetContent(content)
+	v.lines = strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if v.selecting && max(v.anchor, v.cursor) >= len(v.lines) {
+		v.ClearSelection()
+	}
+}
+
+// Selection returns the first and last selected lines, zero based, and
+// whether there is a selection.
+func (v *Viewport) Selection() (start int, end int, ok bool) {
+	if !v.selecting {
+		return 0, 0, false
+	}
+	return min(v.anchor, v.cursor), max(v.anchor, v.cursor), true
+}
+
+// SelectedText returns the selected lines without styles.
+func (v *Viewport) SelectedText() string {
+	start, end, ok := v.Selection()
+	if !ok {
+		return ""
+	}
+	lines := make([]string, 0, end-start+1)
+	for _, l := range v.lines[start : end+1] {
+		lines = append(lines, strings.TrimRight(ansi.Strip(l), " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FindLine returns the first line from the given one, zero based, whose text
+// without styles matches, or -1 if there's none.
+func (v *Viewport) FindLine(from int, match func(string) bool) int {
+	for i := max(from, 0); i < len(v.lines); i++ {
+		if match(ansi.Strip(v.lines[i])) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Select selects the lines from start to end, zero based and clamped to the
+// content.
+func (v *Viewport) Select(start, end int) {
+	if len(v.lines) == 0 {
+		return
+	}
+	v.selecting = true
+	v.anchor = min(max(0, start), len(v.lines)-1)
+	v.cursor = min(max(v.anchor, end), len(v.lines)-1)
+}
+
+// ClearSelection clears the selected lines.
+func (v *Viewport) ClearSelection() {
+	v.selecting = false
+	v.anchor, v.cursor = 0, 0
+}
+
+// startSelection starts selecting from the first visible line unless there's
+// a selection already.
+func (v *Viewport) startSelection() {
+	if v.selecting || len(v.lines) == 0 {
+		return
+	}
+	v.selecting = true
+	v.anchor = min(max(0, v.YOffset), len(v.lines)-1)
+	v.cursor = v.anchor
+}
+
+// moveCursor moves the end of the selection by n lines and scrolls to keep it
+// visible.
+func (v *Viewport) moveCursor(n int) {
+	if !v.selecting {
+		return
+	}
+	v.cursor = min(max(0, v.cursor+n), len(v.lines)-1)
+	switch {
+	case v.cursor < v.YOffset:
+		v.SetYOffset(v.cursor)
+	case v.cursor >= v.YOffset+v.Height:
+		v.SetYOffset(v.cursor - v.Height + 1)
+	}
 }
 
 // GotoTop moves the viewport to the top of the log.
@@ -71,6 +212,12 @@ func (v *Viewport) GotoBottom() {
 	v.Model.GotoBottom()
 }
 
+// CenterLine scrolls the viewport so that the given line, zero based, is in
+// the middle of it, or as close as the content allows.
+func (v *Viewport) CenterLine(n int) {
+	v.SetYOffset(n - v.Height/2)
+}
+
 // HalfViewDown moves the viewport down by half the viewport height.
 func (v *Viewport) HalfViewDown() {
 	v.Model.HalfViewDown()
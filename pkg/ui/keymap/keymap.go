@@ -23,6 +23,18 @@ type KeyMap struct {
 	BackItem   key.Binding
 
 	Copy key.Binding
+
+	SelectLines key.Binding
+	SelectUp    key.Binding
+	SelectDown  key.Binding
+
+	RecentRepos key.Binding
+	Bookmarks   key.Binding
+
+	HistoryBack    key.Binding
+	HistoryForward key.Binding
+
+	CommandPalette key.Binding
 }
 
 // DefaultKeyMap returns the default key map.
@@ -226,5 +238,89 @@ func DefaultKeyMap() *KeyMap {
 		),
 	)
 
+	km.SelectLines = key.NewBinding(
+		key.WithKeys(
+			"v",
+		),
+		key.WithHelp(
+			"v",
+			"select lines",
+		),
+	)
+
+	km.SelectUp = key.NewBinding(
+		key.WithKeys(
+			"shift+up",
+			"K",
+		),
+		key.WithHelp(
+			"shift+↑",
+			"extend selection up",
+		),
+	)
+
+	km.SelectDown = key.NewBinding(
+		key.WithKeys(
+			"shift+down",
+			"J",
+		),
+		key.WithHelp(
+			"shift+↓",
+			"extend selection down",
+		),
+	)
+
+	km.RecentRepos = key.NewBinding(
+		key.WithKeys(
+			"ctrl+r",
+		),
+		key.WithHelp(
+			"ctrl+r",
+			"recent repos",
+		),
+	)
+
+	km.Bookmarks = key.NewBinding(
+		key.WithKeys(
+			"ctrl+b",
+		),
+		key.WithHelp(
+			"ctrl+b",
+			"bookmarks",
+		),
+	)
+
+	km.HistoryBack = key.NewBinding(
+		key.WithKeys(
+			"ctrl+o",
+			"alt+left",
+		),
+		key.WithHelp(
+			"ctrl+o",
+			"history back",
+		),
+	)
+
+	km.HistoryForward = key.NewBinding(
+		key.WithKeys(
+			"ctrl+n",
+			"alt+right",
+		),
+		key.WithHelp(
+			"ctrl+n",
+			"history forward",
+		),
+	)
+
+	km.CommandPalette = key.NewBinding(
+		key.WithKeys(
+			"ctrl+k",
+		),
+		key.WithHelp(
+			"ctrl+k",
+			"commands",
+		),
+	)
+
 	return km
 }
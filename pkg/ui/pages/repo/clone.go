@@ -0,0 +1,103 @@
+package repo
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+)
+
+var showCloneInstructions = key.NewBinding(
+	key.WithKeys("i"),
+	key.WithHelp("i", "clone instructions"),
+)
+
+// cloneInstructions is the view of the clone command and the clone
+// instructions of a repository, shown in the place of the tabs.
+type cloneInstructions struct {
+	// text is the Markdown of the instructions, empty when the repository
+	// has none.
+	text string
+	// show is true while the view is open.
+	show bool
+
+	// rendered is the last render of the instructions, for width.
+	rendered string
+	width    int
+}
+
+// cloneInstructionsText returns the clone instructions of the selected
+// repository.
+func (r *Repo) cloneInstructionsText() string {
+	be := r.common.Backend()
+	if be == nil || r.selectedRepo == nil {
+		return ""
+	}
+	text, _, err := be.CloneInstructions(r.common.Context(), r.selectedRepo.Name())
+	if err != nil {
+		r.common.Logger.Debugf("ui: failed to get clone instructions: %v", err)
+		return ""
+	}
+	return text
+}
+
+// updateCloneInstructions handles the keys of the clone instructions view. The
+// instructions and the clone command can be copied.
+func (r *Repo) updateCloneInstructions(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, r.common.KeyMap.Back), key.Matches(msg, showCloneInstructions):
+		r.clone.show = false
+	case key.Matches(msg, r.common.KeyMap.Copy):
+		return copyCmd(r.clone.text, "Clone instructions copied to clipboard")
+	case key.Matches(msg, copyURL):
+		return r.copyURLCmd()
+	}
+	return nil
+}
+
+// cloneInstructionsHelp returns the keys of the clone instructions view.
+func (r *Repo) cloneInstructionsHelp() []key.Binding {
+	back := r.common.KeyMap.Back
+	back.SetHelp("esc", "back")
+	cp := r.common.KeyMap.Copy
+	cp.SetHelp("c", "copy instructions")
+	return []key.Binding{back, cp, copyURL}
+}
+
+// cloneInstructionsView returns the clone command of the selected repository
+// followed by its clone instructions rendered as Markdown.
+func (r *Repo) cloneInstructionsView() string {
+	var url string
+	if cfg := r.common.Config(); cfg != nil {
+		url = r.common.CloneCmd(cfg, r.selectedRepo.Name())
+	}
+	title := r.common.Styles.Log.CommitHash.Render(
+		common.TruncateString("Clone "+r.selectedRepo.Name(), r.common.Width))
+	url = r.common.Styles.URLStyle.MarginLeft(0).Render(common.TruncateString(url, r.common.Width))
+
+	width := r.common.Width - r.common.Styles.Repo.Body.GetHorizontalFrameSize()
+	if r.clone.rendered == "" || r.clone.width != width {
+		r.clone.rendered = r.renderCloneInstructions(width)
+		r.clone.width = width
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, title, "", url, r.clone.rendered)
+}
+
+// renderCloneInstructions renders the clone instructions as Markdown, or as
+// they are if they can't be rendered.
+func (r *Repo) renderCloneInstructions(width int) string {
+	width = min(max(width, 20), 120)
+	tr, err := glamour.NewTermRenderer(
+		glamour.WithStyles(common.StyleConfig()),
+		glamour.WithWordWrap(width),
+	)
+	if err == nil {
+		if s, err := tr.Render(r.clone.text); err == nil {
+			return strings.TrimRight(s, "\n")
+		}
+	}
+	return "\n" + r.clone.text
+}
@@ -0,0 +1,139 @@
+package repo
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+)
+
+var showDeps = key.NewBinding(
+	key.WithKeys("D"),
+	key.WithHelp("D", "submodule graph"),
+)
+
+// DepsMsg is a message that contains the submodule graph of the repositories
+// hosted on the server, laid out as a tree.
+type DepsMsg []backend.SubmoduleNode
+
+// depsInfo is the submodule graph of the repositories the user can read. It's
+// shown in the place of the tabs.
+type depsInfo struct {
+	nodes []backend.SubmoduleNode
+	// loading is true until the graph is read.
+	loading bool
+	// show is true while the view is open.
+	show   bool
+	cursor int
+}
+
+// depsCmd reads the submodule graph in the background, reading the
+// submodules of every repository can take a while.
+func (r *Repo) depsCmd() tea.Cmd {
+	be := r.common.Backend()
+	if be == nil {
+		return nil
+	}
+	ctx := r.common.Context()
+	user := r.common.User()
+	return func() tea.Msg {
+		graph, err := be.SubmoduleGraph(ctx, user)
+		if err != nil {
+			r.common.Logger.Debugf("ui: failed to read submodule graph: %v", err)
+			return common.ErrorMsg(err)
+		}
+		return DepsMsg(backend.SubmoduleTree(graph))
+	}
+}
+
+// setDeps shows the submodule graph with the cursor on the selected
+// repository, if it's in the graph.
+func (r *Repo) setDeps(nodes []backend.SubmoduleNode) {
+	r.deps.nodes, r.deps.loading, r.deps.cursor = nodes, false, 0
+	for i, n := range nodes {
+		if n.Repo == r.selectedRepo.Name() {
+			r.deps.cursor = i
+			break
+		}
+	}
+}
+
+// updateDeps handles the keys of the submodule graph view. Selecting a
+// repository opens it.
+func (r *Repo) updateDeps(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, r.common.KeyMap.Back), key.Matches(msg, showDeps):
+		r.deps.show = false
+	case r.deps.loading:
+	case key.Matches(msg, r.common.KeyMap.Up):
+		if r.deps.cursor > 0 {
+			r.deps.cursor--
+		}
+	case key.Matches(msg, r.common.KeyMap.Down):
+		if r.deps.cursor < len(r.deps.nodes)-1 {
+			r.deps.cursor++
+		}
+	case key.Matches(msg, r.common.KeyMap.Select) && len(r.deps.nodes) > 0:
+		r.deps.show = false
+		name := r.deps.nodes[r.deps.cursor].Repo
+		if name == r.selectedRepo.Name() {
+			return nil
+		}
+		return func() tea.Msg {
+			return OpenRepoMsg{Repo: name}
+		}
+	}
+	return nil
+}
+
+// depsHelp returns the keys of the submodule graph view.
+func (r *Repo) depsHelp() []key.Binding {
+	back := r.common.KeyMap.Back
+	back.SetHelp("esc", "back")
+	sel := r.common.KeyMap.Select
+	sel.SetHelp("enter", "open")
+	return []key.Binding{back, r.common.KeyMap.UpDown, sel}
+}
+
+// depsView returns the tree of the repositories linked by submodules, each
+// one under the repositories that have it as a submodule.
+func (r *Repo) depsView() string {
+	st := r.common.Styles.RepoSelector
+	width := r.common.Width - r.common.Styles.Repo.Body.GetHorizontalFrameSize()
+	var sb strings.Builder
+	sb.WriteString(r.common.Styles.Log.CommitHash.Render(
+		common.TruncateString("Submodule graph", width)))
+	sb.WriteString("\n")
+	switch {
+	case r.deps.loading:
+		sb.WriteString("\n")
+		sb.WriteString(st.Normal.Desc.Render("  Loading…"))
+		return sb.String()
+	case len(r.deps.nodes) == 0:
+		sb.WriteString("\n")
+		sb.WriteString(st.Normal.Desc.Render("  No repository has a submodule hosted on this server"))
+		return sb.String()
+	}
+	for i, n := range r.deps.nodes {
+		line := n.Repo
+		if n.Depth > 0 {
+			line = strings.Repeat("  ", n.Depth-1) + "↳ " + line
+		}
+		if n.Cycle {
+			line += " (cycle)"
+		}
+		if n.Repo == r.selectedRepo.Name() {
+			line += " (current)"
+		}
+		line = common.TruncateString(line, width-2)
+		sb.WriteString("\n")
+		if i == r.deps.cursor {
+			sb.WriteString(st.Active.Title.Render("> " + line))
+		} else {
+			sb.WriteString(st.Normal.Desc.Render("  " + line))
+		}
+	}
+	return sb.String()
+}
@@ -1,20 +1,28 @@
 package repo
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	gitm "github.com/aymanbagabas/git-module"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/lfs"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
 	"github.com/charmbracelet/soft-serve/pkg/ui/components/code"
 	"github.com/charmbracelet/soft-serve/pkg/ui/components/selector"
+	"github.com/dustin/go-humanize"
 )
 
 type filesView int
@@ -23,12 +31,18 @@ const (
 	filesViewLoading filesView = iota
 	filesViewFiles
 	filesViewContent
+	filesViewChangeRefs
+	filesViewChanges
+	filesViewChangeDiff
+	filesViewMarkers
 )
 
 var (
 	errNoFileSelected = errors.New("no file selected")
 	errBinaryFile     = errors.New("binary file")
 	errInvalidFile    = errors.New("invalid file")
+	errSubmodule      = errors.New("submodule")
+	errBlameTimeout   = errors.New("blame took too long and was canceled")
 )
 
 var (
@@ -40,24 +54,123 @@ var (
 		key.WithKeys("b"),
 		key.WithHelp("b", "toggle blame view"),
 	)
+	blameHeatmap = key.NewBinding(
+		key.WithKeys("H"),
+		key.WithHelp("H", "toggle heatmap"),
+	)
 	preview = key.NewBinding(
 		key.WithKeys("p"),
 		key.WithHelp("p", "toggle preview"),
 	)
+	treeView = key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "toggle tree view"),
+	)
+	copyPath = key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy path"),
+	)
+	copyPermalink = key.NewBinding(
+		key.WithKeys("Y"),
+		key.WithHelp("Y", "copy permalink"),
+	)
+	showCommit = key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "show commit"),
+	)
+	showVendored = key.NewBinding(
+		key.WithKeys("."),
+		key.WithHelp(".", "toggle vendored files"),
+	)
+	hexDump = key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "toggle hex view"),
+	)
+	wrapLines = key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "toggle wrap"),
+	)
+	fullPath = key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "toggle full path"),
+	)
 )
 
-// FileItemsMsg is a message that contains a list of files.
-type FileItemsMsg []selector.IdentifiableItem
+// FileItemsMsg is a message that contains a page of files of a directory.
+type FileItemsMsg struct {
+	path    string
+	entries git.Entries
+	start   int
+	end     int
+	hidden  int
+}
+
+// FileTreeMsg is a message that contains the visible items of a directory in
+// tree view.
+type FileTreeMsg struct {
+	path   string
+	items  []selector.IdentifiableItem
+	hidden int
+}
 
 // FileContentMsg is a message that contains the content of a file.
 type FileContentMsg struct {
-	content string
-	ext     string
+	content    string
+	ext        string
+	language   string
+	encoding   string
+	lineEnding string
+
+	// binary is the file when it's binary, its content isn't read.
+	binary *git.File
+
+	// pointer is the LFS pointer the file is, if any. The content is the
+	// pointer itself.
+	pointer *lfs.Pointer
 }
 
 // FileBlameMsg is a message that contains the blame of a file.
 type FileBlameMsg *gitm.Blame
 
+// FileJumpMsg is a message to show a file at another revision, e.g. the blame
+// of the file at the parent of a commit, scrolled to the given line.
+type FileJumpMsg struct {
+	rev   string
+	path  string
+	line  int
+	blame bool
+}
+
+// fileJumpResultMsg is a message that contains the file of a jump along with
+// its content and blame.
+type fileJumpResultMsg struct {
+	ref     *git.Reference
+	entry   *git.TreeEntry
+	content FileContentMsg
+	blame   FileBlameMsg
+	line    int
+	err     error
+}
+
+// filesJump is the state of the files view before jumping to the blame of a
+// file from another tab.
+type filesJump struct {
+	view         filesView
+	ref          *git.Reference
+	path         string
+	item         *FileItem
+	content      FileContentMsg
+	blame        FileBlameMsg
+	blameView    bool
+	useGlamour   bool
+	raw          bool
+	treeFile     bool
+	treeRoot     string
+	lastSelected []int
+	cursor       int
+	yOffset      int
+}
+
 // Files is the model for the files view.
 type Files struct {
 	common         common.Common
@@ -75,6 +188,80 @@ type Files struct {
 	spinner        spinner.Model
 	cursor         int
 	blameView      bool
+
+	// heatmap shows the age of the last change of the lines in the blame
+	// view instead of their commits.
+	heatmap bool
+
+	// wrapToggled is true once the user turned wrapping on or off, the view
+	// settings of the repositories don't change it anymore.
+	wrapToggled bool
+
+	// fullPath shows the whole path above the status bar, long paths are
+	// truncated in the middle otherwise.
+	fullPath bool
+
+	// blameCancel cancels the blame that is being loaded, if any.
+	blameCancel context.CancelFunc
+
+	// trees caches the sorted entries of the directories that were visited
+	// in this session keyed by path, and items holds the file items that
+	// were loaded so far. Unloaded items are nil.
+	treesMtx    sync.Mutex
+	trees       map[string]git.Entries
+	items       map[string][]selector.IdentifiableItem
+	loadingPage bool
+
+	// vendored holds the paths that are marked as vendored or generated in
+	// the .gitattributes of the current ref. They're hidden from the list
+	// unless showVendored is set, and hidden is the number of entries of the
+	// current directory that are hidden.
+	vendored     map[string]bool
+	showVendored bool
+	hidden       int
+
+	// pointers holds the files of the current ref that are LFS pointers
+	// keyed by path. They're looked up as the entries are loaded.
+	pointers map[string]lfs.Pointer
+
+	// submodules holds the submodules of the current ref keyed by path.
+	// They're loaded the first time a directory with a submodule is shown.
+	submodules map[string]proto.Submodule
+
+	// treeMode shows directories as a tree that can be expanded in place.
+	// expanded holds the expanded directories of the current ref keyed by
+	// path, and treeRoot is the directory the tree is rooted at while a file
+	// opened from the tree is shown.
+	treeMode bool
+	treeFile bool
+	treeRoot string
+	expanded map[string]bool
+
+	// jumps holds the states to go back to after jumping to the blame of
+	// files from other tabs.
+	jumps []filesJump
+
+	// changeRefs lists the references to compare the files with, changes
+	// lists the files that changed in the current reference since it forked
+	// from changesBase at the changesSince commit, and changeDiff shows the
+	// diff of one of them.
+	changeRefs   *selector.Selector
+	changes      *selector.Selector
+	changeDiff   *code.Code
+	changesBase  string
+	changesSince string
+
+	// markers lists the lines of the current file that match markerPatterns,
+	// like TODO comments, and markerCount is their number.
+	markers        *selector.Selector
+	markerPatterns []*regexp.Regexp
+	markerCount    int
+
+	// conflicts are the merge conflicts of the current file, and conflict
+	// the index of the one the conflict keys moved to, -1 before they're
+	// used.
+	conflicts []code.Conflict
+	conflict  int
 }
 
 // NewFiles creates a new files model.
@@ -85,8 +272,13 @@ func NewFiles(common common.Common) *Files {
 		activeView:   filesViewLoading,
 		lastSelected: make([]int, 0),
 		lineNumber:   true,
+		trees:        make(map[string]git.Entries),
+		items:        make(map[string][]selector.IdentifiableItem),
+		expanded:     make(map[string]bool),
+		vendored:     make(map[string]bool),
+		pointers:     make(map[string]lfs.Pointer),
 	}
-	selector := selector.New(common, []selector.IdentifiableItem{}, FileItemDelegate{&common})
+	selector := selector.New(common, []selector.IdentifiableItem{}, FileItemDelegate{common: &common})
 	selector.SetShowFilter(false)
 	selector.SetShowHelp(false)
 	selector.SetShowPagination(false)
@@ -96,7 +288,16 @@ func NewFiles(common common.Common) *Files {
 	selector.DisableQuitKeybindings()
 	selector.KeyMap.NextPage = common.KeyMap.NextPage
 	selector.KeyMap.PrevPage = common.KeyMap.PrevPage
+	if cfg := common.Config(); cfg != nil {
+		selector.SetEmptyMessage(cfg.UI.Empty.Files)
+	}
 	f.selector = selector
+	f.changeRefs = newListSelector(common, RefItemDelegate{&common})
+	f.changes = newListSelector(common, ChangedFileItemDelegate{common: &common})
+	f.changes.SetEmptyMessage("No changed files.")
+	f.changeDiff = code.New(common, "", "")
+	f.markers = newListSelector(common, MarkerItemDelegate{&common})
+	f.markerPatterns = markerPatterns(common.Config())
 	f.code.ShowLineNumber = f.lineNumber
 	s := spinner.New(spinner.WithSpinner(spinner.Dot),
 		spinner.WithStyle(common.Styles.Spinner))
@@ -106,6 +307,9 @@ func NewFiles(common common.Common) *Files {
 
 // Path implements common.TabComponent.
 func (f *Files) Path() string {
+	if f.inChanges() {
+		return "changes" // XXX: this is a place holder and doesn't mean anything
+	}
 	path := f.path
 	if path == "." {
 		return ""
@@ -123,6 +327,10 @@ func (f *Files) SetSize(width, height int) {
 	f.common.SetSize(width, height)
 	f.selector.SetSize(width, height)
 	f.code.SetSize(width, height)
+	f.changeRefs.SetSize(width, height-2)
+	f.changes.SetSize(width, height-2)
+	f.changeDiff.SetSize(width, height)
+	f.markers.SetSize(width, height-2)
 }
 
 // ShortHelp implements help.KeyMap.
@@ -136,10 +344,51 @@ func (f *Files) ShortHelp() []key.Binding {
 			k.CursorUp,
 			k.CursorDown,
 		}
+	case filesViewChangeRefs, filesViewChanges, filesViewMarkers:
+		return []key.Binding{
+			f.common.KeyMap.UpDown,
+			f.common.KeyMap.SelectItem,
+			f.common.KeyMap.BackItem,
+		}
+	case filesViewChangeDiff:
+		return []key.Binding{
+			f.common.KeyMap.UpDown,
+			f.common.KeyMap.BackItem,
+		}
 	case filesViewContent:
+		if f.currentContent.binary != nil {
+			if f.code.IsHex() {
+				return []key.Binding{
+					f.common.KeyMap.UpDown,
+					f.common.KeyMap.BackItem,
+					hexDump,
+				}
+			}
+			return []key.Binding{
+				f.common.KeyMap.BackItem,
+				hexDump,
+			}
+		}
+		if _, _, ok := f.code.Selection(); ok {
+			copyKey := f.common.KeyMap.Copy
+			copyKey.SetHelp("c", "copy lines")
+			return []key.Binding{
+				f.common.KeyMap.UpDown,
+				copyKey,
+				copyPermalink,
+				f.common.KeyMap.Back,
+			}
+		}
 		b := []key.Binding{
 			f.common.KeyMap.UpDown,
 			f.common.KeyMap.BackItem,
+			f.common.KeyMap.SelectLines,
+		}
+		if f.blameView {
+			b = append(b, showCommit, blameHeatmap)
+		}
+		if f.hasConflicts() {
+			b = append(b, conflictKeys)
 		}
 		return b
 	default:
@@ -150,18 +399,91 @@ func (f *Files) ShortHelp() []key.Binding {
 // FullHelp implements help.KeyMap.
 func (f *Files) FullHelp() [][]key.Binding {
 	b := make([][]key.Binding, 0)
+	switch f.activeView {
+	case filesViewChangeRefs, filesViewChanges, filesViewMarkers:
+		k := f.changes.KeyMap
+		return [][]key.Binding{
+			{
+				f.common.KeyMap.SelectItem,
+				f.common.KeyMap.BackItem,
+			},
+			{
+				k.CursorUp,
+				k.CursorDown,
+				k.NextPage,
+				k.PrevPage,
+			},
+		}
+	case filesViewChangeDiff:
+		k := f.changeDiff.KeyMap
+		return [][]key.Binding{
+			{
+				f.common.KeyMap.BackItem,
+			},
+			{
+				k.PageDown,
+				k.PageUp,
+				k.HalfPageDown,
+				k.HalfPageUp,
+			},
+			{
+				k.Down,
+				k.Up,
+				f.common.KeyMap.GotoTop,
+				f.common.KeyMap.GotoBottom,
+			},
+		}
+	case filesViewContent:
+		if f.currentContent.binary == nil {
+			break
+		}
+		h := [][]key.Binding{
+			{
+				f.common.KeyMap.BackItem,
+				hexDump,
+			},
+		}
+		if f.code.IsHex() {
+			k := f.code.KeyMap
+			h = append(h, [][]key.Binding{
+				{
+					k.PageDown,
+					k.PageUp,
+					k.HalfPageDown,
+					k.HalfPageUp,
+				},
+				{
+					k.Down,
+					k.Up,
+					f.common.KeyMap.GotoTop,
+					f.common.KeyMap.GotoBottom,
+				},
+			}...)
+		}
+		return append(h, []key.Binding{copyPath, copyPermalink, toggleBookmark, fullPath})
+	}
 	copyKey := f.common.KeyMap.Copy
 	actionKeys := []key.Binding{
 		copyKey,
 	}
-	if !f.code.UseGlamour {
-		actionKeys = append(actionKeys, lineNo)
+	if !f.isPreview() {
+		actionKeys = append(actionKeys, lineNo, wrapLines)
 	}
+	actionKeys = append(actionKeys, rawView)
 	actionKeys = append(actionKeys, blameView)
+	if f.blameView {
+		actionKeys = append(actionKeys, showCommit, blameHeatmap)
+	}
 	if common.IsFileMarkdown(f.currentContent.content, f.currentContent.ext) &&
-		!f.blameView {
+		!f.blameView && !f.code.Raw {
 		actionKeys = append(actionKeys, preview)
 	}
+	if f.activeView == filesViewContent && f.markerCount > 0 && !f.isPreview() {
+		actionKeys = append(actionKeys, showMarkers)
+	}
+	if f.activeView == filesViewContent && f.hasConflicts() {
+		actionKeys = append(actionKeys, nextConflict, prevConflict)
+	}
 	switch f.activeView {
 	case filesViewFiles:
 		copyKey.SetHelp("c", "copy name")
@@ -181,9 +503,16 @@ func (f *Files) FullHelp() [][]key.Binding {
 				k.GoToStart,
 				k.GoToEnd,
 			},
+			{
+				treeView,
+				showVendored,
+				changedFiles,
+				fullPath,
+			},
 		}...)
 	case filesViewContent:
 		copyKey.SetHelp("c", "copy content")
+		actionKeys = append(actionKeys, copyPath, copyPermalink, toggleBookmark, fullPath)
 		k := f.code.KeyMap
 		b = append(b, []key.Binding{
 			f.common.KeyMap.BackItem,
@@ -201,6 +530,11 @@ func (f *Files) FullHelp() [][]key.Binding {
 				f.common.KeyMap.GotoTop,
 				f.common.KeyMap.GotoBottom,
 			},
+			{
+				f.common.KeyMap.SelectLines,
+				f.common.KeyMap.SelectUp,
+				f.common.KeyMap.SelectDown,
+			},
 		}...)
 	}
 	return append(b, actionKeys)
@@ -210,11 +544,16 @@ func (f *Files) FullHelp() [][]key.Binding {
 func (f *Files) Init() tea.Cmd {
 	f.path = ""
 	f.currentItem = nil
+	f.treeFile = false
 	f.activeView = filesViewLoading
 	f.lastSelected = make([]int, 0)
 	f.blameView = false
 	f.currentBlame = nil
+	f.jumps = nil
 	f.code.UseGlamour = false
+	f.code.Raw = false
+	f.markerCount = 0
+	f.conflicts = nil
 	return tea.Batch(f.spinner.Tick, f.updateFilesCmd)
 }
 
@@ -224,35 +563,233 @@ func (f *Files) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case RepoMsg:
 		f.repo = msg
+		f.resetTrees()
+		vs := loadViewSettings(f.common, msg)
+		if !f.wrapToggled {
+			f.code.NoWrap = vs.Wrap != nil && !*vs.Wrap
+		}
+		f.code.Theme = vs.Theme
+		f.selector.SetDelegate(FileItemDelegate{
+			common: &f.common,
+			icons:  f.loadFileIcons(),
+		})
 	case RefMsg:
 		f.ref = msg
+		f.resetTrees()
 		f.selector.Select(0)
 		cmds = append(cmds, f.Init())
+	case FileTreeMsg:
+		if msg.path != f.path || !f.treeMode {
+			break
+		}
+		f.hidden = msg.hidden
+		cmds = append(cmds,
+			f.selector.SetItems(msg.items),
+		)
+		f.activeView = filesViewFiles
+		if f.cursor >= 0 {
+			f.selector.Select(f.cursor)
+			f.cursor = -1
+		}
 	case FileItemsMsg:
+		if msg.path != f.path || f.treeMode {
+			// The user has moved on to another directory.
+			break
+		}
+		f.loadingPage = false
+		f.hidden = msg.hidden
+		items := f.items[msg.path]
+		if items == nil || len(items) != len(msg.entries) {
+			items = make([]selector.IdentifiableItem, len(msg.entries))
+		}
+		for i := msg.start; i < msg.end; i++ {
+			if items[i] == nil {
+				e := msg.entries[i]
+				p := filepath.Join(msg.path, e.Name())
+				items[i] = FileItem{
+					entry:     e,
+					vendored:  f.isVendored(p),
+					submodule: f.submodule(p),
+					pointer:   f.pointer(p),
+				}
+			}
+		}
+		f.items[msg.path] = items
+		page := f.selector.Page()
 		cmds = append(cmds,
-			f.selector.SetItems(msg),
+			f.selector.SetItems(items),
 		)
 		f.activeView = filesViewFiles
 		if f.cursor >= 0 {
 			f.selector.Select(f.cursor)
 			f.cursor = -1
+		} else {
+			f.selector.SetPage(page)
+		}
+	case selector.WindowMsg:
+		// Load the entries of the page on demand when the user scrolls to a
+		// page that hasn't been loaded yet.
+		if f.activeView == filesViewFiles && !f.isWindowLoaded(msg.Start, msg.End) {
+			f.loadingPage = true
+			cmds = append(cmds, f.loadPageCmd(msg.Start/max(f.selector.PerPage(), 1)))
 		}
 	case FileContentMsg:
 		f.activeView = filesViewContent
 		f.currentContent = msg
 		f.code.UseGlamour = common.IsFileMarkdown(f.currentContent.content, f.currentContent.ext)
-		cmds = append(cmds, f.code.SetContent(msg.content, msg.ext))
+		f.code.Language = msg.language
+		f.code.Raw = false
+		f.code.ClearSelection()
+		f.code.SetHex(nil)
+		cmds = append(cmds, f.code.SetContent(msg.content, msg.ext), f.setMarkers())
 		f.code.GotoTop()
 	case FileBlameMsg:
+		f.blameCancel = nil
+		if !f.blameView {
+			// The blame was canceled.
+			break
+		}
 		f.currentBlame = msg
 		f.activeView = filesViewContent
 		f.code.UseGlamour = false
-		f.code.SetSideNote(renderBlame(f.common, f.currentItem, msg))
+		f.code.SetSideNote(f.renderBlame(msg))
+	case FileJumpMsg:
+		f.jumps = append(f.jumps, filesJump{
+			view:         f.activeView,
+			ref:          f.ref,
+			path:         f.path,
+			item:         f.currentItem,
+			content:      f.currentContent,
+			blame:        f.currentBlame,
+			blameView:    f.blameView,
+			useGlamour:   f.code.UseGlamour,
+			raw:          f.code.Raw,
+			treeFile:     f.treeFile,
+			treeRoot:     f.treeRoot,
+			lastSelected: append([]int(nil), f.lastSelected...),
+			cursor:       f.selector.Index(),
+			yOffset:      f.code.YOffset,
+		})
+		f.activeView = filesViewLoading
+		cmds = append(cmds, f.spinner.Tick, f.jumpCmd(msg))
+	case fileJumpResultMsg:
+		f.blameCancel = nil
+		if msg.err != nil {
+			cmds = append(cmds, f.jumpBack())
+			if !errors.Is(msg.err, context.Canceled) {
+				cmds = append(cmds, statusCmd(msg.err.Error()))
+			}
+			break
+		}
+		if f.ref == nil || f.ref.ID != msg.ref.ID {
+			f.resetTrees()
+		}
+		f.ref = msg.ref
+		f.path = msg.entry.File().Path()
+		f.currentItem = &FileItem{entry: msg.entry}
+		f.treeFile = false
+		f.currentContent = msg.content
+		f.currentBlame = msg.blame
+		f.blameView = msg.blame != nil
+		f.activeView = filesViewContent
+		f.code.UseGlamour = !f.blameView && msg.line == 0 &&
+			common.IsFileMarkdown(msg.content.content, msg.content.ext)
+		f.code.Language = msg.content.language
+		f.code.Raw = false
+		f.code.ClearSelection()
+		f.code.SetHex(nil)
+		if f.blameView {
+			f.code.SetSideNote(f.renderBlame(msg.blame))
+		} else {
+			f.code.SetSideNote("")
+		}
+		cmds = append(cmds, f.code.SetContent(msg.content.content, msg.content.ext), f.setMarkers())
+		f.code.GotoLine(msg.line)
+	case FileOpenMsg:
+		f.activeView = filesViewLoading
+		cmds = append(cmds, f.spinner.Tick, f.openFileCmd(msg))
+	case fileOpenResultMsg:
+		cmds = append(cmds, f.openFile(msg))
+	case FileChangeRefsMsg:
+		if f.ref != nil && msg.head == f.ref.ID {
+			f.activeView = filesViewChangeRefs
+			f.changeRefs.Select(0)
+			cmds = append(cmds, f.changeRefs.SetItems(msg.items))
+		}
+	case FileChangesMsg:
+		if f.ref == nil || msg.head != f.ref.ID {
+			break
+		}
+		if msg.err != nil {
+			f.activeView = filesViewChangeRefs
+			cmds = append(cmds, statusCmd(msg.err.Error()))
+			break
+		}
+		f.activeView = filesViewChanges
+		f.changesBase = msg.base
+		f.changesSince = msg.since
+		f.changes.Select(0)
+		cmds = append(cmds, f.changes.SetItems(msg.items))
+		ignored := 0
+		for _, it := range msg.items {
+			if it, ok := it.(ChangedFileItem); ok && it.Ignored {
+				ignored++
+			}
+		}
+		switch ignored {
+		case 0:
+		case 1:
+			cmds = append(cmds, statusCmd("1 changed file matches .gitignore"))
+		default:
+			cmds = append(cmds, statusCmd(fmt.Sprintf("%d changed files match .gitignore", ignored)))
+		}
+	case FileChangeDiffMsg:
+		if f.ref == nil || msg.head != f.ref.ID {
+			break
+		}
+		if msg.err != nil {
+			f.activeView = filesViewChanges
+			cmds = append(cmds, statusCmd(msg.err.Error()))
+			break
+		}
+		f.activeView = filesViewChangeDiff
+		f.changeDiff.GotoTop()
+		cmds = append(cmds, f.changeDiff.SetContent(f.renderChangeDiff(msg), ".diff"))
 	case selector.SelectMsg:
 		switch sel := msg.IdentifiableItem.(type) {
+		case RefItem:
+			if f.activeView == filesViewChangeRefs {
+				f.activeView = filesViewLoading
+				cmds = append(cmds, f.spinner.Tick, f.changesCmd(sel.Reference))
+			}
+		case MarkerItem:
+			if f.activeView == filesViewMarkers {
+				f.activeView = filesViewContent
+				f.code.ClearSelection()
+				f.code.GotoLine(sel.Line)
+			}
+		case ChangedFileItem:
+			if f.activeView == filesViewChanges {
+				f.activeView = filesViewLoading
+				cmds = append(cmds, f.spinner.Tick, f.changeDiffCmd(sel.ChangedFile))
+			}
 		case FileItem:
+			if f.treeMode && sel.entry.IsTree() {
+				// Expand or collapse the directory in place.
+				p := filepath.Join(f.path, sel.path)
+				f.setExpanded(p, !f.isExpanded(p))
+				f.cursor = f.selector.Index()
+				cmds = append(cmds, f.updateFilesCmd)
+				break
+			}
 			f.currentItem = &sel
-			f.path = filepath.Join(f.path, sel.entry.Name())
+			if f.treeMode {
+				f.treeFile = true
+				f.treeRoot = f.path
+				f.path = filepath.Join(f.path, sel.path)
+			} else {
+				f.path = filepath.Join(f.path, sel.entry.Name())
+			}
 			if sel.entry.IsTree() {
 				cmds = append(cmds, f.selectTreeCmd)
 			} else {
@@ -261,41 +798,153 @@ func (f *Files) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case GoBackMsg:
 		switch f.activeView {
-		case filesViewFiles, filesViewContent:
+		case filesViewChangeRefs, filesViewChanges, filesViewChangeDiff:
+			f.goBackChanges()
+		case filesViewMarkers:
+			f.activeView = filesViewContent
+		case filesViewLoading:
+			if f.blameView {
+				f.cancelBlame()
+			}
+		case filesViewContent:
+			if _, _, ok := f.code.Selection(); ok {
+				f.code.ClearSelection()
+				break
+			}
+			if len(f.jumps) > 0 {
+				cmds = append(cmds, f.jumpBack())
+				break
+			}
+			fallthrough
+		case filesViewFiles:
 			cmds = append(cmds, f.deselectItemCmd())
 		}
 	case tea.KeyMsg:
+		if key.Matches(msg, fullPath) {
+			f.fullPath = !f.fullPath
+		}
 		switch f.activeView {
+		case filesViewLoading:
+			if f.blameView && key.Matches(msg, f.common.KeyMap.BackItem) {
+				f.cancelBlame()
+			}
 		case filesViewFiles:
 			switch {
 			case key.Matches(msg, f.common.KeyMap.SelectItem):
 				cmds = append(cmds, f.selector.SelectItemCmd)
 			case key.Matches(msg, f.common.KeyMap.BackItem):
-				cmds = append(cmds, f.deselectItemCmd())
+				if f.treeMode && f.collapseTree() {
+					cmds = append(cmds, f.updateFilesCmd)
+				} else {
+					cmds = append(cmds, f.deselectItemCmd())
+				}
+			case key.Matches(msg, treeView):
+				f.treeMode = !f.treeMode
+				f.cursor = 0
+				f.activeView = filesViewLoading
+				cmds = append(cmds, f.spinner.Tick, f.updateFilesCmd)
+			case key.Matches(msg, showVendored):
+				f.showVendored = !f.showVendored
+				f.cursor = 0
+				f.activeView = filesViewLoading
+				cmds = append(cmds, f.spinner.Tick, f.updateFilesCmd)
+			case key.Matches(msg, changedFiles) && f.ref != nil:
+				f.activeView = filesViewLoading
+				cmds = append(cmds, f.spinner.Tick, f.changeRefsCmd)
+			}
+		case filesViewChangeRefs, filesViewChanges:
+			switch {
+			case key.Matches(msg, f.common.KeyMap.SelectItem):
+				if f.activeView == filesViewChangeRefs {
+					cmds = append(cmds, f.changeRefs.SelectItemCmd)
+				} else {
+					cmds = append(cmds, f.changes.SelectItemCmd)
+				}
+			case key.Matches(msg, f.common.KeyMap.BackItem):
+				f.goBackChanges()
+				return f, tea.Batch(cmds...)
+			}
+		case filesViewChangeDiff:
+			if key.Matches(msg, f.common.KeyMap.BackItem) {
+				f.goBackChanges()
+				return f, tea.Batch(cmds...)
+			}
+		case filesViewMarkers:
+			switch {
+			case key.Matches(msg, f.common.KeyMap.SelectItem):
+				cmds = append(cmds, f.markers.SelectItemCmd)
+			case key.Matches(msg, f.common.KeyMap.BackItem), key.Matches(msg, showMarkers):
+				f.activeView = filesViewContent
+				return f, tea.Batch(cmds...)
 			}
 		case filesViewContent:
 			switch {
+			case key.Matches(msg, f.common.KeyMap.BackItem) && len(f.jumps) > 0:
+				cmds = append(cmds, f.jumpBack())
 			case key.Matches(msg, f.common.KeyMap.BackItem):
 				cmds = append(cmds, f.deselectItemCmd())
-			case key.Matches(msg, f.common.KeyMap.Copy):
-				cmds = append(cmds, copyCmd(f.currentContent.content, "File contents copied to clipboard"))
-			case key.Matches(msg, lineNo) && !f.code.UseGlamour:
+			case key.Matches(msg, showMarkers) && f.markerCount > 0 && !f.isPreview():
+				f.activeView = filesViewMarkers
+			case key.Matches(msg, nextConflict) && f.hasConflicts():
+				cmds = append(cmds, f.gotoConflict(1))
+			case key.Matches(msg, prevConflict) && f.hasConflicts():
+				cmds = append(cmds, f.gotoConflict(-1))
+			case key.Matches(msg, showCommit) && f.blameView && f.currentBlame != nil:
+				cmds = append(cmds, f.showCommitCmd())
+			case key.Matches(msg, blameHeatmap) && f.blameView && f.currentBlame != nil:
+				f.heatmap = !f.heatmap
+				cmds = append(cmds, f.code.SetSideNote(f.renderBlame(f.currentBlame)))
+			case key.Matches(msg, hexDump) && f.currentContent.binary != nil:
+				if f.code.IsHex() {
+					f.code.SetHex(nil)
+				} else {
+					f.code.SetHex(f.currentContent.binary)
+				}
+			case key.Matches(msg, f.common.KeyMap.Copy) && f.currentContent.binary == nil:
+				if _, _, ok := f.code.Selection(); ok {
+					msg := "Selected lines copied to clipboard"
+					if start, end, ok := f.code.SelectedLines(); ok {
+						msg = fmt.Sprintf("%s copied to clipboard", linesString(start, end))
+					}
+					cmds = append(cmds, copyCmd(f.code.SelectedContent(), msg))
+				} else {
+					cmds = append(cmds, copyCmd(f.currentContent.content, "File contents copied to clipboard"))
+				}
+			case key.Matches(msg, copyPath):
+				fp := filepath.ToSlash(f.path)
+				cmds = append(cmds, copyCmd(fp, fmt.Sprintf("File path %q copied to clipboard", fp)))
+			case key.Matches(msg, toggleBookmark) && f.repo != nil && f.ref != nil:
+				cmds = append(cmds, f.toggleBookmarkCmd())
+			case key.Matches(msg, copyPermalink):
+				if f.repo != nil && f.ref != nil {
+					start, end, _ := f.code.SelectedLines()
+					link := common.FileLinesURL(f.common.Config(), f.repo.Name(), f.ref.ID, filepath.ToSlash(f.path), start, end)
+					cmds = append(cmds, copyCmd(link, "Permalink copied to clipboard"))
+				}
+			case key.Matches(msg, lineNo) && !f.isPreview() && f.currentContent.binary == nil:
 				f.lineNumber = !f.lineNumber
 				f.code.ShowLineNumber = f.lineNumber
 				cmds = append(cmds, f.code.SetContent(f.currentContent.content, f.currentContent.ext))
-			case key.Matches(msg, blameView):
+			case key.Matches(msg, wrapLines) && !f.isPreview() && f.currentContent.binary == nil:
+				f.wrapToggled = true
+				f.code.NoWrap = !f.code.NoWrap
+				cmds = append(cmds, f.code.SetContent(f.currentContent.content, f.currentContent.ext))
+			case key.Matches(msg, blameView) && f.currentContent.binary == nil:
 				f.activeView = filesViewLoading
 				f.blameView = !f.blameView
 				if f.blameView {
-					cmds = append(cmds, f.fetchBlame)
+					cmds = append(cmds, f.fetchBlameCmd())
 				} else {
 					f.activeView = filesViewContent
 					cmds = append(cmds, f.code.SetSideNote(""))
 				}
 				cmds = append(cmds, f.spinner.Tick)
+			case key.Matches(msg, rawView) && f.currentContent.binary == nil:
+				cmds = append(cmds, f.toggleRaw())
 			case key.Matches(msg, preview) &&
-				common.IsFileMarkdown(f.currentContent.content, f.currentContent.ext) && !f.blameView:
+				common.IsFileMarkdown(f.currentContent.content, f.currentContent.ext) && !f.blameView && !f.code.Raw:
 				f.code.UseGlamour = !f.code.UseGlamour
+				f.code.ClearSelection()
 				cmds = append(cmds, f.code.SetContent(f.currentContent.content, f.currentContent.ext))
 			}
 		}
@@ -322,7 +971,15 @@ func (f *Files) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		f.activeView = filesViewFiles
 		f.lastSelected = make([]int, 0)
 		f.selector.Select(0)
-		cmds = append(cmds, f.setItems([]selector.IdentifiableItem{}))
+		f.resetTrees()
+		cmds = append(cmds, f.setItems(git.Entries{}))
+	case code.RenderedMsg:
+		for _, c := range []*code.Code{f.code, f.changeDiff} {
+			if _, cmd := c.Update(msg); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+		return f, tea.Batch(cmds...)
 	case spinner.TickMsg:
 		if f.activeView == filesViewLoading && f.spinner.ID() == msg.ID {
 			s, cmd := f.spinner.Update(msg)
@@ -345,6 +1002,30 @@ func (f *Files) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if cmd != nil {
 			cmds = append(cmds, cmd)
 		}
+	case filesViewChangeRefs:
+		m, cmd := f.changeRefs.Update(msg)
+		f.changeRefs = m.(*selector.Selector)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	case filesViewChanges:
+		m, cmd := f.changes.Update(msg)
+		f.changes = m.(*selector.Selector)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	case filesViewChangeDiff:
+		m, cmd := f.changeDiff.Update(msg)
+		f.changeDiff = m.(*code.Code)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	case filesViewMarkers:
+		m, cmd := f.markers.Update(msg)
+		f.markers = m.(*selector.Selector)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
 	}
 	return f, tea.Batch(cmds...)
 }
@@ -357,7 +1038,23 @@ func (f *Files) View() string {
 	case filesViewFiles:
 		return f.selector.View()
 	case filesViewContent:
+		if b := f.currentContent.binary; b != nil && !f.code.IsHex() {
+			return f.common.Styles.NoContent.Render(fmt.Sprintf("Binary file, %s. Press %s to view it as hex.",
+				humanize.IBytes(uint64(b.Size())), hexDump.Help().Key))
+		}
+		if p := f.currentContent.pointer; p != nil && !f.blameView {
+			return f.common.Styles.NoContent.Render(fmt.Sprintf("Git LFS file, %s. The repository only has a pointer to it, object %s.",
+				humanize.IBytes(uint64(p.Size)), p.Oid[:12]))
+		}
 		return f.code.View()
+	case filesViewChangeRefs:
+		return lipgloss.JoinVertical(lipgloss.Left, f.changesHeader(), "", f.changeRefs.View())
+	case filesViewChanges:
+		return lipgloss.JoinVertical(lipgloss.Left, f.changesHeader(), "", f.changes.View())
+	case filesViewChangeDiff:
+		return f.changeDiff.View()
+	case filesViewMarkers:
+		return lipgloss.JoinVertical(lipgloss.Left, f.markersHeader(), "", f.markers.View())
 	default:
 		return ""
 	}
@@ -370,54 +1067,524 @@ func (f *Files) SpinnerID() int {
 
 // StatusBarValue returns the status bar value.
 func (f *Files) StatusBarValue() string {
+	switch f.activeView {
+	case filesViewChangeRefs:
+		if i, ok := f.changeRefs.SelectedItem().(RefItem); ok {
+			return i.Short()
+		}
+		return " "
+	case filesViewChanges, filesViewChangeDiff:
+		if i, ok := f.changes.SelectedItem().(ChangedFileItem); ok {
+			return i.Path
+		}
+		return fmt.Sprintf("%s → %s", f.changesBase, f.refName())
+	}
 	p := f.path
 	if p == "." || p == "" {
 		return " "
 	}
+	if f.ref != nil && f.ref.Refspec == f.ref.ID {
+		// The file was jumped to at a commit.
+		p += " @ " + f.ref.ID[:7]
+	}
 	return p
 }
 
+// StatusBarPath returns whether the status bar value is a path, and whether
+// it's shown in full.
+func (f *Files) StatusBarPath() (bool, bool) {
+	switch f.activeView {
+	case filesViewChangeRefs:
+		return false, false
+	case filesViewChanges, filesViewChangeDiff:
+		if _, ok := f.changes.SelectedItem().(ChangedFileItem); !ok {
+			return false, false
+		}
+	default:
+		if p := f.path; p == "." || p == "" {
+			return false, false
+		}
+	}
+	return true, f.fullPath
+}
+
 // StatusBarInfo returns the status bar info.
 func (f *Files) StatusBarInfo() string {
 	switch f.activeView {
 	case filesViewFiles:
-		return fmt.Sprintf("# %d/%d", f.selector.Index()+1, len(f.selector.VisibleItems()))
+		info := fmt.Sprintf("# %d/%d", f.selector.Index()+1, len(f.selector.VisibleItems()))
+		if f.hidden > 0 {
+			info += fmt.Sprintf(" (%d hidden)", f.hidden)
+		}
+		if f.loadingPage {
+			info += " loading…"
+		}
+		return info
+	case filesViewChangeRefs:
+		return fmt.Sprintf("# %d/%d", f.changeRefs.Index()+1, len(f.changeRefs.VisibleItems()))
+	case filesViewMarkers:
+		return fmt.Sprintf("# %d/%d", f.markers.Index()+1, len(f.markers.VisibleItems()))
+	case filesViewChanges:
+		return fmt.Sprintf("# %d/%d", f.changes.Index()+1, len(f.changes.VisibleItems()))
+	case filesViewChangeDiff:
+		return fmt.Sprintf("☰ %d%%", f.changeDiff.ScrollPosition())
 	case filesViewContent:
-		return fmt.Sprintf("☰ %d%%", f.code.ScrollPosition())
+		if b := f.currentContent.binary; b != nil {
+			info := humanize.IBytes(uint64(b.Size()))
+			if f.code.IsHex() {
+				info += fmt.Sprintf(" ☰ %d%%", f.code.ScrollPosition())
+			}
+			return info
+		}
+		if p := f.currentContent.pointer; p != nil && !f.blameView {
+			return "LFS " + humanize.IBytes(uint64(p.Size))
+		}
+		info := fmt.Sprintf("☰ %d%%", f.code.ScrollPosition())
+		if start, end, ok := f.code.SelectedLines(); ok {
+			info = linesString(start, end) + " " + info
+		}
+		if le := f.currentContent.lineEnding; le != "" {
+			info = le + " " + info
+		}
+		if enc := f.currentContent.encoding; enc != "" {
+			info = enc + " " + info
+		}
+		if n := f.markerCount; n > 0 {
+			info = fmt.Sprintf("⚑ %d %s", n, info)
+		}
+		if f.code.Raw {
+			info = "RAW " + info
+		}
+		if n := len(f.conflicts); n == 1 {
+			info = "⚠ 1 conflict " + info
+		} else if n > 1 {
+			info = fmt.Sprintf("⚠ %d conflicts %s", n, info)
+		}
+		return info
 	default:
 		return ""
 	}
 }
 
 func (f *Files) updateFilesCmd() tea.Msg {
-	files := make([]selector.IdentifiableItem, 0)
-	dirs := make([]selector.IdentifiableItem, 0)
+	if f.treeMode {
+		return f.updateTreeCmd()
+	}
+
+	page := 0
+	if perPage := f.selector.PerPage(); f.cursor > 0 && perPage > 0 {
+		page = f.cursor / perPage
+	}
+	return f.loadPageCmd(page)()
+}
+
+// loadPageCmd loads the given page of the current directory. The directory
+// entries are fetched and sorted only once per session, subsequent calls only
+// load the items of the requested page.
+func (f *Files) loadPageCmd(page int) tea.Cmd {
+	path := f.path
+	ref := f.ref
+	perPage := f.selector.PerPage()
+	return func() tea.Msg {
+		if ref == nil {
+			return nil
+		}
+		ents, ok := f.tree(path)
+		if !ok {
+			r, err := f.repo.Open()
+			if err != nil {
+				return common.ErrorMsg(err)
+			}
+			t, err := r.TreePath(ref, path)
+			if err != nil {
+				return common.ErrorMsg(err)
+			}
+			ents, err = t.Entries()
+			if err != nil {
+				return common.ErrorMsg(err)
+			}
+			ents = sortEntries(ents)
+			f.loadVendored(r, ref, path, ents)
+			f.loadSubmodules(r, ref, ents)
+			f.setTree(path, ents)
+		}
+
+		all := len(ents)
+		ents = f.visibleEntries(path, ents)
+
+		start, end := 0, len(ents)
+		if perPage > 0 {
+			start = min(page*perPage, len(ents))
+			end = min(start+perPage, len(ents))
+		}
+
+		// Fetch the entries sizes beforehand so that rendering the page
+		// doesn't block.
+		f.loadSizes(ents[start:end])
+		f.loadPointers(path, ents[start:end])
+
+		return FileItemsMsg{
+			path:    path,
+			entries: ents,
+			start:   start,
+			end:     end,
+			hidden:  all - len(ents),
+		}
+	}
+}
+
+// updateTreeCmd loads the visible items of the current directory in tree
+// view, including the contents of the expanded directories.
+func (f *Files) updateTreeCmd() tea.Msg {
 	if f.ref == nil {
 		return nil
 	}
+
+	path := f.path
+	items := make([]selector.IdentifiableItem, 0)
+	var hidden int
+	var walk func(dir string, depth int) error
+	walk = func(dir string, depth int) error {
+		all, err := f.loadTree(filepath.Join(path, dir))
+		if err != nil {
+			return err
+		}
+		ents := f.visibleEntries(filepath.Join(path, dir), all)
+		hidden += len(all) - len(ents)
+		f.loadSizes(ents)
+		f.loadPointers(filepath.Join(path, dir), ents)
+		for _, e := range ents {
+			p := filepath.Join(dir, e.Name())
+			expanded := e.IsTree() && f.isExpanded(filepath.Join(path, p))
+			items = append(items, FileItem{
+				entry:     e,
+				vendored:  f.isVendored(filepath.Join(path, p)),
+				submodule: f.submodule(filepath.Join(path, p)),
+				pointer:   f.pointer(filepath.Join(path, p)),
+				path:      p,
+				depth:     depth,
+				tree:      true,
+				expanded:  expanded,
+			})
+			if expanded {
+				if err := walk(p, depth+1); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := walk("", 0); err != nil {
+		return common.ErrorMsg(err)
+	}
+
+	return FileTreeMsg{
+		path:   path,
+		items:  items,
+		hidden: hidden,
+	}
+}
+
+// loadTree returns the sorted entries of the given directory. Entries are
+// cached for the session.
+func (f *Files) loadTree(path string) (git.Entries, error) {
+	if ents, ok := f.tree(path); ok {
+		return ents, nil
+	}
+
 	r, err := f.repo.Open()
 	if err != nil {
-		return common.ErrorCmd(err)
+		return nil, err
 	}
-	path := f.path
-	ref := f.ref
-	t, err := r.TreePath(ref, path)
+	t, err := r.TreePath(f.ref, path)
 	if err != nil {
-		return common.ErrorCmd(err)
+		return nil, err
 	}
 	ents, err := t.Entries()
 	if err != nil {
-		return common.ErrorCmd(err)
+		return nil, err
+	}
+
+	ents = sortEntries(ents)
+	f.loadVendored(r, f.ref, path, ents)
+	f.loadSubmodules(r, f.ref, ents)
+	f.setTree(path, ents)
+	return ents, nil
+}
+
+// loadVendored reads the linguist-vendored and linguist-generated attributes
+// of the given directory entries.
+func (f *Files) loadVendored(r *git.Repository, ref *git.Reference, dir string, ents git.Entries) {
+	paths := make([]string, len(ents))
+	for i, e := range ents {
+		paths[i] = filepath.Join(dir, e.Name())
+	}
+	attrs, err := r.CheckPathsAttributes(ref, paths)
+	if err != nil {
+		f.common.Logger.Debugf("ui: error checking attributes: %v", err)
+		return
+	}
+
+	f.treesMtx.Lock()
+	defer f.treesMtx.Unlock()
+	for p, as := range attrs {
+		for _, a := range as {
+			if (a.Name == "linguist-vendored" || a.Name == "linguist-generated") && a.IsSet() {
+				f.vendored[p] = true
+			}
+		}
+	}
+}
+
+func (f *Files) isVendored(path string) bool {
+	f.treesMtx.Lock()
+	defer f.treesMtx.Unlock()
+	return f.vendored[path]
+}
+
+// loadSizes fetches the sizes of the given entries in one pass. Listing a
+// directory only reads its tree, not the files in it.
+func (f *Files) loadSizes(ents git.Entries) {
+	r, err := f.repo.Open()
+	if err == nil {
+		err = r.LoadSizes(ents)
+	}
+	if err != nil {
+		f.common.Logger.Debugf("ui: error loading sizes: %v", err)
+	}
+}
+
+// loadPointers finds the LFS pointers among the given entries of a directory.
+// The sizes of the entries must be fetched beforehand.
+func (f *Files) loadPointers(dir string, ents git.Entries) {
+	blobs := make([]git.TreeBlob, 0, len(ents))
+	for _, e := range ents {
+		if e.IsBlob() {
+			blobs = append(blobs, git.TreeBlob{
+				Path: filepath.Join(dir, e.Name()),
+				ID:   e.ID().String(),
+				Size: e.Size(),
+			})
+		}
+	}
+	r, err := f.repo.Open()
+	if err != nil {
+		return
+	}
+	pointers, err := lfs.TreePointers(r, blobs)
+	if err != nil {
+		f.common.Logger.Debugf("ui: error finding lfs pointers: %v", err)
+		return
+	}
+
+	f.treesMtx.Lock()
+	defer f.treesMtx.Unlock()
+	for p, ptr := range pointers {
+		f.pointers[p] = ptr
+	}
+}
+
+func (f *Files) pointer(path string) *lfs.Pointer {
+	f.treesMtx.Lock()
+	defer f.treesMtx.Unlock()
+	if p, ok := f.pointers[path]; ok {
+		return &p
+	}
+	return nil
+}
+
+// visibleEntries returns the entries of the given directory that are shown.
+// Vendored and generated entries are hidden unless showVendored is set. This
+// only filters the list, the tree itself is left untouched.
+func (f *Files) visibleEntries(dir string, ents git.Entries) git.Entries {
+	if f.showVendored {
+		return ents
+	}
+	f.treesMtx.Lock()
+	defer f.treesMtx.Unlock()
+	visible := make(git.Entries, 0, len(ents))
+	for _, e := range ents {
+		if !f.vendored[filepath.Join(dir, e.Name())] {
+			visible = append(visible, e)
+		}
 	}
+	return visible
+}
+
+// loadSubmodules loads the submodules of the given ref if any of the given
+// entries is a submodule. On the server, submodules hosted here are checked
+// against their repository.
+func (f *Files) loadSubmodules(r *git.Repository, ref *git.Reference, ents git.Entries) {
+	f.treesMtx.Lock()
+	loaded := f.submodules != nil
+	f.treesMtx.Unlock()
+	if loaded {
+		return
+	}
+
+	var hasSubmodule bool
+	for _, e := range ents {
+		if e.IsCommit() {
+			hasSubmodule = true
+			break
+		}
+	}
+	if !hasSubmodule {
+		return
+	}
+
+	var subs []proto.Submodule
+	if be := f.common.Backend(); be != nil {
+		var err error
+		subs, err = be.Submodules(f.common.Context(), f.common.User(), f.repo, ref.ID)
+		if err != nil {
+			f.common.Logger.Debugf("ui: error loading submodules: %v", err)
+			return
+		}
+	} else {
+		ss, err := r.Submodules(ref.ID)
+		if err != nil {
+			f.common.Logger.Debugf("ui: error loading submodules: %v", err)
+			return
+		}
+		for _, s := range ss {
+			subs = append(subs, proto.Submodule{
+				Name:   s.Name,
+				Path:   s.Path,
+				URL:    s.URL,
+				Branch: s.Branch,
+				Commit: s.Commit,
+			})
+		}
+	}
+
+	f.treesMtx.Lock()
+	defer f.treesMtx.Unlock()
+	f.submodules = make(map[string]proto.Submodule, len(subs))
+	for _, s := range subs {
+		f.submodules[s.Path] = s
+	}
+}
+
+func (f *Files) submodule(path string) *proto.Submodule {
+	f.treesMtx.Lock()
+	defer f.treesMtx.Unlock()
+	if s, ok := f.submodules[path]; ok {
+		return &s
+	}
+	return nil
+}
+
+// collapseTree collapses the selected directory if it's expanded, otherwise
+// it collapses the directory that contains the selected item. It returns
+// false if there is nothing to collapse.
+func (f *Files) collapseTree() bool {
+	sel, ok := f.selector.SelectedItem().(FileItem)
+	if !ok {
+		return false
+	}
+
+	if sel.expanded {
+		f.setExpanded(filepath.Join(f.path, sel.path), false)
+		f.cursor = f.selector.Index()
+		return true
+	}
+
+	if sel.depth == 0 {
+		return false
+	}
+
+	parent := filepath.Dir(sel.path)
+	f.setExpanded(filepath.Join(f.path, parent), false)
+	f.cursor = 0
+	for i, it := range f.selector.Items() {
+		if fi, ok := it.(FileItem); ok && fi.path == parent {
+			f.cursor = i
+			break
+		}
+	}
+
+	return true
+}
+
+func (f *Files) isExpanded(path string) bool {
+	f.treesMtx.Lock()
+	defer f.treesMtx.Unlock()
+	return f.expanded[path]
+}
+
+func (f *Files) setExpanded(path string, expanded bool) {
+	f.treesMtx.Lock()
+	defer f.treesMtx.Unlock()
+	f.expanded[path] = expanded
+}
+
+// popPath returns the directory to go back to when leaving the current file
+// or directory. Files opened from the tree view go back to the tree root.
+func (f *Files) popPath() string {
+	if f.treeFile {
+		f.treeFile = false
+		return f.treeRoot
+	}
+	return filepath.Dir(f.path)
+}
+
+// sortEntries sorts the entries with directories first and then by name.
+func sortEntries(ents git.Entries) git.Entries {
 	ents.Sort()
+	dirs := make(git.Entries, 0)
+	files := make(git.Entries, 0)
 	for _, e := range ents {
 		if e.IsTree() {
-			dirs = append(dirs, FileItem{entry: e})
+			dirs = append(dirs, e)
 		} else {
-			files = append(files, FileItem{entry: e})
+			files = append(files, e)
+		}
+	}
+	return append(dirs, files...)
+}
+
+func (f *Files) tree(path string) (git.Entries, bool) {
+	f.treesMtx.Lock()
+	defer f.treesMtx.Unlock()
+	ents, ok := f.trees[path]
+	return ents, ok
+}
+
+func (f *Files) setTree(path string, ents git.Entries) {
+	f.treesMtx.Lock()
+	defer f.treesMtx.Unlock()
+	f.trees[path] = ents
+}
+
+func (f *Files) resetTrees() {
+	f.treesMtx.Lock()
+	defer f.treesMtx.Unlock()
+	f.trees = make(map[string]git.Entries)
+	f.items = make(map[string][]selector.IdentifiableItem)
+	f.expanded = make(map[string]bool)
+	f.vendored = make(map[string]bool)
+	f.pointers = make(map[string]lfs.Pointer)
+	f.submodules = nil
+	f.loadingPage = false
+}
+
+// isWindowLoaded returns whether all the items of the current directory
+// between start and end are loaded.
+func (f *Files) isWindowLoaded(start, end int) bool {
+	if f.treeMode {
+		return true
+	}
+	items := f.items[f.path]
+	start = min(start, len(items))
+	end = min(max(start, end), len(items))
+	for _, it := range items[start:end] {
+		if it == nil {
+			return false
 		}
 	}
-	return FileItemsMsg(append(dirs, files...))
+	return true
 }
 
 func (f *Files) selectTreeCmd() tea.Msg {
@@ -431,67 +1598,300 @@ func (f *Files) selectTreeCmd() tea.Msg {
 
 func (f *Files) selectFileCmd() tea.Msg {
 	i := f.currentItem
+	if i != nil && i.entry.IsCommit() {
+		f.path = f.popPath()
+		return common.ErrorMsg(errSubmodule)
+	}
 	if i != nil && !i.entry.IsTree() {
-		fi := i.entry.File()
 		if i.Mode().IsDir() || f == nil {
 			return common.ErrorMsg(errInvalidFile)
 		}
 
-		var err error
-		var bin bool
+		// The attributes are skipped if the repository can't be opened.
+		r, _ := f.repo.Open()
+		content, err := fileContent(r, f.ref, i.entry)
+		if err != nil {
+			f.path = f.popPath()
+			return common.ErrorMsg(err)
+		}
 
-		r, err := f.repo.Open()
+		f.lastSelected = append(f.lastSelected, f.selector.Index())
+		return content
+	}
+
+	return common.ErrorMsg(errNoFileSelected)
+}
+
+// fileContent reads the content of the given file at ref. The attributes of
+// the file are only checked if r isn't nil. The content of binary files isn't
+// read, they can be shown as a hex dump. LFS pointers are read as they are,
+// whatever the type of the file they point to.
+func fileContent(r *git.Repository, ref *git.Reference, e *git.TreeEntry) (FileContentMsg, error) {
+	fi := e.File()
+	if p, ok := lfs.FilePointer(fi); ok {
+		return FileContentMsg{
+			content: p.String(),
+			ext:     e.Name(),
+			pointer: &p,
+		}, nil
+	}
+
+	var bin bool
+	var lang string
+	if r != nil {
+		attrs, err := r.CheckAttributes(ref, fi.Path())
 		if err == nil {
-			attrs, err := r.CheckAttributes(f.ref, fi.Path())
-			if err == nil {
-				for _, attr := range attrs {
-					if (attr.Name == "binary" && attr.Value == "set") ||
-						(attr.Name == "text" && attr.Value == "unset") {
-						bin = true
-						break
-					}
+			for _, attr := range attrs {
+				switch {
+				case (attr.Name == "binary" && attr.Value == "set") ||
+					(attr.Name == "text" && attr.Value == "unset"):
+					bin = true
+				case attr.Name == "linguist-language":
+					lang = attr.Value
 				}
 			}
 		}
+	}
 
-		if !bin {
-			bin, err = fi.IsBinary()
-			if err != nil {
-				f.path = filepath.Dir(f.path)
-				return common.ErrorMsg(err)
-			}
+	if !bin {
+		var err error
+		bin, err = fi.IsBinary()
+		if err != nil {
+			return FileContentMsg{}, err
 		}
+	}
 
-		if bin {
-			f.path = filepath.Dir(f.path)
-			return common.ErrorMsg(errBinaryFile)
+	if bin {
+		return FileContentMsg{ext: e.Name(), binary: fi}, nil
+	}
+
+	c, err := fi.Bytes()
+	if err != nil {
+		return FileContentMsg{}, err
+	}
+
+	return FileContentMsg{
+		content:    string(c),
+		ext:        e.Name(),
+		language:   lang,
+		encoding:   common.DetectEncoding(c),
+		lineEnding: common.DetectLineEnding(c),
+	}, nil
+}
+
+// fetchBlameCmd loads the blame of the current file. The blame is canceled
+// when it takes longer than the configured operation timeout or when
+// cancelBlame is called.
+func (f *Files) fetchBlameCmd() tea.Cmd {
+	ctx, cancel := f.blameContext()
+	f.blameCancel = cancel
+	ref := f.ref
+	rev := ref.ID
+	path := f.currentItem.entry.File().Path()
+	return func() tea.Msg {
+		defer cancel()
+		r, err := f.repo.Open()
+		if err != nil {
+			return common.ErrorMsg(err)
 		}
 
-		c, err := fi.Bytes()
+		b, err := blameFile(ctx, r, rev, path)
 		if err != nil {
-			f.path = filepath.Dir(f.path)
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
 			return common.ErrorMsg(err)
 		}
+		if mm, err := r.Mailmap(ref); err == nil {
+			mapBlame(mm, b)
+		} else {
+			f.common.Logger.Debugf("ui: error loading mailmap: %v", err)
+		}
 
-		f.lastSelected = append(f.lastSelected, f.selector.Index())
-		return FileContentMsg{string(c), i.entry.Name()}
+		return FileBlameMsg(b)
 	}
+}
 
-	return common.ErrorMsg(errNoFileSelected)
+// mapBlame replaces the authors and committers of the commits of a blame
+// with their canonical identities. The lines of a commit share it.
+func mapBlame(mm *git.Mailmap, b *gitm.Blame) {
+	seen := map[*git.Commit]bool{}
+	for i := 1; ; i++ {
+		c := b.Line(i)
+		if c == nil {
+			return
+		}
+		if !seen[c] {
+			seen[c] = true
+			mm.MapCommits(c)
+		}
+	}
 }
 
-func (f *Files) fetchBlame() tea.Msg {
-	r, err := f.repo.Open()
-	if err != nil {
-		return common.ErrorMsg(err)
+// blameContext returns the context of a blame. It expires after the
+// configured operation timeout.
+func (f *Files) blameContext() (context.Context, context.CancelFunc) {
+	if cfg := f.common.Config(); cfg != nil {
+		return cfg.Repo.OperationContext(f.common.Context())
 	}
+	return context.WithCancel(f.common.Context())
+}
 
-	b, err := r.BlameFile(f.ref.ID, f.currentItem.entry.File().Path())
+// blameFile blames the file at the given revision. It returns
+// context.Canceled if ctx is canceled and errBlameTimeout if it expires.
+func blameFile(ctx context.Context, r *git.Repository, rev, path string) (*gitm.Blame, error) {
+	b, err := r.BlameFile(rev, path, gitm.BlameOptions{
+		CommandOptions: gitm.CommandOptions{
+			Context: ctx,
+			// The context handles the timeout.
+			Timeout: -1,
+		},
+	})
 	if err != nil {
-		return common.ErrorMsg(err)
+		switch {
+		case errors.Is(ctx.Err(), context.Canceled):
+			return nil, context.Canceled
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			return nil, errBlameTimeout
+		}
+		return nil, err
 	}
+	return b, nil
+}
+
+// jumpCmd loads the file of a jump along with its content, and its blame when
+// asked for. It can be canceled with cancelBlame.
+func (f *Files) jumpCmd(msg FileJumpMsg) tea.Cmd {
+	ctx, cancel := f.blameContext()
+	f.blameCancel = cancel
+	return func() tea.Msg {
+		defer cancel()
+		r, err := f.repo.Open()
+		if err != nil {
+			return fileJumpResultMsg{err: err}
+		}
 
-	return FileBlameMsg(b)
+		ref := r.CommitReference(msg.rev)
+		dir, name := filepath.Split(msg.path)
+		var e *git.TreeEntry
+		t, err := r.TreePath(ref, dir)
+		if err == nil {
+			e, err = t.TreeEntry(name)
+		}
+		if err != nil {
+			return fileJumpResultMsg{
+				err: fmt.Errorf("%s doesn't exist in commit %s", msg.path, msg.rev[:7]),
+			}
+		}
+		if e.IsTree() || e.IsCommit() {
+			return fileJumpResultMsg{err: errInvalidFile}
+		}
+
+		content, err := fileContent(r, ref, e)
+		if err != nil {
+			return fileJumpResultMsg{err: err}
+		}
+		if content.binary != nil {
+			return fileJumpResultMsg{err: errBinaryFile}
+		}
+
+		res := fileJumpResultMsg{
+			ref:     ref,
+			entry:   e,
+			content: content,
+			line:    msg.line,
+		}
+		if msg.blame {
+			res.blame, err = blameFile(ctx, r, ref.ID, e.File().Path())
+			if err != nil {
+				return fileJumpResultMsg{err: err}
+			}
+		}
+		return res
+	}
+}
+
+// jumpBack restores the state of the files view before the last jump and
+// goes back to the tab that jumped here.
+func (f *Files) jumpBack() tea.Cmd {
+	j := f.jumps[len(f.jumps)-1]
+	f.jumps = f.jumps[:len(f.jumps)-1]
+	if f.ref == nil || j.ref == nil || f.ref.ID != j.ref.ID {
+		f.resetTrees()
+	}
+	f.ref = j.ref
+	f.path = j.path
+	f.currentItem = j.item
+	f.currentContent = j.content
+	f.currentBlame = j.blame
+	f.blameView = j.blameView && j.blame != nil
+	f.treeFile = j.treeFile
+	f.treeRoot = j.treeRoot
+	f.lastSelected = j.lastSelected
+	f.code.ClearSelection()
+
+	cmds := []tea.Cmd{jumpBackCmd}
+	if j.view == filesViewContent {
+		note := ""
+		if f.blameView {
+			note = f.renderBlame(f.currentBlame)
+		}
+		f.code.UseGlamour = j.useGlamour
+		f.code.Raw = j.raw
+		f.code.Language = j.content.language
+		f.code.SetSideNote(note)
+		cmds = append(cmds, f.code.SetContent(j.content.content, j.content.ext), f.setMarkers())
+		f.code.SetYOffset(j.yOffset)
+		f.activeView = filesViewContent
+	} else {
+		f.blameView = false
+		f.currentBlame = nil
+		f.code.SetSideNote("")
+		f.cursor = j.cursor
+		f.activeView = filesViewFiles
+		if f.ref != nil {
+			f.activeView = filesViewLoading
+			cmds = append(cmds, f.spinner.Tick, f.updateFilesCmd)
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// showCommitCmd jumps to the diff of the commit that last changed the current
+// line of the blame. The current line is the start of the selection or the
+// top of the view.
+func (f *Files) showCommitCmd() tea.Cmd {
+	c := (*gitm.Blame)(f.currentBlame).Line(f.code.CurrentLine())
+	if c == nil {
+		return nil
+	}
+	path := filepath.ToSlash(f.currentItem.entry.File().Path())
+	return func() tea.Msg {
+		return LogJumpMsg{
+			commit: c,
+			path:   path,
+		}
+	}
+}
+
+// cancelBlame cancels the blame that is being loaded and goes back to the
+// file content.
+func (f *Files) cancelBlame() {
+	if f.blameCancel != nil {
+		f.blameCancel()
+		f.blameCancel = nil
+	}
+	f.blameView = false
+	f.activeView = filesViewContent
+}
+
+// renderBlame renders the blame of the current file as a heatmap or as the
+// commits of the lines.
+func (f *Files) renderBlame(b FileBlameMsg) string {
+	if f.heatmap {
+		return renderBlameHeatmap(f.common, f.currentItem, b)
+	}
+	return renderBlame(f.common, f.currentItem, b)
 }
 
 func renderBlame(c common.Common, f *FileItem, b *gitm.Blame) string {
@@ -525,8 +1925,88 @@ func renderBlame(c common.Common, f *FileItem, b *gitm.Blame) string {
 	return strings.Join(lines, "\n")
 }
 
+// renderBlameHeatmap renders the age of the last change of every line colored
+// from the most recent to the oldest change of the file.
+func renderBlameHeatmap(c common.Common, f *FileItem, b *gitm.Blame) string {
+	if f == nil || f.entry.IsTree() || b == nil {
+		return ""
+	}
+
+	commits := make([]*gitm.Commit, 0)
+	var newest, oldest time.Time
+	for i := 1; ; i++ {
+		commit := b.Line(i)
+		if commit == nil {
+			break
+		}
+		commits = append(commits, commit)
+		when := commit.Author.When
+		if newest.IsZero() || when.After(newest) {
+			newest = when
+		}
+		if oldest.IsZero() || when.Before(oldest) {
+			oldest = when
+		}
+	}
+
+	heat := blameHeatStyles(c)
+	lines := make([]string, len(commits))
+	var prev string
+	for i, commit := range commits {
+		when := commit.Author.When
+		level := 0
+		if span := newest.Sub(oldest); span > 0 {
+			level = int(float64(newest.Sub(when)) / float64(span) * float64(len(heat)-1))
+		}
+		st := heat[level]
+		line := st.Render("█")
+		if id := commit.ID.String(); id != prev {
+			line += " " + st.Render(humanize.Time(when))
+			prev = id
+		}
+		lines[i] = line
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// blameHeatStyles returns the colors of the blame heatmap, the configured
+// ones or the ones of the theme.
+func blameHeatStyles(c common.Common) []lipgloss.Style {
+	if cfg := c.Config(); cfg != nil && len(cfg.UI.BlameHeatmap) > 0 {
+		heat := make([]lipgloss.Style, len(cfg.UI.BlameHeatmap))
+		for i, color := range cfg.UI.BlameHeatmap {
+			heat[i] = c.Renderer.NewStyle().Foreground(lipgloss.Color(color))
+		}
+		return heat
+	}
+	return c.Styles.Tree.Blame.Heat
+}
+
+// loadFileIcons returns the icons of the file list preferred by the user, or
+// those of the server.
+func (f *Files) loadFileIcons() string {
+	icons := config.FileIconsNone
+	if cfg := f.common.Config(); cfg != nil && cfg.UI.FileIcons != "" {
+		icons = cfg.UI.FileIcons
+	}
+	be := f.common.Backend()
+	if be == nil {
+		return icons
+	}
+	v, err := be.Preference(f.common.Context(), f.common.PublicKey(), common.FileIconsPreference)
+	if err != nil {
+		f.common.Logger.Debugf("ui: failed to load file icons: %v", err)
+		return icons
+	}
+	if v != "" {
+		icons = v
+	}
+	return icons
+}
+
 func (f *Files) deselectItemCmd() tea.Cmd {
-	f.path = filepath.Dir(f.path)
+	f.path = f.popPath()
 	index := 0
 	if len(f.lastSelected) > 0 {
 		index = f.lastSelected[len(f.lastSelected)-1]
@@ -534,15 +2014,27 @@ func (f *Files) deselectItemCmd() tea.Cmd {
 	}
 	f.cursor = index
 	f.activeView = filesViewFiles
+	f.code.ClearSelection()
 	f.code.SetSideNote("")
+	if f.blameCancel != nil {
+		f.blameCancel()
+		f.blameCancel = nil
+	}
 	f.blameView = false
 	f.currentBlame = nil
 	f.code.UseGlamour = false
+	f.code.Raw = false
+	f.code.Language = ""
 	return f.updateFilesCmd
 }
 
-func (f *Files) setItems(items []selector.IdentifiableItem) tea.Cmd {
+func (f *Files) setItems(ents git.Entries) tea.Cmd {
+	path := f.path
 	return func() tea.Msg {
-		return FileItemsMsg(items)
+		return FileItemsMsg{
+			path:    path,
+			entries: ents,
+			end:     len(ents),
+		}
 	}
 }
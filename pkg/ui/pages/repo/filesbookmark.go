@@ -0,0 +1,211 @@
+package repo
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+)
+
+var toggleBookmark = key.NewBinding(
+	key.WithKeys("B"),
+	key.WithHelp("B", "bookmark"),
+)
+
+// ToggleBookmarkMsg is a message to bookmark a file, or to remove its
+// bookmark.
+type ToggleBookmarkMsg struct {
+	Bookmark common.Bookmark
+}
+
+// FileOpenMsg is a message to open a file of the current reference, scrolled
+// to the given line, e.g. to open a bookmark. The lines up to end are
+// selected when it's set.
+type FileOpenMsg struct {
+	ref  string
+	path string
+	line int
+	end  int
+}
+
+// fileOpenResultMsg is a message that contains the file that was opened along
+// with its content. The entry is nil when the file doesn't exist anymore, dir
+// is the closest directory that still exists then. notices explain how the
+// file differs from the one that was asked for.
+type fileOpenResultMsg struct {
+	entry   *git.TreeEntry
+	content FileContentMsg
+	dir     string
+	line    int
+	end     int
+	notices []string
+}
+
+// OpenFileCmd opens the file of the Files tab at the given path and line of
+// the reference, with the full name ref. The file is opened at the current
+// reference when it isn't ref anymore.
+func OpenFileCmd(ref, path string, line int) tea.Cmd {
+	return OpenFileRangeCmd(ref, path, line, 0)
+}
+
+// OpenFileRangeCmd is like OpenFileCmd, with the lines from start to end
+// selected. Paths of directories open the directory.
+func OpenFileRangeCmd(ref, path string, start, end int) tea.Cmd {
+	return func() tea.Msg {
+		return FileOpenMsg{
+			ref:  ref,
+			path: path,
+			line: start,
+			end:  end,
+		}
+	}
+}
+
+// bookmark returns the bookmark of the current file. The line is the start of
+// the selection, or the top of the view once it's scrolled.
+func (f *Files) bookmark() common.Bookmark {
+	b := common.Bookmark{
+		Repo: f.repo.Name(),
+		Ref:  f.ref.Name().String(),
+		Path: filepath.ToSlash(f.path),
+	}
+	if start, _, ok := f.code.SelectedLines(); ok {
+		b.Line = start
+	} else if f.code.YOffset > 0 {
+		b.Line = f.code.CurrentLine()
+	}
+	return b
+}
+
+// RestoreCmd implements refreshTab. The directory or file is opened again,
+// at the line at the top of the view for files.
+func (f *Files) RestoreCmd() tea.Cmd {
+	if f.path == "" || f.ref == nil {
+		return nil
+	}
+	var line int
+	if f.activeView == filesViewContent && !f.isPreview() {
+		line = f.bookmark().Line
+	}
+	return OpenFileCmd("", f.path, line)
+}
+
+// toggleBookmarkCmd bookmarks the current file, or removes its bookmark.
+func (f *Files) toggleBookmarkCmd() tea.Cmd {
+	b := f.bookmark()
+	return func() tea.Msg {
+		return ToggleBookmarkMsg{Bookmark: b}
+	}
+}
+
+// openFileCmd loads the file to open at the current reference. Files that
+// moved or were deleted open the closest directory that's still there
+// instead.
+func (f *Files) openFileCmd(msg FileOpenMsg) tea.Cmd {
+	repo, ref := f.repo, f.ref
+	return func() tea.Msg {
+		if repo == nil || ref == nil {
+			return StatusMsg(fmt.Sprintf("%s doesn't exist", msg.path))
+		}
+
+		res := fileOpenResultMsg{line: msg.line, end: msg.end}
+		if msg.ref != "" && msg.ref != ref.Name().String() {
+			res.notices = append(res.notices, fmt.Sprintf("%s no longer exists, showing %s",
+				git.ReferenceName(msg.ref).Short(), ref.Name().Short()))
+		}
+
+		r, err := repo.Open()
+		if err != nil {
+			return common.ErrorMsg(err)
+		}
+
+		p := path.Clean(strings.TrimPrefix(msg.path, "/"))
+		if p == "." {
+			return res
+		}
+		dir, name := path.Split(p)
+		var e *git.TreeEntry
+		t, err := r.TreePath(ref, dir)
+		if err == nil {
+			e, err = t.TreeEntry(name)
+		}
+		if err == nil && e.IsTree() {
+			res.dir = p
+			return res
+		}
+		if err == nil && !e.IsCommit() {
+			content, err := fileContent(r, ref, e)
+			if err != nil {
+				return common.ErrorMsg(err)
+			}
+			res.entry = e
+			res.content = content
+			if n := strings.Count(strings.TrimSuffix(content.content, "\n"), "\n") + 1; content.binary == nil && res.line > n {
+				res.notices = append(res.notices, fmt.Sprintf("%s has only %d lines", p, n))
+				res.line, res.end = 0, 0
+			} else if res.end > n {
+				res.end = n
+			}
+			return res
+		}
+
+		res.notices = append(res.notices, fmt.Sprintf("%s no longer exists in %s", p, ref.Name().Short()))
+		for dir = path.Dir(p); dir != "."; dir = path.Dir(dir) {
+			if _, err := r.TreePath(ref, dir); err == nil {
+				break
+			}
+		}
+		if dir == "." {
+			dir = ""
+		}
+		res.dir = dir
+		return res
+	}
+}
+
+// openFile shows the file that was opened, or the closest directory when it
+// doesn't exist anymore.
+func (f *Files) openFile(msg fileOpenResultMsg) tea.Cmd {
+	var notice tea.Cmd
+	if len(msg.notices) > 0 {
+		notice = statusCmd(strings.Join(msg.notices, "; "))
+	}
+	f.cancelBlame()
+	f.currentBlame = nil
+	f.code.SetSideNote("")
+	f.code.ClearSelection()
+	f.jumps = nil
+	f.treeFile = false
+	f.lastSelected = make([]int, 0)
+	if msg.entry == nil {
+		f.path = msg.dir
+		f.currentItem = nil
+		f.cursor = 0
+		f.activeView = filesViewLoading
+		// The notice comes after the items, they reset the status bar.
+		return tea.Batch(f.spinner.Tick, tea.Sequence(f.updateFilesCmd, notice))
+	}
+
+	f.path = msg.entry.File().Path()
+	f.currentItem = &FileItem{entry: msg.entry}
+	f.currentContent = msg.content
+	f.activeView = filesViewContent
+	f.code.UseGlamour = msg.line == 0 && common.IsFileMarkdown(msg.content.content, msg.content.ext)
+	f.code.Language = msg.content.language
+	f.code.Raw = false
+	f.code.SetHex(nil)
+	cmd := tea.Batch(f.code.SetContent(msg.content.content, msg.content.ext), f.setMarkers(), notice)
+	f.code.GotoTop()
+	switch {
+	case msg.line > 0 && msg.end >= msg.line:
+		f.code.SelectLines(msg.line, msg.end)
+	case msg.line > 0:
+		f.code.GotoLine(msg.line)
+	}
+	return cmd
+}
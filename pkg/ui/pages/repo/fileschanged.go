@@ -0,0 +1,327 @@
+package repo
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/ui/components/selector"
+)
+
+var changedFiles = key.NewBinding(
+	key.WithKeys("C"),
+	key.WithHelp("C", "changed files"),
+)
+
+// FileChangeRefsMsg is a message that contains the references the files can
+// be compared with.
+type FileChangeRefsMsg struct {
+	head  string
+	items []selector.IdentifiableItem
+}
+
+// FileChangesMsg is a message that contains the files that changed in the
+// current reference since it forked from base. since is the commit the
+// changes are relative to.
+type FileChangesMsg struct {
+	head  string
+	base  string
+	since string
+	items []selector.IdentifiableItem
+	err   error
+}
+
+// FileChangeDiffMsg is a message that contains the diff of a changed file.
+type FileChangeDiffMsg struct {
+	head string
+	file git.ChangedFile
+	diff *git.Diff
+	err  error
+}
+
+// ChangedFileItem is a list item for a file that changed since the compare
+// target.
+type ChangedFileItem struct {
+	git.ChangedFile
+	// Ignored is true if the file matches the .gitignore files of the
+	// reference, e.g. a build artifact committed by mistake.
+	Ignored bool
+}
+
+// ID implements selector.IdentifiableItem.
+func (i ChangedFileItem) ID() string {
+	return i.Path
+}
+
+// Title returns the path of the file, and its old path if it was renamed or
+// copied.
+func (i ChangedFileItem) Title() string {
+	if i.OldPath != "" {
+		return common.UnquoteFilename(i.OldPath) + " → " + common.UnquoteFilename(i.Path)
+	}
+	return common.UnquoteFilename(i.Path)
+}
+
+// Description implements list.DefaultItem.
+func (i ChangedFileItem) Description() string {
+	return ""
+}
+
+// FilterValue implements list.Item.
+func (i ChangedFileItem) FilterValue() string { return i.Title() }
+
+// ChangedFileItemDelegate is the delegate for the changed files list.
+type ChangedFileItemDelegate struct {
+	common *common.Common
+}
+
+// Height implements list.ItemDelegate.
+func (d ChangedFileItemDelegate) Height() int { return 1 }
+
+// Spacing implements list.ItemDelegate.
+func (d ChangedFileItemDelegate) Spacing() int { return 0 }
+
+// Update implements list.ItemDelegate.
+func (d ChangedFileItemDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd {
+	item, ok := m.SelectedItem().(ChangedFileItem)
+	if !ok {
+		return nil
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, d.common.KeyMap.Copy) {
+		return copyCmd(item.Path, fmt.Sprintf("File path %q copied to clipboard", item.Path))
+	}
+	return nil
+}
+
+// Render implements list.ItemDelegate.
+func (d ChangedFileItemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(ChangedFileItem)
+	if !ok {
+		return
+	}
+
+	s := d.common.Styles.Tree
+	nameStyle := s.Normal.FileName
+	selector := s.Selector.Render(" ")
+	if index == m.Index() {
+		nameStyle = s.Active.FileName
+		selector = s.Selector.Render(">")
+	}
+
+	var status lipgloss.Style
+	switch i.Status {
+	case "A":
+		status = s.Change.Added
+	case "D":
+		status = s.Change.Deleted
+	case "R", "C":
+		status = s.Change.Renamed
+	default:
+		status = s.Change.Modified
+	}
+
+	var ignored string
+	if i.Ignored {
+		ignored = " " + s.Change.Ignored.Render("⚠ ignored")
+	}
+	width := m.Width() - lipgloss.Width(selector) - 2 - nameStyle.GetHorizontalFrameSize() - lipgloss.Width(ignored)
+	fmt.Fprint(w, //nolint:errcheck
+		d.common.Zone.Mark(
+			i.ID(),
+			selector+" "+status.Render(i.Status)+nameStyle.Render(common.TruncateString(i.Title(), width))+ignored,
+		),
+	)
+}
+
+// newListSelector returns a selector for a list shown in the place of the
+// content of a tab, like the compare targets or the changed files.
+func newListSelector(c common.Common, delegate list.ItemDelegate) *selector.Selector {
+	s := selector.New(c, []selector.IdentifiableItem{}, delegate)
+	s.SetShowFilter(false)
+	s.SetShowHelp(false)
+	s.SetShowPagination(false)
+	s.SetShowStatusBar(false)
+	s.SetShowTitle(false)
+	s.SetFilteringEnabled(false)
+	s.DisableQuitKeybindings()
+	s.KeyMap.NextPage = c.KeyMap.NextPage
+	s.KeyMap.PrevPage = c.KeyMap.PrevPage
+	return s
+}
+
+// inChanges returns true if the files view shows the changed files, the
+// compare target picker, or the diff of a changed file.
+func (f *Files) inChanges() bool {
+	switch f.activeView {
+	case filesViewChangeRefs, filesViewChanges, filesViewChangeDiff:
+		return true
+	}
+	return false
+}
+
+// changesHeader returns the line shown above the compare target picker and
+// the changed files.
+func (f *Files) changesHeader() string {
+	var title string
+	switch f.activeView {
+	case filesViewChangeRefs:
+		title = "Compare the files of " + f.refName() + " with"
+	case filesViewChanges:
+		title = fmt.Sprintf("Files changed in %s since %s", f.refName(), f.changesBase)
+		if f.changesSince != "" {
+			title += " (" + f.changesSince[:7] + ")"
+		}
+	}
+	return f.common.Styles.Log.CommitHash.Render(common.TruncateString(title, f.common.Width))
+}
+
+// refName returns the short name of the current reference.
+func (f *Files) refName() string {
+	if f.ref == nil {
+		return ""
+	}
+	return f.ref.Name().Short()
+}
+
+// goBackChanges goes back from the diff of a changed file to the changed
+// files, from the changed files to the compare target picker, and from the
+// picker to the files.
+func (f *Files) goBackChanges() {
+	switch f.activeView {
+	case filesViewChangeDiff:
+		f.activeView = filesViewChanges
+	case filesViewChanges:
+		f.activeView = filesViewChangeRefs
+	case filesViewChangeRefs:
+		f.activeView = filesViewFiles
+	}
+}
+
+// changeRefsCmd loads the references the files can be compared with. The
+// default branch comes first, then the most recently updated branches and
+// tags. The current reference is left out.
+func (f *Files) changeRefsCmd() tea.Msg {
+	if f.repo == nil || f.ref == nil {
+		return nil
+	}
+	r, err := f.repo.Open()
+	if err != nil {
+		return common.ErrorMsg(err)
+	}
+	refs, err := referencesInfo(f.repo, r, git.RefsHeads, git.RefsTags)
+	if err != nil {
+		f.common.Logger.Debugf("ui: error getting references: %v", err)
+		return common.ErrorMsg(err)
+	}
+	var def string
+	if h, err := r.HEAD(); err == nil {
+		def = h.Name().String()
+	}
+	its := make(RefItems, 0, len(refs))
+	for _, ref := range refs {
+		if ref.Name() == f.ref.Name() {
+			continue
+		}
+		its = append(its, RefItem{
+			Reference:  ref.Reference,
+			Commit:     ref.Commit,
+			TagMessage: ref.TagMessage,
+		})
+	}
+	sort.Stable(its)
+	items := make([]selector.IdentifiableItem, 0, len(its))
+	for _, it := range its {
+		if it.Reference.Name().String() == def {
+			items = append([]selector.IdentifiableItem{it}, items...)
+			continue
+		}
+		items = append(items, it)
+	}
+	return FileChangeRefsMsg{
+		head:  f.ref.ID,
+		items: items,
+	}
+}
+
+// changesCmd loads the files that changed in the current reference since it
+// forked from base.
+func (f *Files) changesCmd(base *git.Reference) tea.Cmd {
+	repo := f.repo
+	head := f.ref
+	return func() tea.Msg {
+		msg := FileChangesMsg{head: head.ID, base: base.Name().Short()}
+		r, err := repo.Open()
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		files, since, err := r.ChangedFiles(base.ID, head.ID)
+		if err != nil {
+			f.common.Logger.Debugf("ui: error getting changed files: %v", err)
+			msg.err = err
+			return msg
+		}
+		msg.since = since
+		// Flag the committed files that match the .gitignore files of the
+		// reference, deleted files don't matter.
+		paths := make([]string, 0, len(files))
+		for _, cf := range files {
+			if cf.Status != "D" {
+				paths = append(paths, cf.Path)
+			}
+		}
+		ignored, err := r.IgnoredFiles(head.ID, paths...)
+		if err != nil {
+			f.common.Logger.Debugf("ui: error matching ignored files: %v", err)
+		}
+		msg.items = make([]selector.IdentifiableItem, len(files))
+		for i, cf := range files {
+			msg.items[i] = ChangedFileItem{ChangedFile: cf, Ignored: cf.Status != "D" && ignored[cf.Path]}
+		}
+		return msg
+	}
+}
+
+// changeDiffCmd loads the diff of a changed file since the commit the
+// changes are relative to.
+func (f *Files) changeDiffCmd(file git.ChangedFile) tea.Cmd {
+	repo := f.repo
+	head := f.ref
+	since := f.changesSince
+	return func() tea.Msg {
+		msg := FileChangeDiffMsg{head: head.ID, file: file}
+		r, err := repo.Open()
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		paths := []string{file.Path}
+		if file.OldPath != "" {
+			paths = append(paths, file.OldPath)
+		}
+		msg.diff, msg.err = r.DiffPaths(since, head.ID, paths...)
+		return msg
+	}
+}
+
+// renderChangeDiff renders the diff of a changed file with the diff renderer
+// of the log.
+func (f *Files) renderChangeDiff(msg FileChangeDiffMsg) string {
+	title := f.common.Styles.Log.CommitHash.Render(
+		fmt.Sprintf("%s %s → %s", ChangedFileItem{ChangedFile: msg.file}.Title(), f.changesBase, f.refName()))
+	if len(msg.diff.Files) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, title, "", "The file is the same.")
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		"",
+		renderSummary(msg.diff, f.common.Styles, f.common.Width),
+		renderDiff(msg.diff, f.common.Styles, f.common.Width),
+	)
+}
@@ -11,6 +11,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/lfs"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
 	"github.com/dustin/go-humanize"
 )
@@ -18,10 +20,31 @@ import (
 // FileItem is a list item for a file.
 type FileItem struct {
 	entry *git.TreeEntry
+
+	// vendored is true if the file is marked as vendored or generated in
+	// .gitattributes.
+	vendored bool
+
+	// submodule is the submodule of a gitlink entry, if it's configured in
+	// .gitmodules.
+	submodule *proto.Submodule
+
+	// pointer is the LFS pointer the file is, if any.
+	pointer *lfs.Pointer
+
+	// Tree view fields. path is relative to the directory the tree is rooted
+	// at.
+	path     string
+	depth    int
+	tree     bool
+	expanded bool
 }
 
 // ID returns the ID of the file item.
 func (i FileItem) ID() string {
+	if i.path != "" {
+		return i.path
+	}
 	return i.entry.Name()
 }
 
@@ -30,6 +53,44 @@ func (i FileItem) Title() string {
 	return common.UnquoteFilename(i.entry.Name())
 }
 
+// Pin returns the pinned commit and the status of a submodule item.
+func (i FileItem) Pin() string {
+	if !i.entry.IsCommit() {
+		return ""
+	}
+	pin := "@ " + i.entry.ID().String()[:7]
+	if i.submodule != nil {
+		if status := i.submodule.Status(); status != "" {
+			pin += " · " + status
+		}
+	}
+	return pin
+}
+
+// Kind returns the kind of the file item.
+func (i FileItem) Kind() common.FileKind {
+	switch {
+	case i.entry.IsTree():
+		return common.FileKindDir
+	case i.entry.IsCommit():
+		return common.FileKindSubmodule
+	case i.entry.IsSymlink():
+		return common.FileKindSymlink
+	case i.entry.IsExec():
+		return common.FileKindExec
+	}
+	return common.FileKindFile
+}
+
+// Size returns the size of the file, or of the file it points to for LFS
+// pointers.
+func (i FileItem) Size() int64 {
+	if i.pointer != nil {
+		return i.pointer.Size
+	}
+	return i.entry.Size()
+}
+
 // Description returns the description of the file item.
 func (i FileItem) Description() string {
 	return ""
@@ -67,6 +128,10 @@ func (cl FileItems) Less(i, j int) bool {
 // FileItemDelegate is the delegate for the file item list.
 type FileItemDelegate struct {
 	common *common.Common
+
+	// icons are the icons shown before the names of files, see
+	// common.FileIcon.
+	icons string
 }
 
 // Height returns the height of the file item list. Implements list.ItemDelegate.
@@ -100,16 +165,33 @@ func (d FileItemDelegate) Render(w io.Writer, m list.Model, index int, listItem
 
 	s := d.common.Styles.Tree
 
-	name := i.Title()
-	size := humanize.Bytes(uint64(i.entry.Size()))
+	name := common.FileIcon(d.icons, i.entry.Name(), i.Kind()) + i.Title()
+	if i.tree {
+		marker := "  "
+		if i.entry.IsTree() {
+			marker = "▸ "
+			if i.expanded {
+				marker = "▾ "
+			}
+		}
+		name = strings.Repeat("  ", i.depth) + marker + name
+	}
+	size := humanize.Bytes(uint64(i.Size()))
 	size = strings.ReplaceAll(size, " ", "")
 	sizeLen := lipgloss.Width(size)
+	if pin := i.Pin(); pin != "" {
+		size = strings.Repeat(" ", sizeLen)
+		name += " " + pin
+	}
+	if i.pointer != nil {
+		name += " · LFS"
+	}
 	if i.entry.IsTree() {
 		size = strings.Repeat(" ", sizeLen)
 		if index == m.Index() {
-			name = s.Active.FileDir.Render(name)
+			name = s.Active.FileDir.Faint(i.vendored).Render(name)
 		} else {
-			name = s.Normal.FileDir.Render(name)
+			name = s.Normal.FileDir.Faint(i.vendored).Render(name)
 		}
 	}
 	var nameStyle, sizeStyle, modeStyle lipgloss.Style
@@ -136,7 +218,7 @@ func (d FileItemDelegate) Render(w io.Writer, m list.Model, index int, listItem
 		nameStyle.GetMarginLeft() +
 		sizeStyle.GetHorizontalFrameSize()
 	name = common.TruncateString(name, m.Width()-leftMargin)
-	name = nameStyle.Render(name)
+	name = nameStyle.Faint(i.vendored).Render(name)
 	size = sizeStyle.Render(size)
 	modeStr := modeStyle.Render(mode.String())
 	truncate := d.common.Renderer.NewStyle().MaxWidth(m.Width() -
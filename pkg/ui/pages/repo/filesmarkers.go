@@ -0,0 +1,194 @@
+package repo
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/ui/components/code"
+	"github.com/charmbracelet/soft-serve/pkg/ui/components/selector"
+)
+
+var (
+	showMarkers = key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "list markers"),
+	)
+	nextConflict = key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "next conflict"),
+	)
+	prevConflict = key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "previous conflict"),
+	)
+
+	// conflictKeys sums up the conflict keys in the short help.
+	conflictKeys = key.NewBinding(
+		key.WithKeys("n", "N"),
+		key.WithHelp("n/N", "conflict"),
+	)
+)
+
+// MarkerItem is a list item for a line of a file with a marker, like a TODO
+// comment.
+type MarkerItem struct {
+	// Line is the line of the marker, one based.
+	Line int
+	// Text is the content of the line.
+	Text string
+}
+
+// ID implements selector.IdentifiableItem.
+func (i MarkerItem) ID() string {
+	return strconv.Itoa(i.Line)
+}
+
+// Title implements list.DefaultItem.
+func (i MarkerItem) Title() string {
+	return i.Text
+}
+
+// Description implements list.DefaultItem.
+func (i MarkerItem) Description() string {
+	return ""
+}
+
+// FilterValue implements list.Item.
+func (i MarkerItem) FilterValue() string { return i.Text }
+
+// MarkerItemDelegate is the delegate for the markers list.
+type MarkerItemDelegate struct {
+	common *common.Common
+}
+
+// Height implements list.ItemDelegate.
+func (d MarkerItemDelegate) Height() int { return 1 }
+
+// Spacing implements list.ItemDelegate.
+func (d MarkerItemDelegate) Spacing() int { return 0 }
+
+// Update implements list.ItemDelegate.
+func (d MarkerItemDelegate) Update(tea.Msg, *list.Model) tea.Cmd { return nil }
+
+// Render implements list.ItemDelegate.
+func (d MarkerItemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(MarkerItem)
+	if !ok {
+		return
+	}
+
+	s := d.common.Styles.Tree
+	nameStyle := s.Normal.FileName
+	selector := s.Selector.Render(" ")
+	if index == m.Index() {
+		nameStyle = s.Active.FileName
+		selector = s.Selector.Render(">")
+	}
+
+	// Pad the line numbers to the widest one of the list.
+	digits := 1
+	if items := m.Items(); len(items) > 0 {
+		if last, ok := items[len(items)-1].(MarkerItem); ok {
+			digits = len(strconv.Itoa(last.Line))
+		}
+	}
+	line := s.Normal.FileSize.Render(fmt.Sprintf("%*d", digits, i.Line))
+	width := m.Width() - lipgloss.Width(selector) - lipgloss.Width(line) - 2 - nameStyle.GetHorizontalFrameSize()
+	fmt.Fprint(w, //nolint:errcheck
+		d.common.Zone.Mark(
+			i.ID(),
+			selector+" "+line+" "+nameStyle.Render(common.TruncateString(i.Text, width)),
+		),
+	)
+}
+
+// markerPatterns returns the compiled patterns of the file markers of the
+// config.
+func markerPatterns(cfg *config.Config) []*regexp.Regexp {
+	patterns := config.DefaultFileMarkers
+	if cfg != nil && len(cfg.UI.FileMarkers) > 0 {
+		patterns = cfg.UI.FileMarkers
+	}
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		// The patterns are validated with the config.
+		if re, err := regexp.Compile(p); err == nil {
+			res = append(res, re)
+		}
+	}
+	return res
+}
+
+// findMarkers returns the lines of the content that match one of the
+// patterns.
+func findMarkers(content string, patterns []*regexp.Regexp) []selector.IdentifiableItem {
+	if len(patterns) == 0 || content == "" {
+		return nil
+	}
+	var items []selector.IdentifiableItem
+	for n, line := range strings.Split(content, "\n") {
+		for _, re := range patterns {
+			if re.MatchString(line) {
+				items = append(items, MarkerItem{
+					Line: n + 1,
+					Text: strings.TrimSpace(strings.ReplaceAll(line, "\t", " ")),
+				})
+				break
+			}
+		}
+	}
+	return items
+}
+
+// setMarkers lists the markers and the merge conflicts of the content of the
+// current file. Binary files and LFS pointers have none.
+func (f *Files) setMarkers() tea.Cmd {
+	var items []selector.IdentifiableItem
+	f.conflicts = nil
+	f.conflict = -1
+	if c := f.currentContent; c.binary == nil && c.pointer == nil {
+		items = findMarkers(c.content, f.markerPatterns)
+		f.conflicts = code.FindConflicts(c.content)
+	}
+	f.markerCount = len(items)
+	f.markers.Select(0)
+	return f.markers.SetItems(items)
+}
+
+// markersHeader returns the line shown above the markers.
+func (f *Files) markersHeader() string {
+	title := fmt.Sprintf("Markers in %s", f.path)
+	return f.common.Styles.Log.CommitHash.Render(common.TruncateString(title, f.common.Width))
+}
+
+// hasConflicts returns whether the current file is shown with the merge
+// conflicts it has.
+func (f *Files) hasConflicts() bool {
+	return len(f.conflicts) > 0 && !f.isPreview()
+}
+
+// gotoConflict scrolls to the merge conflict after the current one, or
+// before it when dir is negative, wrapping around the file.
+func (f *Files) gotoConflict(dir int) tea.Cmd {
+	n := len(f.conflicts)
+	switch {
+	case f.conflict < 0 && dir < 0:
+		f.conflict = n - 1
+	case f.conflict < 0:
+		f.conflict = 0
+	default:
+		f.conflict = (f.conflict + dir + n) % n
+	}
+	f.code.ClearSelection()
+	f.code.GotoLine(f.conflicts[f.conflict].Start)
+	return statusCmd(fmt.Sprintf("Conflict %d/%d", f.conflict+1, n))
+}
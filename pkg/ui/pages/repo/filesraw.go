@@ -0,0 +1,29 @@
+package repo
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var rawView = key.NewBinding(
+	key.WithKeys("r"),
+	key.WithHelp("r", "toggle raw view"),
+)
+
+// isPreview returns whether the current file is shown as rendered markdown.
+func (f *Files) isPreview() bool {
+	return f.code.UseGlamour && !f.code.Raw
+}
+
+// toggleRaw switches the current file between its rendered and raw views.
+// The raw view shows the file as it's stored, unstyled, for the files the
+// highlighter or the markdown renderer get wrong. It only lasts until another
+// file is opened.
+func (f *Files) toggleRaw() tea.Cmd {
+	f.code.Raw = !f.code.Raw
+	f.code.ClearSelection()
+	y := f.code.YOffset
+	cmd := f.code.SetContent(f.currentContent.content, f.currentContent.ext)
+	f.code.SetYOffset(y)
+	return cmd
+}
@@ -0,0 +1,146 @@
+package repo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+)
+
+var showForks = key.NewBinding(
+	key.WithKeys("F"),
+	key.WithHelp("F", "forks"),
+)
+
+// OpenRepoMsg is a message to open another repository, like the parent or a
+// fork of the current one.
+type OpenRepoMsg struct {
+	Repo string
+}
+
+// forkInfo is the repository the selected repository was forked from and its
+// forks, the ones the user can read. They're shown in the place of the tabs.
+type forkInfo struct {
+	parent string
+	forks  []string
+	// show is true while the view is open.
+	show   bool
+	cursor int
+}
+
+// repos returns the repositories of the view, the parent first.
+func (f forkInfo) repos() []string {
+	repos := make([]string, 0, len(f.forks)+1)
+	if f.parent != "" {
+		repos = append(repos, f.parent)
+	}
+	return append(repos, f.forks...)
+}
+
+// forkInfo returns the parent and the forks of the selected repository.
+func (r *Repo) forkInfo() forkInfo {
+	var f forkInfo
+	be := r.common.Backend()
+	if be == nil || r.selectedRepo == nil {
+		return f
+	}
+	ctx := r.common.Context()
+	name := r.selectedRepo.Name()
+	readable := func(repo string) bool {
+		return be.AccessLevelByPublicKey(ctx, repo, r.common.PublicKey()) >= access.ReadOnlyAccess
+	}
+	if parent, err := be.ForkParent(ctx, name); err != nil {
+		r.common.Logger.Debugf("ui: failed to get fork parent: %v", err)
+	} else if parent != "" && readable(parent) {
+		f.parent = parent
+	}
+	forks, err := be.Forks(ctx, name)
+	if err != nil {
+		r.common.Logger.Debugf("ui: failed to get forks: %v", err)
+	}
+	for _, fork := range forks {
+		if readable(fork) {
+			f.forks = append(f.forks, fork)
+		}
+	}
+	return f
+}
+
+// forkView returns where the selected repository was forked from and how many
+// forks it has, shown with the metadata of the repository.
+func (r *Repo) forkView() string {
+	var parts []string
+	if r.fork.parent != "" {
+		parts = append(parts, "Forked from "+r.fork.parent)
+	}
+	switch n := len(r.fork.forks); n {
+	case 0:
+	case 1:
+		parts = append(parts, "1 fork")
+	default:
+		parts = append(parts, fmt.Sprintf("%d forks", n))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// updateForks handles the keys of the forks view. Selecting a repository
+// opens it.
+func (r *Repo) updateForks(msg tea.KeyMsg) tea.Cmd {
+	repos := r.fork.repos()
+	switch {
+	case key.Matches(msg, r.common.KeyMap.Back), key.Matches(msg, showForks):
+		r.fork.show = false
+	case key.Matches(msg, r.common.KeyMap.Up):
+		if r.fork.cursor > 0 {
+			r.fork.cursor--
+		}
+	case key.Matches(msg, r.common.KeyMap.Down):
+		if r.fork.cursor < len(repos)-1 {
+			r.fork.cursor++
+		}
+	case key.Matches(msg, r.common.KeyMap.Select):
+		r.fork.show = false
+		name := repos[r.fork.cursor]
+		return func() tea.Msg {
+			return OpenRepoMsg{Repo: name}
+		}
+	}
+	return nil
+}
+
+// forksHelp returns the keys of the forks view.
+func (r *Repo) forksHelp() []key.Binding {
+	back := r.common.KeyMap.Back
+	back.SetHelp("esc", "back")
+	sel := r.common.KeyMap.Select
+	sel.SetHelp("enter", "open")
+	return []key.Binding{back, r.common.KeyMap.UpDown, sel}
+}
+
+// forksView returns the parent of the selected repository followed by its
+// forks.
+func (r *Repo) forksView() string {
+	st := r.common.Styles.RepoSelector
+	width := r.common.Width - r.common.Styles.Repo.Body.GetHorizontalFrameSize()
+	var sb strings.Builder
+	sb.WriteString(r.common.Styles.Log.CommitHash.Render(
+		common.TruncateString("Forks of "+r.selectedRepo.Name(), width)))
+	sb.WriteString("\n")
+	for i, name := range r.fork.repos() {
+		line := name
+		if i == 0 && r.fork.parent != "" {
+			line += " (parent)"
+		}
+		line = common.TruncateString(line, width-2)
+		sb.WriteString("\n")
+		if i == r.fork.cursor {
+			sb.WriteString(st.Active.Title.Render("> " + line))
+		} else {
+			sb.WriteString(st.Normal.Desc.Render("  " + line))
+		}
+	}
+	return sb.String()
+}
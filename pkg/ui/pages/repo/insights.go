@@ -0,0 +1,535 @@
+package repo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/ui/components/viewport"
+	"github.com/dustin/go-humanize"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// insightsWeeks is the number of weeks of commit activity shown in the
+// sparkline, fewer when the terminal is narrower.
+const insightsWeeks = 52
+
+// maxContributors is the number of top contributors shown in the insights.
+const maxContributors = 5
+
+// sparkBlocks are the blocks of the sparkline, from the lowest to the
+// highest.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// activityCache caches the commit activity of references keyed by
+// repository path, commit, and day, since the weeks of the activity end on
+// the day it's computed.
+var activityCache, _ = lru.New[string, *insightsActivity](100)
+
+// insightsPiece is a piece of the insights. The pieces load on their own.
+type insightsPiece int
+
+const (
+	insightsActivityPiece insightsPiece = 1 << iota
+	insightsLanguagesPiece
+	insightsBranchesPiece
+	insightsReleasePiece
+
+	insightsAllPieces = insightsActivityPiece | insightsLanguagesPiece |
+		insightsBranchesPiece | insightsReleasePiece
+)
+
+// insightsActivity is the commit activity of a reference, merges aside.
+type insightsActivity struct {
+	// weeks are the numbers of commits of the last weeks, the oldest first.
+	weeks []int
+	// total is the number of commits.
+	total int
+	// contributors are the authors with the most commits, the most first.
+	contributors []insightsContributor
+}
+
+// insightsContributor is an author and their number of commits.
+type insightsContributor struct {
+	name    string
+	commits int
+}
+
+// InsightsActivityMsg is a message that contains the commit activity of a
+// reference.
+type InsightsActivityMsg struct {
+	ref      string
+	activity *insightsActivity
+	err      error
+}
+
+// InsightsLanguagesMsg is a message that contains the languages of a
+// reference.
+type InsightsLanguagesMsg struct {
+	ref       string
+	languages []common.Language
+	err       error
+}
+
+// InsightsBranchesMsg is a message that contains the number of branches of a
+// repository, and of the ones that aren't merged into a reference.
+type InsightsBranchesMsg struct {
+	ref       string
+	branches  int
+	notMerged int
+	err       error
+}
+
+// InsightsReleaseMsg is a message that contains the latest release of a
+// repository, nil if it has none.
+type InsightsReleaseMsg struct {
+	ref     string
+	release *git.Release
+	err     error
+}
+
+// Insights is a component that shows an overview of a repository: its commit
+// activity, top contributors, languages, branches, and latest release.
+type Insights struct {
+	common  common.Common
+	vp      *viewport.Viewport
+	spinner spinner.Model
+	repo    proto.Repository
+	ref     *git.Reference
+
+	// loaded are the pieces that are loaded, failed the ones that failed to
+	// load.
+	loaded    insightsPiece
+	failed    insightsPiece
+	activity  *insightsActivity
+	languages []common.Language
+	branches  int
+	notMerged int
+	release   *git.Release
+}
+
+// NewInsights creates a new insights model.
+func NewInsights(common common.Common) *Insights {
+	s := spinner.New(spinner.WithSpinner(spinner.Dot),
+		spinner.WithStyle(common.Styles.Spinner))
+	return &Insights{
+		common:  common,
+		vp:      viewport.New(common),
+		spinner: s,
+	}
+}
+
+// Path implements common.TabComponent.
+func (i *Insights) Path() string {
+	return ""
+}
+
+// TabName returns the name of the tab.
+func (i *Insights) TabName() string {
+	return "Insights"
+}
+
+// Hidden implements hiddenTab. Empty repositories have no insights.
+func (i *Insights) Hidden(repo proto.Repository) bool {
+	r, err := repo.Open()
+	if err != nil {
+		return true
+	}
+	bs, _ := r.Branches()
+	return len(bs) == 0
+}
+
+// SetSize implements common.Component.
+func (i *Insights) SetSize(width, height int) {
+	i.common.SetSize(width, height)
+	i.vp.SetSize(width, height)
+	i.render()
+}
+
+// ShortHelp implements help.KeyMap.
+func (i *Insights) ShortHelp() []key.Binding {
+	return []key.Binding{
+		i.common.KeyMap.UpDown,
+	}
+}
+
+// FullHelp implements help.KeyMap.
+func (i *Insights) FullHelp() [][]key.Binding {
+	k := i.vp.KeyMap
+	return [][]key.Binding{
+		{
+			k.PageDown,
+			k.PageUp,
+			k.HalfPageDown,
+			k.HalfPageUp,
+		},
+		{
+			k.Down,
+			k.Up,
+			i.common.KeyMap.GotoTop,
+			i.common.KeyMap.GotoBottom,
+		},
+	}
+}
+
+// StatusBarValue implements statusbar.StatusBar.
+func (i *Insights) StatusBarValue() string {
+	return " "
+}
+
+// StatusBarInfo implements statusbar.StatusBar.
+func (i *Insights) StatusBarInfo() string {
+	return fmt.Sprintf("☰ %.f%%", i.vp.ScrollPercent()*100)
+}
+
+// SpinnerID implements common.TabComponent.
+func (i *Insights) SpinnerID() int {
+	return i.spinner.ID()
+}
+
+// Init implements tea.Model.
+func (i *Insights) Init() tea.Cmd {
+	i.loaded, i.failed = 0, 0
+	i.activity = nil
+	i.languages = nil
+	i.release = nil
+	i.vp.GotoTop()
+	i.render()
+	if i.repo == nil || i.ref == nil {
+		return nil
+	}
+	return tea.Batch(
+		i.spinner.Tick,
+		i.activityCmd(),
+		i.languagesCmd(),
+		i.branchesCmd(),
+		i.releaseCmd(),
+	)
+}
+
+// Update implements tea.Model.
+func (i *Insights) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	cmds := make([]tea.Cmd, 0)
+	switch msg := msg.(type) {
+	case RepoMsg:
+		i.repo = msg
+	case RefMsg:
+		i.ref = msg
+		cmds = append(cmds, i.Init())
+	case EmptyRepoMsg:
+		i.ref = nil
+		cmds = append(cmds, i.Init())
+	case tea.WindowSizeMsg:
+		i.SetSize(msg.Width, msg.Height)
+	case spinner.TickMsg:
+		if i.loaded != insightsAllPieces && i.spinner.ID() == msg.ID {
+			s, cmd := i.spinner.Update(msg)
+			i.spinner = s
+			i.render()
+			if cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+	case InsightsActivityMsg:
+		if i.isRef(msg.ref) {
+			i.activity = msg.activity
+			i.setLoaded(insightsActivityPiece, msg.err)
+		}
+	case InsightsLanguagesMsg:
+		if i.isRef(msg.ref) {
+			i.languages = msg.languages
+			i.setLoaded(insightsLanguagesPiece, msg.err)
+		}
+	case InsightsBranchesMsg:
+		if i.isRef(msg.ref) {
+			i.branches, i.notMerged = msg.branches, msg.notMerged
+			i.setLoaded(insightsBranchesPiece, msg.err)
+		}
+	case InsightsReleaseMsg:
+		if i.isRef(msg.ref) {
+			i.release = msg.release
+			i.setLoaded(insightsReleasePiece, msg.err)
+		}
+	}
+	vp, cmd := i.vp.Update(msg)
+	i.vp = vp.(*viewport.Viewport)
+	if cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	return i, tea.Batch(cmds...)
+}
+
+// View implements tea.Model.
+func (i *Insights) View() string {
+	return i.vp.View()
+}
+
+// isRef returns true if the given commit is the one of the reference the
+// insights are shown for.
+func (i *Insights) isRef(id string) bool {
+	return i.ref != nil && i.ref.ID == id
+}
+
+// setLoaded marks a piece as loaded, or failed to load when err isn't nil,
+// and renders the insights again.
+func (i *Insights) setLoaded(p insightsPiece, err error) {
+	i.loaded |= p
+	if err != nil {
+		i.common.Logger.Debugf("ui: error loading insights: %v", err)
+		i.failed |= p
+	}
+	i.render()
+}
+
+// render renders the insights in the viewport. The pieces that aren't loaded
+// yet show a placeholder.
+func (i *Insights) render() {
+	if i.ref == nil {
+		i.vp.SetContent("")
+		return
+	}
+
+	st := i.common.Styles.Insights
+	width := i.common.Width - st.Base.GetHorizontalFrameSize()
+	section := func(title string, p insightsPiece, body func() string) string {
+		var s string
+		switch {
+		case i.failed&p != 0:
+			s = st.Placeholder.Render("unavailable")
+		case i.loaded&p == 0:
+			s = st.Placeholder.Render(i.spinner.View() + " loading…")
+		default:
+			s = body()
+		}
+		return st.Base.Render(st.Title.Render(title) + "\n" + s)
+	}
+
+	sections := []string{
+		section("Commit activity", insightsActivityPiece, func() string {
+			return i.activityView(width)
+		}),
+		section("Top contributors", insightsActivityPiece, func() string {
+			return i.contributorsView(width)
+		}),
+	}
+
+	// The languages are as wide as the component, like in the readme.
+	langs := st.Base.Render(st.Title.Render("Languages"))
+	switch {
+	case i.failed&insightsLanguagesPiece != 0:
+		langs += "\n" + st.Base.Render(st.Placeholder.Render("unavailable"))
+	case i.loaded&insightsLanguagesPiece == 0:
+		langs += "\n" + st.Base.Render(st.Placeholder.Render(i.spinner.View()+" loading…"))
+	case len(i.languages) == 0:
+		langs += "\n" + st.Base.Render(st.Label.Render("No languages detected"))
+	default:
+		langs += "\n" + strings.TrimSuffix(renderLanguages(i.common, i.languages), "\n")
+	}
+	sections = append(sections, langs,
+		section("Branches", insightsBranchesPiece, func() string {
+			s := st.Value.Render(plural(i.branches, "branch", "branches"))
+			if i.notMerged > 0 {
+				s += st.Label.Render(fmt.Sprintf(" · %d not merged into %s", i.notMerged, i.ref.Name().Short()))
+			}
+			return common.TruncateString(s, width)
+		}),
+		section("Latest release", insightsReleasePiece, func() string {
+			if i.release == nil {
+				return st.Label.Render("No releases")
+			}
+			return common.TruncateString(st.Value.Render(i.release.Name().Short())+
+				st.Label.Render(" · "+humanize.Time(i.release.Date)), width)
+		}),
+	)
+
+	i.vp.SetContent(strings.Join(sections, "\n\n"))
+}
+
+// activityView renders the commit activity as a sparkline of the commits per
+// week, as many weeks as fit the width.
+func (i *Insights) activityView(width int) string {
+	st := i.common.Styles.Insights
+	a := i.activity
+	weeks := a.weeks[max(0, len(a.weeks)-width):]
+	peak, recent := 0, 0
+	for _, n := range weeks {
+		peak = max(peak, n)
+		recent += n
+	}
+	var spark strings.Builder
+	for _, n := range weeks {
+		b := 0
+		if peak > 0 {
+			b = int(math.Ceil(float64(n) / float64(peak) * float64(len(sparkBlocks)-1)))
+		}
+		spark.WriteRune(sparkBlocks[b])
+	}
+	return st.Spark.Render(spark.String()) + "\n" +
+		common.TruncateString(st.Label.Render(fmt.Sprintf("%s in the last %s · %d in total",
+			plural(recent, "commit", "commits"), plural(len(weeks), "week", "weeks"), a.total)), width)
+}
+
+// contributorsView renders the top contributors and their number of commits.
+func (i *Insights) contributorsView(width int) string {
+	st := i.common.Styles.Insights
+	cs := i.activity.contributors
+	if len(cs) == 0 {
+		return st.Label.Render("No contributors")
+	}
+	nameWidth := 0
+	for _, c := range cs {
+		nameWidth = max(nameWidth, lipgloss.Width(c.name))
+	}
+	nameWidth = min(nameWidth, width/2)
+	lines := make([]string, len(cs))
+	for j, c := range cs {
+		name := common.TruncateString(c.name, nameWidth)
+		name += strings.Repeat(" ", nameWidth-lipgloss.Width(name))
+		share := float64(c.commits) / float64(max(1, i.activity.total)) * 100
+		lines[j] = common.TruncateString(st.Value.Render(name)+
+			st.Label.Render(fmt.Sprintf("  %s (%.0f%%)", plural(c.commits, "commit", "commits"), share)), width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// plural returns the number followed by the singular or plural noun.
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return "1 " + singular
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// activityCmd loads the commit activity of the reference.
+func (i *Insights) activityCmd() tea.Cmd {
+	repo, ref := i.repo, i.ref
+	return func() tea.Msg {
+		rr, err := repo.Open()
+		if err != nil {
+			return InsightsActivityMsg{ref: ref.ID, err: err}
+		}
+		a, err := commitActivity(rr, ref, time.Now())
+		return InsightsActivityMsg{ref: ref.ID, activity: a, err: err}
+	}
+}
+
+// languagesCmd loads the languages of the reference.
+func (i *Insights) languagesCmd() tea.Cmd {
+	repo, ref := i.repo, i.ref
+	return func() tea.Msg {
+		rr, err := repo.Open()
+		if err != nil {
+			return InsightsLanguagesMsg{ref: ref.ID, err: err}
+		}
+		langs, err := common.Languages(rr, ref)
+		return InsightsLanguagesMsg{ref: ref.ID, languages: langs, err: err}
+	}
+}
+
+// branchesCmd counts the branches, and the ones that aren't merged into the
+// reference.
+func (i *Insights) branchesCmd() tea.Cmd {
+	repo, ref := i.repo, i.ref
+	return func() tea.Msg {
+		rr, err := repo.Open()
+		if err != nil {
+			return InsightsBranchesMsg{ref: ref.ID, err: err}
+		}
+		bs, err := rr.Branches()
+		if err != nil {
+			return InsightsBranchesMsg{ref: ref.ID, err: err}
+		}
+		nm, err := rr.BranchesNotMerged(ref.ID)
+		if err != nil {
+			return InsightsBranchesMsg{ref: ref.ID, err: err}
+		}
+		return InsightsBranchesMsg{ref: ref.ID, branches: len(bs), notMerged: len(nm)}
+	}
+}
+
+// releaseCmd loads the latest release.
+func (i *Insights) releaseCmd() tea.Cmd {
+	repo, ref := i.repo, i.ref
+	return func() tea.Msg {
+		rr, err := repo.Open()
+		if err != nil {
+			return InsightsReleaseMsg{ref: ref.ID, err: err}
+		}
+		rels, err := rr.Releases()
+		if err != nil {
+			return InsightsReleaseMsg{ref: ref.ID, err: err}
+		}
+		msg := InsightsReleaseMsg{ref: ref.ID}
+		if len(rels) > 0 {
+			msg.release = rels[0]
+		}
+		return msg
+	}
+}
+
+// commitActivity returns the commit activity of the given ref in the weeks up
+// to now, with the authors mapped with the mailmap of the ref. The result is
+// cached per commit and day.
+func commitActivity(r *git.Repository, ref *git.Reference, now time.Time) (*insightsActivity, error) {
+	key := r.Path + "@" + ref.ID + "@" + now.Format(time.DateOnly)
+	if a, ok := activityCache.Get(key); ok {
+		return a, nil
+	}
+
+	authors, err := r.CommitAuthors(ref)
+	if err != nil {
+		return nil, err
+	}
+	mm, err := r.Mailmap(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &insightsActivity{
+		weeks: make([]int, insightsWeeks),
+		total: len(authors),
+	}
+	commits := map[string]*insightsContributor{}
+	for _, c := range authors {
+		if w := max(0, int(now.Sub(c.When)/(7*24*time.Hour))); w < insightsWeeks {
+			a.weeks[insightsWeeks-1-w]++
+		}
+		name, email := mm.Map(c.Name, c.Email)
+		id := strings.ToLower(email)
+		if id == "" {
+			id = name
+		}
+		if name == "" {
+			name = email
+		}
+		if ic, ok := commits[id]; ok {
+			ic.commits++
+		} else {
+			commits[id] = &insightsContributor{name: name, commits: 1}
+		}
+	}
+	for _, c := range commits {
+		a.contributors = append(a.contributors, *c)
+	}
+	sort.Slice(a.contributors, func(i, j int) bool {
+		if a.contributors[i].commits != a.contributors[j].commits {
+			return a.contributors[i].commits > a.contributors[j].commits
+		}
+		return a.contributors[i].name < a.contributors[j].name
+	})
+	if len(a.contributors) > maxContributors {
+		a.contributors = a.contributors[:maxContributors]
+	}
+
+	activityCache.Add(key, a)
+	return a, nil
+}
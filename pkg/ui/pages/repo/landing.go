@@ -0,0 +1,93 @@
+package repo
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/ui/components/tabs"
+)
+
+// SetLinkTab sets the tab the next repository opens on in place of its
+// landing tab, e.g. the tab of a link to the repository.
+func (r *Repo) SetLinkTab(tab string) {
+	r.linkTab = tab
+	r.linkExact = false
+}
+
+// SetRestoreTab is like SetLinkTab, the tab is opened even if it has nothing
+// to show, e.g. to go back to it.
+func (r *Repo) SetRestoreTab(tab string) {
+	r.linkTab = tab
+	r.linkExact = true
+}
+
+// landingTabCmd switches to the linked tab, or to the landing tab of the
+// repository if it has one. Repositories open on the first tab otherwise.
+func (r *Repo) landingTabCmd(repo proto.Repository) tea.Cmd {
+	linked, exact := r.linkTab, r.linkExact
+	r.linkTab, r.linkExact = "", false
+	be := r.common.Backend()
+	if be == nil || repo == nil {
+		return nil
+	}
+	ctx := r.common.Context()
+	names := make([]string, len(r.panes))
+	for i, p := range r.panes {
+		names[i] = p.TabName()
+	}
+	return func() tea.Msg {
+		tab := linked
+		if tab == "" {
+			var err error
+			tab, err = be.LandingTab(ctx, repo.Name())
+			if err != nil {
+				r.common.Logger.Debugf("ui: failed to get landing tab of %s: %v", repo.Name(), err)
+				return nil
+			}
+		}
+		if tab == "" {
+			return nil
+		}
+		if !exact {
+			tab = availableTab(repo, tab, readmeFile(&r.common, repo))
+		}
+		for i, n := range names {
+			if strings.EqualFold(n, tab) && i > 0 {
+				return tabs.SelectTabMsg(i)
+			}
+		}
+		return nil
+	}
+}
+
+// availableTab returns the given tab if the repository has something to show
+// in it. Empty repositories land on the readme, which shows how to push to
+// them. Repositories without a readme land on the files, and repositories
+// without tags or stash on the readme. readme is the file shown as the readme,
+// if any.
+func availableTab(repo proto.Repository, tab string, readme string) string {
+	r, err := repo.Open()
+	if err != nil {
+		return "readme"
+	}
+	if bs, _ := r.Branches(); len(bs) == 0 {
+		return "readme"
+	}
+	switch tab {
+	case "tags", "releases":
+		if ts, _ := r.Tags(); len(ts) == 0 {
+			return "readme"
+		}
+	case "readme":
+		if rm, _, _ := backend.ReadmeFile(repo, nil, readme); rm == "" {
+			return "files"
+		}
+	case "stash":
+		if !r.HasStash() {
+			return "readme"
+		}
+	}
+	return tab
+}
@@ -0,0 +1,148 @@
+package repo
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/ui/components/tabs"
+)
+
+// Location is where the repository page is at: the tab, the file or
+// directory of the Files tab and the line the file is scrolled to, or the
+// commit whose diff the Commits tab shows.
+type Location struct {
+	Tab    string
+	Path   string
+	Line   int
+	Commit string
+}
+
+// LocationMsg is a message to go to a location of the current repository and
+// reference.
+type LocationMsg Location
+
+// CommitOpenMsg is a message to show the diff of a commit in the Commits tab.
+type CommitOpenMsg struct {
+	hash string
+}
+
+// commitOpenResultMsg is a message that contains the commit that was opened.
+// The commit is nil when it doesn't exist anymore.
+type commitOpenResultMsg struct {
+	hash   string
+	commit *git.Commit
+}
+
+// OpenCommitCmd shows the diff of the commit with the given hash in the
+// Commits tab.
+func OpenCommitCmd(hash string) tea.Cmd {
+	return func() tea.Msg {
+		return CommitOpenMsg{hash: hash}
+	}
+}
+
+// GoToLocationCmd goes to the given location of the current repository and
+// reference.
+func GoToLocationCmd(loc Location) tea.Cmd {
+	return func() tea.Msg {
+		return LocationMsg(loc)
+	}
+}
+
+// goToLocation switches to the tab of the location and opens its file or
+// commit. The Commits tab shows the log when there is no commit.
+func (r *Repo) goToLocation(loc Location) tea.Cmd {
+	for i, p := range r.panes {
+		if !strings.EqualFold(p.TabName(), loc.Tab) {
+			continue
+		}
+		switch p := p.(type) {
+		case *Files:
+			ref := ""
+			if r.ref != nil {
+				ref = r.ref.Name().String()
+			}
+			return OpenFileCmd(ref, loc.Path, loc.Line)
+		case *Log:
+			if loc.Commit != "" {
+				return OpenCommitCmd(loc.Commit)
+			}
+			if p.activeView == logViewDiff {
+				p.activeView = logViewCommits
+				p.selectedCommit = nil
+				p.picker = nil
+			}
+		}
+		return tabs.SelectTabCmd(i)
+	}
+	return nil
+}
+
+// Location returns where the repository page is at. It's false while the
+// active tab is loading what it shows.
+func (r *Repo) Location() (Location, bool) {
+	if r.selectedRepo == nil || r.ref == nil {
+		return Location{}, false
+	}
+	loc := Location{Tab: strings.ToLower(r.TabName())}
+	switch p := r.panes[r.activeTab].(type) {
+	case *Files:
+		switch p.activeView {
+		case filesViewLoading:
+			return loc, false
+		case filesViewContent:
+			loc.Path = filepath.ToSlash(p.path)
+			loc.Line = p.bookmark().Line
+		default:
+			if p.path != "." {
+				loc.Path = filepath.ToSlash(p.path)
+			}
+		}
+	case *Log:
+		switch p.activeView {
+		case logViewLoading:
+			return loc, false
+		case logViewDiff:
+			if p.selectedCommit != nil {
+				loc.Commit = p.selectedCommit.ID.String()
+			}
+		}
+	}
+	return loc, true
+}
+
+// openCommit shows the diff of the opened commit, or the log when it doesn't
+// exist anymore.
+func (l *Log) openCommit(msg commitOpenResultMsg) tea.Cmd {
+	if msg.commit == nil {
+		l.activeView = logViewCommits
+		return statusCmd(fmt.Sprintf("%s no longer exists", msg.hash[:min(7, len(msg.hash))]))
+	}
+	l.selectedCommit = msg.commit
+	l.picker = nil
+	l.selectLoadedCommit(msg.commit)
+	return l.loadDiffCmd
+}
+
+// openCommitCmd loads the commit with the given hash to show its diff.
+func (l *Log) openCommitCmd(hash string) tea.Cmd {
+	repo := l.repo
+	return func() tea.Msg {
+		if repo == nil {
+			return nil
+		}
+		r, err := repo.Open()
+		if err != nil {
+			return common.ErrorMsg(err)
+		}
+		res := commitOpenResultMsg{hash: hash}
+		if c, err := r.CommitByRevision(hash); err == nil {
+			res.commit = c
+		}
+		return res
+	}
+}
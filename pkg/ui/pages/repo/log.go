@@ -2,15 +2,18 @@ package repo
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	gansi "github.com/charmbracelet/glamour/ansi"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
 	"github.com/charmbracelet/soft-serve/pkg/ui/components/footer"
@@ -22,6 +25,61 @@ import (
 
 var waitBeforeLoading = time.Millisecond * 100
 
+var (
+	parentCommit = key.NewBinding(
+		key.WithKeys("["),
+		key.WithHelp("[", "parent"),
+	)
+	childCommit = key.NewBinding(
+		key.WithKeys("]"),
+		key.WithHelp("]", "child"),
+	)
+	pickCommit = key.NewBinding(
+		key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"),
+		key.WithHelp("1-9", "pick"),
+	)
+	moreContext = key.NewBinding(
+		key.WithKeys("+", "="),
+		key.WithHelp("+", "more context"),
+	)
+	lessContext = key.NewBinding(
+		key.WithKeys("-"),
+		key.WithHelp("-", "less context"),
+	)
+	cycleWhitespace = key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "whitespace"),
+	)
+	blameParent = key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "blame parent"),
+	)
+	toggleMessage = key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "message"),
+	)
+	scrollMessage = key.NewBinding(
+		key.WithKeys("ctrl+d", "ctrl+u"),
+		key.WithHelp("ctrl+d/u", "scroll message"),
+	)
+	messageRefs = key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "referenced commit"),
+	)
+	commitTypes = key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "commit types"),
+	)
+	toggleCommitter = key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "committer"),
+	)
+	rawMessage = key.NewBinding(
+		key.WithKeys("M"),
+		key.WithHelp("M", "raw message"),
+	)
+)
+
 type logView int
 
 const (
@@ -36,12 +94,64 @@ type LogCountMsg int64
 // LogItemsMsg is a message that contains a slice of LogItem.
 type LogItemsMsg []selector.IdentifiableItem
 
+// LogStatusesMsg is a message that contains the combined commit status states
+// of the loaded commits keyed by commit hash.
+type LogStatusesMsg map[string]proto.CommitState
+
 // LogCommitMsg is a message that contains a git commit.
 type LogCommitMsg *git.Commit
 
 // LogDiffMsg is a message that contains a git diff.
 type LogDiffMsg *git.Diff
 
+// LogPickerMsg is a message that asks the user to pick one of many commits,
+// or files, to jump to from the diff view.
+type LogPickerMsg struct {
+	title   string
+	commits []*git.Commit
+	files   []messageFile
+}
+
+// LogJumpMsg is a message to show the diff of a commit from another tab, e.g.
+// the blame of a file. The diff is scrolled to the given file.
+type LogJumpMsg struct {
+	commit *git.Commit
+	path   string
+}
+
+// logJump is the state of the log before jumping to a commit from another
+// tab.
+type logJump struct {
+	view    logView
+	commit  *git.Commit
+	diff    *git.Diff
+	yOffset int
+
+	mergeDiff  int
+	resolution *git.MergeResolution
+}
+
+// logPicker is a small list of commits to choose from, e.g. the parents of a
+// merge commit, of references to switch to, or of files to show.
+type logPicker struct {
+	title   string
+	commits []*git.Commit
+	refs    []*git.Reference
+	files   []messageFile
+	cursor  int
+}
+
+// len returns the number of commits, references, or files to choose from.
+func (p *logPicker) len() int {
+	switch {
+	case p.refs != nil:
+		return len(p.refs)
+	case p.files != nil:
+		return len(p.files)
+	}
+	return len(p.commits)
+}
+
 // Log is a model that displays a list of commits and their diffs.
 type Log struct {
 	common         common.Common
@@ -55,8 +165,96 @@ type Log struct {
 	activeCommit   *git.Commit
 	selectedCommit *git.Commit
 	currentDiff    *git.Diff
+	diffOptions    git.DiffOptions
+	picker         *logPicker
 	loadingTime    time.Time
 	spinner        spinner.Model
+
+	// showMessage shows the full message of the active commit below the
+	// list. messageCommit is the commit shown in msgVp. reselect is the index
+	// to select again once the commits are loaded after the list is resized,
+	// or -1.
+	showMessage   bool
+	msgVp         *viewport.Viewport
+	messageCommit *git.Commit
+	reselect      int
+
+	// msgRefs holds the commits referenced by the messages of the commits,
+	// keyed by commit hash. Commits that are being loaded, or that don't
+	// reference other commits, have a nil entry.
+	msgRefs map[string]map[string]*git.Commit
+	// msgFiles holds the files of the trees of the commits their messages
+	// mention, keyed by commit hash.
+	msgFiles map[string][]messageFile
+
+	// identities holds the canonical identities of the co-authors of the
+	// commits, keyed by the identity in the trailer. rawMessage shows the
+	// message of the selected commit as is, without the trailers section.
+	identities map[string]string
+	rawMessage bool
+
+	// columns are the log columns preferred by the user. noBadges holds the
+	// repositories the commit type badges are turned off for.
+	columns  []common.LogColumn
+	noBadges map[string]bool
+
+	// committer shows the committers of the commits instead of their
+	// authors, as preferred by the user.
+	committer bool
+
+	// diffLines maps the rendered lines of the diff view to the lines of the
+	// patch. Lines that aren't part of the patch are -1. split shows the diff
+	// side by side on wide terminals.
+	diffLines []int
+	split     bool
+
+	// hunkLines and fileLines are the rendered lines of the diff view the
+	// hunks and files start at. Until navDiff is scrolled away from
+	// navOffset, navLine is the line the hunk and file keys moved to.
+	hunkLines []int
+	fileLines []int
+	navDiff   *git.Diff
+	navLine   int
+	navOffset int
+
+	// jumps holds the states to go back to after jumping to commits from
+	// other tabs. jumpPath is the file to scroll to once the diff is loaded.
+	// Until the diff is scrolled away from jumpOffset, the jumped to file
+	// starting at patch line jumpLine is the current one.
+	jumps      []logJump
+	jumpPath   string
+	jumpLine   int
+	jumpOffset int
+
+	// filtering is true while the filter is typed in filterInput. query is
+	// the applied filter, typed as queryText, and matches the hashes of the
+	// commits that match it, or nil when the log isn't filtered.
+	filtering   bool
+	filterInput textinput.Model
+	queryText   string
+	query       logQuery
+	matches     []string
+
+	// signatures holds the verified signatures of the commits shown in the
+	// diff view, keyed by commit hash. Unsigned commits have an empty entry.
+	signatures map[string]string
+
+	// contains holds the branches and tags the commits are reachable from,
+	// keyed by commit hash.
+	contains map[string][]*git.Reference
+
+	// contextToggled and whitespaceToggled are true once the user changed
+	// the context lines of the diffs and how they handle whitespace, the
+	// view settings of the repositories don't change them anymore.
+	contextToggled    bool
+	whitespaceToggled bool
+
+	// mergeDiff is the diff of the selected merge commit the diff view
+	// shows, the diff against the parent at that index, or against the
+	// automatic merge of its two parents after the last parent. resolution
+	// holds the conflicts and edits of the merge when it's shown.
+	mergeDiff  int
+	resolution *git.MergeResolution
 }
 
 // NewLog creates a new Log model.
@@ -64,9 +262,21 @@ func NewLog(common common.Common) *Log {
 	l := &Log{
 		common:     common,
 		vp:         viewport.New(common),
+		msgVp:      viewport.New(common),
 		activeView: logViewCommits,
+		reselect:   -1,
+		navLine:    -1,
+		msgRefs:    map[string]map[string]*git.Commit{},
+		msgFiles:   map[string][]messageFile{},
+		identities: map[string]string{},
+		signatures: map[string]string{},
+		contains:   map[string][]*git.Reference{},
+		noBadges:   map[string]bool{},
+		diffOptions: git.DiffOptions{
+			Context: git.DefaultDiffContext,
+		},
 	}
-	selector := selector.New(common, []selector.IdentifiableItem{}, LogItemDelegate{&common})
+	selector := selector.New(common, []selector.IdentifiableItem{}, LogItemDelegate{common: &common, badges: true})
 	selector.SetShowFilter(false)
 	selector.SetShowHelp(false)
 	selector.SetShowPagination(false)
@@ -76,17 +286,36 @@ func NewLog(common common.Common) *Log {
 	selector.DisableQuitKeybindings()
 	selector.KeyMap.NextPage = common.KeyMap.NextPage
 	selector.KeyMap.PrevPage = common.KeyMap.PrevPage
+	if cfg := common.Config(); cfg != nil {
+		selector.SetEmptyMessage(cfg.UI.Empty.Log)
+	}
 	l.selector = selector
+	ti := textinput.New()
+	ti.Prompt = "Filter: "
+	ti.Placeholder = "author:, path:, signed:true, signer:SHA256:…, message"
+	ti.PromptStyle = common.Styles.Log.CommitAuthor
+	ti.PlaceholderStyle = common.Styles.Log.CommitAuthor.Faint(true)
+	l.filterInput = ti
 	s := spinner.New(spinner.WithSpinner(spinner.Dot),
 		spinner.WithStyle(common.Styles.Spinner))
 	l.spinner = s
 	return l
 }
 
+// ShowsRelativeTimes implements common.RelativeTimes. The ages of the
+// commits are only shown in the list.
+func (l *Log) ShowsRelativeTimes() bool {
+	return l.activeView == logViewCommits
+}
+
 // Path implements common.TabComponent.
 func (l *Log) Path() string {
 	switch l.activeView {
 	case logViewCommits:
+		if l.queryText != "" {
+			// Going back clears the filter first.
+			return "filter"
+		}
 		return ""
 	default:
 		return "diff" // XXX: this is a place holder and doesn't mean anything
@@ -101,25 +330,87 @@ func (l *Log) TabName() string {
 // SetSize implements common.Component.
 func (l *Log) SetSize(width, height int) {
 	l.common.SetSize(width, height)
-	l.selector.SetSize(width, height)
 	l.vp.SetSize(width, height)
+	l.filterInput.Width = width - lipgloss.Width(l.filterInput.Prompt) - 1
+	if l.showFilter() {
+		height--
+	}
+	if l.showMessage {
+		mh := messageHeight(height)
+		l.selector.SetSize(width, height-mh-1)
+		l.msgVp.SetSize(width, mh)
+		// Wrap the message to the new width.
+		l.messageCommit = nil
+		l.updateMessage()
+	} else {
+		l.selector.SetSize(width, height)
+	}
+}
+
+// messageHeight returns the height of the commit message below the list.
+func messageHeight(height int) int {
+	return max(height*2/5, 3)
 }
 
 // ShortHelp implements help.KeyMap.
 func (l *Log) ShortHelp() []key.Binding {
+	if l.filtering {
+		return []key.Binding{applyFilter, cancelFilter}
+	}
 	switch l.activeView {
 	case logViewCommits:
 		copyKey := l.common.KeyMap.Copy
 		copyKey.SetHelp("c", "copy hash")
-		return []key.Binding{
+		b := []key.Binding{
 			l.common.KeyMap.UpDown,
 			l.common.KeyMap.SelectItem,
 			copyKey,
+			toggleMessage,
+			commitTypes,
+			l.committerKey(),
+			l.filterKey(),
 		}
+		if l.showMessage {
+			b = append(b, scrollMessage)
+		}
+		return b
 	case logViewDiff:
+		if l.picker != nil {
+			return []key.Binding{
+				l.common.KeyMap.UpDown,
+				l.common.KeyMap.SelectItem,
+				pickCommit,
+				l.common.KeyMap.BackItem,
+			}
+		}
+		if _, _, ok := l.vp.Selection(); ok {
+			copyKey := l.common.KeyMap.Copy
+			copyKey.SetHelp("c", "copy lines")
+			return []key.Binding{
+				l.common.KeyMap.UpDown,
+				copyKey,
+				l.common.KeyMap.Back,
+			}
+		}
 		return []key.Binding{
 			l.common.KeyMap.UpDown,
 			l.common.KeyMap.BackItem,
+			parentCommit,
+			childCommit,
+			messageRefs,
+			messageFiles,
+			containingRefs,
+			rawMessage,
+			l.committerKey(),
+			l.mergeDiffKey(),
+			moreContext,
+			lessContext,
+			cycleWhitespace,
+			l.splitKey(),
+			blameParent,
+			hunkKeys,
+			fileKeys,
+			l.common.KeyMap.SelectLines,
 			l.common.KeyMap.GotoTop,
 			l.common.KeyMap.GotoBottom,
 		}
@@ -132,6 +423,9 @@ func (l *Log) ShortHelp() []key.Binding {
 func (l *Log) FullHelp() [][]key.Binding {
 	k := l.selector.KeyMap
 	b := make([][]key.Binding, 0)
+	if l.filtering {
+		return append(b, []key.Binding{applyFilter, cancelFilter})
+	}
 	switch l.activeView {
 	case logViewCommits:
 		copyKey := l.common.KeyMap.Copy
@@ -146,6 +440,13 @@ func (l *Log) FullHelp() [][]key.Binding {
 				k.CursorUp,
 				k.CursorDown,
 			},
+			{
+				toggleMessage,
+				scrollMessage,
+				commitTypes,
+				l.committerKey(),
+				l.filterKey(),
+			},
 			{
 				k.NextPage,
 				k.PrevPage,
@@ -154,9 +455,49 @@ func (l *Log) FullHelp() [][]key.Binding {
 			},
 		}...)
 	case logViewDiff:
+		if l.picker != nil {
+			b = append(b, []key.Binding{
+				l.common.KeyMap.SelectItem,
+				l.common.KeyMap.BackItem,
+			}, []key.Binding{
+				l.common.KeyMap.Up,
+				l.common.KeyMap.Down,
+				pickCommit,
+			})
+			break
+		}
 		k := l.vp.KeyMap
+		copyKey := l.common.KeyMap.Copy
+		copyKey.SetHelp("c", "copy lines")
 		b = append(b, []key.Binding{
 			l.common.KeyMap.BackItem,
+			parentCommit,
+			childCommit,
+			messageRefs,
+			messageFiles,
+			containingRefs,
+			rawMessage,
+			l.committerKey(),
+			blameParent,
+		}, []key.Binding{
+			l.mergeDiffKey(),
+			moreContext,
+			lessContext,
+			cycleWhitespace,
+			l.splitKey(),
+			l.diffColorsKey(),
+		}, []key.Binding{
+			nextHunk,
+			prevHunk,
+			nextFile,
+			prevFile,
+			firstChange,
+			lastChange,
+		}, []key.Binding{
+			l.common.KeyMap.SelectLines,
+			l.common.KeyMap.SelectUp,
+			l.common.KeyMap.SelectDown,
+			copyKey,
 		})
 		b = append(b, [][]key.Binding{
 			{
@@ -189,6 +530,15 @@ func (l *Log) Init() tea.Cmd {
 	l.count = 0
 	l.activeCommit = nil
 	l.selectedCommit = nil
+	l.picker = nil
+	l.jumps = nil
+	l.jumpPath, l.jumpLine = "", 0
+	l.messageCommit = nil
+	l.reselect = -1
+	l.msgRefs = map[string]map[string]*git.Commit{}
+	l.msgFiles = map[string][]messageFile{}
+	l.identities = map[string]string{}
+	l.matches = nil
 	return tea.Batch(
 		l.countCommitsCmd,
 		// start loading on init
@@ -198,29 +548,87 @@ func (l *Log) Init() tea.Cmd {
 
 // Update implements tea.Model.
 func (l *Log) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && l.filtering {
+		return l, l.updateFilter(msg)
+	}
 	cmds := make([]tea.Cmd, 0)
+	if l.filtering {
+		// Keep the cursor blinking.
+		var cmd tea.Cmd
+		l.filterInput, cmd = l.filterInput.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
 	switch msg := msg.(type) {
 	case RepoMsg:
 		l.repo = msg
+		l.queryText, l.query = "", logQuery{}
+		l.signatures = map[string]string{}
+		l.contains = map[string][]*git.Reference{}
+		l.applyViewSettings(loadViewSettings(l.common, msg))
+		l.stopFilter()
+		l.columns = l.loadColumns()
+		l.committer = l.loadCommitter()
+		l.updateDelegate()
 	case RefMsg:
 		l.ref = msg
 		l.selector.Select(0)
 		cmds = append(cmds, l.Init())
 	case LogCountMsg:
-		l.count = int64(msg)
-		l.selector.SetTotalPages(int(msg))
-		l.selector.SetItems(make([]selector.IdentifiableItem, l.count))
-		cmds = append(cmds, l.updateCommitsCmd)
+		// The count might be of the log before it was filtered.
+		if l.query.isZero() {
+			l.matches = nil
+			cmds = append(cmds, l.setCount(int64(msg)))
+		}
+	case LogMatchesMsg:
+		if !l.query.isZero() {
+			l.matches = msg
+			cmds = append(cmds, l.setCount(int64(len(msg))))
+		}
+	case LogQueryMsg:
+		q, err := parseLogQuery(string(msg))
+		if err != nil {
+			cmds = append(cmds, statusCmd(err.Error()))
+			break
+		}
+		l.stopFilter()
+		cmds = append(cmds, l.setQuery(string(msg), q))
 	case LogItemsMsg:
-		// stop loading after receiving items
-		l.activeView = logViewCommits
+		// stop loading after receiving items, the diff of a commit that was
+		// opened before them stays.
+		if l.activeView != logViewDiff {
+			l.activeView = logViewCommits
+		}
 		cmds = append(cmds, l.selector.SetItems(msg))
 		l.selector.SetPage(l.nextPage)
 		l.SetSize(l.common.Width, l.common.Height)
+		if l.reselect >= 0 {
+			l.selector.Select(l.reselect)
+			l.reselect = -1
+		}
 		i := l.selector.SelectedItem()
 		if i != nil {
 			l.activeCommit = i.(LogItem).Commit
 		}
+		cmds = append(cmds, l.loadStatusesCmd(msg))
+	case LogStatusesMsg:
+		items := l.selector.Items()
+		newItems := make([]selector.IdentifiableItem, len(items))
+		for i, it := range items {
+			li, ok := it.(LogItem)
+			if !ok {
+				continue
+			}
+			if state, ok := msg[li.Hash()]; ok {
+				li.State = state
+			}
+			newItems[i] = li
+		}
+		idx := l.selector.Index()
+		cmds = append(cmds, l.selector.SetItems(newItems))
+		l.selector.SetPage(l.nextPage)
+		l.selector.Select(idx)
 	case tea.KeyMsg, tea.MouseMsg:
 		switch l.activeView {
 		case logViewCommits:
@@ -229,6 +637,25 @@ func (l *Log) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				switch {
 				case key.Matches(kmsg, l.common.KeyMap.SelectItem):
 					cmds = append(cmds, l.selector.SelectItemCmd)
+				case key.Matches(kmsg, toggleMessage):
+					cmds = append(cmds, l.toggleMessage())
+				case key.Matches(kmsg, commitTypes):
+					if l.repo != nil {
+						l.noBadges[l.repo.Name()] = !l.noBadges[l.repo.Name()]
+						l.updateDelegate()
+					}
+				case key.Matches(kmsg, toggleCommitter):
+					l.toggleCommitter()
+				case key.Matches(kmsg, filterLog):
+					return l, l.startFilter()
+				case l.showMessage && key.Matches(kmsg, scrollMessage):
+					if kmsg.String() == "ctrl+d" {
+						l.msgVp.HalfViewDown()
+					} else {
+						l.msgVp.HalfViewUp()
+					}
+					// Don't move the selection while scrolling the message.
+					return l, tea.Batch(cmds...)
 				}
 			}
 			// XXX: This is a hack for loading commits on demand based on
@@ -249,14 +676,99 @@ func (l *Log) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case logViewDiff:
 			switch kmsg := msg.(type) {
 			case tea.KeyMsg:
+				if l.picker != nil {
+					cmds = append(cmds, l.updatePicker(kmsg))
+					// Don't scroll the diff while picking a commit.
+					return l, tea.Batch(cmds...)
+				}
+				if cmd, ok := l.updateDiffNav(kmsg); ok {
+					cmds = append(cmds, cmd)
+					// Don't let the viewport handle the key too.
+					return l, tea.Batch(cmds...)
+				}
 				switch {
 				case key.Matches(kmsg, l.common.KeyMap.BackItem):
-					l.goBack()
+					cmds = append(cmds, l.goBack())
+				case key.Matches(kmsg, blameParent):
+					cmds = append(cmds, l.blameParentCmd())
+				case key.Matches(kmsg, parentCommit):
+					if c := l.selectedCommit; c != nil && c.ParentsCount() > 0 {
+						cmds = append(cmds,
+							l.loadParentsCmd(c),
+							l.startLoading(),
+						)
+					}
+				case key.Matches(kmsg, childCommit):
+					cmds = append(cmds, l.childrenCmd())
+				case key.Matches(kmsg, messageRefs):
+					cmds = append(cmds, l.messageRefsCmd())
+				case key.Matches(kmsg, messageFiles):
+					cmds = append(cmds, l.messageFilesCmd())
+				case key.Matches(kmsg, containingRefs):
+					cmds = append(cmds, l.containingRefsCmd())
+				case key.Matches(kmsg, rawMessage):
+					l.rawMessage = !l.rawMessage
+					if l.selectedCommit != nil && l.currentDiff != nil {
+						l.setDiffContent(l.currentDiff)
+					}
+				case key.Matches(kmsg, toggleCommitter):
+					l.toggleCommitter()
+					if l.selectedCommit != nil && l.currentDiff != nil {
+						l.setDiffContent(l.currentDiff)
+					}
+				case key.Matches(kmsg, moreContext):
+					if l.diffOptions.Context < git.MaxDiffContext {
+						l.contextToggled = true
+						l.diffOptions.Context++
+						cmds = append(cmds, l.loadDiffCmd, l.startLoading())
+					}
+				case key.Matches(kmsg, lessContext):
+					if l.diffOptions.Context > 0 {
+						l.contextToggled = true
+						l.diffOptions.Context--
+						cmds = append(cmds, l.loadDiffCmd, l.startLoading())
+					}
+				case key.Matches(kmsg, mergeDiff):
+					if l.nextMergeDiff() {
+						cmds = append(cmds, l.loadDiffCmd, l.startLoading())
+					}
+				case key.Matches(kmsg, cycleWhitespace):
+					l.whitespaceToggled = true
+					l.diffOptions.Whitespace = l.diffOptions.Whitespace.Next()
+					cmds = append(cmds, l.loadDiffCmd, l.startLoading())
+				case key.Matches(kmsg, splitDiff):
+					if !l.split && l.common.Width < splitDiffWidth {
+						cmds = append(cmds, statusCmd(fmt.Sprintf("split diff needs %d columns", splitDiffWidth)))
+						break
+					}
+					l.toggleSplit()
+				case key.Matches(kmsg, cycleDiffColors):
+					cmds = append(cmds, l.nextDiffColors())
+				case key.Matches(kmsg, l.common.KeyMap.Copy):
+					if start, end, ok := l.vp.Selection(); ok {
+						cmds = append(cmds, copyCmd(l.vp.SelectedText(),
+							fmt.Sprintf("%s copied to clipboard", linesString(start+1, end+1))))
+					}
 				}
 			}
 		}
 	case GoBackMsg:
-		l.goBack()
+		cmds = append(cmds, l.goBack())
+	case LogJumpMsg:
+		l.jumps = append(l.jumps, logJump{
+			view:    l.activeView,
+			commit:  l.selectedCommit,
+			diff:    l.currentDiff,
+			yOffset: l.vp.YOffset,
+
+			mergeDiff:  l.mergeDiff,
+			resolution: l.resolution,
+		})
+		l.selectedCommit = msg.commit
+		l.mergeDiff, l.resolution = 0, nil
+		l.picker = nil
+		l.jumpPath = msg.path
+		cmds = append(cmds, l.loadDiffCmd, l.startLoading())
 	case selector.ActiveMsg:
 		switch sel := msg.IdentifiableItem.(type) {
 		case LogItem:
@@ -270,32 +782,106 @@ func (l *Log) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				l.startLoading(),
 			)
 		}
+	case CommitOpenMsg:
+		cmds = append(cmds, l.openCommitCmd(msg.hash), l.startLoading())
+	case commitOpenResultMsg:
+		cmds = append(cmds, l.openCommit(msg))
 	case LogCommitMsg:
 		l.selectedCommit = msg
+		l.mergeDiff, l.resolution = 0, nil
+		l.picker = nil
+		l.selectLoadedCommit(msg)
 		cmds = append(cmds, l.loadDiffCmd)
+	case LogPickerMsg:
+		l.activeView = logViewDiff
+		l.picker = &logPicker{
+			title:   msg.title,
+			commits: msg.commits,
+			files:   msg.files,
+		}
+	case LogContainsMsg:
+		l.contains[msg.id] = msg.refs
+		if c := l.selectedCommit; c == nil || c.ID.String() != msg.id {
+			break
+		}
+		l.activeView = logViewDiff
+		if len(msg.refs) == 0 {
+			cmds = append(cmds, statusCmd(fmt.Sprintf("No branch or tag contains %s", msg.id[:7])))
+			break
+		}
+		l.picker = &logPicker{
+			title: "Branches and tags containing",
+			refs:  msg.refs,
+		}
 	case LogDiffMsg:
+		// The repo page delivers the diff twice when the log is the active
+		// tab. Don't lose the position of a jump the second time.
+		if l.currentDiff == msg && l.activeView == logViewDiff {
+			break
+		}
+		if l.resolution != nil && l.resolution.Diff != (*git.Diff)(msg) {
+			l.resolution = nil
+		}
 		l.currentDiff = msg
-		l.vp.SetContent(
-			lipgloss.JoinVertical(lipgloss.Left,
-				l.renderCommit(l.selectedCommit),
-				renderSummary(msg, l.common.Styles, l.common.Width),
-				renderDiff(msg, l.common.Width),
-			),
-		)
+		l.vp.ClearSelection()
+		l.setDiffContent(msg)
 		l.vp.GotoTop()
+		l.jumpLine = 0
+		if l.jumpPath != "" {
+			l.gotoFile(l.jumpPath)
+			l.jumpPath = ""
+		}
 		l.activeView = logViewDiff
+		cmds = append(cmds,
+			l.loadMessageRefsCmd(l.selectedCommit),
+			l.loadIdentitiesCmd(l.selectedCommit),
+			l.loadSignatureCmd(l.selectedCommit),
+		)
+	case LogResolutionMsg:
+		l.resolution = msg
+		_, cmd := l.Update(LogDiffMsg(msg.Diff))
+		cmds = append(cmds, cmd)
+	case LogRefsMsg:
+		// The repo page delivers the references twice when the log is the
+		// active tab.
+		if (len(msg.refs) == 0 && len(msg.files) == 0) || l.msgRefs[msg.id] != nil {
+			break
+		}
+		l.msgRefs[msg.id] = msg.refs
+		l.msgFiles[msg.id] = msg.files
+		if c := l.selectedCommit; c != nil && c.ID.String() == msg.id && l.currentDiff != nil {
+			l.setDiffContent(l.currentDiff)
+		}
+		if c := l.messageCommit; c != nil && c.ID.String() == msg.id {
+			l.renderMessage()
+		}
+	case LogIdentitiesMsg:
+		changed := false
+		for id, canonical := range msg.identities {
+			if l.identities[id] != canonical {
+				l.identities[id] = canonical
+				changed = true
+			}
+		}
+		if c := l.selectedCommit; changed && c != nil && c.ID.String() == msg.id && l.currentDiff != nil {
+			l.setDiffContent(l.currentDiff)
+		}
+	case LogSignatureMsg:
+		// The repo page delivers the signature twice when the log is the
+		// active tab.
+		if sig, ok := l.signatures[msg.id]; ok && sig == msg.signature {
+			break
+		}
+		l.signatures[msg.id] = msg.signature
+		if c := l.selectedCommit; msg.signature != "" && c != nil && c.ID.String() == msg.id && l.currentDiff != nil {
+			l.setDiffContent(l.currentDiff)
+		}
 	case footer.ToggleFooterMsg:
 		cmds = append(cmds, l.updateCommitsCmd)
 	case tea.WindowSizeMsg:
 		l.SetSize(msg.Width, msg.Height)
 		if l.selectedCommit != nil && l.currentDiff != nil {
-			l.vp.SetContent(
-				lipgloss.JoinVertical(lipgloss.Left,
-					l.renderCommit(l.selectedCommit),
-					renderSummary(l.currentDiff, l.common.Styles, l.common.Width),
-					renderDiff(l.currentDiff, l.common.Width),
-				),
-			)
+			l.setDiffContent(l.currentDiff)
 		}
 		if l.repo != nil && l.ref != nil {
 			cmds = append(cmds,
@@ -312,6 +898,7 @@ func (l *Log) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		l.count = 0
 		l.activeCommit = nil
 		l.selectedCommit = nil
+		l.picker = nil
 		l.selector.Select(0)
 		cmds = append(cmds,
 			l.setItems([]selector.IdentifiableItem{}),
@@ -333,6 +920,7 @@ func (l *Log) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 	}
+	cmds = append(cmds, l.updateMessage())
 	return l, tea.Batch(cmds...)
 }
 
@@ -352,8 +940,25 @@ func (l *Log) View() string {
 		}
 		fallthrough
 	case logViewCommits:
-		return l.selector.View()
+		view := l.selector.View()
+		if l.showMessage {
+			sep := l.common.Renderer.NewStyle().
+				Foreground(l.common.Styles.InactiveBorderColor).
+				Render(strings.Repeat("─", max(l.common.Width, 0)))
+			view = lipgloss.JoinVertical(lipgloss.Left,
+				view,
+				sep,
+				l.msgVp.View(),
+			)
+		}
+		if l.showFilter() {
+			view = lipgloss.JoinVertical(lipgloss.Left, l.filterView(), view)
+		}
+		return view
 	case logViewDiff:
+		if l.picker != nil {
+			return l.renderPicker()
+		}
 		return l.vp.View()
 	default:
 		return ""
@@ -374,8 +979,12 @@ func (l *Log) StatusBarValue() string {
 	if c == nil {
 		return ""
 	}
-	who := c.Author.Name
-	if email := c.Author.Email; email != "" {
+	sig := c.Author
+	if l.committer {
+		sig = c.Committer
+	}
+	who := sig.Name
+	if email := sig.Email; email != "" {
 		who += " <" + email + ">"
 	}
 	value := c.ID.String()[:7]
@@ -398,23 +1007,486 @@ func (l *Log) StatusBarInfo() string {
 		// of the paginator hack above.
 		return fmt.Sprintf("p. %d/%d", l.nextPage+1, l.selector.TotalPages())
 	case logViewDiff:
-		return fmt.Sprintf("☰ %.f%%", l.vp.ScrollPercent()*100)
+		info := strings.Join(l.diffOptions.Args(), " ") +
+			fmt.Sprintf(" ☰ %.f%%", l.vp.ScrollPercent()*100)
+		if hunk := l.hunkInfo(); hunk != "" {
+			info = hunk + " " + info
+		}
+		if start, end, ok := l.vp.Selection(); ok {
+			info = linesString(start+1, end+1) + " " + info
+		}
+		return info
 	default:
 		return ""
 	}
 }
 
-func (l *Log) goBack() {
+func (l *Log) goBack() tea.Cmd {
+	if l.activeView == logViewCommits && l.queryText != "" {
+		return l.setQuery("", logQuery{})
+	}
 	if l.activeView == logViewDiff {
+		if l.picker != nil {
+			l.picker = nil
+			return nil
+		}
+		if _, _, ok := l.vp.Selection(); ok {
+			l.vp.ClearSelection()
+			return nil
+		}
+		if len(l.jumps) > 0 {
+			return l.jumpBack()
+		}
 		l.activeView = logViewCommits
 		l.selectedCommit = nil
 	}
+	return nil
+}
+
+// jumpBack restores the state of the log before the last jump and goes back
+// to the tab that jumped here.
+func (l *Log) jumpBack() tea.Cmd {
+	j := l.jumps[len(l.jumps)-1]
+	l.jumps = l.jumps[:len(l.jumps)-1]
+	l.jumpPath, l.jumpLine = "", 0
+	l.picker = nil
+	l.vp.ClearSelection()
+	l.selectedCommit = j.commit
+	l.currentDiff = j.diff
+	l.mergeDiff, l.resolution = j.mergeDiff, j.resolution
+	if j.view == logViewDiff && j.commit != nil && j.diff != nil {
+		l.setDiffContent(j.diff)
+		l.vp.SetYOffset(j.yOffset)
+		l.activeView = logViewDiff
+	} else {
+		l.selectedCommit = nil
+		l.activeView = logViewCommits
+	}
+	return jumpBackCmd
+}
+
+// blameParentCmd jumps to the blame of the file at the current line of the
+// diff in the parent of the selected commit the diff is against. The current line is the
+// start of the selection, the line the hunk and file keys moved to, or the top
+// of the view.
+func (l *Log) blameParentCmd() tea.Cmd {
+	c, diff := l.selectedCommit, l.currentDiff
+	if c == nil || diff == nil {
+		return nil
+	}
+
+	line := l.vp.YOffset
+	if l.navLine >= 0 && l.vp.YOffset == l.navOffset {
+		line = l.navLine
+	}
+	if start, _, ok := l.vp.Selection(); ok {
+		line = start
+	}
+	n := 0
+	if line >= 0 && line < len(l.diffLines) && l.diffLines[line] >= 0 {
+		n = l.diffLines[line]
+	}
+	if _, _, ok := l.vp.Selection(); !ok && l.jumpLine > 0 && l.vp.YOffset == l.jumpOffset {
+		n = l.jumpLine
+	}
+	f, old := diff.LineAt(n)
+	if f == nil {
+		return nil
+	}
+
+	if l.showResolution() {
+		return statusCmd("The automatic merge has no blame, switch to the diff against a parent")
+	}
+	from, _ := f.Files()
+	parent, err := c.ParentID(l.mergeDiff)
+	if from == nil || err != nil {
+		return statusCmd(fmt.Sprintf("%s doesn't exist before commit %s", f.Name, c.ID.String()[:7]))
+	}
+
+	return func() tea.Msg {
+		return FileJumpMsg{
+			rev:   parent.String(),
+			path:  from.Name(),
+			line:  old,
+			blame: true,
+		}
+	}
+}
+
+// setDiffContent renders the selected commit and the given diff in the
+// viewport and maps the rendered lines to the lines of the patch.
+func (l *Log) setDiffContent(diff *git.Diff) {
+	parts := []string{l.renderCommit(l.selectedCommit)}
+	if md := l.renderMergeDiff(l.selectedCommit); md != "" {
+		parts = append(parts, md)
+	}
+	parts = append(parts, renderSummary(diff, l.common.Styles, l.common.Width))
+	header := lipgloss.JoinVertical(lipgloss.Left, parts...)
+	navLine := l.diffNavLine(diff)
+	var body string
+	var lines []int
+	if l.showSplit() {
+		body, lines = renderSplitDiffLines(diff, l.common.Styles, l.common.Width)
+	} else {
+		body, lines = renderDiffLines(diff, l.common.Styles, l.common.Width)
+	}
+	l.diffLines = make([]int, lipgloss.Height(header), lipgloss.Height(header)+len(lines))
+	for i := range l.diffLines {
+		l.diffLines[i] = -1
+	}
+	l.diffLines = append(l.diffLines, lines...)
+	l.vp.SetContent(lipgloss.JoinVertical(lipgloss.Left, header, body))
+	l.setDiffTargets(diff, navLine)
+}
+
+// gotoFile scrolls the diff view to the given file.
+func (l *Log) gotoFile(path string) {
+	n := l.currentDiff.FileStart(path)
+	if n < 0 {
+		return
+	}
+	for i, pl := range l.diffLines {
+		if pl == n {
+			l.vp.SetYOffset(i)
+			l.jumpLine, l.jumpOffset = n, l.vp.YOffset
+			return
+		}
+	}
+}
+
+// loadColumns returns the log columns preferred by the user. It returns nil
+// for the default log.
+func (l *Log) loadColumns() []common.LogColumn {
+	be := l.common.Backend()
+	if be == nil {
+		return nil
+	}
+	spec, err := be.Preference(l.common.Context(), l.common.PublicKey(), common.LogColumnsPreference)
+	if err != nil {
+		l.common.Logger.Debugf("ui: failed to load log columns: %v", err)
+		return nil
+	}
+	if spec == "" {
+		return nil
+	}
+	cols, err := common.ParseLogColumns(spec)
+	if err != nil {
+		l.common.Logger.Debugf("ui: invalid log columns %q: %v", spec, err)
+		return nil
+	}
+	return cols
+}
+
+// applyViewSettings uses the diff settings of the repository, unless the user
+// changed them in this session.
+func (l *Log) applyViewSettings(vs backend.ViewSettings) {
+	if !l.contextToggled {
+		l.diffOptions.Context = git.DefaultDiffContext
+		if vs.DiffContext != nil {
+			l.diffOptions.Context = *vs.DiffContext
+		}
+	}
+	if !l.whitespaceToggled {
+		l.diffOptions.Whitespace = git.DiffWhitespaceShow
+		if vs.DiffWhitespace != nil {
+			l.diffOptions.Whitespace = *vs.DiffWhitespace
+		}
+	}
+}
+
+// loadCommitter returns true if the user prefers to see the committers of
+// the commits.
+func (l *Log) loadCommitter() bool {
+	be := l.common.Backend()
+	if be == nil {
+		return false
+	}
+	v, err := be.Preference(l.common.Context(), l.common.PublicKey(), common.LogCommitterPreference)
+	if err != nil {
+		l.common.Logger.Debugf("ui: failed to load log committer preference: %v", err)
+		return false
+	}
+	return v == "true"
+}
+
+// toggleCommitter switches between the authors and the committers of the
+// commits, and saves the choice of the user.
+func (l *Log) toggleCommitter() {
+	l.committer = !l.committer
+	l.updateDelegate()
+	be, pk := l.common.Backend(), l.common.PublicKey()
+	if be == nil || pk == nil {
+		return
+	}
+	ctx := l.common.Context()
+	var err error
+	if l.committer {
+		err = be.SetPreference(ctx, pk, common.LogCommitterPreference, "true")
+	} else {
+		err = be.DeletePreference(ctx, pk, common.LogCommitterPreference)
+	}
+	if err != nil {
+		l.common.Logger.Debugf("ui: failed to save log committer preference: %v", err)
+	}
+}
+
+// filterKey returns the key that filters the commits.
+func (l *Log) filterKey() key.Binding {
+	k := filterLog
+	if l.queryText != "" {
+		k.SetHelp("/", "edit filter")
+	}
+	return k
+}
+
+// committerKey returns the key that switches between the authors and the
+// committers of the commits.
+func (l *Log) committerKey() key.Binding {
+	k := toggleCommitter
+	if l.committer {
+		k.SetHelp("a", "author")
+	}
+	return k
+}
+
+// updateDelegate sets the delegate of the list with the columns of the user
+// and the commit type badges of the repository.
+func (l *Log) updateDelegate() {
+	var badges bool
+	if l.repo != nil {
+		badges = !l.noBadges[l.repo.Name()]
+	}
+	l.selector.SetDelegate(LogItemDelegate{
+		common:    &l.common,
+		columns:   l.columns,
+		badges:    badges,
+		committer: l.committer,
+	})
+}
+
+// toggleMessage shows or hides the message of the active commit below the
+// list. The list gets shorter, so the commits are loaded again for the page
+// of the selected commit.
+func (l *Log) toggleMessage() tea.Cmd {
+	idx := l.selector.Index()
+	perPage := l.selector.PerPage()
+	l.showMessage = !l.showMessage
+	l.messageCommit = nil
+	l.SetSize(l.common.Width, l.common.Height)
+	if l.ref == nil || l.count == 0 || l.selector.PerPage() == perPage {
+		return nil
+	}
+	if pp := l.selector.PerPage(); pp > 0 {
+		l.nextPage = idx / pp
+	}
+	l.reselect = idx
+	return tea.Batch(l.updateCommitsCmd, l.startLoading())
+}
+
+// updateMessage shows the message of the active commit if it changed and
+// loads the commits it references.
+func (l *Log) updateMessage() tea.Cmd {
+	if !l.showMessage || l.activeCommit == l.messageCommit {
+		return nil
+	}
+	l.messageCommit = l.activeCommit
+	l.renderMessage()
+	l.msgVp.GotoTop()
+	return l.loadMessageRefsCmd(l.messageCommit)
+}
+
+// renderMessage renders the message of the shown commit below the list.
+func (l *Log) renderMessage() {
+	c := l.messageCommit
+	if c == nil {
+		l.msgVp.SetContent("")
+		return
+	}
+	msg := strings.TrimSpace(l.messageBody(c))
+	l.msgVp.SetContent(wrap.String(l.common.Styles.Log.CommitBody.Render(msg), l.common.Width-2))
+}
+
+// updatePicker handles key presses while the commit picker is open.
+func (l *Log) updatePicker(msg tea.KeyMsg) tea.Cmd {
+	p := l.picker
+	switch {
+	case key.Matches(msg, l.common.KeyMap.BackItem):
+		l.picker = nil
+	case key.Matches(msg, l.common.KeyMap.Up):
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case key.Matches(msg, l.common.KeyMap.Down):
+		if p.cursor < p.len()-1 {
+			p.cursor++
+		}
+	case key.Matches(msg, l.common.KeyMap.SelectItem),
+		key.Matches(msg, l.common.KeyMap.Select):
+		return l.pick(p.cursor)
+	case key.Matches(msg, pickCommit):
+		n, err := strconv.Atoi(msg.String())
+		if err != nil || n > p.len() {
+			return nil
+		}
+		return l.pick(n - 1)
+	}
+	return nil
+}
+
+// pick jumps to the commit at the given index of the picker, switches to the
+// reference, or shows the file.
+func (l *Log) pick(i int) tea.Cmd {
+	p := l.picker
+	if p.refs != nil {
+		l.picker = nil
+		return switchRefCmd(p.refs[i])
+	}
+	if p.files != nil {
+		l.picker = nil
+		return l.fileJumpCmd(p.files[i])
+	}
+	return tea.Batch(l.selectCommitCmd(p.commits[i]), l.startLoading())
+}
+
+// selectLoadedCommit moves the log cursor to the given commit if it's part of
+// the currently loaded log window. This way going back to the log lands on
+// the commit we navigated to.
+func (l *Log) selectLoadedCommit(c *git.Commit) {
+	if c == nil {
+		return
+	}
+	for i, it := range l.selector.Items() {
+		if li, ok := it.(LogItem); ok && li.Commit != nil && li.ID() == c.ID.String() {
+			l.selector.Select(i)
+			l.activeCommit = li.Commit
+			return
+		}
+	}
+}
+
+// loadParentsCmd loads the parents of the given commit. It jumps straight to
+// the parent of a regular commit and asks the user to pick one when the commit
+// is a merge.
+func (l *Log) loadParentsCmd(c *git.Commit) tea.Cmd {
+	repo, ref := l.repo, l.ref
+	return func() tea.Msg {
+		parents := make([]*git.Commit, 0, c.ParentsCount())
+		for i := 0; i < c.ParentsCount(); i++ {
+			p, err := c.Parent(i)
+			if err != nil {
+				l.common.Logger.Debugf("ui: error loading parent commit: %v", err)
+				return common.ErrorMsg(err)
+			}
+			parents = append(parents, p)
+		}
+		if r, err := repo.Open(); err == nil {
+			l.mapCommits(r, ref, parents...)
+		}
+		if len(parents) == 1 {
+			return LogCommitMsg(parents[0])
+		}
+		return LogPickerMsg{
+			title:   "Parents",
+			commits: parents,
+		}
+	}
+}
+
+// childrenCmd finds the children of the selected commit in the currently
+// loaded log window.
+func (l *Log) childrenCmd() tea.Cmd {
+	c := l.selectedCommit
+	if c == nil {
+		return nil
+	}
+	children := make([]*git.Commit, 0)
+	for _, it := range l.selector.Items() {
+		li, ok := it.(LogItem)
+		if !ok || li.Commit == nil {
+			continue
+		}
+		for i := 0; i < li.ParentsCount(); i++ {
+			if id, err := li.ParentID(i); err == nil && id.Equal(c.ID) {
+				children = append(children, li.Commit)
+				break
+			}
+		}
+	}
+	switch len(children) {
+	case 0:
+		return nil
+	case 1:
+		return tea.Batch(l.selectCommitCmd(children[0]), l.startLoading())
+	default:
+		return func() tea.Msg {
+			return LogPickerMsg{
+				title:   "Children",
+				commits: children,
+			}
+		}
+	}
+}
+
+func (l *Log) renderPicker() string {
+	p := l.picker
+	s := strings.Builder{}
+	heading := fmt.Sprintf("%s of %s", p.title, l.selectedCommit.ID.String()[:7])
+	if p.refs != nil {
+		heading = fmt.Sprintf("%s %s", p.title, l.selectedCommit.ID.String()[:7])
+	}
+	s.WriteString(l.common.Styles.Log.CommitHash.Render(heading))
+	s.WriteString("\n\n")
+	for i, ref := range p.refs {
+		st := l.common.Styles.LogItem.Normal
+		if i == p.cursor {
+			st = l.common.Styles.LogItem.Active
+		}
+		kind := st.Hash.Render(fmt.Sprintf("%-6s", refKind(ref)))
+		name := common.TruncateString(ref.Name().Short(),
+			l.common.Width-lipgloss.Width(kind)-st.Base.GetHorizontalFrameSize()-4)
+		s.WriteString(st.Base.Render(fmt.Sprintf("%d %s %s", i+1, kind, st.Title.Render(name))))
+		s.WriteString("\n")
+	}
+	for i, c := range p.commits {
+		st := l.common.Styles.LogItem.Normal
+		if i == p.cursor {
+			st = l.common.Styles.LogItem.Active
+		}
+		hash := st.Hash.Render(c.ID.String()[:7])
+		title := common.TruncateString(c.Summary(),
+			l.common.Width-lipgloss.Width(hash)-st.Base.GetHorizontalFrameSize()-4)
+		s.WriteString(st.Base.Render(fmt.Sprintf("%d %s %s", i+1, hash, st.Title.Render(title))))
+		s.WriteString("\n")
+	}
+	for i, f := range p.files {
+		st := l.common.Styles.LogItem.Normal
+		if i == p.cursor {
+			st = l.common.Styles.LogItem.Active
+		}
+		name := common.TruncateString(f.path, l.common.Width-st.Base.GetHorizontalFrameSize()-4)
+		s.WriteString(st.Base.Render(fmt.Sprintf("%d %s", i+1, st.Title.Render(name))))
+		s.WriteString("\n")
+	}
+	if p.files != nil && p.files[p.cursor].preview != "" {
+		s.WriteString("\n")
+		for _, line := range strings.Split(p.files[p.cursor].preview, "\n") {
+			line = common.TruncateString(strings.ReplaceAll(line, "\t", "    "), l.common.Width-4)
+			s.WriteString(l.common.Styles.LogItem.Normal.Desc.Render("  " + line))
+			s.WriteString("\n")
+		}
+	}
+	return l.common.Renderer.NewStyle().
+		Height(l.common.Height).
+		Render(s.String())
 }
 
 func (l *Log) countCommitsCmd() tea.Msg {
 	if l.ref == nil {
 		return nil
 	}
+	if !l.query.isZero() {
+		return l.matchesCmd()
+	}
 	r, err := l.repo.Open()
 	if err != nil {
 		return common.ErrorMsg(err)
@@ -427,6 +1499,29 @@ func (l *Log) countCommitsCmd() tea.Msg {
 	return LogCountMsg(count)
 }
 
+// setCount sets the number of commits of the log and loads the commits of
+// the current page.
+func (l *Log) setCount(count int64) tea.Cmd {
+	l.count = count
+	l.selector.SetTotalPages(int(count))
+	l.selector.SetItems(make([]selector.IdentifiableItem, l.count))
+	return l.updateCommitsCmd
+}
+
+// matchingCommits returns the commits matching the filter of the given page.
+func (l *Log) matchingCommits(r *git.Repository, skip, limit int) (git.Commits, error) {
+	end := min(skip+limit, len(l.matches))
+	cc := make(git.Commits, 0, max(end-skip, 0))
+	for i := skip; i < end; i++ {
+		c, err := r.CatFileCommit(l.matches[i])
+		if err != nil {
+			return nil, err
+		}
+		cc = append(cc, c)
+	}
+	return cc, nil
+}
+
 func (l *Log) updateCommitsCmd() tea.Msg {
 	if l.ref == nil {
 		return nil
@@ -446,12 +1541,18 @@ func (l *Log) updateCommitsCmd() tea.Msg {
 	skip := page * limit
 	ref := l.ref
 	items := make([]selector.IdentifiableItem, count)
-	// CommitsByPage pages start at 1
-	cc, err := r.CommitsByPage(ref, page+1, limit)
+	var cc git.Commits
+	if l.matches != nil {
+		cc, err = l.matchingCommits(r, skip, limit)
+	} else {
+		// CommitsByPage pages start at 1
+		cc, err = r.CommitsByPage(ref, page+1, limit)
+	}
 	if err != nil {
 		l.common.Logger.Debugf("ui: error loading commits: %v", err)
 		return common.ErrorMsg(err)
 	}
+	l.mapCommits(r, ref, cc...)
 	for i, c := range cc {
 		idx := i + skip
 		if int64(idx) >= count {
@@ -462,6 +1563,58 @@ func (l *Log) updateCommitsCmd() tea.Msg {
 	return LogItemsMsg(items)
 }
 
+// loadStatusesCmd loads the commit statuses of the given items in the
+// background.
+func (l *Log) loadStatusesCmd(items []selector.IdentifiableItem) tea.Cmd {
+	repo := l.repo
+	be := l.common.Backend()
+	if repo == nil || be == nil {
+		return nil
+	}
+
+	shas := make([]string, 0)
+	for _, it := range items {
+		if li, ok := it.(LogItem); ok {
+			shas = append(shas, li.Hash())
+		}
+	}
+	if len(shas) == 0 {
+		return nil
+	}
+
+	ctx := l.common.Context()
+	return func() tea.Msg {
+		statuses, err := be.CommitStatusesForCommits(ctx, repo, shas)
+		if err != nil {
+			l.common.Logger.Debugf("ui: error loading commit statuses: %v", err)
+			return nil
+		}
+		if len(statuses) == 0 {
+			return nil
+		}
+
+		states := make(LogStatusesMsg, len(statuses))
+		for sha, ss := range statuses {
+			states[sha] = proto.CombinedCommitState(ss)
+		}
+		return states
+	}
+}
+
+// mapCommits replaces the authors and committers of the given commits with
+// their canonical identities according to the mailmap of the ref.
+func (l *Log) mapCommits(r *git.Repository, ref *git.Reference, commits ...*git.Commit) {
+	if ref == nil {
+		return
+	}
+	mm, err := r.Mailmap(ref)
+	if err != nil {
+		l.common.Logger.Debugf("ui: error loading mailmap: %v", err)
+		return
+	}
+	mm.MapCommits(commits...)
+}
+
 func (l *Log) selectCommitCmd(commit *git.Commit) tea.Cmd {
 	return func() tea.Msg {
 		return LogCommitMsg(commit)
@@ -477,7 +1630,19 @@ func (l *Log) loadDiffCmd() tea.Msg {
 		l.common.Logger.Debugf("ui: error loading diff repository: %v", err)
 		return common.ErrorMsg(err)
 	}
-	diff, err := r.Diff(l.selectedCommit)
+	if l.showResolution() {
+		res, err := r.MergeResolution(l.selectedCommit, l.diffOptions)
+		if err != nil {
+			l.common.Logger.Debugf("ui: error loading merge resolution: %v", err)
+			return common.ErrorMsg(err)
+		}
+		return LogResolutionMsg(res)
+	}
+	opts := l.diffOptions
+	if l.mergeDiff < l.selectedCommit.ParentsCount() {
+		opts.Parent = l.mergeDiff
+	}
+	diff, err := r.DiffWithOptions(l.selectedCommit, opts)
 	if err != nil {
 		l.common.Logger.Debugf("ui: error loading diff: %v", err)
 		return common.ErrorMsg(err)
@@ -490,12 +1655,49 @@ func (l *Log) renderCommit(c *git.Commit) string {
 	// FIXME: lipgloss prints empty lines when CRLF is used
 	// sanitize commit message from CRLF
 	msg := strings.ReplaceAll(c.Message, "\r\n", "\n")
-	s.WriteString(fmt.Sprintf("%s\n%s\n%s\n%s\n",
-		l.common.Styles.Log.CommitHash.Render("commit "+c.ID.String()),
-		l.common.Styles.Log.CommitAuthor.Render(fmt.Sprintf("Author: %s <%s>", c.Author.Name, c.Author.Email)),
-		l.common.Styles.Log.CommitDate.Render("Date:   "+c.Committer.When.Format(time.UnixDate)),
-		l.common.Styles.Log.CommitBody.Render(msg),
-	))
+	var trailers []git.Trailer
+	if !l.rawMessage {
+		msg, trailers = git.SplitTrailers(msg)
+	}
+	msg = renderMessageRefs(l.common.Styles, msg, l.msgRefs[c.ID.String()], l.msgFiles[c.ID.String()])
+	s.WriteString(l.common.Styles.Log.CommitHash.Render("commit "+c.ID.String()) + "\n")
+	if c.ParentsCount() > 1 {
+		parents := make([]string, 0, c.ParentsCount())
+		for i := 0; i < c.ParentsCount(); i++ {
+			if id, err := c.ParentID(i); err == nil {
+				parents = append(parents, id.String()[:7])
+			}
+		}
+		s.WriteString(l.common.Styles.Log.CommitAuthor.Render("Merge:  "+strings.Join(parents, " ")) + "\n")
+	}
+	a, cm := c.Author, c.Committer
+	switch {
+	case a.Name != cm.Name || a.Email != cm.Email || !a.When.Equal(cm.When):
+		// Rewritten history, show both.
+		s.WriteString(fmt.Sprintf("%s\n%s\n%s\n%s\n",
+			l.common.Styles.Log.CommitAuthor.Render(fmt.Sprintf("Author:     %s <%s>", a.Name, a.Email)),
+			l.common.Styles.Log.CommitDate.Render("AuthorDate: "+a.When.Format(time.UnixDate)),
+			l.common.Styles.Log.CommitAuthor.Render(fmt.Sprintf("Commit:     %s <%s>", cm.Name, cm.Email)),
+			l.common.Styles.Log.CommitDate.Render("CommitDate: "+cm.When.Format(time.UnixDate)),
+		))
+	case l.committer:
+		s.WriteString(fmt.Sprintf("%s\n%s\n",
+			l.common.Styles.Log.CommitAuthor.Render(fmt.Sprintf("Commit: %s <%s>", cm.Name, cm.Email)),
+			l.common.Styles.Log.CommitDate.Render("Date:   "+cm.When.Format(time.UnixDate)),
+		))
+	default:
+		s.WriteString(fmt.Sprintf("%s\n%s\n",
+			l.common.Styles.Log.CommitAuthor.Render(fmt.Sprintf("Author: %s <%s>", a.Name, a.Email)),
+			l.common.Styles.Log.CommitDate.Render("Date:   "+a.When.Format(time.UnixDate)),
+		))
+	}
+	if sig := l.signatures[c.ID.String()]; sig != "" {
+		s.WriteString(l.common.Styles.Log.CommitAuthor.Render("Signature: "+sig) + "\n")
+	}
+	s.WriteString(l.common.Styles.Log.CommitBody.Render(msg) + "\n")
+	if len(trailers) > 0 {
+		s.WriteString(l.renderTrailers(trailers) + "\n")
+	}
 	return wrap.String(s.String(), l.common.Width-2)
 }
 
@@ -513,20 +1715,40 @@ func renderSummary(diff *git.Diff, styles *styles.Styles, width int) string {
 	return wrap.String(strings.Join(stats, "\n"), width-2)
 }
 
-func renderDiff(diff *git.Diff, width int) string {
-	var s strings.Builder
+func renderDiff(diff *git.Diff, styles *styles.Styles, width int) string {
+	s, _ := renderDiffLines(diff, styles, width)
+	return s
+}
+
+// renderDiffLines renders the diff and returns the zero based line of the
+// patch of every rendered line. Lines that aren't part of the patch are -1.
+func renderDiffLines(diff *git.Diff, styles *styles.Styles, width int) (string, []int) {
 	var pr strings.Builder
 	diffChroma := &gansi.CodeBlockElement{
 		Code:     diff.Patch(),
 		Language: "diff",
 	}
-	err := diffChroma.Render(&pr, common.StyleRenderer())
+	err := diffChroma.Render(&pr, common.DiffStyleRenderer(styles.DiffColors))
 	if err != nil {
-		s.WriteString(fmt.Sprintf("\n%s", err.Error()))
-	} else {
-		s.WriteString(fmt.Sprintf("\n%s", pr.String()))
+		s := wrap.String(fmt.Sprintf("\n%s", err.Error()), width)
+		lines := make([]int, strings.Count(s, "\n")+1)
+		for i := range lines {
+			lines[i] = -1
+		}
+		return s, lines
+	}
+
+	// Wrap line by line to keep track of the patch line of each rendered
+	// line. The code block is rendered one line per patch line.
+	rendered := []string{""}
+	lines := []int{-1}
+	for i, pl := range strings.Split(pr.String(), "\n") {
+		for _, wl := range strings.Split(wrap.String(pl, width), "\n") {
+			rendered = append(rendered, wl)
+			lines = append(lines, i)
+		}
 	}
-	return wrap.String(s.String(), width)
+	return strings.Join(rendered, "\n"), lines
 }
 
 func (l *Log) setItems(items []selector.IdentifiableItem) tea.Cmd {
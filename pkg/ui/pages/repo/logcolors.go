@@ -0,0 +1,49 @@
+package repo
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/ui/styles"
+)
+
+var cycleDiffColors = key.NewBinding(
+	key.WithKeys("C"),
+	key.WithHelp("C", "diff colors"),
+)
+
+// diffColorsKey returns the key that switches the diffs to the next color
+// scheme.
+func (l *Log) diffColorsKey() key.Binding {
+	k := cycleDiffColors
+	k.SetHelp("C", "colors: "+string(l.common.Styles.DiffColors))
+	return k
+}
+
+// nextDiffColors switches the diffs to the next color scheme, and saves the
+// choice of the user. The styles are shared by the whole session, so the
+// other diffs use the scheme the next time they're shown.
+func (l *Log) nextDiffColors() tea.Cmd {
+	c := l.common.Styles.DiffColors.Next()
+	l.common.Styles.SetDiffColors(c)
+	if l.selectedCommit != nil && l.currentDiff != nil {
+		yOffset := l.vp.YOffset
+		l.setDiffContent(l.currentDiff)
+		l.vp.SetYOffset(yOffset)
+	}
+
+	be, pk := l.common.Backend(), l.common.PublicKey()
+	if be != nil && pk != nil {
+		ctx := l.common.Context()
+		var err error
+		if c == styles.DiffColorsClassic {
+			err = be.DeletePreference(ctx, pk, common.DiffColorsPreference)
+		} else {
+			err = be.SetPreference(ctx, pk, common.DiffColorsPreference, string(c))
+		}
+		if err != nil {
+			l.common.Logger.Debugf("ui: failed to save diff colors preference: %v", err)
+		}
+	}
+	return statusCmd("Diff colors: " + string(c))
+}
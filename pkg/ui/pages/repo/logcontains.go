@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+)
+
+var containingRefs = key.NewBinding(
+	key.WithKeys("B"),
+	key.WithHelp("B", "branches & tags"),
+)
+
+// LogContainsMsg is a message that contains the branches and tags a commit is
+// reachable from.
+type LogContainsMsg struct {
+	id   string
+	refs []*git.Reference
+}
+
+// containingRefsCmd lists the branches and tags the selected commit is
+// reachable from. The references are only looked up once per commit.
+func (l *Log) containingRefsCmd() tea.Cmd {
+	c := l.selectedCommit
+	if c == nil {
+		return nil
+	}
+	id := c.ID.String()
+	if refs, ok := l.contains[id]; ok {
+		return func() tea.Msg {
+			return LogContainsMsg{id: id, refs: refs}
+		}
+	}
+
+	repo := l.repo
+	return tea.Batch(func() tea.Msg {
+		r, err := repo.Open()
+		if err != nil {
+			return common.ErrorMsg(err)
+		}
+		refs, err := r.ReferencesContaining(id)
+		if err != nil {
+			l.common.Logger.Debugf("ui: error loading references containing commit: %v", err)
+			return common.ErrorMsg(err)
+		}
+		return LogContainsMsg{id: id, refs: refs}
+	}, l.startLoading())
+}
+
+// refKind returns the kind of the reference, as shown in the picker.
+func refKind(ref *git.Reference) string {
+	if ref.IsTag() {
+		return "tag"
+	}
+	return "branch"
+}
@@ -0,0 +1,186 @@
+package repo
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/soft-serve/git"
+)
+
+var messageFiles = key.NewBinding(
+	key.WithKeys("o"),
+	key.WithHelp("o", "referenced file"),
+)
+
+const (
+	// maxMessageFiles is the maximum number of paths in a commit message
+	// that are looked up in the tree of the commit.
+	maxMessageFiles = 10
+	// maxFilePreviewSize is the size of the largest file previewed in the
+	// picker.
+	maxFilePreviewSize = 64 * 1024
+	// filePreviewLines is the number of lines of a file previewed in the
+	// picker.
+	filePreviewLines = 5
+)
+
+// messageFileRe matches the words that may be paths in commit messages, e.g.
+// "pkg/ui/log.go" or "README.md".
+var messageFileRe = regexp.MustCompile(`[A-Za-z0-9_.\-/]+`)
+
+// messageFile is a file of the tree of a commit referenced by its message.
+type messageFile struct {
+	path    string
+	preview string
+}
+
+// findMessageFiles returns the words of the message that look like paths.
+// The detection is conservative: a path stands on its own or is quoted, has
+// a slash or a dot, and doesn't climb up the tree. Trailing dots, e.g. the
+// end of a sentence, aren't part of the path.
+func findMessageFiles(msg string) []messageRef {
+	refs := make([]messageRef, 0)
+	for _, m := range messageFileRe.FindAllStringIndex(msg, -1) {
+		start, end := m[0], m[1]
+		for end > start && msg[end-1] == '.' {
+			end--
+		}
+		if start > 0 && !strings.ContainsRune(" \t\n`'\"([", rune(msg[start-1])) {
+			continue
+		}
+		if end < len(msg) && !strings.ContainsRune(" \t\n`'\"),:;.!?]", rune(msg[end])) {
+			continue
+		}
+		if isMessageFile(msg[start:end]) {
+			refs = append(refs, messageRef{start: start, end: end, file: true})
+		}
+	}
+	return refs
+}
+
+// isMessageFile returns whether the word looks like the path of a file.
+func isMessageFile(word string) bool {
+	p := messageFilePath(word)
+	return p != "" &&
+		len(p) <= 255 &&
+		strings.ContainsAny(p, "./") &&
+		strings.IndexFunc(p, func(r rune) bool {
+			return ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z')
+		}) >= 0 &&
+		!strings.HasPrefix(p, "/") &&
+		!strings.HasSuffix(p, "/") &&
+		!strings.Contains(p, "//") &&
+		!strings.Contains(p, "..")
+}
+
+// messageFilePath returns the path of the file a path-like word refers to.
+func messageFilePath(word string) string {
+	return strings.TrimPrefix(word, "./")
+}
+
+// messageFileCandidates returns the unique paths in the message, at most
+// maxMessageFiles of them.
+func messageFileCandidates(msg string) []string {
+	seen := map[string]bool{}
+	paths := make([]string, 0)
+	for _, ref := range findMessageFiles(msg) {
+		p := messageFilePath(msg[ref.start:ref.end])
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		paths = append(paths, p)
+		if len(paths) == maxMessageFiles {
+			break
+		}
+	}
+	return paths
+}
+
+// resolveMessageFiles returns the files of the tree of the given commit
+// referenced by its message, in the order they're mentioned. Paths that
+// don't resolve to a file are left out.
+func resolveMessageFiles(r *git.Repository, c *git.Commit) []messageFile {
+	ref := r.CommitReference(c.ID.String())
+	trees := map[string]*git.Tree{}
+	files := make([]messageFile, 0)
+	for _, p := range messageFileCandidates(c.Message) {
+		dir, name := filepath.Split(p)
+		t, ok := trees[dir]
+		if !ok {
+			var err error
+			if t, err = r.TreePath(ref, dir); err != nil {
+				t = nil
+			}
+			trees[dir] = t
+		}
+		if t == nil {
+			continue
+		}
+		e, err := t.TreeEntry(name)
+		if err != nil || e.IsTree() || e.IsCommit() {
+			continue
+		}
+		files = append(files, messageFile{
+			path:    p,
+			preview: filePreview(e),
+		})
+	}
+	return files
+}
+
+// filePreview returns the first lines of the file, nothing for binary and
+// large files.
+func filePreview(e *git.TreeEntry) string {
+	fi := e.File()
+	if fi.Size() > maxFilePreviewSize {
+		return ""
+	}
+	if bin, err := fi.IsBinary(); err != nil || bin {
+		return ""
+	}
+	c, err := fi.Bytes()
+	if err != nil {
+		return ""
+	}
+	lines := strings.SplitN(strings.ReplaceAll(string(c), "\r\n", "\n"), "\n", filePreviewLines+1)
+	if len(lines) > filePreviewLines {
+		lines = lines[:filePreviewLines]
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// messageFilesCmd asks the user to pick one of the files referenced by the
+// message of the selected commit, with a preview of the file, to show it at
+// the commit.
+func (l *Log) messageFilesCmd() tea.Cmd {
+	c := l.selectedCommit
+	if c == nil {
+		return nil
+	}
+	files := l.msgFiles[c.ID.String()]
+	if len(files) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		return LogPickerMsg{
+			title: "Files in the message",
+			files: files,
+		}
+	}
+}
+
+// fileJumpCmd shows the file referenced by the message of the selected
+// commit in the files tab, at the tree of the commit.
+func (l *Log) fileJumpCmd(f messageFile) tea.Cmd {
+	rev := l.selectedCommit.ID.String()
+	return func() tea.Msg {
+		return FileJumpMsg{
+			rev:  rev,
+			path: f.path,
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package repo
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/sshutils"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+var (
+	filterLog = key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "filter"),
+	)
+	applyFilter = key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "apply"),
+	)
+	cancelFilter = key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "cancel"),
+	)
+)
+
+// commitSignatureNamespace is the namespace of the SSH signatures of commits
+// made by Git.
+const commitSignatureNamespace = "git"
+
+// LogMatchesMsg is a message that contains the hashes of the commits that
+// match the log filter, newest first.
+type LogMatchesMsg []string
+
+// LogQueryMsg is a message to filter the log with the given query.
+type LogQueryMsg string
+
+// LogSignatureMsg is a message that contains the signature of a commit as
+// shown in the diff view.
+type LogSignatureMsg struct {
+	id        string
+	signature string
+}
+
+// logQuery is a parsed log filter. It's a list of space separated tokens:
+// "author:", "path:", and "message:" match the commits like git log does,
+// "signed:true" and "signed:false" the commits that are, or aren't, signed
+// with a valid SSH signature, "signer:" the commits signed by the key with
+// the given fingerprint, and "since:" the commits that aren't reachable from
+// the commit with the given hash. Other words match the message.
+type logQuery struct {
+	filter git.LogFilter
+	// signed is "true" or "false", or empty to match every commit.
+	signed string
+	// signer is a prefix of the SHA256 fingerprint of the signing key,
+	// without the "SHA256:" prefix.
+	signer string
+}
+
+// isZero returns true if the query matches every commit.
+func (q logQuery) isZero() bool {
+	return q == logQuery{}
+}
+
+// parseLogQuery parses a log filter.
+func parseLogQuery(s string) (logQuery, error) {
+	var q logQuery
+	words := make([]string, 0)
+	for _, tok := range strings.Fields(s) {
+		name, value, ok := strings.Cut(tok, ":")
+		if !ok || value == "" {
+			words = append(words, tok)
+			continue
+		}
+		switch strings.ToLower(name) {
+		case "author":
+			q.filter.Author = value
+		case "path":
+			q.filter.Path = value
+		case "message":
+			words = append(words, value)
+		case "signed":
+			value = strings.ToLower(value)
+			if value != "true" && value != "false" {
+				return q, errors.New("signed must be true or false")
+			}
+			q.signed = value
+		case "signer":
+			q.signer = strings.TrimPrefix(value, "SHA256:")
+		case "since":
+			value = strings.ToLower(value)
+			if len(value) < 4 || strings.Trim(value, "0123456789abcdef") != "" {
+				return q, errors.New("since must be a commit hash")
+			}
+			q.filter.Since = value
+		default:
+			words = append(words, tok)
+		}
+	}
+	if q.signer != "" && q.signed == "false" {
+		return q, errors.New("signer needs signed:true")
+	}
+	q.filter.Message = strings.Join(words, " ")
+	return q, nil
+}
+
+// matchesSignature returns true if a commit with the given signing key, or
+// nil, matches the signed and signer tokens of the query.
+func (q logQuery) matchesSignature(pk gossh.PublicKey) bool {
+	switch {
+	case q.signer != "":
+		return pk != nil && strings.HasPrefix(strings.TrimPrefix(gossh.FingerprintSHA256(pk), "SHA256:"), q.signer)
+	case q.signed == "true":
+		return pk != nil
+	case q.signed == "false":
+		return pk == nil
+	default:
+		return true
+	}
+}
+
+// commitSigner returns the key that signed a commit with an SSH signature
+// valid for its content. Other signatures, e.g. OpenPGP ones, can't be
+// verified.
+func commitSigner(sig git.CommitSignature) (gossh.PublicKey, error) {
+	if !sig.IsSSH() {
+		return nil, sshutils.ErrInvalidSignature
+	}
+	return sshutils.VerifySigner(commitSignatureNamespace, sig.Payload, sig.Signature)
+}
+
+// IsFiltering returns true if the log filter is being typed.
+func (l *Log) IsFiltering() bool {
+	return l.filtering
+}
+
+// startFilter focuses the filter input with the current filter.
+func (l *Log) startFilter() tea.Cmd {
+	l.filtering = true
+	l.filterInput.SetValue(l.queryText)
+	l.filterInput.CursorEnd()
+	l.SetSize(l.common.Width, l.common.Height)
+	return l.filterInput.Focus()
+}
+
+// stopFilter blurs the filter input.
+func (l *Log) stopFilter() {
+	l.filtering = false
+	l.filterInput.Blur()
+	l.SetSize(l.common.Width, l.common.Height)
+}
+
+// setQuery filters the log with the given query and loads the matching
+// commits.
+func (l *Log) setQuery(text string, q logQuery) tea.Cmd {
+	l.queryText, l.query = text, q
+	l.SetSize(l.common.Width, l.common.Height)
+	l.selector.Select(0)
+	return l.Init()
+}
+
+// updateFilter handles key presses while typing the log filter.
+func (l *Log) updateFilter(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, cancelFilter):
+		l.stopFilter()
+		return nil
+	case key.Matches(msg, applyFilter):
+		text := strings.TrimSpace(l.filterInput.Value())
+		q, err := parseLogQuery(text)
+		if err != nil {
+			return statusCmd(err.Error())
+		}
+		l.stopFilter()
+		if q == l.query {
+			return nil
+		}
+		if q.isZero() {
+			text = ""
+		}
+		return l.setQuery(text, q)
+	}
+	var cmd tea.Cmd
+	l.filterInput, cmd = l.filterInput.Update(msg)
+	return cmd
+}
+
+// filterView renders the filter of the log above the commits.
+func (l *Log) filterView() string {
+	if l.filtering {
+		return l.filterInput.View()
+	}
+	return l.common.Styles.Log.CommitAuthor.Render("Filter: " + l.queryText)
+}
+
+// showFilter returns true if the filter is shown above the commits.
+func (l *Log) showFilter() bool {
+	return l.filtering || l.queryText != ""
+}
+
+// matchesCmd returns the hashes of the commits that match the filter.
+func (l *Log) matchesCmd() tea.Msg {
+	r, err := l.repo.Open()
+	if err != nil {
+		return common.ErrorMsg(err)
+	}
+	q := l.query
+	ids, err := r.FilterCommits(l.ref, q.filter)
+	if err != nil {
+		l.common.Logger.Debugf("ui: error filtering commits: %v", err)
+		return common.ErrorMsg(err)
+	}
+	if q.signed == "" && q.signer == "" {
+		return LogMatchesMsg(ids)
+	}
+
+	sigs, err := r.CommitSignatures(ids...)
+	if err != nil {
+		l.common.Logger.Debugf("ui: error loading commit signatures: %v", err)
+		return common.ErrorMsg(err)
+	}
+	matches := make([]string, 0, len(ids))
+	for _, id := range ids {
+		var pk gossh.PublicKey
+		if sig, ok := sigs[id]; ok {
+			pk, _ = commitSigner(sig)
+		}
+		if q.matchesSignature(pk) {
+			matches = append(matches, id)
+		}
+	}
+	return LogMatchesMsg(matches)
+}
+
+// loadSignatureCmd verifies the signature of the given commit in the
+// background.
+func (l *Log) loadSignatureCmd(c *git.Commit) tea.Cmd {
+	if c == nil || l.repo == nil {
+		return nil
+	}
+	id := c.ID.String()
+	if _, ok := l.signatures[id]; ok {
+		return nil
+	}
+
+	repo := l.repo
+	be := l.common.Backend()
+	ctx := l.common.Context()
+	return func() tea.Msg {
+		r, err := repo.Open()
+		if err != nil {
+			l.common.Logger.Debugf("ui: error loading commit signature: %v", err)
+			return nil
+		}
+		sigs, err := r.CommitSignatures(id)
+		if err != nil {
+			l.common.Logger.Debugf("ui: error loading commit signature: %v", err)
+			return nil
+		}
+		sig, ok := sigs[id]
+		if !ok {
+			return LogSignatureMsg{id: id}
+		}
+		if !sig.IsSSH() {
+			return LogSignatureMsg{id: id, signature: "not verified, not an SSH signature"}
+		}
+		pk, err := commitSigner(sig)
+		if err != nil {
+			return LogSignatureMsg{id: id, signature: "bad signature"}
+		}
+		signer := gossh.FingerprintSHA256(pk)
+		if be != nil {
+			if user, err := be.UserByPublicKey(ctx, pk); err == nil && user != nil {
+				signer = user.Username() + " " + signer
+			}
+		}
+		return LogSignatureMsg{id: id, signature: "good signature by " + signer}
+	}
+}
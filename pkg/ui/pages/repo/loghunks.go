@@ -0,0 +1,185 @@
+package repo
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/soft-serve/git"
+)
+
+var (
+	nextHunk = key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "next hunk"),
+	)
+	prevHunk = key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "previous hunk"),
+	)
+	nextFile = key.NewBinding(
+		key.WithKeys("}"),
+		key.WithHelp("}", "next file"),
+	)
+	prevFile = key.NewBinding(
+		key.WithKeys("{"),
+		key.WithHelp("{", "previous file"),
+	)
+	firstChange = key.NewBinding(
+		key.WithKeys("<"),
+		key.WithHelp("<", "first change"),
+	)
+	lastChange = key.NewBinding(
+		key.WithKeys(">"),
+		key.WithHelp(">", "last change"),
+	)
+
+	// hunkKeys and fileKeys sum up the hunk and file keys in the short help.
+	hunkKeys = key.NewBinding(
+		key.WithKeys("n", "N"),
+		key.WithHelp("n/N", "hunk"),
+	)
+	fileKeys = key.NewBinding(
+		key.WithKeys("}", "{"),
+		key.WithHelp("}/{", "file"),
+	)
+)
+
+// diffNavLine returns the patch line the hunk and file keys moved to, if
+// they did in the given diff and it wasn't scrolled away from since, or -1. It
+// must be called before the diff is rendered again.
+func (l *Log) diffNavLine(diff *git.Diff) int {
+	if diff != l.navDiff || l.navLine < 0 || l.navLine >= len(l.diffLines) || l.vp.YOffset != l.navOffset {
+		return -1
+	}
+	return l.diffLines[l.navLine]
+}
+
+// setDiffTargets maps the hunks and files of the diff to the rendered lines
+// of the diff view, and the patch line the hunk and file keys moved to, if
+// any, back to the position. It must be called once the lines are rendered.
+func (l *Log) setDiffTargets(diff *git.Diff, navLine int) {
+	l.hunkLines = l.hunkLines[:0]
+	l.fileLines = l.fileLines[:0]
+	l.navDiff = diff
+	l.navLine, l.navOffset = -1, 0
+	for _, h := range diff.Hunks() {
+		if i := l.renderedLine(h.Change); i >= 0 {
+			l.hunkLines = append(l.hunkLines, i)
+		}
+	}
+	for _, n := range diff.FileStarts() {
+		if i := l.renderedLine(n); i >= 0 {
+			l.fileLines = append(l.fileLines, i)
+		}
+	}
+	if navLine >= 0 {
+		l.navLine, l.navOffset = l.renderedLine(navLine), l.vp.YOffset
+	}
+}
+
+// renderedLine returns the first rendered line of the diff view at or after
+// the given patch line, or -1 if there's none.
+func (l *Log) renderedLine(n int) int {
+	for i, pl := range l.diffLines {
+		if pl >= n {
+			return i
+		}
+	}
+	return -1
+}
+
+// diffPosition returns the rendered line the hunk and file keys move from:
+// the line they moved to until the view is scrolled away from it, the middle
+// of the view otherwise. It's -1 at the top of the diff, so that the first
+// hunk is the next one even when it's in view.
+func (l *Log) diffPosition() int {
+	switch {
+	case l.navLine >= 0 && l.vp.YOffset == l.navOffset:
+		return l.navLine
+	case l.vp.YOffset == 0:
+		return -1
+	default:
+		return l.vp.YOffset + l.vp.Height/2
+	}
+}
+
+// gotoDiffLine centers the diff view on a rendered line, and makes it the
+// position of the hunk and file keys.
+func (l *Log) gotoDiffLine(line int) {
+	l.vp.CenterLine(line)
+	l.navLine, l.navOffset = line, l.vp.YOffset
+}
+
+// moveDiff moves the diff view to the next or previous of the given rendered
+// lines from the current position. what is shown in the status bar when
+// there's none.
+func (l *Log) moveDiff(lines []int, next bool, what string) tea.Cmd {
+	pos := l.diffPosition()
+	if next {
+		for _, line := range lines {
+			if line > pos {
+				l.gotoDiffLine(line)
+				return nil
+			}
+		}
+		return statusCmd(fmt.Sprintf("no next %s", what))
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		if lines[i] < pos {
+			l.gotoDiffLine(lines[i])
+			return nil
+		}
+	}
+	return statusCmd(fmt.Sprintf("no previous %s", what))
+}
+
+// updateDiffNav handles the hunk and file keys of the diff view. It returns
+// false if the key isn't one of them.
+func (l *Log) updateDiffNav(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, nextHunk):
+		return l.moveDiff(l.hunkLines, true, "hunk"), true
+	case key.Matches(msg, prevHunk):
+		return l.moveDiff(l.hunkLines, false, "hunk"), true
+	case key.Matches(msg, nextFile):
+		return l.moveDiff(l.fileLines, true, "file"), true
+	case key.Matches(msg, prevFile):
+		return l.moveDiff(l.fileLines, false, "file"), true
+	case key.Matches(msg, firstChange):
+		if len(l.hunkLines) > 0 {
+			l.gotoDiffLine(l.hunkLines[0])
+		}
+		return nil, true
+	case key.Matches(msg, lastChange):
+		if len(l.hunkLines) > 0 {
+			l.gotoDiffLine(l.hunkLines[len(l.hunkLines)-1])
+		}
+		return nil, true
+	}
+	return nil, false
+}
+
+// hunkInfo returns the index of the hunk at the current position out of all
+// hunks for the status bar, e.g. "hunk 3/12", or the number of hunks before
+// the first one.
+func (l *Log) hunkInfo() string {
+	total := len(l.hunkLines)
+	if total == 0 {
+		return ""
+	}
+	pos := l.diffPosition()
+	cur := 0
+	for _, line := range l.hunkLines {
+		if line <= pos {
+			cur++
+		}
+	}
+	if cur == 0 {
+		if total == 1 {
+			return "1 hunk"
+		}
+		return fmt.Sprintf("%d hunks", total)
+	}
+	return fmt.Sprintf("hunk %d/%d", cur, total)
+}
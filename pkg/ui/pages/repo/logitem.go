@@ -11,13 +11,19 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/ui/styles"
+	"github.com/dustin/go-humanize"
 	"github.com/muesli/reflow/truncate"
 )
 
 // LogItem is a item in the log list that displays a git commit.
 type LogItem struct {
 	*git.Commit
+
+	// State is the combined state of the commit statuses.
+	State proto.CommitState
 }
 
 // ID implements selector.IdentifiableItem.
@@ -44,13 +50,38 @@ func (i LogItem) Description() string { return "" }
 // FilterValue implements list.Item.
 func (i LogItem) FilterValue() string { return i.Title() }
 
+// identity returns the name and date of the author of the commit, or of its
+// committer.
+func (i LogItem) identity(committer bool) (string, time.Time) {
+	if committer {
+		return i.Committer.Name, i.Committer.When
+	}
+	return i.Author.Name, i.Author.When
+}
+
 // LogItemDelegate is the delegate for LogItem.
 type LogItemDelegate struct {
 	common *common.Common
+
+	// columns are the columns of a compact one line log. The default two
+	// line log is used when it's empty.
+	columns []common.LogColumn
+
+	// badges shows the type of Conventional Commits as a badge before the
+	// subject.
+	badges bool
+
+	// committer shows the committer name and date instead of the author's.
+	committer bool
 }
 
 // Height returns the item height. Implements list.ItemDelegate.
-func (d LogItemDelegate) Height() int { return 2 }
+func (d LogItemDelegate) Height() int {
+	if len(d.columns) > 0 {
+		return 1
+	}
+	return 2
+}
 
 // Spacing returns the item spacing. Implements list.ItemDelegate.
 func (d LogItemDelegate) Spacing() int { return 1 }
@@ -87,48 +118,72 @@ func (d LogItemDelegate) Render(w io.Writer, m list.Model, index int, listItem l
 	}
 
 	horizontalFrameSize := styles.Base.GetHorizontalFrameSize()
+	if len(d.columns) > 0 {
+		fmt.Fprint(w,
+			d.common.Zone.Mark(
+				i.ID(),
+				styles.Base.Render(d.renderColumns(i, index == m.Index(), m.Width()-horizontalFrameSize)),
+			),
+		)
+		return
+	}
 
 	hash := i.Commit.ID.String()[:7]
-	title := styles.Title.Render(
-		common.TruncateString(i.Title(),
-			m.Width()-
-				horizontalFrameSize-
-				// 9 is the length of the hash (7) + the left padding (1) + the
-				// title truncation symbol (1)
-				9),
-	)
+	status := renderCommitState(d.common.Styles, i.State)
+	var statusWidth int
+	if status != "" {
+		status = " " + status
+		statusWidth = lipgloss.Width(status)
+	}
+	title := d.renderSubject(styles.Title, i.Title(),
+		m.Width()-
+			horizontalFrameSize-
+			statusWidth-
+			// 9 is the length of the hash (7) + the left padding (1) + the
+			// title truncation symbol (1)
+			9)
 	hashStyle := styles.Hash.
 		Align(lipgloss.Right).
 		PaddingLeft(1).
 		Width(m.Width() -
 			horizontalFrameSize -
+			statusWidth -
 			lipgloss.Width(title) - 1) // 1 is for the left padding
 	if index == m.Index() {
 		hashStyle = hashStyle.Bold(true)
 	}
-	hash = hashStyle.Render(hash)
+	hash = hashStyle.Render(hash) + status
 	if m.Width()-horizontalFrameSize-hashStyle.GetHorizontalFrameSize()-hashStyle.GetWidth() <= 0 {
 		hash = ""
-		title = styles.Title.Render(
-			common.TruncateString(i.Title(),
-				m.Width()-horizontalFrameSize),
-		)
+		title = d.renderSubject(styles.Title, i.Title(), m.Width()-horizontalFrameSize)
 	}
 	author := i.Author.Name
 	committer := i.Committer.Name
+	_, when := i.identity(d.committer)
 	who := ""
 	if author != "" && committer != "" {
-		who = styles.Keyword.Render(committer) + styles.Desc.Render(" committed")
-		if author != committer {
-			who = styles.Keyword.Render(author) + styles.Desc.Render(" authored and ") + who
+		switch {
+		case d.committer:
+			who = styles.Keyword.Render(committer) + styles.Desc.Render(" committed ")
+		case author != committer:
+			who = styles.Keyword.Render(author) + styles.Desc.Render(" authored ")
+		default:
+			who = styles.Keyword.Render(author) + styles.Desc.Render(" committed ")
 		}
-		who += " "
 	}
-	date := i.Committer.When.Format("Jan 02")
-	if i.Committer.When.Year() != time.Now().Year() {
-		date += fmt.Sprintf(" %d", i.Committer.When.Year())
+	date := when.Format("Jan 02")
+	if when.Year() != time.Now().Year() {
+		date += fmt.Sprintf(" %d", when.Year())
 	}
 	who += styles.Desc.Render("on ") + styles.Keyword.Render(date)
+	if author != "" && committer != "" && author != committer {
+		// Name the other one when the history was rewritten.
+		if d.committer {
+			who += styles.Desc.Render(" (authored by ") + styles.Keyword.Render(author) + styles.Desc.Render(")")
+		} else {
+			who += styles.Desc.Render(" (committed by ") + styles.Keyword.Render(committer) + styles.Desc.Render(")")
+		}
+	}
 	who = common.TruncateString(who, m.Width()-horizontalFrameSize)
 	fmt.Fprint(w,
 		d.common.Zone.Mark(
@@ -145,3 +200,108 @@ func (d LogItemDelegate) Render(w io.Writer, m list.Model, index int, listItem l
 		),
 	)
 }
+
+// renderColumns renders the commit on a single line with the configured
+// columns. Every column is truncated or padded to its width so that the
+// columns line up.
+func (d LogItemDelegate) renderColumns(i LogItem, active bool, width int) string {
+	styles := d.common.Styles.LogItem.Normal
+	if active {
+		styles = d.common.Styles.LogItem.Active
+	}
+	status := renderCommitState(d.common.Styles, i.State)
+	if status != "" {
+		status = " " + status
+		width -= lipgloss.Width(status)
+	}
+
+	// The subject takes the space left by the other columns.
+	rest := width
+	for n, c := range d.columns {
+		if n > 0 {
+			rest--
+		}
+		rest -= c.Width
+	}
+
+	cells := make([]string, 0, len(d.columns))
+	for _, c := range d.columns {
+		w := c.Width
+		if w == 0 {
+			w = max(rest, 1)
+		}
+		var cell string
+		style := styles.Desc
+		switch c.Kind {
+		case common.LogColumnHash:
+			cell = i.Hash()[:min(w, len(i.Hash()))]
+			style = styles.Hash
+		case common.LogColumnAuthor:
+			cell, _ = i.identity(d.committer)
+			style = styles.Keyword
+		case common.LogColumnDate:
+			_, when := i.identity(d.committer)
+			cell = when.Format("Jan 02 2006")
+		case common.LogColumnAge:
+			_, when := i.identity(d.committer)
+			cell = humanize.Time(when)
+		case common.LogColumnSubject:
+			if d.badges {
+				if _, ok := common.ParseCommitType(i.Title()); ok {
+					cell = d.renderSubject(styles.Title, i.Title(), w)
+					cells = append(cells, cell+strings.Repeat(" ", max(w-lipgloss.Width(cell), 0)))
+					continue
+				}
+			}
+			cell = i.Title()
+			style = styles.Title
+		}
+		if lipgloss.Width(cell) > w {
+			cell = common.TruncateString(cell, w)
+		}
+		cells = append(cells, style.Render(cell+strings.Repeat(" ", max(w-lipgloss.Width(cell), 0))))
+	}
+
+	return truncate.String(strings.Join(cells, " ")+status, uint(width+lipgloss.Width(status)))
+}
+
+// renderSubject renders the subject of a commit truncated to width. The type
+// and scope of Conventional Commits are shown as a badge when badges are
+// enabled and there's room for them.
+func (d LogItemDelegate) renderSubject(style lipgloss.Style, subject string, width int) string {
+	if d.badges {
+		if ct, ok := common.ParseCommitType(subject); ok {
+			label := ct.Type
+			if ct.Breaking {
+				label += "!"
+			}
+			badge, ok := d.common.Styles.CommitType.Types[ct.Type]
+			if !ok {
+				badge = d.common.Styles.CommitType.Badge
+			}
+			prefix := badge.Render(label) + " "
+			if ct.Scope != "" {
+				prefix += d.common.Styles.CommitType.Scope.Render(ct.Scope) + " "
+			}
+			if pw := lipgloss.Width(prefix); pw < width {
+				return prefix + style.Render(common.TruncateString(ct.Description, width-pw))
+			}
+		}
+	}
+	return style.Render(common.TruncateString(subject, width))
+}
+
+// renderCommitState renders a color-coded indicator of a commit status state.
+// It returns an empty string if the state is empty.
+func renderCommitState(s *styles.Styles, state proto.CommitState) string {
+	switch state {
+	case proto.CommitStatePending:
+		return s.CommitStatus.Pending.Render("●")
+	case proto.CommitStateSuccess:
+		return s.CommitStatus.Success.Render("✓")
+	case proto.CommitStateFailure, proto.CommitStateError:
+		return s.CommitStatus.Failure.Render("✗")
+	default:
+		return ""
+	}
+}
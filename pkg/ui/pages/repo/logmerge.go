@@ -0,0 +1,103 @@
+package repo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/muesli/reflow/wrap"
+)
+
+var mergeDiff = key.NewBinding(
+	key.WithKeys("p"),
+	key.WithHelp("p", "merge diff"),
+)
+
+// LogResolutionMsg is a message that contains what a merge commit changes on
+// top of the automatic merge of its parents.
+type LogResolutionMsg *git.MergeResolution
+
+// mergeDiffs returns the number of diffs of a merge commit the diff view
+// cycles through, one against every parent, and one against the automatic
+// merge of two parents. Other commits have a single diff.
+func mergeDiffs(c *git.Commit) int {
+	switch n := c.ParentsCount(); {
+	case n < 2:
+		return 1
+	case n == 2:
+		return n + 1
+	default:
+		return n
+	}
+}
+
+// nextMergeDiff switches the diff view of a merge commit to its next diff.
+// It returns false for commits that aren't merges.
+func (l *Log) nextMergeDiff() bool {
+	c := l.selectedCommit
+	if c == nil || mergeDiffs(c) < 2 {
+		return false
+	}
+	l.mergeDiff = (l.mergeDiff + 1) % mergeDiffs(c)
+	return true
+}
+
+// showResolution returns true if the diff view shows the diff of the selected
+// merge commit against the automatic merge of its parents.
+func (l *Log) showResolution() bool {
+	c := l.selectedCommit
+	return c != nil && c.ParentsCount() == 2 && l.mergeDiff == 2
+}
+
+// mergeDiffKey returns the key that cycles through the diffs of the selected
+// commit, disabled for commits that aren't merges.
+func (l *Log) mergeDiffKey() key.Binding {
+	k := mergeDiff
+	k.SetEnabled(l.selectedCommit != nil && mergeDiffs(l.selectedCommit) > 1)
+	return k
+}
+
+// renderMergeDiff renders what the diff of a merge commit is against, and for
+// the diff against the automatic merge, the conflicts the merge resolved and
+// the files edited while merging. Edits come from neither parent and deserve
+// a careful review.
+func (l *Log) renderMergeDiff(c *git.Commit) string {
+	if c.ParentsCount() < 2 {
+		return ""
+	}
+	st := l.common.Styles.Log
+	parents := make([]string, 0, c.ParentsCount())
+	for i := 0; i < c.ParentsCount(); i++ {
+		if id, err := c.ParentID(i); err == nil {
+			parents = append(parents, id.String()[:7])
+		}
+	}
+
+	var s strings.Builder
+	res := l.resolution
+	switch {
+	case !l.showResolution():
+		if l.mergeDiff >= len(parents) {
+			return ""
+		}
+		s.WriteString(st.MergeDiff.Render(fmt.Sprintf("Diff against parent %d of %d, %s",
+			l.mergeDiff+1, c.ParentsCount(), parents[l.mergeDiff])) + "\n")
+	case res == nil:
+		return ""
+	default:
+		s.WriteString(st.MergeDiff.Render(fmt.Sprintf("Diff against the automatic merge of %s",
+			strings.Join(parents, " and "))) + "\n")
+		edits := res.Edits()
+		if len(res.Files) == 0 {
+			s.WriteString(st.CommitAuthor.Render("Clean merge, nothing changed on top of the automatic merge") + "\n")
+		}
+		if len(res.Conflicts) > 0 {
+			s.WriteString(st.CommitAuthor.Render("Resolved conflicts: "+strings.Join(res.Conflicts, ", ")) + "\n")
+		}
+		if len(edits) > 0 {
+			s.WriteString(st.MergeEdit.Render("Edited while merging: "+strings.Join(edits, ", ")) + "\n")
+		}
+	}
+	return wrap.String(s.String(), l.common.Width-2)
+}
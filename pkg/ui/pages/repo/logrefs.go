@@ -0,0 +1,233 @@
+package repo
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/ui/styles"
+)
+
+// messageRefRe matches the commit hashes and the issue references, e.g.
+// "#123", in commit messages.
+var messageRefRe = regexp.MustCompile(`[0-9a-fA-F]{7,40}|#[0-9]+`)
+
+// LogRefsMsg is a message that contains the commits referenced by the
+// message of a commit, keyed by the lower case hash in the message, and the
+// files of its tree the message mentions.
+type LogRefsMsg struct {
+	id    string
+	refs  map[string]*git.Commit
+	files []messageFile
+}
+
+// messageRef is a reference in a commit message.
+type messageRef struct {
+	start, end int
+	issue      bool
+	file       bool
+}
+
+// findMessageRefs returns the commit hashes and the issue references in the
+// message. References must be whole words.
+func findMessageRefs(msg string) []messageRef {
+	refs := make([]messageRef, 0)
+	for _, m := range messageRefRe.FindAllStringIndex(msg, -1) {
+		if (m[0] > 0 && isWordByte(msg[m[0]-1])) || (m[1] < len(msg) && isWordByte(msg[m[1]])) {
+			continue
+		}
+		refs = append(refs, messageRef{
+			start: m[0],
+			end:   m[1],
+			issue: msg[m[0]] == '#',
+		})
+	}
+	return refs
+}
+
+// isWordByte returns true if b is an ASCII letter, digit, or underscore.
+func isWordByte(b byte) bool {
+	return b == '_' ||
+		('0' <= b && b <= '9') ||
+		('a' <= b && b <= 'z') ||
+		('A' <= b && b <= 'Z')
+}
+
+// commitRefCandidates returns the unique lower case commit hashes in the
+// message.
+func commitRefCandidates(msg string) []string {
+	seen := map[string]bool{}
+	shas := make([]string, 0)
+	for _, ref := range findMessageRefs(msg) {
+		sha := strings.ToLower(msg[ref.start:ref.end])
+		if ref.issue || seen[sha] {
+			continue
+		}
+		seen[sha] = true
+		shas = append(shas, sha)
+	}
+	return shas
+}
+
+// resolveCommitRefs returns the commits of the repository referenced by the
+// message of the given commit. Hashes that don't resolve to a commit, and the
+// hash of the commit itself, are left out.
+func resolveCommitRefs(r *git.Repository, c *git.Commit) map[string]*git.Commit {
+	refs := map[string]*git.Commit{}
+	id := c.ID.String()
+	for _, sha := range commitRefCandidates(c.Message) {
+		if strings.HasPrefix(id, sha) {
+			continue
+		}
+		rc, err := r.CommitByRevision(sha)
+		if err != nil || !strings.HasPrefix(rc.ID.String(), sha) {
+			continue
+		}
+		refs[sha] = rc
+	}
+	return refs
+}
+
+// renderMessageRefs highlights the resolved commit hashes, the issue
+// references, and the resolved files in the message. Other hashes and paths
+// are left as is.
+func renderMessageRefs(st *styles.Styles, msg string, refs map[string]*git.Commit, files []messageFile) string {
+	linked := map[string]bool{}
+	for _, f := range files {
+		linked[f.path] = true
+	}
+	spans := make([]messageRef, 0)
+	for _, ref := range findMessageFiles(msg) {
+		if linked[messageFilePath(msg[ref.start:ref.end])] {
+			spans = append(spans, ref)
+		}
+	}
+	// Paths win over the hashes and issues in them, e.g. "docs/1234567.md".
+	n := len(spans)
+	for _, ref := range findMessageRefs(msg) {
+		if !ref.issue && refs[strings.ToLower(msg[ref.start:ref.end])] == nil {
+			continue
+		}
+		overlaps := false
+		for _, f := range spans[:n] {
+			if ref.start < f.end && f.start < ref.end {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			spans = append(spans, ref)
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].start < spans[j].start
+	})
+
+	var s strings.Builder
+	last := 0
+	for _, ref := range spans {
+		word := msg[ref.start:ref.end]
+		switch {
+		case ref.file:
+			word = st.Log.FileRef.Render(word)
+		case ref.issue:
+			word = st.Log.IssueRef.Render(word)
+		default:
+			word = st.Log.CommitRef.Render(word)
+		}
+		s.WriteString(msg[last:ref.start])
+		s.WriteString(word)
+		last = ref.end
+	}
+	s.WriteString(msg[last:])
+	return s.String()
+}
+
+// messageBody returns the message of the commit with its references
+// highlighted.
+func (l *Log) messageBody(c *git.Commit) string {
+	// FIXME: lipgloss prints empty lines when CRLF is used
+	// sanitize commit message from CRLF
+	msg := strings.ReplaceAll(c.Message, "\r\n", "\n")
+	id := c.ID.String()
+	return renderMessageRefs(l.common.Styles, msg, l.msgRefs[id], l.msgFiles[id])
+}
+
+// messageRefCommits returns the commits referenced by the message of the
+// given commit in the order they're mentioned.
+func (l *Log) messageRefCommits(c *git.Commit) []*git.Commit {
+	refs := l.msgRefs[c.ID.String()]
+	seen := map[string]bool{}
+	commits := make([]*git.Commit, 0)
+	for _, sha := range commitRefCandidates(c.Message) {
+		rc, ok := refs[sha]
+		if !ok || seen[rc.ID.String()] {
+			continue
+		}
+		seen[rc.ID.String()] = true
+		commits = append(commits, rc)
+	}
+	return commits
+}
+
+// loadMessageRefsCmd resolves the commit hashes and the paths in the message
+// of the given commit in the background. The references are loaded once per
+// commit.
+func (l *Log) loadMessageRefsCmd(c *git.Commit) tea.Cmd {
+	if c == nil || l.repo == nil {
+		return nil
+	}
+	id := c.ID.String()
+	if _, ok := l.msgRefs[id]; ok {
+		return nil
+	}
+	// Mark the commit as loaded so that moving the cursor back and forth
+	// doesn't resolve the same references again.
+	l.msgRefs[id] = nil
+	if len(commitRefCandidates(c.Message)) == 0 && len(messageFileCandidates(c.Message)) == 0 {
+		return nil
+	}
+
+	repo, ref := l.repo, l.ref
+	return func() tea.Msg {
+		r, err := repo.Open()
+		if err != nil {
+			l.common.Logger.Debugf("ui: error loading commit references: %v", err)
+			return nil
+		}
+		refs := resolveCommitRefs(r, c)
+		for _, rc := range refs {
+			l.mapCommits(r, ref, rc)
+		}
+		return LogRefsMsg{
+			id:    id,
+			refs:  refs,
+			files: resolveMessageFiles(r, c),
+		}
+	}
+}
+
+// messageRefsCmd jumps to the commit referenced by the message of the
+// selected commit, or asks the user to pick one when there are many.
+func (l *Log) messageRefsCmd() tea.Cmd {
+	c := l.selectedCommit
+	if c == nil {
+		return nil
+	}
+	commits := l.messageRefCommits(c)
+	switch len(commits) {
+	case 0:
+		return nil
+	case 1:
+		return tea.Batch(l.selectCommitCmd(commits[0]), l.startLoading())
+	default:
+		return func() tea.Msg {
+			return LogPickerMsg{
+				title:   "References",
+				commits: commits,
+			}
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package repo
+
+import (
+	"strconv"
+	"strings"
+
+	gitm "github.com/aymanbagabas/git-module"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/ui/styles"
+	"github.com/muesli/reflow/wrap"
+)
+
+// splitDiffWidth is the terminal width below which the diff view shows the
+// unified diff even when the split diff is on.
+const splitDiffWidth = 100
+
+var splitDiff = key.NewBinding(
+	key.WithKeys("s"),
+	key.WithHelp("s", "split diff"),
+)
+
+// splitKey returns the key that switches between the unified and the split
+// diffs.
+func (l *Log) splitKey() key.Binding {
+	k := splitDiff
+	if l.split {
+		k.SetHelp("s", "unified diff")
+	}
+	return k
+}
+
+// showSplit returns true if the diff view shows the split diff. Narrow
+// terminals fall back to the unified diff.
+func (l *Log) showSplit() bool {
+	return l.split && l.common.Width >= splitDiffWidth
+}
+
+// toggleSplit switches between the unified and the split diffs, and keeps the
+// line at the top of the diff view in view.
+func (l *Log) toggleSplit() {
+	l.split = !l.split
+	if l.selectedCommit == nil || l.currentDiff == nil {
+		return
+	}
+	line := -1
+	for _, pl := range l.diffLines[min(l.vp.YOffset, len(l.diffLines)):] {
+		if pl >= 0 {
+			line = pl
+			break
+		}
+	}
+	yOffset := l.vp.YOffset
+	l.setDiffContent(l.currentDiff)
+	if line < 0 {
+		l.vp.SetYOffset(yOffset)
+		return
+	}
+	for i, pl := range l.diffLines {
+		if pl >= line {
+			l.vp.SetYOffset(i)
+			return
+		}
+	}
+}
+
+// renderSplitDiffLines renders the diff side by side, the old file on the
+// left and the new one on the right, and returns the zero based line of the
+// patch of every rendered line like renderDiffLines. Paired lines highlight
+// the part of the line that changed.
+func renderSplitDiffLines(diff *git.Diff, s *styles.Styles, width int) (string, []int) {
+	rows := diff.Split()
+	maxLine := 0
+	for _, r := range rows {
+		if r.Left != nil {
+			maxLine = max(maxLine, r.Left.LeftLine)
+		}
+		if r.Right != nil {
+			maxLine = max(maxLine, r.Right.RightLine)
+		}
+	}
+	gutter := len(strconv.Itoa(maxLine))
+	side := (width - 3) / 2
+	content := max(side-gutter-1, 1)
+	divider := s.Log.SplitDivider.Render(" │ ")
+
+	rendered := []string{""}
+	lines := []int{-1}
+	for _, r := range rows {
+		if r.Header != "" {
+			style := s.Log.SplitHeader
+			if strings.HasPrefix(r.Header, "@@") {
+				style = s.Log.SplitHunk
+			}
+			for _, wl := range strings.Split(wrap.String(r.Header, width), "\n") {
+				rendered = append(rendered, style.Render(wl))
+				lines = append(lines, r.Line)
+			}
+			continue
+		}
+
+		left, right := splitLineContent(r, s)
+		lw := wrapSide(left, content)
+		rw := wrapSide(right, content)
+		for i := 0; i < max(len(lw), len(rw)); i++ {
+			var ls, rs string
+			var ln, rn int
+			if i < len(lw) {
+				ls = lw[i]
+			}
+			if i < len(rw) {
+				rs = rw[i]
+			}
+			if i == 0 {
+				if r.Left != nil {
+					ln = r.Left.LeftLine
+				}
+				if r.Right != nil {
+					rn = r.Right.RightLine
+				}
+			}
+			rendered = append(rendered,
+				splitGutter(s, ln, gutter)+" "+padSide(ls, content)+divider+
+					splitGutter(s, rn, gutter)+" "+rs)
+			lines = append(lines, r.Line)
+		}
+	}
+	return strings.Join(rendered, "\n"), lines
+}
+
+// splitLineContent returns the styled content of both sides of a row. The
+// part of a deleted line that differs from the added line it's paired with
+// is highlighted on both sides.
+func splitLineContent(r git.SplitRow, s *styles.Styles) (string, string) {
+	var left, right string
+	if r.Left != nil {
+		left = expandTabs(r.Left.Content)
+	}
+	if r.Right != nil {
+		right = expandTabs(r.Right.Content)
+	}
+	if r.Left != nil && r.Right != nil && r.Left.Type == gitm.DiffLineDelete && r.Right.Type == gitm.DiffLineAdd {
+		lp, rp := changedRange(left[1:], right[1:])
+		return left[:1] + highlightRange(left[1:], lp, s.Log.SplitDel, s.Log.SplitDelChange),
+			right[:1] + highlightRange(right[1:], rp, s.Log.SplitAdd, s.Log.SplitAddChange)
+	}
+	return styleLine(r.Left, left, s), styleLine(r.Right, right, s)
+}
+
+// styleLine styles a line of one side by its type.
+func styleLine(l *gitm.DiffLine, content string, s *styles.Styles) string {
+	if l == nil {
+		return ""
+	}
+	switch l.Type {
+	case gitm.DiffLineAdd:
+		return s.Log.SplitAdd.Render(content)
+	case gitm.DiffLineDelete:
+		return s.Log.SplitDel.Render(content)
+	default:
+		return content
+	}
+}
+
+// changedRange returns the byte ranges of a and b between their common
+// prefix and suffix.
+func changedRange(a, b string) ([2]int, [2]int) {
+	p := 0
+	for p < len(a) && p < len(b) && a[p] == b[p] {
+		p++
+	}
+	// Don't split multi-byte characters.
+	for p > 0 && p < len(a) && !isRuneStart(a[p]) {
+		p--
+	}
+	sa, sb := len(a), len(b)
+	for sa > p && sb > p && a[sa-1] == b[sb-1] {
+		sa--
+		sb--
+	}
+	for sa < len(a) && !isRuneStart(a[sa]) {
+		sa++
+		sb++
+	}
+	return [2]int{p, sa}, [2]int{p, sb}
+}
+
+// isRuneStart returns true if the byte starts a UTF-8 encoded character.
+func isRuneStart(b byte) bool {
+	return b&0xc0 != 0x80
+}
+
+// highlightRange styles the given byte range of the line with hl, and the rest
+// with style.
+func highlightRange(line string, r [2]int, style, hl lipgloss.Style) string {
+	if r[0] >= r[1] {
+		return style.Render(line)
+	}
+	var sb strings.Builder
+	if r[0] > 0 {
+		sb.WriteString(style.Render(line[:r[0]]))
+	}
+	sb.WriteString(hl.Render(line[r[0]:r[1]]))
+	if r[1] < len(line) {
+		sb.WriteString(style.Render(line[r[1]:]))
+	}
+	return sb.String()
+}
+
+// wrapSide wraps the content of one side to the given width.
+func wrapSide(content string, width int) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(wrap.String(content, width), "\n")
+}
+
+// padSide pads a line of the left side to the given width.
+func padSide(line string, width int) string {
+	return line + strings.Repeat(" ", max(width-lipgloss.Width(line), 0))
+}
+
+// splitGutter renders the line number of one side, or blank space for rows
+// without one.
+func splitGutter(s *styles.Styles, n, width int) string {
+	if n <= 0 {
+		return strings.Repeat(" ", width)
+	}
+	return s.Log.SplitLineNo.Render(strings.Repeat(" ", width-len(strconv.Itoa(n))) + strconv.Itoa(n))
+}
+
+// expandTabs replaces the tabs of a line with spaces, so both sides line up.
+func expandTabs(s string) string {
+	return strings.ReplaceAll(s, "\t", "    ")
+}
@@ -0,0 +1,132 @@
+package repo
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/git"
+)
+
+// LogIdentitiesMsg is a message that contains the canonical identities of the
+// co-authors of a commit, keyed by the identity in the message.
+type LogIdentitiesMsg struct {
+	id         string
+	identities map[string]string
+}
+
+// trailerLabels are the labels of the well-known trailers, keyed by the lower
+// case trailer key. Other trailers are labeled by their key.
+var trailerLabels = map[string]string{
+	"co-authored-by": "Co-authors",
+	"signed-off-by":  "Signed off by",
+	"reviewed-by":    "Reviewed by",
+	"acked-by":       "Acked by",
+	"tested-by":      "Tested by",
+	"reported-by":    "Reported by",
+}
+
+// isCoAuthor returns true if the trailer names a co-author of the commit.
+func isCoAuthor(t git.Trailer) bool {
+	return strings.EqualFold(t.Key, "co-authored-by")
+}
+
+// trailerGroup is the values of the trailers with the same key.
+type trailerGroup struct {
+	label  string
+	values []string
+}
+
+// groupTrailers groups the trailers by key in the order the keys first
+// appear.
+func groupTrailers(trailers []git.Trailer) []trailerGroup {
+	groups := make([]trailerGroup, 0)
+	index := map[string]int{}
+	for _, t := range trailers {
+		k := strings.ToLower(t.Key)
+		i, ok := index[k]
+		if !ok {
+			label, ok := trailerLabels[k]
+			if !ok {
+				label = t.Key
+			}
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, trailerGroup{label: label})
+		}
+		groups[i].values = append(groups[i].values, t.Value)
+	}
+	return groups
+}
+
+// renderTrailers renders the trailers grouped by key, with the co-authors
+// resolved to their canonical identities.
+func (l *Log) renderTrailers(trailers []git.Trailer) string {
+	resolved := make([]git.Trailer, len(trailers))
+	for i, t := range trailers {
+		if id, ok := l.identities[t.Value]; ok && isCoAuthor(t) {
+			t.Value = id
+		}
+		resolved[i] = t
+	}
+
+	groups := groupTrailers(resolved)
+	width := 0
+	for _, g := range groups {
+		width = max(width, lipgloss.Width(g.label)+1)
+	}
+	lines := make([]string, 0, len(trailers))
+	for _, g := range groups {
+		for i, v := range g.values {
+			label := ""
+			if i == 0 {
+				label = g.label + ":"
+			}
+			label = l.common.Styles.Log.TrailerKey.Render(fmt.Sprintf("%-*s", width, label))
+			lines = append(lines, label+" "+v)
+		}
+	}
+	return l.common.Styles.Log.CommitTrailers.Render(strings.Join(lines, "\n"))
+}
+
+// loadIdentitiesCmd resolves the co-authors of the given commit against the
+// mailmap of the repository in the background. Each identity is resolved
+// once.
+func (l *Log) loadIdentitiesCmd(c *git.Commit) tea.Cmd {
+	if c == nil || l.repo == nil {
+		return nil
+	}
+	_, trailers := git.SplitTrailers(strings.ReplaceAll(c.Message, "\r\n", "\n"))
+	ids := make([]string, 0)
+	for _, t := range trailers {
+		if _, ok := l.identities[t.Value]; ok || !isCoAuthor(t) || !git.IsIdentity(t.Value) {
+			continue
+		}
+		// Until it's resolved, the identity is shown as is.
+		l.identities[t.Value] = t.Value
+		ids = append(ids, t.Value)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	id := c.ID.String()
+	repo := l.repo
+	return func() tea.Msg {
+		r, err := repo.Open()
+		if err != nil {
+			l.common.Logger.Debugf("ui: error loading co-authors: %v", err)
+			return nil
+		}
+		identities, err := r.MapIdentities(ids...)
+		if err != nil {
+			l.common.Logger.Debugf("ui: error loading co-authors: %v", err)
+			return nil
+		}
+		return LogIdentitiesMsg{
+			id:         id,
+			identities: identities,
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package repo
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/db/models"
+	"github.com/dustin/go-humanize"
+)
+
+// mirrorInfo is the remotes of a mirror and the state of its syncs.
+type mirrorInfo struct {
+	remotes []git.Remote
+	sync    models.MirrorSync
+}
+
+// mirrorInfo returns the remotes and the syncs of the selected repository, or
+// nil if it's not a mirror.
+func (r *Repo) mirrorInfo() *mirrorInfo {
+	be := r.common.Backend()
+	if be == nil || r.selectedRepo == nil || !r.selectedRepo.IsMirror() {
+		return nil
+	}
+	ctx := r.common.Context()
+	name := r.selectedRepo.Name()
+	var m mirrorInfo
+	var err error
+	if m.remotes, err = be.MirrorRemotes(ctx, name); err != nil {
+		r.common.Logger.Debugf("ui: failed to get mirror remotes: %v", err)
+	}
+	if m.sync, err = be.MirrorSync(ctx, name); err != nil {
+		r.common.Logger.Debugf("ui: failed to get mirror sync: %v", err)
+	}
+	return &m
+}
+
+// mirrorView returns when the selected mirror was last synced and its
+// upstream, shown with the metadata of the repository. The upstream comes last
+// as it's the longest. Other remotes are counted, "repo info" lists them.
+func (r *Repo) mirrorView() string {
+	m := r.mirror
+	if m == nil {
+		return ""
+	}
+	s := "Never synced"
+	if m.sync.SyncedAt.Valid {
+		s = "Synced " + humanize.Time(m.sync.SyncedAt.Time)
+	}
+	if m.sync.Error != "" {
+		s += " · Last sync failed"
+	}
+	s += " · Mirror"
+	if len(m.remotes) > 0 {
+		s += " of " + m.remotes[0].URL
+		if n := len(m.remotes) - 1; n > 0 {
+			s += fmt.Sprintf(" (+%d)", n)
+		}
+	}
+	return s
+}
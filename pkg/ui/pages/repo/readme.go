@@ -2,23 +2,80 @@ package repo
 
 import (
 	"fmt"
+	"math"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/git"
 	"github.com/charmbracelet/soft-serve/pkg/backend"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
 	"github.com/charmbracelet/soft-serve/pkg/ui/components/code"
+	"github.com/charmbracelet/soft-serve/pkg/ui/components/selector"
+	"github.com/dustin/go-humanize"
+)
+
+var diffReadme = key.NewBinding(
+	key.WithKeys("d"),
+	key.WithHelp("d", "diff with ref"),
+)
+
+var readmeFull = key.NewBinding(
+	key.WithKeys("a"),
+	key.WithHelp("a", "view full"),
+)
+
+type readmeState int
+
+const (
+	readmeStateReadme readmeState = iota
+	readmeStatePicker
+	readmeStateDiff
+	readmeStateContents
 )
 
 // ReadmeMsg is a message sent when the readme is loaded.
 type ReadmeMsg struct {
 	Content string
 	Path    string
+	// Size is the size of the whole readme, Content is truncated when it's
+	// larger.
+	Size int64
+}
+
+// LanguagesMsg is a message sent when the languages of the repository are
+// loaded.
+type LanguagesMsg struct {
+	ref       string
+	languages []common.Language
 }
 
+// ReadmeRefsMsg is a message that contains the references the readme can be
+// compared with.
+type ReadmeRefsMsg struct {
+	head  string
+	items []selector.IdentifiableItem
+}
+
+// ReadmeDiffMsg is a message that contains the diff of the readme between two
+// references. The diff is nil if neither reference has a readme. unrelated is
+// true when the references share no history, e.g. an orphan branch.
+type ReadmeDiffMsg struct {
+	base      string
+	head      string
+	diff      *git.Diff
+	unrelated bool
+}
+
+// maxLanguages is the number of languages shown in the legend. The rest are
+// grouped as "Other".
+const maxLanguages = 5
+
 // Readme is the readme component page.
 type Readme struct {
 	common     common.Common
@@ -28,25 +85,71 @@ type Readme struct {
 	readmePath string
 	spinner    spinner.Model
 	isLoading  bool
+	languages  []common.Language
+
+	// state is the current view. refs lists the references to compare the
+	// readme with, and diff shows the diff of the readme from diffBase to
+	// the current reference.
+	state    readmeState
+	refs     *selector.Selector
+	diff     *code.Code
+	diffBase string
+
+	// contents is the table of contents of the readme, built from its
+	// headings.
+	contents *selector.Selector
+	headings []common.Heading
+
+	// offset is the scroll position to restore once the readme is loaded
+	// again after a refresh, or 0.
+	offset int
+
+	// truncated is the size of the whole readme when only its beginning is
+	// shown, 0 otherwise. full is true once the user asked for the whole
+	// readme of the repository.
+	truncated int64
+	full      bool
 }
 
+// readmeOffsetMsg is a message to scroll the readme back to where it was
+// before a refresh.
+type readmeOffsetMsg int
+
 // NewReadme creates a new readme model.
 func NewReadme(common common.Common) *Readme {
 	readme := code.New(common, "", "")
-	readme.NoContentStyle = readme.NoContentStyle.SetString("No readme found.")
+	msg := "No readme found."
+	if cfg := common.Config(); cfg != nil {
+		msg = cfg.UI.Empty.Readme
+	}
+	readme.NoContentStyle = readme.NoContentStyle.SetString(msg)
 	readme.UseGlamour = true
 	s := spinner.New(spinner.WithSpinner(spinner.Dot),
 		spinner.WithStyle(common.Styles.Spinner))
+	refs := selector.New(common, []selector.IdentifiableItem{}, RefItemDelegate{&common})
+	refs.SetShowFilter(false)
+	refs.SetShowHelp(false)
+	refs.SetShowPagination(false)
+	refs.SetShowStatusBar(false)
+	refs.SetShowTitle(false)
+	refs.SetFilteringEnabled(false)
+	refs.DisableQuitKeybindings()
 	return &Readme{
 		code:      readme,
 		common:    common,
 		spinner:   s,
 		isLoading: true,
+		refs:      refs,
+		diff:      code.New(common, "", ""),
+		contents:  newListSelector(common, HeadingItemDelegate{&common}),
 	}
 }
 
 // Path implements common.TabComponent.
 func (r *Readme) Path() string {
+	if r.state != readmeStateReadme {
+		return "diff" // XXX: this is a place holder and doesn't mean anything
+	}
 	return ""
 }
 
@@ -58,21 +161,66 @@ func (r *Readme) TabName() string {
 // SetSize implements common.Component.
 func (r *Readme) SetSize(width, height int) {
 	r.common.SetSize(width, height)
-	r.code.SetSize(width, height)
+	r.code.SetSize(width, height-lipgloss.Height(r.languagesView())-lipgloss.Height(r.truncatedView()))
+	r.refs.SetSize(width, height-2)
+	r.diff.SetSize(width, height)
+	r.contents.SetSize(width, height-2)
 }
 
 // ShortHelp implements help.KeyMap.
 func (r *Readme) ShortHelp() []key.Binding {
+	switch r.state {
+	case readmeStatePicker, readmeStateContents:
+		return []key.Binding{
+			r.common.KeyMap.UpDown,
+			r.common.KeyMap.SelectItem,
+			r.common.KeyMap.BackItem,
+		}
+	case readmeStateDiff:
+		return []key.Binding{
+			r.common.KeyMap.UpDown,
+			r.common.KeyMap.BackItem,
+		}
+	}
 	b := []key.Binding{
 		r.common.KeyMap.UpDown,
+		readmeContents,
+		diffReadme,
+	}
+	if r.truncated > 0 {
+		b = append(b, readmeFull)
 	}
 	return b
 }
 
 // FullHelp implements help.KeyMap.
 func (r *Readme) FullHelp() [][]key.Binding {
+	switch r.state {
+	case readmeStatePicker, readmeStateContents:
+		k := r.refs.KeyMap
+		return [][]key.Binding{
+			{
+				r.common.KeyMap.SelectItem,
+				r.common.KeyMap.BackItem,
+			},
+			{
+				k.CursorUp,
+				k.CursorDown,
+				k.NextPage,
+				k.PrevPage,
+			},
+		}
+	}
 	k := r.code.KeyMap
+	first := []key.Binding{readmeContents, diffReadme}
+	if r.truncated > 0 {
+		first = append(first, readmeFull)
+	}
+	if r.state == readmeStateDiff {
+		first = []key.Binding{r.common.KeyMap.BackItem}
+	}
 	b := [][]key.Binding{
+		first,
 		{
 			k.PageDown,
 			k.PageUp,
@@ -92,7 +240,18 @@ func (r *Readme) FullHelp() [][]key.Binding {
 // Init implements tea.Model.
 func (r *Readme) Init() tea.Cmd {
 	r.isLoading = true
-	return tea.Batch(r.spinner.Tick, r.updateReadmeCmd)
+	return tea.Batch(r.spinner.Tick, r.updateReadmeCmd())
+}
+
+// RestoreCmd implements refreshTab. The readme scrolls back to where it was.
+func (r *Readme) RestoreCmd() tea.Cmd {
+	offset := r.code.YOffset
+	if r.state != readmeStateReadme || offset == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		return readmeOffsetMsg(offset)
+	}
 }
 
 // Update implements tea.Model.
@@ -101,21 +260,124 @@ func (r *Readme) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case RepoMsg:
 		r.repo = msg
+		r.full = false
 	case RefMsg:
 		r.ref = msg
-		cmds = append(cmds, r.Init())
+		r.state = readmeStateReadme
+		r.languages = nil
+		r.SetSize(r.common.Width, r.common.Height)
+		cmds = append(cmds, r.Init(), r.languagesCmd)
 	case tea.WindowSizeMsg:
 		r.SetSize(msg.Width, msg.Height)
 	case EmptyRepoMsg:
+		r.isLoading = false
+		r.headings = nil
 		cmds = append(cmds,
-			r.code.SetContent(defaultEmptyRepoMsg(r.common.Config(),
-				r.repo.Name()), ".md"),
+			r.code.SetContent(emptyRepoMsg(r.common, r.repo.Name()), ".md"),
 		)
+	case LanguagesMsg:
+		if r.ref != nil && msg.ref == r.ref.ID {
+			r.languages = msg.languages
+			r.SetSize(r.common.Width, r.common.Height)
+		}
 	case ReadmeMsg:
 		r.isLoading = false
 		r.readmePath = msg.Path
+		r.truncated = 0
+		if msg.Size > int64(len(msg.Content)) {
+			r.truncated = msg.Size
+		}
+		r.SetSize(r.common.Width, r.common.Height)
 		r.code.GotoTop()
-		cmds = append(cmds, r.code.SetContent(msg.Content, msg.Path))
+		if r.offset > 0 {
+			r.code.SetYOffset(r.offset)
+			r.offset = 0
+		}
+		cmds = append(cmds,
+			r.code.SetContent(msg.Content, msg.Path),
+			r.setHeadings(msg.Content, msg.Path),
+		)
+	case readmeOffsetMsg:
+		if r.isLoading {
+			r.offset = int(msg)
+		} else {
+			r.code.SetYOffset(int(msg))
+		}
+	case ReadmeRefsMsg:
+		if r.ref != nil && msg.head == r.ref.ID {
+			r.isLoading = false
+			r.state = readmeStatePicker
+			r.refs.Select(0)
+			cmds = append(cmds, r.refs.SetItems(msg.items))
+		}
+	case ReadmeDiffMsg:
+		if r.ref != nil && msg.head == r.ref.ID {
+			r.isLoading = false
+			r.state = readmeStateDiff
+			r.diffBase = msg.base
+			r.diff.GotoTop()
+			cmds = append(cmds, r.diff.SetContent(r.renderDiff(msg), ".diff"))
+		}
+	case selector.SelectMsg:
+		switch i := msg.IdentifiableItem.(type) {
+		case RefItem:
+			if r.state == readmeStatePicker {
+				r.isLoading = true
+				cmds = append(cmds, r.spinner.Tick, r.readmeDiffCmd(i.Reference))
+			}
+		case HeadingItem:
+			if r.state == readmeStateContents {
+				cmds = append(cmds, r.gotoHeading(i))
+			}
+		}
+	case GoBackMsg:
+		r.goBack()
+	case tea.KeyMsg:
+		switch r.state {
+		case readmeStateReadme:
+			switch {
+			case key.Matches(msg, diffReadme) && r.ref != nil && !r.isLoading:
+				r.isLoading = true
+				cmds = append(cmds, r.spinner.Tick, r.readmeRefsCmd)
+			case key.Matches(msg, readmeContents) && !r.isLoading && !r.code.IsLoading():
+				cmds = append(cmds, r.openContents())
+				return r, tea.Batch(cmds...)
+			case key.Matches(msg, readmeFull) && r.truncated > 0 && !r.isLoading:
+				r.full = true
+				r.offset = r.code.YOffset
+				r.isLoading = true
+				cmds = append(cmds, r.spinner.Tick, r.updateReadmeCmd())
+				return r, tea.Batch(cmds...)
+			}
+		case readmeStateContents:
+			switch {
+			case key.Matches(msg, r.common.KeyMap.SelectItem):
+				cmds = append(cmds, r.contents.SelectItemCmd)
+			case key.Matches(msg, r.common.KeyMap.BackItem):
+				r.goBack()
+				return r, tea.Batch(cmds...)
+			}
+		case readmeStatePicker:
+			switch {
+			case key.Matches(msg, r.common.KeyMap.SelectItem):
+				cmds = append(cmds, r.refs.SelectItemCmd)
+			case key.Matches(msg, r.common.KeyMap.BackItem):
+				r.goBack()
+				return r, tea.Batch(cmds...)
+			}
+		case readmeStateDiff:
+			if key.Matches(msg, r.common.KeyMap.BackItem) {
+				r.goBack()
+				return r, tea.Batch(cmds...)
+			}
+		}
+	case code.RenderedMsg:
+		for _, c := range []*code.Code{r.code, r.diff} {
+			if _, cmd := c.Update(msg); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+		return r, tea.Batch(cmds...)
 	case spinner.TickMsg:
 		if r.isLoading && r.spinner.ID() == msg.ID {
 			s, cmd := r.spinner.Update(msg)
@@ -125,10 +387,31 @@ func (r *Readme) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 	}
-	c, cmd := r.code.Update(msg)
-	r.code = c.(*code.Code)
-	if cmd != nil {
-		cmds = append(cmds, cmd)
+	switch r.state {
+	case readmeStatePicker:
+		m, cmd := r.refs.Update(msg)
+		r.refs = m.(*selector.Selector)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	case readmeStateDiff:
+		c, cmd := r.diff.Update(msg)
+		r.diff = c.(*code.Code)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	case readmeStateContents:
+		m, cmd := r.contents.Update(msg)
+		r.contents = m.(*selector.Selector)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	default:
+		c, cmd := r.code.Update(msg)
+		r.code = c.(*code.Code)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
 	}
 	return r, tea.Batch(cmds...)
 }
@@ -138,9 +421,121 @@ func (r *Readme) View() string {
 	if r.isLoading {
 		return renderLoading(r.common, r.spinner)
 	}
+	switch r.state {
+	case readmeStatePicker:
+		return lipgloss.JoinVertical(lipgloss.Left,
+			r.common.Styles.Log.CommitHash.Render("Compare the readme of "+r.refName()+" with"),
+			"",
+			r.refs.View(),
+		)
+	case readmeStateDiff:
+		return r.diff.View()
+	case readmeStateContents:
+		return lipgloss.JoinVertical(lipgloss.Left,
+			r.common.Styles.Log.CommitHash.Render("Contents of "+filepath.Base(r.readmePath)),
+			"",
+			r.contents.View(),
+		)
+	}
+	views := make([]string, 0, 3)
+	for _, v := range []string{r.languagesView(), r.truncatedView()} {
+		if v != "" {
+			views = append(views, v)
+		}
+	}
+	if len(views) > 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, append(views, r.code.View())...)
+	}
 	return r.code.View()
 }
 
+// truncatedView renders the notice shown above a truncated readme.
+func (r *Readme) truncatedView() string {
+	if r.truncated == 0 {
+		return ""
+	}
+	st := r.common.Styles.Repo.Truncated
+	width := r.common.Width - st.GetHorizontalFrameSize()
+	if width <= 0 {
+		return ""
+	}
+	return st.Render(common.TruncateString(fmt.Sprintf("Showing the beginning of a %s readme, press %s to view full.",
+		humanize.Bytes(uint64(r.truncated)), readmeFull.Help().Key), width))
+}
+
+// languagesView renders the languages of the repository as a bar and a
+// legend.
+func (r *Readme) languagesView() string {
+	return renderLanguages(r.common, r.languages)
+}
+
+// renderLanguages renders languages as a bar and a legend as wide as the
+// component.
+func renderLanguages(c common.Common, languages []common.Language) string {
+	if len(languages) == 0 {
+		return ""
+	}
+
+	st := c.Styles.Repo
+	width := c.Width - st.Languages.GetHorizontalFrameSize()
+	if width <= 0 {
+		return ""
+	}
+
+	// Split the bar using the largest remainder method so that the segments
+	// always fill the whole width.
+	widths := make([]int, len(languages))
+	rems := make([]float64, len(languages))
+	used := 0
+	for i, l := range languages {
+		w := l.Percent / 100 * float64(width)
+		widths[i] = int(w)
+		rems[i] = w - math.Floor(w)
+		used += widths[i]
+	}
+	for ; used < width; used++ {
+		j := 0
+		for i := range rems {
+			if rems[i] > rems[j] {
+				j = i
+			}
+		}
+		widths[j]++
+		rems[j] = -1
+	}
+
+	var bar strings.Builder
+	for i, l := range languages {
+		if widths[i] == 0 {
+			continue
+		}
+		bar.WriteString(c.Renderer.NewStyle().
+			Foreground(common.LanguageColor(l.Name)).
+			Render(strings.Repeat("▬", widths[i])))
+	}
+
+	legend := make([]string, 0, maxLanguages+1)
+	var other float64
+	for i, l := range languages {
+		if i >= maxLanguages {
+			other += l.Percent
+			continue
+		}
+		dot := c.Renderer.NewStyle().
+			Foreground(common.LanguageColor(l.Name)).
+			Render("●")
+		legend = append(legend, dot+" "+st.Language.Render(fmt.Sprintf("%s %.1f%%", l.Name, l.Percent)))
+	}
+	if other > 0 {
+		legend = append(legend, st.Language.Render(fmt.Sprintf("Other %.1f%%", other)))
+	}
+
+	return st.Languages.Render(lipgloss.JoinVertical(lipgloss.Left,
+		bar.String(),
+		common.TruncateString(strings.Join(legend, "  "), width),
+	))
+}
+
 // SpinnerID implements common.TabComponent.
 func (r *Readme) SpinnerID() int {
 	return r.spinner.ID()
@@ -148,6 +543,20 @@ func (r *Readme) SpinnerID() int {
 
 // StatusBarValue implements statusbar.StatusBar.
 func (r *Readme) StatusBarValue() string {
+	switch r.state {
+	case readmeStatePicker:
+		if i, ok := r.refs.SelectedItem().(RefItem); ok {
+			return i.Short()
+		}
+		return " "
+	case readmeStateDiff:
+		return fmt.Sprintf("%s → %s", r.diffBase, r.refName())
+	case readmeStateContents:
+		if i, ok := r.contents.SelectedItem().(HeadingItem); ok {
+			return i.Heading.Title
+		}
+		return " "
+	}
 	dir := filepath.Dir(r.readmePath)
 	if dir == "." || dir == "" {
 		return " "
@@ -157,16 +566,193 @@ func (r *Readme) StatusBarValue() string {
 
 // StatusBarInfo implements statusbar.StatusBar.
 func (r *Readme) StatusBarInfo() string {
+	switch r.state {
+	case readmeStatePicker:
+		totalPages := r.refs.TotalPages()
+		if totalPages <= 1 {
+			return "p. 1/1"
+		}
+		return fmt.Sprintf("p. %d/%d", r.refs.Page()+1, totalPages)
+	case readmeStateContents:
+		totalPages := r.contents.TotalPages()
+		if totalPages <= 1 {
+			return "p. 1/1"
+		}
+		return fmt.Sprintf("p. %d/%d", r.contents.Page()+1, totalPages)
+	case readmeStateDiff:
+		return fmt.Sprintf("☰ %d%%", r.diff.ScrollPosition())
+	}
 	return fmt.Sprintf("☰ %d%%", r.code.ScrollPosition())
 }
 
-func (r *Readme) updateReadmeCmd() tea.Msg {
-	m := ReadmeMsg{}
-	if r.repo == nil {
-		return common.ErrorMsg(common.ErrMissingRepo)
+// refName returns the short name of the current reference.
+func (r *Readme) refName() string {
+	if r.ref == nil {
+		return ""
+	}
+	return (*git.Reference)(r.ref).Name().Short()
+}
+
+// goBack goes back from the diff to the reference picker, and from the
+// picker or the table of contents to the readme.
+func (r *Readme) goBack() {
+	switch r.state {
+	case readmeStateDiff:
+		r.state = readmeStatePicker
+	case readmeStatePicker, readmeStateContents:
+		r.state = readmeStateReadme
+	}
+}
+
+// readmeRefsCmd loads the branches and tags to compare the readme with, most
+// recently updated first. The current reference is left out.
+func (r *Readme) readmeRefsCmd() tea.Msg {
+	if r.repo == nil || r.ref == nil {
+		return nil
+	}
+	rr, err := r.repo.Open()
+	if err != nil {
+		return common.ErrorMsg(err)
+	}
+	refs, err := referencesInfo(r.repo, rr, git.RefsHeads, git.RefsTags)
+	if err != nil {
+		r.common.Logger.Debugf("ui: error getting references: %v", err)
+		return common.ErrorMsg(err)
+	}
+	head := (*git.Reference)(r.ref)
+	its := make(RefItems, 0, len(refs))
+	for _, ref := range refs {
+		if ref.Name() == head.Name() {
+			continue
+		}
+		its = append(its, RefItem{
+			Reference:  ref.Reference,
+			Commit:     ref.Commit,
+			TagMessage: ref.TagMessage,
+		})
+	}
+	sort.Stable(its)
+	items := make([]selector.IdentifiableItem, len(its))
+	for i, it := range its {
+		items[i] = it
+	}
+	return ReadmeRefsMsg{
+		head:  head.ID,
+		items: items,
+	}
+}
+
+// readmeDiffCmd loads the diff of the readme from base to the current
+// reference.
+func (r *Readme) readmeDiffCmd(base *git.Reference) tea.Cmd {
+	repo := r.repo
+	head := (*git.Reference)(r.ref)
+	return func() tea.Msg {
+		diff, err := backend.ReadmeDiff(repo, base, head, readmeFile(&r.common, repo))
+		if err != nil {
+			r.common.Logger.Debugf("ui: error loading readme diff: %v", err)
+			return common.ErrorMsg(err)
+		}
+		var unrelated bool
+		if rr, err := repo.Open(); err == nil {
+			unrelated, _ = rr.Unrelated(base.ID, head.ID)
+		}
+		return ReadmeDiffMsg{
+			base:      base.Name().Short(),
+			head:      head.ID,
+			diff:      diff,
+			unrelated: unrelated,
+		}
+	}
+}
+
+// renderDiff renders the diff of the readme with the diff renderer of the
+// log.
+func (r *Readme) renderDiff(msg ReadmeDiffMsg) string {
+	title := r.common.Styles.Log.CommitHash.Render(
+		fmt.Sprintf("Readme %s → %s", msg.base, r.refName()))
+	if msg.unrelated {
+		title += " " + r.common.Styles.Ref.ItemUnrelated.Render("(unrelated histories)")
+	}
+	switch {
+	case msg.diff == nil:
+		return lipgloss.JoinVertical(lipgloss.Left, title, "",
+			fmt.Sprintf("Neither %s nor %s has a readme.", msg.base, r.refName()))
+	case len(msg.diff.Files) == 0:
+		return lipgloss.JoinVertical(lipgloss.Left, title, "",
+			"The readme is the same.")
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		title,
+		"",
+		renderSummary(msg.diff, r.common.Styles, r.common.Width),
+		renderDiff(msg.diff, r.common.Styles, r.common.Width),
+	)
+}
+
+func (r *Readme) languagesCmd() tea.Msg {
+	if r.repo == nil || r.ref == nil {
+		return nil
+	}
+	rr, err := r.repo.Open()
+	if err != nil {
+		return nil
+	}
+	ref := r.ref
+	langs, err := common.Languages(rr, ref)
+	if err != nil {
+		r.common.Logger.Debugf("ui: error getting languages: %v", err)
+		return nil
+	}
+	return LanguagesMsg{
+		ref:       ref.ID,
+		languages: langs,
+	}
+}
+
+// readmeFile returns the path of the file the repository shows as its readme,
+// or an empty string to show its README.
+func readmeFile(c *common.Common, repo proto.Repository) string {
+	be := c.Backend()
+	if be == nil || repo == nil {
+		return ""
+	}
+	p, err := be.ReadmePath(c.Context(), repo.Name())
+	if err != nil {
+		c.Logger.Debugf("ui: failed to get readme path: %v", err)
+		return ""
+	}
+	return p
+}
+
+// updateReadmeCmd loads the readme. The readme path and size limit are read
+// here, the command only uses their values.
+func (r *Readme) updateReadmeCmd() tea.Cmd {
+	repo, ref := r.repo, r.ref
+	if repo == nil {
+		return common.ErrorCmd(common.ErrMissingRepo)
+	}
+	path := readmeFile(&r.common, repo)
+	maxSize := r.readmeMaxSize()
+	return func() tea.Msg {
+		rm, rp, size, _ := backend.ReadmeFileLimit(repo, ref, path, maxSize)
+		return ReadmeMsg{
+			Content: rm,
+			Path:    rp,
+			Size:    size,
+		}
+	}
+}
+
+// readmeMaxSize returns the size of the largest readme shown whole, 0 once
+// the user asked for the whole readme. Larger readmes are truncated so that
+// they render quickly.
+func (r *Readme) readmeMaxSize() int64 {
+	if r.full {
+		return 0
+	}
+	if vs := loadViewSettings(r.common, r.repo); vs.ReadmeMaxSize > 0 {
+		return vs.ReadmeMaxSize
 	}
-	rm, rp, _ := backend.Readme(r.repo, r.ref)
-	m.Content = rm
-	m.Path = rp
-	return m
+	return backend.DefaultReadmeMaxSize
 }
@@ -0,0 +1,153 @@
+package repo
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/ui/components/selector"
+)
+
+var readmeContents = key.NewBinding(
+	key.WithKeys("t"),
+	key.WithHelp("t", "contents"),
+)
+
+// HeadingItem is a heading in the table of contents of the readme.
+type HeadingItem struct {
+	common.Heading
+	index int
+}
+
+// ID implements selector.IdentifiableItem.
+func (i HeadingItem) ID() string {
+	return strconv.Itoa(i.index)
+}
+
+// Title implements list.DefaultItem.
+func (i HeadingItem) Title() string {
+	return i.Heading.Title
+}
+
+// Description implements list.DefaultItem.
+func (i HeadingItem) Description() string {
+	return ""
+}
+
+// FilterValue implements list.Item.
+func (i HeadingItem) FilterValue() string { return i.Heading.Title }
+
+// HeadingItemDelegate is the delegate for the table of contents of the
+// readme.
+type HeadingItemDelegate struct {
+	common *common.Common
+}
+
+// Height implements list.ItemDelegate.
+func (d HeadingItemDelegate) Height() int { return 1 }
+
+// Spacing implements list.ItemDelegate.
+func (d HeadingItemDelegate) Spacing() int { return 0 }
+
+// Update implements list.ItemDelegate.
+func (d HeadingItemDelegate) Update(tea.Msg, *list.Model) tea.Cmd { return nil }
+
+// Render implements list.ItemDelegate.
+func (d HeadingItemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(HeadingItem)
+	if !ok {
+		return
+	}
+
+	s := d.common.Styles.Tree
+	nameStyle := s.Normal.FileName
+	selector := s.Selector.Render(" ")
+	if index == m.Index() {
+		nameStyle = s.Active.FileName
+		selector = s.Selector.Render(">")
+	}
+
+	// Sub headings are indented under their parents.
+	indent := strings.Repeat("  ", i.Level-1)
+	width := m.Width() - lipgloss.Width(selector) - 1 - len(indent) - nameStyle.GetHorizontalFrameSize()
+	fmt.Fprint(w, //nolint:errcheck
+		d.common.Zone.Mark(
+			i.ID(),
+			selector+" "+indent+nameStyle.Render(common.TruncateString(i.Heading.Title, width)),
+		),
+	)
+}
+
+// setHeadings sets the headings of the table of contents from the content of
+// the readme. Only markdown readmes have headings.
+func (r *Readme) setHeadings(content, path string) tea.Cmd {
+	r.headings = nil
+	if common.IsFileMarkdown(content, path) {
+		r.headings = common.MarkdownHeadings(content)
+	}
+	items := make([]selector.IdentifiableItem, len(r.headings))
+	for i, h := range r.headings {
+		items[i] = HeadingItem{Heading: h, index: i}
+	}
+	r.contents.Select(0)
+	return r.contents.SetItems(items)
+}
+
+// headingLines returns the rendered line of every heading of the readme, or
+// -1 for the headings that aren't found. Headings are looked for one after
+// the other, so that a title repeated in the text before its heading isn't
+// mistaken for it.
+func (r *Readme) headingLines() []int {
+	lines := make([]int, len(r.headings))
+	from := 0
+	for i, h := range r.headings {
+		lines[i] = r.code.FindLine(from, matchHeading(h.Title))
+		if lines[i] >= 0 {
+			from = lines[i] + 1
+		}
+	}
+	return lines
+}
+
+// matchHeading returns a function that tells whether a rendered line shows
+// the given heading title. Long titles may wrap, their first line matches.
+func matchHeading(title string) func(string) bool {
+	return func(line string) bool {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+		return line == title || (len(line) >= 8 && strings.HasPrefix(title, line+" "))
+	}
+}
+
+// openContents shows the table of contents of the readme, with the heading of
+// the section in view selected.
+func (r *Readme) openContents() tea.Cmd {
+	if len(r.headings) == 0 {
+		return statusCmd("No headings found in the readme.")
+	}
+	sel := 0
+	for i, l := range r.headingLines() {
+		if l >= 0 && l <= r.code.YOffset {
+			sel = i
+		}
+	}
+	r.state = readmeStateContents
+	r.contents.Select(sel)
+	return nil
+}
+
+// gotoHeading scrolls the readme to the given heading.
+func (r *Readme) gotoHeading(i HeadingItem) tea.Cmd {
+	r.state = readmeStateReadme
+	lines := r.headingLines()
+	if i.index >= len(lines) || lines[i.index] < 0 {
+		return statusCmd(fmt.Sprintf("Heading %q not found in the readme.", i.Heading.Title))
+	}
+	r.code.SetYOffset(lines[i.index])
+	return nil
+}
@@ -2,8 +2,9 @@ package repo
 
 import (
 	"fmt"
-	"sort"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
@@ -11,28 +12,128 @@ import (
 	"github.com/charmbracelet/soft-serve/git"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/ui/components/code"
 	"github.com/charmbracelet/soft-serve/pkg/ui/components/selector"
 )
 
+var (
+	mergeCheck = key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "merge check"),
+	)
+	tagDetails = key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "tag details"),
+	)
+	sortRefs = key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "sort by name"),
+	)
+	groupRefs = key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "group by prefix"),
+	)
+	pinRef = key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "pin ref"),
+	)
+)
+
+type refsState int
+
+const (
+	refsStateList refsState = iota
+	refsStateMerge
+	refsStateConflict
+	refsStateTag
+)
+
 // RefMsg is a message that contains a git.Reference.
 type RefMsg *git.Reference
 
 // RefItemsMsg is a message that contains a list of RefItem.
 type RefItemsMsg struct {
 	prefix string
-	items  []selector.IdentifiableItem
+	head   string
+	items  RefItems
+}
+
+// RefUnrelatedMsg is a message that contains the branches of a page that
+// share no history with the default branch.
+type RefUnrelatedMsg struct {
+	prefix    string
+	unrelated map[string]bool
+}
+
+// RefMergeMsg is a message that contains the result of a trial merge of a
+// branch into the current branch.
+type RefMergeMsg struct {
+	prefix string
+	base   string
+	head   string
+	result *git.MergeResult
+}
+
+// RefConflictMsg is a message that contains the three-way merge of a
+// conflicting path.
+type RefConflictMsg struct {
+	prefix  string
+	path    string
+	content string
+}
+
+// RefTagMsg is a message that contains the details of a tag and the tags it
+// points to.
+type RefTagMsg struct {
+	prefix  string
+	name    string
+	content string
+}
+
+// refMerge is the state of the merge check view.
+type refMerge struct {
+	base   string
+	head   string
+	result *git.MergeResult
+	cursor int
 }
 
 // Refs is a component that displays a list of references.
 type Refs struct {
 	common    common.Common
 	selector  *selector.Selector
+	code      *code.Code
 	repo      proto.Repository
 	ref       *git.Reference
 	activeRef *git.Reference
 	refPrefix string
 	spinner   spinner.Model
 	isLoading bool
+	state     refsState
+	merge     *refMerge
+	conflict  string
+	tag       string
+
+	// refs holds the loaded references. Branches are sorted by sortBy and,
+	// when grouped is set, folded under their prefix. folds holds the groups
+	// the user folded or unfolded, other groups are folded unless they hold
+	// the current branch. activeGroup is the prefix of the selected group
+	// header.
+	refs        RefItems
+	sortBy      refsSort
+	grouped     bool
+	folds       map[string]bool
+	activeGroup string
+
+	// head is the commit of the default branch. Checking whether a branch
+	// shares history with it is expensive, so it's only done for the
+	// branches shown. checked holds the branches checked so far.
+	head    string
+	checked map[string]bool
+
+	// pinned is the full name of the ref the user pinned the repository to,
+	// the one it opens on instead of HEAD.
+	pinned string
 }
 
 // NewRefs creates a new Refs component.
@@ -41,6 +142,8 @@ func NewRefs(common common.Common, refPrefix string) *Refs {
 		common:    common,
 		refPrefix: refPrefix,
 		isLoading: true,
+		folds:     make(map[string]bool),
+		checked:   make(map[string]bool),
 	}
 	s := selector.New(common, []selector.IdentifiableItem{}, RefItemDelegate{&common})
 	s.SetShowFilter(false)
@@ -51,6 +154,8 @@ func NewRefs(common common.Common, refPrefix string) *Refs {
 	s.SetFilteringEnabled(false)
 	s.DisableQuitKeybindings()
 	r.selector = s
+	r.code = code.New(common, "", "")
+	r.code.ShowLineNumber = true
 	sp := spinner.New(spinner.WithSpinner(spinner.Dot),
 		spinner.WithStyle(common.Styles.Spinner))
 	r.spinner = sp
@@ -76,26 +181,92 @@ func (r *Refs) TabName() string {
 func (r *Refs) SetSize(width, height int) {
 	r.common.SetSize(width, height)
 	r.selector.SetSize(width, height)
+	r.code.SetSize(width, height)
 }
 
 // ShortHelp implements help.KeyMap.
 func (r *Refs) ShortHelp() []key.Binding {
+	switch r.state {
+	case refsStateMerge:
+		return []key.Binding{
+			r.common.KeyMap.UpDown,
+			r.common.KeyMap.SelectItem,
+			r.common.KeyMap.BackItem,
+		}
+	case refsStateConflict, refsStateTag:
+		return []key.Binding{
+			r.common.KeyMap.UpDown,
+			r.common.KeyMap.BackItem,
+			r.common.KeyMap.GotoTop,
+			r.common.KeyMap.GotoBottom,
+		}
+	}
 	copyKey := r.common.KeyMap.Copy
 	copyKey.SetHelp("c", "copy ref")
 	k := r.selector.KeyMap
-	return []key.Binding{
+	b := []key.Binding{
 		r.common.KeyMap.SelectItem,
 		k.CursorUp,
 		k.CursorDown,
 		copyKey,
+		r.pinKey(),
+	}
+	switch r.refPrefix {
+	case git.RefsHeads:
+		b = append(b, mergeCheck, r.sortKey(), r.groupKey())
+	case git.RefsTags:
+		b = append(b, tagDetails)
 	}
+	return b
 }
 
 // FullHelp implements help.KeyMap.
 func (r *Refs) FullHelp() [][]key.Binding {
+	switch r.state {
+	case refsStateMerge:
+		return [][]key.Binding{
+			{
+				r.common.KeyMap.SelectItem,
+				r.common.KeyMap.BackItem,
+			},
+			{
+				r.common.KeyMap.Up,
+				r.common.KeyMap.Down,
+			},
+		}
+	case refsStateConflict, refsStateTag:
+		k := r.code.KeyMap
+		return [][]key.Binding{
+			{r.common.KeyMap.BackItem},
+			{
+				k.PageDown,
+				k.PageUp,
+				k.HalfPageDown,
+				k.HalfPageUp,
+			},
+			{
+				k.Down,
+				k.Up,
+				r.common.KeyMap.GotoTop,
+				r.common.KeyMap.GotoBottom,
+			},
+		}
+	}
 	copyKey := r.common.KeyMap.Copy
 	copyKey.SetHelp("c", "copy ref")
 	k := r.selector.KeyMap
+	last := []key.Binding{
+		k.GoToStart,
+		k.GoToEnd,
+		copyKey,
+		r.pinKey(),
+	}
+	switch r.refPrefix {
+	case git.RefsHeads:
+		last = append(last, mergeCheck, r.sortKey(), r.groupKey())
+	case git.RefsTags:
+		last = append(last, tagDetails)
+	}
 	return [][]key.Binding{
 		{r.common.KeyMap.SelectItem},
 		{
@@ -104,17 +275,15 @@ func (r *Refs) FullHelp() [][]key.Binding {
 			k.NextPage,
 			k.PrevPage,
 		},
-		{
-			k.GoToStart,
-			k.GoToEnd,
-			copyKey,
-		},
+		last,
 	}
 }
 
 // Init implements tea.Model.
 func (r *Refs) Init() tea.Cmd {
 	r.isLoading = true
+	r.state = refsStateList
+	r.merge = nil
 	return tea.Batch(r.spinner.Tick, r.updateItemsCmd)
 }
 
@@ -125,25 +294,38 @@ func (r *Refs) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case RepoMsg:
 		r.selector.Select(0)
 		r.repo = msg
+		r.folds = make(map[string]bool)
+		r.pinned = r.common.PinnedRef(msg.Name())
 	case RefMsg:
 		r.ref = msg
 		cmds = append(cmds, r.Init())
+	case code.RenderedMsg:
+		c, cmd := r.code.Update(msg)
+		r.code = c.(*code.Code)
+		return r, cmd
 	case tea.WindowSizeMsg:
 		r.SetSize(msg.Width, msg.Height)
 	case RefItemsMsg:
 		if r.refPrefix == msg.prefix {
-			cmds = append(cmds, r.selector.SetItems(msg.items))
-			i := r.selector.SelectedItem()
-			if i != nil {
-				r.activeRef = i.(RefItem).Reference
-			}
+			r.refs = msg.items
+			r.head = msg.head
+			r.checked = make(map[string]bool)
+			cmds = append(cmds, r.updateList())
 			r.isLoading = false
 		}
-	case selector.ActiveMsg:
-		switch sel := msg.IdentifiableItem.(type) {
-		case RefItem:
-			r.activeRef = sel.Reference
+	case RefUnrelatedMsg:
+		if r.refPrefix == msg.prefix {
+			for i, ref := range r.refs {
+				if msg.unrelated[ref.ID()] {
+					r.refs[i].Unrelated = true
+				}
+			}
+			cmds = append(cmds, r.updateList())
 		}
+	case selector.WindowMsg:
+		cmds = append(cmds, r.unrelatedCmd(msg.Items))
+	case selector.ActiveMsg:
+		r.setActive(msg.IdentifiableItem)
 	case selector.SelectMsg:
 		switch i := msg.IdentifiableItem.(type) {
 		case RefItem:
@@ -151,15 +333,111 @@ func (r *Refs) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				switchRefCmd(i.Reference),
 				switchTabCmd(&Files{}),
 			)
+		case RefGroupItem:
+			r.folds[i.Prefix] = !i.Collapsed
+			cmds = append(cmds, r.updateList())
 		}
 	case tea.KeyMsg:
-		switch {
-		case key.Matches(msg, r.common.KeyMap.SelectItem):
-			cmds = append(cmds, r.selector.SelectItemCmd)
+		switch r.state {
+		case refsStateList:
+			switch {
+			case key.Matches(msg, r.common.KeyMap.SelectItem):
+				cmds = append(cmds, r.selector.SelectItemCmd)
+			case key.Matches(msg, mergeCheck):
+				if r.refPrefix == git.RefsHeads && r.activeRef != nil && r.ref != nil && (r.ref.IsBranch() || r.ref.IsRemoteBranch()) {
+					r.isLoading = true
+					cmds = append(cmds, r.spinner.Tick, r.mergeCheckCmd(r.ref, r.activeRef))
+				}
+			case key.Matches(msg, tagDetails):
+				if r.refPrefix == git.RefsTags && r.activeRef != nil {
+					r.isLoading = true
+					cmds = append(cmds, r.spinner.Tick, r.tagCmd(r.activeRef))
+				}
+			case key.Matches(msg, sortRefs):
+				if r.refPrefix == git.RefsHeads {
+					if r.sortBy == refsSortDate {
+						r.sortBy = refsSortName
+					} else {
+						r.sortBy = refsSortDate
+					}
+					cmds = append(cmds, r.updateList())
+				}
+			case key.Matches(msg, groupRefs):
+				if r.refPrefix == git.RefsHeads {
+					r.grouped = !r.grouped
+					cmds = append(cmds, r.updateList())
+				}
+			case key.Matches(msg, pinRef):
+				if r.activeRef != nil {
+					cmds = append(cmds, r.togglePin(r.activeRef))
+				}
+			}
+		case refsStateMerge:
+			m := r.merge
+			switch {
+			case key.Matches(msg, r.common.KeyMap.BackItem):
+				r.goBack()
+			case key.Matches(msg, r.common.KeyMap.Up):
+				if m.cursor > 0 {
+					m.cursor--
+				}
+			case key.Matches(msg, r.common.KeyMap.Down):
+				if m.cursor < len(m.result.Conflicts)-1 {
+					m.cursor++
+				}
+			case key.Matches(msg, r.common.KeyMap.SelectItem),
+				key.Matches(msg, r.common.KeyMap.Select):
+				if !m.result.Clean() {
+					r.isLoading = true
+					cmds = append(cmds, r.spinner.Tick, r.conflictCmd(m.result, m.result.Conflicts[m.cursor]))
+				}
+			}
+			// Don't move the branch selection while in the merge view.
+			return r, tea.Batch(cmds...)
+		case refsStateConflict, refsStateTag:
+			switch {
+			case key.Matches(msg, r.common.KeyMap.BackItem):
+				r.goBack()
+				return r, tea.Batch(cmds...)
+			}
+			c, cmd := r.code.Update(msg)
+			r.code = c.(*code.Code)
+			if cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+			return r, tea.Batch(cmds...)
 		}
+	case RefMergeMsg:
+		if r.refPrefix == msg.prefix {
+			r.isLoading = false
+			r.state = refsStateMerge
+			r.merge = &refMerge{
+				base:   msg.base,
+				head:   msg.head,
+				result: msg.result,
+			}
+		}
+	case RefConflictMsg:
+		if r.refPrefix == msg.prefix {
+			r.isLoading = false
+			r.state = refsStateConflict
+			r.conflict = msg.path
+			cmds = append(cmds, r.code.SetContent(msg.content, msg.path))
+			r.code.GotoTop()
+		}
+	case RefTagMsg:
+		if r.refPrefix == msg.prefix {
+			r.isLoading = false
+			r.state = refsStateTag
+			r.tag = msg.name
+			cmds = append(cmds, r.code.SetContent(msg.content, ""))
+			r.code.GotoTop()
+		}
+	case GoBackMsg:
+		r.goBack()
 	case EmptyRepoMsg:
 		r.ref = nil
-		cmds = append(cmds, r.setItems([]selector.IdentifiableItem{}))
+		cmds = append(cmds, r.setItems(RefItems{}))
 	case spinner.TickMsg:
 		if r.isLoading && r.spinner.ID() == msg.ID {
 			s, cmd := r.spinner.Update(msg)
@@ -182,9 +460,20 @@ func (r *Refs) View() string {
 	if r.isLoading {
 		return renderLoading(r.common, r.spinner)
 	}
+	switch r.state {
+	case refsStateMerge:
+		return r.renderMerge()
+	case refsStateConflict, refsStateTag:
+		return r.code.View()
+	}
 	return r.selector.View()
 }
 
+// ShowsRelativeTimes implements common.RelativeTimes.
+func (r *Refs) ShowsRelativeTimes() bool {
+	return !r.isLoading && r.state == refsStateList
+}
+
 // SpinnerID implements common.TabComponent.
 func (r *Refs) SpinnerID() int {
 	return r.spinner.ID()
@@ -192,6 +481,17 @@ func (r *Refs) SpinnerID() int {
 
 // StatusBarValue implements statusbar.StatusBar.
 func (r *Refs) StatusBarValue() string {
+	switch r.state {
+	case refsStateMerge:
+		return fmt.Sprintf("%s → %s", r.merge.head, r.merge.base)
+	case refsStateConflict:
+		return r.conflict
+	case refsStateTag:
+		return r.tag
+	}
+	if r.activeGroup != "" {
+		return r.activeGroup
+	}
 	if r.activeRef == nil {
 		return ""
 	}
@@ -200,6 +500,15 @@ func (r *Refs) StatusBarValue() string {
 
 // StatusBarInfo implements statusbar.StatusBar.
 func (r *Refs) StatusBarInfo() string {
+	switch r.state {
+	case refsStateMerge:
+		if n := len(r.merge.result.Conflicts); n > 0 {
+			return fmt.Sprintf("%d conflicts", n)
+		}
+		return "clean"
+	case refsStateConflict, refsStateTag:
+		return fmt.Sprintf("☰ %d%%", r.code.ScrollPosition())
+	}
 	totalPages := r.selector.TotalPages()
 	if totalPages <= 1 {
 		return "p. 1/1"
@@ -213,40 +522,320 @@ func (r *Refs) updateItemsCmd() tea.Msg {
 	if err != nil {
 		return common.ErrorMsg(err)
 	}
-	refs, err := rr.References()
+	refs, err := referencesInfo(r.repo, rr, r.refPrefix)
 	if err != nil {
 		r.common.Logger.Debugf("ui: error getting references: %v", err)
 		return common.ErrorMsg(err)
 	}
+	// Orphan branches have no history in common with the default branch.
+	var head string
+	if r.refPrefix == git.RefsHeads {
+		if h, err := rr.HEAD(); err == nil {
+			head = h.ID
+		}
+	}
 	for _, ref := range refs {
-		if strings.HasPrefix(ref.Name().String(), r.refPrefix) {
-			refItem := RefItem{
-				Reference: ref,
+		its = append(its, RefItem{
+			Reference:  ref.Reference,
+			Commit:     ref.Commit,
+			TagMessage: ref.TagMessage,
+		})
+	}
+	return RefItemsMsg{
+		items:  its,
+		head:   head,
+		prefix: r.refPrefix,
+	}
+}
+
+// referencesInfo returns the references of the repository with the given
+// prefixes. The branches of mirrors include the remote-tracking branches of
+// the mirrored repository, see git.MirrorReferences.
+func referencesInfo(repo proto.Repository, rr *git.Repository, prefixes ...string) ([]*git.ReferenceInfo, error) {
+	if !repo.IsMirror() || !slices.Contains(prefixes, git.RefsHeads) {
+		return rr.ReferencesInfo(prefixes...)
+	}
+	refs, err := rr.ReferencesInfo(append(slices.Clone(prefixes), git.RefsRemotes)...)
+	if err != nil {
+		return nil, err
+	}
+	return git.MirrorReferences(refs), nil
+}
+
+// unrelatedCmd returns a command that checks which of the given branches
+// share no history with the default branch. Branches are only checked once.
+func (r *Refs) unrelatedCmd(items []selector.IdentifiableItem) tea.Cmd {
+	if r.head == "" || r.repo == nil {
+		return nil
+	}
+	commits := make(map[string]string)
+	for _, it := range items {
+		ref, ok := it.(RefItem)
+		if !ok || ref.Commit == nil || r.checked[ref.ID()] {
+			continue
+		}
+		r.checked[ref.ID()] = true
+		if id := ref.Commit.ID.String(); id != r.head {
+			commits[ref.ID()] = id
+		}
+	}
+	if len(commits) == 0 {
+		return nil
+	}
+	repo, head, prefix := r.repo, r.head, r.refPrefix
+	return func() tea.Msg {
+		rr, err := repo.Open()
+		if err != nil {
+			return common.ErrorMsg(err)
+		}
+		unrelated := make(map[string]bool)
+		for id, commit := range commits {
+			if ok, _ := rr.Unrelated(head, commit); ok {
+				unrelated[id] = true
 			}
+		}
+		return RefUnrelatedMsg{
+			prefix:    prefix,
+			unrelated: unrelated,
+		}
+	}
+}
 
-			if ref.IsTag() {
-				refItem.Tag, _ = rr.Tag(ref.Name().Short())
-				if refItem.Tag != nil {
-					refItem.Commit, _ = refItem.Tag.Commit()
-				}
-			} else {
-				refItem.Commit, _ = rr.CatFileCommit(ref.ID)
+// updateList sets the items of the list from the loaded references, keeping
+// the cursor on the selected reference. When the selected branch is folded,
+// the cursor moves to the header of its group.
+func (r *Refs) updateList() tea.Cmd {
+	var id string
+	if sel := r.selector.SelectedItem(); sel != nil {
+		id = sel.ID()
+	}
+
+	grouped := r.grouped && r.refPrefix == git.RefsHeads
+	items := refListItems(r.refs, r.sortBy, grouped, r.isCollapsed)
+	cmd := r.selector.SetItems(items)
+	if id != "" {
+		group := RefGroupItem{Prefix: refGroupPrefix(git.ReferenceName(id).Short())}.ID()
+		for i, it := range items {
+			if it.ID() == id {
+				r.selector.Select(i)
+				break
+			}
+			if it.ID() == group {
+				r.selector.Select(i)
 			}
-			its = append(its, refItem)
 		}
 	}
-	sort.Sort(its)
-	items := make([]selector.IdentifiableItem, len(its))
-	for i, it := range its {
-		items[i] = it
+	r.setActive(r.selector.SelectedItem())
+	return tea.Batch(cmd, r.unrelatedCmd(r.selector.WindowItems()))
+}
+
+// setActive updates the active reference or group from the selected item.
+func (r *Refs) setActive(item selector.IdentifiableItem) {
+	switch sel := item.(type) {
+	case RefItem:
+		r.activeRef = sel.Reference
+		r.activeGroup = ""
+	case RefGroupItem:
+		r.activeRef = nil
+		r.activeGroup = sel.Prefix
 	}
-	return RefItemsMsg{
-		items:  items,
-		prefix: r.refPrefix,
+}
+
+// isCollapsed returns true if the group of branches with the given prefix is
+// folded. Groups are folded by default, except the group of the current
+// branch so that it stays visible.
+func (r *Refs) isCollapsed(prefix string) bool {
+	if folded, ok := r.folds[prefix]; ok {
+		return folded
+	}
+	if r.ref != nil && (r.ref.IsBranch() || r.ref.IsRemoteBranch()) {
+		return refGroupPrefix(r.ref.Name().Short()) != prefix
+	}
+	return true
+}
+
+// sortKey returns the key binding that changes the order of the branches.
+func (r *Refs) sortKey() key.Binding {
+	k := sortRefs
+	if r.sortBy == refsSortName {
+		k.SetHelp("s", "sort by date")
+	}
+	return k
+}
+
+// groupKey returns the key binding that groups the branches.
+func (r *Refs) groupKey() key.Binding {
+	k := groupRefs
+	if r.grouped {
+		k.SetHelp("p", "ungroup branches")
+	}
+	return k
+}
+
+// pinKey returns the key that pins the selected ref, or unpins it if it's the
+// pinned one.
+func (r *Refs) pinKey() key.Binding {
+	k := pinRef
+	if r.activeRef != nil && r.activeRef.Name().String() == r.pinned {
+		k.SetHelp("P", "unpin ref")
+	}
+	return k
+}
+
+// togglePin pins the repository to the given ref, so that it opens on it, or
+// unpins it if it's already pinned to it, and saves the choice of the user.
+func (r *Refs) togglePin(ref *git.Reference) tea.Cmd {
+	be, pk := r.common.Backend(), r.common.PublicKey()
+	if be == nil || pk == nil || r.repo == nil {
+		return nil
+	}
+	ctx := r.common.Context()
+	name := ref.Name().String()
+	pref := common.PinnedRefPreferenceName(r.repo.Name())
+	if r.pinned == name {
+		if err := be.DeletePreference(ctx, pk, pref); err != nil {
+			return statusCmd(fmt.Sprintf("Failed to unpin %s: %v", ref.Name().Short(), err))
+		}
+		r.pinned = ""
+		return statusCmd(fmt.Sprintf("Unpinned %s, %s opens on HEAD", ref.Name().Short(), r.repo.Name()))
+	}
+	if err := be.SetPreference(ctx, pk, pref, name); err != nil {
+		return statusCmd(fmt.Sprintf("Failed to pin %s: %v", ref.Name().Short(), err))
 	}
+	r.pinned = name
+	return statusCmd(fmt.Sprintf("Pinned %s, %s opens on it", ref.Name().Short(), r.repo.Name()))
 }
 
-func (r *Refs) setItems(items []selector.IdentifiableItem) tea.Cmd {
+func (r *Refs) goBack() {
+	switch r.state {
+	case refsStateConflict:
+		r.state = refsStateMerge
+	case refsStateTag:
+		r.state = refsStateList
+	case refsStateMerge:
+		r.state = refsStateList
+		r.merge = nil
+	}
+}
+
+// mergeCheckCmd runs a trial merge of head into base.
+func (r *Refs) mergeCheckCmd(base, head *git.Reference) tea.Cmd {
+	repo := r.repo
+	prefix := r.refPrefix
+	return func() tea.Msg {
+		rr, err := repo.Open()
+		if err != nil {
+			return common.ErrorMsg(err)
+		}
+		res, err := rr.TrialMerge(base.Name().String(), head.Name().String())
+		if err != nil {
+			r.common.Logger.Debugf("ui: error checking merge: %v", err)
+			return common.ErrorMsg(err)
+		}
+		return RefMergeMsg{
+			prefix: prefix,
+			base:   base.Name().Short(),
+			head:   head.Name().Short(),
+			result: res,
+		}
+	}
+}
+
+// conflictCmd loads the three-way merge of a conflicting path.
+func (r *Refs) conflictCmd(res *git.MergeResult, path string) tea.Cmd {
+	repo := r.repo
+	prefix := r.refPrefix
+	return func() tea.Msg {
+		rr, err := repo.Open()
+		if err != nil {
+			return common.ErrorMsg(err)
+		}
+		bts, err := rr.ConflictContent(res, path)
+		if err != nil {
+			r.common.Logger.Debugf("ui: error loading conflict: %v", err)
+			return common.ErrorMsg(err)
+		}
+		return RefConflictMsg{
+			prefix:  prefix,
+			path:    path,
+			content: string(bts),
+		}
+	}
+}
+
+// tagCmd loads the tag and the chain of tags it points to.
+func (r *Refs) tagCmd(ref *git.Reference) tea.Cmd {
+	repo := r.repo
+	prefix := r.refPrefix
+	return func() tea.Msg {
+		rr, err := repo.Open()
+		if err != nil {
+			return common.ErrorMsg(err)
+		}
+		chain, err := rr.TagChain(ref.ID)
+		if err != nil {
+			r.common.Logger.Debugf("ui: error loading tag chain: %v", err)
+			return common.ErrorMsg(err)
+		}
+
+		var s strings.Builder
+		if len(chain) == 0 {
+			fmt.Fprintf(&s, "Lightweight tag %s\n\n", ref.Name().Short())
+			fmt.Fprintf(&s, "object %s\n", ref.ID)
+		}
+		for i, t := range chain {
+			if i > 0 {
+				s.WriteString("\n")
+			}
+			fmt.Fprintf(&s, "tag %s\n", t.Name)
+			fmt.Fprintf(&s, "object %s (%s)\n", t.Object, t.ObjectType)
+			if t.Tagger != nil {
+				fmt.Fprintf(&s, "Tagger: %s <%s>\n", t.Tagger.Name, t.Tagger.Email)
+				fmt.Fprintf(&s, "Date:   %s\n", t.Tagger.When.Format(time.RFC1123Z))
+			}
+			if msg := strings.TrimSpace(t.Message); msg != "" {
+				s.WriteString("\n" + msg + "\n")
+			}
+		}
+
+		return RefTagMsg{
+			prefix:  prefix,
+			name:    ref.Name().Short(),
+			content: s.String(),
+		}
+	}
+}
+
+func (r *Refs) renderMerge() string {
+	m := r.merge
+	s := strings.Builder{}
+	if m.result.Clean() {
+		s.WriteString(r.common.Styles.CommitStatus.Success.Render(
+			fmt.Sprintf("✓ %s merges cleanly into %s", m.head, m.base)))
+	} else {
+		files := "files"
+		if len(m.result.Conflicts) == 1 {
+			files = "file"
+		}
+		s.WriteString(r.common.Styles.CommitStatus.Failure.Render(
+			fmt.Sprintf("✗ %s conflicts with %s in %d %s", m.head, m.base, len(m.result.Conflicts), files)))
+	}
+	s.WriteString("\n\n")
+	for i, p := range m.result.Conflicts {
+		st := r.common.Styles.LogItem.Normal
+		if i == m.cursor {
+			st = r.common.Styles.LogItem.Active
+		}
+		p = common.TruncateString(common.UnquoteFilename(p), r.common.Width-st.Base.GetHorizontalFrameSize())
+		s.WriteString(st.Base.Render(st.Title.Render(p)))
+		s.WriteString("\n")
+	}
+	return r.common.Renderer.NewStyle().
+		Height(r.common.Height).
+		Render(s.String())
+}
+
+func (r *Refs) setItems(items RefItems) tea.Cmd {
 	return func() tea.Msg {
 		return RefItemsMsg{
 			items:  items,
@@ -279,3 +868,67 @@ func UpdateRefCmd(repo proto.Repository) tea.Cmd {
 		return RefMsg(ref)
 	}
 }
+
+// UpdateRefNameCmd gets the repository's reference with the given full name
+// and sends a RefMsg. The hash of a commit is browsed as a detached reference.
+// It falls back to HEAD if the reference doesn't exist.
+func UpdateRefNameCmd(repo proto.Repository, name string) tea.Cmd {
+	return func() tea.Msg {
+		r, err := repo.Open()
+		if err != nil {
+			return common.ErrorMsg(err)
+		}
+		refs, _ := r.ReferencesInfo(name)
+		for _, ref := range refs {
+			if ref.Name().String() == name {
+				return RefMsg(ref.Reference)
+			}
+		}
+		if !strings.HasPrefix(name, "-") {
+			if id, err := r.RevParse(name + "^{commit}"); err == nil && id == name {
+				return RefMsg(r.CommitReference(id))
+			}
+		}
+		return UpdateRefCmd(repo)()
+	}
+}
+
+// PinnedRefCmd gets the repository's reference with the given full name, the
+// one the user pinned it to, and sends a RefMsg. It falls back to HEAD with a
+// notice if the reference doesn't exist anymore.
+func PinnedRefCmd(repo proto.Repository, name string) tea.Cmd {
+	return func() tea.Msg {
+		r, err := repo.Open()
+		if err != nil {
+			return common.ErrorMsg(err)
+		}
+		refs, _ := r.ReferencesInfo(name)
+		for _, ref := range refs {
+			if ref.Name().String() == name {
+				return RefMsg(ref.Reference)
+			}
+		}
+		notice := fmt.Sprintf("pinned ref %s no longer exists, showing HEAD", git.ReferenceName(name).Short())
+		return tea.Sequence(UpdateRefCmd(repo), statusCmd(notice))()
+	}
+}
+
+// RestoreRefCmd gets the repository's reference with the given full name, the
+// last one browsed, and sends a RefMsg. It falls back to HEAD with a notice if
+// the reference doesn't exist anymore.
+func RestoreRefCmd(repo proto.Repository, name string) tea.Cmd {
+	return func() tea.Msg {
+		r, err := repo.Open()
+		if err != nil {
+			return common.ErrorMsg(err)
+		}
+		refs, _ := r.ReferencesInfo(name)
+		for _, ref := range refs {
+			if ref.Name().String() == name {
+				return RefMsg(ref.Reference)
+			}
+		}
+		notice := fmt.Sprintf("%s no longer exists, showing HEAD", git.ReferenceName(name).Short())
+		return tea.Sequence(UpdateRefCmd(repo), statusCmd(notice))()
+	}
+}
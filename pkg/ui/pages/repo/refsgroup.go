@@ -0,0 +1,124 @@
+package repo
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/ui/components/selector"
+)
+
+// refsSort is the order of the reference list.
+type refsSort int
+
+const (
+	// refsSortDate sorts references by the date of their last commit, most
+	// recent first.
+	refsSortDate refsSort = iota
+	// refsSortName sorts references by name.
+	refsSortName
+)
+
+// RefGroupItem is the header of the branches that share a prefix, e.g.
+// "feature/".
+type RefGroupItem struct {
+	// Prefix is the prefix of the branches including the trailing slash.
+	Prefix string
+	// Count is the number of branches in the group.
+	Count int
+	// Collapsed is true when the branches of the group are hidden.
+	Collapsed bool
+}
+
+// ID implements selector.IdentifiableItem.
+func (i RefGroupItem) ID() string {
+	return "group:" + i.Prefix
+}
+
+// Title implements list.DefaultItem.
+func (i RefGroupItem) Title() string {
+	return i.Prefix
+}
+
+// Description implements list.DefaultItem.
+func (i RefGroupItem) Description() string {
+	return ""
+}
+
+// FilterValue implements list.Item.
+func (i RefGroupItem) FilterValue() string { return i.Prefix }
+
+// refGroupPrefix returns the prefix a branch is grouped under, i.e. its name
+// up to and including the first slash. Branches without a slash aren't
+// grouped.
+func refGroupPrefix(name string) string {
+	if i := strings.Index(name, "/"); i > 0 {
+		return name[:i+1]
+	}
+	return ""
+}
+
+// sortRefItems sorts the references in the given order.
+func sortRefItems(refs RefItems, by refsSort) RefItems {
+	sorted := make(RefItems, len(refs))
+	copy(sorted, refs)
+	switch by {
+	case refsSortName:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Short() < sorted[j].Short()
+		})
+	default:
+		sort.Stable(sorted)
+	}
+	return sorted
+}
+
+// refListItems returns the items of the reference list. Grouped branches are
+// folded under a header per prefix, placed where the first branch of the group
+// would be, so groups follow the sort order too. Prefixes with a single branch
+// aren't grouped.
+func refListItems(refs RefItems, by refsSort, grouped bool, collapsed func(prefix string) bool) []selector.IdentifiableItem {
+	sorted := sortRefItems(refs, by)
+	items := make([]selector.IdentifiableItem, 0, len(sorted))
+	if !grouped {
+		for _, ref := range sorted {
+			items = append(items, ref)
+		}
+		return items
+	}
+
+	groups := map[string]RefItems{}
+	for _, ref := range sorted {
+		if p := refGroupPrefix(ref.Short()); p != "" {
+			groups[p] = append(groups[p], ref)
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, ref := range sorted {
+		p := refGroupPrefix(ref.Short())
+		members := groups[p]
+		if len(members) < 2 {
+			items = append(items, ref)
+			continue
+		}
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+
+		folded := collapsed(p)
+		items = append(items, RefGroupItem{
+			Prefix:    p,
+			Count:     len(members),
+			Collapsed: folded,
+		})
+		if folded {
+			continue
+		}
+		for _, m := range members {
+			m.group = p
+			items = append(items, m)
+		}
+	}
+	return items
+}
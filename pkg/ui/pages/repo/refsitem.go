@@ -19,8 +19,17 @@ import (
 // RefItem is a git reference item.
 type RefItem struct {
 	*git.Reference
-	*git.Tag
 	*git.Commit
+
+	// TagMessage is the message of annotated tags.
+	TagMessage string
+
+	// Unrelated is true for branches that share no history with the default
+	// branch, e.g. orphan branches.
+	Unrelated bool
+
+	// group is the prefix of the group the branch is shown under, if any.
+	group string
 }
 
 // ID implements selector.IdentifiableItem.
@@ -94,6 +103,11 @@ func (d RefItemDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd {
 
 // Render implements list.ItemDelegate.
 func (d RefItemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	if g, ok := listItem.(RefGroupItem); ok {
+		d.renderGroup(w, m, index, g)
+		return
+	}
+
 	i, ok := listItem.(RefItem)
 	if !ok {
 		return
@@ -128,6 +142,9 @@ func (d RefItemDelegate) Render(w io.Writer, m list.Model, index int, listItem l
 	}
 
 	ref := i.Short()
+	if i.group != "" {
+		ref = "  " + strings.TrimPrefix(ref, i.group)
+	}
 
 	var desc string
 	if isTag {
@@ -139,10 +156,9 @@ func (d RefItemDelegate) Render(w io.Writer, m list.Model, index int, listItem l
 			desc += " " + st.ItemDesc.Render(date)
 		}
 
-		t := i.Tag
-		if t != nil {
+		if i.TagMessage != "" {
 			msgSt := st.ItemDesc.Faint(false)
-			msg := t.Message()
+			msg := i.TagMessage
 			nl := strings.Index(msg, "\n")
 			if nl > 0 {
 				msg = msg[:nl]
@@ -163,6 +179,9 @@ func (d RefItemDelegate) Render(w io.Writer, m list.Model, index int, listItem l
 			}
 		}
 	} else if c != nil {
+		if i.Unrelated {
+			desc += " " + s.ItemUnrelated.Render("unrelated")
+		}
 		onMargin := m.Width() -
 			horizontalFrameSize -
 			lipgloss.Width(selector) -
@@ -203,3 +222,37 @@ func (d RefItemDelegate) Render(w io.Writer, m list.Model, index int, listItem l
 		),
 	)
 }
+
+// renderGroup renders the header of a group of branches.
+func (d RefItemDelegate) renderGroup(w io.Writer, m list.Model, index int, g RefGroupItem) {
+	s := d.common.Styles.Ref
+	st := s.Normal
+	selector := "  "
+	if index == m.Index() {
+		st = s.Active
+		selector = s.ItemSelector.String()
+	}
+
+	fold := "▾ "
+	if g.Collapsed {
+		fold = "▸ "
+	}
+	branches := "branches"
+	if g.Count == 1 {
+		branches = "branch"
+	}
+
+	horizontalFrameSize := st.Base.GetHorizontalFrameSize()
+	fmt.Fprint(w,
+		d.common.Zone.Mark(
+			g.ID(),
+			st.Base.Render(
+				truncate.String(
+					selector+st.ItemGroup.Render(fold+g.Prefix)+" "+
+						st.ItemDesc.Render(fmt.Sprintf("%d %s", g.Count, branches)),
+					uint(m.Width()-horizontalFrameSize),
+				),
+			),
+		),
+	)
+}
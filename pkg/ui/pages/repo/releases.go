@@ -0,0 +1,402 @@
+package repo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/ui/components/code"
+	"github.com/charmbracelet/soft-serve/pkg/ui/components/selector"
+)
+
+var releaseLog = key.NewBinding(
+	key.WithKeys("L"),
+	key.WithHelp("L", "commits in log"),
+)
+
+// maxReleaseCommits is the maximum number of commits listed in the notes of
+// a release.
+const maxReleaseCommits = 100
+
+type releasesState int
+
+const (
+	releasesStateLoading releasesState = iota
+	releasesStateList
+	releasesStateNotes
+)
+
+// ReleasesMsg is a message that contains the releases of a repository, the
+// latest first.
+type ReleasesMsg []*git.Release
+
+// ReleaseNotesMsg is a message that contains the rendered notes of a release
+// and the commits since the previous one.
+type ReleaseNotesMsg struct {
+	tag     string
+	content string
+}
+
+// Releases is a component that lists the tags of a repository as releases,
+// with their notes and the commits since the previous release.
+type Releases struct {
+	common  common.Common
+	code    *code.Code
+	repo    proto.Repository
+	spinner spinner.Model
+	list    *selector.Selector
+	state   releasesState
+	release *git.Release
+}
+
+// NewReleases creates a new releases model.
+func NewReleases(common common.Common) *Releases {
+	c := code.New(common, "", "")
+	c.UseGlamour = true
+	s := spinner.New(spinner.WithSpinner(spinner.Dot),
+		spinner.WithStyle(common.Styles.Spinner))
+	list := selector.New(common, []selector.IdentifiableItem{}, ReleaseItemDelegate{&common})
+	list.SetShowFilter(false)
+	list.SetShowHelp(false)
+	list.SetShowPagination(false)
+	list.SetShowStatusBar(false)
+	list.SetShowTitle(false)
+	list.SetFilteringEnabled(false)
+	list.DisableQuitKeybindings()
+	list.KeyMap.NextPage = common.KeyMap.NextPage
+	list.KeyMap.PrevPage = common.KeyMap.PrevPage
+	return &Releases{
+		common:  common,
+		code:    c,
+		spinner: s,
+		list:    list,
+	}
+}
+
+// Path implements common.TabComponent.
+func (r *Releases) Path() string {
+	return ""
+}
+
+// TabName returns the name of the tab.
+func (r *Releases) TabName() string {
+	return "Releases"
+}
+
+// Hidden implements hiddenTab. The tab is only shown for repositories with
+// tags.
+func (r *Releases) Hidden(repo proto.Repository) bool {
+	rr, err := repo.Open()
+	if err != nil {
+		return true
+	}
+	ts, _ := rr.Tags()
+	return len(ts) == 0
+}
+
+// SetSize implements common.Component.
+func (r *Releases) SetSize(width, height int) {
+	r.common.SetSize(width, height)
+	r.code.SetSize(width, height)
+	r.list.SetSize(width, height)
+}
+
+// ShortHelp implements help.KeyMap.
+func (r *Releases) ShortHelp() []key.Binding {
+	if r.state == releasesStateNotes {
+		return []key.Binding{
+			r.common.KeyMap.UpDown,
+			r.common.KeyMap.BackItem,
+			releaseLog,
+		}
+	}
+	return []key.Binding{
+		r.common.KeyMap.SelectItem,
+		r.common.KeyMap.UpDown,
+		releaseLog,
+	}
+}
+
+// FullHelp implements help.KeyMap.
+func (r *Releases) FullHelp() [][]key.Binding {
+	if r.state == releasesStateNotes {
+		k := r.code.KeyMap
+		return [][]key.Binding{
+			{
+				r.common.KeyMap.BackItem,
+				releaseLog,
+			},
+			{
+				k.PageDown,
+				k.PageUp,
+				k.HalfPageDown,
+				k.HalfPageUp,
+			},
+			{
+				k.Down,
+				k.Up,
+				r.common.KeyMap.GotoTop,
+				r.common.KeyMap.GotoBottom,
+			},
+		}
+	}
+	k := r.list.KeyMap
+	return [][]key.Binding{
+		{
+			r.common.KeyMap.SelectItem,
+			releaseLog,
+		},
+		{
+			k.CursorUp,
+			k.CursorDown,
+			k.NextPage,
+			k.PrevPage,
+		},
+		{
+			k.GoToStart,
+			k.GoToEnd,
+		},
+	}
+}
+
+// StatusBarValue implements statusbar.StatusBar.
+func (r *Releases) StatusBarValue() string {
+	if rel := r.selected(); rel != nil {
+		return rel.Name().Short()
+	}
+	return " "
+}
+
+// StatusBarInfo implements statusbar.StatusBar.
+func (r *Releases) StatusBarInfo() string {
+	switch r.state {
+	case releasesStateList:
+		totalPages := r.list.TotalPages()
+		if totalPages <= 1 {
+			return "p. 1/1"
+		}
+		return fmt.Sprintf("p. %d/%d", r.list.Page()+1, totalPages)
+	case releasesStateNotes:
+		return fmt.Sprintf("☰ %d%%", r.code.ScrollPosition())
+	}
+	return ""
+}
+
+// SpinnerID implements common.TabComponent.
+func (r *Releases) SpinnerID() int {
+	return r.spinner.ID()
+}
+
+// Init implements tea.Model.
+func (r *Releases) Init() tea.Cmd {
+	r.state = releasesStateLoading
+	r.release = nil
+	return tea.Batch(r.spinner.Tick, r.releasesCmd)
+}
+
+// Update implements tea.Model.
+func (r *Releases) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	cmds := make([]tea.Cmd, 0)
+	switch msg := msg.(type) {
+	case RepoMsg:
+		r.repo = msg
+		r.list.Select(0)
+		cmds = append(cmds, r.Init())
+	case code.RenderedMsg:
+		c, cmd := r.code.Update(msg)
+		r.code = c.(*code.Code)
+		return r, cmd
+	case tea.WindowSizeMsg:
+		r.SetSize(msg.Width, msg.Height)
+	case spinner.TickMsg:
+		if r.state == releasesStateLoading && r.spinner.ID() == msg.ID {
+			sp, cmd := r.spinner.Update(msg)
+			r.spinner = sp
+			if cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+	case tea.KeyMsg:
+		switch r.state {
+		case releasesStateList:
+			switch {
+			case key.Matches(msg, r.common.KeyMap.SelectItem):
+				cmds = append(cmds, r.list.SelectItemCmd)
+			case key.Matches(msg, releaseLog):
+				cmds = append(cmds, r.logCmd(r.selected()))
+			}
+		case releasesStateNotes:
+			switch {
+			case key.Matches(msg, r.common.KeyMap.BackItem):
+				cmds = append(cmds, goBackCmd)
+			case key.Matches(msg, releaseLog):
+				cmds = append(cmds, r.logCmd(r.release))
+			}
+		}
+	case ReleasesMsg:
+		r.state = releasesStateList
+		items := make([]selector.IdentifiableItem, len(msg))
+		for i, rel := range msg {
+			items[i] = ReleaseItem{rel}
+		}
+		cmds = append(cmds, r.list.SetItems(items))
+	case selector.SelectMsg:
+		if item, ok := msg.IdentifiableItem.(ReleaseItem); ok {
+			r.release = item.Release
+			r.state = releasesStateLoading
+			cmds = append(cmds, r.spinner.Tick, r.notesCmd(item.Release))
+		}
+	case ReleaseNotesMsg:
+		if r.release != nil && r.release.Name().Short() == msg.tag {
+			r.state = releasesStateNotes
+			cmds = append(cmds, r.code.SetContent(msg.content, ".md"))
+			r.code.GotoTop()
+		}
+	case GoBackMsg:
+		if r.state == releasesStateNotes {
+			r.state = releasesStateList
+			r.release = nil
+		}
+	case EmptyRepoMsg:
+		r.state = releasesStateList
+		cmds = append(cmds, r.list.SetItems([]selector.IdentifiableItem{}))
+	}
+	switch r.state {
+	case releasesStateList:
+		l, cmd := r.list.Update(msg)
+		r.list = l.(*selector.Selector)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	case releasesStateNotes:
+		c, cmd := r.code.Update(msg)
+		r.code = c.(*code.Code)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	return r, tea.Batch(cmds...)
+}
+
+// View implements tea.Model.
+func (r *Releases) View() string {
+	switch r.state {
+	case releasesStateLoading:
+		return renderLoading(r.common, r.spinner)
+	case releasesStateNotes:
+		return r.code.View()
+	}
+	return r.list.View()
+}
+
+// selected returns the release shown in the notes view, or the selected one
+// in the list.
+func (r *Releases) selected() *git.Release {
+	if r.release != nil {
+		return r.release
+	}
+	if item, ok := r.list.SelectedItem().(ReleaseItem); ok {
+		return item.Release
+	}
+	return nil
+}
+
+func (r *Releases) releasesCmd() tea.Msg {
+	if r.repo == nil {
+		return ReleasesMsg(nil)
+	}
+	rr, err := r.repo.Open()
+	if err != nil {
+		return common.ErrorMsg(err)
+	}
+	rels, err := rr.Releases()
+	if err != nil {
+		r.common.Logger.Debugf("ui: error loading releases: %v", err)
+		return common.ErrorMsg(err)
+	}
+	return ReleasesMsg(rels)
+}
+
+// notesCmd renders the notes of the release and the commits since the
+// previous one as markdown.
+func (r *Releases) notesCmd(rel *git.Release) tea.Cmd {
+	repo := r.repo
+	return func() tea.Msg {
+		rr, err := repo.Open()
+		if err != nil {
+			return common.ErrorMsg(err)
+		}
+		ids, err := rr.ReleaseCommits(rel)
+		if err != nil {
+			r.common.Logger.Debugf("ui: error loading release commits: %v", err)
+			return common.ErrorMsg(err)
+		}
+
+		var s strings.Builder
+		fmt.Fprintf(&s, "# %s\n\n", rel.Name().Short())
+		commits := fmt.Sprintf("%d commits", len(ids))
+		if len(ids) == 1 {
+			commits = "1 commit"
+		}
+		if rel.Previous != nil {
+			commits += " since " + rel.Previous.Name().Short()
+		}
+		date := rel.Date.Format("Jan 02 2006")
+		switch {
+		case rel.TagMessage == "":
+			fmt.Fprintf(&s, "Lightweight tag of a commit made on %s · %s\n\n", date, commits)
+		case rel.Tagger != nil:
+			fmt.Fprintf(&s, "Tagged by %s on %s · %s\n\n", rel.Tagger.Name, date, commits)
+		default:
+			fmt.Fprintf(&s, "Tagged on %s · %s\n\n", date, commits)
+		}
+		if notes := strings.TrimSpace(rel.TagMessage); notes != "" {
+			s.WriteString(notes + "\n\n")
+		}
+
+		if len(ids) > 0 {
+			s.WriteString("## Commits\n\n")
+		}
+		for i, id := range ids {
+			if i == maxReleaseCommits {
+				fmt.Fprintf(&s, "\n…and %d more\n", len(ids)-i)
+				break
+			}
+			c, err := rr.CatFileCommit(id)
+			if err != nil {
+				r.common.Logger.Debugf("ui: error loading release commit: %v", err)
+				return common.ErrorMsg(err)
+			}
+			fmt.Fprintf(&s, "- `%s` %s\n", id[:7], strings.TrimSpace(c.Summary()))
+		}
+
+		return ReleaseNotesMsg{
+			tag:     rel.Name().Short(),
+			content: s.String(),
+		}
+	}
+}
+
+// logCmd switches to the tag of the release and shows the commits since the
+// previous release in the commits tab.
+func (r *Releases) logCmd(rel *git.Release) tea.Cmd {
+	if rel == nil {
+		return nil
+	}
+	var query string
+	if rel.Previous != nil {
+		query = "since:" + rel.Previous.Commit.ID.String()[:7]
+	}
+	return tea.Sequence(
+		switchRefCmd(rel.Reference),
+		func() tea.Msg {
+			return LogQueryMsg(query)
+		},
+		switchTabCmd(&Log{}),
+	)
+}
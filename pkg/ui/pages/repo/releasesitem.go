@@ -0,0 +1,112 @@
+package repo
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/muesli/reflow/truncate"
+)
+
+// ReleaseItem is a release in the list of releases.
+type ReleaseItem struct{ *git.Release }
+
+// ID implements selector.IdentifiableItem.
+func (i ReleaseItem) ID() string {
+	return i.Name().String()
+}
+
+// Title returns the name of the tag of the release.
+func (i ReleaseItem) Title() string {
+	return i.Name().Short()
+}
+
+// Description returns the first line of the release notes.
+func (i ReleaseItem) Description() string {
+	line, _, _ := strings.Cut(strings.TrimSpace(i.TagMessage), "\n")
+	return strings.TrimSpace(line)
+}
+
+// FilterValue implements list.Item.
+func (i ReleaseItem) FilterValue() string { return i.Title() }
+
+// ReleaseItemDelegate is a delegate for release items.
+type ReleaseItemDelegate struct {
+	common *common.Common
+}
+
+// Height implements list.ItemDelegate.
+func (d ReleaseItemDelegate) Height() int { return 1 }
+
+// Spacing implements list.ItemDelegate.
+func (d ReleaseItemDelegate) Spacing() int { return 0 }
+
+// Update implements list.ItemDelegate.
+func (d ReleaseItemDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd {
+	item, ok := m.SelectedItem().(ReleaseItem)
+	if !ok {
+		return nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, d.common.KeyMap.Copy):
+			return copyCmd(item.Title(), fmt.Sprintf("Tag %q copied to clipboard", item.Title()))
+		}
+	}
+
+	return nil
+}
+
+// Render implements list.ItemDelegate. A release shows its tag, date, the
+// first line of its notes, and the release it follows.
+func (d ReleaseItemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(ReleaseItem)
+	if !ok {
+		return
+	}
+
+	s := d.common.Styles.Ref
+	st := s.Normal
+	selector := "  "
+	if index == m.Index() {
+		st = s.Active
+		selector = s.ItemSelector.String()
+	}
+
+	date := i.Date.Format("Jan 02")
+	if i.Date.Year() != time.Now().Year() {
+		date += fmt.Sprintf(" %d", i.Date.Year())
+	}
+	line := selector + st.ItemTag.Render(i.Title()) + " " + st.ItemDesc.Render(date)
+	if desc := i.Description(); desc != "" {
+		line += " " + st.ItemDesc.Faint(false).Render(desc)
+	}
+
+	since := "first release"
+	if i.Previous != nil {
+		since = "since " + i.Previous.Name().Short()
+	}
+	since = st.ItemHash.PaddingLeft(1).Render(since)
+
+	horizontalFrameSize := st.Base.GetHorizontalFrameSize()
+	width := m.Width() - horizontalFrameSize
+	line = truncate.StringWithTail(line, uint(max(width-lipgloss.Width(since), 0)), "…")
+	if margin := width - lipgloss.Width(line) - lipgloss.Width(since); margin >= 0 {
+		line += strings.Repeat(" ", margin) + since
+	}
+	fmt.Fprint(w,
+		d.common.Zone.Mark(
+			i.ID(),
+			st.Base.Render(truncate.String(line, uint(max(width, 0)))),
+		),
+	)
+}
@@ -7,17 +7,48 @@ import (
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/ui/components/code"
 	"github.com/charmbracelet/soft-serve/pkg/ui/components/footer"
 	"github.com/charmbracelet/soft-serve/pkg/ui/components/selector"
 	"github.com/charmbracelet/soft-serve/pkg/ui/components/statusbar"
 	"github.com/charmbracelet/soft-serve/pkg/ui/components/tabs"
 )
 
+var (
+	editDescription = key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "edit description"),
+	)
+	saveDescription = key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "save"),
+	)
+	cancelDescription = key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "cancel"),
+	)
+	copyURL = key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "copy clone command"),
+	)
+	toggleWatch = key.NewBinding(
+		key.WithKeys("W"),
+		key.WithHelp("W", "watch"),
+	)
+	refresh = key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "refresh"),
+	)
+)
+
 type state int
 
 const (
@@ -43,9 +74,67 @@ type CopyMsg struct {
 	Message string
 }
 
+// HeadStatusMsg is a message that contains the combined commit status state of
+// the latest commit of the selected reference.
+type HeadStatusMsg proto.CommitState
+
 // SwitchTabMsg is a message to switch tabs.
 type SwitchTabMsg common.TabComponent
 
+// JumpBackMsg is a message to go back to the tab that jumped to the current
+// one, e.g. from a commit to the blame it was opened from.
+type JumpBackMsg struct{}
+
+// StatusMsg is a message to show a short note in the status bar.
+type StatusMsg string
+
+// ToggleWatchMsg is a message to start or stop watching the pushes to a
+// repository.
+type ToggleWatchMsg struct {
+	Repo string
+}
+
+// DescriptionMsg is a message that contains the repository after its
+// description has been updated.
+type DescriptionMsg struct {
+	Repo proto.Repository
+}
+
+// refreshMsg is a message that contains the repository and its reference
+// loaded again, ref is the RefMsg, or the EmptyRepoMsg or error, to update
+// the tabs with.
+type refreshMsg struct {
+	repo proto.Repository
+	ref  tea.Msg
+}
+
+// refreshTab is implemented by tabs that keep their position when the
+// repository is refreshed. RestoreCmd returns the command that restores it
+// once the tab is loaded again.
+type refreshTab interface {
+	RestoreCmd() tea.Cmd
+}
+
+// hiddenTab is implemented by tabs that are hidden for some repositories.
+type hiddenTab interface {
+	Hidden(repo proto.Repository) bool
+}
+
+// pathStatusBar is a tab whose status bar value is a path. The path is
+// truncated in the middle, or shown in full above the status bar when it's
+// expanded.
+type pathStatusBar interface {
+	StatusBarPath() (path bool, expanded bool)
+}
+
+// compactWidth is the terminal width below which the repository view switches
+// to its compact layout.
+const compactWidth = 60
+
+// avatarSize is the width of the avatar in the header. It's as high as the
+// header.
+const avatarSize = 4
+
 // Repo is a view for a git repository.
 type Repo struct {
 	common       common.Common
@@ -58,20 +147,53 @@ type Repo struct {
 	state        state
 	spinner      spinner.Model
 	panesReady   []bool
+	headStatus   proto.CommitState
+	owner        string
+	mirror       *mirrorInfo
+	fork         forkInfo
+	watchers     int
+	deps         depsInfo
+	visit        visitInfo
+	clone        cloneInstructions
+	avatar       string
+	access       access.AccessLevel
+	canEdit      bool
+	editing      bool
+	descInput    textinput.Model
+
+	// jumps holds the tabs to go back to when jumping between the blame of a
+	// file and the diff of a commit.
+	jumps []int
+
+	// linkTab is the tab the next repository opens on, see SetLinkTab.
+	// linkExact is true when it's opened even if it has nothing to show.
+	linkTab   string
+	linkExact bool
 }
 
 // New returns a new Repo.
 func New(c common.Common, comps ...common.TabComponent) *Repo {
 	sb := statusbar.New(c)
+	cfg := c.Config()
 	ts := make([]string, 0)
-	for _, c := range comps {
-		ts = append(ts, c.TabName())
+	for _, comp := range comps {
+		name := comp.TabName()
+		if cfg != nil {
+			// The tabs are named after their default names, e.g. "commits".
+			name = cfg.UI.TabLabel(strings.ToLower(name), name)
+		}
+		ts = append(ts, name)
 	}
 	c.Logger = c.Logger.WithPrefix("ui.repo")
 	tb := tabs.New(c, ts)
-	// Make sure the order matches the order of tab constants above.
 	s := spinner.New(spinner.WithSpinner(spinner.Dot),
 		spinner.WithStyle(c.Styles.Spinner))
+	ti := textinput.New()
+	ti.Prompt = ""
+	ti.Placeholder = "Description"
+	ti.CharLimit = 255
+	ti.TextStyle = c.Styles.Repo.HeaderDesc
+	ti.PlaceholderStyle = c.Styles.Repo.HeaderDesc.Faint(true)
 	r := &Repo{
 		common:     c,
 		tabs:       tb,
@@ -80,22 +202,43 @@ func New(c common.Common, comps ...common.TabComponent) *Repo {
 		state:      loadingState,
 		spinner:    s,
 		panesReady: make([]bool, len(comps)),
+		descInput:  ti,
 	}
 	return r
 }
 
+// hideTabs hides the tabs that have nothing to show for a repository.
+func (r *Repo) hideTabs(repo proto.Repository) {
+	for i, p := range r.panes {
+		if h, ok := p.(hiddenTab); ok {
+			r.tabs.SetHidden(i, repo != nil && h.Hidden(repo))
+		}
+	}
+}
+
 func (r *Repo) getMargins() (int, int) {
 	hh := lipgloss.Height(r.headerView())
 	hm := r.common.Styles.Repo.Body.GetVerticalFrameSize() +
 		hh +
 		r.common.Styles.Repo.Header.GetVerticalFrameSize() +
-		r.common.Styles.StatusBar.GetHeight()
+		r.statusbar.Height()
 	return 0, hm
 }
 
+// compact returns true if the repository view uses the compact layout, which
+// stacks the header and status bar and shrinks the tab bar to the active tab.
+func (r *Repo) compact() bool {
+	return r.common.Width < compactWidth
+}
+
 // SetSize implements common.Component.
 func (r *Repo) SetSize(width, height int) {
 	r.common.SetSize(width, height)
+	r.tabs.Compact = r.compact()
+	r.statusbar.SetCompact(r.compact())
+	// The height of the status bar depends on its width when it shows a
+	// whole path.
+	r.statusbar.SetSize(width, height)
 	_, hm := r.getMargins()
 	r.tabs.SetSize(width, height-hm)
 	r.statusbar.SetSize(width, height-hm)
@@ -104,33 +247,113 @@ func (r *Repo) SetSize(width, height int) {
 	}
 }
 
+// ShowsRelativeTimes implements common.RelativeTimes. It's up to the active
+// tab.
+func (r *Repo) ShowsRelativeTimes() bool {
+	if r.state != readyState || r.clone.show || r.fork.show || r.deps.show {
+		return false
+	}
+	t, ok := r.panes[r.activeTab].(common.RelativeTimes)
+	return ok && t.ShowsRelativeTimes()
+}
+
 // Path returns the current component path.
 func (r *Repo) Path() string {
+	if r.clone.show {
+		return "clone"
+	}
+	if r.fork.show {
+		return "forks"
+	}
+	if r.deps.show {
+		return "deps"
+	}
 	return r.panes[r.activeTab].Path()
 }
 
+// TabName returns the name of the active tab.
+func (r *Repo) TabName() string {
+	return r.panes[r.activeTab].TabName()
+}
+
+// IsEditing returns true if the repository description is being edited.
+func (r *Repo) IsEditing() bool {
+	return r.editing || r.filteringLog()
+}
+
+// filteringLog returns true if the filter of the log is being typed.
+func (r *Repo) filteringLog() bool {
+	l, ok := r.panes[r.activeTab].(*Log)
+	return ok && l.IsFiltering()
+}
+
 func (r *Repo) commonHelp() []key.Binding {
 	b := make([]key.Binding, 0)
+	if r.editing {
+		return append(b, saveDescription, cancelDescription)
+	}
+	if r.clone.show {
+		return r.cloneInstructionsHelp()
+	}
+	if r.fork.show {
+		return r.forksHelp()
+	}
+	if r.deps.show {
+		return r.depsHelp()
+	}
 	back := r.common.KeyMap.Back
 	back.SetHelp("esc", "back to menu")
 	tab := r.common.KeyMap.Section
 	tab.SetHelp("tab", "switch tab")
 	b = append(b, back)
 	b = append(b, tab)
+	if r.hideURL() {
+		b = append(b, copyURL)
+	}
+	if r.canEdit {
+		b = append(b, editDescription)
+	}
+	if r.selectedRepo != nil {
+		b = append(b, toggleWatch, refresh)
+	}
+	if r.clone.text != "" {
+		b = append(b, showCloneInstructions)
+	}
+	if len(r.fork.repos()) > 0 {
+		b = append(b, showForks)
+	}
+	if r.selectedRepo != nil {
+		b = append(b, showDeps)
+	}
+	if r.visit.count > 0 {
+		b = append(b, showUnseen)
+	}
 	return b
 }
 
 // ShortHelp implements help.KeyMap.
 func (r *Repo) ShortHelp() []key.Binding {
+	if r.filteringLog() {
+		return r.panes[r.activeTab].(help.KeyMap).ShortHelp()
+	}
 	b := r.commonHelp()
+	if r.editing || r.clone.show || r.fork.show || r.deps.show {
+		return b
+	}
 	b = append(b, r.panes[r.activeTab].(help.KeyMap).ShortHelp()...)
 	return b
 }
 
 // FullHelp implements help.KeyMap.
 func (r *Repo) FullHelp() [][]key.Binding {
+	if r.filteringLog() {
+		return r.panes[r.activeTab].(help.KeyMap).FullHelp()
+	}
 	b := make([][]key.Binding, 0)
 	b = append(b, r.commonHelp())
+	if r.editing || r.clone.show || r.fork.show || r.deps.show {
+		return b
+	}
 	b = append(b, r.panes[r.activeTab].(help.KeyMap).FullHelp()...)
 	return b
 }
@@ -138,9 +361,10 @@ func (r *Repo) FullHelp() [][]key.Binding {
 // Init implements tea.View.
 func (r *Repo) Init() tea.Cmd {
 	r.state = loadingState
-	r.activeTab = 0
+	tcmd := r.tabs.Init()
+	r.activeTab = r.tabs.Active()
 	return tea.Batch(
-		r.tabs.Init(),
+		tcmd,
 		r.statusbar.Init(),
 		r.spinner.Tick,
 	)
@@ -148,21 +372,102 @@ func (r *Repo) Init() tea.Cmd {
 
 // Update implements tea.Model.
 func (r *Repo) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && r.editing {
+		return r, r.updateDescription(msg)
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok && r.clone.show {
+		return r, r.updateCloneInstructions(msg)
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok && r.fork.show {
+		return r, r.updateForks(msg)
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok && r.deps.show {
+		return r, r.updateDeps(msg)
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok && r.filteringLog() {
+		cmd := r.updateTabComponent(&Log{}, msg)
+		r.setStatusBarInfo()
+		return r, cmd
+	}
+
 	cmds := make([]tea.Cmd, 0)
+	if r.editing {
+		// Keep the cursor blinking.
+		var cmd tea.Cmd
+		r.descInput, cmd = r.descInput.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
 	switch msg := msg.(type) {
 	case RepoMsg:
 		// Set the state to loading when we get a new repository.
 		r.selectedRepo = msg
+		r.headStatus = ""
+		r.editing = false
+		r.access = r.accessLevel()
+		r.canEdit = r.access >= access.ReadWriteAccess
+		r.owner = r.ownerName()
+		r.mirror = r.mirrorInfo()
+		r.fork = r.forkInfo()
+		r.watchers = r.watcherCount()
+		r.deps = depsInfo{}
+		r.visit = visitInfo{}
+		r.clone = cloneInstructions{text: r.cloneInstructionsText()}
+		r.avatar = r.common.Avatar(msg, avatarSize)
+		r.jumps = nil
+		r.hideTabs(msg)
+		// The header height depends on the repository.
+		r.SetSize(r.common.Width, r.common.Height)
 		cmds = append(cmds,
 			r.Init(),
 			// This will set the selected repo in each pane's model.
 			r.updateModels(msg),
+			r.landingTabCmd(msg),
+			r.visitCmd(msg),
 		)
 	case RefMsg:
 		r.ref = msg
-		cmds = append(cmds, r.updateModels(msg))
+		r.headStatus = ""
+		r.jumps = nil
+		cmds = append(cmds, r.updateModels(msg), r.headStatusCmd(msg))
 		r.state = readyState
+	case HeadStatusMsg:
+		r.headStatus = proto.CommitState(msg)
+	case DepsMsg:
+		if r.deps.show {
+			r.setDeps(msg)
+		}
+	case VisitMsg:
+		if r.selectedRepo != nil && msg.repo == r.selectedRepo.Name() {
+			r.visit = msg.info
+		}
+	case refreshMsg:
+		r.selectedRepo = msg.repo
+		r.SetSize(r.common.Width, r.common.Height)
+		var restore tea.Cmd
+		if t, ok := r.panes[r.activeTab].(refreshTab); ok {
+			restore = t.RestoreCmd()
+		}
+		ref := msg.ref
+		return r, tea.Sequence(
+			func() tea.Msg { return ref },
+			restore,
+			statusCmd("Refreshed"),
+		)
+	case WatchedMsg:
+		if r.selectedRepo != nil && r.selectedRepo.Name() == msg.Repo {
+			r.watchers = r.watcherCount()
+		}
+	case DescriptionMsg:
+		r.selectedRepo = msg.Repo
+		r.SetSize(r.common.Width, r.common.Height)
+		r.statusbar.SetStatus("", "Description updated", "", "")
+		return r, nil
 	case tabs.SelectTabMsg:
+		if r.tabs.Hidden(int(msg)) {
+			break
+		}
 		r.activeTab = int(msg)
 		t, cmd := r.tabs.Update(msg)
 		r.tabs = t.(*tabs.Tabs)
@@ -179,9 +484,8 @@ func (r *Repo) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		if r.selectedRepo != nil {
 			urlID := fmt.Sprintf("%s-url", r.selectedRepo.Name())
-			cmd := r.common.CloneCmd(r.common.Config().SSH.PublicURL, r.selectedRepo.Name())
 			if msg, ok := msg.(tea.MouseMsg); ok && r.common.Zone.Get(urlID).InBounds(msg) {
-				cmds = append(cmds, copyCmd(cmd, "Command copied to clipboard"))
+				cmds = append(cmds, r.copyURLCmd())
 			}
 		}
 		switch msg := msg.(type) {
@@ -207,6 +511,32 @@ func (r *Repo) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch {
 			case key.Matches(msg, r.common.KeyMap.Back):
 				cmds = append(cmds, goBackCmd)
+			case key.Matches(msg, editDescription) && r.canEdit && r.state == readyState:
+				return r, r.startEditing()
+			case key.Matches(msg, showCloneInstructions) && r.clone.text != "" && r.state == readyState:
+				r.clone.show = true
+				return r, nil
+			case key.Matches(msg, showForks) && len(r.fork.repos()) > 0 && r.state == readyState:
+				r.fork.show, r.fork.cursor = true, 0
+				return r, nil
+			case key.Matches(msg, showDeps) && r.selectedRepo != nil && r.state == readyState:
+				r.deps = depsInfo{show: true, loading: true}
+				return r, r.depsCmd()
+			case key.Matches(msg, showUnseen) && r.visit.count > 0 && r.state == readyState:
+				cmds = append(cmds,
+					r.updateTabComponent(&Log{}, LogQueryMsg(r.visit.unseenQuery())),
+					switchTabCmd(&Log{}),
+				)
+			case key.Matches(msg, copyURL) && r.hideURL() && r.selectedRepo != nil:
+				cmds = append(cmds, r.copyURLCmd())
+			case key.Matches(msg, refresh) && r.selectedRepo != nil && r.state == readyState:
+				r.state = loadingState
+				cmds = append(cmds, r.spinner.Tick, r.refreshCmd())
+			case key.Matches(msg, toggleWatch) && r.selectedRepo != nil:
+				name := r.selectedRepo.Name()
+				cmds = append(cmds, func() tea.Msg {
+					return ToggleWatchMsg{Repo: name}
+				})
 			}
 		}
 	case CopyMsg:
@@ -214,17 +544,63 @@ func (r *Repo) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if cfg := r.common.Config(); cfg != nil {
 			r.common.Output.Copy(txt)
 		}
-		r.statusbar.SetStatus("", msg.Message, "", "")
-	case ReadmeMsg:
+		r.setStatusBarNotice(msg.Message)
+	case StatusMsg:
+		r.setStatusBarNotice(string(msg))
+	case LogJumpMsg:
+		return r, r.jumpTo(&Log{}, msg)
+	case FileJumpMsg:
+		return r, r.jumpTo(&Files{}, msg)
+	case fileJumpResultMsg:
+		cmd := r.updateTabComponent(&Files{}, msg)
+		r.setStatusBarInfo()
+		return r, cmd
+	case FileOpenMsg:
+		return r, tea.Batch(r.updateTabComponent(&Files{}, msg), switchTabCmd(&Files{}))
+	case LocationMsg:
+		cmd := r.goToLocation(Location(msg))
+		r.setStatusBarInfo()
+		return r, cmd
+	case CommitOpenMsg:
+		return r, tea.Batch(r.updateTabComponent(&Log{}, msg), switchTabCmd(&Log{}))
+	case commitOpenResultMsg:
+		cmd := r.updateTabComponent(&Log{}, msg)
+		r.setStatusBarInfo()
+		return r, cmd
+	case fileOpenResultMsg:
+		cmd := r.updateTabComponent(&Files{}, msg)
+		r.setStatusBarInfo()
+		return r, cmd
+	case JumpBackMsg:
+		if n := len(r.jumps); n > 0 {
+			cmds = append(cmds, tabs.SelectTabCmd(r.jumps[n-1]))
+			r.jumps = r.jumps[:n-1]
+		}
+	case ReadmeMsg, LanguagesMsg, ReadmeRefsMsg, ReadmeDiffMsg:
 		cmds = append(cmds, r.updateTabComponent(&Readme{}, msg))
-	case FileItemsMsg, FileContentMsg:
+	case FileItemsMsg, FileTreeMsg, FileContentMsg, FileChangeRefsMsg, FileChangesMsg, FileChangeDiffMsg:
 		cmds = append(cmds, r.updateTabComponent(&Files{}, msg))
-	case LogItemsMsg, LogDiffMsg, LogCountMsg:
+	case LogItemsMsg, LogDiffMsg, LogResolutionMsg, LogCountMsg, LogMatchesMsg, LogQueryMsg, LogStatusesMsg, LogRefsMsg, LogIdentitiesMsg, LogSignatureMsg:
 		cmds = append(cmds, r.updateTabComponent(&Log{}, msg))
 	case RefItemsMsg:
 		cmds = append(cmds, r.updateTabComponent(&Refs{refPrefix: msg.prefix}, msg))
+	case RefUnrelatedMsg:
+		cmds = append(cmds, r.updateTabComponent(&Refs{refPrefix: msg.prefix}, msg))
+	case RefMergeMsg:
+		cmds = append(cmds, r.updateTabComponent(&Refs{refPrefix: msg.prefix}, msg))
+	case RefConflictMsg:
+		cmds = append(cmds, r.updateTabComponent(&Refs{refPrefix: msg.prefix}, msg))
+	case RefTagMsg:
+		cmds = append(cmds, r.updateTabComponent(&Refs{refPrefix: msg.prefix}, msg))
 	case StashListMsg, StashPatchMsg:
 		cmds = append(cmds, r.updateTabComponent(&Stash{}, msg))
+	case ReleasesMsg, ReleaseNotesMsg:
+		cmds = append(cmds, r.updateTabComponent(&Releases{}, msg))
+	case InsightsActivityMsg, InsightsLanguagesMsg, InsightsBranchesMsg, InsightsReleaseMsg:
+		cmds = append(cmds, r.updateTabComponent(&Insights{}, msg))
+	case code.RenderedMsg:
+		// The content might be rendered for a tab that isn't active anymore.
+		return r, r.updateModels(msg)
 	// We have two spinners, one is used to when loading the repository and the
 	// other is used when loading the log.
 	// Check if the spinner ID matches the spinner model.
@@ -275,10 +651,19 @@ func (r *Repo) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Must come after we've updated the active tab
 	switch msg.(type) {
 	case RepoMsg, RefMsg, tabs.ActiveTabMsg, tea.KeyMsg, tea.MouseMsg,
-		FileItemsMsg, FileContentMsg, FileBlameMsg, selector.ActiveMsg,
-		LogItemsMsg, GoBackMsg, LogDiffMsg, EmptyRepoMsg,
-		StashListMsg, StashPatchMsg:
+		FileItemsMsg, FileTreeMsg, FileContentMsg, FileBlameMsg, selector.ActiveMsg,
+		LogItemsMsg, GoBackMsg, LogDiffMsg, LogResolutionMsg, EmptyRepoMsg, JumpBackMsg,
+		RefMergeMsg, RefConflictMsg, RefTagMsg, ReadmeRefsMsg, ReadmeDiffMsg,
+		StashListMsg, StashPatchMsg, FileChangeRefsMsg, FileChangesMsg, FileChangeDiffMsg,
+		ReleasesMsg, ReleaseNotesMsg, InsightsActivityMsg, InsightsLanguagesMsg,
+		InsightsBranchesMsg, InsightsReleaseMsg:
 		r.setStatusBarInfo()
+	case selector.WindowMsg:
+		// Show that Files is loading the page it scrolled to. Other window
+		// changes leave the status bar, and its notice, as it is.
+		if f, ok := r.panes[r.activeTab].(*Files); ok && f.loadingPage {
+			r.setStatusBarInfo()
+		}
 	}
 
 	s, cmd := r.statusbar.Update(msg)
@@ -307,6 +692,21 @@ func (r *Repo) View() string {
 		main = fmt.Sprintf("%s loading…", r.spinner.View())
 	case readyState:
 		main = r.panes[r.activeTab].View()
+		if r.clone.show {
+			main = r.common.Renderer.NewStyle().
+				MaxHeight(r.common.Height - hm - mainStyle.GetVerticalFrameSize()).
+				Render(r.cloneInstructionsView())
+		}
+		if r.fork.show {
+			main = r.common.Renderer.NewStyle().
+				MaxHeight(r.common.Height - hm - mainStyle.GetVerticalFrameSize()).
+				Render(r.forksView())
+		}
+		if r.deps.show {
+			main = r.common.Renderer.NewStyle().
+				MaxHeight(r.common.Height - hm - mainStyle.GetVerticalFrameSize()).
+				Render(r.depsView())
+		}
 		statusbar = r.statusbar.View()
 	}
 	main = r.common.Zone.Mark(
@@ -332,49 +732,325 @@ func (r *Repo) headerView() string {
 		header = r.selectedRepo.Name()
 	}
 	header = r.common.Styles.Repo.HeaderName.Render(header)
+	if status := renderCommitState(r.common.Styles, r.headStatus); status != "" {
+		header += " " + status
+	}
 	desc := strings.TrimSpace(r.selectedRepo.Description())
+	if r.editing {
+		desc = r.descInput.View()
+	} else if desc != "" {
+		desc = r.common.Styles.Repo.HeaderDesc.Render(desc)
+	}
 	if desc != "" {
 		header = lipgloss.JoinVertical(lipgloss.Left,
 			header,
-			r.common.Styles.Repo.HeaderDesc.Render(desc),
+			desc,
 		)
 	}
-	urlStyle := r.common.Styles.URLStyle.
-		Width(r.common.Width - lipgloss.Width(desc) - 1).
-		Align(lipgloss.Right)
-	var url string
-	if cfg := r.common.Config(); cfg != nil {
-		url = r.common.CloneCmd(cfg.SSH.PublicURL, r.selectedRepo.Name())
-	}
-	url = common.TruncateString(url, r.common.Width-lipgloss.Width(desc)-1)
-	url = r.common.Zone.Mark(
-		fmt.Sprintf("%s-url", r.selectedRepo.Name()),
-		urlStyle.Render(url),
+	header = lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		r.common.Styles.Repo.HeaderDesc.Faint(true).Render(r.metaView()),
 	)
+	if !r.compact() {
+		header = lipgloss.JoinHorizontal(lipgloss.Top, r.avatar, " ", header)
+	}
+	style := r.common.Styles.Repo.Header.Width(r.common.Width)
+	if !r.hideURL() && r.compact() {
+		// Stack the clone command on its own line under the name and
+		// description.
+		var url string
+		if cfg := r.common.Config(); cfg != nil {
+			url = r.common.CloneCmd(cfg, r.selectedRepo.Name())
+		}
+		url = common.TruncateString(url, r.common.Width)
+		url = r.common.Zone.Mark(
+			fmt.Sprintf("%s-url", r.selectedRepo.Name()),
+			r.common.Styles.URLStyle.MarginLeft(0).Render(url),
+		)
 
-	header = lipgloss.JoinHorizontal(lipgloss.Top, header, url)
+		lines := r.common.Styles.Repo.Header.GetMaxHeight()
+		header = lipgloss.JoinVertical(lipgloss.Left,
+			r.common.Renderer.NewStyle().MaxHeight(lines).Render(header),
+			url,
+		)
+		style = style.MaxHeight(lines + 1)
+	} else if !r.hideURL() {
+		// Leave room for the name, description, and metadata on the left.
+		urlWidth := r.common.Width - lipgloss.Width(header) - 1
+		urlStyle := r.common.Styles.URLStyle.
+			Width(urlWidth).
+			Align(lipgloss.Right)
+		var url string
+		if cfg := r.common.Config(); cfg != nil {
+			url = r.common.CloneCmd(cfg, r.selectedRepo.Name())
+		}
+		url = common.TruncateString(url, urlWidth)
+		url = r.common.Zone.Mark(
+			fmt.Sprintf("%s-url", r.selectedRepo.Name()),
+			urlStyle.Render(url),
+		)
+
+		header = lipgloss.JoinHorizontal(lipgloss.Top, header, url)
+	}
 
-	style := r.common.Styles.Repo.Header.Width(r.common.Width)
 	return style.Render(
 		truncate.Render(header),
 	)
 }
 
+// hideURL returns true if the clone command is hidden from the header.
+func (r *Repo) hideURL() bool {
+	cfg := r.common.Config()
+	return cfg != nil && cfg.UI.HideCloneURL
+}
+
+// copyURLCmd copies the clone command of the selected repository.
+func (r *Repo) copyURLCmd() tea.Cmd {
+	var url string
+	if cfg := r.common.Config(); cfg != nil {
+		url = r.common.CloneCmd(cfg, r.selectedRepo.Name())
+	}
+	return copyCmd(url, "Command copied to clipboard")
+}
+
+// metaView returns the owner and creation date of the selected repository,
+// where it's mirrored or forked from, its forks and watchers, and what's new
+// since the last visit.
+func (r *Repo) metaView() string {
+	owner := r.owner
+	if owner == "" {
+		owner = "unknown"
+	}
+	created := "unknown"
+	if t := r.selectedRepo.CreatedAt(); !t.IsZero() {
+		created = t.Format("Jan 02 2006")
+	}
+	meta := fmt.Sprintf("Owner %s · Created %s", owner, created)
+	if m := r.mirrorView(); m != "" {
+		meta += " · " + m
+	}
+	if f := r.forkView(); f != "" {
+		meta += " · " + f
+	}
+	if w := r.watchersView(); w != "" {
+		meta += " · " + w
+	}
+	if r.selectedRepo.IsArchived() {
+		meta += " · Archived, read-only"
+	}
+	if v := r.visit.visitView(); v != "" {
+		meta += " · " + v
+	}
+	return meta
+}
+
+// ownerName returns the username of the owner of the selected repository. It
+// returns an empty string if the repository doesn't have an owner, e.g. repos
+// that were added to the data directory by hand.
+func (r *Repo) ownerName() string {
+	be := r.common.Backend()
+	if be == nil || r.selectedRepo == nil || r.selectedRepo.UserID() <= 0 {
+		return ""
+	}
+	owner, err := be.UserByID(r.common.Context(), r.selectedRepo.UserID())
+	if err != nil {
+		r.common.Logger.Debugf("ui: failed to get repository owner: %v", err)
+		return ""
+	}
+	return owner.Username()
+}
+
+// accessLevel returns the access level of the user to the selected
+// repository. Users with read-write access can change its description.
+func (r *Repo) accessLevel() access.AccessLevel {
+	be := r.common.Backend()
+	if be == nil || r.selectedRepo == nil {
+		return access.NoAccess
+	}
+	return be.AccessLevelByPublicKey(r.common.Context(), r.selectedRepo.Name(), r.common.PublicKey())
+}
+
+// loadViewSettings returns the view settings of the given repository. Invalid
+// settings are ignored.
+func loadViewSettings(c common.Common, repo proto.Repository) backend.ViewSettings {
+	be := c.Backend()
+	if be == nil || repo == nil {
+		return backend.ViewSettings{}
+	}
+	vs, err := be.ViewSettings(c.Context(), repo.Name())
+	if err != nil {
+		c.Logger.Debugf("ui: failed to load view settings: %v", err)
+		return backend.ViewSettings{}
+	}
+	return vs
+}
+
+// startEditing focuses the description input.
+func (r *Repo) startEditing() tea.Cmd {
+	r.editing = true
+	r.descInput.Width = r.common.Width / 2
+	r.descInput.SetValue(strings.TrimSpace(r.selectedRepo.Description()))
+	r.descInput.CursorEnd()
+	r.SetSize(r.common.Width, r.common.Height)
+	return r.descInput.Focus()
+}
+
+// stopEditing blurs the description input.
+func (r *Repo) stopEditing() {
+	r.editing = false
+	r.descInput.Blur()
+	r.SetSize(r.common.Width, r.common.Height)
+}
+
+// updateDescription handles key presses while editing the description.
+func (r *Repo) updateDescription(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, cancelDescription):
+		r.stopEditing()
+		return nil
+	case key.Matches(msg, saveDescription):
+		desc := strings.TrimSpace(r.descInput.Value())
+		r.stopEditing()
+		return r.setDescriptionCmd(desc)
+	}
+	var cmd tea.Cmd
+	r.descInput, cmd = r.descInput.Update(msg)
+	return cmd
+}
+
+// setDescriptionCmd saves the description of the selected repository and
+// sends a DescriptionMsg with the updated repository.
+func (r *Repo) setDescriptionCmd(desc string) tea.Cmd {
+	repo := r.selectedRepo
+	be := r.common.Backend()
+	if repo == nil || be == nil {
+		return nil
+	}
+
+	ctx := r.common.Context()
+	return func() tea.Msg {
+		if err := be.SetDescription(ctx, repo.Name(), desc); err != nil {
+			return common.ErrorMsg(err)
+		}
+		rr, err := be.Repository(ctx, repo.Name())
+		if err != nil {
+			return common.ErrorMsg(err)
+		}
+		return DescriptionMsg{Repo: rr}
+	}
+}
+
+// refreshCmd loads the selected repository and the browsed reference again,
+// for when they changed since they were loaded, e.g. after a push. The default
+// branch is resolved from HEAD again, in case it changed too.
+func (r *Repo) refreshCmd() tea.Cmd {
+	repo, ref := r.selectedRepo, r.ref
+	be := r.common.Backend()
+	ctx := r.common.Context()
+	return func() tea.Msg {
+		if be != nil {
+			rr, err := be.Repository(ctx, repo.Name())
+			if err != nil {
+				return common.ErrorMsg(err)
+			}
+			repo = rr
+		}
+
+		refCmd := UpdateRefCmd(repo)
+		if ref != nil {
+			gr, err := repo.Open()
+			if err != nil {
+				return common.ErrorMsg(err)
+			}
+			if head, err := gr.HEAD(); err != nil || head.Name() != ref.Name() {
+				refCmd = UpdateRefNameCmd(repo, ref.Name().String())
+			}
+		}
+		return refreshMsg{repo: repo, ref: refCmd()}
+	}
+}
+
+// headStatusCmd loads the commit status of the latest commit of the given
+// reference in the background.
+func (r *Repo) headStatusCmd(ref *git.Reference) tea.Cmd {
+	repo := r.selectedRepo
+	be := r.common.Backend()
+	if repo == nil || ref == nil || be == nil {
+		return nil
+	}
+
+	ctx := r.common.Context()
+	return func() tea.Msg {
+		statuses, err := be.CommitStatuses(ctx, repo, ref.ID)
+		if err != nil {
+			r.common.Logger.Debugf("ui: error loading commit statuses: %v", err)
+			return nil
+		}
+		return HeadStatusMsg(proto.CombinedCommitState(statuses))
+	}
+}
+
 func (r *Repo) setStatusBarInfo() {
 	if r.selectedRepo == nil {
 		return
 	}
 
+	h := r.statusbar.Height()
 	active := r.panes[r.activeTab]
 	key := r.selectedRepo.Name()
 	value := active.StatusBarValue()
 	info := active.StatusBarInfo()
-	extra := "*"
+	extra := refStatus(r.ref)
+	fields := map[string]string{"access": r.access.String()}
 	if r.ref != nil {
-		extra += " " + r.ref.Name().Short()
+		fields["sha"] = shortID(r.ref.ID)
 	}
 
 	r.statusbar.SetStatus(key, value, info, extra)
+	r.statusbar.SetFields(fields)
+	var path, expanded bool
+	if p, ok := active.(pathStatusBar); ok {
+		path, expanded = p.StatusBarPath()
+	}
+	r.statusbar.SetPath(path, expanded)
+	if r.statusbar.Height() != h {
+		r.SetSize(r.common.Width, r.common.Height)
+	}
+}
+
+// setStatusBarNotice shows a notice in place of the status bar value until
+// the status bar is updated again.
+func (r *Repo) setStatusBarNotice(notice string) {
+	h := r.statusbar.Height()
+	r.statusbar.SetNotice(notice)
+	if r.statusbar.Height() != h {
+		r.SetSize(r.common.Width, r.common.Height)
+	}
+}
+
+// refStatus returns the status bar segment of the browsed reference: the name
+// of a branch, the name of a tag, or the commit of a detached reference.
+func refStatus(ref *git.Reference) string {
+	switch {
+	case ref == nil:
+		return "*"
+	case ref.IsBranch(), ref.IsRemoteBranch():
+		return "* " + ref.Name().Short()
+	case ref.IsTag():
+		return "◇ tag: " + ref.Name().Short()
+	case ref.Refspec == ref.ID, ref.Refspec == git.HEAD:
+		return "○ detached @ " + shortID(ref.ID)
+	default:
+		return "* " + ref.Name().Short()
+	}
+}
+
+// shortID returns the abbreviated commit hash of an ID.
+func shortID(id string) string {
+	if len(id) > 7 {
+		return id[:7]
+	}
+	return id
 }
 
 func (r *Repo) updateTabComponent(c common.TabComponent, msg tea.Msg) tea.Cmd {
@@ -392,6 +1068,18 @@ func (r *Repo) updateTabComponent(c common.TabComponent, msg tea.Msg) tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
+// jumpTo remembers the active tab to jump back to it later, passes the message
+// to the given tab, and switches to it.
+func (r *Repo) jumpTo(c common.TabComponent, msg tea.Msg) tea.Cmd {
+	for i, p := range r.panes {
+		if p.TabName() == c.TabName() {
+			r.jumps = append(r.jumps, r.activeTab)
+			return tea.Batch(r.updateTabComponent(c, msg), tabs.SelectTabCmd(i))
+		}
+	}
+	return nil
+}
+
 func (r *Repo) updateModels(msg tea.Msg) tea.Cmd {
 	cmds := make([]tea.Cmd, 0)
 	for i, b := range r.panes {
@@ -413,10 +1101,28 @@ func copyCmd(text, msg string) tea.Cmd {
 	}
 }
 
+// linesString returns a human readable line range.
+func linesString(start, end int) string {
+	if start == end {
+		return fmt.Sprintf("Line %d", start)
+	}
+	return fmt.Sprintf("Lines %d-%d", start, end)
+}
+
 func goBackCmd() tea.Msg {
 	return GoBackMsg{}
 }
 
+func statusCmd(msg string) tea.Cmd {
+	return func() tea.Msg {
+		return StatusMsg(msg)
+	}
+}
+
+func jumpBackCmd() tea.Msg {
+	return JumpBackMsg{}
+}
+
 func switchTabCmd(m common.TabComponent) tea.Cmd {
 	return func() tea.Msg {
 		return SwitchTabMsg(m)
@@ -3,7 +3,6 @@ package repo
 import (
 	"fmt"
 
-	gitm "github.com/aymanbagabas/git-module"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
@@ -24,7 +23,7 @@ const (
 )
 
 // StashListMsg is a message sent when the stash list is loaded.
-type StashListMsg []*gitm.Stash
+type StashListMsg []*git.Stash
 
 // StashPatchMsg is a message sent when the stash patch is loaded.
 type StashPatchMsg struct{ *git.Diff }
@@ -74,6 +73,16 @@ func (s *Stash) TabName() string {
 	return "Stash"
 }
 
+// Hidden implements hiddenTab. The tab is only shown for repositories with a
+// stash, which are rare.
+func (s *Stash) Hidden(repo proto.Repository) bool {
+	r, err := repo.Open()
+	if err != nil {
+		return true
+	}
+	return !r.HasStash()
+}
+
 // SetSize implements common.Component.
 func (s *Stash) SetSize(width, height int) {
 	s.common.SetSize(width, height)
@@ -155,6 +164,10 @@ func (s *Stash) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.ref = msg
 		s.list.Select(0)
 		cmds = append(cmds, s.Init())
+	case code.RenderedMsg:
+		c, cmd := s.code.Update(msg)
+		s.code = c.(*code.Code)
+		return s, cmd
 	case tea.WindowSizeMsg:
 		s.SetSize(msg.Width, msg.Height)
 	case spinner.TickMsg:
@@ -201,7 +214,7 @@ func (s *Stash) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				title,
 				"",
 				renderSummary(msg.Diff, s.common.Styles, s.common.Width),
-				renderDiff(msg.Diff, s.common.Width),
+				renderDiff(msg.Diff, s.common.Styles, s.common.Width),
 			)
 			cmds = append(cmds, s.code.SetContent(content, ".diff"))
 			s.code.GotoTop()
@@ -257,7 +270,7 @@ func (s *Stash) fetchStash() tea.Msg {
 		return common.ErrorMsg(err)
 	}
 
-	stash, err := r.StashList()
+	stash, err := r.Stashes()
 	if err != nil {
 		return common.ErrorMsg(err)
 	}
@@ -4,15 +4,15 @@ import (
 	"fmt"
 	"io"
 
-	gitm "github.com/aymanbagabas/git-module"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/soft-serve/git"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
 )
 
 // StashItem represents a stash item.
-type StashItem struct{ *gitm.Stash }
+type StashItem struct{ *git.Stash }
 
 // ID returns the ID of the stash item.
 func (i StashItem) ID() string {
@@ -0,0 +1,110 @@
+package repo
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+)
+
+var showUnseen = key.NewBinding(
+	key.WithKeys("U"),
+	key.WithHelp("U", "new commits"),
+)
+
+// VisitMsg is a message that contains what changed in a repository since the
+// user last opened it.
+type VisitMsg struct {
+	repo string
+	info visitInfo
+}
+
+// visitInfo is what changed in the selected repository since the user last
+// opened it.
+type visitInfo struct {
+	// since is the hash of the HEAD commit the last time, and count the
+	// number of commits made since then.
+	since string
+	count int
+	// rewritten is true if the last seen commit isn't part of the history
+	// anymore, e.g. after a force push.
+	rewritten bool
+}
+
+// visitView returns the number of new commits since the last visit.
+func (v visitInfo) visitView() string {
+	switch {
+	case v.rewritten:
+		return "History rewritten since last visit"
+	case v.count == 1:
+		return "1 new commit since last visit"
+	case v.count > 1:
+		return fmt.Sprintf("%d new commits since last visit", v.count)
+	}
+	return ""
+}
+
+// unseenQuery returns the log filter that shows the new commits.
+func (v visitInfo) unseenQuery() string {
+	since := v.since
+	if len(since) > 7 {
+		since = since[:7]
+	}
+	return "since:" + since
+}
+
+// visitCmd records the HEAD commit of the repository as seen by the user, and
+// returns what changed since the previous visit. Nothing is shown on the
+// first visit, and the history rewritten since then falls back to the
+// current HEAD.
+func (r *Repo) visitCmd(repo proto.Repository) tea.Cmd {
+	be := r.common.Backend()
+	pk := r.common.PublicKey()
+	if be == nil || pk == nil {
+		return nil
+	}
+
+	ctx := r.common.Context()
+	return func() tea.Msg {
+		rr, err := repo.Open()
+		if err != nil {
+			r.common.Logger.Debugf("ui: error opening repository: %v", err)
+			return nil
+		}
+		head, err := rr.HEAD()
+		if err != nil {
+			// Empty repositories don't have a HEAD commit.
+			return nil
+		}
+		pref := common.LastSeenPreferenceName(repo.Name())
+		last, err := be.Preference(ctx, pk, pref)
+		if err != nil {
+			r.common.Logger.Debugf("ui: error loading last seen commit: %v", err)
+			return nil
+		}
+		if last != head.ID {
+			if err := be.SetPreference(ctx, pk, pref, head.ID); err != nil {
+				r.common.Logger.Debugf("ui: error saving last seen commit: %v", err)
+			}
+		}
+		if last == "" || last == head.ID {
+			return nil
+		}
+
+		msg := VisitMsg{repo: repo.Name()}
+		if bases, err := rr.MergeBase(last, head.ID, false); err != nil || len(bases) != 1 || bases[0] != last {
+			msg.info.rewritten = true
+			return msg
+		}
+		ids, err := rr.FilterCommits(head, git.LogFilter{Since: last})
+		if err != nil {
+			r.common.Logger.Debugf("ui: error counting new commits: %v", err)
+			return nil
+		}
+		msg.info.since, msg.info.count = last, len(ids)
+		return msg
+	}
+}
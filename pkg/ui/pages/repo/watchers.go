@@ -0,0 +1,36 @@
+package repo
+
+import "fmt"
+
+// WatchedMsg is a message sent once the user started or stopped watching the
+// pushes to a repository.
+type WatchedMsg struct {
+	Repo string
+}
+
+// watcherCount returns the number of watchers of the selected repository.
+func (r *Repo) watcherCount() int {
+	be := r.common.Backend()
+	if be == nil || r.selectedRepo == nil {
+		return 0
+	}
+	watchers, err := be.Watchers(r.common.Context(), r.selectedRepo.Name())
+	if err != nil {
+		r.common.Logger.Debugf("ui: failed to get watchers: %v", err)
+		return 0
+	}
+	return len(watchers)
+}
+
+// watchersView returns how many users watch the selected repository, shown
+// with the metadata of the repository.
+func (r *Repo) watchersView() string {
+	switch n := r.watchers; n {
+	case 0:
+		return ""
+	case 1:
+		return "1 watcher"
+	default:
+		return fmt.Sprintf("%d watchers", n)
+	}
+}
@@ -0,0 +1,150 @@
+package selection
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/dustin/go-humanize"
+)
+
+const (
+	// activityLimit is the number of pushes shown in the activity feed.
+	activityLimit = 100
+
+	// activityInterval is how often the activity feed is refreshed while
+	// it's shown.
+	activityInterval = 5 * time.Second
+)
+
+// ActivityMsg is a message that contains the recent pushes.
+type ActivityMsg []proto.PushEvent
+
+// activityTickMsg is a message to refresh the activity feed.
+type activityTickMsg struct{}
+
+// PushItem is a push in the activity feed.
+type PushItem struct {
+	proto.PushEvent
+}
+
+// ID implements selector.IdentifiableItem.
+func (i PushItem) ID() string {
+	return fmt.Sprintf("%s:%s:%s", i.Repo, i.Ref, i.After)
+}
+
+// Title returns the item title. Implements list.DefaultItem.
+func (i PushItem) Title() string {
+	return fmt.Sprintf("%s %s", i.Repo, git.ReferenceName(i.Ref).Short())
+}
+
+// Description returns the item description. Implements list.DefaultItem.
+func (i PushItem) Description() string {
+	pusher := i.Pusher
+	if pusher == "" {
+		pusher = "Someone"
+	}
+
+	var desc string
+	switch {
+	case git.IsZeroHash(i.After):
+		desc = fmt.Sprintf("%s deleted %s", pusher, git.ReferenceName(i.Ref).Short())
+	case i.Forced:
+		desc = fmt.Sprintf("⚠ %s force-pushed %s...%s", pusher, i.Before[:7], i.After[:7])
+	case i.Commits == 1:
+		desc = fmt.Sprintf("%s pushed 1 commit", pusher)
+	default:
+		desc = fmt.Sprintf("%s pushed %d commits", pusher, i.Commits)
+	}
+	if i.DeployScript != "" {
+		desc += fmt.Sprintf(" · deploy %s %s", i.DeployScript, i.DeployStatus)
+	}
+	return desc
+}
+
+// deployFailed returns true if the deploy the push ran failed or timed out.
+func (i PushItem) deployFailed() bool {
+	return i.DeployStatus == proto.DeployFailed || i.DeployStatus == proto.DeployTimedOut
+}
+
+// FilterValue implements list.Item.
+func (i PushItem) FilterValue() string { return i.Title() }
+
+// PushItemDelegate is the delegate for the activity feed items.
+type PushItemDelegate struct {
+	common *common.Common
+}
+
+// Height returns the item height. Implements list.ItemDelegate.
+func (d PushItemDelegate) Height() int { return 2 }
+
+// Spacing returns the spacing between items. Implements list.ItemDelegate.
+func (d PushItemDelegate) Spacing() int { return 1 }
+
+// Update implements list.ItemDelegate.
+func (d PushItemDelegate) Update(tea.Msg, *list.Model) tea.Cmd { return nil }
+
+// Render implements list.ItemDelegate.
+func (d PushItemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	i, ok := listItem.(PushItem)
+	if !ok {
+		return
+	}
+
+	styles := d.common.Styles.RepoSelector.Normal
+	if index == m.Index() {
+		styles = d.common.Styles.RepoSelector.Active
+	}
+
+	width := m.Width() - styles.Base.GetHorizontalFrameSize()
+	title := common.TruncateString(i.Title(), width)
+	when := " " + humanize.Time(i.CreatedAt)
+	if width-lipgloss.Width(when)-lipgloss.Width(title) <= 0 {
+		when = ""
+	}
+	when = styles.Updated.
+		Align(lipgloss.Right).
+		Width(width - lipgloss.Width(title)).
+		Render(when)
+
+	s := strings.Builder{}
+	s.WriteString(lipgloss.JoinHorizontal(lipgloss.Bottom, styles.Title.Render(title), when))
+	s.WriteRune('\n')
+	desc := styles.Desc
+	if i.Forced || i.deployFailed() {
+		desc = styles.Warning
+	}
+	s.WriteString(desc.Render(common.TruncateString(i.Description(), width)))
+	fmt.Fprint(w, styles.Base.Height(d.Height()).Render(s.String()))
+}
+
+// activityCmd loads the recent pushes to the repositories the user can
+// access.
+func (s *Selection) activityCmd() tea.Msg {
+	be := s.common.Backend()
+	if be == nil {
+		return nil
+	}
+
+	events, err := be.PushEvents(s.common.Context(), s.common.User(), nil, activityLimit)
+	if err != nil {
+		s.common.Logger.Debugf("ui: failed to load activity: %v", err)
+		return nil
+	}
+
+	return ActivityMsg(events)
+}
+
+// activityTickCmd refreshes the activity feed after activityInterval.
+func activityTickCmd() tea.Cmd {
+	return tea.Tick(activityInterval, func(time.Time) tea.Msg {
+		return activityTickMsg{}
+	})
+}
@@ -0,0 +1,230 @@
+package selection
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+)
+
+var (
+	markRepo = key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "mark"),
+	)
+	bulkActions = key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "bulk actions"),
+	)
+	confirmBulk = key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "confirm"),
+	)
+	cancelBulk = key.NewBinding(
+		key.WithKeys("n", "esc"),
+		key.WithHelp("n", "cancel"),
+	)
+)
+
+// bulkAction is an action applied to all the marked repositories.
+type bulkAction int
+
+const (
+	bulkMakePrivate bulkAction = iota
+	bulkMakePublic
+	bulkGC
+)
+
+// String returns the name of the action shown in the menu.
+func (a bulkAction) String() string {
+	return []string{
+		"Make private",
+		"Make public",
+		"Run gc",
+	}[a]
+}
+
+// describe describes the action applied to the given number of repositories.
+func (a bulkAction) describe(n int, past bool) string {
+	repos := fmt.Sprintf("%d repositories", n)
+	if n == 1 {
+		repos = "1 repository"
+	}
+	switch a {
+	case bulkMakePrivate:
+		if past {
+			return fmt.Sprintf("Made %s private", repos)
+		}
+		return fmt.Sprintf("Make %s private", repos)
+	case bulkMakePublic:
+		if past {
+			return fmt.Sprintf("Made %s public", repos)
+		}
+		return fmt.Sprintf("Make %s public", repos)
+	default:
+		if past {
+			return fmt.Sprintf("Ran gc on %s", repos)
+		}
+		return fmt.Sprintf("Run gc on %s", repos)
+	}
+}
+
+// bulkMenu is the menu of bulk actions for the marked repositories. Once an
+// action is chosen, it asks for a confirmation.
+type bulkMenu struct {
+	repos   []string
+	actions []bulkAction
+	cursor  int
+	confirm bool
+}
+
+// BulkMsg is a message that contains the result of a bulk action.
+type BulkMsg struct {
+	action bulkAction
+	done   int
+	failed []string
+}
+
+// String returns the summary of the bulk action.
+func (m BulkMsg) String() string {
+	s := m.action.describe(m.done, true)
+	if len(m.failed) > 0 {
+		s += fmt.Sprintf(", failed: %s", strings.Join(m.failed, ", "))
+	}
+	return s
+}
+
+// canBulkEdit returns true if the user can apply bulk actions to the
+// repositories. Only admins can.
+func (s *Selection) canBulkEdit() bool {
+	be := s.common.Backend()
+	if be == nil {
+		return false
+	}
+	return be.AccessLevelByPublicKey(s.common.Context(), "", s.common.PublicKey()) >= access.AdminAccess
+}
+
+// openBulkMenu opens the bulk action menu for the marked repositories.
+func (s *Selection) openBulkMenu() {
+	marked := s.selector.MarkedItems()
+	if len(marked) == 0 {
+		return
+	}
+	repos := make([]string, len(marked))
+	for i, it := range marked {
+		repos[i] = it.ID()
+	}
+	s.bulk = &bulkMenu{
+		repos:   repos,
+		actions: []bulkAction{bulkMakePrivate, bulkMakePublic, bulkGC},
+	}
+}
+
+// updateBulk handles key presses while the bulk action menu is open.
+func (s *Selection) updateBulk(msg tea.KeyMsg) tea.Cmd {
+	b := s.bulk
+	if b.confirm {
+		switch {
+		case key.Matches(msg, confirmBulk):
+			s.bulk = nil
+			return s.bulkCmd(b.actions[b.cursor], b.repos)
+		case key.Matches(msg, cancelBulk):
+			b.confirm = false
+		}
+		return nil
+	}
+
+	switch {
+	case key.Matches(msg, s.common.KeyMap.Back), key.Matches(msg, cancelBulk):
+		s.bulk = nil
+	case key.Matches(msg, s.common.KeyMap.Up):
+		if b.cursor > 0 {
+			b.cursor--
+		}
+	case key.Matches(msg, s.common.KeyMap.Down):
+		if b.cursor < len(b.actions)-1 {
+			b.cursor++
+		}
+	case key.Matches(msg, s.common.KeyMap.Select):
+		b.confirm = true
+	}
+	return nil
+}
+
+// bulkCmd applies the action to the given repositories.
+func (s *Selection) bulkCmd(action bulkAction, repos []string) tea.Cmd {
+	be := s.common.Backend()
+	ctx := s.common.Context()
+	return func() tea.Msg {
+		msg := BulkMsg{action: action}
+		for _, name := range repos {
+			var err error
+			switch action {
+			case bulkMakePrivate:
+				err = be.SetPrivate(ctx, name, true)
+			case bulkMakePublic:
+				err = be.SetPrivate(ctx, name, false)
+			case bulkGC:
+				err = be.GCRepository(ctx, name)
+			}
+			if err != nil {
+				s.common.Logger.Debugf("ui: bulk action %q failed for %s: %v", action, name, err)
+				msg.failed = append(msg.failed, name)
+				continue
+			}
+			msg.done++
+		}
+		return msg
+	}
+}
+
+// bulkHelp returns the key bindings of the bulk action menu.
+func (s *Selection) bulkHelp() []key.Binding {
+	if s.bulk.confirm {
+		return []key.Binding{confirmBulk, cancelBulk}
+	}
+	back := s.common.KeyMap.Back
+	back.SetHelp("esc", "cancel")
+	return []key.Binding{
+		s.common.KeyMap.UpDown,
+		s.common.KeyMap.Select,
+		back,
+	}
+}
+
+// renderBulk renders the bulk action menu.
+func (s *Selection) renderBulk() string {
+	b := s.bulk
+	st := s.common.Styles.RepoSelector
+	var sb strings.Builder
+	if b.confirm {
+		sb.WriteString(st.Active.Title.Render(b.actions[b.cursor].describe(len(b.repos), false) + "?"))
+		sb.WriteString("\n\n")
+		for _, name := range b.repos {
+			sb.WriteString("  " + common.TruncateString(name, s.common.Width-2))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+		sb.WriteString(st.Normal.Desc.Render("Press y to confirm or n to cancel."))
+		return sb.String()
+	}
+
+	n := fmt.Sprintf("%d repositories", len(b.repos))
+	if len(b.repos) == 1 {
+		n = "1 repository"
+	}
+	sb.WriteString(st.Normal.Title.Render("Bulk actions for " + n))
+	sb.WriteString("\n\n")
+	for i, a := range b.actions {
+		if i == b.cursor {
+			sb.WriteString(st.Active.Title.Render("> " + a.String()))
+		} else {
+			sb.WriteString(st.Normal.Desc.Render("  " + a.String()))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
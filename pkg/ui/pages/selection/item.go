@@ -18,6 +18,10 @@ import (
 
 var _ sort.Interface = Items{}
 
+// avatarSize is the width of the avatars of the items. They're as high as
+// the items.
+const avatarSize = 6
+
 // Items is a list of Item.
 type Items []Item
 
@@ -26,8 +30,15 @@ func (it Items) Len() int {
 	return len(it)
 }
 
-// Less implements sort.Interface.
+// Less implements sort.Interface. Archived repositories come last, and the
+// repositories of the teams of the user first.
 func (it Items) Less(i int, j int) bool {
+	if ai, aj := it[i].repo.IsArchived(), it[j].repo.IsArchived(); ai != aj {
+		return aj
+	}
+	if ti, tj := len(it[i].teams) > 0, len(it[j].teams) > 0; ti != tj {
+		return ti
+	}
 	if it[i].lastUpdate == nil && it[j].lastUpdate != nil {
 		return false
 	}
@@ -50,6 +61,8 @@ type Item struct {
 	repo       proto.Repository
 	lastUpdate *time.Time
 	cmd        string
+	// teams are the teams of the user with access to the repository.
+	teams []string
 }
 
 // New creates a new Item.
@@ -61,7 +74,7 @@ func NewItem(c common.Common, repo proto.Repository) (Item, error) {
 	}
 	var cmd string
 	if cfg := c.Config(); cfg != nil {
-		cmd = c.CloneCmd(cfg.SSH.PublicURL, repo.Name())
+		cmd = c.CloneCmd(cfg, repo.Name())
 	}
 	return Item{
 		repo:       repo,
@@ -101,6 +114,14 @@ type ItemDelegate struct {
 	common     *common.Common
 	activePane *pane
 	copiedIdx  int
+
+	// isMarked reports whether the item with the given ID is marked for a
+	// bulk action.
+	isMarked func(id string) bool
+
+	// avatar returns the rendered avatar of the item with the given ID, empty
+	// until it's rendered.
+	avatar func(id string) string
 }
 
 // NewItemDelegate creates a new ItemDelegate.
@@ -163,11 +184,25 @@ func (d *ItemDelegate) Render(w io.Writer, m list.Model, index int, listItem lis
 		styles = d.common.Styles.RepoSelector.Active
 	}
 
+	// Leave room for the avatar and the space after it.
+	width := m.Width() - styles.Base.GetHorizontalFrameSize() - avatarSize - 1
+
 	title := i.Title()
-	title = common.TruncateString(title, m.Width()-styles.Base.GetHorizontalFrameSize())
+	if d.isMarked != nil && d.isMarked(i.ID()) {
+		title = "✓ " + title
+	}
+	title = common.TruncateString(title, width)
 	if i.repo.IsPrivate() {
 		title += " 🔒"
 	}
+	if len(i.teams) > 0 {
+		title += " [" + strings.Join(i.teams, ", ") + "]"
+	}
+	// Archived repositories are dimmed.
+	archived := i.repo.IsArchived()
+	if archived {
+		title += " (archived)"
+	}
 	if isSelected {
 		title += " "
 	}
@@ -175,12 +210,13 @@ func (d *ItemDelegate) Render(w io.Writer, m list.Model, index int, listItem lis
 	if i.lastUpdate != nil {
 		updatedStr = fmt.Sprintf(" Updated %s", humanize.Time(*i.lastUpdate))
 	}
-	if m.Width()-styles.Base.GetHorizontalFrameSize()-lipgloss.Width(updatedStr)-lipgloss.Width(title) <= 0 {
+	if width-lipgloss.Width(updatedStr)-lipgloss.Width(title) <= 0 {
 		updatedStr = ""
 	}
 	updatedStyle := styles.Updated.
+		Faint(archived).
 		Align(lipgloss.Right).
-		Width(m.Width() - styles.Base.GetHorizontalFrameSize() - lipgloss.Width(title))
+		Width(width - lipgloss.Width(title))
 	updated := updatedStyle.Render(updatedStr)
 
 	if isFiltered && index < len(m.VisibleItems()) {
@@ -189,14 +225,14 @@ func (d *ItemDelegate) Render(w io.Writer, m list.Model, index int, listItem lis
 	}
 
 	if isFiltered {
-		unmatched := styles.Title.Inline(true)
+		unmatched := styles.Title.Faint(archived).Inline(true)
 		matched := unmatched.Underline(true)
 		title = lipgloss.StyleRunes(title, matchedRunes, matched, unmatched)
 	}
-	title = styles.Title.Render(title)
+	title = styles.Title.Faint(archived).Render(title)
 	desc := i.Description()
-	desc = common.TruncateString(desc, m.Width()-styles.Base.GetHorizontalFrameSize())
-	desc = styles.Desc.Render(desc)
+	desc = common.TruncateString(desc, width)
+	desc = styles.Desc.Faint(archived).Render(desc)
 
 	s.WriteString(lipgloss.JoinHorizontal(lipgloss.Bottom, title, updated))
 	s.WriteRune('\n')
@@ -204,17 +240,25 @@ func (d *ItemDelegate) Render(w io.Writer, m list.Model, index int, listItem lis
 	s.WriteRune('\n')
 
 	cmd := i.Command()
-	cmdStyler := styles.Command.Render
+	cmdStyler := styles.Command.Faint(archived).Render
 	if d.copiedIdx == index {
 		cmd = "(copied to clipboard)"
 		cmdStyler = styles.Desc.Render
 		d.copiedIdx = -1
 	}
-	cmd = common.TruncateString(cmd, m.Width()-styles.Base.GetHorizontalFrameSize())
+	cmd = common.TruncateString(cmd, width)
 	s.WriteString(cmdStyler(cmd))
+
+	var avatar string
+	if d.avatar != nil {
+		avatar = d.avatar(i.ID())
+	}
+	if avatar == "" {
+		avatar = strings.Repeat(" ", avatarSize)
+	}
 	fmt.Fprint(w,
 		d.common.Zone.Mark(i.ID(),
-			styles.Base.Render(s.String()),
+			styles.Base.Render(lipgloss.JoinHorizontal(lipgloss.Top, avatar, " ", s.String())),
 		),
 	)
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/soft-serve/pkg/access"
 	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/ui/common"
 	"github.com/charmbracelet/soft-serve/pkg/ui/components/code"
 	"github.com/charmbracelet/soft-serve/pkg/ui/components/selector"
@@ -20,11 +21,32 @@ const (
 	defaultNoContent = "No readme found.\n\nCreate a `.soft-serve` repository and add a `README.md` file to display readme."
 )
 
+var toggleRepoFilter = key.NewBinding(
+	key.WithKeys("F"),
+	key.WithHelp("F", "show all"),
+)
+
+var toggleArchived = key.NewBinding(
+	key.WithKeys("A"),
+	key.WithHelp("A", "hide archived"),
+)
+
+// archivedView is whether the archived repositories are listed, left out, or
+// the only ones listed.
+type archivedView int
+
+const (
+	showArchived archivedView = iota
+	hideArchived
+	onlyArchived
+)
+
 type pane int
 
 const (
 	selectorPane pane = iota
 	readmePane
+	activityPane
 	lastPane
 )
 
@@ -32,9 +54,18 @@ func (p pane) String() string {
 	return []string{
 		"Repositories",
 		"About",
+		"Activity",
 	}[p]
 }
 
+// StatusMsg is a message that shows a notification in place of the stats
+// until the next key press.
+type StatusMsg string
+
+// AvatarsMsg is a message that contains the rendered avatars of repositories
+// keyed by name.
+type AvatarsMsg map[string]string
+
 // Selection is the model for the selection screen/page.
 type Selection struct {
 	common     common.Common
@@ -42,12 +73,53 @@ type Selection struct {
 	selector   *selector.Selector
 	activePane pane
 	tabs       *tabs.Tabs
+
+	// activity is the feed of recent pushes. It's refreshed periodically
+	// while it's shown.
+	activity        *selector.Selector
+	activityTicking bool
+
+	// stats is the footer of the repositories pane. It's refreshed when a
+	// new push shows up in the activity feed.
+	stats    *StatsMsg
+	lastPush string
+
+	// admin is true if the user can apply bulk actions to the marked
+	// repositories. status is the result of the last bulk action, or a
+	// notification, shown in place of the stats until the next key press.
+	admin  bool
+	bulk   *bulkMenu
+	status string
+
+	// items are all the repositories the user can access. repoFilter is the
+	// default filter of the list saved by the user, it's loaded once per
+	// session. showAll is true while the user chose to see every repository.
+	items            Items
+	repoFilter       common.RepoFilter
+	repoFilterLoaded bool
+	showAll          bool
+
+	// avatars holds the rendered avatars of the repositories keyed by name.
+	// Rendering them is expensive, so it's only done for the repositories
+	// shown, and the ones being rendered are empty.
+	avatars map[string]string
+
+	// archived is whether the archived repositories are listed, cycled with
+	// the archived key. The key only shows when there's any.
+	archived    archivedView
+	hasArchived bool
+
+	// welcome is the welcome message shown above the repository list, if
+	// any. dismissedWelcome is the version of it the user dismissed in this
+	// session.
+	welcome          *welcome
+	dismissedWelcome string
 }
 
 // New creates a new selection model.
 func New(c common.Common) *Selection {
 	ts := make([]string, lastPane)
-	for i, b := range []pane{selectorPane, readmePane} {
+	for i, b := range []pane{selectorPane, readmePane, activityPane} {
 		ts[i] = b.String()
 	}
 	t := tabs.New(c, ts)
@@ -60,20 +132,34 @@ func New(c common.Common) *Selection {
 		common:     c,
 		activePane: selectorPane, // start with the selector focused
 		tabs:       t,
+		avatars:    make(map[string]string),
 	}
 	readme := code.New(c, "", "")
 	readme.UseGlamour = true
 	readme.NoContentStyle = c.Styles.NoContent.
 		SetString(defaultNoContent)
+	activity := selector.New(c,
+		[]selector.IdentifiableItem{},
+		PushItemDelegate{&c})
+	activity.SetShowTitle(false)
+	activity.SetShowHelp(false)
+	activity.SetShowStatusBar(false)
+	activity.SetShowFilter(false)
+	activity.SetFilteringEnabled(false)
+	activity.DisableQuitKeybindings()
+	delegate := NewItemDelegate(&c, &sel.activePane)
 	selector := selector.New(c,
 		[]selector.IdentifiableItem{},
-		NewItemDelegate(&c, &sel.activePane))
+		delegate)
+	delegate.isMarked = selector.IsMarked
+	delegate.avatar = sel.avatar
 	selector.SetShowTitle(false)
 	selector.SetShowHelp(false)
 	selector.SetShowStatusBar(false)
 	selector.DisableQuitKeybindings()
 	sel.selector = selector
 	sel.readme = readme
+	sel.activity = activity
 	return sel
 }
 
@@ -93,12 +179,20 @@ func (s *Selection) FilterState() list.FilterState {
 	return s.selector.FilterState()
 }
 
+// ShowsRelativeTimes implements common.RelativeTimes. Both the repositories
+// and the activity feed show when they were updated.
+func (s *Selection) ShowsRelativeTimes() bool {
+	return s.activePane != readmePane
+}
+
 // SetSize implements common.Component.
 func (s *Selection) SetSize(width, height int) {
 	s.common.SetSize(width, height)
 	wm, hm := s.getMargins()
 	s.tabs.SetSize(width, height-hm)
-	s.selector.SetSize(width-wm, height-hm)
+	// -1 for stats footer
+	s.selector.SetSize(width-wm, height-hm-1-s.welcomeHeight(width-wm))
+	s.activity.SetSize(width-wm, height-hm)
 	s.readme.SetSize(width-wm, height-hm-1) // -1 for readme status line
 }
 
@@ -109,12 +203,20 @@ func (s *Selection) IsFiltering() bool {
 
 // ShortHelp implements help.KeyMap.
 func (s *Selection) ShortHelp() []key.Binding {
+	if s.bulk != nil && s.activePane == selectorPane {
+		return s.bulkHelp()
+	}
 	k := s.selector.KeyMap
 	kb := make([]key.Binding, 0)
 	kb = append(kb,
 		s.common.KeyMap.UpDown,
 		s.common.KeyMap.Section,
 	)
+	if s.activePane == activityPane {
+		open := s.common.KeyMap.Select
+		open.SetHelp("enter", "open")
+		kb = append(kb, open)
+	}
 	if s.activePane == selectorPane {
 		copyKey := s.common.KeyMap.Copy
 		copyKey.SetHelp("c", "copy command")
@@ -124,12 +226,30 @@ func (s *Selection) ShortHelp() []key.Binding {
 			k.ClearFilter,
 			copyKey,
 		)
+		if !s.repoFilter.IsZero() {
+			kb = append(kb, s.repoFilterKey())
+		}
+		if s.hasArchived {
+			kb = append(kb, s.archivedKey())
+		}
+		if s.welcome != nil {
+			kb = append(kb, dismissWelcome)
+		}
+		if s.admin {
+			kb = append(kb, markRepo)
+			if len(s.selector.MarkedItems()) > 0 {
+				kb = append(kb, bulkActions)
+			}
+		}
 	}
 	return kb
 }
 
 // FullHelp implements help.KeyMap.
 func (s *Selection) FullHelp() [][]key.Binding {
+	if s.bulk != nil && s.activePane == selectorPane {
+		return [][]key.Binding{s.bulkHelp()}
+	}
 	b := [][]key.Binding{
 		{
 			s.common.KeyMap.Section,
@@ -150,6 +270,21 @@ func (s *Selection) FullHelp() [][]key.Binding {
 			k.Down,
 			k.Up,
 		})
+	case activityPane:
+		open := s.common.KeyMap.Select
+		open.SetHelp("enter", "open")
+		k := s.activity.KeyMap
+		b[0] = append(b[0], open)
+		b = append(b, []key.Binding{
+			k.CursorUp,
+			k.CursorDown,
+		})
+		b = append(b, []key.Binding{
+			k.NextPage,
+			k.PrevPage,
+			k.GoToStart,
+			k.GoToEnd,
+		})
 	case selectorPane:
 		copyKey := s.common.KeyMap.Copy
 		copyKey.SetHelp("c", "copy command")
@@ -159,6 +294,18 @@ func (s *Selection) FullHelp() [][]key.Binding {
 				s.common.KeyMap.Select,
 				copyKey,
 			)
+			if s.admin {
+				b[0] = append(b[0], markRepo, bulkActions)
+			}
+			if !s.repoFilter.IsZero() {
+				b[0] = append(b[0], s.repoFilterKey())
+			}
+			if s.hasArchived {
+				b[0] = append(b[0], s.archivedKey())
+			}
+			if s.welcome != nil {
+				b[0] = append(b[0], dismissWelcome)
+			}
 		}
 		b = append(b, []key.Binding{
 			k.CursorUp,
@@ -199,7 +346,22 @@ func (s *Selection) Init() tea.Cmd {
 	if err != nil {
 		return common.ErrorCmd(err)
 	}
+	s.admin = s.canBulkEdit()
+	if !s.repoFilterLoaded {
+		s.repoFilterLoaded = true
+		s.repoFilter = s.loadRepoFilter()
+	}
+	// The repositories of the teams of the user come first.
+	teams := make(map[string][]string)
+	tas, err := be.UserTeamRepos(ctx, s.common.User())
+	if err != nil {
+		s.common.Logger.Debugf("ui: failed to get team repositories: %v", err)
+	}
+	for _, ta := range tas {
+		teams[ta.Repo] = append(teams[ta.Repo], ta.Team)
+	}
 	sortedItems := make(Items, 0)
+	s.hasArchived = false
 	for _, r := range repos {
 		if r.Name() == ".soft-serve" {
 			readme, path, err := backend.Readme(r, nil)
@@ -220,18 +382,23 @@ func (s *Selection) Init() tea.Cmd {
 				s.common.Logger.Debugf("ui: failed to create item for %s: %v", r.Name(), err)
 				continue
 			}
+			item.teams = teams[r.Name()]
+			s.hasArchived = s.hasArchived || r.IsArchived()
 			sortedItems = append(sortedItems, item)
 		}
 	}
 	sort.Sort(sortedItems)
-	items := make([]selector.IdentifiableItem, len(sortedItems))
-	for i, it := range sortedItems {
-		items[i] = it
-	}
+	s.items = sortedItems
+	s.avatars = make(map[string]string)
+	s.loadWelcome(repos)
+	s.SetSize(s.common.Width, s.common.Height)
 	return tea.Batch(
 		s.selector.Init(),
-		s.selector.SetItems(items),
+		s.setItems(),
+		s.avatarsCmd(s.selector.WindowItems()),
 		readmeCmd,
+		s.activityCmd,
+		s.statsCmd,
 	)
 }
 
@@ -239,6 +406,10 @@ func (s *Selection) Init() tea.Cmd {
 func (s *Selection) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	cmds := make([]tea.Cmd, 0)
 	switch msg := msg.(type) {
+	case code.RenderedMsg:
+		r, cmd := s.readme.Update(msg)
+		s.readme = r.(*code.Code)
+		return s, cmd
 	case tea.WindowSizeMsg:
 		r, cmd := s.readme.Update(msg)
 		s.readme = r.(*code.Code)
@@ -253,7 +424,32 @@ func (s *Selection) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg, tea.MouseMsg:
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
+			s.status = ""
+			if s.bulk != nil && s.activePane == selectorPane {
+				return s, s.updateBulk(msg)
+			}
+			bulk := s.admin && s.activePane == selectorPane && !s.IsFiltering()
 			switch {
+			case bulk && key.Matches(msg, markRepo):
+				if it := s.selector.SelectedItem(); it != nil {
+					s.selector.ToggleMarked(it.ID())
+				}
+				return s, nil
+			case bulk && key.Matches(msg, bulkActions):
+				s.openBulkMenu()
+				return s, nil
+			case s.activePane == selectorPane && !s.IsFiltering() &&
+				!s.repoFilter.IsZero() && key.Matches(msg, toggleRepoFilter):
+				s.showAll = !s.showAll
+				return s, s.setItems()
+			case s.activePane == selectorPane && !s.IsFiltering() &&
+				s.welcome != nil && key.Matches(msg, dismissWelcome):
+				s.dismissWelcome()
+				return s, nil
+			case s.activePane == selectorPane && !s.IsFiltering() &&
+				s.hasArchived && key.Matches(msg, toggleArchived):
+				s.archived = (s.archived + 1) % (onlyArchived + 1)
+				return s, s.setItems()
 			case key.Matches(msg, s.common.KeyMap.Back):
 				cmds = append(cmds, s.selector.Init())
 			}
@@ -265,6 +461,39 @@ func (s *Selection) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case tabs.ActiveTabMsg:
 		s.activePane = pane(msg)
+		if s.activePane == activityPane && !s.activityTicking {
+			s.activityTicking = true
+			cmds = append(cmds, s.activityCmd, activityTickCmd())
+		}
+	case activityTickMsg:
+		// Keep refreshing the feed only while it's shown.
+		s.activityTicking = s.activePane == activityPane
+		if s.activityTicking {
+			cmds = append(cmds, s.activityCmd, activityTickCmd())
+		}
+	case ActivityMsg:
+		cmds = append(cmds, s.setActivity(msg))
+		if len(msg) > 0 {
+			last := PushItem{msg[0]}.ID()
+			if s.lastPush != "" && last != s.lastPush {
+				cmds = append(cmds, s.statsCmd)
+			}
+			s.lastPush = last
+		}
+	case selector.WindowMsg:
+		cmds = append(cmds, s.avatarsCmd(msg.Items))
+	case AvatarsMsg:
+		for name, avatar := range msg {
+			s.avatars[name] = avatar
+		}
+	case StatsMsg:
+		s.stats = &msg
+	case StatusMsg:
+		s.status = string(msg)
+	case BulkMsg:
+		s.status = msg.String()
+		s.selector.ClearMarked()
+		cmds = append(cmds, s.Init())
 	}
 	switch s.activePane {
 	case readmePane:
@@ -279,20 +508,191 @@ func (s *Selection) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if cmd != nil {
 			cmds = append(cmds, cmd)
 		}
+	case activityPane:
+		m, cmd := s.activity.Update(msg)
+		s.activity = m.(*selector.Selector)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
 	}
 	return s, tea.Batch(cmds...)
 }
 
+// loadRepoFilter returns the default filter of the repository list saved by
+// the user. It returns the zero filter if there is none.
+func (s *Selection) loadRepoFilter() common.RepoFilter {
+	be := s.common.Backend()
+	pk := s.common.PublicKey()
+	if be == nil || pk == nil {
+		return common.RepoFilter{}
+	}
+	spec, err := be.Preference(s.common.Context(), pk, common.RepoFilterPreference)
+	if err != nil {
+		s.common.Logger.Debugf("ui: failed to load repository filter: %v", err)
+		return common.RepoFilter{}
+	}
+	if spec == "" {
+		return common.RepoFilter{}
+	}
+	f, err := common.ParseRepoFilter(spec)
+	if err != nil {
+		s.common.Logger.Debugf("ui: invalid repository filter %q: %v", spec, err)
+		return common.RepoFilter{}
+	}
+	return f
+}
+
+// isRepoFiltered returns true if the default filter of the user hides
+// repositories from the list.
+func (s *Selection) isRepoFiltered() bool {
+	return !s.repoFilter.IsZero() && !s.showAll
+}
+
+// repoFilterKey returns the binding that toggles the default filter.
+func (s *Selection) repoFilterKey() key.Binding {
+	k := toggleRepoFilter
+	if s.showAll {
+		k.SetHelp("F", "apply filter")
+	}
+	return k
+}
+
+// archivedKey returns the binding that cycles through listing the archived
+// repositories, leaving them out, and only listing them.
+func (s *Selection) archivedKey() key.Binding {
+	k := toggleArchived
+	switch s.archived {
+	case hideArchived:
+		k.SetHelp("A", "only archived")
+	case onlyArchived:
+		k.SetHelp("A", "show archived")
+	}
+	return k
+}
+
+// setItems sets the repositories of the list that pass the default filter of
+// the user, unless they chose to see every repository, and the archived
+// repositories the user chose to see.
+func (s *Selection) setItems() tea.Cmd {
+	items := make([]selector.IdentifiableItem, 0, len(s.items))
+	for _, it := range s.items {
+		if s.isRepoFiltered() && !s.repoFilter.Match(it.repo) {
+			continue
+		}
+		if (s.archived == hideArchived && it.repo.IsArchived()) ||
+			(s.archived == onlyArchived && !it.repo.IsArchived()) {
+			continue
+		}
+		items = append(items, it)
+	}
+	return s.selector.SetItems(items)
+}
+
+// avatar returns the rendered avatar of the repository with the given name,
+// empty if it isn't rendered yet.
+func (s *Selection) avatar(name string) string {
+	return s.avatars[name]
+}
+
+// avatarsCmd returns a command that renders the avatars of the given
+// repositories. Avatars are only rendered once.
+func (s *Selection) avatarsCmd(items []selector.IdentifiableItem) tea.Cmd {
+	repos := make([]proto.Repository, 0, len(items))
+	for _, it := range items {
+		i, ok := it.(Item)
+		if !ok {
+			continue
+		}
+		if _, ok := s.avatars[i.ID()]; ok {
+			continue
+		}
+		s.avatars[i.ID()] = ""
+		repos = append(repos, i.repo)
+	}
+	if len(repos) == 0 {
+		return nil
+	}
+	c := s.common
+	return func() tea.Msg {
+		avatars := make(AvatarsMsg, len(repos))
+		for _, r := range repos {
+			avatars[r.Name()] = c.Avatar(r, avatarSize)
+		}
+		return avatars
+	}
+}
+
+// setActivity sets the activity feed items and keeps the selected push
+// selected.
+func (s *Selection) setActivity(events ActivityMsg) tea.Cmd {
+	var selected string
+	if it := s.activity.SelectedItem(); it != nil {
+		selected = it.ID()
+	}
+
+	idx := 0
+	items := make([]selector.IdentifiableItem, len(events))
+	for i, ev := range events {
+		items[i] = PushItem{ev}
+		if items[i].ID() == selected {
+			idx = i
+		}
+	}
+
+	cmd := s.activity.SetItems(items)
+	s.activity.Select(idx)
+	return cmd
+}
+
 // View implements tea.Model.
 func (s *Selection) View() string {
 	var view string
 	wm, hm := s.getMargins()
 	switch s.activePane {
 	case selectorPane:
+		ss := s.common.Renderer.NewStyle().
+			Width(s.common.Width - wm).
+			Height(s.common.Height - hm - 1)
+		var stats string
+		if s.stats != nil {
+			stats = s.stats.String()
+		}
+		if s.isRepoFiltered() {
+			stats = fmt.Sprintf("Filtered by %s · %s", s.repoFilter, stats)
+		}
+		switch s.archived {
+		case hideArchived:
+			stats = "Archived hidden · " + stats
+		case onlyArchived:
+			stats = "Only archived · " + stats
+		}
+		if s.status != "" {
+			stats = s.status
+		}
+		footer := s.common.Renderer.NewStyle().
+			Width(s.common.Width - wm).
+			Foreground(s.common.Styles.InactiveBorderColor).
+			Render(common.TruncateString(stats, s.common.Width-wm))
+		content := s.selector.View()
+		if s.bulk != nil {
+			content = s.renderBulk()
+		}
+		if s.welcome != nil {
+			content = lipgloss.JoinVertical(lipgloss.Left, s.renderWelcome(s.common.Width-wm), content)
+		}
+		view = lipgloss.JoinVertical(lipgloss.Left,
+			ss.Render(content),
+			footer,
+		)
+	case activityPane:
 		ss := s.common.Renderer.NewStyle().
 			Width(s.common.Width - wm).
 			Height(s.common.Height - hm)
-		view = ss.Render(s.selector.View())
+		if len(s.activity.Items()) == 0 {
+			view = ss.Render(s.common.Styles.NoContent.Render("No recent activity."))
+		} else {
+			view = ss.Render(s.activity.View())
+		}
 	case readmePane:
 		rs := s.common.Renderer.NewStyle().
 			Height(s.common.Height - hm)
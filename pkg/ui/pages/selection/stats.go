@@ -0,0 +1,83 @@
+package selection
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/dustin/go-humanize"
+)
+
+// StatsMsg is a message that contains the repository stats shown in the
+// selection page footer.
+type StatsMsg struct {
+	// Total is the number of repositories the user can access.
+	Total int
+	// Private is the number of private repositories the user can access.
+	Private int
+	// DiskUsage is the total disk usage in bytes. It's only set for admins.
+	DiskUsage int64
+	// ShowDiskUsage is true if the disk usage is shown.
+	ShowDiskUsage bool
+}
+
+// String returns the footer line of the stats.
+func (m StatsMsg) String() string {
+	noun := "repositories"
+	if m.Total == 1 {
+		noun = "repository"
+	}
+	parts := []string{
+		fmt.Sprintf("%d %s", m.Total, noun),
+		fmt.Sprintf("%d public", m.Total-m.Private),
+		fmt.Sprintf("%d private", m.Private),
+	}
+	if m.ShowDiskUsage {
+		parts = append(parts, humanize.IBytes(uint64(m.DiskUsage))+" on disk")
+	}
+	return strings.Join(parts, " · ")
+}
+
+// statsCmd counts the repositories the user can access. Admins also get the
+// total disk usage.
+func (s *Selection) statsCmd() tea.Msg {
+	be := s.common.Backend()
+	if be == nil {
+		return nil
+	}
+
+	ctx := s.common.Context()
+	pk := s.common.PublicKey()
+	repos, err := be.Repositories(ctx)
+	if err != nil {
+		s.common.Logger.Debugf("ui: failed to load repository stats: %v", err)
+		return nil
+	}
+
+	var msg StatsMsg
+	for _, r := range repos {
+		if r.IsHidden() {
+			continue
+		}
+		if be.AccessLevelByPublicKey(ctx, r.Name(), pk) < access.ReadOnlyAccess {
+			continue
+		}
+		msg.Total++
+		if r.IsPrivate() {
+			msg.Private++
+		}
+	}
+
+	if be.AccessLevelByPublicKey(ctx, "", pk) >= access.AdminAccess {
+		size, err := be.DiskUsage(ctx)
+		if err != nil {
+			s.common.Logger.Debugf("ui: failed to compute disk usage: %v", err)
+		} else {
+			msg.DiskUsage = size
+			msg.ShowDiskUsage = true
+		}
+	}
+
+	return msg
+}
@@ -0,0 +1,165 @@
+package selection
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/ui/common"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// WelcomePreference is the name of the preference that holds the version of
+// the welcome message the user dismissed. The message shows again once its
+// README changes.
+const WelcomePreference = "ui.welcome"
+
+var dismissWelcome = key.NewBinding(
+	key.WithKeys("x"),
+	key.WithHelp("x", "dismiss welcome"),
+)
+
+// welcome is the welcome message of the server, the README of the welcome
+// repository shown above the repository list. id is the version of the
+// README, rendered is the message rendered at width.
+type welcome struct {
+	readme   string
+	id       string
+	rendered string
+	width    int
+}
+
+// loadWelcome loads the README of the welcome repository unless the user
+// can't read it, or dismissed this version of it.
+func (s *Selection) loadWelcome(repos []proto.Repository) {
+	s.welcome = nil
+	cfg := s.common.Config()
+	if cfg == nil || cfg.UI.Welcome.Repo == "" {
+		return
+	}
+
+	name := utils.SanitizeRepo(cfg.UI.Welcome.Repo)
+	ctx := s.common.Context()
+	be := s.common.Backend()
+	for _, r := range repos {
+		if r.Name() != name {
+			continue
+		}
+		if be.AccessLevelByPublicKey(ctx, name, s.common.PublicKey()) < access.ReadOnlyAccess {
+			return
+		}
+		readme, _, err := backend.Readme(r, nil)
+		if err != nil || strings.TrimSpace(readme) == "" {
+			return
+		}
+		sum := sha256.Sum256([]byte(readme))
+		id := hex.EncodeToString(sum[:8])
+		if id == s.welcomeDismissed() {
+			return
+		}
+		s.welcome = &welcome{readme: readme, id: id}
+		return
+	}
+}
+
+// welcomeDismissed returns the version of the welcome message the user
+// dismissed, if any. Keyless users dismiss it until they disconnect.
+func (s *Selection) welcomeDismissed() string {
+	if s.dismissedWelcome != "" {
+		return s.dismissedWelcome
+	}
+	be := s.common.Backend()
+	pk := s.common.PublicKey()
+	if be == nil || pk == nil {
+		return ""
+	}
+	v, err := be.Preference(s.common.Context(), pk, WelcomePreference)
+	if err != nil {
+		s.common.Logger.Debugf("ui: failed to load welcome preference: %v", err)
+	}
+	return v
+}
+
+// dismissWelcome hides the welcome message until its README changes.
+func (s *Selection) dismissWelcome() {
+	s.dismissedWelcome = s.welcome.id
+	s.welcome = nil
+	s.SetSize(s.common.Width, s.common.Height)
+	be := s.common.Backend()
+	pk := s.common.PublicKey()
+	if be == nil || pk == nil {
+		return
+	}
+	if err := be.SetPreference(s.common.Context(), pk, WelcomePreference, s.dismissedWelcome); err != nil {
+		s.common.Logger.Debugf("ui: failed to save welcome preference: %v", err)
+	}
+}
+
+// welcomeHeight returns the height of the welcome message, 0 when there is
+// none.
+func (s *Selection) welcomeHeight(width int) int {
+	if s.welcome == nil {
+		return 0
+	}
+	return lipgloss.Height(s.renderWelcome(width))
+}
+
+// renderWelcome renders the welcome message in a box as wide as width, cut
+// to the configured height. It's rendered again when the width changes.
+func (s *Selection) renderWelcome(width int) string {
+	w := s.welcome
+	if w.rendered != "" && w.width == width {
+		return w.rendered
+	}
+
+	height := 0
+	if cfg := s.common.Config(); cfg != nil {
+		height = cfg.UI.Welcome.Height
+	}
+	box := s.common.Renderer.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(s.common.Styles.InactiveBorderColor).
+		Padding(0, 1)
+	inner := max(width-box.GetHorizontalFrameSize(), 1)
+
+	text := w.readme
+	wrap := min(max(inner, 20), 120)
+	tr, err := glamour.NewTermRenderer(
+		glamour.WithStyles(common.StyleConfig()),
+		glamour.WithWordWrap(wrap),
+	)
+	if err == nil {
+		if md, err := tr.Render(common.PrepareMarkdown(w.readme, wrap)); err == nil {
+			text = md
+		}
+	}
+
+	lines := strings.Split(strings.Trim(text, "\n"), "\n")
+	// Glamour pads the document with blank lines.
+	for len(lines) > 0 && strings.TrimSpace(ansi.Strip(lines[0])) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(ansi.Strip(lines[len(lines)-1])) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if height > 0 && len(lines) > height {
+		lines = lines[:height]
+		lines[height-1] = s.common.Renderer.NewStyle().
+			Foreground(s.common.Styles.InactiveBorderColor).
+			Render("…")
+	}
+	for i, l := range lines {
+		lines[i] = common.TruncateString(strings.TrimRight(l, " "), inner)
+	}
+
+	w.rendered = box.Width(width - box.GetHorizontalBorderSize()).Render(strings.Join(lines, "\n"))
+	w.width = width
+	return w.rendered
+}
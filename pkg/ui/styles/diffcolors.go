@@ -0,0 +1,93 @@
+package styles
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DiffColors is a color scheme of the added and deleted lines of diffs and of
+// their stats.
+type DiffColors string
+
+const (
+	// DiffColorsClassic shows additions in green and deletions in red.
+	DiffColorsClassic DiffColors = "classic"
+	// DiffColorsColorblind shows additions in blue and deletions in orange,
+	// colors that are told apart with the common color vision deficiencies.
+	DiffColorsColorblind DiffColors = "colorblind"
+	// DiffColorsMonochrome doesn't use colors, additions are bold and
+	// deletions italic.
+	DiffColorsMonochrome DiffColors = "monochrome"
+)
+
+// DiffColorSchemes are the diff color schemes in the order they're cycled
+// through.
+var DiffColorSchemes = []DiffColors{
+	DiffColorsClassic,
+	DiffColorsColorblind,
+	DiffColorsMonochrome,
+}
+
+// ParseDiffColors returns the diff color scheme of the given name. It returns
+// false if there's no such scheme.
+func ParseDiffColors(name string) (DiffColors, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, c := range DiffColorSchemes {
+		if string(c) == name {
+			return c, true
+		}
+	}
+	return DiffColorsClassic, false
+}
+
+// Next returns the diff color scheme after this one.
+func (c DiffColors) Next() DiffColors {
+	for i, s := range DiffColorSchemes {
+		if s == c {
+			return DiffColorSchemes[(i+1)%len(DiffColorSchemes)]
+		}
+	}
+	return DiffColorsClassic
+}
+
+// SetDiffColors sets the styles of the added and deleted lines of diffs, of
+// the stats, and of the status of changed files to the given color scheme.
+// The other styles are left alone. Unknown schemes are the classic one.
+func (s *Styles) SetDiffColors(c DiffColors) {
+	r := s.renderer
+	if r == nil {
+		r = lipgloss.DefaultRenderer()
+	}
+
+	add, del := r.NewStyle(), r.NewStyle()
+	var addChange, delChange lipgloss.Style
+	switch c {
+	case DiffColorsColorblind:
+		add = add.Foreground(lipgloss.Color("33"))
+		del = del.Foreground(lipgloss.Color("208"))
+		addChange = add.Background(lipgloss.Color("17")).Bold(true)
+		delChange = del.Background(lipgloss.Color("94")).Bold(true)
+	case DiffColorsMonochrome:
+		add = add.Bold(true)
+		del = del.Italic(true)
+		addChange = add.Underline(true)
+		delChange = del.Underline(true)
+	default:
+		c = DiffColorsClassic
+		add = add.Foreground(lipgloss.Color("42"))
+		del = del.Foreground(lipgloss.Color("203"))
+		addChange = add.Background(lipgloss.Color("22")).Bold(true)
+		delChange = del.Background(lipgloss.Color("52")).Bold(true)
+	}
+
+	s.DiffColors = c
+	s.Log.SplitAdd = add
+	s.Log.SplitDel = del
+	s.Log.SplitAddChange = addChange
+	s.Log.SplitDelChange = delChange
+	s.Log.CommitStatsAdd = add.Bold(true)
+	s.Log.CommitStatsDel = del.Bold(true)
+	s.Tree.Change.Added = add.Bold(true)
+	s.Tree.Change.Deleted = del.Bold(true)
+}
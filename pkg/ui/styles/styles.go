@@ -9,11 +9,18 @@ import (
 
 // Styles defines styles for the UI.
 type Styles struct {
+	renderer *lipgloss.Renderer
+
+	// DiffColors is the color scheme of the diffs, see SetDiffColors.
+	DiffColors DiffColors
+
 	ActiveBorderColor   lipgloss.Color
 	InactiveBorderColor lipgloss.Color
 
 	App                  lipgloss.Style
 	ServerName           lipgloss.Style
+	ServerUser           lipgloss.Style
+	Maintenance          lipgloss.Style
 	TopLevelNormalTab    lipgloss.Style
 	TopLevelActiveTab    lipgloss.Style
 	TopLevelActiveTabDot lipgloss.Style
@@ -28,6 +35,7 @@ type Styles struct {
 			Desc    lipgloss.Style
 			Command lipgloss.Style
 			Updated lipgloss.Style
+			Warning lipgloss.Style
 		}
 		Active struct {
 			Base    lipgloss.Style
@@ -35,6 +43,7 @@ type Styles struct {
 			Desc    lipgloss.Style
 			Command lipgloss.Style
 			Updated lipgloss.Style
+			Warning lipgloss.Style
 		}
 	}
 
@@ -46,6 +55,9 @@ type Styles struct {
 		Header     lipgloss.Style
 		HeaderName lipgloss.Style
 		HeaderDesc lipgloss.Style
+		Languages  lipgloss.Style
+		Language   lipgloss.Style
+		Truncated  lipgloss.Style
 	}
 
 	Footer      lipgloss.Style
@@ -82,29 +94,64 @@ type Styles struct {
 		CommitAuthor   lipgloss.Style
 		CommitDate     lipgloss.Style
 		CommitBody     lipgloss.Style
+		CommitTrailers lipgloss.Style
+		TrailerKey     lipgloss.Style
+		CommitRef      lipgloss.Style
+		IssueRef       lipgloss.Style
+		FileRef        lipgloss.Style
 		CommitStatsAdd lipgloss.Style
 		CommitStatsDel lipgloss.Style
 		Paginator      lipgloss.Style
+
+		// The side-by-side diff. The colors of the added and deleted lines, and
+		// of the stats, come from the diff color scheme.
+		SplitHeader    lipgloss.Style
+		SplitHunk      lipgloss.Style
+		SplitLineNo    lipgloss.Style
+		SplitDivider   lipgloss.Style
+		SplitAdd       lipgloss.Style
+		SplitDel       lipgloss.Style
+		SplitAddChange lipgloss.Style
+		SplitDelChange lipgloss.Style
+
+		// The diffs of merge commits.
+		MergeDiff lipgloss.Style
+		MergeEdit lipgloss.Style
+	}
+
+	CommitStatus struct {
+		Pending lipgloss.Style
+		Success lipgloss.Style
+		Failure lipgloss.Style
+	}
+
+	CommitType struct {
+		Badge lipgloss.Style
+		Scope lipgloss.Style
+		Types map[string]lipgloss.Style
 	}
 
 	Ref struct {
 		Normal struct {
-			Base     lipgloss.Style
-			Item     lipgloss.Style
-			ItemTag  lipgloss.Style
-			ItemDesc lipgloss.Style
-			ItemHash lipgloss.Style
+			Base      lipgloss.Style
+			Item      lipgloss.Style
+			ItemTag   lipgloss.Style
+			ItemGroup lipgloss.Style
+			ItemDesc  lipgloss.Style
+			ItemHash  lipgloss.Style
 		}
 		Active struct {
-			Base     lipgloss.Style
-			Item     lipgloss.Style
-			ItemTag  lipgloss.Style
-			ItemDesc lipgloss.Style
-			ItemHash lipgloss.Style
+			Base      lipgloss.Style
+			Item      lipgloss.Style
+			ItemTag   lipgloss.Style
+			ItemGroup lipgloss.Style
+			ItemDesc  lipgloss.Style
+			ItemHash  lipgloss.Style
 		}
-		ItemSelector lipgloss.Style
-		Paginator    lipgloss.Style
-		Selector     lipgloss.Style
+		ItemSelector  lipgloss.Style
+		ItemUnrelated lipgloss.Style
+		Paginator     lipgloss.Style
+		Selector      lipgloss.Style
 	}
 
 	Tree struct {
@@ -127,6 +174,20 @@ type Styles struct {
 			Hash    lipgloss.Style
 			Message lipgloss.Style
 			Who     lipgloss.Style
+			// Heat holds the colors of the heatmap from the most recent to
+			// the oldest changes.
+			Heat []lipgloss.Style
+		}
+		// Change styles the status of the files that changed since a
+		// compare target.
+		Change struct {
+			Added    lipgloss.Style
+			Modified lipgloss.Style
+			Deleted  lipgloss.Style
+			Renamed  lipgloss.Style
+			// Ignored styles the mark of the changed files that match
+			// the .gitignore files.
+			Ignored lipgloss.Style
 		}
 	}
 
@@ -141,6 +202,15 @@ type Styles struct {
 		Selector lipgloss.Style
 	}
 
+	Insights struct {
+		Base        lipgloss.Style
+		Title       lipgloss.Style
+		Spark       lipgloss.Style
+		Label       lipgloss.Style
+		Value       lipgloss.Style
+		Placeholder lipgloss.Style
+	}
+
 	Spinner          lipgloss.Style
 	SpinnerContainer lipgloss.Style
 
@@ -161,6 +231,12 @@ type Styles struct {
 	Code struct {
 		LineDigit lipgloss.Style
 		LineBar   lipgloss.Style
+		Selection lipgloss.Style
+
+		ConflictMarker lipgloss.Style
+		ConflictOurs   lipgloss.Style
+		ConflictBase   lipgloss.Style
+		ConflictTheirs lipgloss.Style
 	}
 }
 
@@ -172,6 +248,7 @@ func DefaultStyles(r *lipgloss.Renderer) *Styles {
 	hashColor := lipgloss.Color("185")
 
 	s := new(Styles)
+	s.renderer = r
 
 	s.ActiveBorderColor = lipgloss.Color("62")
 	s.InactiveBorderColor = lipgloss.Color("241")
@@ -188,6 +265,16 @@ func DefaultStyles(r *lipgloss.Renderer) *Styles {
 		Foreground(lipgloss.Color("229")).
 		Bold(true)
 
+	s.ServerUser = r.NewStyle().
+		Height(1).
+		MarginLeft(1).
+		Foreground(lipgloss.Color("241"))
+
+	s.Maintenance = r.NewStyle().
+		Height(1).
+		MarginLeft(1).
+		Foreground(lipgloss.Color("214"))
+
 	s.TopLevelNormalTab = r.NewStyle().
 		MarginRight(2)
 
@@ -213,6 +300,9 @@ func DefaultStyles(r *lipgloss.Renderer) *Styles {
 	s.RepoSelector.Normal.Updated = r.NewStyle().
 		Foreground(lipgloss.Color("243"))
 
+	s.RepoSelector.Normal.Warning = r.NewStyle().
+		Foreground(lipgloss.Color("203"))
+
 	s.RepoSelector.Active.Base = s.RepoSelector.Normal.Base.
 		BorderStyle(lipgloss.Border{Left: "┃"}).
 		BorderForeground(lipgloss.Color("176"))
@@ -229,6 +319,9 @@ func DefaultStyles(r *lipgloss.Renderer) *Styles {
 	s.RepoSelector.Active.Command = s.RepoSelector.Normal.Command.
 		Foreground(lipgloss.Color("204"))
 
+	s.RepoSelector.Active.Warning = s.RepoSelector.Normal.Warning.
+		Foreground(lipgloss.Color("209"))
+
 	s.MenuItem = r.NewStyle().
 		PaddingLeft(1).
 		Border(lipgloss.Border{
@@ -263,6 +356,18 @@ func DefaultStyles(r *lipgloss.Renderer) *Styles {
 	s.Repo.HeaderDesc = r.NewStyle().
 		Foreground(lipgloss.Color("243"))
 
+	s.Repo.Languages = r.NewStyle().
+		Padding(0, 1).
+		MarginBottom(1)
+
+	s.Repo.Language = r.NewStyle().
+		Foreground(lipgloss.Color("243"))
+
+	s.Repo.Truncated = r.NewStyle().
+		Foreground(lipgloss.Color("179")).
+		Padding(0, 1).
+		MarginBottom(1)
+
 	s.Footer = r.NewStyle().
 		MarginTop(1).
 		Padding(0, 1).
@@ -344,6 +449,38 @@ func DefaultStyles(r *lipgloss.Renderer) *Styles {
 	s.Log.Commit = r.NewStyle().
 		Margin(0, 2)
 
+	s.CommitStatus.Pending = r.NewStyle().
+		Foreground(lipgloss.Color("214"))
+
+	s.CommitStatus.Success = r.NewStyle().
+		Foreground(lipgloss.Color("42"))
+
+	s.CommitStatus.Failure = r.NewStyle().
+		Foreground(lipgloss.Color("203"))
+
+	s.CommitType.Badge = r.NewStyle().
+		Padding(0, 1).
+		Foreground(lipgloss.Color("0")).
+		Background(lipgloss.Color("245"))
+
+	s.CommitType.Scope = r.NewStyle().
+		Foreground(lipgloss.Color("246")).
+		Italic(true)
+
+	s.CommitType.Types = map[string]lipgloss.Style{
+		"feat":     s.CommitType.Badge.Background(lipgloss.Color("42")),
+		"fix":      s.CommitType.Badge.Background(lipgloss.Color("203")),
+		"docs":     s.CommitType.Badge.Background(lipgloss.Color("75")),
+		"style":    s.CommitType.Badge.Background(lipgloss.Color("219")),
+		"refactor": s.CommitType.Badge.Background(lipgloss.Color("141")),
+		"perf":     s.CommitType.Badge.Background(lipgloss.Color("214")),
+		"test":     s.CommitType.Badge.Background(lipgloss.Color("178")),
+		"build":    s.CommitType.Badge.Background(lipgloss.Color("109")),
+		"ci":       s.CommitType.Badge.Background(lipgloss.Color("109")),
+		"revert":   s.CommitType.Badge.Background(lipgloss.Color("167")),
+		"chore":    s.CommitType.Badge,
+	}
+
 	s.Log.CommitHash = r.NewStyle().
 		Foreground(hashColor).
 		Bold(true)
@@ -352,12 +489,41 @@ func DefaultStyles(r *lipgloss.Renderer) *Styles {
 		MarginTop(1).
 		MarginLeft(2)
 
-	s.Log.CommitStatsAdd = r.NewStyle().
-		Foreground(lipgloss.Color("42")).
+	s.Log.CommitTrailers = r.NewStyle().
+		MarginTop(1).
+		MarginLeft(2)
+
+	s.Log.TrailerKey = r.NewStyle().
+		Foreground(lipgloss.Color("243"))
+
+	s.Log.CommitRef = r.NewStyle().
+		Foreground(hashColor).
+		Underline(true)
+
+	s.Log.IssueRef = r.NewStyle().
+		Foreground(lipgloss.Color("75"))
+
+	s.Log.FileRef = r.NewStyle().
+		Foreground(lipgloss.Color("179")).
+		Underline(true)
+
+	s.Log.SplitHeader = r.NewStyle().
 		Bold(true)
 
-	s.Log.CommitStatsDel = r.NewStyle().
-		Foreground(lipgloss.Color("203")).
+	s.Log.SplitHunk = r.NewStyle().
+		Foreground(lipgloss.Color("39"))
+
+	s.Log.SplitLineNo = r.NewStyle().
+		Foreground(lipgloss.Color("240"))
+
+	s.Log.SplitDivider = r.NewStyle().
+		Foreground(lipgloss.Color("236"))
+
+	s.Log.MergeDiff = r.NewStyle().
+		Foreground(lipgloss.Color("39"))
+
+	s.Log.MergeEdit = r.NewStyle().
+		Foreground(lipgloss.Color("214")).
 		Bold(true)
 
 	s.Log.Paginator = r.NewStyle().
@@ -388,6 +554,13 @@ func DefaultStyles(r *lipgloss.Renderer) *Styles {
 		Bold(true).
 		Foreground(highlightColor)
 
+	s.Ref.Normal.ItemGroup = r.NewStyle().
+		Bold(true)
+
+	s.Ref.Active.ItemGroup = r.NewStyle().
+		Bold(true).
+		Foreground(highlightColor)
+
 	s.Ref.Normal.ItemDesc = r.NewStyle().
 		Faint(true)
 
@@ -403,6 +576,9 @@ func DefaultStyles(r *lipgloss.Renderer) *Styles {
 		Foreground(highlightColor).
 		Bold(true)
 
+	s.Ref.ItemUnrelated = r.NewStyle().
+		Foreground(lipgloss.Color("214"))
+
 	s.Ref.Paginator = s.Log.Paginator
 
 	s.Ref.Selector = r.NewStyle()
@@ -450,6 +626,22 @@ func DefaultStyles(r *lipgloss.Renderer) *Styles {
 	s.Tree.Blame.Who = r.NewStyle().
 		Faint(true)
 
+	for _, c := range []string{"196", "202", "208", "214", "150", "109", "67", "61"} {
+		s.Tree.Blame.Heat = append(s.Tree.Blame.Heat, r.NewStyle().
+			Foreground(lipgloss.Color(c)))
+	}
+
+	s.Tree.Change.Modified = r.NewStyle().
+		Foreground(lipgloss.Color("214")).
+		Bold(true)
+
+	s.Tree.Change.Renamed = r.NewStyle().
+		Foreground(lipgloss.Color("39")).
+		Bold(true)
+
+	s.Tree.Change.Ignored = r.NewStyle().
+		Foreground(lipgloss.Color("203"))
+
 	s.Spinner = r.NewStyle().
 		MarginTop(1).
 		MarginLeft(2).
@@ -509,6 +701,21 @@ func DefaultStyles(r *lipgloss.Renderer) *Styles {
 
 	s.Code.LineBar = r.NewStyle().Foreground(lipgloss.Color("236"))
 
+	s.Code.Selection = r.NewStyle().
+		Foreground(lipgloss.Color("255")).
+		Background(lipgloss.Color("237"))
+
+	s.Code.ConflictMarker = r.NewStyle().
+		Foreground(lipgloss.Color("255")).
+		Background(lipgloss.Color("124")).
+		Bold(true)
+
+	s.Code.ConflictOurs = r.NewStyle().Foreground(lipgloss.Color("35"))
+
+	s.Code.ConflictBase = r.NewStyle().Foreground(lipgloss.Color("243"))
+
+	s.Code.ConflictTheirs = r.NewStyle().Foreground(lipgloss.Color("33"))
+
 	s.Stash.Normal.Message = r.NewStyle().MarginLeft(1)
 
 	s.Stash.Active.Message = s.Stash.Normal.Message.Foreground(selectorColor)
@@ -521,5 +728,26 @@ func DefaultStyles(r *lipgloss.Renderer) *Styles {
 		Width(1).
 		Foreground(selectorColor)
 
+	s.Insights.Base = r.NewStyle().
+		Padding(0, 1)
+
+	s.Insights.Title = r.NewStyle().
+		Foreground(lipgloss.Color("212")).
+		Bold(true)
+
+	s.Insights.Spark = r.NewStyle().
+		Foreground(lipgloss.Color("42"))
+
+	s.Insights.Label = r.NewStyle().
+		Foreground(lipgloss.Color("243"))
+
+	s.Insights.Value = r.NewStyle()
+
+	s.Insights.Placeholder = r.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Italic(true)
+
+	s.SetDiffColors(DiffColorsClassic)
+
 	return s
 }
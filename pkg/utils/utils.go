@@ -36,6 +36,49 @@ func ValidateUsername(username string) error {
 	return nil
 }
 
+// ValidateTeam returns an error if the given team name is invalid. Team
+// names follow the rules of usernames.
+func ValidateTeam(team string) error {
+	if team == "" {
+		return fmt.Errorf("team name cannot be empty")
+	}
+
+	if !unicode.IsLetter(rune(team[0])) {
+		return fmt.Errorf("team name must start with a letter")
+	}
+
+	for _, r := range team {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' {
+			return fmt.Errorf("team name can only contain letters, numbers, and hyphens")
+		}
+	}
+
+	return nil
+}
+
+// ValidateName returns an error if the given display name is invalid.
+func ValidateName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+
+	if name != strings.TrimSpace(name) {
+		return fmt.Errorf("name cannot start or end with spaces")
+	}
+
+	if len(name) > 64 {
+		return fmt.Errorf("name cannot be longer than 64 characters")
+	}
+
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ' ' && r != '-' && r != '_' && r != '.' && r != '\'' {
+			return fmt.Errorf("name can only contain letters, numbers, spaces, hyphens, underscores, periods, and apostrophes")
+		}
+	}
+
+	return nil
+}
+
 // ValidateRepo returns an error if the given repository name is invalid.
 func ValidateRepo(repo string) error {
 	if repo == "" {
@@ -1,6 +1,9 @@
 package utils
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestValidateRepo(t *testing.T) {
 	t.Run("valid", func(t *testing.T) {
@@ -35,6 +38,19 @@ func TestValidateRepo(t *testing.T) {
 	})
 }
 
+func TestValidateTeam(t *testing.T) {
+	for _, team := range []string{"backend", "team-a", "t2"} {
+		if err := ValidateTeam(team); err != nil {
+			t.Errorf("ValidateTeam(%q) => %v, want nil error", team, err)
+		}
+	}
+	for _, team := range []string{"", "2team", "team a", "team/a"} {
+		if err := ValidateTeam(team); err == nil {
+			t.Errorf("ValidateTeam(%q) => nil, want an error", team)
+		}
+	}
+}
+
 func TestSanitizeRepo(t *testing.T) {
 	cases := []struct {
 		in, out string
@@ -54,3 +70,39 @@ func TestSanitizeRepo(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateName(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		for _, name := range []string{
+			"lower",
+			"Jane Doe",
+			"O'Brien",
+			"with-dash",
+			"with_underline",
+			"J. R. R.",
+		} {
+			t.Run(name, func(t *testing.T) {
+				if err := ValidateName(name); err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+			})
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, name := range []string{
+			"",
+			"   ",
+			" leading",
+			"trailing ",
+			"with@",
+			"with<html>",
+			strings.Repeat("a", 65),
+		} {
+			t.Run(name, func(t *testing.T) {
+				if err := ValidateName(name); err == nil {
+					t.Error("expected an error, got nil")
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,567 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/soft-serve/git"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/ratelimit"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// apiDefaultPerPage is the number of items of a page when the request
+	// doesn't ask for one.
+	apiDefaultPerPage = 30
+
+	// apiMaxPerPage is the maximum number of items of a page.
+	apiMaxPerPage = 100
+)
+
+// APIController is a router for the read-only JSON API.
+//
+// The API is served under the configured base path and its version, e.g.
+// /api/v1/repos. Users authenticate with an access token, like for the Git
+// routes, and only get the repositories they can read. Anonymous users get
+// the repositories anonymous access allows. The resources of a repository
+// follow a "-", e.g. /api/v1/repos/team/app/-/refs, so that nested names are
+// never mistaken for them.
+func APIController(ctx context.Context, r *mux.Router) {
+	cfg := config.FromContext(ctx)
+	if cfg.HTTP.API.Path == "" {
+		return
+	}
+
+	s := r.PathPrefix(cfg.HTTP.API.Path + "/v1").Subrouter()
+	s.Use(newAPIRateLimitMiddleware(cfg.HTTP.API.RateLimit))
+
+	s.HandleFunc("/repos", serviceAPIRepos).
+		Methods(http.MethodGet, http.MethodHead)
+	s.HandleFunc("/repos/{repo:.+}/-/refs", serviceAPIRefs).
+		Methods(http.MethodGet, http.MethodHead)
+	s.HandleFunc("/repos/{repo:.+}/-/tree", serviceAPITree).
+		Methods(http.MethodGet, http.MethodHead)
+	s.HandleFunc("/repos/{repo:.+}/-/blob", serviceAPIBlob).
+		Methods(http.MethodGet, http.MethodHead)
+	s.HandleFunc("/repos/{repo:.+}/-/commits", serviceAPICommits).
+		Methods(http.MethodGet, http.MethodHead)
+	s.HandleFunc("/repos/{repo:.+}", serviceAPIRepo).
+		Methods(http.MethodGet, http.MethodHead)
+	s.PathPrefix("/").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			renderAPIError(w, http.StatusMethodNotAllowed, "the API is read-only")
+			return
+		}
+		renderAPIError(w, http.StatusNotFound, "not found")
+	})
+}
+
+// newAPIRateLimitMiddleware returns a middleware that limits the number of
+// API requests per minute from a client address.
+func newAPIRateLimitMiddleware(limit int) mux.MiddlewareFunc {
+	limiter := ratelimit.New(limit)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			host, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				host = r.RemoteAddr
+			}
+			if !limiter.Allow(host) {
+				// A request is allowed again once a token is refilled.
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(60/float64(limit)))))
+				renderAPIError(w, http.StatusTooManyRequests, "rate limit exceeded")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// apiRepo is a repository of the API.
+type apiRepo struct {
+	Name          string    `json:"name"`
+	ProjectName   string    `json:"project_name"`
+	Description   string    `json:"description"`
+	Private       bool      `json:"private"`
+	Hidden        bool      `json:"hidden"`
+	Archived      bool      `json:"archived"`
+	DefaultBranch string    `json:"default_branch"`
+	HTTPURL       string    `json:"http_url"`
+	SSHURL        string    `json:"ssh_url"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// apiRef is a reference of a repository.
+type apiRef struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	SHA  string `json:"sha"`
+}
+
+// apiTreeEntry is an entry of a tree, i.e. a file, a directory, or a
+// submodule.
+type apiTreeEntry struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Type string `json:"type"`
+	Mode string `json:"mode"`
+	Size int64  `json:"size"`
+	SHA  string `json:"sha"`
+}
+
+// apiBlob is the metadata of a file.
+type apiBlob struct {
+	apiTreeEntry
+	Binary bool   `json:"binary"`
+	RawURL string `json:"raw_url"`
+}
+
+// apiCommit is a commit of a repository.
+type apiCommit struct {
+	SHA       string       `json:"sha"`
+	Message   string       `json:"message"`
+	Author    apiSignature `json:"author"`
+	Committer apiSignature `json:"committer"`
+	Parents   []string     `json:"parents"`
+}
+
+// apiSignature is the author or committer of a commit.
+type apiSignature struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+func serviceAPIRepos(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	logger := log.FromContext(ctx).WithPrefix("http.api")
+
+	page, perPage, ok := parseAPIPage(w, r)
+	if !ok {
+		return
+	}
+
+	user, ok := apiUser(w, r)
+	if !ok {
+		return
+	}
+
+	repos, err := be.Repositories(ctx)
+	if err != nil {
+		logger.Error("failed to get repositories", "err", err)
+		renderAPIError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	// Hidden repositories are only served by name.
+	readable := make([]proto.Repository, 0, len(repos))
+	for _, repo := range repos {
+		if !repo.IsHidden() && be.AccessLevelForUser(ctx, repo.Name(), user) >= access.ReadOnlyAccess {
+			readable = append(readable, repo)
+		}
+	}
+	sort.Slice(readable, func(i, j int) bool {
+		return readable[i].Name() < readable[j].Name()
+	})
+
+	start, end := apiPageBounds(page, perPage, len(readable))
+	res := make([]apiRepo, 0, end-start)
+	for _, repo := range readable[start:end] {
+		res = append(res, newAPIRepo(ctx, repo))
+	}
+
+	setAPIPageHeaders(w, r, page, perPage, len(readable))
+	renderStatusJSON(w, http.StatusOK, res)
+}
+
+func serviceAPIRepo(w http.ResponseWriter, r *http.Request) {
+	repo, _, ok := apiRepository(w, r)
+	if !ok {
+		return
+	}
+
+	renderStatusJSON(w, http.StatusOK, newAPIRepo(r.Context(), repo))
+}
+
+func serviceAPIRefs(w http.ResponseWriter, r *http.Request) {
+	page, perPage, ok := parseAPIPage(w, r)
+	if !ok {
+		return
+	}
+
+	_, rr, ok := apiRepository(w, r)
+	if !ok {
+		return
+	}
+
+	res := make([]apiRef, 0)
+	// Empty repositories have no references.
+	if refs, err := rr.References(); err == nil {
+		for _, ref := range refs {
+			typ := "branch"
+			if ref.IsTag() {
+				typ = "tag"
+			} else if !ref.IsBranch() {
+				continue
+			}
+			res = append(res, apiRef{
+				Name: ref.Name().String(),
+				Type: typ,
+				SHA:  ref.ID,
+			})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+
+	start, end := apiPageBounds(page, perPage, len(res))
+	setAPIPageHeaders(w, r, page, perPage, len(res))
+	renderStatusJSON(w, http.StatusOK, res[start:end])
+}
+
+func serviceAPITree(w http.ResponseWriter, r *http.Request) {
+	_, rr, ok := apiRepository(w, r)
+	if !ok {
+		return
+	}
+
+	ref, ok := apiRevision(w, r, rr)
+	if !ok {
+		return
+	}
+
+	tree, err := rr.LsTree(ref.ID)
+	if err != nil {
+		renderAPIError(w, http.StatusNotFound, "tree not found")
+		return
+	}
+
+	dir := apiPath(r)
+	if dir != "" {
+		tree, err = tree.SubTree(dir)
+		if err != nil {
+			renderAPIError(w, http.StatusNotFound, "tree not found")
+			return
+		}
+	}
+
+	ents, err := tree.Entries()
+	if err != nil {
+		renderAPIError(w, http.StatusNotFound, "tree not found")
+		return
+	}
+	ents.Sort()
+
+	res := make([]apiTreeEntry, 0, len(ents))
+	for _, te := range ents {
+		res = append(res, newAPITreeEntry(te, path.Join(dir, te.Name())))
+	}
+
+	renderStatusJSON(w, http.StatusOK, res)
+}
+
+func serviceAPIBlob(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	cfg := config.FromContext(ctx)
+	repo, rr, ok := apiRepository(w, r)
+	if !ok {
+		return
+	}
+
+	ref, ok := apiRevision(w, r, rr)
+	if !ok {
+		return
+	}
+
+	fp := apiPath(r)
+	tree, err := rr.LsTree(ref.ID)
+	if err != nil || fp == "" {
+		renderAPIError(w, http.StatusNotFound, "file not found")
+		return
+	}
+
+	te, err := tree.TreeEntry(fp)
+	if err != nil || te.Type() != "blob" {
+		renderAPIError(w, http.StatusNotFound, "file not found")
+		return
+	}
+
+	isBin, _ := te.File().IsBinary()
+	renderStatusJSON(w, http.StatusOK, apiBlob{
+		apiTreeEntry: newAPITreeEntry(te, fp),
+		Binary:       isBin,
+		RawURL:       fmt.Sprintf("%s/%s/blob/%s/%s", cfg.HTTP.PublicURL, repo.Name(), ref.ID, fp),
+	})
+}
+
+func serviceAPICommits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx).WithPrefix("http.api")
+	page, perPage, ok := parseAPIPage(w, r)
+	if !ok {
+		return
+	}
+
+	repo, rr, ok := apiRepository(w, r)
+	if !ok {
+		return
+	}
+
+	ref, ok := apiRevision(w, r, rr)
+	if !ok {
+		return
+	}
+
+	total, err := rr.CountCommits(ref)
+	if err != nil {
+		logger.Error("failed to count commits", "repo", repo.Name(), "err", err)
+		renderAPIError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	res := make([]apiCommit, 0)
+	if start, _ := apiPageBounds(page, perPage, int(total)); start < int(total) {
+		commits, err := rr.CommitsByPage(ref, page, perPage)
+		if err != nil {
+			logger.Error("failed to get commits", "repo", repo.Name(), "err", err)
+			renderAPIError(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		for _, c := range commits {
+			parents := make([]string, 0, c.ParentsCount())
+			for i := 0; i < c.ParentsCount(); i++ {
+				if id, err := c.ParentID(i); err == nil {
+					parents = append(parents, id.String())
+				}
+			}
+			res = append(res, apiCommit{
+				SHA:       c.ID.String(),
+				Message:   c.Message,
+				Author:    newAPISignature(c.Author),
+				Committer: newAPISignature(c.Committer),
+				Parents:   parents,
+			})
+		}
+	}
+
+	setAPIPageHeaders(w, r, page, perPage, int(total))
+	renderStatusJSON(w, http.StatusOK, res)
+}
+
+// apiUser returns the authenticated user of the request, nil for anonymous
+// users. It renders an error and returns false if the credentials are
+// invalid. Unlike the Git routes, unknown tokens aren't treated as anonymous
+// so that scripts notice revoked or expired tokens.
+func apiUser(w http.ResponseWriter, r *http.Request) (proto.User, bool) {
+	user, err := authenticate(r)
+	if errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrInvalidPassword) ||
+		(err != nil && r.Header.Get("Authorization") != "") {
+		renderAPIError(w, http.StatusForbidden, "invalid credentials")
+		return nil, false
+	}
+	return user, true
+}
+
+// apiRepository returns the repository of the request if the user can read
+// it, and renders an error otherwise. Repositories the user can't read are
+// reported as not found to authenticated users so that their names don't
+// leak.
+func apiRepository(w http.ResponseWriter, r *http.Request) (proto.Repository, *git.Repository, bool) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	logger := log.FromContext(ctx).WithPrefix("http.api")
+	repoName := utils.SanitizeRepo(mux.Vars(r)["repo"])
+
+	user, ok := apiUser(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+
+	if be.AccessLevelForUser(ctx, repoName, user) < access.ReadOnlyAccess {
+		if user == nil {
+			askCredentials(w, r)
+			renderAPIError(w, http.StatusUnauthorized, "authentication required")
+		} else {
+			renderAPIError(w, http.StatusNotFound, "repository not found")
+		}
+		return nil, nil, false
+	}
+
+	repo, err := be.Repository(ctx, repoName)
+	if err != nil {
+		renderAPIError(w, http.StatusNotFound, "repository not found")
+		return nil, nil, false
+	}
+
+	rr, err := repo.Open()
+	if err != nil {
+		logger.Error("failed to open repository", "repo", repoName, "err", err)
+		renderAPIError(w, http.StatusInternalServerError, "internal server error")
+		return nil, nil, false
+	}
+
+	return repo, rr, true
+}
+
+// apiRevision returns the commit of the "ref" query parameter, a branch, tag,
+// or commit hash, HEAD by default. It renders an error and returns false if
+// the revision doesn't exist.
+func apiRevision(w http.ResponseWriter, r *http.Request, rr *git.Repository) (*git.Reference, bool) {
+	rev := r.URL.Query().Get("ref")
+	if rev == "" {
+		head, err := rr.HEAD()
+		if err != nil {
+			renderAPIError(w, http.StatusNotFound, "repository is empty")
+			return nil, false
+		}
+		return head, true
+	}
+
+	if !strings.HasPrefix(rev, "-") {
+		if id, err := rr.RevParse(rev + "^{commit}"); err == nil {
+			return rr.CommitReference(id), true
+		}
+	}
+
+	renderAPIError(w, http.StatusNotFound, fmt.Sprintf("revision %q not found", rev))
+	return nil, false
+}
+
+// apiPath returns the cleaned "path" query parameter, empty for the root of
+// the tree.
+func apiPath(r *http.Request) string {
+	p := path.Clean("/" + r.URL.Query().Get("path"))
+	return strings.TrimPrefix(p, "/")
+}
+
+// parseAPIPage returns the "page" and "per_page" query parameters. It renders
+// an error and returns false if they're invalid.
+func parseAPIPage(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	page, perPage := 1, apiDefaultPerPage
+	q := r.URL.Query()
+	if v := q.Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			renderAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid page %q: must be a positive number", v))
+			return 0, 0, false
+		}
+		page = n
+	}
+	if v := q.Get("per_page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > apiMaxPerPage {
+			renderAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid per_page %q: must be between 1 and %d", v, apiMaxPerPage))
+			return 0, 0, false
+		}
+		perPage = n
+	}
+	return page, perPage, true
+}
+
+// apiPageBounds returns the start and end indexes of a page in a list of
+// total items.
+func apiPageBounds(page, perPage, total int) (int, int) {
+	start := min((page-1)*perPage, total)
+	end := min(start+perPage, total)
+	return start, end
+}
+
+// setAPIPageHeaders sets the total number of items and the links to the
+// previous and next pages.
+func setAPIPageHeaders(w http.ResponseWriter, r *http.Request, page, perPage, total int) {
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+
+	link := func(page int, rel string) string {
+		q := r.URL.Query()
+		q.Set("page", strconv.Itoa(page))
+		q.Set("per_page", strconv.Itoa(perPage))
+		return fmt.Sprintf("<%s?%s>; rel=%q", r.URL.Path, q.Encode(), rel)
+	}
+
+	var links []string
+	if page > 1 {
+		links = append(links, link(page-1, "prev"))
+	}
+	if page*perPage < total {
+		links = append(links, link(page+1, "next"))
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+}
+
+func newAPIRepo(ctx context.Context, repo proto.Repository) apiRepo {
+	cfg := config.FromContext(ctx)
+	res := apiRepo{
+		Name:        repo.Name(),
+		ProjectName: repo.ProjectName(),
+		Description: repo.Description(),
+		Private:     repo.IsPrivate(),
+		Hidden:      repo.IsHidden(),
+		Archived:    repo.IsArchived(),
+		HTTPURL:     cfg.HTTPCloneURL(repo.Name()),
+		SSHURL:      cfg.SSHCloneURL(repo.Name()),
+		CreatedAt:   repo.CreatedAt(),
+		UpdatedAt:   repo.UpdatedAt(),
+	}
+
+	// Empty repositories don't have a HEAD.
+	if rr, err := repo.Open(); err == nil {
+		if head, err := rr.HEAD(); err == nil {
+			res.DefaultBranch = head.Name().Short()
+		}
+	}
+
+	return res
+}
+
+func newAPITreeEntry(te *git.TreeEntry, fp string) apiTreeEntry {
+	res := apiTreeEntry{
+		Name: te.Name(),
+		Path: fp,
+		Type: string(te.Type()),
+		Mode: fmt.Sprintf("%06o", te.TreeEntry.Mode()),
+		SHA:  te.ID().String(),
+	}
+	if te.IsBlob() {
+		res.Size = te.Size()
+	}
+	return res
+}
+
+func newAPISignature(s *git.Signature) apiSignature {
+	if s == nil {
+		return apiSignature{}
+	}
+	return apiSignature{
+		Name:  s.Name,
+		Email: s.Email,
+		Date:  s.When,
+	}
+}
+
+// renderAPIError renders an API error as JSON.
+func renderAPIError(w http.ResponseWriter, code int, msg string) {
+	renderStatusJSON(w, code, map[string]string{
+		"message": msg,
+	})
+}
@@ -0,0 +1,90 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/gorilla/mux"
+)
+
+// BlobController is a router for raw file contents.
+//
+// Files are served at /{repo}/blob/{sha}/{path} which is the permalink format
+// used by the UI.
+func BlobController(_ context.Context, r *mux.Router) {
+	r.Handle("/{repo:.+}/blob/{sha:[0-9a-fA-F]{40}(?:[0-9a-fA-F]{24})?}/{path:.+}", http.HandlerFunc(serviceBlob)).
+		Methods(http.MethodGet, http.MethodHead)
+}
+
+func serviceBlob(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	logger := log.FromContext(ctx).WithPrefix("http.blob")
+	vars := mux.Vars(r)
+	repoName := utils.SanitizeRepo(vars["repo"])
+	sha := vars["sha"]
+	path := vars["path"]
+
+	user, err := authenticate(r)
+	if errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrInvalidPassword) {
+		renderForbidden(w, r)
+		return
+	}
+
+	if be.AccessLevelForUser(ctx, repoName, user) < access.ReadOnlyAccess {
+		askCredentials(w, r)
+		renderUnauthorized(w, r)
+		return
+	}
+
+	repo, err := be.Repository(ctx, repoName)
+	if err != nil {
+		renderNotFound(w, r)
+		return
+	}
+
+	rr, err := repo.Open()
+	if err != nil {
+		logger.Error("failed to open repository", "repo", repoName, "err", err)
+		renderInternalServerError(w, r)
+		return
+	}
+
+	tree, err := rr.LsTree(sha)
+	if err != nil {
+		renderNotFound(w, r)
+		return
+	}
+
+	te, err := tree.TreeEntry(path)
+	if err != nil || te.Type() != "blob" {
+		renderNotFound(w, r)
+		return
+	}
+
+	bts, err := te.Contents()
+	if err != nil {
+		logger.Error("failed to read file", "repo", repoName, "path", path, "err", err)
+		renderInternalServerError(w, r)
+		return
+	}
+
+	contentType := "text/plain; charset=utf-8"
+	if isBin, _ := te.File().IsBinary(); isBin {
+		contentType = "application/octet-stream"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(bts)))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		w.Write(bts) // nolint: errcheck
+	}
+}
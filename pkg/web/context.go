@@ -8,9 +8,14 @@ import (
 	"github.com/charmbracelet/soft-serve/pkg/backend"
 	"github.com/charmbracelet/soft-serve/pkg/config"
 	"github.com/charmbracelet/soft-serve/pkg/db"
+	logr "github.com/charmbracelet/soft-serve/pkg/log"
 	"github.com/charmbracelet/soft-serve/pkg/store"
 )
 
+// requestIDHeader is the header of the id that ties together the logs of a
+// request. An id set by a proxy in front of the server is kept.
+const requestIDHeader = "X-Request-Id"
+
 // NewContextHandler returns a new context middleware.
 // This middleware adds the config, backend, and logger to the request context.
 func NewContextHandler(ctx context.Context) func(http.Handler) http.Handler {
@@ -21,12 +26,16 @@ func NewContextHandler(ctx context.Context) func(http.Handler) http.Handler {
 	datastore := store.FromContext(ctx)
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := requestID(r)
+			w.Header().Set(requestIDHeader, id)
+
 			ctx := r.Context()
 			ctx = config.WithContext(ctx, cfg)
 			ctx = backend.WithContext(ctx, be)
 			ctx = log.WithContext(ctx, logger.With(
+				"request", id,
 				"method", r.Method,
-				"path", r.URL,
+				"path", r.URL.String(),
 				"addr", r.RemoteAddr,
 			))
 			ctx = db.WithContext(ctx, dbx)
@@ -37,3 +46,22 @@ func NewContextHandler(ctx context.Context) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// requestID returns the id of the request set by a proxy, or a new one. Ids
+// with characters other than letters, digits, dashes, underscores, and dots
+// are replaced so they can't break the logs.
+func requestID(r *http.Request) string {
+	id := r.Header.Get(requestIDHeader)
+	if id == "" || len(id) > 64 {
+		return logr.NewID()
+	}
+	for i := 0; i < len(id); i++ {
+		switch c := id[i]; {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.':
+		default:
+			return logr.NewID()
+		}
+	}
+	return id
+}
@@ -0,0 +1,100 @@
+package web
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+)
+
+// corsPathRe matches the read-only endpoints of a repository that can be
+// requested across origins. The first group is the repository name.
+var corsPathRe = regexp.MustCompile(`^/(.+)/` +
+	`(?:info/refs\.json|info/refs|git-upload-pack|HEAD|objects/info/packs|` +
+	`objects/[0-9a-f]{2}/[0-9a-f]{38}|objects/pack/pack-[0-9a-f]{40}\.(?:pack|idx)|` +
+	`(?:blob|statuses)/[0-9a-fA-F]{40}(?:[0-9a-fA-F]{24})?(?:/.+)?)$`)
+
+// corsAllowedHeaders are the request headers browsers may send across
+// origins, on top of the CORS-safelisted ones.
+const corsAllowedHeaders = "Content-Type, Git-Protocol"
+
+// NewCORSHandler returns a handler that adds CORS headers to the responses of
+// the read-only endpoints of public repositories, i.e. repositories that
+// anonymous users can read, for the allowed origins. Private repositories
+// never get CORS headers, and credentials are never allowed.
+func NewCORSHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+		ctx := r.Context()
+		cfg := config.FromContext(ctx)
+		if !isAllowedOrigin(cfg.HTTP.CORS.AllowedOrigins, origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		method := r.Method
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if preflight {
+			method = r.Header.Get("Access-Control-Request-Method")
+		}
+
+		m := corsPathRe.FindStringSubmatch(r.URL.Path)
+		if m == nil || !isCORSMethod(r, method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		be := backend.FromContext(ctx)
+		if be.AccessLevelForUser(ctx, utils.SanitizeRepo(m[1]), nil) < access.ReadOnlyAccess {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		if preflight {
+			w.Header().Set("Access-Control-Allow-Methods", method)
+			w.Header().Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isAllowedOrigin returns true if the origin is one of the allowed origins.
+func isAllowedOrigin(allowed []string, origin string) bool {
+	for _, o := range allowed {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCORSMethod returns true if the cross-origin request, made with the given
+// method, is read-only. Fetching objects is the only POST request allowed.
+func isCORSMethod(r *http.Request, method string) bool {
+	path := r.URL.Path
+	if r.URL.Query().Get("service") == "git-receive-pack" {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return !strings.HasSuffix(path, "/git-upload-pack")
+	case http.MethodPost:
+		return strings.HasSuffix(path, "/git-upload-pack")
+	}
+	return false
+}
@@ -260,9 +260,39 @@ func withAccess(next http.Handler) http.HandlerFunc {
 				return
 			}
 
+			if err := be.CheckFollower(repoName); err != nil {
+				renderPushError(w, r, http.StatusForbidden, err)
+				return
+			}
+
+			if err := be.CheckMaintenance(ctx, user); err != nil {
+				renderMaintenance(w, r, err)
+				return
+			}
+
+			if repo != nil && repo.IsArchived() {
+				renderPushError(w, r, http.StatusForbidden, proto.ErrRepoArchived)
+				return
+			}
+
 			// Create the repo if it doesn't exist.
 			if repo == nil {
-				repo, err = be.CreateRepository(ctx, repoName, user, proto.RepositoryOptions{})
+				defaults, err := cfg.Repo.DefaultsFor(utils.SanitizeRepo(repoName))
+				if err != nil {
+					logger.Error("failed to get repository defaults", "repo", repoName, "err", err)
+					renderInternalServerError(w, r)
+					return
+				}
+				repo, err = be.CreateRepository(ctx, repoName, user, proto.RepositoryOptions{
+					Private:     defaults.Private(),
+					Description: defaults.Description,
+					ProjectName: defaults.ProjectName,
+					Hidden:      defaults.Hidden,
+				})
+				if errors.Is(err, proto.ErrInvalidRepoName) {
+					renderPushError(w, r, http.StatusBadRequest, err)
+					return
+				}
 				if err != nil {
 					logger.Error("failed to create repository", "repo", repoName, "err", err)
 					renderInternalServerError(w, r)
@@ -322,6 +352,24 @@ func withAccess(next http.Handler) http.HandlerFunc {
 						})
 						return
 					}
+					if err := be.CheckFollower(repoName); err != nil {
+						renderJSON(w, http.StatusForbidden, lfs.ErrorResponse{
+							Message: err.Error(),
+						})
+						return
+					}
+					if err := be.CheckMaintenance(ctx, user); err != nil {
+						renderJSON(w, http.StatusServiceUnavailable, lfs.ErrorResponse{
+							Message: err.Error(),
+						})
+						return
+					}
+					if repo != nil && repo.IsArchived() {
+						renderJSON(w, http.StatusForbidden, lfs.ErrorResponse{
+							Message: proto.ErrRepoArchived.Error(),
+						})
+						return
+					}
 				case http.MethodGet:
 					// Basic download
 				case http.MethodPost:
@@ -362,6 +410,22 @@ func withAccess(next http.Handler) http.HandlerFunc {
 			return
 		}
 
+		if !strings.HasPrefix(file, "info/lfs") {
+			// Only pushes change the repository, advertising the references
+			// to push to doesn't.
+			write := git.Service(mux.Vars(r)["service"]) == git.ReceivePackService
+			release, err := be.AcquireRepository(ctx, repoName, write)
+			if err != nil {
+				renderInternalServerError(w, r)
+				return
+			}
+			defer func() {
+				if err := release(); err != nil {
+					logger.Error("failed to release repository", "repo", repoName, "err", err)
+				}
+			}()
+		}
+
 		next.ServeHTTP(w, r)
 	}
 }
@@ -378,8 +442,19 @@ func serviceRpc(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	be := backend.FromContext(ctx)
+	done, err := be.StartOperation(ctx, repoName, proto.UserFromContext(ctx), nil)
+	if err != nil {
+		renderBusy(w, r, err)
+		return
+	}
+	defer done()
+
 	if service == git.ReceivePackService {
 		gitHttpReceiveCounter.WithLabelValues(repoName)
+
+		// Housekeeping skips the repository until the push is done.
+		defer be.StartPush(repoName)()
 	}
 
 	w.Header().Set("Content-Type", fmt.Sprintf("application/x-%s-result", service))
@@ -395,6 +470,7 @@ func serviceRpc(w http.ResponseWriter, r *http.Request) {
 		Stdout: &stdout,
 		Dir:    dir,
 		Args:   []string{"--stateless-rpc"},
+		Config: cfg.Repo.GitConfig(),
 	}
 
 	user := proto.UserFromContext(ctx)
@@ -415,10 +491,7 @@ func serviceRpc(w http.ResponseWriter, r *http.Request) {
 		}...)
 	}
 
-	var (
-		err    error
-		reader io.ReadCloser
-	)
+	var reader io.ReadCloser
 
 	// Handle gzip encoding
 	reader = r.Body
@@ -435,6 +508,17 @@ func serviceRpc(w http.ResponseWriter, r *http.Request) {
 
 	cmd.Stdin = reader
 	cmd.Stdout = &flushResponseWriter{w}
+	if service == git.ReceivePackService {
+		// The report of the push is shown in the interactive sessions of the
+		// pusher.
+		reporter := git.NewPushReporter(repoName)
+		cmd.Stdout = &flushResponseWriter{&reportResponseWriter{w, reporter}}
+		defer func() {
+			if r := reporter.Report(); !r.Empty() {
+				be.PublishPushReport(user, nil, r)
+			}
+		}()
+	}
 
 	if err := service.Handler(ctx, cmd); err != nil {
 		logger.Errorf("failed to handle service: %v", err)
@@ -448,6 +532,25 @@ func serviceRpc(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// reportResponseWriter passes what's written to the response on to the
+// report of a push.
+type reportResponseWriter struct {
+	http.ResponseWriter
+	reporter *git.PushReporter
+}
+
+// Write implements io.Writer.
+func (w *reportResponseWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.reporter.Write(p[:n]) // nolint: errcheck
+	return n, err
+}
+
+// Unwrap returns the response writer so that it can still be flushed.
+func (w *reportResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 // Handle buffered output
 // Useful when using proxies
 type flushResponseWriter struct {
@@ -633,6 +736,41 @@ func renderInternalServerError(w http.ResponseWriter, r *http.Request) {
 	renderStatus(http.StatusInternalServerError)(w, r)
 }
 
+// renderMaintenance rejects a push while the server is in maintenance mode.
+func renderMaintenance(w http.ResponseWriter, r *http.Request, err error) {
+	renderPushError(w, r, http.StatusServiceUnavailable, err)
+}
+
+// renderBusy rejects a git operation over the concurrency limits, telling the
+// client when to try again.
+func renderBusy(w http.ResponseWriter, r *http.Request, err error) {
+	var limit *backend.OperationLimitError
+	if !errors.As(err, &limit) {
+		// The request was canceled.
+		renderStatus(http.StatusServiceUnavailable)(w, r)
+		return
+	}
+	if limit.Wait > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(max(limit.Wait, time.Second).Round(time.Second).Seconds())))
+	}
+	http.Error(w, limit.Error(), http.StatusServiceUnavailable)
+}
+
+// renderPushError rejects a push with the given error. Git clients only show
+// the error message when it's sent as part of the refs advertisement.
+func renderPushError(w http.ResponseWriter, r *http.Request, code int, err error) {
+	if !strings.HasSuffix(r.URL.Path, "/info/refs") {
+		http.Error(w, err.Error(), code)
+		return
+	}
+
+	hdrNocache(w)
+	w.Header().Set("Content-Type", fmt.Sprintf("application/x-%s-advertisement", git.ReceivePackService))
+	w.WriteHeader(http.StatusOK)
+	git.WritePktline(w, "# service="+git.ReceivePackService.String()) // nolint: errcheck
+	git.WritePktlineErr(w, err)                                       // nolint: errcheck
+}
+
 // Header writing functions
 
 func hdrNocache(w http.ResponseWriter) {
@@ -0,0 +1,103 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/config"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/gorilla/mux"
+)
+
+// InfoController is a router for repository information.
+//
+// The repository and its references are served as JSON at
+// /{repo}/info/refs.json so that web pages can show them. Together with CORS,
+// browsers on other origins can read the information of public repositories.
+func InfoController(_ context.Context, r *mux.Router) {
+	r.Handle("/{repo:.+}/info/refs.json", http.HandlerFunc(serviceInfo)).
+		Methods(http.MethodGet, http.MethodHead)
+}
+
+// repoInfo is the information of a repository.
+type repoInfo struct {
+	Name          string    `json:"name"`
+	ProjectName   string    `json:"project_name"`
+	Description   string    `json:"description"`
+	Private       bool      `json:"private"`
+	DefaultBranch string    `json:"default_branch"`
+	HTTPURL       string    `json:"http_url"`
+	SSHURL        string    `json:"ssh_url"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Refs          []refInfo `json:"refs"`
+}
+
+// refInfo is a reference of a repository.
+type refInfo struct {
+	Name string `json:"name"`
+	Hash string `json:"hash"`
+}
+
+func serviceInfo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	cfg := config.FromContext(ctx)
+	logger := log.FromContext(ctx).WithPrefix("http.info")
+	repoName := utils.SanitizeRepo(mux.Vars(r)["repo"])
+
+	user, err := authenticate(r)
+	if errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrInvalidPassword) {
+		renderForbidden(w, r)
+		return
+	}
+
+	if be.AccessLevelForUser(ctx, repoName, user) < access.ReadOnlyAccess {
+		askCredentials(w, r)
+		renderUnauthorized(w, r)
+		return
+	}
+
+	repo, err := be.Repository(ctx, repoName)
+	if err != nil {
+		renderNotFound(w, r)
+		return
+	}
+
+	rr, err := repo.Open()
+	if err != nil {
+		logger.Error("failed to open repository", "repo", repoName, "err", err)
+		renderInternalServerError(w, r)
+		return
+	}
+
+	info := repoInfo{
+		Name:        repo.Name(),
+		ProjectName: repo.ProjectName(),
+		Description: repo.Description(),
+		Private:     repo.IsPrivate(),
+		HTTPURL:     cfg.HTTPCloneURL(repo.Name()),
+		SSHURL:      cfg.SSHCloneURL(repo.Name()),
+		UpdatedAt:   repo.UpdatedAt(),
+		Refs:        []refInfo{},
+	}
+
+	// Empty repositories have neither a HEAD nor references.
+	if head, err := rr.HEAD(); err == nil {
+		info.DefaultBranch = head.Name().Short()
+	}
+	if refs, err := rr.References(); err == nil {
+		for _, ref := range refs {
+			info.Refs = append(info.Refs, refInfo{
+				Name: ref.Name().String(),
+				Hash: ref.ID,
+			})
+		}
+	}
+
+	renderStatusJSON(w, http.StatusOK, info)
+}
@@ -69,17 +69,23 @@ func (r *logWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 }
 
 // NewLoggingMiddleware returns a new logging middleware.
+// It uses the logger of the request context when there is one, which ties the
+// logs of the request together.
 func NewLoggingMiddleware(next http.Handler, logger *log.Logger) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 		writer := &logWriter{code: http.StatusOK, ResponseWriter: w}
-		logger.Debug("request",
+		l := logger.With(
 			"method", r.Method,
-			"path", r.URL,
+			"path", r.URL.String(),
 			"addr", r.RemoteAddr)
+		if rl, ok := r.Context().Value(log.ContextKey).(*log.Logger); ok {
+			l = rl
+		}
+		l.Debug("request")
 		next.ServeHTTP(writer, r)
 		elapsed := time.Since(start)
-		logger.Debug("response",
+		l.Debug("response",
 			"status", fmt.Sprintf("%d %s", writer.code, http.StatusText(writer.code)),
 			"bytes", humanize.Bytes(uint64(writer.bytes)),
 			"time", elapsed)
@@ -14,6 +14,14 @@ func NewRouter(ctx context.Context) http.Handler {
 	logger := log.FromContext(ctx).WithPrefix("http")
 	router := mux.NewRouter()
 
+	// API, commit status, blob and info routes
+	// These must come before the git routes since the go-get route matches
+	// any path.
+	APIController(ctx, router)
+	StatusController(ctx, router)
+	BlobController(ctx, router)
+	InfoController(ctx, router)
+
 	// Git routes
 	GitController(ctx, router)
 
@@ -21,7 +29,8 @@ func NewRouter(ctx context.Context) http.Handler {
 
 	// Context handler
 	// Adds context to the request
-	h := NewLoggingMiddleware(router, logger)
+	h := NewCORSHandler(router)
+	h = NewLoggingMiddleware(h, logger)
 	h = NewContextHandler(ctx)(h)
 	h = handlers.CompressHandler(h)
 	h = handlers.RecoveryHandler()(h)
@@ -0,0 +1,150 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/soft-serve/pkg/access"
+	"github.com/charmbracelet/soft-serve/pkg/backend"
+	"github.com/charmbracelet/soft-serve/pkg/proto"
+	"github.com/charmbracelet/soft-serve/pkg/utils"
+	"github.com/gorilla/mux"
+)
+
+// StatusController is a router for commit statuses.
+//
+// CI systems report the build results of a commit by posting a status to
+// /{repo}/statuses/{sha} using an access token. The statuses of a commit can
+// be listed with a GET request to the same path.
+func StatusController(_ context.Context, r *mux.Router) {
+	r.Handle("/{repo:.+}/statuses/{sha:[0-9a-fA-F]{40}(?:[0-9a-fA-F]{24})?}", http.HandlerFunc(serviceCommitStatus)).
+		Methods(http.MethodGet, http.MethodPost)
+}
+
+// maxCommitStatusSize is the maximum size of the body of a commit status
+// request.
+const maxCommitStatusSize = 64 * 1024
+
+// commitStatusRequest is the body of a commit status request.
+type commitStatusRequest struct {
+	State       string `json:"state"`
+	Context     string `json:"context"`
+	Description string `json:"description"`
+	TargetURL   string `json:"target_url"`
+}
+
+func serviceCommitStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	be := backend.FromContext(ctx)
+	logger := log.FromContext(ctx).WithPrefix("http.status")
+	vars := mux.Vars(r)
+	repoName := utils.SanitizeRepo(vars["repo"])
+	sha := vars["sha"]
+
+	user, err := authenticate(r)
+	if errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrInvalidPassword) {
+		renderForbidden(w, r)
+		return
+	}
+
+	accessLevel := be.AccessLevelForUser(ctx, repoName, user)
+	if accessLevel < access.ReadOnlyAccess {
+		askCredentials(w, r)
+		renderUnauthorized(w, r)
+		return
+	}
+
+	repo, err := be.Repository(ctx, repoName)
+	if err != nil {
+		renderNotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		statuses, err := be.CommitStatuses(ctx, repo, sha)
+		if err != nil {
+			logger.Error("failed to get commit statuses", "repo", repoName, "sha", sha, "err", err)
+			renderInternalServerError(w, r)
+			return
+		}
+
+		if statuses == nil {
+			statuses = []proto.CommitStatus{}
+		}
+
+		renderStatusJSON(w, http.StatusOK, statuses)
+	case http.MethodPost:
+		if user == nil {
+			askCredentials(w, r)
+			renderUnauthorized(w, r)
+			return
+		}
+
+		if accessLevel < access.ReadWriteAccess {
+			renderForbidden(w, r)
+			return
+		}
+
+		var req commitStatusRequest
+		r.Body = http.MaxBytesReader(w, r.Body, maxCommitStatusSize)
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			renderBadRequest(w, r)
+			return
+		}
+
+		state, err := proto.ParseCommitState(req.State)
+		if err != nil {
+			renderStatusJSON(w, http.StatusUnprocessableEntity, map[string]string{
+				"message": err.Error(),
+			})
+			return
+		}
+
+		status := proto.CommitStatus{
+			SHA:         sha,
+			Context:     req.Context,
+			State:       state,
+			Description: req.Description,
+			TargetURL:   req.TargetURL,
+		}
+		if err := be.SetCommitStatus(ctx, repo, status); err != nil {
+			logger.Error("failed to set commit status", "repo", repoName, "sha", sha, "err", err)
+			renderInternalServerError(w, r)
+			return
+		}
+
+		statuses, err := be.CommitStatuses(ctx, repo, sha)
+		if err != nil {
+			logger.Error("failed to get commit statuses", "repo", repoName, "sha", sha, "err", err)
+			renderInternalServerError(w, r)
+			return
+		}
+
+		// The context is stored trimmed, see Backend.SetCommitStatus.
+		status.Context = strings.TrimSpace(status.Context)
+		if status.Context == "" {
+			status.Context = backend.DefaultCommitStatusContext
+		}
+		for _, s := range statuses {
+			if s.Context == status.Context {
+				status = s
+				break
+			}
+		}
+
+		renderStatusJSON(w, http.StatusCreated, status)
+	}
+}
+
+func renderStatusJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error("error encoding json", "err", err)
+	}
+}
@@ -63,9 +63,9 @@ func NewBranchTagEvent(ctx context.Context, user proto.User, repo proto.Reposito
 	}
 
 	cfg := config.FromContext(ctx)
-	payload.Repository.HTTPURL = repoURL(cfg.HTTP.PublicURL, repo.Name())
-	payload.Repository.SSHURL = repoURL(cfg.SSH.PublicURL, repo.Name())
-	payload.Repository.GitURL = repoURL(cfg.Git.PublicURL, repo.Name())
+	payload.Repository.HTTPURL = cfg.HTTPCloneURL(repo.Name())
+	payload.Repository.SSHURL = cfg.SSHCloneURL(repo.Name())
+	payload.Repository.GitURL = cfg.GitCloneURL(repo.Name())
 
 	// Find repo owner.
 	dbx := db.FromContext(ctx)
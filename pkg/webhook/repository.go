@@ -29,6 +29,8 @@ const (
 	RepositoryEventActionVisibilityChange RepositoryEventAction = "visibility_change"
 	// RepositoryEventActionDefaultBranchChange is a repository default branch changed event.
 	RepositoryEventActionDefaultBranchChange RepositoryEventAction = "default_branch_change"
+	// RepositoryEventActionTransfer is a repository owner changed event.
+	RepositoryEventActionTransfer RepositoryEventAction = "transfer"
 )
 
 // NewRepositoryEvent sends a repository event.
@@ -62,9 +64,9 @@ func NewRepositoryEvent(ctx context.Context, user proto.User, repo proto.Reposit
 	}
 
 	cfg := config.FromContext(ctx)
-	payload.Repository.HTTPURL = repoURL(cfg.HTTP.PublicURL, repo.Name())
-	payload.Repository.SSHURL = repoURL(cfg.SSH.PublicURL, repo.Name())
-	payload.Repository.GitURL = repoURL(cfg.Git.PublicURL, repo.Name())
+	payload.Repository.HTTPURL = cfg.HTTPCloneURL(repo.Name())
+	payload.Repository.SSHURL = cfg.SSHCloneURL(repo.Name())
+	payload.Repository.GitURL = cfg.GitCloneURL(repo.Name())
 
 	// Find repo owner.
 	dbx := db.FromContext(ctx)
@@ -8,7 +8,6 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"net/http"
 
@@ -17,7 +16,6 @@ import (
 	"github.com/charmbracelet/soft-serve/pkg/db/models"
 	"github.com/charmbracelet/soft-serve/pkg/proto"
 	"github.com/charmbracelet/soft-serve/pkg/store"
-	"github.com/charmbracelet/soft-serve/pkg/utils"
 	"github.com/charmbracelet/soft-serve/pkg/version"
 	"github.com/google/go-querystring/query"
 	"github.com/google/uuid"
@@ -142,10 +140,6 @@ func SendEvent(ctx context.Context, payload EventPayload) error {
 	return nil
 }
 
-func repoURL(publicURL string, repo string) string {
-	return fmt.Sprintf("%s/%s.git", publicURL, utils.SanitizeRepo(repo))
-}
-
 func getDefaultBranch(repo proto.Repository) (string, error) {
 	branch, err := proto.RepositoryDefaultBranch(repo)
 	// XXX: we check for ErrReferenceNotExist here because we don't want to
@@ -3,7 +3,9 @@ package testscript
 import (
 	"bytes"
 	"context"
+	crand "crypto/rand"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -17,6 +19,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -73,6 +76,8 @@ func TestScript(t *testing.T) {
 	admin1Key, admin1 := mkkey("admin1")
 	_, admin2 := mkkey("admin2")
 	user1Key, user1 := mkkey("user1")
+	_, ca := mkkey("ca")
+	_, untrustedCA := mkkey("untrusted-ca")
 
 	testscript.Run(t, testscript.Params{
 		Dir:                 "./testdata/",
@@ -81,6 +86,7 @@ func TestScript(t *testing.T) {
 		Cmds: map[string]func(ts *testscript.TestScript, neg bool, args []string){
 			"soft":          cmdSoft("admin", admin1.Signer()),
 			"usoft":         cmdSoft("user1", user1.Signer()),
+			"csoft":         cmdCertSoft(user1, ca.Signer(), untrustedCA.Signer()),
 			"git":           cmdGit(admin1Key),
 			"ugit":          cmdGit(user1Key),
 			"curl":          cmdCurl,
@@ -112,9 +118,14 @@ func TestScript(t *testing.T) {
 			e.Setenv("DATA_PATH", data)
 			e.Setenv("SSH_PORT", fmt.Sprintf("%d", sshPort))
 			e.Setenv("HTTP_PORT", fmt.Sprintf("%d", httpPort))
+			e.Setenv("GIT_PORT", fmt.Sprintf("%d", gitPort))
+			e.Setenv("STATS_PORT", fmt.Sprintf("%d", statsPort))
 			e.Setenv("ADMIN1_AUTHORIZED_KEY", admin1.AuthorizedKey())
 			e.Setenv("ADMIN2_AUTHORIZED_KEY", admin2.AuthorizedKey())
 			e.Setenv("USER1_AUTHORIZED_KEY", user1.AuthorizedKey())
+			e.Setenv("ADMIN1_KEY_PATH", admin1Key)
+			e.Setenv("USER1_KEY_PATH", user1Key)
+			e.Setenv("CA_AUTHORIZED_KEY", ca.AuthorizedKey())
 			e.Setenv("SSH_KNOWN_HOSTS_FILE", filepath.Join(t.TempDir(), "known_hosts"))
 			e.Setenv("SSH_KNOWN_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
 
@@ -177,8 +188,16 @@ func TestScript(t *testing.T) {
 	})
 }
 
+// cmdSoft runs a command on the server as the given user. A leading
+// "-stdin FILE" sends the file to the standard input of the command.
 func cmdSoft(user string, key ssh.Signer) func(ts *testscript.TestScript, neg bool, args []string) {
 	return func(ts *testscript.TestScript, neg bool, args []string) {
+		var stdin io.Reader
+		if len(args) > 1 && args[0] == "-stdin" {
+			stdin = strings.NewReader(ts.ReadFile(args[1]))
+			args = args[2:]
+		}
+
 		cli, err := ssh.Dial(
 			"tcp",
 			net.JoinHostPort("localhost", ts.Getenv("SSH_PORT")),
@@ -195,17 +214,95 @@ func cmdSoft(user string, key ssh.Signer) func(ts *testscript.TestScript, neg bo
 		ts.Check(err)
 		defer sess.Close()
 
+		sess.Stdin = stdin
+		sess.Stdout = ts.Stdout()
+		sess.Stderr = ts.Stderr()
+
+		err = sess.Run(strings.Join(args, " "))
+		ts.Setenv("EXIT_STATUS", strconv.Itoa(exitStatus(err)))
+		check(ts, err, neg)
+	}
+}
+
+// exitStatus returns the exit status of a remote command.
+func exitStatus(err error) int {
+	var eerr *ssh.ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &eerr):
+		return eerr.ExitStatus()
+	default:
+		return -1
+	}
+}
+
+// cmdCertSoft runs a command with a user certificate of the given key signed
+// by the given certificate authority.
+func cmdCertSoft(key *keygen.SSHKeyPair, ca, untrustedCA ssh.Signer) func(ts *testscript.TestScript, neg bool, args []string) {
+	return func(ts *testscript.TestScript, neg bool, args []string) {
+		fs := flag.NewFlagSet("csoft", flag.ContinueOnError)
+		principals := fs.String("principals", "", "comma separated certificate principals")
+		serial := fs.Uint64("serial", 1, "certificate serial number")
+		id := fs.String("id", "", "certificate key ID")
+		expired := fs.Bool("expired", false, "sign an expired certificate")
+		untrusted := fs.Bool("untrusted", false, "sign the certificate with an untrusted authority")
+		ts.Check(fs.Parse(args))
+
+		now := time.Now()
+		cert := &ssh.Certificate{
+			Key:         key.Signer().PublicKey(),
+			Serial:      *serial,
+			CertType:    ssh.UserCert,
+			KeyId:       *id,
+			ValidAfter:  uint64(now.Add(-time.Hour).Unix()),
+			ValidBefore: uint64(now.Add(time.Hour).Unix()),
+		}
+		if *principals != "" {
+			cert.ValidPrincipals = strings.Split(*principals, ",")
+		}
+		if *expired {
+			cert.ValidBefore = uint64(now.Add(-time.Minute).Unix())
+		}
+		signer := ca
+		if *untrusted {
+			signer = untrustedCA
+		}
+		ts.Check(cert.SignCert(crand.Reader, signer))
+		certSigner, err := ssh.NewCertSigner(cert, key.Signer())
+		ts.Check(err)
+
+		cli, err := ssh.Dial(
+			"tcp",
+			net.JoinHostPort("localhost", ts.Getenv("SSH_PORT")),
+			&ssh.ClientConfig{
+				User:            "user1",
+				Auth:            []ssh.AuthMethod{ssh.PublicKeys(certSigner)},
+				HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+			},
+		)
+		if err != nil {
+			// Rejected certificates fail the handshake.
+			check(ts, err, neg)
+			return
+		}
+		defer cli.Close()
+
+		sess, err := cli.NewSession()
+		ts.Check(err)
+		defer sess.Close()
+
 		sess.Stdout = ts.Stdout()
 		sess.Stderr = ts.Stderr()
 
-		check(ts, sess.Run(strings.Join(args, " ")), neg)
+		check(ts, sess.Run(strings.Join(fs.Args(), " ")), neg)
 	}
 }
 
 func cmdUI(key ssh.Signer) func(ts *testscript.TestScript, neg bool, args []string) {
 	return func(ts *testscript.TestScript, neg bool, args []string) {
 		if len(args) < 1 {
-			ts.Fatalf("usage: ui <quoted string input>")
+			ts.Fatalf("usage: ui <quoted string input> [command...]")
 			return
 		}
 
@@ -230,19 +327,28 @@ func cmdUI(key ssh.Signer) func(ts *testscript.TestScript, neg bool, args []stri
 		// in the output
 		defer ts.Stdout().Write([]byte("\n"))
 
-		sess.Stdout = ts.Stdout()
+		scr := &uiScreen{w: ts.Stdout()}
+		sess.Stdout = scr
 		sess.Stderr = ts.Stderr()
 
 		stdin, err := sess.StdinPipe()
 		check(ts, err, neg)
 
-		err = sess.RequestPty("dumb", 40, 80, ssh.TerminalModes{})
+		// UI_WIDTH sets the width of the terminal, 80 columns by default.
+		width := 80
+		if w := ts.Getenv("UI_WIDTH"); w != "" {
+			width, err = strconv.Atoi(w)
+			check(ts, err, neg)
+		}
+
+		err = sess.RequestPty("dumb", 40, width, ssh.TerminalModes{})
 		check(ts, err, neg)
-		check(ts, sess.Start(""), neg)
+		check(ts, sess.Start(strings.Join(args[1:], " ")), neg)
 
 		in, err := strconv.Unquote(args[0])
 		check(ts, err, neg)
 		reader := strings.NewReader(in)
+		done := make(chan error, 1)
 		go func() {
 			defer stdin.Close()
 			for {
@@ -250,16 +356,208 @@ func cmdUI(key ssh.Signer) func(ts *testscript.TestScript, neg bool, args []stri
 				if err == io.EOF {
 					break
 				}
-				check(ts, err, neg)
-				stdin.Write([]byte(string(r))) // nolint: errcheck
+				if err != nil {
+					done <- err
+					return
+				}
 
-				// Wait for the UI to process the input
-				time.Sleep(100 * time.Millisecond)
+				// Wait for the UI to process the previous input
+				if !scr.settle() {
+					sess.Close() // nolint: errcheck
+					done <- fmt.Errorf("ui did not settle within %s", uiSettleTimeout)
+					return
+				}
+				stdin.Write([]byte(string(r))) // nolint: errcheck
+				scr.sent()
 			}
+			done <- nil
 		}()
 
-		check(ts, sess.Wait(), neg)
+		err = sess.Wait()
+		if err := <-done; err != nil {
+			ts.Fatalf("%v", err)
+		}
+		check(ts, err, neg)
+	}
+}
+
+const (
+	// uiSettleTime is how long the UI must not redraw to be considered
+	// settled. It's longer than the interval of the spinners.
+	uiSettleTime = 400 * time.Millisecond
+	// uiSettleTimeout is how long to wait for the UI to settle at most.
+	uiSettleTimeout = 30 * time.Second
+)
+
+// uiScreen is the output of a UI session. It keeps the lines shown on the
+// terminal so that inputs are only sent once the UI has rendered and settled,
+// rather than after fixed delays.
+type uiScreen struct {
+	w io.Writer
+
+	mu sync.Mutex
+	// drawn is whether the UI has rendered anything yet.
+	drawn bool
+	// last is the time of the last redraw or input.
+	last time.Time
+	// lines are the lines on the screen and row is the line of the cursor.
+	// fresh is whether the cursor moved to the line since it was written,
+	// the renderer rewrites lines from their start.
+	lines [][]byte
+	row   int
+	fresh bool
+	// esc is an escape sequence split across writes.
+	esc []byte
+}
+
+// Write implements io.Writer.
+func (s *uiScreen) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	s.drawn = true
+	s.last = time.Now()
+	s.draw(p)
+	s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+// draw applies the output of the renderer to the lines of the screen. Only
+// the cursor movements and line clears the renderer uses are handled, styles
+// and other sequences are dropped.
+func (s *uiScreen) draw(p []byte) {
+	b := append(s.esc, p...)
+	s.esc = nil
+	for i := 0; i < len(b); i++ {
+		switch c := b[i]; c {
+		case '\x1b':
+			n := escapeLen(b[i:])
+			if n < 0 {
+				s.esc = append([]byte(nil), b[i:]...)
+				return
+			}
+			s.escape(b[i : i+n])
+			i += n - 1
+		case '\r':
+			s.fresh = true
+		case '\n':
+			s.move(s.row + 1)
+		default:
+			l := s.line()
+			if s.fresh {
+				*l = (*l)[:0]
+				s.fresh = false
+			}
+			*l = append(*l, c)
+		}
+	}
+}
+
+// escapeLen returns the length of the escape sequence b starts with, or -1
+// if it's incomplete.
+func escapeLen(b []byte) int {
+	if len(b) < 2 {
+		return -1
+	}
+	switch b[1] {
+	case '[':
+		// CSI sequences end with a byte in the 0x40-0x7e range.
+		for i := 2; i < len(b); i++ {
+			if b[i] >= 0x40 && b[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return -1
+	case ']':
+		// OSC sequences, like the clipboard, end with BEL or ST.
+		for i := 2; i < len(b); i++ {
+			if b[i] == '\a' {
+				return i + 1
+			}
+			if b[i] == '\x1b' && i+1 < len(b) && b[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return -1
+	}
+	return 2
+}
+
+// escape applies a CSI sequence to the screen.
+func (s *uiScreen) escape(seq []byte) {
+	if len(seq) < 3 || seq[1] != '[' {
+		return
+	}
+	params := string(seq[2 : len(seq)-1])
+	n, err := strconv.Atoi(strings.Split(params, ";")[0])
+	if err != nil || n < 1 {
+		n = 1
+	}
+	switch seq[len(seq)-1] {
+	case 'A':
+		s.move(s.row - n)
+	case 'B':
+		s.move(s.row + n)
+	case 'H':
+		s.move(n - 1)
+	case 'K':
+		if params == "2" || s.fresh {
+			l := s.line()
+			*l = (*l)[:0]
+		}
+	case 'J':
+		if params == "2" {
+			s.lines = nil
+		}
+	}
+}
+
+// move moves the cursor to the given line.
+func (s *uiScreen) move(row int) {
+	s.row = max(row, 0)
+	s.fresh = true
+}
+
+// line returns the line of the cursor.
+func (s *uiScreen) line() *[]byte {
+	for len(s.lines) <= s.row {
+		s.lines = append(s.lines, nil)
+	}
+	return &s.lines[s.row]
+}
+
+// loading returns whether a spinner is shown.
+func (s *uiScreen) loading() bool {
+	for _, l := range s.lines {
+		if bytes.Contains(l, []byte("loading…")) {
+			return true
+		}
+	}
+	return false
+}
+
+// sent records that an input was sent, the UI is waited for again.
+func (s *uiScreen) sent() {
+	s.mu.Lock()
+	s.last = time.Now()
+	s.mu.Unlock()
+}
+
+// settle waits for the UI to render and stop redrawing. Spinners keep
+// redrawing the UI while something loads, and none must be left on the
+// screen. It reports false if the UI doesn't settle within uiSettleTimeout.
+func (s *uiScreen) settle() bool {
+	deadline := time.Now().Add(uiSettleTimeout)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		settled := s.drawn &&
+			time.Since(s.last) >= uiSettleTime &&
+			!s.loading()
+		s.mu.Unlock()
+		if settled {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
 	}
+	return false
 }
 
 // P.S. Windows sucks!